@@ -10,6 +10,7 @@ import (
 	"github.com/onosproject/onos-api/go/onos/ransim/types"
 	"github.com/onosproject/ran-simulator/pkg/model"
 	"github.com/onosproject/ran-simulator/pkg/utils/honeycomb"
+	"github.com/onosproject/ran-simulator/pkg/utils/ns3"
 	"github.com/spf13/cobra"
 	"gopkg.in/yaml.v2"
 	"io/ioutil"
@@ -31,6 +32,7 @@ func getRootCommand() *cobra.Command {
 		Short: "honeycomb RAN topology generator",
 	}
 	cmd.AddCommand(getHoneycombTopoCommand())
+	cmd.AddCommand(getNs3Command())
 	return cmd
 }
 
@@ -93,3 +95,68 @@ func runHoneycombTopoCommand(cmd *cobra.Command, args []string) error {
 
 	return ioutil.WriteFile(args[0], d, 0644)
 }
+
+func getNs3Command() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:           "ns3 infile outfile",
+		Short:         "ran-simulator config conversion tool for ns-3/5G-LENA scenarios",
+		SilenceUsage:  false,
+		SilenceErrors: false,
+		Args:          cobra.ExactArgs(2),
+		RunE:          runNs3Command,
+	}
+	cmd.Flags().Float64P("latitude", "a", 52.5200, "Latitude in degrees of the origin of the ns-3 coordinates")
+	cmd.Flags().Float64P("longitude", "g", 13.4050, "Longitude in degrees of the origin of the ns-3 coordinates")
+	cmd.Flags().Float64P("max-neighbor-distance", "d", 1000.0, "Maximum distance in meters between neighbor cells")
+	cmd.Flags().Int("max-neighbors", 5, "Maximum number of neighbors a cell will have; -1 no limit")
+	cmd.Flags().StringSlice("service-models", []string{"kpm/1", "ni/2", "rc/3"}, "List of service models supported by the nodes")
+	cmd.Flags().StringSlice("controller-addresses", []string{"onos-e2t"}, "List of E2T controller addresses or service names")
+	cmd.Flags().String("plmnid", "315010", "PlmnID in MCC-MNC format, e.g. CCCNNN or CCCNN")
+	cmd.Flags().Uint32P("enbidstart", "e", 5152, "EnbID start")
+	return cmd
+}
+
+func runNs3Command(cmd *cobra.Command, args []string) error {
+	latitude, _ := cmd.Flags().GetFloat64("latitude")
+	longitude, _ := cmd.Flags().GetFloat64("longitude")
+	plmnid, _ := cmd.Flags().GetString("plmnid")
+	enbidStart, _ := cmd.Flags().GetUint32("enbidstart")
+	maxDistance, _ := cmd.Flags().GetFloat64("max-neighbor-distance")
+	maxNeighbors, _ := cmd.Flags().GetInt("max-neighbors")
+	controllerAddresses, _ := cmd.Flags().GetStringSlice("controller-addresses")
+	serviceModels, _ := cmd.Flags().GetStringSlice("service-models")
+
+	data, err := ioutil.ReadFile(args[0])
+	if err != nil {
+		return err
+	}
+	scenario, err := ns3.Parse(data)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Converting ns-3 scenario with %d gNBs.\n", len(scenario.Gnbs))
+
+	m, err := ns3.Convert(scenario, ns3.Options{
+		Origin:              model.Coordinate{Lat: latitude, Lng: longitude},
+		PlmnID:              types.PlmnIDFromString(plmnid),
+		EnbIDStart:          enbidStart,
+		MaxNeighborDistance: maxDistance,
+		MaxNeighbors:        maxNeighbors,
+		ControllerAddresses: controllerAddresses,
+		ServiceModels:       serviceModels,
+	})
+	if err != nil {
+		return err
+	}
+
+	m.Plmn = plmnid // we want the MCC-MNC format in our YAML
+
+	d, err := yaml.Marshal(&m)
+	if err != nil {
+		fmt.Printf("Unable to marshal model data: %v", err)
+		return err
+	}
+
+	return ioutil.WriteFile(args[1], d, 0644)
+}
@@ -0,0 +1,108 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	modelapi "github.com/onosproject/onos-api/go/onos/ransim/model"
+	"github.com/onosproject/onos-api/go/onos/ransim/types"
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func getCellsCommand(c *cli) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "cells",
+		Short: "List and watch the simulated cells",
+	}
+	cmd.AddCommand(&cobra.Command{
+		Use:   "list",
+		Short: "List the cells",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runCellsList(c, cmd)
+		},
+	})
+	cmd.AddCommand(&cobra.Command{
+		Use:   "watch",
+		Short: "Watch changes of the cells",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runCellsWatch(c, cmd)
+		},
+	})
+	return cmd
+}
+
+func runCellsList(c *cli, cmd *cobra.Command) error {
+	conn, err := c.connection()
+	if err != nil {
+		return err
+	}
+	client := modelapi.NewCellModelClient(conn)
+	stream, err := client.ListCells(context.Background(), &modelapi.ListCellsRequest{})
+	if err != nil {
+		return err
+	}
+
+	w := newTableWriter(cmd)
+	fmt.Fprintf(w, "ECGI\tLAT\tLNG\tAZIMUTH\tARC\tTX POWER\tMAX UES\tUES\tNEIGHBORS\n")
+	for {
+		response, err := stream.Recv()
+		if err == io.EOF {
+			return w.Flush()
+		} else if err != nil {
+			return err
+		}
+		printCell(w, response.Cell)
+	}
+}
+
+// runCellsWatch prints the changes of the cells until interrupted
+func runCellsWatch(c *cli, cmd *cobra.Command) error {
+	conn, err := c.connection()
+	if err != nil {
+		return err
+	}
+	ctx, cancel := watchContext()
+	defer cancel()
+	client := modelapi.NewCellModelClient(conn)
+	stream, err := client.WatchCells(ctx, &modelapi.WatchCellsRequest{NoReplay: true})
+	if err != nil {
+		return err
+	}
+
+	w := newTableWriter(cmd)
+	fmt.Fprintf(w, "EVENT\tECGI\tLAT\tLNG\tAZIMUTH\tARC\tTX POWER\tMAX UES\tUES\tNEIGHBORS\n")
+	for {
+		response, err := stream.Recv()
+		if err == io.EOF || status.Code(err) == codes.Canceled {
+			return w.Flush()
+		} else if err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "%s\t", response.Type)
+		printCell(w, response.Cell)
+		_ = w.Flush()
+	}
+}
+
+func printCell(w *tabwriter.Writer, cell *types.Cell) {
+	var lat, lng float64
+	if cell.Location != nil {
+		lat, lng = cell.Location.Lat, cell.Location.Lng
+	}
+	var azimuth, arc int32
+	if cell.Sector != nil {
+		azimuth, arc = cell.Sector.Azimuth, cell.Sector.Arc
+	}
+	fmt.Fprintf(w, "%d\t%.6f\t%.6f\t%d\t%d\t%.1f\t%d\t%d\t%v\n", cell.ECGI, lat, lng, azimuth, arc,
+		cell.TxPowerdB, cell.MaxUEs, len(cell.CrntiMap), cell.Neighbors)
+}
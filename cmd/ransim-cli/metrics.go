@@ -0,0 +1,103 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+
+	metricsapi "github.com/onosproject/onos-api/go/onos/ransim/metrics"
+	modelapi "github.com/onosproject/onos-api/go/onos/ransim/model"
+	"github.com/spf13/cobra"
+)
+
+func getMetricsCommand(c *cli) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "metrics",
+		Short: "Inspect the KPIs and attributes of nodes, cells and UEs",
+	}
+	cmd.AddCommand(&cobra.Command{
+		Use:   "dump [entityID...]",
+		Short: "Dump the metrics of the given entities or, by default, of all nodes and cells",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runMetricsDump(c, cmd, args)
+		},
+	})
+	return cmd
+}
+
+func runMetricsDump(c *cli, cmd *cobra.Command, args []string) error {
+	conn, err := c.connection()
+	if err != nil {
+		return err
+	}
+	ctx := context.Background()
+
+	entityIDs := make([]uint64, 0, len(args))
+	for _, arg := range args {
+		entityID, err := strconv.ParseUint(arg, 10, 64)
+		if err != nil {
+			return err
+		}
+		entityIDs = append(entityIDs, entityID)
+	}
+	if len(entityIDs) == 0 {
+		if entityIDs, err = listEntities(ctx, modelapi.NewNodeModelClient(conn), modelapi.NewCellModelClient(conn)); err != nil {
+			return err
+		}
+	}
+
+	client := metricsapi.NewMetricsServiceClient(conn)
+	w := newTableWriter(cmd)
+	fmt.Fprintf(w, "ENTITY\tMETRIC\tVALUE\tTYPE\n")
+	for _, entityID := range entityIDs {
+		response, err := client.List(ctx, &metricsapi.ListRequest{EntityID: entityID})
+		if err != nil {
+			return err
+		}
+		sort.Slice(response.Metrics, func(i, j int) bool {
+			return response.Metrics[i].Key < response.Metrics[j].Key
+		})
+		for _, metric := range response.Metrics {
+			fmt.Fprintf(w, "%d\t%s\t%s\t%s\n", entityID, metric.Key, metric.Value, metric.Type)
+		}
+	}
+	return w.Flush()
+}
+
+// listEntities returns the IDs of all nodes and cells
+func listEntities(ctx context.Context, nodeClient modelapi.NodeModelClient, cellClient modelapi.CellModelClient) ([]uint64, error) {
+	entityIDs := make([]uint64, 0)
+	nodeStream, err := nodeClient.ListNodes(ctx, &modelapi.ListNodesRequest{})
+	if err != nil {
+		return nil, err
+	}
+	for {
+		response, err := nodeStream.Recv()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		entityIDs = append(entityIDs, uint64(response.Node.EnbID))
+	}
+	cellStream, err := cellClient.ListCells(ctx, &modelapi.ListCellsRequest{})
+	if err != nil {
+		return nil, err
+	}
+	for {
+		response, err := cellStream.Recv()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		entityIDs = append(entityIDs, uint64(response.Cell.ECGI))
+	}
+	return entityIDs, nil
+}
@@ -0,0 +1,130 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"text/tabwriter"
+
+	modelapi "github.com/onosproject/onos-api/go/onos/ransim/model"
+	"github.com/onosproject/onos-api/go/onos/ransim/types"
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func getNodesCommand(c *cli) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "nodes",
+		Short: "List, watch and control the simulated E2 nodes",
+	}
+	cmd.AddCommand(&cobra.Command{
+		Use:   "list",
+		Short: "List the E2 nodes",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runNodesList(c, cmd)
+		},
+	})
+	cmd.AddCommand(&cobra.Command{
+		Use:   "watch",
+		Short: "Watch changes of the E2 nodes",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runNodesWatch(c, cmd)
+		},
+	})
+	cmd.AddCommand(getAgentControlCommand(c, "start", "Start the E2 agent of a node"))
+	cmd.AddCommand(getAgentControlCommand(c, "stop", "Stop the E2 agent of a node"))
+	return cmd
+}
+
+func getAgentControlCommand(c *cli, command string, short string) *cobra.Command {
+	return &cobra.Command{
+		Use:   command + " enbID",
+		Short: short,
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			enbID, err := strconv.ParseUint(args[0], 10, 32)
+			if err != nil {
+				return err
+			}
+			conn, err := c.connection()
+			if err != nil {
+				return err
+			}
+			client := modelapi.NewNodeModelClient(conn)
+			_, err = client.AgentControl(context.Background(), &modelapi.AgentControlRequest{
+				EnbID:   types.EnbID(enbID),
+				Command: command,
+			})
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Requested %s of node %d\n", command, enbID)
+			return nil
+		},
+	}
+}
+
+func runNodesList(c *cli, cmd *cobra.Command) error {
+	conn, err := c.connection()
+	if err != nil {
+		return err
+	}
+	client := modelapi.NewNodeModelClient(conn)
+	stream, err := client.ListNodes(context.Background(), &modelapi.ListNodesRequest{})
+	if err != nil {
+		return err
+	}
+
+	w := newTableWriter(cmd)
+	fmt.Fprintf(w, "ENBID\tSTATUS\tCELLS\tSERVICE MODELS\tCONTROLLERS\n")
+	for {
+		response, err := stream.Recv()
+		if err == io.EOF {
+			return w.Flush()
+		} else if err != nil {
+			return err
+		}
+		printNode(w, response.Node)
+	}
+}
+
+// runNodesWatch prints the changes of the nodes until interrupted
+func runNodesWatch(c *cli, cmd *cobra.Command) error {
+	conn, err := c.connection()
+	if err != nil {
+		return err
+	}
+	ctx, cancel := watchContext()
+	defer cancel()
+	client := modelapi.NewNodeModelClient(conn)
+	stream, err := client.WatchNodes(ctx, &modelapi.WatchNodesRequest{NoReplay: true})
+	if err != nil {
+		return err
+	}
+
+	w := newTableWriter(cmd)
+	fmt.Fprintf(w, "EVENT\tENBID\tSTATUS\tCELLS\tSERVICE MODELS\tCONTROLLERS\n")
+	for {
+		response, err := stream.Recv()
+		if err == io.EOF || status.Code(err) == codes.Canceled {
+			return w.Flush()
+		} else if err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "%s\t", response.Type)
+		printNode(w, response.Node)
+		_ = w.Flush()
+	}
+}
+
+func printNode(w *tabwriter.Writer, node *types.Node) {
+	fmt.Fprintf(w, "%d\t%s\t%v\t%v\t%v\n", node.EnbID, node.Status, node.CellECGIs, node.ServiceModels, node.Controllers)
+}
@@ -0,0 +1,157 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/onosproject/onos-ric-sdk-go/pkg/e2/creds"
+	"github.com/onosproject/ran-simulator/pkg/tenant"
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+)
+
+const defaultAddress = "localhost:5150"
+
+// A command-line client for driving the simulator via its northbound gRPC APIs
+func main() {
+	c := &cli{address: defaultAddress}
+	defer c.close()
+	if err := getRootCommand(c).Execute(); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+}
+
+// cli holds the connection settings and the connection to the simulator shared by the commands,
+// including the commands run successively from the interactive shell
+type cli struct {
+	address    string
+	noTLS      bool
+	tenant     string
+	conn       *grpc.ClientConn
+	connTenant string
+}
+
+func getRootCommand(c *cli) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:           "ransim-cli",
+		Short:         "RAN simulator command-line client",
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+	cmd.PersistentFlags().StringVar(&c.address, "address", c.address, "address of the simulator northbound API")
+	cmd.PersistentFlags().BoolVar(&c.noTLS, "no-tls", c.noTLS, "connect to the simulator without TLS")
+	cmd.PersistentFlags().StringVar(&c.tenant, "tenant", c.tenant, "tenant of the simulator to drive; empty drives the default tenant")
+	cmd.AddCommand(getNodesCommand(c))
+	cmd.AddCommand(getCellsCommand(c))
+	cmd.AddCommand(getUEsCommand(c))
+	cmd.AddCommand(getRoutesCommand(c))
+	cmd.AddCommand(getMetricsCommand(c))
+	cmd.AddCommand(getShellCommand(c))
+	return cmd
+}
+
+// connection returns the connection to the simulator, dialing it on first use
+func (c *cli) connection() (*grpc.ClientConn, error) {
+	if c.conn != nil && c.conn.Target() == c.address && c.connTenant == c.tenant {
+		return c.conn, nil
+	}
+	c.close()
+	opts := []grpc.DialOption{grpc.WithInsecure()}
+	if !c.noTLS {
+		tlsConfig, err := creds.GetClientCredentials()
+		if err != nil {
+			return nil, err
+		}
+		opts = []grpc.DialOption{grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig))}
+	}
+	if c.tenant != "" {
+		opts = append(opts, tenant.DialOptions(c.tenant)...)
+	}
+	conn, err := grpc.DialContext(context.Background(), c.address, opts...)
+	if err != nil {
+		return nil, err
+	}
+	c.conn = conn
+	c.connTenant = c.tenant
+	return conn, nil
+}
+
+func (c *cli) close() {
+	if c.conn != nil {
+		_ = c.conn.Close()
+		c.conn = nil
+	}
+}
+
+func getShellCommand(c *cli) *cobra.Command {
+	return &cobra.Command{
+		Use:   "shell",
+		Short: "Run commands interactively",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runShell(c, os.Stdin, cmd.OutOrStdout())
+		},
+	}
+}
+
+// runShell reads commands line by line and runs each of them as if given on the command line
+func runShell(c *cli, in io.Reader, out io.Writer) error {
+	scanner := bufio.NewScanner(in)
+	for {
+		fmt.Fprint(out, "ransim> ")
+		if !scanner.Scan() {
+			fmt.Fprintln(out)
+			return scanner.Err()
+		}
+		args := strings.Fields(scanner.Text())
+		if len(args) == 0 {
+			continue
+		}
+		if args[0] == "exit" || args[0] == "quit" {
+			return nil
+		}
+		if args[0] == "shell" {
+			fmt.Fprintln(out, "Already in the shell")
+			continue
+		}
+		// A fresh command tree per line keeps flags of previous commands from leaking into the next
+		root := getRootCommand(c)
+		root.SetArgs(args)
+		root.SetOut(out)
+		if err := root.Execute(); err != nil {
+			fmt.Fprintln(out, err)
+		}
+	}
+}
+
+// watchContext returns a context cancelled by an interrupt, allowing to end a watch without leaving the shell
+func watchContext() (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(context.Background())
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt)
+	go func() {
+		select {
+		case <-signals:
+			cancel()
+		case <-ctx.Done():
+		}
+		signal.Stop(signals)
+	}()
+	return ctx, cancel
+}
+
+func newTableWriter(cmd *cobra.Command) *tabwriter.Writer {
+	return tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+}
@@ -0,0 +1,142 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	modelapi "github.com/onosproject/onos-api/go/onos/ransim/model"
+	"github.com/onosproject/onos-api/go/onos/ransim/types"
+	"github.com/spf13/cobra"
+)
+
+func getRoutesCommand(c *cli) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "routes",
+		Short: "List, create and delete UE routes",
+	}
+	cmd.AddCommand(&cobra.Command{
+		Use:   "list",
+		Short: "List the routes",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runRoutesList(c, cmd)
+		},
+	})
+	create := &cobra.Command{
+		Use:   "create imsi lat,lng lat,lng...",
+		Short: "Create the route of a UE through the given waypoints",
+		Args:  cobra.MinimumNArgs(3),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runRoutesCreate(c, cmd, args)
+		},
+	}
+	create.Flags().String("color", "", "color used to display the route")
+	cmd.AddCommand(create)
+	cmd.AddCommand(&cobra.Command{
+		Use:   "delete imsi",
+		Short: "Delete the route of a UE",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runRoutesDelete(c, cmd, args[0])
+		},
+	})
+	return cmd
+}
+
+func runRoutesList(c *cli, cmd *cobra.Command) error {
+	conn, err := c.connection()
+	if err != nil {
+		return err
+	}
+	client := modelapi.NewRouteModelClient(conn)
+	stream, err := client.ListRoutes(context.Background(), &modelapi.ListRoutesRequest{})
+	if err != nil {
+		return err
+	}
+
+	w := newTableWriter(cmd)
+	fmt.Fprintf(w, "IMSI\tCOLOR\tWAYPOINTS\n")
+	for {
+		response, err := stream.Recv()
+		if err == io.EOF {
+			return w.Flush()
+		} else if err != nil {
+			return err
+		}
+		waypoints := make([]string, 0, len(response.Route.Waypoints))
+		for _, p := range response.Route.Waypoints {
+			waypoints = append(waypoints, fmt.Sprintf("%.6f,%.6f", p.Lat, p.Lng))
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\n", response.Route.RouteID, response.Route.Color, strings.Join(waypoints, " "))
+	}
+}
+
+func runRoutesCreate(c *cli, cmd *cobra.Command, args []string) error {
+	imsi, err := strconv.ParseUint(args[0], 10, 64)
+	if err != nil {
+		return err
+	}
+	route := &types.Route{RouteID: types.IMSI(imsi)}
+	route.Color, _ = cmd.Flags().GetString("color")
+	for _, arg := range args[1:] {
+		point, err := parsePoint(arg)
+		if err != nil {
+			return err
+		}
+		route.Waypoints = append(route.Waypoints, point)
+	}
+
+	conn, err := c.connection()
+	if err != nil {
+		return err
+	}
+	client := modelapi.NewRouteModelClient(conn)
+	_, err = client.CreateRoute(context.Background(), &modelapi.CreateRouteRequest{Route: route})
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "Created route of UE %d with %d waypoints\n", imsi, len(route.Waypoints))
+	return nil
+}
+
+func runRoutesDelete(c *cli, cmd *cobra.Command, arg string) error {
+	imsi, err := strconv.ParseUint(arg, 10, 64)
+	if err != nil {
+		return err
+	}
+	conn, err := c.connection()
+	if err != nil {
+		return err
+	}
+	client := modelapi.NewRouteModelClient(conn)
+	_, err = client.DeleteRoute(context.Background(), &modelapi.DeleteRouteRequest{IMSI: types.IMSI(imsi)})
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "Deleted route of UE %d\n", imsi)
+	return nil
+}
+
+// parsePoint parses a waypoint given as latitude and longitude in degrees separated by a comma
+func parsePoint(arg string) (*types.Point, error) {
+	coords := strings.Split(arg, ",")
+	if len(coords) != 2 {
+		return nil, fmt.Errorf("invalid waypoint %q; expected lat,lng", arg)
+	}
+	lat, err := strconv.ParseFloat(coords[0], 64)
+	if err != nil {
+		return nil, err
+	}
+	lng, err := strconv.ParseFloat(coords[1], 64)
+	if err != nil {
+		return nil, err
+	}
+	return &types.Point{Lat: lat, Lng: lng}, nil
+}
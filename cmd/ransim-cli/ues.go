@@ -0,0 +1,132 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"text/tabwriter"
+
+	simapi "github.com/onosproject/onos-api/go/onos/ransim/trafficsim"
+	"github.com/onosproject/onos-api/go/onos/ransim/types"
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const ueHeader = "IMSI\tTYPE\tLAT\tLNG\tHEADING\tSERVING CELL\tSTRENGTH\tCRNTI\tADMITTED\n"
+
+func getUEsCommand(c *cli) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "ues",
+		Short: "List, watch and scale the simulated UEs",
+	}
+	cmd.AddCommand(&cobra.Command{
+		Use:   "list",
+		Short: "List the UEs",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runUEsList(c, cmd)
+		},
+	})
+	cmd.AddCommand(&cobra.Command{
+		Use:   "watch",
+		Short: "Watch changes of the UEs",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runUEsWatch(c, cmd)
+		},
+	})
+	cmd.AddCommand(&cobra.Command{
+		Use:   "count number",
+		Short: "Set the number of UEs",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runUEsCount(c, cmd, args[0])
+		},
+	})
+	return cmd
+}
+
+func runUEsList(c *cli, cmd *cobra.Command) error {
+	conn, err := c.connection()
+	if err != nil {
+		return err
+	}
+	client := simapi.NewTrafficClient(conn)
+	stream, err := client.ListUes(context.Background(), &simapi.ListUesRequest{})
+	if err != nil {
+		return err
+	}
+
+	w := newTableWriter(cmd)
+	fmt.Fprint(w, ueHeader)
+	for {
+		response, err := stream.Recv()
+		if err == io.EOF {
+			return w.Flush()
+		} else if err != nil {
+			return err
+		}
+		printUE(w, response.Ue)
+	}
+}
+
+// runUEsWatch prints the changes of the UEs until interrupted
+func runUEsWatch(c *cli, cmd *cobra.Command) error {
+	conn, err := c.connection()
+	if err != nil {
+		return err
+	}
+	ctx, cancel := watchContext()
+	defer cancel()
+	client := simapi.NewTrafficClient(conn)
+	stream, err := client.WatchUes(ctx, &simapi.WatchUesRequest{NoReplay: true})
+	if err != nil {
+		return err
+	}
+
+	w := newTableWriter(cmd)
+	fmt.Fprint(w, ueHeader)
+	for {
+		response, err := stream.Recv()
+		if err == io.EOF || status.Code(err) == codes.Canceled {
+			return w.Flush()
+		} else if err != nil {
+			return err
+		}
+		printUE(w, response.Ue)
+		_ = w.Flush()
+	}
+}
+
+func runUEsCount(c *cli, cmd *cobra.Command, arg string) error {
+	number, err := strconv.ParseUint(arg, 10, 32)
+	if err != nil {
+		return err
+	}
+	conn, err := c.connection()
+	if err != nil {
+		return err
+	}
+	client := simapi.NewTrafficClient(conn)
+	_, err = client.SetNumberUEs(context.Background(), &simapi.SetNumberUEsRequest{Number: uint32(number)})
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "Number of UEs set to %d\n", number)
+	return nil
+}
+
+func printUE(w *tabwriter.Writer, ue *types.Ue) {
+	var lat, lng float64
+	if ue.Position != nil {
+		lat, lng = ue.Position.Lat, ue.Position.Lng
+	}
+	fmt.Fprintf(w, "%d\t%s\t%.6f\t%.6f\t%d\t%d\t%.2f\t%d\t%t\n", ue.IMSI, ue.Type, lat, lng, ue.Rotation,
+		ue.ServingTower, ue.ServingTowerStrength, ue.CRNTI, ue.Admitted)
+}
@@ -14,6 +14,16 @@ Arguments
 
 -certPath <the location of a client certificate>
 
+-clientAuth requires the northbound gRPC clients to present a certificate signed by the CA
+
+-auth requires a JWT bearer token on the northbound APIs, granting the roles of -authAdminGroups and -authReadOnlyGroups
+to tokens issued for the -authAudience
+
+-tenant name=modelName simulates the named model next to the default one, as the tenant given by the
+ransim-tenant metadata of the northbound requests
+
+-e2TLS secures the E2 connections with TLS using the above certificates
+
 
 See ../../docs/run.md for how to run the application.
 */
@@ -21,9 +31,19 @@ package main
 
 import (
 	"flag"
-
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/onosproject/onos-api/go/onos/ransim/types"
+	"github.com/onosproject/onos-lib-go/pkg/atomix"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
 	"github.com/onosproject/onos-lib-go/pkg/logging"
+	"github.com/onosproject/ran-simulator/pkg/auth"
 	"github.com/onosproject/ran-simulator/pkg/manager"
+	"github.com/onosproject/ran-simulator/pkg/shard"
+	"github.com/onosproject/ran-simulator/pkg/store/distributed"
 )
 
 var log = logging.GetLogger("main")
@@ -46,22 +66,95 @@ func main() {
 
 	var serviceModelPlugins arrayFlags
 	flag.Var(&serviceModelPlugins, "serviceModel", "names of service model plugins to load (repeated)")
+	var tenants arrayFlags
+	flag.Var(&tenants, "tenant", "tenant simulating its own model next to the default one, as name=modelName (repeated)")
 	caPath := flag.String("caPath", "", "path to CA certificate")
 	keyPath := flag.String("keyPath", "", "path to client private key")
 	certPath := flag.String("certPath", "", "path to client certificate")
+	clientAuth := flag.Bool("clientAuth", false, "require and verify the certificates of the northbound gRPC clients against the CA certificate")
+	authEnabled := flag.Bool("auth", false, "require a JWT bearer token on the northbound APIs, validated against SHARED_SECRET_KEY or the OIDC server at OIDC_SERVER_URL")
+	authAdminGroups := flag.String("authAdminGroups", "", "comma-separated token groups granted the admin role allowing to mutate the simulation")
+	authReadOnlyGroups := flag.String("authReadOnlyGroups", "", "comma-separated token groups granted the read-only role; empty grants it to all authenticated users")
+	authAudience := flag.String("authAudience", "", "audience the tokens must be issued for, i.e. listed by their aud claim; empty accepts any audience")
+	e2TLS := flag.Bool("e2TLS", false, "secure the E2 connections with TLS over SCTP, verifying the controllers against the CA certificate and presenting the client certificate")
 	grpcPort := flag.Int("grpcPort", 5150, "GRPC port for e2T server")
+	o1Port := flag.Int("o1Port", 5152, "HTTP port for O1 configuration server; zero disables the server")
+	a1Port := flag.Int("a1Port", 5153, "HTTP port for A1 policy server; zero disables the server")
 	modelName := flag.String("modelName", "model", "RANSim model name")
 	metricName := flag.String("metricName", "metric", "RANSim metric name")
+	faultMTBF := flag.Duration("faultMTBF", 0, "mean time between random faults; zero disables random faults")
+	faultMTTR := flag.Duration("faultMTTR", time.Minute, "mean time to repair random faults")
+	journalPort := flag.Int("journalPort", 5154, "HTTP port for journal queries; zero disables the server")
+	scenarioPort := flag.Int("scenarioPort", 5155, "HTTP port for scenario control, e.g. forced handovers; zero disables the server")
+	adminPort := flag.Int("adminPort", 5156, "HTTP port for admin operations, e.g. subscription audits; zero disables the server")
+	adminPprof := flag.Bool("adminPprof", false, "serve the net/http/pprof endpoints under /debug/pprof/ on the admin port")
+	journalPath := flag.String("journal", "", "path of the file to persist the journal of simulation milestones to as line-delimited JSON")
+	exportInterval := flag.Duration("exportInterval", 10*time.Second, "KPI export sampling interval")
+	exportCSV := flag.String("exportCSV", "", "path of the CSV file to export KPIs to; empty disables CSV export")
+	exportInflux := flag.String("exportInflux", "", "InfluxDB write URL to export KPIs to, e.g. http://influxdb:8086/write?db=ransim; empty disables InfluxDB export")
+	topoAddress := flag.String("topoAddress", "", "address of onos-topo to register the nodes and cells with, e.g. onos-topo:5150; empty disables the registration")
+	e2CaptureDir := flag.String("e2CaptureDir", "", "directory to capture the E2AP traffic of each node to as pcap file; empty disables the capture")
+	logDir := flag.String("logDir", "", "directory the log files set via the admin API are confined to; empty disables log file sinks via the admin API")
+	e2SetupParallelism := flag.Int("e2SetupParallelism", 32, "maximum number of nodes performing the E2 setup concurrently; zero or less sets up all nodes at once")
+	shardIndex := flag.Int("shardIndex", -1, "index of this instance among the instances sharing the model; negative derives it from the ordinal of the stateful set pod")
+	shardCount := flag.Int("shardCount", 0, "number of instances sharing the model; fewer than two disables sharding unless shardNodes are given")
+	shardStrategy := flag.String("shardStrategy", shard.StrategyIndex, "assignment of nodes to the instances sharing the model: index or hash")
+	shardNodes := flag.String("shardNodes", "", "comma-separated E2 node IDs explicitly owned by this instance")
+	shardPeers := flag.String("shardPeers", "", "comma-separated northbound addresses of all instances ordered by index; an address containing %d is expanded for every index")
+	storeBackend := flag.String("storeBackend", distributed.BackendMemory, "backend of the node, cell and UE stores: memory or atomix")
+	atomixController := flag.String("atomixController", "", "address of the Atomix controller; empty derives it from the namespace of the pod")
+	atomixDatabase := flag.String("atomixDatabase", "ran-simulator", "name of the Atomix database backing the stores")
 	flag.Parse()
 
+	shardConfig, err := getShardConfig(*shardIndex, *shardCount, *shardStrategy, *shardNodes, *shardPeers)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	tenantConfigs, err := getTenants(tenants)
+	if err != nil {
+		log.Fatal(err)
+	}
+
 	cfg := &manager.Config{
-		CAPath:              *caPath,
-		KeyPath:             *keyPath,
-		CertPath:            *certPath,
+		CAPath:     *caPath,
+		KeyPath:    *keyPath,
+		CertPath:   *certPath,
+		ClientAuth: *clientAuth,
+		E2TLS:      *e2TLS,
+		Auth: auth.Config{
+			Enabled:        *authEnabled,
+			AdminGroups:    splitList(*authAdminGroups),
+			ReadOnlyGroups: splitList(*authReadOnlyGroups),
+			Audience:       *authAudience,
+		},
 		GRPCPort:            *grpcPort,
+		O1Port:              *o1Port,
+		A1Port:              *a1Port,
 		ServiceModelPlugins: serviceModelPlugins,
 		ModelName:           *modelName,
 		MetricName:          *metricName,
+		FaultMTBF:           *faultMTBF,
+		FaultMTTR:           *faultMTTR,
+		JournalPort:         *journalPort,
+		JournalPath:         *journalPath,
+		ScenarioPort:        *scenarioPort,
+		AdminPort:           *adminPort,
+		AdminPprof:          *adminPprof,
+		ExportInterval:      *exportInterval,
+		ExportCSVPath:       *exportCSV,
+		ExportInfluxURL:     *exportInflux,
+		TopoAddress:         *topoAddress,
+		E2CaptureDir:        *e2CaptureDir,
+		LogDir:              *logDir,
+		E2SetupParallelism:  *e2SetupParallelism,
+		Shard:               shardConfig,
+		Tenants:             tenantConfigs,
+		Store: distributed.Config{
+			Backend:  *storeBackend,
+			Atomix:   atomix.Config{Controller: *atomixController},
+			Database: *atomixDatabase,
+		},
 	}
 
 	mgr, err := manager.NewManager(cfg)
@@ -71,3 +164,57 @@ func main() {
 		mgr.Close()
 	}
 }
+
+// splitList returns the items of the comma-separated list
+func splitList(list string) []string {
+	var items []string
+	for _, item := range strings.Split(list, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// getTenants returns the tenants given on the command line as name=modelName
+func getTenants(values []string) ([]manager.Tenant, error) {
+	var tenants []manager.Tenant
+	for _, value := range values {
+		i := strings.Index(value, "=")
+		if i <= 0 || i == len(value)-1 {
+			return nil, errors.New(errors.Invalid, "invalid tenant %s; expected name=modelName", value)
+		}
+		tenants = append(tenants, manager.Tenant{Name: value[:i], ModelName: value[i+1:]})
+	}
+	return tenants, nil
+}
+
+// getShardConfig returns the sharding configuration given on the command line
+func getShardConfig(index int, count int, strategy string, nodes string, peers string) (shard.Config, error) {
+	config := shard.Config{Index: index, Count: count, Strategy: strategy}
+	if nodes != "" {
+		for _, node := range strings.Split(nodes, ",") {
+			enbID, err := strconv.ParseUint(strings.TrimSpace(node), 10, 32)
+			if err != nil {
+				return config, err
+			}
+			config.Nodes = append(config.Nodes, types.EnbID(enbID))
+		}
+	}
+	if peers != "" {
+		config.Peers = strings.Split(peers, ",")
+	}
+	if config.Index < 0 {
+		config.Index = 0
+		if config.Count > 1 {
+			hostname, err := os.Hostname()
+			if err != nil {
+				return config, err
+			}
+			if config.Index, err = shard.IndexFromHostname(hostname); err != nil {
+				return config, err
+			}
+		}
+	}
+	return config, nil
+}
@@ -4,13 +4,16 @@ go 1.15
 
 require (
 	github.com/Microsoft/go-winio v0.4.15 // indirect
+	github.com/atomix/go-client v0.4.1
 	github.com/cenkalti/backoff v2.2.1+incompatible
 	github.com/docker/docker v1.13.1 // indirect
 	github.com/garyburd/redigo v1.1.1-0.20170914051019-70e1b1943d4f // indirect
+	github.com/golang-jwt/jwt/v4 v4.5.2
 	github.com/google/uuid v1.1.2
 	github.com/googleapis/gnostic v0.3.0 // indirect
 	github.com/gorilla/mux v1.8.0 // indirect
 	github.com/gregjones/httpcache v0.0.0-20190611155906-901d90724c79 // indirect
+	github.com/grpc-ecosystem/go-grpc-middleware v1.2.0
 	github.com/mattn/go-colorable v0.1.4 // indirect
 	github.com/mattn/go-isatty v0.0.12 // indirect
 	github.com/onosproject/helmit v0.6.8
@@ -22,6 +25,7 @@ require (
 	github.com/onosproject/onos-lib-go v0.7.7
 	github.com/onosproject/onos-ric-sdk-go v0.7.11
 	github.com/onosproject/onos-test v0.6.4
+	github.com/openconfig/gnmi v0.0.0-20200617225440-d2b4e6a45802
 	github.com/opencontainers/go-digest v1.0.0 // indirect
 	github.com/pmcxs/hexgrid v0.0.0-20190126214921-42796ac894ab
 	github.com/prometheus/client_golang v1.4.1 // indirect
@@ -0,0 +1,100 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package a1
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/onosproject/onos-api/go/onos/ransim/types"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/onosproject/ran-simulator/pkg/mobility"
+)
+
+const (
+	// TrafficSteeringPolicyType policy type expressing UE preferences for cells
+	TrafficSteeringPolicyType = "ORAN_TrafficSteeringPreference_2.0.0"
+	// LoadTargetPolicyType policy type expressing target maximum load of cells
+	LoadTargetPolicyType = "ORAN_CellLoadTarget_1.0.0"
+)
+
+// PolicyTypes lists the supported policy types
+var PolicyTypes = []string{TrafficSteeringPolicyType, LoadTargetPolicyType}
+
+// Scope identifies the UEs and cells a policy applies to; an empty scope applies to all of them
+type Scope struct {
+	UEID       types.IMSI   `json:"ueId,omitempty"`
+	CellIDList []types.ECGI `json:"cellIdList,omitempty"`
+}
+
+// TSPResource is a list of cells with the preference of the UEs in scope for them
+type TSPResource struct {
+	CellIDList []types.ECGI `json:"cellIdList"`
+	Preference string       `json:"preference"`
+}
+
+// Policy is an A1 policy; the statements present depend on the policy type
+type Policy struct {
+	Scope        Scope         `json:"scope"`
+	TSPResources []TSPResource `json:"tspResources,omitempty"`
+	MaxLoad      float64       `json:"maxLoad,omitempty"`
+}
+
+// ParsePolicy parses and validates a policy of the specified type
+func ParsePolicy(policyType string, data []byte) (*Policy, error) {
+	policy := &Policy{}
+	if err := json.Unmarshal(data, policy); err != nil {
+		return nil, errors.New(errors.Invalid, "malformed policy: %v", err)
+	}
+	switch policyType {
+	case TrafficSteeringPolicyType:
+		if len(policy.TSPResources) == 0 {
+			return nil, errors.New(errors.Invalid, "traffic steering policy must have at least one resource")
+		}
+		for _, resource := range policy.TSPResources {
+			if _, err := parsePreference(resource.Preference); err != nil {
+				return nil, err
+			}
+		}
+	case LoadTargetPolicyType:
+		if policy.MaxLoad <= 0 || policy.MaxLoad > 1 {
+			return nil, errors.New(errors.Invalid, "maximum load must be in range (0, 1]")
+		}
+	default:
+		return nil, errors.New(errors.NotFound, "unknown policy type %s", policyType)
+	}
+	return policy, nil
+}
+
+func parsePreference(preference string) (mobility.Preference, error) {
+	switch strings.ToUpper(preference) {
+	case "SHALL":
+		return mobility.Shall, nil
+	case "PREFER":
+		return mobility.Prefer, nil
+	case "AVOID":
+		return mobility.Avoid, nil
+	case "FORBID":
+		return mobility.Forbid, nil
+	}
+	return mobility.NoPreference, errors.New(errors.Invalid, "unknown preference %s", preference)
+}
+
+// appliesTo returns true if the policy scope covers the specified UE and cell
+func (s Scope) appliesTo(imsi types.IMSI, ecgi types.ECGI) bool {
+	if s.UEID != 0 && s.UEID != imsi {
+		return false
+	}
+	return s.CellIDList == nil || containsCell(s.CellIDList, ecgi)
+}
+
+func containsCell(cells []types.ECGI, ecgi types.ECGI) bool {
+	for _, c := range cells {
+		if c == ecgi {
+			return true
+		}
+	}
+	return false
+}
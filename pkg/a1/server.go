@@ -0,0 +1,167 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package a1
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/onosproject/onos-lib-go/pkg/logging"
+)
+
+var log = logging.GetLogger("a1")
+
+const policyTypesPath = "/a1-p/policytypes"
+
+// Server is an HTTP server implementing a subset of the A1-P policy management interface
+type Server struct {
+	store    *Store
+	onChange func()
+	server   *http.Server
+}
+
+// NewServer creates a new A1 policy server listening on the specified port; the onChange
+// function is invoked whenever a policy is created, replaced or deleted
+func NewServer(store *Store, port int, onChange func()) *Server {
+	s := &Server{
+		store:    store,
+		onChange: onChange,
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc(policyTypesPath, s.listPolicyTypes)
+	mux.HandleFunc(policyTypesPath+"/", s.handlePolicies)
+	s.server = &http.Server{
+		Addr:    fmt.Sprintf(":%d", port),
+		Handler: mux,
+	}
+	return s
+}
+
+// Use wraps the handler of the A1 server with the given middleware before it serves
+func (s *Server) Use(middleware func(http.Handler) http.Handler) {
+	s.server.Handler = middleware(s.server.Handler)
+}
+
+// Serve starts serving the A1 requests in the background
+func (s *Server) Serve() {
+	go func() {
+		log.Info("Started A1 server on ", s.server.Addr)
+		if err := s.server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Error(err)
+		}
+	}()
+}
+
+// Stop stops the A1 server
+func (s *Server) Stop() {
+	if err := s.server.Shutdown(context.Background()); err != nil {
+		log.Error(err)
+	}
+}
+
+func (s *Server) listPolicyTypes(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+	writeJSON(w, http.StatusOK, PolicyTypes)
+}
+
+// handlePolicies handles requests for /a1-p/policytypes/{policyTypeId}/policies[/{policyId}]
+func (s *Server) handlePolicies(w http.ResponseWriter, r *http.Request) {
+	parts := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, policyTypesPath), "/"), "/")
+	if len(parts) < 2 || parts[1] != "policies" || len(parts) > 3 {
+		http.NotFound(w, r)
+		return
+	}
+	policyType := parts[0]
+	if len(parts) == 2 {
+		if r.Method != http.MethodGet {
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+			return
+		}
+		ids, err := s.store.List(policyType)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, ids)
+		return
+	}
+
+	policyID := parts[2]
+	switch r.Method {
+	case http.MethodGet:
+		policy, err := s.store.Get(policyType, policyID)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, policy)
+	case http.MethodPut:
+		data, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		policy, err := ParsePolicy(policyType, data)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		created, err := s.store.Put(policyType, policyID, policy)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		log.Infof("Policy %s of type %s stored", policyID, policyType)
+		s.changed()
+		if created {
+			w.WriteHeader(http.StatusCreated)
+		} else {
+			w.WriteHeader(http.StatusOK)
+		}
+	case http.MethodDelete:
+		if err := s.store.Delete(policyType, policyID); err != nil {
+			writeError(w, err)
+			return
+		}
+		log.Infof("Policy %s of type %s deleted", policyID, policyType)
+		s.changed()
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *Server) changed() {
+	if s.onChange != nil {
+		go s.onChange()
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(value); err != nil {
+		log.Warn(err)
+	}
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.IsNotFound(err):
+		status = http.StatusNotFound
+	case errors.IsInvalid(err):
+		status = http.StatusBadRequest
+	}
+	http.Error(w, err.Error(), status)
+}
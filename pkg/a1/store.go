@@ -0,0 +1,118 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package a1
+
+import (
+	"sync"
+
+	"github.com/onosproject/onos-api/go/onos/ransim/types"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/onosproject/ran-simulator/pkg/mobility"
+)
+
+// Store keeps the A1 policies and evaluates them for the simulation models
+type Store struct {
+	mu       sync.RWMutex
+	policies map[string]map[string]*Policy
+}
+
+// NewStore creates a new policy store
+func NewStore() *Store {
+	policies := make(map[string]map[string]*Policy)
+	for _, policyType := range PolicyTypes {
+		policies[policyType] = make(map[string]*Policy)
+	}
+	return &Store{
+		policies: policies,
+	}
+}
+
+// Put creates or replaces the policy with the specified ID; returns true if the policy was created
+func (s *Store) Put(policyType string, policyID string, policy *Policy) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	policies, ok := s.policies[policyType]
+	if !ok {
+		return false, errors.New(errors.NotFound, "unknown policy type %s", policyType)
+	}
+	_, exists := policies[policyID]
+	policies[policyID] = policy
+	return !exists, nil
+}
+
+// Get returns the policy with the specified ID
+func (s *Store) Get(policyType string, policyID string) (*Policy, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	policy, ok := s.policies[policyType][policyID]
+	if !ok {
+		return nil, errors.New(errors.NotFound, "policy %s not found", policyID)
+	}
+	return policy, nil
+}
+
+// Delete deletes the policy with the specified ID
+func (s *Store) Delete(policyType string, policyID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.policies[policyType][policyID]; !ok {
+		return errors.New(errors.NotFound, "policy %s not found", policyID)
+	}
+	delete(s.policies[policyType], policyID)
+	return nil
+}
+
+// List returns the IDs of all policies of the specified type
+func (s *Store) List(policyType string) ([]string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	policies, ok := s.policies[policyType]
+	if !ok {
+		return nil, errors.New(errors.NotFound, "unknown policy type %s", policyType)
+	}
+	ids := make([]string, 0, len(policies))
+	for id := range policies {
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// Preference returns the preference of the UE served by the serving cell for the cell; the scope of traffic
+// steering policies selects the UEs by their serving cell, and the strongest preference among the applicable
+// policies wins, i.e. FORBID overrides SHALL
+func (s *Store) Preference(imsi types.IMSI, serving types.ECGI, ecgi types.ECGI) mobility.Preference {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	result := mobility.NoPreference
+	for _, policy := range s.policies[TrafficSteeringPolicyType] {
+		if !policy.Scope.appliesTo(imsi, serving) {
+			continue
+		}
+		for _, resource := range policy.TSPResources {
+			if !containsCell(resource.CellIDList, ecgi) {
+				continue
+			}
+			if preference, err := parsePreference(resource.Preference); err == nil && preference > result {
+				result = preference
+			}
+		}
+	}
+	return result
+}
+
+// MaxLoad returns the lowest target maximum load of the applicable load target policies
+func (s *Store) MaxLoad(ecgi types.ECGI) float64 {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	result := 1.0
+	for _, policy := range s.policies[LoadTargetPolicyType] {
+		if policy.Scope.appliesTo(0, ecgi) && policy.MaxLoad < result {
+			result = policy.MaxLoad
+		}
+	}
+	return result
+}
+
+var _ mobility.Policies = &Store{}
@@ -0,0 +1,66 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package a1
+
+import (
+	"testing"
+
+	"github.com/onosproject/onos-api/go/onos/ransim/types"
+	"github.com/onosproject/ran-simulator/pkg/mobility"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPolicies(t *testing.T) {
+	store := NewStore()
+	imsi := types.IMSI(123)
+	ecgi1 := types.ECGI(84325717505)
+	ecgi2 := types.ECGI(84325717506)
+
+	_, err := ParsePolicy(TrafficSteeringPolicyType, []byte(`{"tspResources": []}`))
+	assert.Error(t, err)
+	_, err = ParsePolicy(TrafficSteeringPolicyType, []byte(`{"tspResources": [{"cellIdList": [1], "preference": "MAYBE"}]}`))
+	assert.Error(t, err)
+	_, err = ParsePolicy(LoadTargetPolicyType, []byte(`{"maxLoad": 1.5}`))
+	assert.Error(t, err)
+	_, err = ParsePolicy("unknown", []byte(`{}`))
+	assert.Error(t, err)
+
+	tsp, err := ParsePolicy(TrafficSteeringPolicyType,
+		[]byte(`{"scope": {"ueId": 123}, "tspResources": [{"cellIdList": [84325717505], "preference": "FORBID"}, {"cellIdList": [84325717506], "preference": "prefer"}]}`))
+	assert.NoError(t, err)
+	created, err := store.Put(TrafficSteeringPolicyType, "tsp1", tsp)
+	assert.NoError(t, err)
+	assert.True(t, created)
+
+	assert.Equal(t, mobility.Forbid, store.Preference(imsi, ecgi2, ecgi1))
+	assert.Equal(t, mobility.Prefer, store.Preference(imsi, ecgi1, ecgi2))
+	assert.Equal(t, mobility.NoPreference, store.Preference(456, ecgi2, ecgi1))
+
+	// FORBID overrides SHALL, and the scope selects the UEs by their serving cell
+	shall, err := ParsePolicy(TrafficSteeringPolicyType,
+		[]byte(`{"scope": {"cellIdList": [84325717506]}, "tspResources": [{"cellIdList": [84325717505], "preference": "SHALL"}]}`))
+	assert.NoError(t, err)
+	_, err = store.Put(TrafficSteeringPolicyType, "tsp2", shall)
+	assert.NoError(t, err)
+	assert.Equal(t, mobility.Forbid, store.Preference(imsi, ecgi2, ecgi1))
+	assert.Equal(t, mobility.Shall, store.Preference(456, ecgi2, ecgi1))
+	assert.Equal(t, mobility.NoPreference, store.Preference(456, ecgi1, ecgi1))
+	assert.NoError(t, store.Delete(TrafficSteeringPolicyType, "tsp2"))
+
+	lt, err := ParsePolicy(LoadTargetPolicyType, []byte(`{"scope": {"cellIdList": [84325717505]}, "maxLoad": 0.5}`))
+	assert.NoError(t, err)
+	_, err = store.Put(LoadTargetPolicyType, "lt1", lt)
+	assert.NoError(t, err)
+	assert.Equal(t, 0.5, store.MaxLoad(ecgi1))
+	assert.Equal(t, 1.0, store.MaxLoad(ecgi2))
+
+	ids, err := store.List(TrafficSteeringPolicyType)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"tsp1"}, ids)
+
+	assert.NoError(t, store.Delete(TrafficSteeringPolicyType, "tsp1"))
+	assert.Error(t, store.Delete(TrafficSteeringPolicyType, "tsp1"))
+	assert.Equal(t, mobility.NoPreference, store.Preference(imsi, ecgi2, ecgi1))
+}
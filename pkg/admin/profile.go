@@ -0,0 +1,89 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package admin
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/pprof"
+	"runtime"
+	rpprof "runtime/pprof"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+)
+
+const (
+	profilesPath = "/profiles/"
+	pprofPath    = "/debug/pprof/"
+
+	defaultProfileDuration = 30 * time.Second
+	maxProfileDuration     = 10 * time.Minute
+)
+
+// EnablePprof serves the net/http/pprof endpoints under /debug/pprof/ next to the admin operations, wrapped with the
+// given middleware, e.g. requiring the admin role for these GET requests; it must be called before Serve
+func (s *Server) EnablePprof(middleware func(http.Handler) http.Handler) {
+	s.mux.Handle(pprofPath, middleware(http.HandlerFunc(pprof.Index)))
+	s.mux.Handle(pprofPath+"cmdline", middleware(http.HandlerFunc(pprof.Cmdline)))
+	s.mux.Handle(pprofPath+"profile", middleware(http.HandlerFunc(pprof.Profile)))
+	s.mux.Handle(pprofPath+"symbol", middleware(http.HandlerFunc(pprof.Symbol)))
+	s.mux.Handle(pprofPath+"trace", middleware(http.HandlerFunc(pprof.Trace)))
+	log.Info("Enabled pprof endpoints on admin server")
+}
+
+// captureProfile handles POST /profiles/cpu?seconds={n} capturing a CPU profile over the given number of seconds, 30
+// by default, and POST /profiles/heap capturing a heap profile after a garbage collection; the profile is returned in
+// the gzipped protobuf format of pprof
+func (s *Server) captureProfile(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+	var profile bytes.Buffer
+	name := strings.Trim(strings.TrimPrefix(r.URL.Path, profilesPath), "/")
+	switch name {
+	case "cpu":
+		duration := defaultProfileDuration
+		if value := r.URL.Query().Get("seconds"); value != "" {
+			seconds, err := strconv.ParseUint(value, 10, 32)
+			if err != nil || seconds == 0 || time.Duration(seconds)*time.Second > maxProfileDuration {
+				writeError(w, errors.New(errors.Invalid, "invalid seconds %s", value))
+				return
+			}
+			duration = time.Duration(seconds) * time.Second
+		}
+		if err := rpprof.StartCPUProfile(&profile); err != nil {
+			writeError(w, errors.New(errors.Unavailable, "CPU profile already in progress"))
+			return
+		}
+		log.Infof("Capturing CPU profile for %s", duration)
+		select {
+		case <-time.After(duration):
+		case <-r.Context().Done():
+		}
+		rpprof.StopCPUProfile()
+		if r.Context().Err() != nil {
+			return
+		}
+	case "heap":
+		runtime.GC()
+		if err := rpprof.Lookup("heap").WriteTo(&profile, 0); err != nil {
+			writeError(w, err)
+			return
+		}
+	default:
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", "application/octet-stream")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", name+".pprof"))
+	if _, err := profile.WriteTo(w); err != nil {
+		log.Warn(err)
+	}
+}
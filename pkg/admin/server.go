@@ -0,0 +1,379 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/onosproject/onos-api/go/onos/ransim/types"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/onosproject/onos-lib-go/pkg/logging"
+	"github.com/onosproject/ran-simulator/pkg/logsink"
+	"github.com/onosproject/ran-simulator/pkg/stats"
+	"github.com/onosproject/ran-simulator/pkg/store/subscriptions"
+)
+
+var log = logging.GetLogger("admin")
+
+const (
+	subscriptionAuditPath = "/subscriptions/audit"
+	statsPath             = "/stats"
+	loggersPath           = "/logging/loggers/"
+	logSinkPath           = "/logging/sink"
+	counterResetPath      = "/metrics/counters/reset"
+	runPath               = "/run"
+	nodesConnectedPath    = "/nodes/connected"
+)
+
+// SubscriptionAuditor audits the E2 subscriptions of the simulated nodes
+type SubscriptionAuditor interface {
+	// AuditSubscriptions audits the subscriptions of the nodes created until the audit started; unless nil, known
+	// lists the subscriptions E2T knows about per node, in which case only the listed nodes are audited
+	AuditSubscriptions(known map[types.EnbID][]subscriptions.ID, clean bool, started time.Time) (map[types.EnbID]subscriptions.Audit, error)
+}
+
+// StatsCollector collects the statistics of the simulation
+type StatsCollector interface {
+	// Stats returns a snapshot of the current statistics of the simulation
+	Stats(ctx context.Context) (*stats.Snapshot, error)
+}
+
+// CounterResetter resets the counters of the metrics store
+type CounterResetter interface {
+	// ResetCounters resets the named counters, or all counters if none is named, of the specified entity or of all
+	// entities if the entity ID is zero
+	ResetCounters(ctx context.Context, entityID uint64, names []string) error
+}
+
+// RunState is the state of the simulation run
+type RunState struct {
+	// Running is true while the E2 agents and the controllers of the simulation are running
+	Running bool `json:"running"`
+	// Iteration counts the resets of the simulation since it started
+	Iteration uint64 `json:"iteration"`
+	// Since is the time the simulation was last started or stopped
+	Since time.Time `json:"since"`
+}
+
+// RunController controls the lifecycle of the simulation run
+type RunController interface {
+	// RunState returns the state of the simulation run
+	RunState() RunState
+	// StartRun starts the E2 agents and the controllers of the simulation, unless running
+	StartRun(ctx context.Context) error
+	// StopRun stops the E2 agents, dropping their subscriptions, and the controllers of the simulation, unless stopped
+	StopRun(ctx context.Context) error
+	// ResetRun stops the simulation, returns all stores to the initial state of the loaded model and starts the
+	// simulation again if requested
+	ResetRun(ctx context.Context, start bool) error
+}
+
+// ConnectionWaiter waits for the E2 nodes to connect to their E2T controllers
+type ConnectionWaiter interface {
+	// WaitConnected waits until all E2 nodes are connected to their controller, failing as soon as any node failed
+	// to connect
+	WaitConnected(ctx context.Context) error
+}
+
+// Server is an HTTP server for administrative operations helping to debug long-running simulations
+type Server struct {
+	auditor   SubscriptionAuditor
+	collector StatsCollector
+	resetter  CounterResetter
+	runner    RunController
+	waiter    ConnectionWaiter
+	logDir    string
+	mux       *http.ServeMux
+	server    *http.Server
+}
+
+// NewServer creates a new admin server listening on the specified port
+func NewServer(auditor SubscriptionAuditor, collector StatsCollector, resetter CounterResetter, runner RunController,
+	waiter ConnectionWaiter, port int) *Server {
+	s := &Server{
+		auditor:   auditor,
+		collector: collector,
+		resetter:  resetter,
+		runner:    runner,
+		waiter:    waiter,
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc(subscriptionAuditPath, s.auditSubscriptions)
+	mux.HandleFunc(statsPath, s.getStats)
+	mux.HandleFunc(loggersPath, s.handleLogger)
+	mux.HandleFunc(logSinkPath, s.handleLogSink)
+	mux.HandleFunc(counterResetPath, s.resetCounters)
+	mux.HandleFunc(runPath, s.handleRun)
+	mux.HandleFunc(runPath+"/", s.handleRun)
+	mux.HandleFunc(nodesConnectedPath, s.waitConnected)
+	mux.HandleFunc(profilesPath, s.captureProfile)
+	s.mux = mux
+	s.server = &http.Server{
+		Addr:    fmt.Sprintf(":%d", port),
+		Handler: mux,
+	}
+	return s
+}
+
+// SetLogDir sets the directory the log files of the file sinks set via the API are confined to; file sinks cannot be
+// set via the API if it is empty
+func (s *Server) SetLogDir(dir string) {
+	s.logDir = dir
+}
+
+// Use wraps the handler of the admin server with the given middleware, e.g. authorizing the requests; it must be
+// called before Serve
+func (s *Server) Use(middleware func(http.Handler) http.Handler) {
+	s.server.Handler = middleware(s.server.Handler)
+}
+
+// Serve starts serving the admin requests in the background
+func (s *Server) Serve() {
+	go func() {
+		log.Info("Started admin server on ", s.server.Addr)
+		if err := s.server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Error(err)
+		}
+	}()
+}
+
+// Stop stops the admin server
+func (s *Server) Stop() {
+	if err := s.server.Shutdown(context.Background()); err != nil {
+		log.Error(err)
+	}
+}
+
+// auditSubscriptions handles GET /subscriptions/audit auditing the subscriptions against the running report loops
+// and POST /subscriptions/audit?clean={true|false} additionally auditing them against the subscriptions E2T knows
+// about, given by the optional JSON body mapping node IDs to subscription IDs
+func (s *Server) auditSubscriptions(w http.ResponseWriter, r *http.Request) {
+	started := time.Now()
+	var known map[types.EnbID][]subscriptions.ID
+	clean := false
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPost:
+		if value := r.URL.Query().Get("clean"); value != "" {
+			var err error
+			if clean, err = strconv.ParseBool(value); err != nil {
+				writeError(w, errors.New(errors.Invalid, "invalid clean %s", value))
+				return
+			}
+		}
+		if err := json.NewDecoder(r.Body).Decode(&known); err != nil && err != io.EOF {
+			writeError(w, errors.New(errors.Invalid, err.Error()))
+			return
+		}
+	default:
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	audits, err := s.auditor.AuditSubscriptions(known, clean, started)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	for enbID, audit := range audits {
+		if !audit.Clean() {
+			log.Warnf("Subscriptions of E2 node %d are inconsistent: %+v", enbID, audit)
+		}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(audits); err != nil {
+		log.Warn(err)
+	}
+}
+
+// getStats handles GET /stats returning a snapshot of the statistics of the simulation
+func (s *Server) getStats(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+	snapshot, err := s.collector.Stats(r.Context())
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(snapshot); err != nil {
+		log.Warn(err)
+	}
+}
+
+// loggerLevel is the level of a logger
+type loggerLevel struct {
+	Name  string `json:"name"`
+	Level string `json:"level"`
+}
+
+// handleLogger handles GET /logging/loggers/{name} returning the level of the logger, e.g. sm/kpm2, and
+// PUT /logging/loggers/{name}?level={debug|info|warn|error} changing it
+func (s *Server) handleLogger(w http.ResponseWriter, r *http.Request) {
+	name := strings.Trim(strings.TrimPrefix(r.URL.Path, loggersPath), "/")
+	if name == "" {
+		http.NotFound(w, r)
+		return
+	}
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPut:
+		level, err := logsink.ParseLevel(r.URL.Query().Get("level"))
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		logsink.SetLevel(name, level)
+	default:
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	level := loggerLevel{Name: name, Level: strings.ToLower(logsink.GetLevel(name).String())}
+	if err := json.NewEncoder(w).Encode(level); err != nil {
+		log.Warn(err)
+	}
+}
+
+// handleLogSink handles GET /logging/sink returning the current sink of the logs and PUT /logging/sink redirecting
+// the logs to the sink given by the JSON body
+func (s *Server) handleLogSink(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPut:
+		var sink logsink.Sink
+		if err := json.NewDecoder(r.Body).Decode(&sink); err != nil {
+			writeError(w, errors.New(errors.Invalid, err.Error()))
+			return
+		}
+		sink, err := logsink.Confine(sink, s.logDir)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		if err := logsink.Redirect(sink); err != nil {
+			writeError(w, err)
+			return
+		}
+	default:
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(logsink.Current()); err != nil {
+		log.Warn(err)
+	}
+}
+
+// resetCounters handles POST /metrics/counters/reset?entity={id}&name={name} resetting the named counters, or all
+// counters if no name is given, of the entity or of all entities if no entity is given
+func (s *Server) resetCounters(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+	var entityID uint64
+	if value := r.URL.Query().Get("entity"); value != "" {
+		var err error
+		if entityID, err = strconv.ParseUint(value, 10, 64); err != nil {
+			writeError(w, errors.New(errors.Invalid, "invalid entity %s", value))
+			return
+		}
+	}
+	if err := s.resetter.ResetCounters(r.Context(), entityID, r.URL.Query()["name"]); err != nil {
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleRun handles GET /run returning the state of the simulation run and POST /run/{start|stop|reset} controlling
+// it; POST /run/reset?start={true|false} returns the stores to the initial state of the loaded model and restarts the
+// simulation, unless start is false
+func (s *Server) handleRun(w http.ResponseWriter, r *http.Request) {
+	action := strings.Trim(strings.TrimPrefix(r.URL.Path, runPath), "/")
+	if action == "" && r.Method != http.MethodGet || action != "" && r.Method != http.MethodPost {
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+	var err error
+	switch action {
+	case "":
+	case "start":
+		err = s.runner.StartRun(r.Context())
+	case "stop":
+		err = s.runner.StopRun(r.Context())
+	case "reset":
+		start := true
+		if value := r.URL.Query().Get("start"); value != "" {
+			if start, err = strconv.ParseBool(value); err != nil {
+				writeError(w, errors.New(errors.Invalid, "invalid start %s", value))
+				return
+			}
+		}
+		err = s.runner.ResetRun(r.Context(), start)
+	default:
+		http.NotFound(w, r)
+		return
+	}
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(s.runner.RunState()); err != nil {
+		log.Warn(err)
+	}
+}
+
+// waitConnected handles GET /nodes/connected?timeout={duration} responding once all E2 nodes are connected to their
+// controller, or as soon as any node failed to connect or the timeout elapsed; without a timeout, it responds at once
+func (s *Server) waitConnected(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+	timeout := time.Duration(0)
+	if value := r.URL.Query().Get("timeout"); value != "" {
+		var err error
+		if timeout, err = time.ParseDuration(value); err != nil || timeout < 0 {
+			writeError(w, errors.New(errors.Invalid, "invalid timeout %s", value))
+			return
+		}
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), timeout)
+	defer cancel()
+	if err := s.waiter.WaitConnected(ctx); err != nil {
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.IsNotFound(err):
+		status = http.StatusNotFound
+	case errors.IsInvalid(err):
+		status = http.StatusBadRequest
+	case errors.IsForbidden(err):
+		status = http.StatusForbidden
+	case errors.IsUnavailable(err):
+		status = http.StatusServiceUnavailable
+	case errors.IsTimeout(err):
+		status = http.StatusGatewayTimeout
+	}
+	http.Error(w, err.Error(), status)
+}
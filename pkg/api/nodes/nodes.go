@@ -7,6 +7,8 @@ package nodes
 
 import (
 	"context"
+	"fmt"
+	"sort"
 
 	"github.com/onosproject/ran-simulator/pkg/store/event"
 
@@ -17,15 +19,37 @@ import (
 	"github.com/onosproject/ran-simulator/pkg/model"
 	"github.com/onosproject/ran-simulator/pkg/store/nodes"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
 )
 
 var log = liblog.GetLogger("api", "nodes")
 
+// Agent control commands acted upon by the simulator; other commands are merely recorded as the node status
+const (
+	// StartCommand starts the E2 agent of the node
+	StartCommand = "start"
+	// StopCommand stops the E2 agent of the node
+	StopCommand = "stop"
+)
+
+// ConnectionsMetadataKey is the response header key of the states of the connections of a node to its controllers
+const ConnectionsMetadataKey = "connections"
+
+// Agents allows stopping and starting the E2 agents of individual nodes
+type Agents interface {
+	// StartAgent starts the E2 agent of the specified node
+	StartAgent(enbID types.EnbID) error
+
+	// StopAgent stops the E2 agent of the specified node
+	StopAgent(enbID types.EnbID) error
+}
+
 // NewService returns a new model Service
-func NewService(nodeStore nodes.Store, plmnID types.PlmnID) service.Service {
+func NewService(nodeStore nodes.Store, plmnID types.PlmnID, agents Agents) service.Service {
 	return &Service{
 		plmnID:    plmnID,
 		nodeStore: nodeStore,
+		agents:    agents,
 	}
 }
 
@@ -34,6 +58,7 @@ type Service struct {
 	service.Service
 	plmnID    types.PlmnID
 	nodeStore nodes.Store
+	agents    Agents
 }
 
 // Register registers the TrafficSim Service with the gRPC server.
@@ -41,6 +66,7 @@ func (s *Service) Register(r *grpc.Server) {
 	server := &Server{
 		plmnID:    s.plmnID,
 		nodeStore: s.nodeStore,
+		agents:    s.agents,
 	}
 	modelapi.RegisterNodeModelServer(r, server)
 }
@@ -49,6 +75,7 @@ func (s *Service) Register(r *grpc.Server) {
 type Server struct {
 	plmnID    types.PlmnID
 	nodeStore nodes.Store
+	agents    Agents
 }
 
 func nodeToAPI(node *model.Node) *types.Node {
@@ -93,9 +120,28 @@ func (s *Server) GetNode(ctx context.Context, request *modelapi.GetNodeRequest)
 	if err != nil {
 		return nil, err
 	}
+	// The node message has no room for the connections of the node, which are sent in the response header instead
+	if err := grpc.SetHeader(ctx, connectionsHeader(node)); err != nil {
+		log.Warn(err)
+	}
 	return &modelapi.GetNodeResponse{Node: nodeToAPI(node)}, nil
 }
 
+// connectionsHeader returns the metadata listing the states of the connections of the node to its controllers, as
+// <controller>=<state> values of the connections key ordered by controller
+func connectionsHeader(node *model.Node) metadata.MD {
+	controllers := make([]string, 0, len(node.Connections))
+	for controller := range node.Connections {
+		controllers = append(controllers, controller)
+	}
+	sort.Strings(controllers)
+	header := metadata.MD{}
+	for _, controller := range controllers {
+		header.Append(ConnectionsMetadataKey, fmt.Sprintf("%s=%s", controller, node.Connections[controller].State))
+	}
+	return header
+}
+
 // UpdateNode updates the specified simulated E2 node
 func (s *Server) UpdateNode(ctx context.Context, request *modelapi.UpdateNodeRequest) (*modelapi.UpdateNodeResponse, error) {
 	log.Debugf("Received update node request: %+v", request)
@@ -176,9 +222,16 @@ func (s *Server) AgentControl(ctx context.Context, request *modelapi.AgentContro
 		return nil, err
 	}
 	log.Infof("Requested '%s' of agent %d", request.Command, node.EnbID)
-	// TODO: implement agent stop|start, implement connection drop|reconnect, etc.
-	// For now, just put the command into the status
-	err = s.nodeStore.SetStatus(ctx, node.EnbID, request.Command)
+	switch {
+	case s.agents != nil && request.Command == StartCommand:
+		err = s.agents.StartAgent(node.EnbID)
+	case s.agents != nil && request.Command == StopCommand:
+		err = s.agents.StopAgent(node.EnbID)
+	default:
+		// TODO: implement connection drop|reconnect, etc.
+		// For now, just put the command into the status
+		err = s.nodeStore.SetStatus(ctx, node.EnbID, request.Command)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -17,44 +17,55 @@ import (
 	service "github.com/onosproject/onos-lib-go/pkg/northbound"
 	"github.com/onosproject/ran-simulator/pkg/model"
 	"github.com/onosproject/ran-simulator/pkg/store/cells"
+	"github.com/onosproject/ran-simulator/pkg/store/metrics"
+	"github.com/onosproject/ran-simulator/pkg/store/routes"
 	"github.com/onosproject/ran-simulator/pkg/store/ues"
 	"google.golang.org/grpc"
 )
 
 var log = liblog.GetLogger("trafficsim")
 
-// NewService returns a new trafficsim Service
-func NewService(model *model.Model, cellStore cells.Store, ueStore ues.Store) service.Service {
+// NewService returns a new trafficsim Service; it keeps the legacy tower and UE API used by existing GUIs working
+// over the stores of the model-driven simulator, towers being the simulated cells
+func NewService(model *model.Model, cellStore cells.Store, ueStore ues.Store, routeStore routes.Store, metricStore metrics.Store) service.Service {
 	return &Service{
-		model:     model,
-		cellStore: cellStore,
-		ueStore:   ueStore,
+		model:       model,
+		cellStore:   cellStore,
+		ueStore:     ueStore,
+		routeStore:  routeStore,
+		metricStore: metricStore,
 	}
 }
 
 // Service is a Service implementation for administration.
 type Service struct {
 	service.Service
-	model     *model.Model
-	cellStore cells.Store
-	ueStore   ues.Store
+	model       *model.Model
+	cellStore   cells.Store
+	ueStore     ues.Store
+	routeStore  routes.Store
+	metricStore metrics.Store
 }
 
 // Register registers the TrafficSim Service with the gRPC server.
 func (s *Service) Register(r *grpc.Server) {
 	server := &Server{
-		model:     s.model,
-		cellStore: s.cellStore,
-		ueStore:   s.ueStore,
+		model:       s.model,
+		cellStore:   s.cellStore,
+		ueStore:     s.ueStore,
+		routeStore:  s.routeStore,
+		metricStore: s.metricStore,
 	}
 	simapi.RegisterTrafficServer(r, server)
 }
 
 // Server implements the TrafficSim gRPC service for administrative facilities.
 type Server struct {
-	model     *model.Model
-	cellStore cells.Store
-	ueStore   ues.Store
+	model       *model.Model
+	cellStore   cells.Store
+	ueStore     ues.Store
+	routeStore  routes.Store
+	metricStore metrics.Store
 }
 
 // GetMapLayout :
@@ -66,6 +77,7 @@ func (s *Server) GetMapLayout(ctx context.Context, req *simapi.MapLayoutRequest)
 		ShowRoutes:     s.model.MapLayout.ShowRoutes,
 		ShowPower:      s.model.MapLayout.ShowPower,
 		LocationsScale: s.model.MapLayout.LocationsScale,
+		CurrentRoutes:  uint32(len(s.routeStore.List(ctx))),
 	}, nil
 }
 
@@ -73,34 +85,103 @@ func ueToAPI(ue *model.UE) *simtypes.Ue {
 	r := &simtypes.Ue{
 		IMSI:     ue.IMSI,
 		Type:     string(ue.Type),
-		Position: nil,
+		Position: &simtypes.Point{Lat: ue.Location.Lat, Lng: ue.Location.Lng},
 		Rotation: ue.Heading,
 		CRNTI:    ue.CRNTI,
 		Admitted: ue.IsAdmitted,
 	}
 	if ue.Cell != nil {
-		r.ServingTower = simtypes.ECGI(ue.Cell.ID)
+		r.ServingTower = ue.Cell.ECGI
 		r.ServingTowerStrength = ue.Cell.Strength
 	}
 	if len(ue.Cells) > 0 {
-		r.Tower1 = simtypes.ECGI(ue.Cells[0].ID)
+		r.Tower1 = ue.Cells[0].ECGI
 		r.Tower1Strength = ue.Cells[0].Strength
 	}
 	if len(ue.Cells) > 1 {
-		r.Tower2 = simtypes.ECGI(ue.Cells[1].ID)
+		r.Tower2 = ue.Cells[1].ECGI
 		r.Tower2Strength = ue.Cells[1].Strength
 	}
 	if len(ue.Cells) > 2 {
-		r.Tower3 = simtypes.ECGI(ue.Cells[2].ID)
+		r.Tower3 = ue.Cells[2].ECGI
 		r.Tower3Strength = ue.Cells[2].Strength
 	}
 	return r
 }
 
+func routeToAPI(route *model.Route) *simtypes.Route {
+	points := make([]*simtypes.Point, 0, len(route.Points))
+	for _, p := range route.Points {
+		points = append(points, &simtypes.Point{Lat: p.Lat, Lng: p.Lng})
+	}
+	return &simtypes.Route{
+		RouteID:   route.IMSI,
+		Waypoints: points,
+		Color:     route.Color,
+	}
+}
+
+func routeEventType(routeEvent routes.RouteEvent) simapi.Type {
+	switch routeEvent {
+	case routes.Created:
+		return simapi.Type_ADDED
+	case routes.Updated:
+		return simapi.Type_UPDATED
+	case routes.Deleted:
+		return simapi.Type_REMOVED
+	}
+	return simapi.Type_NONE
+}
+
+// ueEventType returns the legacy event type of the UE event; updates of a UE are qualified as handovers, changes of
+// its non-serving towers or of its position only, compared to the UE as last sent
+func ueEventType(ueEvent ues.UeEvent, ue *simtypes.Ue, last *simtypes.Ue) (simapi.Type, simapi.UpdateType) {
+	switch ueEvent {
+	case ues.None:
+		return simapi.Type_NONE, simapi.UpdateType_NOUPDATETYPE
+	case ues.Created:
+		return simapi.Type_ADDED, simapi.UpdateType_NOUPDATETYPE
+	case ues.Deleted:
+		return simapi.Type_REMOVED, simapi.UpdateType_NOUPDATETYPE
+	}
+	switch {
+	case ueEvent == ues.HandedOver || last != nil && last.ServingTower != ue.ServingTower:
+		return simapi.Type_UPDATED, simapi.UpdateType_HANDOVER
+	case last == nil || last.Tower1 != ue.Tower1 || last.Tower2 != ue.Tower2 || last.Tower3 != ue.Tower3:
+		return simapi.Type_UPDATED, simapi.UpdateType_TOWER
+	}
+	return simapi.Type_UPDATED, simapi.UpdateType_POSITION
+}
+
 // ListRoutes provides means to list (and optionally monitor) simulated routes
 func (s *Server) ListRoutes(req *simapi.ListRoutesRequest, stream simapi.Traffic_ListRoutesServer) error {
-	// TODO: reimplement list
-	// TODO: add watch capability
+	log.Debugf("Received listing routes request: %v", req)
+	if req.NoSubscribe {
+		if req.NoReplay {
+			return nil
+		}
+		for _, route := range s.routeStore.List(stream.Context()) {
+			if err := stream.Send(&simapi.ListRoutesResponse{Route: routeToAPI(route), Type: simapi.Type_NONE}); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	ch := make(chan event.Event)
+	err := s.routeStore.Watch(stream.Context(), ch, routes.WatchOptions{Replay: !req.NoReplay, Monitor: true})
+	if err != nil {
+		return err
+	}
+	for routeEvent := range ch {
+		response := &simapi.ListRoutesResponse{
+			Route: routeToAPI(routeEvent.Value.(*model.Route)),
+			Type:  routeEventType(routeEvent.Type.(routes.RouteEvent)),
+		}
+		if err := stream.Send(response); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -124,14 +205,36 @@ func (s *Server) ListUes(request *simapi.ListUesRequest, stream simapi.Traffic_L
 // WatchUes watch ue changes
 func (s *Server) WatchUes(request *simapi.WatchUesRequest, server simapi.Traffic_WatchUesServer) error {
 	log.Debugf("Received watching ue changes request: %v", request)
+	if request.NoSubscribe {
+		if request.NoReplay {
+			return nil
+		}
+		for _, ue := range s.ueStore.ListAllUEs(server.Context()) {
+			if err := server.Send(&simapi.WatchUesResponse{Ue: ueToAPI(ue), Type: simapi.Type_NONE}); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
 	ch := make(chan event.Event)
 	err := s.ueStore.Watch(server.Context(), ch, ues.WatchOptions{Replay: !request.NoReplay})
 	if err != nil {
 		return err
 	}
+	sent := make(map[simtypes.IMSI]*simtypes.Ue)
 	for ueEvent := range ch {
+		ue := ueToAPI(ueEvent.Value.(*model.UE))
+		eventType, updateType := ueEventType(ueEvent.Type.(ues.UeEvent), ue, sent[ue.IMSI])
+		if eventType == simapi.Type_REMOVED {
+			delete(sent, ue.IMSI)
+		} else {
+			sent[ue.IMSI] = ue
+		}
 		response := &simapi.WatchUesResponse{
-			Ue: ueToAPI(ueEvent.Value.(*model.UE)),
+			Ue:         ue,
+			Type:       eventType,
+			UpdateType: updateType,
 		}
 		err := server.Send(response)
 		if err != nil {
@@ -150,8 +253,17 @@ func (s *Server) SetNumberUEs(ctx context.Context, req *simapi.SetNumberUEsReque
 	return &simapi.SetNumberUEsResponse{Number: ueCount}, nil
 }
 
-// ResetMetrics resets the metrics on demand
+// ResetMetrics resets the counters of all cells, nodes and UEs on demand
 func (s *Server) ResetMetrics(ctx context.Context, req *simapi.ResetMetricsMsg) (*simapi.ResetMetricsMsg, error) {
-	// TODO: Reimplement
-	return nil, nil
+	log.Info("Resetting metrics")
+	entityIDs, err := s.metricStore.ListEntities(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, entityID := range entityIDs {
+		if err := s.metricStore.Reset(ctx, entityID); err != nil {
+			return nil, err
+		}
+	}
+	return &simapi.ResetMetricsMsg{}, nil
 }
@@ -10,9 +10,12 @@ import (
 	"testing"
 
 	simapi "github.com/onosproject/onos-api/go/onos/ransim/trafficsim"
+	simtypes "github.com/onosproject/onos-api/go/onos/ransim/types"
 	"github.com/onosproject/ran-simulator/pkg/model"
 	"github.com/onosproject/ran-simulator/pkg/store/cells"
+	"github.com/onosproject/ran-simulator/pkg/store/metrics"
 	"github.com/onosproject/ran-simulator/pkg/store/nodes"
+	"github.com/onosproject/ran-simulator/pkg/store/routes"
 	"github.com/onosproject/ran-simulator/pkg/store/ues"
 
 	"github.com/onosproject/onos-lib-go/pkg/northbound"
@@ -21,7 +24,11 @@ import (
 	"google.golang.org/grpc/test/bufconn"
 )
 
-var lis *bufconn.Listener
+var (
+	lis         *bufconn.Listener
+	routeStore  routes.Store
+	metricStore metrics.Store
+)
 
 func bufDialer(context.Context, string) (net.Conn, error) {
 	return lis.Dial()
@@ -36,7 +43,9 @@ func newTestService() (northbound.Service, error) {
 	nodeStore := nodes.NewNodeRegistry(m.Nodes)
 	cellStore := cells.NewCellRegistry(m.Cells, nodeStore)
 	ueStore := ues.NewUERegistry(m.UECount, cellStore)
-	return &Service{model: m, cellStore: cellStore, ueStore: ueStore}, nil
+	routeStore = routes.NewRouteRegistry()
+	metricStore = metrics.NewMetricsStore()
+	return &Service{model: m, cellStore: cellStore, ueStore: ueStore, routeStore: routeStore, metricStore: metricStore}, nil
 }
 
 func createServerConnection(t *testing.T) *grpc.ClientConn {
@@ -108,3 +117,61 @@ func countUEs(t *testing.T, stream simapi.Traffic_ListUesClient) int {
 	}
 	return count
 }
+
+func TestListRoutes(t *testing.T) {
+	client := simapi.NewTrafficClient(createServerConnection(t))
+	ctx := context.Background()
+	assert.NoError(t, routeStore.Add(ctx, &model.Route{IMSI: 1, Points: []*model.Coordinate{{Lat: 45, Lng: -30}}, Color: "red"}))
+
+	stream, err := client.ListRoutes(ctx, &simapi.ListRoutesRequest{NoSubscribe: true})
+	assert.NoError(t, err)
+	response, err := stream.Recv()
+	assert.NoError(t, err)
+	assert.Equal(t, simtypes.IMSI(1), response.Route.RouteID)
+	assert.Equal(t, 45.0, response.Route.Waypoints[0].Lat)
+	_, err = stream.Recv()
+	assert.Error(t, err)
+
+	layout, err := client.GetMapLayout(ctx, &simapi.MapLayoutRequest{})
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(1), layout.CurrentRoutes)
+}
+
+func TestWatchUes(t *testing.T) {
+	client := simapi.NewTrafficClient(createServerConnection(t))
+	stream, err := client.WatchUes(context.Background(), &simapi.WatchUesRequest{NoSubscribe: true})
+	assert.NoError(t, err)
+	response, err := stream.Recv()
+	assert.NoError(t, err)
+	assert.NotNil(t, response.Ue.Position)
+	assert.NotEqual(t, simtypes.ECGI(0), response.Ue.ServingTower)
+}
+
+func TestUeEventType(t *testing.T) {
+	ue := &simtypes.Ue{IMSI: 1, ServingTower: 2, Tower1: 3}
+	eventType, updateType := ueEventType(ues.Created, ue, nil)
+	assert.Equal(t, simapi.Type_ADDED, eventType)
+	assert.Equal(t, simapi.UpdateType_NOUPDATETYPE, updateType)
+	_, updateType = ueEventType(ues.Updated, ue, &simtypes.Ue{IMSI: 1, ServingTower: 2, Tower1: 3})
+	assert.Equal(t, simapi.UpdateType_POSITION, updateType)
+	_, updateType = ueEventType(ues.Updated, ue, &simtypes.Ue{IMSI: 1, ServingTower: 2, Tower1: 4})
+	assert.Equal(t, simapi.UpdateType_TOWER, updateType)
+	_, updateType = ueEventType(ues.HandedOver, ue, &simtypes.Ue{IMSI: 1, ServingTower: 3, Tower1: 3})
+	assert.Equal(t, simapi.UpdateType_HANDOVER, updateType)
+	eventType, _ = ueEventType(ues.Deleted, ue, nil)
+	assert.Equal(t, simapi.Type_REMOVED, eventType)
+}
+
+func TestResetMetrics(t *testing.T) {
+	client := simapi.NewTrafficClient(createServerConnection(t))
+	ctx := context.Background()
+	_, _ = metricStore.Add(ctx, 1, "HO.Out.Tot", 3)
+	assert.NoError(t, metricStore.Set(ctx, 1, "PEE.AvgPower", 2.5))
+
+	_, err := client.ResetMetrics(ctx, &simapi.ResetMetricsMsg{})
+	assert.NoError(t, err)
+	value, _ := metricStore.Get(ctx, 1, "HO.Out.Tot")
+	assert.Equal(t, uint64(0), value)
+	value, _ = metricStore.Get(ctx, 1, "PEE.AvgPower")
+	assert.Equal(t, 2.5, value)
+}
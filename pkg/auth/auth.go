@@ -0,0 +1,241 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package auth authenticates the requests of the northbound APIs by their JWT bearer token and authorizes them by the
+// role the groups of the token grant, so that shared deployments can keep users from mutating the simulation
+package auth
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/golang-jwt/jwt/v4"
+	grpc_auth "github.com/grpc-ecosystem/go-grpc-middleware/auth"
+	libauth "github.com/onosproject/onos-lib-go/pkg/auth"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/onosproject/onos-lib-go/pkg/logging"
+	"google.golang.org/grpc"
+)
+
+var log = logging.GetLogger("auth")
+
+const (
+	// bearerScheme is the authorization scheme of the tokens
+	bearerScheme = "bearer"
+	// groupsClaim is the claim listing the groups of the user
+	groupsClaim = "groups"
+)
+
+// Role is the role granted to a user
+type Role int
+
+const (
+	// NoRole grants no access
+	NoRole Role = iota
+	// ReadOnly grants read access to the simulation
+	ReadOnly
+	// Admin grants read and write access to the simulation
+	Admin
+)
+
+func (r Role) String() string {
+	switch r {
+	case ReadOnly:
+		return "read-only"
+	case Admin:
+		return "admin"
+	}
+	return "none"
+}
+
+// Config is the configuration of the authorization of the northbound APIs
+type Config struct {
+	// Enabled requires the requests to carry a valid token
+	Enabled bool
+	// AdminGroups are the groups granted the admin role
+	AdminGroups []string
+	// ReadOnlyGroups are the groups granted the read-only role; all authenticated users are if empty
+	ReadOnlyGroups []string
+	// Audience is the audience the tokens must be issued for, i.e. listed by their aud claim; the audience is not
+	// checked if empty
+	Audience string
+}
+
+// Authorizer authorizes the requests of the northbound APIs; the tokens are validated by onos-lib-go, i.e. against
+// the SHARED_SECRET_KEY environment variable for HS tokens and the keys of the OIDC server at OIDC_SERVER_URL for RS
+// tokens
+type Authorizer struct {
+	config Config
+	// jwtMu guards the authenticator, which caches the keys of the OIDC server
+	jwtMu sync.Mutex
+	jwt   *libauth.JwtAuthenticator
+}
+
+// NewAuthorizer creates a new authorizer with the given configuration
+func NewAuthorizer(config Config) *Authorizer {
+	return &Authorizer{
+		config: config,
+		jwt:    &libauth.JwtAuthenticator{},
+	}
+}
+
+// Enabled returns whether the requests are authorized
+func (a *Authorizer) Enabled() bool {
+	return a.config.Enabled
+}
+
+// Authorize validates the token and returns the role it grants, Unauthorized if the token is invalid and Forbidden
+// if the role does not allow writing when requested
+func (a *Authorizer) Authorize(token string, write bool) (Role, error) {
+	if token == "" {
+		return NoRole, errors.New(errors.Unauthorized, "missing bearer token")
+	}
+	a.jwtMu.Lock()
+	validated, err := a.jwt.ParseAndValidate(token)
+	a.jwtMu.Unlock()
+	if err != nil {
+		return NoRole, errors.New(errors.Unauthorized, "invalid bearer token: %v", err)
+	}
+	// onos-lib-go validates the signature and the time claims only
+	claims := jwt.MapClaims(validated)
+	if a.config.Audience != "" && !claims.VerifyAudience(a.config.Audience, true) {
+		return NoRole, errors.New(errors.Unauthorized, "bearer token not issued for audience %s", a.config.Audience)
+	}
+	role := a.role(claims)
+	subject, _ := claims["sub"].(string)
+	switch {
+	case role == NoRole:
+		return role, errors.New(errors.Forbidden, "user %s is not granted any role", subject)
+	case write && role != Admin:
+		return role, errors.New(errors.Forbidden, "user %s is not granted the admin role", subject)
+	}
+	return role, nil
+}
+
+// role returns the role the groups of the claims grant
+func (a *Authorizer) role(claims jwt.MapClaims) Role {
+	groups := make(map[string]bool)
+	switch values := claims[groupsClaim].(type) {
+	case []interface{}:
+		for _, value := range values {
+			if group, ok := value.(string); ok {
+				groups[group] = true
+			}
+		}
+	case string:
+		groups[values] = true
+	}
+	for _, group := range a.config.AdminGroups {
+		if groups[group] {
+			return Admin
+		}
+	}
+	if len(a.config.ReadOnlyGroups) == 0 {
+		return ReadOnly
+	}
+	for _, group := range a.config.ReadOnlyGroups {
+		if groups[group] {
+			return ReadOnly
+		}
+	}
+	return NoRole
+}
+
+// IsReadOnlyMethod returns whether the gRPC method only reads the simulation, e.g. /onos.ransim.model.NodeModel/GetNode
+// or /gnmi.gNMI/Subscribe
+func IsReadOnlyMethod(fullMethod string) bool {
+	name := fullMethod[strings.LastIndex(fullMethod, "/")+1:]
+	for _, prefix := range []string{"Get", "List", "Watch", "Subscribe", "Capabilities"} {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// authorizeContext authorizes the gRPC request carrying the token in its metadata
+func (a *Authorizer) authorizeContext(ctx context.Context, fullMethod string) error {
+	token, err := grpc_auth.AuthFromMD(ctx, bearerScheme)
+	if err != nil {
+		token = ""
+	}
+	role, err := a.Authorize(token, !IsReadOnlyMethod(fullMethod))
+	if err != nil {
+		log.Warnf("Denied %s: %v", fullMethod, err)
+		return errors.Status(err).Err()
+	}
+	log.Debugf("Authorized %s as %s", fullMethod, role)
+	return nil
+}
+
+// UnaryServerInterceptor returns the interceptor authorizing the unary gRPC requests
+func (a *Authorizer) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if a.config.Enabled {
+			if err := a.authorizeContext(ctx, info.FullMethod); err != nil {
+				return nil, err
+			}
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor returns the interceptor authorizing the streaming gRPC requests
+func (a *Authorizer) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if a.config.Enabled {
+			if err := a.authorizeContext(stream.Context(), info.FullMethod); err != nil {
+				return err
+			}
+		}
+		return handler(srv, stream)
+	}
+}
+
+// Handler returns the HTTP handler authorizing the requests carrying the token in their Authorization header before
+// passing them to the given handler; GET and HEAD requests only read the simulation
+func (a *Authorizer) Handler(next http.Handler) http.Handler {
+	return a.handler(next, false)
+}
+
+// AdminHandler returns the HTTP handler authorizing the requests like Handler, but requiring the admin role whatever
+// the method, e.g. for GET requests exposing the internals of the process
+func (a *Authorizer) AdminHandler(next http.Handler) http.Handler {
+	return a.handler(next, true)
+}
+
+func (a *Authorizer) handler(next http.Handler, admin bool) http.Handler {
+	if a == nil || !a.config.Enabled {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token := ""
+		if scheme, value, ok := splitAuthorization(r.Header.Get("Authorization")); ok && strings.EqualFold(scheme, bearerScheme) {
+			token = value
+		}
+		write := admin || r.Method != http.MethodGet && r.Method != http.MethodHead
+		if _, err := a.Authorize(token, write); err != nil {
+			log.Warnf("Denied %s %s: %v", r.Method, r.URL.Path, err)
+			if errors.IsUnauthorized(err) {
+				w.Header().Set("WWW-Authenticate", "Bearer")
+				http.Error(w, err.Error(), http.StatusUnauthorized)
+			} else {
+				http.Error(w, err.Error(), http.StatusForbidden)
+			}
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// splitAuthorization splits the value of an Authorization header into its scheme and credentials
+func splitAuthorization(value string) (string, string, bool) {
+	i := strings.IndexByte(value, ' ')
+	if i < 0 {
+		return "", "", false
+	}
+	return value[:i], strings.TrimSpace(value[i+1:]), true
+}
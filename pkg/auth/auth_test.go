@@ -0,0 +1,185 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package auth
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	libauth "github.com/onosproject/onos-lib-go/pkg/auth"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const testSecret = "testkey"
+
+func token(t *testing.T, secret string, groups ...string) string {
+	claims := jwt.MapClaims{
+		"sub": "test",
+		"exp": time.Now().Add(time.Minute).Unix(),
+	}
+	if len(groups) > 0 {
+		claims[groupsClaim] = groups
+	}
+	signed, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(secret))
+	assert.NoError(t, err)
+	return signed
+}
+
+func TestAuthorize(t *testing.T) {
+	assert.NoError(t, os.Setenv(libauth.SharedSecretKey, testSecret))
+	defer os.Unsetenv(libauth.SharedSecretKey)
+	a := NewAuthorizer(Config{Enabled: true, AdminGroups: []string{"lab-a"}})
+
+	_, err := a.Authorize("", false)
+	assert.True(t, errors.IsUnauthorized(err))
+	_, err = a.Authorize(token(t, "otherkey"), false)
+	assert.True(t, errors.IsUnauthorized(err))
+
+	role, err := a.Authorize(token(t, testSecret, "lab-b"), false)
+	assert.NoError(t, err)
+	assert.Equal(t, ReadOnly, role)
+	_, err = a.Authorize(token(t, testSecret, "lab-b"), true)
+	assert.True(t, errors.IsForbidden(err))
+	role, err = a.Authorize(token(t, testSecret, "lab-b", "lab-a"), true)
+	assert.NoError(t, err)
+	assert.Equal(t, Admin, role)
+
+	// Only the read-only groups are granted access if any is configured
+	a = NewAuthorizer(Config{Enabled: true, AdminGroups: []string{"lab-a"}, ReadOnlyGroups: []string{"lab-b"}})
+	_, err = a.Authorize(token(t, testSecret), false)
+	assert.True(t, errors.IsForbidden(err))
+	role, err = a.Authorize(token(t, testSecret, "lab-b"), false)
+	assert.NoError(t, err)
+	assert.Equal(t, ReadOnly, role)
+}
+
+func TestAudience(t *testing.T) {
+	assert.NoError(t, os.Setenv(libauth.SharedSecretKey, testSecret))
+	defer os.Unsetenv(libauth.SharedSecretKey)
+	a := NewAuthorizer(Config{Enabled: true, Audience: "ransim"})
+	withAudience := func(audience interface{}) string {
+		claims := jwt.MapClaims{
+			"sub": "test",
+			"exp": time.Now().Add(time.Minute).Unix(),
+		}
+		if audience != nil {
+			claims["aud"] = audience
+		}
+		signed, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(testSecret))
+		assert.NoError(t, err)
+		return signed
+	}
+
+	// The audience may be a single string or listed in an array
+	_, err := a.Authorize(withAudience("ransim"), false)
+	assert.NoError(t, err)
+	_, err = a.Authorize(withAudience([]string{"onos", "ransim"}), false)
+	assert.NoError(t, err)
+
+	// Tokens for other audiences, including empty arrays, and tokens without audience are rejected
+	for _, audience := range []interface{}{"onos", []string{"onos"}, []string{}, nil} {
+		_, err = a.Authorize(withAudience(audience), false)
+		assert.True(t, errors.IsUnauthorized(err), "%v", audience)
+	}
+}
+
+func TestIsReadOnlyMethod(t *testing.T) {
+	assert.True(t, IsReadOnlyMethod("/onos.ransim.model.NodeModel/GetNode"))
+	assert.True(t, IsReadOnlyMethod("/onos.ransim.model.CellModel/WatchCells"))
+	assert.True(t, IsReadOnlyMethod("/gnmi.gNMI/Subscribe"))
+	assert.False(t, IsReadOnlyMethod("/gnmi.gNMI/Set"))
+	assert.False(t, IsReadOnlyMethod("/onos.ransim.model.ModelService/Load"))
+	assert.False(t, IsReadOnlyMethod("/onos.ransim.trafficsim.Traffic/ResetMetrics"))
+}
+
+func TestInterceptor(t *testing.T) {
+	assert.NoError(t, os.Setenv(libauth.SharedSecretKey, testSecret))
+	defer os.Unsetenv(libauth.SharedSecretKey)
+	interceptor := NewAuthorizer(Config{Enabled: true, AdminGroups: []string{"lab-a"}}).UnaryServerInterceptor()
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return "ok", nil
+	}
+	call := func(method string, token string) error {
+		ctx := context.Background()
+		if token != "" {
+			ctx = metadata.NewIncomingContext(ctx, metadata.Pairs("authorization", "bearer "+token))
+		}
+		_, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: method}, handler)
+		return err
+	}
+
+	assert.Equal(t, codes.Unauthenticated, status.Code(call("/onos.ransim.model.NodeModel/GetNode", "")))
+	assert.NoError(t, call("/onos.ransim.model.NodeModel/GetNode", token(t, testSecret)))
+	assert.Equal(t, codes.PermissionDenied, status.Code(call("/onos.ransim.model.NodeModel/DeleteNode", token(t, testSecret))))
+	assert.NoError(t, call("/onos.ransim.model.NodeModel/DeleteNode", token(t, testSecret, "lab-a")))
+
+	// Requests are not authorized unless enabled
+	interceptor = NewAuthorizer(Config{}).UnaryServerInterceptor()
+	assert.NoError(t, call("/onos.ransim.model.NodeModel/DeleteNode", ""))
+}
+
+func TestHandler(t *testing.T) {
+	assert.NoError(t, os.Setenv(libauth.SharedSecretKey, testSecret))
+	defer os.Unsetenv(libauth.SharedSecretKey)
+	a := NewAuthorizer(Config{Enabled: true, AdminGroups: []string{"lab-a"}})
+	handler := a.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	serve := func(method string, token string) int {
+		r := httptest.NewRequest(method, "/run", nil)
+		if token != "" {
+			r.Header.Set("Authorization", "Bearer "+token)
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		return w.Code
+	}
+
+	assert.Equal(t, http.StatusUnauthorized, serve(http.MethodGet, ""))
+	assert.Equal(t, http.StatusNoContent, serve(http.MethodGet, token(t, testSecret)))
+	assert.Equal(t, http.StatusForbidden, serve(http.MethodPost, token(t, testSecret)))
+	assert.Equal(t, http.StatusNoContent, serve(http.MethodPost, token(t, testSecret, "lab-a")))
+
+	// The admin handler requires the admin role for GET requests as well
+	handler = a.AdminHandler(handler)
+	assert.Equal(t, http.StatusUnauthorized, serve(http.MethodGet, ""))
+	assert.Equal(t, http.StatusForbidden, serve(http.MethodGet, token(t, testSecret)))
+	assert.Equal(t, http.StatusNoContent, serve(http.MethodGet, token(t, testSecret, "lab-a")))
+}
+
+func TestPeerCredentials(t *testing.T) {
+	a := NewAuthorizer(Config{Enabled: true, AdminGroups: []string{"lab-a"}})
+	_, ok := a.PeerCredentials()
+	assert.False(t, ok)
+
+	assert.NoError(t, os.Setenv(libauth.SharedSecretKey, testSecret))
+	defer os.Unsetenv(libauth.SharedSecretKey)
+	creds, ok := a.PeerCredentials()
+	assert.True(t, ok)
+	md, err := creds.GetRequestMetadata(context.Background())
+	assert.NoError(t, err)
+	role, err := a.Authorize(strings.TrimPrefix(md["authorization"], "bearer "), true)
+	assert.NoError(t, err)
+	assert.Equal(t, Admin, role)
+
+	// Peer tokens are issued for the configured audience
+	a = NewAuthorizer(Config{Enabled: true, AdminGroups: []string{"lab-a"}, Audience: "ransim"})
+	creds, _ = a.PeerCredentials()
+	md, err = creds.GetRequestMetadata(context.Background())
+	assert.NoError(t, err)
+	_, err = a.Authorize(strings.TrimPrefix(md["authorization"], "bearer "), true)
+	assert.NoError(t, err)
+}
@@ -0,0 +1,63 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package auth
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	libauth "github.com/onosproject/onos-lib-go/pkg/auth"
+	"google.golang.org/grpc/credentials"
+)
+
+const (
+	// peerSubject is the subject of the tokens the instances authenticate with against each other
+	peerSubject = "ran-simulator"
+	// peerTokenLifetime is the lifetime of the tokens of the instances
+	peerTokenLifetime = 5 * time.Minute
+)
+
+// peerCredentials authenticates the requests of an instance to the other instances sharing the model by tokens
+// granting the admin role, signed with the shared secret
+type peerCredentials struct {
+	secret   []byte
+	group    string
+	audience string
+}
+
+// PeerCredentials returns the credentials an instance authenticates with against the other instances sharing the
+// model; false is returned if the authorization is disabled or the shared secret is not set, the instances being
+// unable to sign tokens on their own with OIDC
+func (a *Authorizer) PeerCredentials() (credentials.PerRPCCredentials, bool) {
+	secret := os.Getenv(libauth.SharedSecretKey)
+	if !a.config.Enabled || secret == "" || len(a.config.AdminGroups) == 0 {
+		return nil, false
+	}
+	return &peerCredentials{secret: []byte(secret), group: a.config.AdminGroups[0], audience: a.config.Audience}, true
+}
+
+func (c *peerCredentials) GetRequestMetadata(ctx context.Context, uri ...string) (map[string]string, error) {
+	now := time.Now()
+	claims := jwt.MapClaims{
+		"sub":       peerSubject,
+		"iat":       now.Unix(),
+		"exp":       now.Add(peerTokenLifetime).Unix(),
+		groupsClaim: []string{c.group},
+	}
+	if c.audience != "" {
+		claims["aud"] = c.audience
+	}
+	signed, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(c.secret)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]string{"authorization": bearerScheme + " " + signed}, nil
+}
+
+func (c *peerCredentials) RequireTransportSecurity() bool {
+	return true
+}
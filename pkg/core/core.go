@@ -0,0 +1,332 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package core implements a lightweight stub of the 5G core, i.e. of the AMF and SMF, handling the registration and
+// the PDU sessions of the simulated UEs so that session KPIs are generated without an external core
+package core
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/onosproject/onos-api/go/onos/ransim/types"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/onosproject/onos-lib-go/pkg/logging"
+	"github.com/onosproject/ran-simulator/pkg/journal"
+	"github.com/onosproject/ran-simulator/pkg/model"
+	"github.com/onosproject/ran-simulator/pkg/qos"
+	"github.com/onosproject/ran-simulator/pkg/store/metrics"
+	"github.com/onosproject/ran-simulator/pkg/store/ues"
+)
+
+var log = logging.GetLogger("core")
+
+// Per-cell counters of the registrations and PDU sessions of the UEs served by the cell, named as in 3GPP TS 28.552,
+// maintained in the metrics store and reported via KPM; the session counters are also maintained per slice under the
+// names suffixed with the S-NSSAI, e.g. SM.PDUSessionSetupReq.1-010203
+const (
+	// RegistrationAttempts number of initial registrations requested by UEs
+	RegistrationAttempts = "RM.RegInitReq"
+	// RegistrationSuccesses number of initial registrations accepted by the core
+	RegistrationSuccesses = "RM.RegInitSucc"
+	// SessionSetupAttempts number of PDU session establishments requested
+	SessionSetupAttempts = "SM.PDUSessionSetupReq"
+	// SessionSetupSuccesses number of PDU sessions established
+	SessionSetupSuccesses = "SM.PDUSessionSetupSucc"
+	// SessionSetupFailures number of PDU session establishments failed
+	SessionSetupFailures = "SM.PDUSessionSetupFail"
+	// SessionReleases number of PDU sessions released
+	SessionReleases = "SM.PDUSessionRel.Tot"
+)
+
+const (
+	defaultMeanSessionDuration = 5 * time.Minute
+	defaultMeanSessionInterval = time.Minute
+	defaultRetryInterval       = 10 * time.Second
+	defaultFiveQI              = 9
+	defaultSNSSAI              = "1"
+
+	coreUpdateInterval = time.Second
+)
+
+// Pager pages idle UEs on behalf of the core, bringing them into the connected state
+type Pager interface {
+	Page(ctx context.Context, imsi types.IMSI) error
+}
+
+// sessionState is the state of a PDU session of a UE held by the core
+type sessionState struct {
+	active bool
+	// next is the time of the next establishment attempt of an inactive session and the release time of an active one
+	next time.Time
+	// nextDownlink is the time of the next downlink data arrival of an active session
+	nextDownlink time.Time
+}
+
+// ueContext is the context of a UE held by the core
+type ueContext struct {
+	registered bool
+	// next is the time of the next registration attempt
+	next     time.Time
+	sessions map[int32]*sessionState
+}
+
+// Core registers the UEs connected to the RAN and establishes and releases their PDU sessions as per the session
+// profiles of their type; the QoS flow of session N is set up on the RAN side on DRB N via the DRB attribute of the
+// UE, handled by the QoS controller
+type Core struct {
+	ueStore     ues.Store
+	metricStore metrics.Store
+	config      model.CoreConfig
+	profiles    map[model.UEType]model.ActivityProfile
+	pager       Pager
+	mu          sync.Mutex
+	contexts    map[types.IMSI]*ueContext
+	cancel      context.CancelFunc
+}
+
+// NewCore creates a new core stub; unset times, 5QI and S-NSSAI are replaced by defaults
+func NewCore(ueStore ues.Store, metricStore metrics.Store, config model.CoreConfig) *Core {
+	if config.MeanSessionDuration <= 0 {
+		config.MeanSessionDuration = defaultMeanSessionDuration
+	}
+	if config.MeanSessionInterval <= 0 {
+		config.MeanSessionInterval = defaultMeanSessionInterval
+	}
+	if config.RetryInterval <= 0 {
+		config.RetryInterval = defaultRetryInterval
+	}
+	if config.FiveQI == 0 {
+		config.FiveQI = defaultFiveQI
+	}
+	if config.SNSSAI == "" {
+		config.SNSSAI = defaultSNSSAI
+	}
+	return &Core{
+		ueStore:     ueStore,
+		metricStore: metricStore,
+		config:      config,
+		contexts:    make(map[types.IMSI]*ueContext),
+	}
+}
+
+// SetPager sets the pager used to reach idle UEs upon downlink data arrival
+func (c *Core) SetPager(pager Pager) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.pager = pager
+}
+
+// SetProfiles sets the traffic profiles by UE type, listing the PDU sessions established by the UEs
+func (c *Core) SetProfiles(profiles map[model.UEType]model.ActivityProfile) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.profiles = profiles
+}
+
+// Counters lists the names of the metrics maintained by the core; the per-slice session counters are reported by KPM
+// v2 as the per-slice variants of these measurements
+func Counters() []string {
+	return []string{RegistrationAttempts, RegistrationSuccesses, SessionSetupAttempts, SessionSetupSuccesses,
+		SessionSetupFailures, SessionReleases}
+}
+
+// SliceCounter returns the name of the per-slice variant of the given session counter, e.g.
+// SM.PDUSessionSetupReq.1-010203, as read by KPM v2 for measurement labels with a slice ID
+func SliceCounter(name string, snssai string) string {
+	return fmt.Sprintf("%s.%s", name, snssai)
+}
+
+// Start starts handling the UEs
+func (c *Core) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	c.cancel = cancel
+	go c.run(ctx)
+}
+
+// Stop stops handling the UEs
+func (c *Core) Stop() {
+	if c.cancel != nil {
+		c.cancel()
+	}
+}
+
+func (c *Core) run(ctx context.Context) {
+	ticker := time.NewTicker(coreUpdateInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case now := <-ticker.C:
+			c.step(ctx, now)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// step advances the registration and the PDU sessions of all UEs to the given time; UEs register and establish their
+// sessions while connected, and idle UEs with a session are paged upon downlink data arrival
+func (c *Core) step(ctx context.Context, now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	present := make(map[types.IMSI]bool)
+	for _, ue := range c.ueStore.ListAllUEs(ctx) {
+		present[ue.IMSI] = true
+		uctx, ok := c.contexts[ue.IMSI]
+		if !ok {
+			uctx = &ueContext{next: now, sessions: make(map[int32]*sessionState)}
+			c.contexts[ue.IMSI] = uctx
+		}
+		if ue.Cell == nil {
+			continue
+		}
+		connected := ue.RrcState == model.RrcConnected
+		if !uctx.registered {
+			if connected && !now.Before(uctx.next) {
+				c.register(ctx, ue, uctx, now)
+			}
+			continue
+		}
+		for i, profile := range c.sessionProfiles(ue.Type) {
+			id := int32(i + 1)
+			state, ok := uctx.sessions[id]
+			if !ok {
+				state = &sessionState{next: now}
+				uctx.sessions[id] = state
+			}
+			switch {
+			case !state.active:
+				if connected && !now.Before(state.next) {
+					c.establishSession(ctx, ue, id, profile, state, now)
+				}
+			case !now.Before(state.next):
+				c.releaseSession(ctx, ue, id, profile, state, now)
+			case ue.RrcState == model.RrcIdle && c.pager != nil && c.config.MeanDownlinkInterval > 0 && !now.Before(state.nextDownlink):
+				state.nextDownlink = now.Add(exponential(c.config.MeanDownlinkInterval))
+				log.Debugf("Downlink data for PDU session %d of idle UE %d", id, ue.IMSI)
+				if err := c.pager.Page(ctx, ue.IMSI); err != nil {
+					log.Debug(err)
+				}
+			}
+		}
+	}
+	for imsi, uctx := range c.contexts {
+		if !present[imsi] {
+			for id := range uctx.sessions {
+				_ = c.metricStore.Delete(ctx, uint64(imsi), drbAttribute(id))
+			}
+			delete(c.contexts, imsi)
+		}
+	}
+}
+
+// sessionProfiles returns the profiles of the PDU sessions of UEs of the given type, with unset parameters replaced
+// by those of the core configuration
+func (c *Core) sessionProfiles(ueType model.UEType) []model.SessionProfile {
+	profiles := append([]model.SessionProfile{}, c.profiles[ueType].Sessions...)
+	if len(profiles) == 0 {
+		profiles = append(profiles, model.SessionProfile{})
+	}
+	for i := range profiles {
+		if profiles[i].SNSSAI == "" {
+			profiles[i].SNSSAI = c.config.SNSSAI
+		}
+		if profiles[i].FiveQI == 0 {
+			profiles[i].FiveQI = c.config.FiveQI
+		}
+		if profiles[i].MeanDuration <= 0 {
+			profiles[i].MeanDuration = c.config.MeanSessionDuration
+		}
+		if profiles[i].MeanInterval <= 0 {
+			profiles[i].MeanInterval = c.config.MeanSessionInterval
+		}
+	}
+	return profiles
+}
+
+// register handles the initial registration of the UE
+func (c *Core) register(ctx context.Context, ue *model.UE, uctx *ueContext, now time.Time) {
+	ecgi := ue.Cell.ECGI
+	c.increment(ctx, uint64(ecgi), RegistrationAttempts)
+	if c.config.RegistrationFailureProbability > 0 && rand.Float64() < c.config.RegistrationFailureProbability {
+		log.Infof("Registration of UE %d rejected", ue.IMSI)
+		uctx.next = now.Add(c.config.RetryInterval)
+		journal.Record(journal.RegistrationRejected, uint64(ue.IMSI), map[string]interface{}{"ecgi": ecgi})
+		return
+	}
+	log.Debugf("UE %d registered via cell %d", ue.IMSI, ecgi)
+	c.increment(ctx, uint64(ecgi), RegistrationSuccesses)
+	uctx.registered = true
+	journal.Record(journal.UERegistered, uint64(ue.IMSI), map[string]interface{}{"ecgi": ecgi})
+}
+
+// establishSession establishes the PDU session of the UE, setting up its QoS flow on the DRB of the session
+func (c *Core) establishSession(ctx context.Context, ue *model.UE, id int32, profile model.SessionProfile, state *sessionState, now time.Time) {
+	ecgi := ue.Cell.ECGI
+	c.countSession(ctx, ecgi, SessionSetupAttempts, profile.SNSSAI)
+	details := map[string]interface{}{"ecgi": ecgi, "sessionID": id, "snssai": profile.SNSSAI, "fiveQI": profile.FiveQI}
+	session := &model.PDUSession{ID: id, SNSSAI: profile.SNSSAI, FiveQI: profile.FiveQI, DRB: id}
+	err := errors.New(errors.Unavailable, "PDU session establishment rejected")
+	if c.config.SessionFailureProbability <= 0 || rand.Float64() >= c.config.SessionFailureProbability {
+		err = c.ueStore.AddPDUSession(ctx, ue.IMSI, session)
+	}
+	if err == nil {
+		spec := fmt.Sprintf("qfi=%d,fiveQI=%d", id, profile.FiveQI)
+		if err = c.metricStore.Set(ctx, uint64(ue.IMSI), drbAttribute(id), spec); err != nil {
+			_, _ = c.ueStore.DeletePDUSession(ctx, ue.IMSI, id)
+		}
+	}
+	if err != nil {
+		log.Infof("PDU session %d establishment of UE %d failed: %v", id, ue.IMSI, err)
+		c.countSession(ctx, ecgi, SessionSetupFailures, profile.SNSSAI)
+		state.next = now.Add(c.config.RetryInterval)
+		journal.Record(journal.PDUSessionFailed, uint64(ue.IMSI), details)
+		return
+	}
+	log.Debugf("PDU session %d of UE %d established via cell %d on slice %s", id, ue.IMSI, ecgi, profile.SNSSAI)
+	c.countSession(ctx, ecgi, SessionSetupSuccesses, profile.SNSSAI)
+	state.active = true
+	state.next = now.Add(exponential(profile.MeanDuration))
+	if c.config.MeanDownlinkInterval > 0 {
+		state.nextDownlink = now.Add(exponential(c.config.MeanDownlinkInterval))
+	}
+	journal.Record(journal.PDUSessionEstablished, uint64(ue.IMSI), details)
+}
+
+// releaseSession releases the PDU session of the UE along with its DRB
+func (c *Core) releaseSession(ctx context.Context, ue *model.UE, id int32, profile model.SessionProfile, state *sessionState, now time.Time) {
+	log.Debugf("Releasing PDU session %d of UE %d", id, ue.IMSI)
+	if _, err := c.ueStore.DeletePDUSession(ctx, ue.IMSI, id); err != nil {
+		log.Warn(err)
+	}
+	if err := c.metricStore.Delete(ctx, uint64(ue.IMSI), drbAttribute(id)); err != nil {
+		log.Warn(err)
+	}
+	c.countSession(ctx, ue.Cell.ECGI, SessionReleases, profile.SNSSAI)
+	state.active = false
+	state.next = now.Add(exponential(profile.MeanInterval))
+	journal.Record(journal.PDUSessionReleased, uint64(ue.IMSI), map[string]interface{}{"ecgi": ue.Cell.ECGI, "sessionID": id, "snssai": profile.SNSSAI})
+}
+
+// drbAttribute returns the name of the UE attribute configuring the DRB of the PDU session
+func drbAttribute(sessionID int32) string {
+	return fmt.Sprintf("%s%d", qos.DRBAttributePrefix, sessionID)
+}
+
+func exponential(mean time.Duration) time.Duration {
+	return time.Duration(rand.ExpFloat64() * float64(mean))
+}
+
+// countSession increments the session counter of the cell, both overall and for the slice of the session
+func (c *Core) countSession(ctx context.Context, ecgi types.ECGI, name string, snssai string) {
+	c.increment(ctx, uint64(ecgi), name)
+	c.increment(ctx, uint64(ecgi), SliceCounter(name, snssai))
+}
+
+func (c *Core) increment(ctx context.Context, entityID uint64, name string) {
+	_, _ = c.metricStore.Add(ctx, entityID, name, 1)
+}
@@ -0,0 +1,134 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package core
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/onosproject/onos-api/go/onos/ransim/types"
+	"github.com/onosproject/ran-simulator/pkg/model"
+	"github.com/onosproject/ran-simulator/pkg/store/cells"
+	"github.com/onosproject/ran-simulator/pkg/store/metrics"
+	"github.com/onosproject/ran-simulator/pkg/store/nodes"
+	"github.com/onosproject/ran-simulator/pkg/store/ues"
+	"github.com/stretchr/testify/assert"
+)
+
+type testPager struct {
+	paged []types.IMSI
+}
+
+func (p *testPager) Page(ctx context.Context, imsi types.IMSI) error {
+	p.paged = append(p.paged, imsi)
+	return nil
+}
+
+func TestSessionLifecycle(t *testing.T) {
+	ctx := context.Background()
+	m := model.Model{}
+	assert.NoError(t, model.LoadConfig(&m, "../model/test"))
+	cellStore := cells.NewCellRegistry(m.Cells, nodes.NewNodeRegistry(m.Nodes))
+	ueStore := ues.NewUERegistry(2, cellStore)
+	metricStore := metrics.NewMetricsStore()
+	core := NewCore(ueStore, metricStore, model.CoreConfig{
+		MeanSessionDuration:  time.Millisecond,
+		MeanDownlinkInterval: time.Millisecond,
+	})
+	pager := &testPager{}
+	core.SetPager(pager)
+
+	ecgi := types.ECGI(84325717505)
+	ueList := ueStore.ListAllUEs(ctx)
+	connected, idle := ueList[0], ueList[1]
+	for _, ue := range ueList {
+		assert.NoError(t, ueStore.MoveToCell(ctx, ue.IMSI, ecgi, 10))
+	}
+	assert.NoError(t, ueStore.UpdateRrcState(ctx, connected.IMSI, model.RrcConnected))
+	assert.NoError(t, ueStore.UpdateRrcState(ctx, idle.IMSI, model.RrcIdle))
+	count := func(name string) uint64 {
+		value, _ := metricStore.Get(ctx, uint64(ecgi), name)
+		c, _ := value.(uint64)
+		return c
+	}
+
+	// Only connected UEs register and then establish their PDU session
+	now := time.Now()
+	core.step(ctx, now)
+	assert.Equal(t, uint64(1), count(RegistrationAttempts))
+	assert.Equal(t, uint64(1), count(RegistrationSuccesses))
+	core.step(ctx, now)
+	assert.Equal(t, uint64(1), count(SessionSetupAttempts))
+	assert.Equal(t, uint64(1), count(SessionSetupSuccesses))
+	assert.Equal(t, uint64(1), count(SliceCounter(SessionSetupSuccesses, "1")))
+	spec, ok := metricStore.Get(ctx, uint64(connected.IMSI), "drb.1")
+	assert.True(t, ok)
+	assert.Equal(t, "qfi=1,fiveQI=9", spec)
+	assert.Equal(t, []*model.PDUSession{{ID: 1, SNSSAI: "1", FiveQI: 9, DRB: 1}}, connected.PDUSessions)
+
+	// Downlink data for the session of an idle UE triggers its paging
+	assert.NoError(t, ueStore.UpdateRrcState(ctx, connected.IMSI, model.RrcIdle))
+	core.contexts[connected.IMSI].sessions[1].next = now.Add(time.Hour)
+	core.step(ctx, now.Add(time.Minute))
+	assert.Equal(t, []types.IMSI{connected.IMSI}, pager.paged)
+
+	// The session is released along with its DRB
+	core.step(ctx, now.Add(2*time.Hour))
+	assert.Equal(t, uint64(1), count(SessionReleases))
+	_, ok = metricStore.Get(ctx, uint64(connected.IMSI), "drb.1")
+	assert.False(t, ok)
+	assert.Equal(t, 0, len(connected.PDUSessions))
+
+	// Rejected registrations and failed session establishments are retried
+	core.config.RegistrationFailureProbability = 1
+	core.config.SessionFailureProbability = 1
+	assert.NoError(t, ueStore.UpdateRrcState(ctx, idle.IMSI, model.RrcConnected))
+	assert.NoError(t, ueStore.UpdateRrcState(ctx, connected.IMSI, model.RrcConnected))
+	later := now.Add(3 * time.Hour)
+	core.step(ctx, later)
+	core.step(ctx, later.Add(time.Second))
+	assert.Equal(t, uint64(2), count(RegistrationAttempts))
+	assert.Equal(t, uint64(1), count(RegistrationSuccesses))
+	assert.Equal(t, uint64(2), count(SessionSetupAttempts))
+	assert.Equal(t, uint64(1), count(SessionSetupFailures))
+	core.step(ctx, later.Add(core.config.RetryInterval))
+	assert.Equal(t, uint64(3), count(RegistrationAttempts))
+}
+
+func TestSessionProfiles(t *testing.T) {
+	ctx := context.Background()
+	m := model.Model{}
+	assert.NoError(t, model.LoadConfig(&m, "../model/test"))
+	cellStore := cells.NewCellRegistry(m.Cells, nodes.NewNodeRegistry(m.Nodes))
+	ueStore := ues.NewUERegistry(1, cellStore)
+	metricStore := metrics.NewMetricsStore()
+	core := NewCore(ueStore, metricStore, model.CoreConfig{})
+	ue := ueStore.ListAllUEs(ctx)[0]
+	core.SetProfiles(map[model.UEType]model.ActivityProfile{
+		ue.Type: {Sessions: []model.SessionProfile{{SNSSAI: "1-010203"}, {SNSSAI: "2", FiveQI: 1}}},
+	})
+
+	ecgi := types.ECGI(84325717505)
+	assert.NoError(t, ueStore.MoveToCell(ctx, ue.IMSI, ecgi, 10))
+	assert.NoError(t, ueStore.UpdateRrcState(ctx, ue.IMSI, model.RrcConnected))
+	now := time.Now()
+	core.step(ctx, now)
+	core.step(ctx, now)
+
+	// Each session of the profile is established on its own slice and DRB
+	assert.Equal(t, []*model.PDUSession{
+		{ID: 1, SNSSAI: "1-010203", FiveQI: 9, DRB: 1},
+		{ID: 2, SNSSAI: "2", FiveQI: 1, DRB: 2},
+	}, ue.PDUSessions)
+	spec, _ := metricStore.Get(ctx, uint64(ue.IMSI), "drb.2")
+	assert.Equal(t, "qfi=2,fiveQI=1", spec)
+	for _, name := range []string{SessionSetupAttempts + ".1-010203", SessionSetupSuccesses + ".2"} {
+		value, _ := metricStore.Get(ctx, uint64(ecgi), name)
+		assert.Equal(t, uint64(1), value, name)
+	}
+	value, _ := metricStore.Get(ctx, uint64(ecgi), SessionSetupSuccesses)
+	assert.Equal(t, uint64(2), value)
+}
@@ -0,0 +1,86 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package e2agent
+
+import (
+	"strings"
+
+	e2apies "github.com/onosproject/onos-e2t/api/e2ap/v1beta2/e2ap-ies"
+	e2appducontents "github.com/onosproject/onos-e2t/api/e2ap/v1beta2/e2ap-pdu-contents"
+	e2aptypes "github.com/onosproject/onos-e2t/pkg/southbound/e2ap101/types"
+	"github.com/onosproject/ran-simulator/pkg/servicemodel/registry"
+	subutils "github.com/onosproject/ran-simulator/pkg/utils/e2ap/subscription"
+)
+
+// actionTypeName returns the name of the action type used by admission policies, e.g. report
+func actionTypeName(actionType e2apies.RicactionType) string {
+	return strings.ToLower(strings.TrimPrefix(actionType.String(), "RICACTION_TYPE_"))
+}
+
+// serviceModelName returns the name the service model with the given RAN function ID has in the node
+func (a *e2Agent) serviceModelName(ranFuncID registry.RanFunctionID) string {
+	if a.model == nil {
+		return ""
+	}
+	for _, name := range a.node.ServiceModels {
+		if config, ok := a.model.ServiceModels[name]; ok && registry.RanFunctionID(config.ID) == ranFuncID {
+			return name
+		}
+	}
+	return ""
+}
+
+// admit checks the subscription request for the given RAN function against the admission policy of the node and
+// returns the cause of the subscription failure if the request is not admitted. The actions whose type is not
+// admitted for the service model are removed from the request and returned with their cause, so that they are
+// reported as not admitted; the request is rejected if none remain. The limit of subscriptions is enforced when
+// the subscription is added to the store
+func (a *e2Agent) admit(request *e2appducontents.RicsubscriptionRequest, ranFuncID registry.RanFunctionID) (map[e2aptypes.RicActionID]*e2apies.Cause, *e2apies.Cause) {
+	policy := a.node.Admission
+	actions := subutils.GetRicActionToBeSetupList(request)
+	if policy.MaxActions > 0 && len(actions) > policy.MaxActions {
+		log.Warnf("E2 node %d admits at most %d actions per subscription", a.node.EnbID, policy.MaxActions)
+		return nil, &e2apies.Cause{
+			Cause: &e2apies.Cause_RicRequest{
+				RicRequest: e2apies.CauseRic_CAUSE_RIC_EXCESSIVE_ACTIONS,
+			},
+		}
+	}
+	actionTypes, ok := policy.ActionTypes[a.serviceModelName(ranFuncID)]
+	if !ok {
+		return nil, nil
+	}
+	notSupported := &e2apies.Cause{
+		Cause: &e2apies.Cause_RicRequest{
+			RicRequest: e2apies.CauseRic_CAUSE_RIC_ACTION_NOT_SUPPORTED,
+		},
+	}
+	admitted := make([]*e2appducontents.RicactionToBeSetupItemIes, 0, len(actions))
+	notAdmitted := make(map[e2aptypes.RicActionID]*e2apies.Cause)
+	for _, action := range actions {
+		if admitsActionType(actionTypes, action.GetValue().GetRicActionType()) {
+			admitted = append(admitted, action)
+		} else {
+			notAdmitted[e2aptypes.RicActionID(action.GetValue().GetRicActionId().GetValue())] = notSupported
+		}
+	}
+	if len(admitted) == 0 {
+		log.Warnf("E2 node %d admits none of the actions of the subscription", a.node.EnbID)
+		return nil, notSupported
+	}
+	request.ProtocolIes.E2ApProtocolIes30.Value.RicActionToBeSetupList.Value = admitted
+	return notAdmitted, nil
+}
+
+// admitsActionType returns true if the action type is one of the given admitted action types
+func admitsActionType(actionTypes []string, actionType e2apies.RicactionType) bool {
+	name := actionTypeName(actionType)
+	for _, admitted := range actionTypes {
+		if strings.EqualFold(admitted, name) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,85 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package e2agent
+
+import (
+	"testing"
+
+	e2apies "github.com/onosproject/onos-e2t/api/e2ap/v1beta2/e2ap-ies"
+	e2appducontents "github.com/onosproject/onos-e2t/api/e2ap/v1beta2/e2ap-pdu-contents"
+	"github.com/onosproject/ran-simulator/pkg/model"
+	"github.com/onosproject/ran-simulator/pkg/servicemodel/registry"
+	subutils "github.com/onosproject/ran-simulator/pkg/utils/e2ap/subscription"
+	"github.com/stretchr/testify/assert"
+)
+
+func subscriptionRequest(actionTypes ...e2apies.RicactionType) *e2appducontents.RicsubscriptionRequest {
+	actions := make([]*e2appducontents.RicactionToBeSetupItemIes, 0, len(actionTypes))
+	for i, actionType := range actionTypes {
+		actions = append(actions, &e2appducontents.RicactionToBeSetupItemIes{
+			Value: &e2appducontents.RicactionToBeSetupItem{
+				RicActionId:   &e2apies.RicactionId{Value: int32(i + 1)},
+				RicActionType: actionType,
+			},
+		})
+	}
+	return &e2appducontents.RicsubscriptionRequest{
+		ProtocolIes: &e2appducontents.RicsubscriptionRequestIes{
+			E2ApProtocolIes5:  &e2appducontents.RicsubscriptionRequestIes_RicsubscriptionRequestIes5{Value: &e2apies.RanfunctionId{Value: int32(registry.Kpm2)}},
+			E2ApProtocolIes29: &e2appducontents.RicsubscriptionRequestIes_RicsubscriptionRequestIes29{Value: &e2apies.RicrequestId{RicRequestorId: 1, RicInstanceId: 1}},
+			E2ApProtocolIes30: &e2appducontents.RicsubscriptionRequestIes_RicsubscriptionRequestIes30{
+				Value: &e2appducontents.RicsubscriptionDetails{
+					RicActionToBeSetupList: &e2appducontents.RicactionsToBeSetupList{Value: actions},
+				},
+			},
+		},
+	}
+}
+
+func TestAdmit(t *testing.T) {
+	agent := &e2Agent{
+		node: model.Node{
+			EnbID:         144470,
+			ServiceModels: []string{"kpm2"},
+			Admission: model.AdmissionPolicy{
+				MaxActions:  2,
+				ActionTypes: map[string][]string{"kpm2": {"Report"}},
+			},
+		},
+		model: &model.Model{ServiceModels: map[string]model.ServiceModel{"kpm2": {ID: int(registry.Kpm2)}}},
+	}
+	report, policy := e2apies.RicactionType_RICACTION_TYPE_REPORT, e2apies.RicactionType_RICACTION_TYPE_POLICY
+
+	// Actions of types not admitted for the service model are removed from the request and reported as not admitted
+	request := subscriptionRequest(policy, report)
+	notAdmitted, cause := agent.admit(request, registry.Kpm2)
+	assert.Nil(t, cause)
+	actions := subutils.GetRicActionToBeSetupList(request)
+	if assert.Equal(t, 1, len(actions)) {
+		assert.Equal(t, int32(2), actions[0].GetValue().GetRicActionId().GetValue())
+	}
+	if assert.Len(t, notAdmitted, 1) {
+		assert.Equal(t, e2apies.CauseRic_CAUSE_RIC_ACTION_NOT_SUPPORTED, notAdmitted[1].GetRicRequest())
+	}
+	_, cause = agent.admit(subscriptionRequest(policy), registry.Kpm2)
+	assert.Equal(t, e2apies.CauseRic_CAUSE_RIC_ACTION_NOT_SUPPORTED, cause.GetRicRequest())
+	notAdmitted, cause = agent.admit(subscriptionRequest(policy), registry.Rc)
+	assert.Nil(t, cause)
+	assert.Empty(t, notAdmitted)
+
+	_, cause = agent.admit(subscriptionRequest(report, report, report), registry.Kpm2)
+	assert.Equal(t, e2apies.CauseRic_CAUSE_RIC_EXCESSIVE_ACTIONS, cause.GetRicRequest())
+
+	// The not admitted actions are added to the response
+	response, err := subutils.NewSubscription().BuildSubscriptionResponse()
+	assert.NoError(t, err)
+	subutils.AddActionsNotAdmitted(response, notAdmitted)
+	assert.Empty(t, response.GetProtocolIes().GetE2ApProtocolIes18().GetValue().GetValue())
+	notAdmitted, _ = agent.admit(subscriptionRequest(policy, report), registry.Kpm2)
+	subutils.AddActionsNotAdmitted(response, notAdmitted)
+	if items := response.GetProtocolIes().GetE2ApProtocolIes18().GetValue().GetValue(); assert.Len(t, items, 1) {
+		assert.Equal(t, int32(1), items[0].GetValue().GetRicActionId().GetValue())
+	}
+}
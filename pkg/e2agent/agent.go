@@ -6,7 +6,10 @@ package e2agent
 
 import (
 	"context"
+	"crypto/tls"
 	"fmt"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/onosproject/ran-simulator/pkg/servicemodel/kpm2"
@@ -24,14 +27,15 @@ import (
 
 	"github.com/onosproject/ran-simulator/pkg/servicemodel/rc"
 
-	e2aptypes "github.com/onosproject/onos-e2t/pkg/southbound/e2ap101/types"
-
 	subdeleteutils "github.com/onosproject/ran-simulator/pkg/utils/e2ap/subscriptiondelete"
 
 	"github.com/onosproject/ran-simulator/pkg/store/subscriptions"
 
 	"github.com/cenkalti/backoff"
+	"github.com/onosproject/onos-e2t/api/e2ap/v1beta2"
 	e2apies "github.com/onosproject/onos-e2t/api/e2ap/v1beta2/e2ap-ies"
+	"github.com/onosproject/ran-simulator/pkg/journal"
+	"github.com/onosproject/ran-simulator/pkg/kpiprofile"
 	"github.com/onosproject/ran-simulator/pkg/model"
 	"github.com/onosproject/ran-simulator/pkg/modelplugins"
 	"github.com/onosproject/ran-simulator/pkg/servicemodel/kpm"
@@ -40,6 +44,8 @@ import (
 
 	e2appducontents "github.com/onosproject/onos-e2t/api/e2ap/v1beta2/e2ap-pdu-contents"
 	e2 "github.com/onosproject/onos-e2t/pkg/protocols/e2ap101"
+	"github.com/onosproject/onos-e2t/pkg/protocols/e2ap101/channels"
+	"github.com/onosproject/onos-e2t/pkg/protocols/e2ap101/procedures"
 	"github.com/onosproject/onos-lib-go/pkg/errors"
 	"github.com/onosproject/onos-lib-go/pkg/logging"
 	"github.com/onosproject/ran-simulator/pkg/servicemodel/registry"
@@ -47,6 +53,34 @@ import (
 
 var log = logging.GetLogger("e2agent")
 
+// Status values of the E2 node agents recorded in the node store
+const (
+	// StatusPending is the status of agents waiting to start
+	StatusPending = "Pending"
+	// StatusConnecting is the status of agents connecting to their E2T controller
+	StatusConnecting = "Connecting"
+	// StatusSettingUp is the status of agents performing the E2 setup procedure
+	StatusSettingUp = "SettingUp"
+	// StatusRunning is the status of agents which completed the E2 setup
+	StatusRunning = "Running"
+	// StatusStopped is the status of stopped agents
+	StatusStopped = "Stopped"
+	// StatusFailed is the status of agents which failed to start
+	StatusFailed = "Failed"
+)
+
+// Options are the settings shared by the agents of a simulation, e.g. of a tenant
+type Options struct {
+	// CaptureDir is the directory to capture the E2AP traffic of the nodes to, one pcap file per node; the traffic is
+	// not captured if empty
+	CaptureDir string
+	// TLSConfig secures the E2 connections of the nodes, TLS running over the SCTP association as in RFC 3436; the
+	// connections are not secured if nil
+	TLSConfig *tls.Config
+	// Anomalies are the KPI anomalies injected into the measurements reported by the nodes
+	Anomalies *kpiprofile.Anomalies
+}
+
 // E2Agent is an E2 agent
 type E2Agent interface {
 	// Start starts the agent
@@ -54,30 +88,70 @@ type E2Agent interface {
 
 	// Stop stops the agent
 	Stop() error
+
+	// AuditSubscriptions cross-checks the subscriptions created until the audit started against the running report
+	// loops and those known to E2T
+	AuditSubscriptions(known []subscriptions.ID, clean bool, started time.Time) subscriptions.Audit
+
+	// Stats returns the current statistics of the agent
+	Stats() Stats
+
+	// UpdateRanFunctions rebuilds the RAN function descriptions after the cells of the node changed
+	UpdateRanFunctions(node model.Node) error
 }
 
 // e2Agent is an E2 agent
 type e2Agent struct {
 	node      model.Node
 	model     *model.Model
-	channel   e2.ClientChannel
+	options   Options
 	registry  *registry.ServiceModelRegistry
 	subStore  *subscriptions.Subscriptions
 	nodeStore nodes.Store
 	ueStore   ues.Store
 	cellStore cells.Store
+
+	metricStore metrics.Store
+
+	indicationBucket *tokenBucket
+
+	// channel is the connection to the controller, replaced when the agent reconnects; ctx is cancelled when the agent
+	// is stopped, which ends the retries of the connection and the E2 setup
+	channelMu sync.RWMutex
+	channel   e2.ClientChannel
+	ctx       context.Context
+	cancel    context.CancelFunc
+
+	// cells are the cells of the node the RAN functions were last described with
+	cellsMu sync.Mutex
+	cells   []ransimtypes.ECGI
+
+	// connected and indicationsSent are accessed atomically
+	connected       int32
+	indicationsSent uint64
 }
 
 // NewE2Agent creates a new E2 agent
 func NewE2Agent(node model.Node, model *model.Model, modelPluginRegistry modelplugins.ModelRegistry,
-	nodeStore nodes.Store, ueStore ues.Store, cellStore cells.Store, metricStore metrics.Store) (E2Agent, error) {
+	nodeStore nodes.Store, ueStore ues.Store, cellStore cells.Store, metricStore metrics.Store, options Options) (E2Agent, error) {
 	log.Info("Creating New E2 Agent for node with eNbID:", node.EnbID)
 	reg := registry.NewServiceModelRegistry()
 
 	// Each new e2 agent has its own subscription store
 	subStore := subscriptions.NewStore()
 	sms := node.ServiceModels
+	for _, name := range node.RanFunctions {
+		if !contains(sms, name) {
+			log.Warnf("E2 node %d cannot advertise RAN function %s missing from its service models", node.EnbID, name)
+		}
+	}
 	for _, smID := range sms {
+		// Service models which are not advertised are not registered, so that requests for them are rejected as
+		// for any unknown RAN function
+		if !node.Advertises(smID) {
+			log.Infof("E2 node %d does not advertise RAN function %s", node.EnbID, smID)
+			continue
+		}
 		serviceModel, err := model.GetServiceModel(smID)
 		if err != nil {
 			return nil, err
@@ -108,7 +182,7 @@ func NewE2Agent(node model.Node, model *model.Model, modelPluginRegistry modelpl
 		case registry.Kpm2:
 			log.Info("KPM2 service model for node with eNbID:", node.EnbID)
 			kpm2Sm, err := kpm2.NewServiceModel(node, model, modelPluginRegistry,
-				subStore, nodeStore, ueStore)
+				subStore, nodeStore, ueStore, metricStore, options.Anomalies)
 			if err != nil {
 				log.Info("Failure creating KPM2 service model for eNbID:", node.EnbID)
 				return nil, err
@@ -125,27 +199,59 @@ func NewE2Agent(node model.Node, model *model.Model, modelPluginRegistry modelpl
 		node:      node,
 		registry:  reg,
 		model:     model,
+		options:   options,
 		subStore:  subStore,
 		nodeStore: nodeStore,
 		ueStore:   ueStore,
 		cellStore: cellStore,
+
+		metricStore: metricStore,
+
+		indicationBucket: &tokenBucket{},
+
+		cells: append([]ransimtypes.ECGI(nil), node.Cells...),
 	}, nil
 }
 
 func (a *e2Agent) RICControl(ctx context.Context, request *e2appducontents.RiccontrolRequest) (response *e2appducontents.RiccontrolAcknowledge, failure *e2appducontents.RiccontrolFailure, err error) {
 	ranFuncID := registry.RanFunctionID(controlutils.GetRanFunctionID(request))
 	log.Debugf("Received Control Request %+v for ran function %d", request, ranFuncID)
+	a.trace(ctx, traceReceived, request)
+	defer func() {
+		a.trace(ctx, traceSent, response)
+		a.trace(ctx, traceSent, failure)
+	}()
 	sm, err := a.registry.GetServiceModel(ranFuncID)
 	if err != nil {
 		log.Warn(err)
-		// TODO If the target E2 Node receives a RIC CONTROL REQUEST message
+		// If the target E2 Node receives a RIC CONTROL REQUEST message
 		//  which contains a RAN Function ID IE that was not previously announced as a s
 		//  supported RAN function in the E2 Setup procedure or the RIC Service Update procedure,
 		//  or the E2 Node does not support the specific RIC Control procedure action, then
 		//  the target E2 Node shall ignore message and send an ERROR INDICATION message to the Near-RT RIC.
-
+		cause := &e2apies.Cause{
+			Cause: &e2apies.Cause_RicRequest{
+				RicRequest: e2apies.CauseRic_CAUSE_RIC_RAN_FUNCTION_ID_INVALID,
+			},
+		}
+		return a.controlFailure(request, cause)
+	}
+	if a.isFailureForced(ctx, controlProcedure) {
+		cause := &e2apies.Cause{
+			Cause: &e2apies.Cause_RicRequest{
+				RicRequest: e2apies.CauseRic_CAUSE_RIC_UNSPECIFIED,
+			},
+		}
+		return a.controlFailure(request, cause)
+	}
+	// The node takes its processing time before acting upon the request and acknowledging it, if at all
+	executed, err := a.processControl(ctx)
+	if err != nil {
 		return nil, nil, err
 	}
+	if !executed {
+		return a.executionFailure(request)
+	}
 	switch sm.RanFunctionID {
 	case registry.Kpm:
 		client := sm.Client.(*kpm.Client)
@@ -153,6 +259,9 @@ func (a *e2Agent) RICControl(ctx context.Context, request *e2appducontents.Ricco
 	case registry.Rc:
 		client := sm.Client.(*rc.Client)
 		response, failure, err = client.RICControl(ctx, request)
+	case registry.Kpm2:
+		client := sm.Client.(*kpm2.Client)
+		response, failure, err = client.RICControl(ctx, request)
 	}
 	if err != nil {
 		return nil, nil, err
@@ -161,9 +270,32 @@ func (a *e2Agent) RICControl(ctx context.Context, request *e2appducontents.Ricco
 	return response, failure, err
 }
 
+// controlFailure reports an error indication for a control request the node is unable to process
+// and answers it with a control failure carrying the same cause
+func (a *e2Agent) controlFailure(request *e2appducontents.RiccontrolRequest, cause *e2apies.Cause) (*e2appducontents.RiccontrolAcknowledge, *e2appducontents.RiccontrolFailure, error) {
+	reqID := controlutils.GetRequesterID(request)
+	ricInstanceID := controlutils.GetRicInstanceID(request)
+	ranFuncID := controlutils.GetRanFunctionID(request)
+	a.reportErrorIndication(v1beta2.ProcedureCodeIDRICcontrol, reqID, ricInstanceID, ranFuncID, cause)
+	failure, err := controlutils.NewControl(
+		controlutils.WithRanFuncID(ranFuncID),
+		controlutils.WithRequestID(reqID),
+		controlutils.WithRicInstanceID(ricInstanceID),
+		controlutils.WithCause(*cause)).BuildControlFailure()
+	if err != nil {
+		return nil, nil, err
+	}
+	return nil, failure, nil
+}
+
 func (a *e2Agent) RICSubscription(ctx context.Context, request *e2appducontents.RicsubscriptionRequest) (response *e2appducontents.RicsubscriptionResponse, failure *e2appducontents.RicsubscriptionFailure, err error) {
 	ranFuncID := registry.RanFunctionID(subutils.GetRanFunctionID(request))
 	log.Debugf("Received Subscription Request %v for ran function %d", request, ranFuncID)
+	a.trace(ctx, traceReceived, request)
+	defer func() {
+		a.trace(ctx, traceSent, response)
+		a.trace(ctx, traceSent, failure)
+	}()
 	sm, err := a.registry.GetServiceModel(ranFuncID)
 	id := subscriptions.NewID(subutils.GetRicInstanceID(request),
 		subutils.GetRequesterID(request),
@@ -176,40 +308,68 @@ func (a *e2Agent) RICSubscription(ctx context.Context, request *e2appducontents.
 		//  announced as a supported RAN function in the E2 Setup procedure or
 		//  the RIC Service Update procedure, the target E2 Node shall send the RIC SUBSCRIPTION FAILURE message
 		//  to the Near-RT RIC with an appropriate cause value.
-		var ricActionsAccepted []*e2aptypes.RicActionID
-		ricActionsNotAdmitted := make(map[e2aptypes.RicActionID]*e2apies.Cause)
-		actionList := subutils.GetRicActionToBeSetupList(request)
-		reqID := subutils.GetRequesterID(request)
-		ranFuncID := subutils.GetRanFunctionID(request)
-		ricInstanceID := subutils.GetRicInstanceID(request)
-
-		for _, action := range actionList {
-			actionID := e2aptypes.RicActionID(action.Value.RicActionId.Value)
-			cause := &e2apies.Cause{
-				Cause: &e2apies.Cause_RicRequest{
-					RicRequest: e2apies.CauseRic_CAUSE_RIC_RAN_FUNCTION_ID_INVALID,
-				},
-			}
-			ricActionsNotAdmitted[actionID] = cause
+		cause := &e2apies.Cause{
+			Cause: &e2apies.Cause_RicRequest{
+				RicRequest: e2apies.CauseRic_CAUSE_RIC_RAN_FUNCTION_ID_INVALID,
+			},
+		}
+		failure, err := subutils.NewSubscriptionFailure(request, cause)
+		if err != nil {
+			return nil, nil, err
+		}
+		return nil, failure, nil
+	}
+	if a.isFailureForced(ctx, subscriptionProcedure) {
+		cause := &e2apies.Cause{
+			Cause: &e2apies.Cause_RicRequest{
+				RicRequest: e2apies.CauseRic_CAUSE_RIC_UNSPECIFIED,
+			},
 		}
-		subscription := subutils.NewSubscription(
-			subutils.WithRequestID(reqID),
-			subutils.WithRanFuncID(ranFuncID),
-			subutils.WithRicInstanceID(ricInstanceID),
-			subutils.WithActionsAccepted(ricActionsAccepted),
-			subutils.WithActionsNotAdmitted(ricActionsNotAdmitted))
-		failure, err := subscription.BuildSubscriptionFailure()
+		failure, err := subutils.NewSubscriptionFailure(request, cause)
 		if err != nil {
 			return nil, nil, err
 		}
 		return nil, failure, nil
 	}
-	subscription, err := subscriptions.NewSubscription(id, request, a.channel)
+	subscription, err := subscriptions.NewSubscription(id, request, newPacedChannel(newChaosChannel(newLatencyChannel(newFuzzChannel(newTraceChannel(newCountingChannel(a.getChannel(), &a.indicationsSent), a.trace), id, a.fuzz, a.recordCorruption), id, a.timestampsEnabled, a.publishIndicationLatency), a.chaos), id, a.allowIndication))
 	if err != nil {
 		return response, failure, err
 	}
-	err = a.subStore.Add(subscription)
-	if err != nil {
+	notAdmitted, cause := a.admit(request, ranFuncID)
+	if cause != nil {
+		failure, err := subutils.NewSubscriptionFailure(request, cause)
+		if err != nil {
+			return nil, nil, err
+		}
+		return nil, failure, nil
+	}
+	err = a.subStore.AddLimited(subscription, a.node.Admission.MaxSubscriptions)
+	if errors.IsForbidden(err) {
+		log.Warnf("E2 node %d reached its limit of %d subscriptions", a.node.EnbID, a.node.Admission.MaxSubscriptions)
+		cause := &e2apies.Cause{
+			Cause: &e2apies.Cause_RicRequest{
+				RicRequest: e2apies.CauseRic_CAUSE_RIC_FUNCTION_RESOURCE_LIMIT,
+			},
+		}
+		failure, err := subutils.NewSubscriptionFailure(request, cause)
+		if err != nil {
+			return nil, nil, err
+		}
+		return nil, failure, nil
+	} else if errors.IsAlreadyExists(err) {
+		// Installing the same subscription twice must not start another report loop
+		log.Warnf("E2 node %d rejected subscription %s: %v", a.node.EnbID, id, err)
+		cause := &e2apies.Cause{
+			Cause: &e2apies.Cause_RicRequest{
+				RicRequest: e2apies.CauseRic_CAUSE_RIC_DUPLICATE_ACTION,
+			},
+		}
+		failure, err := subutils.NewSubscriptionFailure(request, cause)
+		if err != nil {
+			return nil, nil, err
+		}
+		return nil, failure, nil
+	} else if err != nil {
 		return response, failure, err
 	}
 
@@ -225,17 +385,36 @@ func (a *e2Agent) RICSubscription(ctx context.Context, request *e2appducontents.
 		response, failure, err = client.RICSubscription(ctx, request)
 
 	}
-	// Ric subscription is failed
-	if err != nil {
+	// The actions the admission policy filtered out are reported as not admitted along with those of the service model
+	if response != nil {
+		subutils.AddActionsNotAdmitted(response, notAdmitted)
+	}
+	if failure != nil {
+		subutils.AddFailedActionsNotAdmitted(failure, notAdmitted)
+	}
+	// Ric subscription is failed so the subscription is not retained
+	if err != nil || failure != nil {
+		if err := a.subStore.Remove(id); err != nil {
+			log.Warn(err)
+		}
 		return response, failure, err
 	}
+	journal.Record(journal.SubscriptionCreated, uint64(a.node.EnbID), map[string]interface{}{
+		"subscriptionID": id,
+		"ranFunctionID":  ranFuncID,
+	})
 
-	return response, failure, err
+	return a.misbehaveSubscriptionResponse(ctx, response), failure, err
 }
 
 func (a *e2Agent) RICSubscriptionDelete(ctx context.Context, request *e2appducontents.RicsubscriptionDeleteRequest) (response *e2appducontents.RicsubscriptionDeleteResponse, failure *e2appducontents.RicsubscriptionDeleteFailure, err error) {
 	ranFuncID := registry.RanFunctionID(request.ProtocolIes.E2ApProtocolIes5.Value.Value)
 	log.Debugf("Received Subscription Delete Request %v for ran function ID %d", request, ranFuncID)
+	a.trace(ctx, traceReceived, request)
+	defer func() {
+		a.trace(ctx, traceSent, response)
+		a.trace(ctx, traceSent, failure)
+	}()
 	subID := subscriptions.NewID(subdeleteutils.GetRicInstanceID(request),
 		subdeleteutils.GetRequesterID(request),
 		subdeleteutils.GetRanFunctionID(request))
@@ -264,6 +443,24 @@ func (a *e2Agent) RICSubscriptionDelete(ctx context.Context, request *e2appducon
 
 	}
 
+	if a.isFailureForced(ctx, subscriptionDeleteProcedure) {
+		cause := &e2apies.Cause{
+			Cause: &e2apies.Cause_RicRequest{
+				RicRequest: e2apies.CauseRic_CAUSE_RIC_UNSPECIFIED,
+			},
+		}
+		subscriptionDelete := subdeleteutils.NewSubscriptionDelete(
+			subdeleteutils.WithRanFuncID(subdeleteutils.GetRanFunctionID(request)),
+			subdeleteutils.WithRequestID(subdeleteutils.GetRequesterID(request)),
+			subdeleteutils.WithRicInstanceID(subdeleteutils.GetRicInstanceID(request)),
+			subdeleteutils.WithCause(cause))
+		failure, err := subscriptionDelete.BuildSubscriptionDeleteFailure()
+		if err != nil {
+			return nil, nil, err
+		}
+		return nil, failure, nil
+	}
+
 	sm, err := a.registry.GetServiceModel(ranFuncID)
 	if err != nil {
 		log.Warn(err)
@@ -312,40 +509,162 @@ func (a *e2Agent) RICSubscriptionDelete(ctx context.Context, request *e2appducon
 		log.Error(err)
 		return nil, nil, err
 	}
+	a.clearIndicationLatency(ctx, subID)
+	journal.Record(journal.SubscriptionDeleted, uint64(a.node.EnbID), map[string]interface{}{
+		"subscriptionID": subID,
+		"ranFunctionID":  ranFuncID,
+	})
 	return response, failure, err
 }
 
 func (a *e2Agent) Start() error {
 	if len(a.node.Controllers) == 0 {
+		a.setStatus(StatusFailed)
 		return errors.New(errors.Invalid, "no controller is associated with this node")
 	}
 
+	ctx, cancel := context.WithCancel(context.Background())
+	a.channelMu.Lock()
+	a.ctx, a.cancel = ctx, cancel
+	a.channelMu.Unlock()
 	log.Infof("E2 node %d is starting; attempting to connect", a.node.EnbID)
-	b := newExpBackoff()
+	return a.run(ctx)
+}
 
-	// Attempt to connect to the E2T controller; use exponential back-off retry
+// run connects to the controller and performs the E2 setup, retrying both until they succeed or the agent is stopped
+func (a *e2Agent) run(ctx context.Context) error {
+	a.setStatus(StatusConnecting)
+	a.setConnectionState(a.connectingState())
+	b := backoff.WithContext(newExpBackoff(), ctx)
+
+	// Attempt to connect to the E2T controller; use jittered exponential back-off retry
 	count := 0
 	connectNotify := func(err error, t time.Duration) {
 		count++
-		log.Infof("E2 node %d failed to connect; retry after %v; attempt %d", a.node.EnbID, b.GetElapsedTime(), count)
+		log.Infof("E2 node %d failed to connect; retry after %v; attempt %d", a.node.EnbID, t, count)
 	}
 
 	err := backoff.RetryNotify(a.connect, b, connectNotify)
 	if err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		a.setStatus(StatusFailed)
+		a.setConnectionState(model.ConnectionFailed)
 		return err
 	}
 	log.Infof("E2 node %d connected; attempting setup", a.node.EnbID)
+	a.setStatus(StatusSettingUp)
 
-	// Attempt to negotiate E2 setup procedure; use exponential back-off retry
+	// Attempt to negotiate E2 setup procedure; use jittered exponential back-off retry
 	count = 0
 	setupNotify := func(err error, t time.Duration) {
 		count++
-		log.Infof("E2 node %d failed setup procedure; retry after %v; attempt %d", a.node.EnbID, b.GetElapsedTime(), count)
+		log.Infof("E2 node %d failed setup procedure; retry after %v; attempt %d", a.node.EnbID, t, count)
 	}
 
+	b.Reset()
 	err = backoff.RetryNotify(a.setup, b, setupNotify)
+	if ctx.Err() != nil {
+		// The agent was stopped while setting up
+		if channel := a.getChannel(); channel != nil {
+			_ = channel.Close()
+		}
+		return ctx.Err()
+	}
+	if err != nil {
+		a.setStatus(StatusFailed)
+		a.setConnectionState(model.ConnectionFailed)
+		return err
+	}
 	log.Infof("E2 node %d completed connection setup", a.node.EnbID)
-	return err
+	atomic.StoreInt32(&a.connected, 1)
+	journal.Record(journal.NodeConnected, uint64(a.node.EnbID), nil)
+	a.setStatus(StatusRunning)
+	a.setConnectionState(model.ConnectionConnected)
+	if channel := a.getChannel(); channel != nil {
+		go a.supervise(ctx, channel)
+	}
+	return nil
+}
+
+// supervise reconnects to the controller once the given connection is closed, e.g. by the controller, unless the
+// agent is stopped or the connection replaced in the meantime
+func (a *e2Agent) supervise(ctx context.Context, channel e2.ClientChannel) {
+	select {
+	case <-ctx.Done():
+	case <-channel.Context().Done():
+		if ctx.Err() == nil && a.getChannel() == channel {
+			a.reconnect("connection to the controller lost")
+		}
+	}
+}
+
+// reconnect closes the connection to the controller and connects again in the background, which performs a new E2
+// setup; the subscriptions are released, as the controller drops them along with the connection
+func (a *e2Agent) reconnect(reason string) {
+	if !atomic.CompareAndSwapInt32(&a.connected, 1, 0) {
+		return
+	}
+	log.Infof("E2 node %d is reconnecting: %s", a.node.EnbID, reason)
+	a.channelMu.RLock()
+	channel, ctx := a.channel, a.ctx
+	a.channelMu.RUnlock()
+	if channel == nil {
+		// The agent is being stopped
+		return
+	}
+	a.releaseSubscriptions()
+	journal.Record(journal.NodeDisconnected, uint64(a.node.EnbID), nil)
+	a.setConnectionState(model.ConnectionReconnecting)
+	if err := channel.Close(); err != nil {
+		log.Warnf("E2 node %d connection could not be closed: %v", a.node.EnbID, err)
+	}
+	go func() {
+		if err := a.run(ctx); err != nil && ctx.Err() == nil {
+			log.Warnf("E2 node %d failed to reconnect: %v", a.node.EnbID, err)
+		}
+	}()
+}
+
+// getChannel returns the current connection to the controller
+func (a *e2Agent) getChannel() e2.ClientChannel {
+	a.channelMu.RLock()
+	defer a.channelMu.RUnlock()
+	return a.channel
+}
+
+// setStatus records the status of the agent in the node store
+func (a *e2Agent) setStatus(status string) {
+	if a.nodeStore == nil {
+		return
+	}
+	if err := a.nodeStore.SetStatus(context.Background(), a.node.EnbID, status); err != nil {
+		log.Warnf("E2 node %d status could not be set to %s: %v", a.node.EnbID, status, err)
+	}
+}
+
+// connectingState returns the state of the connection to the controller while being established, i.e. reconnecting
+// if the node was connected to the controller before
+func (a *e2Agent) connectingState() model.ConnectionState {
+	if a.nodeStore != nil {
+		if node, err := a.nodeStore.Get(context.Background(), a.node.EnbID); err == nil {
+			if _, ok := node.Connections[a.node.Controllers[0]]; ok {
+				return model.ConnectionReconnecting
+			}
+		}
+	}
+	return model.ConnectionConnecting
+}
+
+// setConnectionState records the state of the connection to the controller in the node store
+func (a *e2Agent) setConnectionState(state model.ConnectionState) {
+	if a.nodeStore == nil {
+		return
+	}
+	if err := a.nodeStore.SetConnectionState(context.Background(), a.node.EnbID, a.node.Controllers[0], state); err != nil {
+		log.Warnf("E2 node %d connection state could not be set to %s: %v", a.node.EnbID, state, err)
+	}
 }
 
 func (a *e2Agent) connect() error {
@@ -354,16 +673,31 @@ func (a *e2Agent) connect() error {
 		return err
 	}
 	addr := fmt.Sprintf("%s:%d", controller.Address, controller.Port)
-	channel, err := e2.Connect(context.TODO(), addr,
-		func(channel e2.ClientChannel) e2.ClientInterface {
-			return a
-		},
-	)
-
+	conn, err := dial(context.TODO(), a.node.EnbID, addr)
 	if err != nil {
 		return err
 	}
+	if config := a.options.TLSConfig; config != nil {
+		secured, err := newTLSConn(conn, config, controller.Address)
+		if err != nil {
+			return err
+		}
+		conn = secured
+	}
+	if path, ok := capturePath(a.options.CaptureDir, a.node.EnbID); ok {
+		captured, err := newCaptureConn(conn, path)
+		if err != nil {
+			_ = conn.Close()
+			return err
+		}
+		conn = captured
+	}
+	channel := channels.NewE2NodeChannel(conn, func(channel channels.E2NodeChannel) procedures.E2NodeProcedures {
+		return a
+	})
+	a.channelMu.Lock()
 	a.channel = channel
+	a.channelMu.Unlock()
 	return nil
 }
 
@@ -384,7 +718,10 @@ func (a *e2Agent) setup() error {
 		log.Error(err)
 		return err
 	}
-	_, e2SetupFailure, err := a.channel.E2Setup(context.Background(), e2SetupRequest)
+	a.trace(context.Background(), traceSent, e2SetupRequest)
+	e2SetupResponse, e2SetupFailure, err := a.getChannel().E2Setup(context.Background(), e2SetupRequest)
+	a.trace(context.Background(), traceReceived, e2SetupResponse)
+	a.trace(context.Background(), traceReceived, e2SetupFailure)
 	if err != nil {
 		log.Error(err)
 		return errors.NewUnknown("E2 setup failed: %v", err)
@@ -399,12 +736,46 @@ func (a *e2Agent) setup() error {
 func (a *e2Agent) Stop() error {
 	log.Debugf("Stopping e2 agent with ID %d:", a.node.EnbID)
 
-	if a.channel != nil {
-		return a.channel.Close()
+	atomic.StoreInt32(&a.connected, 0)
+	a.channelMu.Lock()
+	channel, cancel := a.channel, a.cancel
+	a.channel, a.cancel = nil, nil
+	a.channelMu.Unlock()
+	if cancel != nil {
+		cancel()
+	}
+	if channel != nil {
+		a.releaseSubscriptions()
+		journal.Record(journal.NodeDisconnected, uint64(a.node.EnbID), nil)
+		a.setConnectionState(model.ConnectionDisconnected)
+		return channel.Close()
 	}
 	return nil
 }
 
+// releaseSubscriptions removes all subscriptions of the node when it is being stopped.
+// E2AP v1.01 does not define the RIC Subscription Delete Required procedure, so the
+// RIC can only learn about the deletion from the loss of the E2 connection.
+func (a *e2Agent) releaseSubscriptions() {
+	subs, err := a.subStore.List()
+	if err != nil {
+		log.Warn(err)
+		return
+	}
+	for _, sub := range subs {
+		a.releaseSubscription(sub.ID, "node stopped")
+	}
+}
+
+func contains(names []string, name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
 var _ E2Agent = &e2Agent{}
 
 var _ e2.ClientInterface = &e2Agent{}
@@ -6,6 +6,8 @@ package agents
 
 import (
 	"context"
+	"sync"
+	"time"
 
 	"github.com/onosproject/onos-api/go/onos/ransim/types"
 
@@ -20,6 +22,7 @@ import (
 	"github.com/onosproject/ran-simulator/pkg/store/agents"
 	"github.com/onosproject/ran-simulator/pkg/store/event"
 	"github.com/onosproject/ran-simulator/pkg/store/nodes"
+	"github.com/onosproject/ran-simulator/pkg/store/subscriptions"
 	"github.com/onosproject/ran-simulator/pkg/store/ues"
 )
 
@@ -34,6 +37,13 @@ type E2Agents struct {
 	cellStore           cells.Store
 	metricStore         metrics.Store
 	model               *model.Model
+	// setupParallelism is the maximum number of agents performing the E2 setup concurrently
+	setupParallelism int
+	options          e2agent.Options
+	// servedCells holds the cells served by each node as of its last event, so that the RAN functions of a node are
+	// only rebuilt when its cells change rather than upon every status or connection update; only accessed by the
+	// node event loop
+	servedCells map[types.EnbID][]types.ECGI
 }
 
 // Agents agents interface
@@ -59,7 +69,7 @@ func (agents *E2Agents) processNodeEvents() {
 			log.Debugf("Starting e2 agent %d", nodeEvent.Key.(types.EnbID))
 			e2Node, err := e2agent.NewE2Agent(*node, agents.model,
 				agents.modelPluginRegistry, agents.nodeStore, agents.ueStore,
-				agents.cellStore, agents.metricStore)
+				agents.cellStore, agents.metricStore, agents.options)
 			if err != nil {
 				log.Error(err)
 				continue
@@ -68,6 +78,7 @@ func (agents *E2Agents) processNodeEvents() {
 			if err != nil {
 				log.Error(err)
 			}
+			agents.servedCells[node.EnbID] = node.Cells
 
 			err = e2Node.Start()
 			if err != nil {
@@ -77,14 +88,25 @@ func (agents *E2Agents) processNodeEvents() {
 					log.Error(err)
 				}
 			}
-			err = agents.nodeStore.SetStatus(context.Background(), node.EnbID, "Running")
+
+		case nodes.Updated:
+			node := nodeEvent.Value.(*model.Node)
+			if cells, ok := agents.servedCells[node.EnbID]; ok && sameCells(cells, node.Cells) {
+				continue
+			}
+			agents.servedCells[node.EnbID] = node.Cells
+			e2Node, err := agents.agentStore.Get(node.EnbID)
 			if err != nil {
-				log.Error(err)
+				continue
+			}
+			if err := e2Node.UpdateRanFunctions(*node); err != nil {
+				log.Warnf("RAN functions of E2 node %d could not be updated: %v", node.EnbID, err)
 			}
 
 		case nodes.Deleted:
 			log.Debugf("Stopping e2 agent %d", nodeEvent.Key.(types.EnbID))
 			node := nodeEvent.Value.(*model.Node)
+			delete(agents.servedCells, node.EnbID)
 			e2Node, err := agents.agentStore.Get(node.EnbID)
 			if err != nil {
 				log.Error(err)
@@ -100,7 +122,7 @@ func (agents *E2Agents) processNodeEvents() {
 			if err != nil {
 				log.Error(err)
 			}
-			err = agents.nodeStore.SetStatus(context.Background(), node.EnbID, "Stopped")
+			err = agents.nodeStore.SetStatus(context.Background(), node.EnbID, e2agent.StatusStopped)
 			if err != nil {
 				log.Error(err)
 			}
@@ -109,9 +131,11 @@ func (agents *E2Agents) processNodeEvents() {
 	}
 }
 
-// NewE2Agents creates a new collection of E2 agents from the specified list of nodes
+// NewE2Agents creates a new collection of E2 agents from the specified list of nodes, sharing the given options; at
+// most setupParallelism agents perform the E2 setup concurrently, all of them if it is not positive
 func NewE2Agents(m *model.Model, modelPluginRegistry modelplugins.ModelRegistry,
-	nodeStore nodes.Store, ueStore ues.Store, cellStore cells.Store, metricStore metrics.Store) (*E2Agents, error) {
+	nodeStore nodes.Store, ueStore ues.Store, cellStore cells.Store, metricStore metrics.Store, setupParallelism int,
+	options e2agent.Options) (*E2Agents, error) {
 	agentStore := agents.NewStore()
 	e2agents := &E2Agents{
 		agentStore:          agentStore,
@@ -121,10 +145,13 @@ func NewE2Agents(m *model.Model, modelPluginRegistry modelplugins.ModelRegistry,
 		ueStore:             ueStore,
 		cellStore:           cellStore,
 		metricStore:         metricStore,
+		setupParallelism:    setupParallelism,
+		options:             options,
+		servedCells:         make(map[types.EnbID][]types.ECGI),
 	}
 
 	for _, node := range m.Nodes {
-		e2Node, err := e2agent.NewE2Agent(node, m, modelPluginRegistry, nodeStore, ueStore, cellStore, metricStore)
+		e2Node, err := e2agent.NewE2Agent(node, m, modelPluginRegistry, nodeStore, ueStore, cellStore, metricStore, options)
 		if err != nil {
 			log.Error(err)
 			return nil, err
@@ -135,7 +162,8 @@ func NewE2Agents(m *model.Model, modelPluginRegistry modelplugins.ModelRegistry,
 			log.Error(err)
 			return nil, err
 		}
-		err = nodeStore.SetStatus(context.Background(), node.EnbID, "Running")
+		e2agents.servedCells[node.EnbID] = append([]types.ECGI(nil), node.Cells...)
+		err = nodeStore.SetStatus(context.Background(), node.EnbID, e2agent.StatusPending)
 		if err != nil {
 			log.Error(err)
 			return nil, err
@@ -145,7 +173,8 @@ func NewE2Agents(m *model.Model, modelPluginRegistry modelplugins.ModelRegistry,
 	return e2agents, nil
 }
 
-// Start all simulated node agents
+// Start all simulated node agents; the agents connect and perform the E2 setup concurrently, bounded by the setup
+// parallelism, and the first error is returned once all of them completed or failed
 func (agents *E2Agents) Start() error {
 	log.Info("Starting E2 Agents")
 	agentList, err := agents.agentStore.List()
@@ -153,14 +182,29 @@ func (agents *E2Agents) Start() error {
 		log.Error(err)
 		return err
 	}
+	parallelism := agents.setupParallelism
+	if parallelism <= 0 || parallelism > len(agentList) {
+		parallelism = len(agentList)
+	}
+	slots := make(chan struct{}, parallelism)
+	errs := make(chan error, len(agentList))
+	wg := sync.WaitGroup{}
 	for id, agent := range agentList {
-		log.Debug("Starting agent with e2 node ID:", id)
-		err := agent.Start()
-		if err != nil {
-			return err
-		}
+		slots <- struct{}{}
+		wg.Add(1)
+		go func(id types.EnbID, agent e2agent.E2Agent) {
+			defer wg.Done()
+			defer func() { <-slots }()
+			log.Debug("Starting agent with e2 node ID:", id)
+			if err := agent.Start(); err != nil {
+				log.Warnf("E2 node %d failed to start: %v", id, err)
+				errs <- err
+			}
+		}(id, agent)
 	}
-	return nil
+	wg.Wait()
+	close(errs)
+	return <-errs
 }
 
 // Stop all simulated node agents
@@ -181,4 +225,77 @@ func (agents *E2Agents) Stop() error {
 	return nil
 }
 
+// StartAgent starts the agent of the specified node
+func (agents *E2Agents) StartAgent(enbID types.EnbID) error {
+	agent, err := agents.agentStore.Get(enbID)
+	if err != nil {
+		return err
+	}
+	log.Debug("Starting agent with e2 node ID:", enbID)
+	// The agent records its setup status in the node store
+	return agent.Start()
+}
+
+// StopAgent stops the agent of the specified node
+func (agents *E2Agents) StopAgent(enbID types.EnbID) error {
+	agent, err := agents.agentStore.Get(enbID)
+	if err != nil {
+		return err
+	}
+	log.Debug("Stopping agent with e2 node ID:", enbID)
+	err = agent.Stop()
+	if err != nil {
+		return err
+	}
+	return agents.nodeStore.SetStatus(context.Background(), enbID, e2agent.StatusStopped)
+}
+
+// AuditSubscriptions audits the subscriptions of all agents created until the audit started; unless nil, known lists
+// the subscriptions E2T knows about per node, in which case only the listed nodes are audited
+func (agents *E2Agents) AuditSubscriptions(known map[types.EnbID][]subscriptions.ID, clean bool, started time.Time) (map[types.EnbID]subscriptions.Audit, error) {
+	agentList, err := agents.agentStore.List()
+	if err != nil {
+		return nil, err
+	}
+	audits := make(map[types.EnbID]subscriptions.Audit)
+	for id, agent := range agentList {
+		var knownIDs []subscriptions.ID
+		if known != nil {
+			ids, ok := known[id]
+			if !ok {
+				continue
+			}
+			knownIDs = append(make([]subscriptions.ID, 0), ids...)
+		}
+		audits[id] = agent.AuditSubscriptions(knownIDs, clean, started)
+	}
+	return audits, nil
+}
+
+// Stats returns the current statistics of all agents
+func (agents *E2Agents) Stats() (map[types.EnbID]e2agent.Stats, error) {
+	agentList, err := agents.agentStore.List()
+	if err != nil {
+		return nil, err
+	}
+	stats := make(map[types.EnbID]e2agent.Stats, len(agentList))
+	for id, agent := range agentList {
+		stats[id] = agent.Stats()
+	}
+	return stats, nil
+}
+
 var _ Agents = &E2Agents{}
+
+// sameCells returns true if both lists hold the same cells in the same order
+func sameCells(cells []types.ECGI, other []types.ECGI) bool {
+	if len(cells) != len(other) {
+		return false
+	}
+	for i := range cells {
+		if cells[i] != other[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,62 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package e2agent
+
+import (
+	"context"
+	"time"
+
+	"github.com/onosproject/ran-simulator/pkg/journal"
+	"github.com/onosproject/ran-simulator/pkg/store/subscriptions"
+)
+
+// AuditSubscriptions cross-checks the subscriptions of the agent created until the audit started against its running
+// report loops and, unless nil, against the given subscriptions known to E2T. If clean is set, leaked report loops
+// are stopped and stale subscriptions as well as subscriptions unknown to E2T are released; subscriptions missing
+// locally are only reported.
+func (a *e2Agent) AuditSubscriptions(known []subscriptions.ID, clean bool, started time.Time) subscriptions.Audit {
+	audit := a.subStore.Audit(known, started)
+	if !clean || audit.Clean() {
+		return audit
+	}
+	for _, id := range audit.Leaked {
+		log.Infof("Stopping leaked report loop of subscription %s of E2 node %d", id, a.node.EnbID)
+		a.subStore.StopReports(id)
+	}
+	released := make(map[subscriptions.ID]bool)
+	for _, ids := range [][]subscriptions.ID{audit.Stale, audit.UnknownToE2T} {
+		for _, id := range ids {
+			if !released[id] {
+				released[id] = true
+				a.releaseSubscription(id, "audit")
+			}
+		}
+	}
+	return audit
+}
+
+// releaseSubscription stops the reports of the specified subscription and removes it
+func (a *e2Agent) releaseSubscription(id subscriptions.ID, cause string) {
+	sub, err := a.subStore.Get(id)
+	if err != nil {
+		log.Warn(err)
+		return
+	}
+	log.Infof("Releasing subscription %s of E2 node %d", id, a.node.EnbID)
+	if sub.Ticker != nil {
+		sub.Ticker.Stop()
+	}
+	a.subStore.StopReports(id)
+	if err := a.subStore.Remove(id); err != nil {
+		log.Warn(err)
+		return
+	}
+	a.clearIndicationLatency(context.Background(), id)
+	journal.Record(journal.SubscriptionDeleted, uint64(a.node.EnbID), map[string]interface{}{
+		"subscriptionID": id,
+		"ranFunctionID":  sub.FnID.GetValue(),
+		"cause":          cause,
+	})
+}
@@ -0,0 +1,218 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package e2agent
+
+import (
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/onosproject/onos-api/go/onos/ransim/types"
+)
+
+const (
+	// e2apPPID is the SCTP payload protocol identifier of E2AP, i.e. E2-CP
+	e2apPPID = 70
+
+	pcapMagic = 0xa1b2c3d4
+	// pcapLinkTypeRaw is the pcap link type of raw IPv4 packets
+	pcapLinkTypeRaw = 101
+	pcapSnapLen     = 262144
+
+	ipv4HeaderLen  = 20
+	sctpHeaderLen  = 12
+	sctpDataHeader = 16
+	ipProtoSCTP    = 132
+)
+
+// capturePath returns the path of the pcap file in the directory the E2AP traffic of the node is captured to, named
+// after the node, e.g. e2-5153.pcap; the traffic is not captured if the directory is empty
+func capturePath(dir string, enbID types.EnbID) (string, bool) {
+	if dir == "" {
+		return "", false
+	}
+	return filepath.Join(dir, fmt.Sprintf("e2-%d.pcap", enbID)), true
+}
+
+// endpoint is an SCTP endpoint of a captured association
+type endpoint struct {
+	ip   net.IP
+	port uint16
+}
+
+// endpointOf returns the endpoint of the address, e.g. 10.0.0.1/10.0.0.2:36421, using the first IPv4 address of
+// multi-homed SCTP endpoints; the unspecified address is used if there is no IPv4 address
+func endpointOf(addr net.Addr) endpoint {
+	ep := endpoint{ip: net.IPv4zero.To4()}
+	if addr == nil {
+		return ep
+	}
+	s := addr.String()
+	i := strings.LastIndex(s, ":")
+	if i < 0 {
+		return ep
+	}
+	if port, err := strconv.ParseUint(s[i+1:], 10, 16); err == nil {
+		ep.port = uint16(port)
+	}
+	for _, host := range strings.Split(s[:i], "/") {
+		if ip := net.ParseIP(strings.Trim(host, "[]")).To4(); ip != nil {
+			ep.ip = ip
+			break
+		}
+	}
+	return ep
+}
+
+// pcapWriter writes E2AP PDUs as SCTP DATA chunks of synthetic IPv4 packets in the pcap format, so that the
+// capture can be dissected by Wireshark
+type pcapWriter struct {
+	mu     sync.Mutex
+	w      io.Writer
+	local  endpoint
+	remote endpoint
+	// tsn and ssn are the next transmission and stream sequence numbers of the sent and received chunks
+	tsn [2]uint32
+	ssn [2]uint16
+}
+
+// newPcapWriter creates a writer of the traffic between the given endpoints, writing the pcap file header first
+// unless the capture is appended to an existing one
+func newPcapWriter(w io.Writer, local endpoint, remote endpoint, header bool) (*pcapWriter, error) {
+	if header {
+		buf := make([]byte, 24)
+		binary.LittleEndian.PutUint32(buf[0:], pcapMagic)
+		binary.LittleEndian.PutUint16(buf[4:], 2)
+		binary.LittleEndian.PutUint16(buf[6:], 4)
+		binary.LittleEndian.PutUint32(buf[16:], pcapSnapLen)
+		binary.LittleEndian.PutUint32(buf[20:], pcapLinkTypeRaw)
+		if _, err := w.Write(buf); err != nil {
+			return nil, err
+		}
+	}
+	return &pcapWriter{w: w, local: local, remote: remote}, nil
+}
+
+// write writes the PDU sent to or received from the remote endpoint at the given time
+func (p *pcapWriter) write(pdu []byte, sent bool, now time.Time) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	dir := 0
+	src, dst := p.local, p.remote
+	if !sent {
+		dir = 1
+		src, dst = p.remote, p.local
+	}
+	padded := (len(pdu) + 3) &^ 3
+	packet := make([]byte, ipv4HeaderLen+sctpHeaderLen+sctpDataHeader+padded)
+
+	ip := packet[:ipv4HeaderLen]
+	ip[0] = 0x45
+	binary.BigEndian.PutUint16(ip[2:], uint16(len(packet)))
+	ip[8] = 64
+	ip[9] = ipProtoSCTP
+	copy(ip[12:16], src.ip)
+	copy(ip[16:20], dst.ip)
+	binary.BigEndian.PutUint16(ip[10:], ipChecksum(ip))
+
+	sctp := packet[ipv4HeaderLen:]
+	binary.BigEndian.PutUint16(sctp[0:], src.port)
+	binary.BigEndian.PutUint16(sctp[2:], dst.port)
+	chunk := sctp[sctpHeaderLen:]
+	chunk[0] = 0    // DATA
+	chunk[1] = 0x03 // beginning and end of an unfragmented message
+	binary.BigEndian.PutUint16(chunk[2:], uint16(sctpDataHeader+len(pdu)))
+	binary.BigEndian.PutUint32(chunk[4:], p.tsn[dir])
+	binary.BigEndian.PutUint16(chunk[10:], p.ssn[dir])
+	binary.BigEndian.PutUint32(chunk[12:], e2apPPID)
+	copy(chunk[sctpDataHeader:], pdu)
+	binary.LittleEndian.PutUint32(sctp[8:], crc32.Checksum(sctp, crc32.MakeTable(crc32.Castagnoli)))
+	p.tsn[dir]++
+	p.ssn[dir]++
+
+	record := make([]byte, 16)
+	binary.LittleEndian.PutUint32(record[0:], uint32(now.Unix()))
+	binary.LittleEndian.PutUint32(record[4:], uint32(now.Nanosecond()/1000))
+	binary.LittleEndian.PutUint32(record[8:], uint32(len(packet)))
+	binary.LittleEndian.PutUint32(record[12:], uint32(len(packet)))
+	if _, err := p.w.Write(record); err != nil {
+		return err
+	}
+	_, err := p.w.Write(packet)
+	return err
+}
+
+// ipChecksum returns the checksum of the IPv4 header
+func ipChecksum(header []byte) uint16 {
+	var sum uint32
+	for i := 0; i < len(header); i += 2 {
+		sum += uint32(binary.BigEndian.Uint16(header[i:]))
+	}
+	for sum > 0xffff {
+		sum = sum&0xffff + sum>>16
+	}
+	return ^uint16(sum)
+}
+
+// captureConn is an SCTP connection capturing the PDUs it sends and receives; each read and write carries a
+// single SCTP message, i.e. E2AP PDU
+type captureConn struct {
+	net.Conn
+	capture *pcapWriter
+	file    *os.File
+}
+
+// newCaptureConn wraps the connection of the node so that its traffic is appended to the pcap file at the path
+func newCaptureConn(conn net.Conn, path string) (net.Conn, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return nil, err
+	}
+	capture, err := newPcapWriter(file, endpointOf(conn.LocalAddr()), endpointOf(conn.RemoteAddr()), info.Size() == 0)
+	if err != nil {
+		_ = file.Close()
+		return nil, err
+	}
+	return &captureConn{Conn: conn, capture: capture, file: file}, nil
+}
+
+func (c *captureConn) Read(b []byte) (int, error) {
+	n, err := c.Conn.Read(b)
+	if n > 0 {
+		if captureErr := c.capture.write(b[:n], false, time.Now()); captureErr != nil {
+			log.Warnf("Unable to capture received E2AP PDU: %v", captureErr)
+		}
+	}
+	return n, err
+}
+
+func (c *captureConn) Write(b []byte) (int, error) {
+	n, err := c.Conn.Write(b)
+	if err == nil {
+		if captureErr := c.capture.write(b, true, time.Now()); captureErr != nil {
+			log.Warnf("Unable to capture sent E2AP PDU: %v", captureErr)
+		}
+	}
+	return n, err
+}
+
+func (c *captureConn) Close() error {
+	err := c.Conn.Close()
+	_ = c.file.Close()
+	return err
+}
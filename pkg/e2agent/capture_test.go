@@ -0,0 +1,80 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package e2agent
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testAddr string
+
+func (a testAddr) Network() string { return "sctp" }
+func (a testAddr) String() string  { return string(a) }
+
+func TestEndpoint(t *testing.T) {
+	ep := endpointOf(testAddr("10.0.0.1/10.0.0.2:36421"))
+	assert.Equal(t, net.IPv4(10, 0, 0, 1).To4(), ep.ip)
+	assert.Equal(t, uint16(36421), ep.port)
+	ep = endpointOf(testAddr("[::1]:5000"))
+	assert.Equal(t, net.IPv4zero.To4(), ep.ip)
+	assert.Equal(t, uint16(5000), ep.port)
+}
+
+func TestPcapWriter(t *testing.T) {
+	buf := &bytes.Buffer{}
+	local := endpoint{ip: net.IPv4(10, 0, 0, 1).To4(), port: 40000}
+	remote := endpoint{ip: net.IPv4(10, 0, 0, 2).To4(), port: 36421}
+	capture, err := newPcapWriter(buf, local, remote, true)
+	assert.NoError(t, err)
+	now := time.Unix(1600000000, 123456000)
+	assert.NoError(t, capture.write([]byte{1, 2, 3, 4, 5}, true, now))
+	assert.NoError(t, capture.write([]byte{6, 7, 8, 9}, false, now))
+	assert.NoError(t, capture.write([]byte{10}, true, now))
+
+	data := buf.Bytes()
+	assert.Equal(t, uint32(pcapMagic), binary.LittleEndian.Uint32(data[0:]))
+	assert.Equal(t, uint32(pcapLinkTypeRaw), binary.LittleEndian.Uint32(data[20:]))
+
+	// The sent PDU is padded to a multiple of 4 bytes in a DATA chunk from the local to the remote endpoint
+	record := data[24:]
+	assert.Equal(t, uint32(1600000000), binary.LittleEndian.Uint32(record[0:]))
+	assert.Equal(t, uint32(123456), binary.LittleEndian.Uint32(record[4:]))
+	length := binary.LittleEndian.Uint32(record[8:])
+	assert.Equal(t, uint32(ipv4HeaderLen+sctpHeaderLen+sctpDataHeader+8), length)
+	packet := record[16 : 16+length]
+	assert.Equal(t, uint16(0), ipChecksum(packet[:ipv4HeaderLen]))
+	assert.Equal(t, []byte(local.ip), packet[12:16])
+	assert.Equal(t, []byte(remote.ip), packet[16:20])
+	sctp := packet[ipv4HeaderLen:]
+	assert.Equal(t, uint16(40000), binary.BigEndian.Uint16(sctp[0:]))
+	assert.Equal(t, uint16(36421), binary.BigEndian.Uint16(sctp[2:]))
+	checksum := binary.LittleEndian.Uint32(sctp[8:])
+	verify := append([]byte{}, sctp...)
+	binary.LittleEndian.PutUint32(verify[8:], 0)
+	assert.Equal(t, crc32.Checksum(verify, crc32.MakeTable(crc32.Castagnoli)), checksum)
+	chunk := sctp[sctpHeaderLen:]
+	assert.Equal(t, uint16(sctpDataHeader+5), binary.BigEndian.Uint16(chunk[2:]))
+	assert.Equal(t, uint32(e2apPPID), binary.BigEndian.Uint32(chunk[12:]))
+	assert.Equal(t, []byte{1, 2, 3, 4, 5}, chunk[sctpDataHeader:sctpDataHeader+5])
+
+	// The received PDU flows the other way with its own sequence numbers
+	record = record[16+length:]
+	length = binary.LittleEndian.Uint32(record[8:])
+	packet = record[16 : 16+length]
+	assert.Equal(t, uint16(36421), binary.BigEndian.Uint16(packet[ipv4HeaderLen:]))
+	assert.Equal(t, uint32(0), binary.BigEndian.Uint32(packet[ipv4HeaderLen+sctpHeaderLen+4:]))
+
+	// The next sent PDU carries the next TSN
+	record = record[16+length:]
+	packet = record[16:]
+	assert.Equal(t, uint32(1), binary.BigEndian.Uint32(packet[ipv4HeaderLen+sctpHeaderLen+4:]))
+}
@@ -0,0 +1,168 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package e2agent
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+	"time"
+
+	e2apcommondatatypes "github.com/onosproject/onos-e2t/api/e2ap/v1beta2/e2ap-commondatatypes"
+	e2apies "github.com/onosproject/onos-e2t/api/e2ap/v1beta2/e2ap-ies"
+	e2appducontents "github.com/onosproject/onos-e2t/api/e2ap/v1beta2/e2ap-pdu-contents"
+	e2 "github.com/onosproject/onos-e2t/pkg/protocols/e2ap101"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+)
+
+// ChaosAttribute is the name of the node attribute configuring the intentional protocol misbehavior
+// of the node as a comma-separated list of key=value pairs, e.g. "delay=2s,duplicate=0.1,wrongRequestID=0.05,truncate=0.05"
+const ChaosAttribute = "e2.chaos"
+
+// Chaos describes how an E2 node intentionally misbehaves to validate the robustness of the RIC
+type Chaos struct {
+	// Delay by which subscription responses are delayed
+	Delay time.Duration
+	// Duplicate is the probability of an indication being sent twice
+	Duplicate float64
+	// WrongRequestID is the probability of an indication or subscription response carrying a wrong RIC request ID
+	WrongRequestID float64
+	// Truncate is the probability of the ASN.1 encoded indication message being truncated
+	Truncate float64
+}
+
+// ParseChaos parses the chaos configuration from the specified comma-separated list of key=value pairs
+func ParseChaos(spec string) (Chaos, error) {
+	chaos := Chaos{}
+	for _, pair := range strings.Split(spec, ",") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+		kv := strings.SplitN(pair, "=", 2)
+		if len(kv) != 2 {
+			return chaos, errors.New(errors.Invalid, "malformed chaos parameter %s", pair)
+		}
+		key, value := strings.TrimSpace(kv[0]), strings.TrimSpace(kv[1])
+		var err error
+		switch strings.ToLower(key) {
+		case "delay":
+			chaos.Delay, err = time.ParseDuration(value)
+		case "duplicate":
+			chaos.Duplicate, err = parseProbability(value)
+		case "wrongrequestid":
+			chaos.WrongRequestID, err = parseProbability(value)
+		case "truncate":
+			chaos.Truncate, err = parseProbability(value)
+		default:
+			return chaos, errors.New(errors.Invalid, "unknown chaos parameter %s", key)
+		}
+		if err != nil {
+			return chaos, errors.New(errors.Invalid, "invalid value for chaos parameter %s: %v", key, err)
+		}
+	}
+	return chaos, nil
+}
+
+func parseProbability(value string) (float64, error) {
+	p, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, err
+	}
+	if p < 0 || p > 1 {
+		return 0, fmt.Errorf("probability must be in range [0, 1]")
+	}
+	return p, nil
+}
+
+// chaos returns the current chaos configuration of the node
+func (a *e2Agent) chaos(ctx context.Context) Chaos {
+	if a.metricStore == nil {
+		return Chaos{}
+	}
+	value, ok := a.metricStore.Get(ctx, uint64(a.node.EnbID), ChaosAttribute)
+	if !ok {
+		return Chaos{}
+	}
+	chaos, err := ParseChaos(fmt.Sprintf("%v", value))
+	if err != nil {
+		log.Warn(err)
+	}
+	return chaos
+}
+
+// misbehaveSubscriptionResponse delays the subscription response and garbles its RIC request ID as configured
+func (a *e2Agent) misbehaveSubscriptionResponse(ctx context.Context, response *e2appducontents.RicsubscriptionResponse) *e2appducontents.RicsubscriptionResponse {
+	chaos := a.chaos(ctx)
+	if chaos.Delay > 0 {
+		log.Infof("Delaying subscription response of E2 node %d by %v", a.node.EnbID, chaos.Delay)
+		time.Sleep(chaos.Delay)
+	}
+	if response == nil || response.GetProtocolIes().GetE2ApProtocolIes29() == nil || rand.Float64() >= chaos.WrongRequestID {
+		return response
+	}
+	ies := *response.ProtocolIes
+	ie := *ies.E2ApProtocolIes29
+	ie.Value = wrongRequestID(ie.Value)
+	ies.E2ApProtocolIes29 = &ie
+	garbled := *response
+	garbled.ProtocolIes = &ies
+	return &garbled
+}
+
+// chaosChannel is an E2 channel which misbehaves when sending indications
+type chaosChannel struct {
+	e2.ClientChannel
+	chaos func(ctx context.Context) Chaos
+}
+
+// newChaosChannel wraps the specified channel so that indications are duplicated, garbled or truncated
+// as per the chaos configuration returned by the given function
+func newChaosChannel(channel e2.ClientChannel, chaos func(ctx context.Context) Chaos) e2.ClientChannel {
+	return &chaosChannel{
+		ClientChannel: channel,
+		chaos:         chaos,
+	}
+}
+
+// RICIndication sends the indication, possibly twice, with a wrong RIC request ID or a truncated message
+func (c *chaosChannel) RICIndication(ctx context.Context, request *e2appducontents.Ricindication) error {
+	chaos := c.chaos(ctx)
+	if request.GetProtocolIes() != nil && (chaos.WrongRequestID > 0 || chaos.Truncate > 0) {
+		ies := *request.ProtocolIes
+		if ies.E2ApProtocolIes29 != nil && rand.Float64() < chaos.WrongRequestID {
+			ie := *ies.E2ApProtocolIes29
+			ie.Value = wrongRequestID(ie.Value)
+			ies.E2ApProtocolIes29 = &ie
+		}
+		if ies.E2ApProtocolIes26 != nil && ies.E2ApProtocolIes26.Value != nil && rand.Float64() < chaos.Truncate {
+			ie := *ies.E2ApProtocolIes26
+			message := ie.Value.Value
+			ie.Value = &e2apcommondatatypes.RicindicationMessage{Value: message[:len(message)/2]}
+			ies.E2ApProtocolIes26 = &ie
+		}
+		garbled := *request
+		garbled.ProtocolIes = &ies
+		request = &garbled
+	}
+	if err := c.ClientChannel.RICIndication(ctx, request); err != nil {
+		return err
+	}
+	if rand.Float64() < chaos.Duplicate {
+		return c.ClientChannel.RICIndication(ctx, request)
+	}
+	return nil
+}
+
+func wrongRequestID(requestID *e2apies.RicrequestId) *e2apies.RicrequestId {
+	if requestID == nil {
+		return nil
+	}
+	return &e2apies.RicrequestId{
+		RicRequestorId: requestID.RicRequestorId + 1,
+		RicInstanceId:  requestID.RicInstanceId,
+	}
+}
@@ -0,0 +1,77 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package e2agent
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	e2apcommondatatypes "github.com/onosproject/onos-e2t/api/e2ap/v1beta2/e2ap-commondatatypes"
+	e2apies "github.com/onosproject/onos-e2t/api/e2ap/v1beta2/e2ap-ies"
+	e2appducontents "github.com/onosproject/onos-e2t/api/e2ap/v1beta2/e2ap-pdu-contents"
+	e2 "github.com/onosproject/onos-e2t/pkg/protocols/e2ap101"
+	"github.com/stretchr/testify/assert"
+)
+
+type testChannel struct {
+	e2.ClientChannel
+	indications []*e2appducontents.Ricindication
+}
+
+func (c *testChannel) RICIndication(ctx context.Context, request *e2appducontents.Ricindication) error {
+	c.indications = append(c.indications, request)
+	return nil
+}
+
+func TestParseChaos(t *testing.T) {
+	chaos, err := ParseChaos("delay=2s, duplicate=0.5,wrongRequestID=1,truncate=0")
+	assert.NoError(t, err)
+	assert.Equal(t, 2*time.Second, chaos.Delay)
+	assert.Equal(t, 0.5, chaos.Duplicate)
+	assert.Equal(t, 1.0, chaos.WrongRequestID)
+	assert.Equal(t, 0.0, chaos.Truncate)
+
+	_, err = ParseChaos("duplicate=2")
+	assert.Error(t, err)
+	_, err = ParseChaos("reorder=0.1")
+	assert.Error(t, err)
+	_, err = ParseChaos("delay")
+	assert.Error(t, err)
+}
+
+func TestChaosChannel(t *testing.T) {
+	ctx := context.Background()
+	indication := &e2appducontents.Ricindication{
+		ProtocolIes: &e2appducontents.RicindicationIes{
+			E2ApProtocolIes29: &e2appducontents.RicindicationIes_RicindicationIes29{
+				Value: &e2apies.RicrequestId{RicRequestorId: 1, RicInstanceId: 2},
+			},
+			E2ApProtocolIes26: &e2appducontents.RicindicationIes_RicindicationIes26{
+				Value: &e2apcommondatatypes.RicindicationMessage{Value: []byte{1, 2, 3, 4}},
+			},
+		},
+	}
+
+	channel := &testChannel{}
+	chaos := Chaos{}
+	chaosChannel := newChaosChannel(channel, func(ctx context.Context) Chaos {
+		return chaos
+	})
+	assert.NoError(t, chaosChannel.RICIndication(ctx, indication))
+	assert.Len(t, channel.indications, 1)
+	assert.Equal(t, indication, channel.indications[0])
+
+	chaos = Chaos{Duplicate: 1, WrongRequestID: 1, Truncate: 1}
+	assert.NoError(t, chaosChannel.RICIndication(ctx, indication))
+	assert.Len(t, channel.indications, 3)
+	garbled := channel.indications[2]
+	assert.Equal(t, int32(2), garbled.ProtocolIes.E2ApProtocolIes29.Value.RicRequestorId)
+	assert.Equal(t, []byte{1, 2}, garbled.ProtocolIes.E2ApProtocolIes26.Value.Value)
+
+	// The original indication must be left untouched
+	assert.Equal(t, int32(1), indication.ProtocolIes.E2ApProtocolIes29.Value.RicRequestorId)
+	assert.Len(t, indication.ProtocolIes.E2ApProtocolIes26.Value.Value, 4)
+}
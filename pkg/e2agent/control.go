@@ -0,0 +1,148 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package e2agent
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"strings"
+	"time"
+
+	e2apies "github.com/onosproject/onos-e2t/api/e2ap/v1beta2/e2ap-ies"
+	e2appducontents "github.com/onosproject/onos-e2t/api/e2ap/v1beta2/e2ap-pdu-contents"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	controlutils "github.com/onosproject/ran-simulator/pkg/utils/e2ap/control"
+)
+
+const (
+	// ControlLatencyAttribute is the name of the node attribute configuring the execution latency and the success
+	// probability of the control requests received by the node as a comma-separated list of key=value pairs, e.g.
+	// "delay=50ms,jitter=20ms,success=0.9"
+	ControlLatencyAttribute = "e2.controlLatency"
+
+	// ControlsExecuted is the name of the node metric counting the control requests executed by the node
+	ControlsExecuted = "E2.ControlsExecuted"
+	// ControlsFailed is the name of the node metric counting the control requests whose execution failed
+	ControlsFailed = "E2.ControlsFailed"
+)
+
+// ControlLatency describes how long an E2 node takes to execute control requests and how likely it succeeds
+type ControlLatency struct {
+	// Delay is the time the node takes to process a control request before acting and acknowledging it
+	Delay time.Duration
+	// Jitter is the maximum deviation of the processing time from the delay, uniformly distributed
+	Jitter time.Duration
+	// Success is the probability of the execution of a control request succeeding
+	Success float64
+}
+
+// ParseControlLatency parses the control latency configuration from the specified comma-separated list of key=value
+// pairs; control requests succeed unless a success probability is specified
+func ParseControlLatency(spec string) (ControlLatency, error) {
+	latency := ControlLatency{Success: 1}
+	for _, pair := range strings.Split(spec, ",") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+		kv := strings.SplitN(pair, "=", 2)
+		if len(kv) != 2 {
+			return latency, errors.New(errors.Invalid, "malformed control latency parameter %s", pair)
+		}
+		key, value := strings.TrimSpace(kv[0]), strings.TrimSpace(kv[1])
+		var err error
+		switch strings.ToLower(key) {
+		case "delay":
+			latency.Delay, err = time.ParseDuration(value)
+		case "jitter":
+			latency.Jitter, err = time.ParseDuration(value)
+		case "success":
+			latency.Success, err = parseProbability(value)
+		default:
+			return latency, errors.New(errors.Invalid, "unknown control latency parameter %s", key)
+		}
+		if err != nil {
+			return latency, errors.New(errors.Invalid, "invalid value for control latency parameter %s: %v", key, err)
+		}
+	}
+	if latency.Delay < 0 || latency.Jitter < 0 {
+		return latency, errors.New(errors.Invalid, "control latency delay and jitter must not be negative")
+	}
+	return latency, nil
+}
+
+// processingTime draws the time taken to process a control request
+func (l ControlLatency) processingTime() time.Duration {
+	delay := l.Delay
+	if l.Jitter > 0 {
+		delay += time.Duration((rand.Float64()*2 - 1) * float64(l.Jitter))
+	}
+	if delay < 0 {
+		return 0
+	}
+	return delay
+}
+
+// controlLatency returns the current control latency configuration of the node
+func (a *e2Agent) controlLatency(ctx context.Context) ControlLatency {
+	if a.metricStore == nil {
+		return ControlLatency{Success: 1}
+	}
+	value, ok := a.metricStore.Get(ctx, uint64(a.node.EnbID), ControlLatencyAttribute)
+	if !ok {
+		return ControlLatency{Success: 1}
+	}
+	latency, err := ParseControlLatency(fmt.Sprintf("%v", value))
+	if err != nil {
+		log.Warn(err)
+		return ControlLatency{Success: 1}
+	}
+	return latency
+}
+
+// processControl waits for the processing time of a control request and returns false if its execution fails, in
+// which case the request must not be acted upon
+func (a *e2Agent) processControl(ctx context.Context) (bool, error) {
+	latency := a.controlLatency(ctx)
+	if delay := latency.processingTime(); delay > 0 {
+		log.Debugf("E2 node %d processing control request for %v", a.node.EnbID, delay)
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return false, ctx.Err()
+		}
+	}
+	if latency.Success < 1 && rand.Float64() >= latency.Success {
+		log.Infof("E2 node %d failed to execute control request", a.node.EnbID)
+		a.countControl(ctx, ControlsFailed)
+		return false, nil
+	}
+	a.countControl(ctx, ControlsExecuted)
+	return true, nil
+}
+
+// executionFailure answers a control request whose execution failed with a control failure
+func (a *e2Agent) executionFailure(request *e2appducontents.RiccontrolRequest) (*e2appducontents.RiccontrolAcknowledge, *e2appducontents.RiccontrolFailure, error) {
+	failure, err := controlutils.NewControl(
+		controlutils.WithRanFuncID(controlutils.GetRanFunctionID(request)),
+		controlutils.WithRequestID(controlutils.GetRequesterID(request)),
+		controlutils.WithRicInstanceID(controlutils.GetRicInstanceID(request)),
+		controlutils.WithCause(e2apies.Cause{
+			Cause: &e2apies.Cause_Misc{
+				Misc: e2apies.CauseMisc_CAUSE_MISC_UNSPECIFIED,
+			},
+		})).BuildControlFailure()
+	if err != nil {
+		return nil, nil, err
+	}
+	return nil, failure, nil
+}
+
+func (a *e2Agent) countControl(ctx context.Context, name string) {
+	if a.metricStore == nil {
+		return
+	}
+	_, _ = a.metricStore.Add(ctx, uint64(a.node.EnbID), name, 1)
+}
@@ -0,0 +1,81 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package e2agent
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/onosproject/ran-simulator/pkg/model"
+	"github.com/onosproject/ran-simulator/pkg/store/metrics"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseControlLatency(t *testing.T) {
+	latency, err := ParseControlLatency("delay=50ms, jitter=10ms,success=0.9")
+	assert.NoError(t, err)
+	assert.Equal(t, ControlLatency{Delay: 50 * time.Millisecond, Jitter: 10 * time.Millisecond, Success: 0.9}, latency)
+
+	latency, err = ParseControlLatency("delay=1s")
+	assert.NoError(t, err)
+	assert.Equal(t, 1.0, latency.Success)
+
+	_, err = ParseControlLatency("success=1.5")
+	assert.Error(t, err)
+	_, err = ParseControlLatency("delay=-1s")
+	assert.Error(t, err)
+	_, err = ParseControlLatency("timeout=1s")
+	assert.Error(t, err)
+
+	for i := 0; i < 100; i++ {
+		delay := latency.processingTime()
+		assert.Equal(t, time.Second, delay)
+	}
+	latency.Jitter = 100 * time.Millisecond
+	for i := 0; i < 100; i++ {
+		delay := latency.processingTime()
+		assert.True(t, delay >= 900*time.Millisecond && delay <= 1100*time.Millisecond)
+	}
+}
+
+func TestProcessControl(t *testing.T) {
+	ctx := context.Background()
+	metricStore := metrics.NewMetricsStore()
+	agent := &e2Agent{
+		node:        model.Node{EnbID: 144470},
+		metricStore: metricStore,
+	}
+
+	executed, err := agent.processControl(ctx)
+	assert.NoError(t, err)
+	assert.True(t, executed)
+
+	// The node takes its processing time before executing the request
+	assert.NoError(t, metricStore.Set(ctx, 144470, ControlLatencyAttribute, "delay=20ms"))
+	start := time.Now()
+	executed, err = agent.processControl(ctx)
+	assert.NoError(t, err)
+	assert.True(t, executed)
+	assert.True(t, time.Since(start) >= 20*time.Millisecond)
+
+	assert.NoError(t, metricStore.Set(ctx, 144470, ControlLatencyAttribute, "success=0"))
+	executed, err = agent.processControl(ctx)
+	assert.NoError(t, err)
+	assert.False(t, executed)
+
+	count, _ := metricStore.Get(ctx, 144470, ControlsExecuted)
+	assert.Equal(t, uint64(2), count)
+	count, _ = metricStore.Get(ctx, 144470, ControlsFailed)
+	assert.Equal(t, uint64(1), count)
+
+	// Requests abandoned by the RIC are not executed
+	assert.NoError(t, metricStore.Set(ctx, 144470, ControlLatencyAttribute, "delay=1h"))
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	executed, err = agent.processControl(cancelled)
+	assert.Error(t, err)
+	assert.False(t, executed)
+}
@@ -0,0 +1,46 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package e2agent
+
+import (
+	"context"
+	"net"
+	"sync"
+
+	"github.com/onosproject/onos-api/go/onos/ransim/types"
+	"github.com/onosproject/onos-e2t/pkg/protocols/sctp"
+)
+
+// Dialer connects the E2 node with the given ID to the controller at the given address
+type Dialer func(ctx context.Context, enbID types.EnbID, address string) (net.Conn, error)
+
+var (
+	dialerMu sync.RWMutex
+	dialer   Dialer = dialSCTP
+)
+
+// dialSCTP connects the E2 node to the controller over SCTP
+func dialSCTP(ctx context.Context, enbID types.EnbID, address string) (net.Conn, error) {
+	return sctp.Dial(ctx, address)
+}
+
+// SetDialer sets the dialer the nodes connect to their controllers with, e.g. to connect them to an in-process E2T in
+// tests; the nodes connect over SCTP if the dialer is nil
+func SetDialer(d Dialer) {
+	dialerMu.Lock()
+	defer dialerMu.Unlock()
+	if d == nil {
+		d = dialSCTP
+	}
+	dialer = d
+}
+
+// dial connects the E2 node to the controller at the given address with the configured dialer
+func dial(ctx context.Context, enbID types.EnbID, address string) (net.Conn, error) {
+	dialerMu.RLock()
+	d := dialer
+	dialerMu.RUnlock()
+	return d(ctx, enbID, address)
+}
@@ -0,0 +1,61 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package e2agent
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/onosproject/onos-e2t/api/e2ap/v1beta2"
+	e2apies "github.com/onosproject/onos-e2t/api/e2ap/v1beta2/e2ap-ies"
+	"github.com/onosproject/ran-simulator/pkg/utils/e2ap/indicationerror"
+)
+
+// ForceFailureAttribute is the name of the node attribute listing the comma-separated E2AP procedures,
+// i.e. "subscription", "subscriptionDelete" and "control", the node fails on purpose for negative testing
+const ForceFailureAttribute = "e2.forceFailure"
+
+// E2AP procedures whose failure can be forced
+const (
+	subscriptionProcedure       = "subscription"
+	subscriptionDeleteProcedure = "subscriptionDelete"
+	controlProcedure            = "control"
+)
+
+// isFailureForced returns true if the node has been configured to fail the given procedure
+func (a *e2Agent) isFailureForced(ctx context.Context, procedure string) bool {
+	if a.metricStore == nil {
+		return false
+	}
+	value, ok := a.metricStore.Get(ctx, uint64(a.node.EnbID), ForceFailureAttribute)
+	if !ok {
+		return false
+	}
+	for _, p := range strings.Split(fmt.Sprintf("%v", value), ",") {
+		if strings.EqualFold(strings.TrimSpace(p), procedure) {
+			log.Infof("Forcing failure of %s procedure for E2 node %d", procedure, a.node.EnbID)
+			return true
+		}
+	}
+	return false
+}
+
+// reportErrorIndication builds an Error Indication for a request the node is unable to process.
+// The E2AP v1.01 client channel does not support initiating the Error Indication procedure,
+// hence the message is only logged; the request itself is answered with a failure carrying the same cause.
+func (a *e2Agent) reportErrorIndication(procedureCode v1beta2.ProcedureCodeT, reqID int32, ricInstanceID int32, ranFuncID int32, cause *e2apies.Cause) {
+	errorIndication, err := indicationerror.NewErrorIndication(
+		indicationerror.WithRequestID(reqID),
+		indicationerror.WithRicInstanceID(ricInstanceID),
+		indicationerror.WithRanFuncID(ranFuncID),
+		indicationerror.WithFailureProcCode(int32(procedureCode)),
+		indicationerror.WithCause(*cause)).Build()
+	if err != nil {
+		log.Warn(err)
+		return
+	}
+	log.Warnf("E2 node %d error indication: %v", a.node.EnbID, errorIndication)
+}
@@ -0,0 +1,236 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package e2agent
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+
+	e2apcommondatatypes "github.com/onosproject/onos-e2t/api/e2ap/v1beta2/e2ap-commondatatypes"
+	e2appducontents "github.com/onosproject/onos-e2t/api/e2ap/v1beta2/e2ap-pdu-contents"
+	e2 "github.com/onosproject/onos-e2t/pkg/protocols/e2ap101"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/onosproject/onos-lib-go/pkg/logging"
+	"github.com/onosproject/ran-simulator/pkg/journal"
+	"github.com/onosproject/ran-simulator/pkg/servicemodel/registry"
+	"github.com/onosproject/ran-simulator/pkg/store/subscriptions"
+)
+
+const (
+	// FuzzAttribute is the name of the node attribute configuring the fuzzing of the ASN.1 encoded E2SM payloads of
+	// the indications of the node as a comma-separated list of key=value pairs, e.g. "percent=5,mutations=bitflip|length"
+	FuzzAttribute = "e2.fuzz"
+
+	// IndicationsFuzzed is the name of the node metric counting the indications whose payload was corrupted
+	IndicationsFuzzed = "E2.IndicationsFuzzed"
+)
+
+var fuzzLog = logging.GetLogger("e2agent", "fuzz")
+
+// Mutation is a corruption of an ASN.1 encoded payload
+type Mutation string
+
+const (
+	// BitFlip flips a random bit of the payload
+	BitFlip Mutation = "bitflip"
+	// Truncation cuts the payload at a random offset
+	Truncation Mutation = "truncate"
+	// LengthCorruption overwrites the payload at a random offset with an APER length determinant of 16383 octets,
+	// i.e. claiming far more content than the payload carries
+	LengthCorruption Mutation = "length"
+)
+
+var mutations = []Mutation{BitFlip, Truncation, LengthCorruption}
+
+// Fuzz describes how the indication payloads of an E2 node are corrupted to test the robustness of the RIC decoders
+type Fuzz struct {
+	// Percent is the percentage of indications having their header or message corrupted
+	Percent float64
+	// Mutations are the mutations applied, one picked at random for each corrupted payload; all if not specified
+	Mutations []Mutation
+}
+
+// ParseFuzz parses the fuzzing configuration from the specified comma-separated list of key=value pairs
+func ParseFuzz(spec string) (Fuzz, error) {
+	fuzz := Fuzz{}
+	for _, pair := range strings.Split(spec, ",") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+		kv := strings.SplitN(pair, "=", 2)
+		if len(kv) != 2 {
+			return fuzz, errors.New(errors.Invalid, "malformed fuzz parameter %s", pair)
+		}
+		key, value := strings.TrimSpace(kv[0]), strings.TrimSpace(kv[1])
+		switch strings.ToLower(key) {
+		case "percent":
+			p, err := strconv.ParseFloat(value, 64)
+			if err != nil || p < 0 || p > 100 {
+				return fuzz, errors.New(errors.Invalid, "fuzz percent must be in range [0, 100]: %s", value)
+			}
+			fuzz.Percent = p
+		case "mutations":
+			for _, name := range strings.Split(value, "|") {
+				mutation, err := parseMutation(name)
+				if err != nil {
+					return fuzz, err
+				}
+				fuzz.Mutations = append(fuzz.Mutations, mutation)
+			}
+		default:
+			return fuzz, errors.New(errors.Invalid, "unknown fuzz parameter %s", key)
+		}
+	}
+	return fuzz, nil
+}
+
+func parseMutation(name string) (Mutation, error) {
+	for _, mutation := range mutations {
+		if strings.EqualFold(strings.TrimSpace(name), string(mutation)) {
+			return mutation, nil
+		}
+	}
+	return "", errors.New(errors.Invalid, "unknown fuzz mutation %s", name)
+}
+
+// Corruption is the ground truth of a corrupted indication payload
+type Corruption struct {
+	Mutation Mutation
+	Payload  registry.PayloadKind
+	// Offset is the bit offset of the flipped bit, or the byte offset of the cut or the corrupted length determinant
+	Offset         int
+	OriginalLength int
+	Length         int
+}
+
+// mutate returns a corrupted copy of the payload
+func mutate(payload []byte, mutation Mutation) ([]byte, int) {
+	if len(payload) == 0 {
+		return payload, 0
+	}
+	offset := rand.Intn(len(payload))
+	switch mutation {
+	case Truncation:
+		return append([]byte{}, payload[:offset]...), offset
+	case LengthCorruption:
+		mutated := append([]byte{}, payload...)
+		mutated[offset] = 0xbf
+		if offset+1 < len(mutated) {
+			mutated[offset+1] = 0xff
+		}
+		return mutated, offset
+	default:
+		mutated := append([]byte{}, payload...)
+		bit := rand.Intn(8)
+		mutated[offset] ^= 1 << uint(bit)
+		return mutated, offset*8 + bit
+	}
+}
+
+// fuzz returns the current fuzzing configuration of the node
+func (a *e2Agent) fuzz(ctx context.Context) Fuzz {
+	if a.metricStore == nil {
+		return Fuzz{}
+	}
+	value, ok := a.metricStore.Get(ctx, uint64(a.node.EnbID), FuzzAttribute)
+	if !ok {
+		return Fuzz{}
+	}
+	fuzz, err := ParseFuzz(fmt.Sprintf("%v", value))
+	if err != nil {
+		log.Warn(err)
+	}
+	return fuzz
+}
+
+// recordCorruption logs and journals the corruption of an indication of the subscription, and counts it
+func (a *e2Agent) recordCorruption(ctx context.Context, subID subscriptions.ID, request *e2appducontents.Ricindication, corruption Corruption) {
+	details := map[string]interface{}{
+		"subscriptionID": subID,
+		"payload":        corruption.Payload,
+		"mutation":       corruption.Mutation,
+		"offset":         corruption.Offset,
+		"originalLength": corruption.OriginalLength,
+		"length":         corruption.Length,
+	}
+	if sn := request.GetProtocolIes().GetE2ApProtocolIes27(); sn != nil {
+		details["indicationSN"] = sn.GetValue().GetValue()
+	}
+	fields := []interface{}{"node", a.node.EnbID}
+	for _, key := range []string{"subscriptionID", "indicationSN", "payload", "mutation", "offset", "originalLength", "length"} {
+		if value, ok := details[key]; ok {
+			fields = append(fields, key, value)
+		}
+	}
+	fuzzLog.Infow(fmt.Sprintf("E2 node %d corrupted %s of indication", a.node.EnbID, corruption.Payload), fields...)
+	journal.Record(journal.IndicationFuzzed, uint64(a.node.EnbID), details)
+
+	_, _ = a.metricStore.Add(ctx, uint64(a.node.EnbID), IndicationsFuzzed, 1)
+}
+
+// fuzzChannel is an E2 channel which corrupts the indication header or message of a share of the indications of
+// a subscription
+type fuzzChannel struct {
+	e2.ClientChannel
+	subID  subscriptions.ID
+	fuzz   func(ctx context.Context) Fuzz
+	record func(ctx context.Context, subID subscriptions.ID, request *e2appducontents.Ricindication, corruption Corruption)
+}
+
+// newFuzzChannel wraps the specified channel so that the indications of the given subscription are corrupted as per
+// the fuzzing configuration returned by the given function, and each corruption reported to the other function
+func newFuzzChannel(channel e2.ClientChannel, subID subscriptions.ID, fuzz func(ctx context.Context) Fuzz,
+	record func(ctx context.Context, subID subscriptions.ID, request *e2appducontents.Ricindication, corruption Corruption)) e2.ClientChannel {
+	return &fuzzChannel{
+		ClientChannel: channel,
+		subID:         subID,
+		fuzz:          fuzz,
+		record:        record,
+	}
+}
+
+// RICIndication sends the indication, with a corrupted header or message if picked for fuzzing
+func (c *fuzzChannel) RICIndication(ctx context.Context, request *e2appducontents.Ricindication) error {
+	fuzz := c.fuzz(ctx)
+	if fuzz.Percent <= 0 || request.GetProtocolIes() == nil || rand.Float64()*100 >= fuzz.Percent {
+		return c.ClientChannel.RICIndication(ctx, request)
+	}
+	candidates := fuzz.Mutations
+	if len(candidates) == 0 {
+		candidates = mutations
+	}
+	corruption := Corruption{Mutation: candidates[rand.Intn(len(candidates))]}
+
+	ies := *request.ProtocolIes
+	if ies.GetE2ApProtocolIes25().GetValue() != nil && (ies.GetE2ApProtocolIes26().GetValue() == nil || rand.Intn(2) == 0) {
+		ie := *ies.E2ApProtocolIes25
+		header := ie.Value.Value
+		corruption.Payload, corruption.OriginalLength = registry.IndicationHeaderPayload, len(header)
+		header, corruption.Offset = mutate(header, corruption.Mutation)
+		ie.Value = &e2apcommondatatypes.RicindicationHeader{Value: header}
+		ies.E2ApProtocolIes25 = &ie
+		corruption.Length = len(header)
+	} else if ies.GetE2ApProtocolIes26().GetValue() != nil {
+		ie := *ies.E2ApProtocolIes26
+		message := ie.Value.Value
+		corruption.Payload, corruption.OriginalLength = registry.IndicationMessagePayload, len(message)
+		message, corruption.Offset = mutate(message, corruption.Mutation)
+		ie.Value = &e2apcommondatatypes.RicindicationMessage{Value: message}
+		ies.E2ApProtocolIes26 = &ie
+		corruption.Length = len(message)
+	} else {
+		return c.ClientChannel.RICIndication(ctx, request)
+	}
+	corrupted := *request
+	corrupted.ProtocolIes = &ies
+	if err := c.ClientChannel.RICIndication(ctx, &corrupted); err != nil {
+		return err
+	}
+	c.record(ctx, c.subID, &corrupted, corruption)
+	return nil
+}
@@ -0,0 +1,90 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package e2agent
+
+import (
+	"context"
+	"testing"
+
+	e2apcommondatatypes "github.com/onosproject/onos-e2t/api/e2ap/v1beta2/e2ap-commondatatypes"
+	e2appducontents "github.com/onosproject/onos-e2t/api/e2ap/v1beta2/e2ap-pdu-contents"
+	"github.com/onosproject/ran-simulator/pkg/journal"
+	"github.com/onosproject/ran-simulator/pkg/model"
+	"github.com/onosproject/ran-simulator/pkg/servicemodel/registry"
+	"github.com/onosproject/ran-simulator/pkg/store/metrics"
+	"github.com/onosproject/ran-simulator/pkg/store/subscriptions"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseFuzz(t *testing.T) {
+	fuzz, err := ParseFuzz("percent=5, mutations=bitflip|Length")
+	assert.NoError(t, err)
+	assert.Equal(t, 5.0, fuzz.Percent)
+	assert.Equal(t, []Mutation{BitFlip, LengthCorruption}, fuzz.Mutations)
+
+	_, err = ParseFuzz("percent=101")
+	assert.Error(t, err)
+	_, err = ParseFuzz("mutations=shuffle")
+	assert.Error(t, err)
+	_, err = ParseFuzz("seed=1")
+	assert.Error(t, err)
+}
+
+func TestMutate(t *testing.T) {
+	payload := []byte{0x10, 0x20, 0x30, 0x40}
+
+	flipped, bit := mutate(payload, BitFlip)
+	assert.Len(t, flipped, 4)
+	assert.Equal(t, payload[bit/8]^1<<uint(bit%8), flipped[bit/8])
+
+	truncated, offset := mutate(payload, Truncation)
+	assert.Len(t, truncated, offset)
+	assert.Equal(t, payload[:offset], truncated)
+
+	corrupted, offset := mutate(payload, LengthCorruption)
+	assert.Len(t, corrupted, 4)
+	assert.Equal(t, byte(0xbf), corrupted[offset])
+
+	// The original payload must be left untouched
+	assert.Equal(t, []byte{0x10, 0x20, 0x30, 0x40}, payload)
+}
+
+func TestFuzzChannel(t *testing.T) {
+	ctx := context.Background()
+	metricStore := metrics.NewMetricsStore()
+	agent := &e2Agent{
+		node:        model.Node{EnbID: 144470},
+		metricStore: metricStore,
+	}
+	indication := &e2appducontents.Ricindication{
+		ProtocolIes: &e2appducontents.RicindicationIes{
+			E2ApProtocolIes26: &e2appducontents.RicindicationIes_RicindicationIes26{
+				Value: &e2apcommondatatypes.RicindicationMessage{Value: []byte{1, 2, 3, 4}},
+			},
+		},
+	}
+
+	channel := &testChannel{}
+	fuzzChannel := newFuzzChannel(channel, "1-2-3", agent.fuzz, agent.recordCorruption)
+	assert.NoError(t, fuzzChannel.RICIndication(ctx, indication))
+	assert.Equal(t, indication, channel.indications[0])
+
+	assert.NoError(t, metricStore.Set(ctx, 144470, FuzzAttribute, "percent=100,mutations=length"))
+	assert.NoError(t, fuzzChannel.RICIndication(ctx, indication))
+	assert.Len(t, channel.indications, 2)
+	corrupted := channel.indications[1].ProtocolIes.E2ApProtocolIes26.Value.Value
+	assert.Contains(t, corrupted, byte(0xbf))
+	assert.Equal(t, []byte{1, 2, 3, 4}, indication.ProtocolIes.E2ApProtocolIes26.Value.Value)
+
+	count, ok := metricStore.Get(ctx, 144470, IndicationsFuzzed)
+	assert.True(t, ok)
+	assert.Equal(t, uint64(1), count)
+	entries := journal.Default().Query(journal.Filter{EntityID: 144470, Kinds: []journal.Kind{journal.IndicationFuzzed}})
+	assert.NotEmpty(t, entries)
+	details := entries[len(entries)-1].Details
+	assert.Equal(t, subscriptions.ID("1-2-3"), details["subscriptionID"])
+	assert.Equal(t, registry.IndicationMessagePayload, details["payload"])
+	assert.Equal(t, LengthCorruption, details["mutation"])
+}
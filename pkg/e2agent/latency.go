@@ -0,0 +1,182 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package e2agent
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/onosproject/onos-e2t/api/e2ap/v1beta2"
+	e2apcommondatatypes "github.com/onosproject/onos-e2t/api/e2ap/v1beta2/e2ap-commondatatypes"
+	e2apies "github.com/onosproject/onos-e2t/api/e2ap/v1beta2/e2ap-ies"
+	e2appducontents "github.com/onosproject/onos-e2t/api/e2ap/v1beta2/e2ap-pdu-contents"
+	e2 "github.com/onosproject/onos-e2t/pkg/protocols/e2ap101"
+	"github.com/onosproject/ran-simulator/pkg/store/metrics"
+	"github.com/onosproject/ran-simulator/pkg/store/subscriptions"
+)
+
+const (
+	// TimestampsAttribute is the name of the node attribute enabling the latency annotation of the indications of
+	// the node; if set, each indication carries its send time in nanoseconds since the epoch as 8-byte big-endian
+	// RIC call process ID and a sequence number counting the indications of its subscription as RIC indication SN
+	TimestampsAttribute = "e2.timestamps"
+
+	// IndicationLatency is the prefix of the node metrics reporting the quantiles of the time taken to send the
+	// indications of each subscription in microseconds, e.g. E2.IndicationLatency.p99.<subscription ID>
+	IndicationLatency = "E2.IndicationLatency"
+
+	// latencyWindow is the number of most recent indications the latency quantiles are computed over
+	latencyWindow = 1000
+	// latencyPublishInterval is the minimum interval between updates of the latency metrics of a subscription
+	latencyPublishInterval = time.Second
+	// maxIndicationSN is the largest RIC indication SN; the sequence number wraps around after it
+	maxIndicationSN = 65535
+)
+
+var latencyQuantiles = []float64{0.5, 0.9, 0.99}
+
+// latencyMetric returns the name of the metric of the given latency quantile of the subscription
+func latencyMetric(subID subscriptions.ID, quantile float64) string {
+	return fmt.Sprintf("%s.p%d.%s", IndicationLatency, int(math.Round(quantile*100)), subID)
+}
+
+// latencyTracker keeps the send latencies of the most recent indications of a subscription
+type latencyTracker struct {
+	samples   []time.Duration
+	next      int
+	published time.Time
+}
+
+// add adds the latency sample and returns true if the quantiles are due to be published at the given time
+func (t *latencyTracker) add(latency time.Duration, now time.Time) bool {
+	if len(t.samples) < latencyWindow {
+		t.samples = append(t.samples, latency)
+	} else {
+		t.samples[t.next] = latency
+		t.next = (t.next + 1) % latencyWindow
+	}
+	if now.Sub(t.published) < latencyPublishInterval {
+		return false
+	}
+	t.published = now
+	return true
+}
+
+// quantile returns the specified quantile of the latency samples
+func (t *latencyTracker) quantile(q float64) time.Duration {
+	if len(t.samples) == 0 {
+		return 0
+	}
+	sorted := make([]time.Duration, len(t.samples))
+	copy(sorted, t.samples)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i] < sorted[j]
+	})
+	return sorted[int(q*float64(len(sorted)-1))]
+}
+
+// latencyChannel is an E2 channel which annotates the indications of a subscription with their send time
+// and sequence number, and tracks the time taken to send them, if enabled
+type latencyChannel struct {
+	e2.ClientChannel
+	subID   subscriptions.ID
+	enabled func(ctx context.Context) bool
+	publish func(ctx context.Context, subID subscriptions.ID, quantiles map[float64]time.Duration)
+	mu      sync.Mutex
+	sn      int32
+	tracker latencyTracker
+}
+
+// newLatencyChannel wraps the specified channel so that indications of the given subscription are annotated
+// and their send latency quantiles published using the given function whenever enabled by the other function
+func newLatencyChannel(channel e2.ClientChannel, subID subscriptions.ID, enabled func(ctx context.Context) bool,
+	publish func(ctx context.Context, subID subscriptions.ID, quantiles map[float64]time.Duration)) e2.ClientChannel {
+	return &latencyChannel{
+		ClientChannel: channel,
+		subID:         subID,
+		enabled:       enabled,
+		publish:       publish,
+	}
+}
+
+// RICIndication sends the indication, annotated with its send time and sequence number if enabled
+func (c *latencyChannel) RICIndication(ctx context.Context, request *e2appducontents.Ricindication) error {
+	if !c.enabled(ctx) || request.GetProtocolIes() == nil {
+		return c.ClientChannel.RICIndication(ctx, request)
+	}
+
+	c.mu.Lock()
+	sn := c.sn
+	c.sn = (c.sn + 1) % (maxIndicationSN + 1)
+	c.mu.Unlock()
+
+	start := time.Now()
+	timestamp := make([]byte, 8)
+	binary.BigEndian.PutUint64(timestamp, uint64(start.UnixNano()))
+	ies := *request.ProtocolIes
+	ies.E2ApProtocolIes27 = &e2appducontents.RicindicationIes_RicindicationIes27{
+		Id:          int32(v1beta2.ProtocolIeIDRicindicationSn),
+		Criticality: int32(e2apcommondatatypes.Criticality_CRITICALITY_REJECT),
+		Value:       &e2apies.RicindicationSn{Value: sn},
+		Presence:    int32(e2apcommondatatypes.Presence_PRESENCE_OPTIONAL),
+	}
+	ies.E2ApProtocolIes20 = &e2appducontents.RicindicationIes_RicindicationIes20{
+		Id:          int32(v1beta2.ProtocolIeIDRiccallProcessID),
+		Criticality: int32(e2apcommondatatypes.Criticality_CRITICALITY_REJECT),
+		Value:       &e2apcommondatatypes.RiccallProcessId{Value: timestamp},
+		Presence:    int32(e2apcommondatatypes.Presence_PRESENCE_OPTIONAL),
+	}
+	annotated := *request
+	annotated.ProtocolIes = &ies
+	err := c.ClientChannel.RICIndication(ctx, &annotated)
+	now := time.Now()
+
+	c.mu.Lock()
+	var quantiles map[float64]time.Duration
+	if c.tracker.add(now.Sub(start), now) {
+		quantiles = make(map[float64]time.Duration, len(latencyQuantiles))
+		for _, q := range latencyQuantiles {
+			quantiles[q] = c.tracker.quantile(q)
+		}
+	}
+	c.mu.Unlock()
+	if quantiles != nil {
+		c.publish(ctx, c.subID, quantiles)
+	}
+	return err
+}
+
+// timestampsEnabled returns true if the indications of the node are annotated with their send time
+func (a *e2Agent) timestampsEnabled(ctx context.Context) bool {
+	if a.metricStore == nil {
+		return false
+	}
+	value, ok := a.metricStore.Get(ctx, uint64(a.node.EnbID), TimestampsAttribute)
+	return ok && metrics.IsSet(value)
+}
+
+// publishIndicationLatency sets the node metrics of the indication send latency quantiles of the subscription
+func (a *e2Agent) publishIndicationLatency(ctx context.Context, subID subscriptions.ID, quantiles map[float64]time.Duration) {
+	for q, latency := range quantiles {
+		_ = a.metricStore.Set(ctx, uint64(a.node.EnbID), latencyMetric(subID, q), float64(latency)/float64(time.Microsecond))
+	}
+}
+
+// clearIndicationLatency removes the latency metrics of the deleted subscription
+func (a *e2Agent) clearIndicationLatency(ctx context.Context, subID subscriptions.ID) {
+	if a.metricStore == nil {
+		return
+	}
+	for _, q := range latencyQuantiles {
+		if _, ok := a.metricStore.Get(ctx, uint64(a.node.EnbID), latencyMetric(subID, q)); ok {
+			_ = a.metricStore.Delete(ctx, uint64(a.node.EnbID), latencyMetric(subID, q))
+		}
+	}
+}
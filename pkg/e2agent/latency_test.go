@@ -0,0 +1,62 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package e2agent
+
+import (
+	"context"
+	"encoding/binary"
+	"testing"
+	"time"
+
+	e2appducontents "github.com/onosproject/onos-e2t/api/e2ap/v1beta2/e2ap-pdu-contents"
+	"github.com/onosproject/ran-simulator/pkg/model"
+	"github.com/onosproject/ran-simulator/pkg/store/metrics"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLatencyTracker(t *testing.T) {
+	tracker := &latencyTracker{}
+	now := time.Now()
+	assert.True(t, tracker.add(time.Millisecond, now))
+	for i := 2; i <= 100; i++ {
+		assert.False(t, tracker.add(time.Duration(i)*time.Millisecond, now))
+	}
+	assert.Equal(t, 50*time.Millisecond, tracker.quantile(0.5))
+	assert.Equal(t, 99*time.Millisecond, tracker.quantile(0.99))
+	assert.True(t, tracker.add(time.Millisecond, now.Add(latencyPublishInterval)))
+}
+
+func TestLatencyChannel(t *testing.T) {
+	ctx := context.Background()
+	metricStore := metrics.NewMetricsStore()
+	agent := &e2Agent{
+		node:        model.Node{EnbID: 144470},
+		metricStore: metricStore,
+	}
+	channel := &testChannel{}
+	latencyChannel := newLatencyChannel(channel, "1-2-3", agent.timestampsEnabled, agent.publishIndicationLatency)
+
+	// Indications are sent as they are unless enabled
+	indication := &e2appducontents.Ricindication{ProtocolIes: &e2appducontents.RicindicationIes{}}
+	assert.NoError(t, latencyChannel.RICIndication(ctx, indication))
+	assert.Equal(t, indication, channel.indications[0])
+
+	assert.NoError(t, metricStore.Set(ctx, 144470, TimestampsAttribute, int32(1)))
+	before := time.Now()
+	assert.NoError(t, latencyChannel.RICIndication(ctx, indication))
+	assert.NoError(t, latencyChannel.RICIndication(ctx, indication))
+	assert.Len(t, channel.indications, 3)
+	for i, annotated := range channel.indications[1:] {
+		assert.Equal(t, int32(i), annotated.GetProtocolIes().GetE2ApProtocolIes27().GetValue().GetValue())
+		sent := int64(binary.BigEndian.Uint64(annotated.GetProtocolIes().GetE2ApProtocolIes20().GetValue().GetValue()))
+		assert.True(t, sent >= before.UnixNano())
+	}
+	_, ok := metricStore.Get(ctx, 144470, "E2.IndicationLatency.p99.1-2-3")
+	assert.True(t, ok)
+
+	agent.clearIndicationLatency(ctx, "1-2-3")
+	_, ok = metricStore.Get(ctx, 144470, "E2.IndicationLatency.p99.1-2-3")
+	assert.False(t, ok)
+}
@@ -0,0 +1,121 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package e2agent
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"sync"
+	"time"
+
+	e2appducontents "github.com/onosproject/onos-e2t/api/e2ap/v1beta2/e2ap-pdu-contents"
+	e2 "github.com/onosproject/onos-e2t/pkg/protocols/e2ap101"
+	"github.com/onosproject/ran-simulator/pkg/store/subscriptions"
+)
+
+const (
+	// MaxIndicationRateAttribute is the name of the node attribute limiting the number of
+	// indications per second the node sends across all its subscriptions
+	MaxIndicationRateAttribute = "e2.maxIndicationRate"
+
+	// MaxSubscriptionIndicationRateAttribute is the name of the node attribute limiting the number of
+	// indications per second the node sends for each of its subscriptions
+	MaxSubscriptionIndicationRateAttribute = "e2.maxSubscriptionIndicationRate"
+
+	// IndicationsDropped is the name of the node metric counting the indications dropped due to pacing
+	IndicationsDropped = "E2.IndicationsDropped"
+)
+
+// tokenBucket paces events to a given rate allowing bursts of up to one second worth of events
+type tokenBucket struct {
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+}
+
+// allow returns true if an event may pass at the given time without exceeding the specified rate per second;
+// a non-positive rate means the events are not paced at all
+func (b *tokenBucket) allow(rate float64, now time.Time) bool {
+	if rate <= 0 {
+		return true
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	burst := math.Max(1, rate)
+	if b.last.IsZero() {
+		b.tokens = burst
+	} else {
+		b.tokens = math.Min(burst, b.tokens+now.Sub(b.last).Seconds()*rate)
+	}
+	b.last = now
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// rateAttribute returns the rate configured by the given node attribute, or zero if there is none
+func (a *e2Agent) rateAttribute(ctx context.Context, name string) float64 {
+	if a.metricStore == nil {
+		return 0
+	}
+	value, ok := a.metricStore.Get(ctx, uint64(a.node.EnbID), name)
+	if !ok {
+		return 0
+	}
+	rate, err := strconv.ParseFloat(fmt.Sprintf("%v", value), 64)
+	if err != nil {
+		log.Warnf("Invalid value %v of attribute %s: %v", value, name, err)
+		return 0
+	}
+	return rate
+}
+
+// allowIndication returns true if the node may send an indication for the subscription with the given bucket
+// now; otherwise the indication is dropped and counted
+func (a *e2Agent) allowIndication(ctx context.Context, subID subscriptions.ID, subBucket *tokenBucket) bool {
+	now := time.Now()
+	if subBucket.allow(a.rateAttribute(ctx, MaxSubscriptionIndicationRateAttribute), now) &&
+		a.indicationBucket.allow(a.rateAttribute(ctx, MaxIndicationRateAttribute), now) {
+		return true
+	}
+	log.Debugf("Dropping indication for subscription %s of E2 node %d due to pacing", subID, a.node.EnbID)
+	a.countDroppedIndication(ctx)
+	return false
+}
+
+func (a *e2Agent) countDroppedIndication(ctx context.Context) {
+	_, _ = a.metricStore.Add(ctx, uint64(a.node.EnbID), IndicationsDropped, 1)
+}
+
+// pacedChannel is an E2 channel which drops the indications of a subscription exceeding the configured rates
+type pacedChannel struct {
+	e2.ClientChannel
+	subID  subscriptions.ID
+	bucket *tokenBucket
+	allow  func(ctx context.Context, subID subscriptions.ID, bucket *tokenBucket) bool
+}
+
+// newPacedChannel wraps the specified channel so that indications of the given subscription
+// are only sent if allowed by the specified function
+func newPacedChannel(channel e2.ClientChannel, subID subscriptions.ID, allow func(ctx context.Context, subID subscriptions.ID, bucket *tokenBucket) bool) e2.ClientChannel {
+	return &pacedChannel{
+		ClientChannel: channel,
+		subID:         subID,
+		bucket:        &tokenBucket{},
+		allow:         allow,
+	}
+}
+
+// RICIndication sends the indication unless it exceeds the configured rates
+func (c *pacedChannel) RICIndication(ctx context.Context, request *e2appducontents.Ricindication) error {
+	if !c.allow(ctx, c.subID, c.bucket) {
+		return nil
+	}
+	return c.ClientChannel.RICIndication(ctx, request)
+}
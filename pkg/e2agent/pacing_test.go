@@ -0,0 +1,59 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package e2agent
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	e2appducontents "github.com/onosproject/onos-e2t/api/e2ap/v1beta2/e2ap-pdu-contents"
+	"github.com/onosproject/ran-simulator/pkg/model"
+	"github.com/onosproject/ran-simulator/pkg/store/metrics"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTokenBucket(t *testing.T) {
+	bucket := &tokenBucket{}
+	now := time.Now()
+	assert.True(t, bucket.allow(0, now))
+
+	// Burst of two indications is allowed, the third one is not
+	assert.True(t, bucket.allow(2, now))
+	assert.True(t, bucket.allow(2, now))
+	assert.False(t, bucket.allow(2, now))
+
+	// Tokens are replenished at the given rate
+	now = now.Add(500 * time.Millisecond)
+	assert.True(t, bucket.allow(2, now))
+	assert.False(t, bucket.allow(2, now))
+
+	// Fractional rates allow a single indication at a time
+	bucket = &tokenBucket{}
+	assert.True(t, bucket.allow(0.5, now))
+	assert.False(t, bucket.allow(0.5, now.Add(time.Second)))
+	assert.True(t, bucket.allow(0.5, now.Add(2*time.Second)))
+}
+
+func TestPacedChannel(t *testing.T) {
+	ctx := context.Background()
+	metricStore := metrics.NewMetricsStore()
+	agent := &e2Agent{
+		node:             model.Node{EnbID: 144470},
+		metricStore:      metricStore,
+		indicationBucket: &tokenBucket{},
+	}
+	assert.NoError(t, metricStore.Set(ctx, 144470, MaxSubscriptionIndicationRateAttribute, 1.0))
+
+	channel := &testChannel{}
+	pacedChannel := newPacedChannel(channel, "1-2-3", agent.allowIndication)
+	for i := 0; i < 3; i++ {
+		assert.NoError(t, pacedChannel.RICIndication(ctx, &e2appducontents.Ricindication{}))
+	}
+	assert.Len(t, channel.indications, 1)
+	dropped, ok := metricStore.Get(ctx, 144470, IndicationsDropped)
+	assert.True(t, ok)
+	assert.Equal(t, uint64(2), dropped)
+}
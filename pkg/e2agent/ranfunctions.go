@@ -0,0 +1,46 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package e2agent
+
+import (
+	"fmt"
+	"sync/atomic"
+
+	"github.com/onosproject/onos-api/go/onos/ransim/types"
+	"github.com/onosproject/ran-simulator/pkg/model"
+)
+
+// UpdateRanFunctions rebuilds the RAN function descriptions of the agent if the cells of the node changed since they
+// were last described, incrementing the revisions of the RAN functions whose description changed. The E2AP v1.01
+// procedures implemented by onos-e2t do not include the RIC Service Update, so a connected agent reconnects to
+// advertise the new revisions by a new E2 setup
+func (a *e2Agent) UpdateRanFunctions(node model.Node) error {
+	a.cellsMu.Lock()
+	defer a.cellsMu.Unlock()
+	if sameCells(a.cells, node.Cells) {
+		return nil
+	}
+	updated, err := a.registry.UpdateDescriptions(node)
+	if err != nil {
+		return err
+	}
+	a.cells = append([]types.ECGI(nil), node.Cells...)
+	if len(updated) > 0 && atomic.LoadInt32(&a.connected) == 1 {
+		a.reconnect(fmt.Sprintf("RAN functions %v changed", updated))
+	}
+	return nil
+}
+
+func sameCells(cells []types.ECGI, other []types.ECGI) bool {
+	if len(cells) != len(other) {
+		return false
+	}
+	for i := range cells {
+		if cells[i] != other[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,55 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package e2agent
+
+import (
+	"context"
+	"sync/atomic"
+
+	e2appducontents "github.com/onosproject/onos-e2t/api/e2ap/v1beta2/e2ap-pdu-contents"
+	e2 "github.com/onosproject/onos-e2t/pkg/protocols/e2ap101"
+)
+
+// Stats are the statistics of an E2 agent
+type Stats struct {
+	// Connected is true if the node completed the E2 setup and has not been stopped since
+	Connected bool
+	// Subscriptions is the number of subscriptions of the node
+	Subscriptions int
+	// IndicationsSent is the number of indications sent by the node since it was created
+	IndicationsSent uint64
+}
+
+// Stats returns the current statistics of the agent
+func (a *e2Agent) Stats() Stats {
+	stats := Stats{
+		Connected:       atomic.LoadInt32(&a.connected) == 1,
+		IndicationsSent: atomic.LoadUint64(&a.indicationsSent),
+	}
+	if count, err := a.subStore.Len(); err == nil {
+		stats.Subscriptions = count
+	}
+	return stats
+}
+
+// countingChannel is an E2 channel counting the indications it sends successfully
+type countingChannel struct {
+	e2.ClientChannel
+	count *uint64
+}
+
+// newCountingChannel wraps the channel so that the indications sent through it are counted by the given counter
+func newCountingChannel(channel e2.ClientChannel, count *uint64) e2.ClientChannel {
+	return &countingChannel{ClientChannel: channel, count: count}
+}
+
+// RICIndication sends and counts the indication
+func (c *countingChannel) RICIndication(ctx context.Context, request *e2appducontents.Ricindication) error {
+	err := c.ClientChannel.RICIndication(ctx, request)
+	if err == nil {
+		atomic.AddUint64(c.count, 1)
+	}
+	return err
+}
@@ -0,0 +1,85 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package e2agent
+
+import (
+	"crypto/tls"
+	"net"
+	"time"
+
+	"github.com/onosproject/onos-lib-go/pkg/certs"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+)
+
+// tlsHandshakeTimeout bounds the TLS handshake of the E2 connections
+const tlsHandshakeTimeout = 10 * time.Second
+
+// maxTLSRecordPayload is the largest plaintext carried by a TLS record
+const maxTLSRecordPayload = 16384
+
+// NewTLSConfig creates the TLS configuration of the E2 connections from the given CA certificate, verifying the
+// controllers, and key pair, presented to the controllers requesting client certificates; the default ONF CA and
+// client certificates are used for the paths left empty
+func NewTLSConfig(caPath string, keyPath string, certPath string) (*tls.Config, error) {
+	var err error
+	config := &tls.Config{}
+	var cert tls.Certificate
+	if keyPath == "" && certPath == "" {
+		cert, err = tls.X509KeyPair([]byte(certs.DefaultClientCrt), []byte(certs.DefaultClientKey))
+	} else {
+		cert, err = tls.LoadX509KeyPair(certPath, keyPath)
+	}
+	if err != nil {
+		return nil, errors.New(errors.Invalid, "invalid E2 client key pair: %v", err)
+	}
+	config.Certificates = []tls.Certificate{cert}
+	if caPath == "" {
+		config.RootCAs, err = certs.GetCertPoolDefault()
+	} else {
+		config.RootCAs, err = certs.GetCertPool(caPath)
+	}
+	if err != nil {
+		return nil, errors.New(errors.Invalid, "invalid E2 CA certificate: %v", err)
+	}
+	return config, nil
+}
+
+// tlsRecordConn is a TLS connection sending each E2AP PDU in a TLS record of its own. Each read of a TLS connection
+// returns the payload of a single record at most, whereas E2AP peers such as onos-e2t decode a whole PDU per read, as
+// SCTP preserves the message boundaries; PDUs exceeding a record are therefore rejected rather than split.
+type tlsRecordConn struct {
+	net.Conn
+}
+
+func (c *tlsRecordConn) Write(b []byte) (int, error) {
+	if len(b) > maxTLSRecordPayload {
+		return 0, errors.New(errors.Invalid, "E2AP PDU of %d bytes exceeds the TLS record payload of %d bytes", len(b), maxTLSRecordPayload)
+	}
+	return c.Conn.Write(b)
+}
+
+// newTLSConn secures the connection to the named controller with TLS, completing the handshake; the connection is
+// closed if the handshake does not complete in time, SCTP connections not supporting deadlines
+func newTLSConn(conn net.Conn, config *tls.Config, serverName string) (net.Conn, error) {
+	config = config.Clone()
+	if config.ServerName == "" {
+		config.ServerName = serverName
+	}
+	// Dynamic record sizing would split the PDUs sent first into records of about a TCP segment
+	config.DynamicRecordSizingDisabled = true
+	tlsConn := tls.Client(conn, config)
+	timer := time.AfterFunc(tlsHandshakeTimeout, func() {
+		_ = conn.Close()
+	})
+	err := tlsConn.Handshake()
+	if !timer.Stop() {
+		return nil, errors.New(errors.Timeout, "TLS handshake with %s timed out", serverName)
+	}
+	if err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+	return &tlsRecordConn{Conn: tlsConn}, nil
+}
@@ -0,0 +1,125 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package e2agent
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// issue creates a certificate for the given name signed by the parent, self-signed if nil, and its key
+func issue(t *testing.T, name string, parent *x509.Certificate, parentKey *ecdsa.PrivateKey) (*x509.Certificate, *ecdsa.PrivateKey, []byte, []byte) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: name},
+		DNSNames:     []string{name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+	if parent == nil {
+		template.IsCA = true
+		template.BasicConstraintsValid = true
+		parent, parentKey = template, key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, parentKey)
+	assert.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	assert.NoError(t, err)
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	assert.NoError(t, err)
+	return cert, key, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer})
+}
+
+func TestTLSConn(t *testing.T) {
+	dir, err := ioutil.TempDir("", "e2tls")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	write := func(name string, data []byte) string {
+		path := filepath.Join(dir, name)
+		assert.NoError(t, ioutil.WriteFile(path, data, 0600))
+		return path
+	}
+
+	ca, caKey, caPEM, _ := issue(t, "ca", nil, nil)
+	_, _, serverPEM, serverKeyPEM := issue(t, "e2t", ca, caKey)
+	_, _, clientPEM, clientKeyPEM := issue(t, "ran-simulator", ca, caKey)
+	caPath := write("ca.crt", caPEM)
+	certPath, keyPath := write("client.crt", clientPEM), write("client.key", clientKeyPEM)
+
+	_, err = NewTLSConfig(caPath, filepath.Join(dir, "missing.key"), certPath)
+	assert.Error(t, err)
+	_, err = NewTLSConfig(filepath.Join(dir, "missing.crt"), keyPath, certPath)
+	assert.Error(t, err)
+	config, err := NewTLSConfig(caPath, keyPath, certPath)
+	assert.NoError(t, err)
+
+	serverCert, err := tls.X509KeyPair(serverPEM, serverKeyPEM)
+	assert.NoError(t, err)
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(ca)
+	serverConfig := &tls.Config{
+		Certificates: []tls.Certificate{serverCert},
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		ClientCAs:    clientCAs,
+	}
+
+	// The node verifies the controller and authenticates with its client certificate, each PDU being read at once
+	client, server := net.Pipe()
+	done := make(chan []byte)
+	go func() {
+		tlsServer := tls.Server(server, serverConfig)
+		for i := 0; i < 2; i++ {
+			buf := make([]byte, 2*maxTLSRecordPayload)
+			n, err := tlsServer.Read(buf)
+			assert.NoError(t, err)
+			done <- buf[:n]
+		}
+		_ = server.Close()
+	}()
+	conn, err := newTLSConn(client, config, "e2t")
+	assert.NoError(t, err)
+	if assert.NotNil(t, conn) {
+		_, err = conn.Write([]byte("E2AP"))
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("E2AP"), <-done)
+		pdu := make([]byte, maxTLSRecordPayload)
+		_, err = conn.Write(pdu)
+		assert.NoError(t, err)
+		assert.Equal(t, pdu, <-done)
+
+		// PDUs exceeding a record are not split
+		_, err = conn.Write(make([]byte, maxTLSRecordPayload+1))
+		assert.Error(t, err)
+		_ = conn.Close()
+	}
+
+	// Controllers not matching their certificate are rejected
+	client, server = net.Pipe()
+	go func() {
+		_ = tls.Server(server, serverConfig).Handshake()
+		_ = server.Close()
+	}()
+	_, err = newTLSConn(client, config, "e2t.example.com")
+	assert.Error(t, err)
+}
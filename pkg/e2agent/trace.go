@@ -0,0 +1,243 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package e2agent
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	e2apies "github.com/onosproject/onos-e2t/api/e2ap/v1beta2/e2ap-ies"
+	e2appducontents "github.com/onosproject/onos-e2t/api/e2ap/v1beta2/e2ap-pdu-contents"
+	e2 "github.com/onosproject/onos-e2t/pkg/protocols/e2ap101"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/onosproject/onos-lib-go/pkg/logging"
+	"github.com/onosproject/ran-simulator/pkg/servicemodel/registry"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// TraceAttribute is the name of the node attribute setting the level at which the E2AP messages sent and received
+// by the node are traced, i.e. "info" for a summary of each message or "debug" for the whole message including the
+// E2SM payloads decoded by the model plugin of its service model; the messages are not traced if not set
+const TraceAttribute = "e2.trace"
+
+var traceLog = logging.GetLogger("e2agent", "trace")
+
+// TraceLevel is the level of detail at which the E2AP messages of a node are traced
+type TraceLevel int
+
+const (
+	// TraceOff disables the tracing
+	TraceOff TraceLevel = iota
+	// TraceInfo traces the name, RAN function and RIC request ID of each message
+	TraceInfo
+	// TraceDebug traces each message with its decoded E2SM payloads
+	TraceDebug
+)
+
+// ParseTraceLevel parses the trace level, i.e. off, info or debug
+func ParseTraceLevel(level string) (TraceLevel, error) {
+	switch strings.ToLower(strings.TrimSpace(level)) {
+	case "", "off", "false", "0":
+		return TraceOff, nil
+	case "info":
+		return TraceInfo, nil
+	case "debug":
+		return TraceDebug, nil
+	}
+	return TraceOff, errors.New(errors.Invalid, "unknown trace level %s", level)
+}
+
+const (
+	traceSent     = "sent"
+	traceReceived = "received"
+)
+
+// tracePayload is an ASN.1 encoded E2SM payload of a message
+type tracePayload struct {
+	kind  registry.PayloadKind
+	bytes []byte
+}
+
+// traceSummary is the summary of a traced message
+type traceSummary struct {
+	name      string
+	ranFuncID int32
+	requestID *e2apies.RicrequestId
+	payloads  []tracePayload
+}
+
+// summarize returns the summary of the E2AP message
+func summarize(pdu interface{}) traceSummary {
+	switch m := pdu.(type) {
+	case *e2appducontents.RicsubscriptionRequest:
+		ies := m.GetProtocolIes()
+		summary := traceSummary{
+			name:      "RICsubscriptionRequest",
+			ranFuncID: ies.GetE2ApProtocolIes5().GetValue().GetValue(),
+			requestID: ies.GetE2ApProtocolIes29().GetValue(),
+		}
+		details := ies.GetE2ApProtocolIes30().GetValue()
+		summary.payloads = append(summary.payloads, tracePayload{registry.EventTriggerPayload, details.GetRicEventTriggerDefinition().GetValue()})
+		for _, item := range details.GetRicActionToBeSetupList().GetValue() {
+			if definition := item.GetValue().GetRicActionDefinition(); definition != nil {
+				summary.payloads = append(summary.payloads, tracePayload{registry.ActionDefinitionPayload, definition.GetValue()})
+			}
+		}
+		return summary
+	case *e2appducontents.RicsubscriptionResponse:
+		ies := m.GetProtocolIes()
+		return traceSummary{name: "RICsubscriptionResponse", ranFuncID: ies.GetE2ApProtocolIes5().GetValue().GetValue(), requestID: ies.GetE2ApProtocolIes29().GetValue()}
+	case *e2appducontents.RicsubscriptionFailure:
+		ies := m.GetProtocolIes()
+		return traceSummary{name: "RICsubscriptionFailure", ranFuncID: ies.GetE2ApProtocolIes5().GetValue().GetValue(), requestID: ies.GetE2ApProtocolIes29().GetValue()}
+	case *e2appducontents.RicsubscriptionDeleteRequest:
+		ies := m.GetProtocolIes()
+		return traceSummary{name: "RICsubscriptionDeleteRequest", ranFuncID: ies.GetE2ApProtocolIes5().GetValue().GetValue(), requestID: ies.GetE2ApProtocolIes29().GetValue()}
+	case *e2appducontents.RicsubscriptionDeleteResponse:
+		ies := m.GetProtocolIes()
+		return traceSummary{name: "RICsubscriptionDeleteResponse", ranFuncID: ies.GetE2ApProtocolIes5().GetValue().GetValue(), requestID: ies.GetE2ApProtocolIes29().GetValue()}
+	case *e2appducontents.RicsubscriptionDeleteFailure:
+		ies := m.GetProtocolIes()
+		return traceSummary{name: "RICsubscriptionDeleteFailure", ranFuncID: ies.GetE2ApProtocolIes5().GetValue().GetValue(), requestID: ies.GetE2ApProtocolIes29().GetValue()}
+	case *e2appducontents.RiccontrolRequest:
+		ies := m.GetProtocolIes()
+		return traceSummary{
+			name:      "RICcontrolRequest",
+			ranFuncID: ies.GetE2ApProtocolIes5().GetValue().GetValue(),
+			requestID: ies.GetE2ApProtocolIes29().GetValue(),
+			payloads: []tracePayload{
+				{registry.ControlHeaderPayload, ies.GetE2ApProtocolIes22().GetValue().GetValue()},
+				{registry.ControlMessagePayload, ies.GetE2ApProtocolIes23().GetValue().GetValue()},
+			},
+		}
+	case *e2appducontents.RiccontrolAcknowledge:
+		ies := m.GetProtocolIes()
+		summary := traceSummary{name: "RICcontrolAcknowledge", ranFuncID: ies.GetE2ApProtocolIes5().GetValue().GetValue(), requestID: ies.GetE2ApProtocolIes29().GetValue()}
+		if outcome := ies.GetE2ApProtocolIes32().GetValue(); outcome != nil {
+			summary.payloads = []tracePayload{{registry.ControlOutcomePayload, outcome.GetValue()}}
+		}
+		return summary
+	case *e2appducontents.RiccontrolFailure:
+		ies := m.GetProtocolIes()
+		return traceSummary{name: "RICcontrolFailure", ranFuncID: ies.GetE2ApProtocolIes5().GetValue().GetValue(), requestID: ies.GetE2ApProtocolIes29().GetValue()}
+	case *e2appducontents.Ricindication:
+		ies := m.GetProtocolIes()
+		return traceSummary{
+			name:      "RICindication",
+			ranFuncID: ies.GetE2ApProtocolIes5().GetValue().GetValue(),
+			requestID: ies.GetE2ApProtocolIes29().GetValue(),
+			payloads: []tracePayload{
+				{registry.IndicationHeaderPayload, ies.GetE2ApProtocolIes25().GetValue().GetValue()},
+				{registry.IndicationMessagePayload, ies.GetE2ApProtocolIes26().GetValue().GetValue()},
+			},
+		}
+	case *e2appducontents.E2SetupRequest:
+		return traceSummary{name: "E2setupRequest"}
+	case *e2appducontents.E2SetupResponse:
+		return traceSummary{name: "E2setupResponse"}
+	case *e2appducontents.E2SetupFailure:
+		return traceSummary{name: "E2setupFailure"}
+	}
+	return traceSummary{name: fmt.Sprintf("%T", pdu)}
+}
+
+// traceLevel returns the current trace level of the node
+func (a *e2Agent) traceLevel(ctx context.Context) TraceLevel {
+	if a.metricStore == nil {
+		return TraceOff
+	}
+	value, ok := a.metricStore.Get(ctx, uint64(a.node.EnbID), TraceAttribute)
+	if !ok {
+		return TraceOff
+	}
+	level, err := ParseTraceLevel(fmt.Sprintf("%v", value))
+	if err != nil {
+		log.Warn(err)
+	}
+	return level
+}
+
+// trace logs the message sent or received by the node at the current trace level of the node, if any message
+func (a *e2Agent) trace(ctx context.Context, direction string, pdu interface{}) {
+	if pdu == nil || reflect.ValueOf(pdu).IsNil() {
+		return
+	}
+	level := a.traceLevel(ctx)
+	if level == TraceOff {
+		return
+	}
+	summary := summarize(pdu)
+	traceLog.Infow(fmt.Sprintf("E2 node %d %s %s", a.node.EnbID, direction, summary.name), a.traceFields(level, direction, summary, pdu)...)
+}
+
+// traceFields returns the structured log fields of the traced message; at debug level the message is included as
+// JSON and each E2SM payload decoded by the service model of the message, or in hex if it cannot be decoded
+func (a *e2Agent) traceFields(level TraceLevel, direction string, summary traceSummary, pdu interface{}) []interface{} {
+	fields := []interface{}{"node", a.node.EnbID, "direction", direction, "message", summary.name}
+	if summary.ranFuncID != 0 {
+		fields = append(fields, "ranFunctionID", summary.ranFuncID)
+	}
+	if summary.requestID != nil {
+		fields = append(fields, "ricRequestorID", summary.requestID.GetRicRequestorId(), "ricInstanceID", summary.requestID.GetRicInstanceId())
+	}
+	if level < TraceDebug {
+		return fields
+	}
+
+	if bytes, err := json.Marshal(pdu); err == nil {
+		fields = append(fields, "pdu", string(bytes))
+	}
+	if len(summary.payloads) == 0 {
+		return fields
+	}
+	sm, smErr := a.registry.GetServiceModel(registry.RanFunctionID(summary.ranFuncID))
+	keys := make(map[string]int)
+	for _, payload := range summary.payloads {
+		key := string(payload.kind)
+		if n := keys[string(payload.kind)]; n > 0 {
+			key = fmt.Sprintf("%s.%d", payload.kind, n)
+		}
+		keys[string(payload.kind)]++
+
+		err := smErr
+		if err == nil {
+			var message proto.Message
+			if message, err = sm.Decode(payload.kind, payload.bytes); err == nil {
+				var bytes []byte
+				if bytes, err = protojson.Marshal(message); err == nil {
+					fields = append(fields, key, string(bytes))
+					continue
+				}
+			}
+		}
+		fields = append(fields, key, hex.EncodeToString(payload.bytes), key+"Error", err.Error())
+	}
+	return fields
+}
+
+// traceChannel is an E2 channel tracing the indications it sends
+type traceChannel struct {
+	e2.ClientChannel
+	trace func(ctx context.Context, direction string, pdu interface{})
+}
+
+// newTraceChannel wraps the channel so that the indications sent through it are traced with the given function
+func newTraceChannel(channel e2.ClientChannel, trace func(ctx context.Context, direction string, pdu interface{})) e2.ClientChannel {
+	return &traceChannel{ClientChannel: channel, trace: trace}
+}
+
+// RICIndication sends and traces the indication
+func (c *traceChannel) RICIndication(ctx context.Context, request *e2appducontents.Ricindication) error {
+	err := c.ClientChannel.RICIndication(ctx, request)
+	if err == nil {
+		c.trace(ctx, traceSent, request)
+	}
+	return err
+}
@@ -0,0 +1,102 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package e2agent
+
+import (
+	"context"
+	"testing"
+
+	e2apcommondatatypes "github.com/onosproject/onos-e2t/api/e2ap/v1beta2/e2ap-commondatatypes"
+	e2apies "github.com/onosproject/onos-e2t/api/e2ap/v1beta2/e2ap-ies"
+	e2appducontents "github.com/onosproject/onos-e2t/api/e2ap/v1beta2/e2ap-pdu-contents"
+	"github.com/onosproject/ran-simulator/pkg/model"
+	"github.com/onosproject/ran-simulator/pkg/servicemodel/registry"
+	"github.com/onosproject/ran-simulator/pkg/store/metrics"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseTraceLevel(t *testing.T) {
+	level, err := ParseTraceLevel("")
+	assert.NoError(t, err)
+	assert.Equal(t, TraceOff, level)
+	level, err = ParseTraceLevel("Info")
+	assert.NoError(t, err)
+	assert.Equal(t, TraceInfo, level)
+	level, err = ParseTraceLevel(" debug")
+	assert.NoError(t, err)
+	assert.Equal(t, TraceDebug, level)
+	_, err = ParseTraceLevel("verbose")
+	assert.Error(t, err)
+}
+
+func TestTrace(t *testing.T) {
+	ctx := context.Background()
+	metricStore := metrics.NewMetricsStore()
+	agent := &e2Agent{
+		node:        model.Node{EnbID: 144470},
+		metricStore: metricStore,
+		registry:    registry.NewServiceModelRegistry(),
+	}
+	indication := &e2appducontents.Ricindication{
+		ProtocolIes: &e2appducontents.RicindicationIes{
+			E2ApProtocolIes5: &e2appducontents.RicindicationIes_RicindicationIes5{
+				Value: &e2apies.RanfunctionId{Value: 2},
+			},
+			E2ApProtocolIes29: &e2appducontents.RicindicationIes_RicindicationIes29{
+				Value: &e2apies.RicrequestId{RicRequestorId: 1, RicInstanceId: 3},
+			},
+			E2ApProtocolIes25: &e2appducontents.RicindicationIes_RicindicationIes25{
+				Value: &e2apcommondatatypes.RicindicationHeader{Value: []byte{0x0a, 0x0b}},
+			},
+			E2ApProtocolIes26: &e2appducontents.RicindicationIes_RicindicationIes26{
+				Value: &e2apcommondatatypes.RicindicationMessage{Value: []byte{1, 2, 3, 4}},
+			},
+		},
+	}
+
+	summary := summarize(indication)
+	assert.Equal(t, "RICindication", summary.name)
+	assert.Equal(t, int32(2), summary.ranFuncID)
+	assert.Equal(t, int32(1), summary.requestID.GetRicRequestorId())
+	assert.Len(t, summary.payloads, 2)
+
+	assert.Equal(t, TraceOff, agent.traceLevel(ctx))
+	assert.NoError(t, metricStore.Set(ctx, 144470, TraceAttribute, "info"))
+	assert.Equal(t, TraceInfo, agent.traceLevel(ctx))
+
+	fields := fieldMap(agent.traceFields(TraceInfo, traceSent, summary, indication))
+	assert.Equal(t, "RICindication", fields["message"])
+	assert.Equal(t, traceSent, fields["direction"])
+	assert.Equal(t, int32(2), fields["ranFunctionID"])
+	assert.Equal(t, int32(3), fields["ricInstanceID"])
+	assert.NotContains(t, fields, "pdu")
+
+	// Payloads that cannot be decoded are traced in hex along with the reason
+	fields = fieldMap(agent.traceFields(TraceDebug, traceSent, summary, indication))
+	assert.Contains(t, fields["pdu"], "protocol_ies")
+	assert.Equal(t, "0a0b", fields["indicationHeader"])
+	assert.Equal(t, "01020304", fields["indicationMessage"])
+	assert.Contains(t, fields, "indicationMessageError")
+
+	// Indications are traced once sent and nil messages are ignored
+	channel := &testChannel{}
+	var traced []interface{}
+	traceChannel := newTraceChannel(channel, func(ctx context.Context, direction string, pdu interface{}) {
+		traced = append(traced, pdu)
+	})
+	assert.NoError(t, traceChannel.RICIndication(ctx, indication))
+	assert.Equal(t, indication, channel.indications[0])
+	assert.Equal(t, []interface{}{indication}, traced)
+	var failure *e2appducontents.RicsubscriptionFailure
+	agent.trace(ctx, traceSent, failure)
+}
+
+func fieldMap(fields []interface{}) map[string]interface{} {
+	m := make(map[string]interface{})
+	for i := 0; i+1 < len(fields); i += 2 {
+		m[fields[i].(string)] = fields[i+1]
+	}
+	return m
+}
@@ -18,11 +18,14 @@ import (
 const (
 	backoffInterval = 10 * time.Millisecond
 	maxBackoffTime  = 5 * time.Second
+	// backoffJitter spreads the retries of the nodes so they do not hit the controller in lockstep
+	backoffJitter = 0.5
 )
 
 func newExpBackoff() *backoff.ExponentialBackOff {
 	b := backoff.NewExponentialBackOff()
 	b.InitialInterval = backoffInterval
+	b.RandomizationFactor = backoffJitter
 	// MaxInterval caps the RetryInterval
 	b.MaxInterval = maxBackoffTime
 	// Never stops retrying
@@ -0,0 +1,140 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package energy
+
+import (
+	"context"
+	"time"
+
+	"github.com/onosproject/onos-lib-go/pkg/logging"
+	"github.com/onosproject/ran-simulator/pkg/store/cells"
+	"github.com/onosproject/ran-simulator/pkg/store/event"
+	"github.com/onosproject/ran-simulator/pkg/store/metrics"
+	"github.com/onosproject/ran-simulator/pkg/store/ues"
+)
+
+var log = logging.GetLogger("energy")
+
+const (
+	// SleepAttribute is the cell attribute controlling the energy-saving state of the cell; any
+	// non-zero value puts the cell to sleep. It can be set via the metrics API or RC control.
+	SleepAttribute = "energy.sleep"
+
+	// AvgPower average power consumed by the cell in Watts
+	AvgPower = "PEE.AvgPower"
+	// SleepTransitions number of transitions of the cell into the energy-saving state
+	SleepTransitions = "ES.SleepTrans.Tot"
+	// WakeTransitions number of transitions of the cell out of the energy-saving state
+	WakeTransitions = "ES.WakeTrans.Tot"
+
+	defaultInterval = 10 * time.Second
+)
+
+// Controller tracks the energy-saving state of cells and periodically computes their power consumption
+type Controller struct {
+	cellStore   cells.Store
+	ueStore     ues.Store
+	metricStore metrics.Store
+	powerModel  PowerModel
+	interval    time.Duration
+	cancel      context.CancelFunc
+}
+
+// NewController creates a new energy controller
+func NewController(cellStore cells.Store, ueStore ues.Store, metricStore metrics.Store) *Controller {
+	return &Controller{
+		cellStore:   cellStore,
+		ueStore:     ueStore,
+		metricStore: metricStore,
+		powerModel:  DefaultPowerModel(),
+		interval:    defaultInterval,
+	}
+}
+
+// Start initializes the energy-saving state of all cells and starts tracking it
+func (c *Controller) Start() error {
+	ctx, cancel := context.WithCancel(context.Background())
+	cellList, err := c.cellStore.List(ctx)
+	if err != nil {
+		cancel()
+		return err
+	}
+	for _, cell := range cellList {
+		// RC control only updates existing attributes; make sure the sleep attribute exists
+		if _, ok := c.metricStore.Get(ctx, uint64(cell.ECGI), SleepAttribute); !ok {
+			_ = c.metricStore.Set(ctx, uint64(cell.ECGI), SleepAttribute, int32(0))
+		}
+	}
+
+	ch := make(chan event.Event)
+	options := metrics.WatchOptions{Names: []string{SleepAttribute}, Types: []metrics.MetricEvent{metrics.Updated}}
+	if err := c.metricStore.Watch(ctx, ch, options); err != nil {
+		cancel()
+		return err
+	}
+	c.cancel = cancel
+	go c.processMetricEvents(ch)
+	go c.updatePower(ctx)
+	return nil
+}
+
+// Stop stops tracking the energy-saving state of cells
+func (c *Controller) Stop() {
+	if c.cancel != nil {
+		c.cancel()
+	}
+}
+
+// IsAsleep returns true if the cell with the specified ECGI is in the energy-saving state
+func IsAsleep(ctx context.Context, metricStore metrics.Store, ecgi uint64) bool {
+	value, ok := metricStore.Get(ctx, ecgi, SleepAttribute)
+	return ok && metrics.IsSet(value)
+}
+
+func (c *Controller) processMetricEvents(ch <-chan event.Event) {
+	ctx := context.Background()
+	asleep := make(map[uint64]bool)
+	for metricEvent := range ch {
+		key := metricEvent.Key.(metrics.Key)
+		sleep := metrics.IsSet(metricEvent.Value)
+		if sleep == asleep[key.EntityID] {
+			continue
+		}
+		asleep[key.EntityID] = sleep
+		if sleep {
+			log.Infof("Cell %d entering energy-saving state", key.EntityID)
+			c.increment(ctx, key.EntityID, SleepTransitions)
+		} else {
+			log.Infof("Cell %d leaving energy-saving state", key.EntityID)
+			c.increment(ctx, key.EntityID, WakeTransitions)
+		}
+	}
+}
+
+func (c *Controller) updatePower(ctx context.Context) {
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			cellList, err := c.cellStore.List(ctx)
+			if err != nil {
+				log.Warn(err)
+				continue
+			}
+			for _, cell := range cellList {
+				load := cell.Load(len(c.ueStore.ListUEs(ctx, cell.ECGI)))
+				asleep := IsAsleep(ctx, c.metricStore, uint64(cell.ECGI))
+				_ = c.metricStore.Set(ctx, uint64(cell.ECGI), AvgPower, c.powerModel.Power(cell, load, asleep))
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (c *Controller) increment(ctx context.Context, entityID uint64, name string) {
+	_, _ = c.metricStore.Add(ctx, entityID, name, 1)
+}
@@ -0,0 +1,45 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package energy
+
+import (
+	"math"
+
+	"github.com/onosproject/ran-simulator/pkg/model"
+)
+
+// PowerModel is a linear base station power consumption model, where the input power of
+// an active cell grows with its transmit power and load and a sleeping cell draws a constant power
+type PowerModel struct {
+	// StaticPower power consumed by an active cell at zero load in Watts
+	StaticPower float64
+	// LoadSlope slope of the load dependent power consumption
+	LoadSlope float64
+	// SleepPower power consumed by a sleeping cell in Watts
+	SleepPower float64
+}
+
+// DefaultPowerModel returns the power model with parameters typical of a macro cell
+func DefaultPowerModel() PowerModel {
+	return PowerModel{
+		StaticPower: 130,
+		LoadSlope:   4.7,
+		SleepPower:  75,
+	}
+}
+
+// Power returns the power consumed by the cell in Watts given its load in range [0, 1]
+func (pm PowerModel) Power(cell *model.Cell, load float64, asleep bool) float64 {
+	if asleep {
+		return pm.SleepPower
+	}
+	load = math.Max(0, math.Min(1, load))
+	return pm.StaticPower + pm.LoadSlope*txPowerWatts(cell.TxPowerDB)*load
+}
+
+// txPowerWatts converts the transmit power from dBm to Watts
+func txPowerWatts(txPowerDBm float64) float64 {
+	return math.Pow(10, txPowerDBm/10) / 1000
+}
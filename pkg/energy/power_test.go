@@ -0,0 +1,26 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package energy
+
+import (
+	"testing"
+
+	"github.com/onosproject/ran-simulator/pkg/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPowerModel(t *testing.T) {
+	pm := DefaultPowerModel()
+	cell := &model.Cell{MaxUEs: 10, TxPowerDB: 40}
+
+	assert.Equal(t, 0.5, cell.Load(5))
+	assert.Equal(t, 1.0, cell.Load(20))
+	assert.Equal(t, 0.0, (&model.Cell{}).Load(5))
+
+	assert.Equal(t, pm.StaticPower, pm.Power(cell, 0, false))
+	assert.InDelta(t, pm.StaticPower+pm.LoadSlope*10*0.5, pm.Power(cell, 0.5, false), 1e-9)
+	assert.Equal(t, pm.SleepPower, pm.Power(cell, 1, true))
+	assert.True(t, pm.Power(cell, 1, false) > pm.Power(cell, 0.5, false))
+}
@@ -0,0 +1,84 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package export
+
+import (
+	"encoding/csv"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"time"
+)
+
+var csvHeader = []string{"time", "measurement", "entity", "name", "value"}
+
+// CSVWriter writes KPI samples as CSV records, one record per KPI
+type CSVWriter struct {
+	closer io.Closer
+	writer *csv.Writer
+}
+
+// NewCSVFileWriter creates a CSV writer appending to the specified file
+func NewCSVFileWriter(path string) (*CSVWriter, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return nil, err
+	}
+	return newCSVWriter(file, file, info.Size() == 0)
+}
+
+func newCSVWriter(w io.Writer, closer io.Closer, header bool) (*CSVWriter, error) {
+	writer := &CSVWriter{
+		closer: closer,
+		writer: csv.NewWriter(w),
+	}
+	if header {
+		if err := writer.writer.Write(csvHeader); err != nil {
+			return nil, err
+		}
+	}
+	return writer, nil
+}
+
+// Write writes the specified samples
+func (w *CSVWriter) Write(samples []*Sample) error {
+	for _, sample := range samples {
+		timestamp := sample.Time.UTC().Format(time.RFC3339Nano)
+		entity := strconv.FormatUint(sample.EntityID, 10)
+		for _, name := range sortedFields(sample) {
+			record := []string{timestamp, sample.Measurement, entity, name,
+				strconv.FormatFloat(sample.Fields[name], 'g', -1, 64)}
+			if err := w.writer.Write(record); err != nil {
+				return err
+			}
+		}
+	}
+	w.writer.Flush()
+	return w.writer.Error()
+}
+
+// Close closes the writer
+func (w *CSVWriter) Close() error {
+	w.writer.Flush()
+	if w.closer != nil {
+		return w.closer.Close()
+	}
+	return nil
+}
+
+func sortedFields(sample *Sample) []string {
+	names := make([]string, 0, len(sample.Fields))
+	for name := range sample.Fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,66 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package export
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/onosproject/ran-simulator/pkg/model"
+	"github.com/onosproject/ran-simulator/pkg/store/cells"
+	"github.com/onosproject/ran-simulator/pkg/store/metrics"
+	"github.com/onosproject/ran-simulator/pkg/store/nodes"
+	"github.com/onosproject/ran-simulator/pkg/store/ues"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v2"
+)
+
+func TestExport(t *testing.T) {
+	m := model.Model{}
+	data, err := ioutil.ReadFile("../model/test.yaml")
+	assert.NoError(t, err)
+	err = yaml.Unmarshal(data, &m)
+	assert.NoError(t, err)
+
+	ctx := context.Background()
+	cellStore := cells.NewCellRegistry(m.Cells, nodes.NewNodeRegistry(m.Nodes))
+	ueStore := ues.NewUERegistry(3, cellStore)
+	metricStore := metrics.NewMetricsStore()
+	assert.NoError(t, metricStore.Set(ctx, 84325717505, "PEE.AvgPower", 130.5))
+	assert.NoError(t, metricStore.Set(ctx, 84325717505, "cellSize", "FEMTO"))
+
+	exporter := NewExporter(cellStore, ueStore, metricStore, time.Second)
+	now := time.Unix(1600000000, 0)
+	samples := exporter.Sample(ctx, now)
+	assert.Len(t, samples, 7)
+
+	var cellSample *Sample
+	for _, sample := range samples {
+		if sample.Measurement == CellMeasurement && sample.EntityID == 84325717505 {
+			cellSample = sample
+		}
+	}
+	assert.NotNil(t, cellSample)
+	assert.Equal(t, 130.5, cellSample.Fields["PEE.AvgPower"])
+	_, ok := cellSample.Fields["cellSize"]
+	assert.False(t, ok)
+
+	buf := &bytes.Buffer{}
+	csvWriter, err := newCSVWriter(buf, nil, true)
+	assert.NoError(t, err)
+	assert.NoError(t, csvWriter.Write([]*Sample{cellSample}))
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	assert.Equal(t, "time,measurement,entity,name,value", lines[0])
+	assert.Equal(t, "2020-09-13T12:26:40Z,cell,84325717505,PEE.AvgPower,130.5", lines[1])
+
+	buf.Reset()
+	assert.NoError(t, WriteLineProtocol(buf, []*Sample{cellSample}))
+	assert.True(t, strings.HasPrefix(buf.String(), "cell,entity=84325717505 PEE.AvgPower=130.5,load=0,txPower=0,ueCount="))
+	assert.True(t, strings.HasSuffix(buf.String(), " 1600000000000000000\n"))
+}
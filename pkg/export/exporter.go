@@ -0,0 +1,183 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package export
+
+import (
+	"context"
+	"time"
+
+	"github.com/onosproject/onos-lib-go/pkg/logging"
+	"github.com/onosproject/ran-simulator/pkg/store/cells"
+	"github.com/onosproject/ran-simulator/pkg/store/metrics"
+	"github.com/onosproject/ran-simulator/pkg/store/ues"
+)
+
+var log = logging.GetLogger("export")
+
+const (
+	// CellMeasurement is the measurement name of the per-cell samples
+	CellMeasurement = "cell"
+	// UEMeasurement is the measurement name of the per-UE samples
+	UEMeasurement = "ue"
+)
+
+// Sample is a set of numeric KPIs of an entity sampled at the same time
+type Sample struct {
+	Time        time.Time
+	Measurement string
+	EntityID    uint64
+	Fields      map[string]float64
+}
+
+// Writer writes KPI samples to a destination
+type Writer interface {
+	// Write writes the specified samples
+	Write(samples []*Sample) error
+
+	// Close closes the writer
+	Close() error
+}
+
+// Exporter periodically samples the per-cell and per-UE KPIs and writes them using the configured writers
+type Exporter struct {
+	cellStore   cells.Store
+	ueStore     ues.Store
+	metricStore metrics.Store
+	writers     []Writer
+	interval    time.Duration
+	cancel      context.CancelFunc
+}
+
+// NewExporter creates a new KPI exporter
+func NewExporter(cellStore cells.Store, ueStore ues.Store, metricStore metrics.Store, interval time.Duration, writers ...Writer) *Exporter {
+	return &Exporter{
+		cellStore:   cellStore,
+		ueStore:     ueStore,
+		metricStore: metricStore,
+		writers:     writers,
+		interval:    interval,
+	}
+}
+
+// Start starts sampling the KPIs
+func (e *Exporter) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	e.cancel = cancel
+	go e.run(ctx)
+}
+
+// Stop stops sampling the KPIs and closes the writers
+func (e *Exporter) Stop() {
+	if e.cancel != nil {
+		e.cancel()
+	}
+}
+
+func (e *Exporter) run(ctx context.Context) {
+	ticker := time.NewTicker(e.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			samples := e.Sample(ctx, time.Now())
+			for _, writer := range e.writers {
+				if err := writer.Write(samples); err != nil {
+					log.Warn(err)
+				}
+			}
+		case <-ctx.Done():
+			for _, writer := range e.writers {
+				if err := writer.Close(); err != nil {
+					log.Warn(err)
+				}
+			}
+			return
+		}
+	}
+}
+
+// Sample samples the current KPIs of all cells and UEs
+func (e *Exporter) Sample(ctx context.Context, now time.Time) []*Sample {
+	samples := make([]*Sample, 0)
+	cellList, err := e.cellStore.List(ctx)
+	if err != nil {
+		log.Warn(err)
+		return samples
+	}
+	for _, cell := range cellList {
+		sample := e.newSample(ctx, now, CellMeasurement, uint64(cell.ECGI))
+		ueCount := len(e.ueStore.ListUEs(ctx, cell.ECGI))
+		sample.Fields["ueCount"] = float64(ueCount)
+		sample.Fields["load"] = cell.Load(ueCount)
+		sample.Fields["txPower"] = cell.TxPowerDB
+		samples = append(samples, sample)
+	}
+	for _, ue := range e.ueStore.ListAllUEs(ctx) {
+		sample := e.newSample(ctx, now, UEMeasurement, uint64(ue.IMSI))
+		if ue.Cell != nil {
+			sample.Fields["servingCell"] = float64(ue.Cell.ECGI)
+			sample.Fields["strength"] = ue.Cell.Strength
+		}
+		sample.Fields["drbs"] = float64(len(ue.DRBs))
+		samples = append(samples, sample)
+	}
+	return samples
+}
+
+// newSample creates a sample holding all numeric metrics of the specified entity
+func (e *Exporter) newSample(ctx context.Context, now time.Time, measurement string, entityID uint64) *Sample {
+	sample := &Sample{
+		Time:        now,
+		Measurement: measurement,
+		EntityID:    entityID,
+		Fields:      make(map[string]float64),
+	}
+	values, err := e.metricStore.List(ctx, entityID)
+	if err != nil {
+		return sample
+	}
+	for name, value := range values {
+		if f, ok := toFloat(value); ok {
+			sample.Fields[name] = f
+		}
+	}
+	return sample
+}
+
+// toFloat converts numeric and boolean metric values to float
+func toFloat(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case int:
+		return float64(v), true
+	case int8:
+		return float64(v), true
+	case int16:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case uint:
+		return float64(v), true
+	case uint8:
+		return float64(v), true
+	case uint16:
+		return float64(v), true
+	case uint32:
+		return float64(v), true
+	case uint64:
+		return float64(v), true
+	case float32:
+		return float64(v), true
+	case float64:
+		return v, true
+	case bool:
+		if v {
+			return 1, true
+		}
+		return 0, true
+	}
+	return 0, false
+}
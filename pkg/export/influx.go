@@ -0,0 +1,86 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package export
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+)
+
+const influxTimeout = 10 * time.Second
+
+// InfluxWriter writes KPI samples in the InfluxDB line protocol to an HTTP write endpoint,
+// e.g. http://influxdb:8086/write?db=ransim
+type InfluxWriter struct {
+	url    string
+	client *http.Client
+}
+
+// NewInfluxWriter creates a writer posting the samples to the specified InfluxDB write URL
+func NewInfluxWriter(url string) *InfluxWriter {
+	return &InfluxWriter{
+		url:    url,
+		client: &http.Client{Timeout: influxTimeout},
+	}
+}
+
+// Write writes the specified samples
+func (w *InfluxWriter) Write(samples []*Sample) error {
+	if len(samples) == 0 {
+		return nil
+	}
+	body := &bytes.Buffer{}
+	if err := WriteLineProtocol(body, samples); err != nil {
+		return err
+	}
+	resp, err := w.client.Post(w.url, "text/plain; charset=utf-8", body)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(ioutil.Discard, resp.Body)
+	if resp.StatusCode/100 != 2 {
+		return errors.New(errors.Unavailable, "InfluxDB write failed with status %s", resp.Status)
+	}
+	return nil
+}
+
+// Close closes the writer
+func (w *InfluxWriter) Close() error {
+	return nil
+}
+
+// WriteLineProtocol writes the samples in the InfluxDB line protocol, one line per sample
+func WriteLineProtocol(w io.Writer, samples []*Sample) error {
+	for _, sample := range samples {
+		if len(sample.Fields) == 0 {
+			continue
+		}
+		fields := make([]string, 0, len(sample.Fields))
+		for _, name := range sortedFields(sample) {
+			fields = append(fields, escape(name)+"="+strconv.FormatFloat(sample.Fields[name], 'g', -1, 64))
+		}
+		_, err := fmt.Fprintf(w, "%s,entity=%d %s %d\n", escape(sample.Measurement), sample.EntityID,
+			strings.Join(fields, ","), sample.Time.UnixNano())
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+var escaper = strings.NewReplacer(",", `\,`, "=", `\=`, " ", `\ `)
+
+func escape(s string) string {
+	return escaper.Replace(s)
+}
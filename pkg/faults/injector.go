@@ -0,0 +1,282 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package faults
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/onosproject/onos-api/go/onos/ransim/types"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/onosproject/onos-lib-go/pkg/logging"
+	"github.com/onosproject/ran-simulator/pkg/journal"
+	"github.com/onosproject/ran-simulator/pkg/mobility"
+	"github.com/onosproject/ran-simulator/pkg/store/cells"
+	"github.com/onosproject/ran-simulator/pkg/store/event"
+	"github.com/onosproject/ran-simulator/pkg/store/metrics"
+	"github.com/onosproject/ran-simulator/pkg/store/nodes"
+	"github.com/onosproject/ran-simulator/pkg/store/watcher"
+)
+
+var log = logging.GetLogger("faults")
+
+// AlarmAttributePrefix is the prefix of the entity attributes that are present while a fault is active, e.g. "alarm.CellOutage"
+const AlarmAttributePrefix = "alarm."
+
+const defaultFlapDuration = 5 * time.Second
+
+// NodeAgents allows stopping and restarting the E2 agents of individual nodes
+type NodeAgents interface {
+	// StopAgent stops the E2 agent of the specified node
+	StopAgent(enbID types.EnbID) error
+
+	// StartAgent starts the E2 agent of the specified node
+	StartAgent(enbID types.EnbID) error
+}
+
+// Injector injects faults into the simulated RAN and clears them
+type Injector struct {
+	mu          sync.RWMutex
+	faults      map[uint64]*Fault
+	nextID      uint64
+	watchers    *watcher.Watchers
+	cellStore   cells.Store
+	nodeStore   nodes.Store
+	metricStore metrics.Store
+	agents      NodeAgents
+	handover    *mobility.HandoverEngine
+	cancel      context.CancelFunc
+	// outages and agentsDown count the active faults taking down each cell and E2 agent, so that overlapping
+	// faults restore them only once the last one is cleared
+	outages    map[uint64]int
+	agentsDown map[uint64]int
+}
+
+// NewInjector creates a new fault injector
+func NewInjector(cellStore cells.Store, nodeStore nodes.Store, metricStore metrics.Store,
+	agents NodeAgents, handover *mobility.HandoverEngine) *Injector {
+	return &Injector{
+		faults:      make(map[uint64]*Fault),
+		nextID:      1,
+		watchers:    watcher.NewWatchers("faults"),
+		cellStore:   cellStore,
+		nodeStore:   nodeStore,
+		metricStore: metricStore,
+		agents:      agents,
+		handover:    handover,
+		outages:     make(map[uint64]int),
+		agentsDown:  make(map[uint64]int),
+	}
+}
+
+// Inject applies the specified fault and schedules its clearing if it has a duration
+func (i *Injector) Inject(ctx context.Context, fault *Fault) error {
+	if fault.Type == E2Flap && fault.Duration == 0 {
+		fault.Duration = defaultFlapDuration
+	}
+	if err := i.apply(ctx, fault, true); err != nil {
+		return err
+	}
+
+	i.mu.Lock()
+	fault.ID = i.nextID
+	fault.Raised = time.Now()
+	i.nextID++
+	i.faults[fault.ID] = fault
+	i.mu.Unlock()
+
+	log.Infof("Raised fault %d: %s on entity %d", fault.ID, fault.Type, fault.EntityID)
+	fault.labelID = journal.DefaultLabels().Add(journal.FaultLabel, fault.Raised, time.Time{}, []uint64{fault.EntityID}, map[string]interface{}{
+		"faultID":     fault.ID,
+		"type":        fault.Type.String(),
+		"degradation": fault.Degradation,
+	})
+	_ = i.metricStore.Set(ctx, fault.EntityID, AlarmAttributePrefix+fault.Type.String(), fault.ID)
+	i.watchers.Send(event.Event{
+		Key:   fault.ID,
+		Value: fault,
+		Type:  Raised,
+	})
+
+	if fault.Duration > 0 {
+		id := fault.ID
+		time.AfterFunc(fault.Duration, func() {
+			if _, err := i.Clear(context.Background(), id); err != nil && !errors.IsNotFound(err) {
+				log.Warn(err)
+			}
+		})
+	}
+	return nil
+}
+
+// Clear clears the fault with the specified ID and reverts its effects
+func (i *Injector) Clear(ctx context.Context, id uint64) (*Fault, error) {
+	i.mu.Lock()
+	fault, ok := i.faults[id]
+	if !ok {
+		i.mu.Unlock()
+		return nil, errors.New(errors.NotFound, "fault %d not found", id)
+	}
+	delete(i.faults, id)
+	i.mu.Unlock()
+
+	if err := i.apply(ctx, fault, false); err != nil {
+		return nil, err
+	}
+
+	log.Infof("Cleared fault %d: %s on entity %d", fault.ID, fault.Type, fault.EntityID)
+	journal.DefaultLabels().End(fault.labelID, time.Now())
+	// The alarm remains present while other faults of the same type affect the entity
+	if remaining := i.find(fault.Type, fault.EntityID); remaining != nil {
+		_ = i.metricStore.Set(ctx, fault.EntityID, AlarmAttributePrefix+fault.Type.String(), remaining.ID)
+	} else {
+		_ = i.metricStore.Delete(ctx, fault.EntityID, AlarmAttributePrefix+fault.Type.String())
+	}
+	i.watchers.Send(event.Event{
+		Key:   fault.ID,
+		Value: fault,
+		Type:  Cleared,
+	})
+	return fault, nil
+}
+
+// ClearAll clears all faults of the specified type affecting the specified entity
+func (i *Injector) ClearAll(ctx context.Context, faultType Type, entityID uint64) {
+	for _, fault := range i.List() {
+		if fault.Type == faultType && fault.EntityID == entityID {
+			if _, err := i.Clear(ctx, fault.ID); err != nil {
+				log.Warn(err)
+			}
+		}
+	}
+}
+
+// find returns an active fault of the specified type affecting the specified entity, if any
+func (i *Injector) find(faultType Type, entityID uint64) *Fault {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	for _, fault := range i.faults {
+		if fault.Type == faultType && fault.EntityID == entityID {
+			return fault
+		}
+	}
+	return nil
+}
+
+// List returns all active faults
+func (i *Injector) List() []*Fault {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	list := make([]*Fault, 0, len(i.faults))
+	for _, fault := range i.faults {
+		list = append(list, fault)
+	}
+	return list
+}
+
+// Watch watches the fault events using the supplied channel
+func (i *Injector) Watch(ctx context.Context, ch chan<- event.Event) error {
+	return i.watchers.Watch(ctx, ch, nil)
+}
+
+// apply applies or reverts the effect of the specified fault
+func (i *Injector) apply(ctx context.Context, fault *Fault, raise bool) error {
+	switch fault.Type {
+	case CellOutage:
+		return i.setOutage(ctx, types.ECGI(fault.EntityID), raise)
+	case DegradedRSRP:
+		degradation := fault.Degradation
+		if !raise {
+			degradation = -degradation
+		}
+		return i.degrade(ctx, types.ECGI(fault.EntityID), degradation)
+	case E2Flap:
+		return i.setAgentDown(types.EnbID(fault.EntityID), raise)
+	case NodeCrash:
+		node, err := i.nodeStore.Get(ctx, types.EnbID(fault.EntityID))
+		if err != nil {
+			return err
+		}
+		if err := i.setAgentDown(node.EnbID, raise); err != nil {
+			return err
+		}
+		for _, ecgi := range node.Cells {
+			if err := i.setOutage(ctx, ecgi, raise); err != nil {
+				log.Warn(err)
+			}
+		}
+		return nil
+	}
+	return errors.New(errors.Invalid, "unknown fault type %d", fault.Type)
+}
+
+// hold counts the faults taking down the specified entity and returns true if the entity changes state, i.e. if the
+// first of its faults is raised or the last one cleared
+func (i *Injector) hold(counts map[uint64]int, entityID uint64, raise bool) bool {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	if raise {
+		counts[entityID]++
+		return counts[entityID] == 1
+	}
+	if counts[entityID] == 0 {
+		return false
+	}
+	counts[entityID]--
+	if counts[entityID] > 0 {
+		return false
+	}
+	delete(counts, entityID)
+	return true
+}
+
+func (i *Injector) setOutage(ctx context.Context, ecgi types.ECGI, outage bool) error {
+	cell, err := i.cellStore.Get(ctx, ecgi)
+	if err != nil {
+		return err
+	}
+	if !i.hold(i.outages, uint64(ecgi), outage) {
+		return nil
+	}
+	updated := *cell
+	updated.Outage = outage
+	if err := i.cellStore.Update(ctx, &updated); err != nil {
+		return err
+	}
+	if outage {
+		return i.handover.EvacuateCell(ctx, ecgi)
+	}
+	return nil
+}
+
+func (i *Injector) degrade(ctx context.Context, ecgi types.ECGI, degradation float64) error {
+	cell, err := i.cellStore.Get(ctx, ecgi)
+	if err != nil {
+		return err
+	}
+	updated := *cell
+	updated.TxPowerDB -= degradation
+	return i.cellStore.Update(ctx, &updated)
+}
+
+func (i *Injector) setAgentDown(enbID types.EnbID, down bool) error {
+	if i.agents == nil {
+		return errors.New(errors.Unavailable, "E2 agents are not available")
+	}
+	if !i.hold(i.agentsDown, uint64(enbID), down) {
+		return nil
+	}
+	if down {
+		return i.agents.StopAgent(enbID)
+	}
+	// Reconnecting may take a while; do not block the caller
+	go func() {
+		if err := i.agents.StartAgent(enbID); err != nil {
+			log.Warnf("Unable to restart E2 agent %d: %v", enbID, err)
+		}
+	}()
+	return nil
+}
@@ -0,0 +1,124 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package faults
+
+import (
+	"context"
+	"io/ioutil"
+	"testing"
+
+	"github.com/onosproject/onos-api/go/onos/ransim/types"
+	"github.com/onosproject/ran-simulator/pkg/mobility"
+	"github.com/onosproject/ran-simulator/pkg/model"
+	"github.com/onosproject/ran-simulator/pkg/store/cells"
+	"github.com/onosproject/ran-simulator/pkg/store/event"
+	"github.com/onosproject/ran-simulator/pkg/store/metrics"
+	"github.com/onosproject/ran-simulator/pkg/store/nodes"
+	"github.com/onosproject/ran-simulator/pkg/store/ues"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v2"
+)
+
+type testAgents struct {
+	stopped map[types.EnbID]bool
+}
+
+func (a *testAgents) StopAgent(enbID types.EnbID) error {
+	a.stopped[enbID] = true
+	return nil
+}
+
+func (a *testAgents) StartAgent(enbID types.EnbID) error {
+	a.stopped[enbID] = false
+	return nil
+}
+
+func TestInjector(t *testing.T) {
+	m := model.Model{}
+	bytes, err := ioutil.ReadFile("../model/test.yaml")
+	assert.NoError(t, err)
+	err = yaml.Unmarshal(bytes, &m)
+	assert.NoError(t, err)
+
+	ctx := context.Background()
+	nodeStore := nodes.NewNodeRegistry(m.Nodes)
+	cellStore := cells.NewCellRegistry(m.Cells, nodeStore)
+	ueStore := ues.NewUERegistry(0, cellStore)
+	metricStore := metrics.NewMetricsStore()
+	agents := &testAgents{stopped: make(map[types.EnbID]bool)}
+	injector := NewInjector(cellStore, nodeStore, metricStore, agents, mobility.NewHandoverEngine(cellStore, ueStore, metricStore))
+
+	ch := make(chan event.Event)
+	assert.NoError(t, injector.Watch(ctx, ch))
+
+	ecgi := types.ECGI(84325717505)
+	err = injector.Inject(ctx, &Fault{Type: CellOutage, EntityID: uint64(ecgi)})
+	assert.NoError(t, err)
+	faultEvent := <-ch
+	assert.Equal(t, Raised, faultEvent.Type)
+	fault := faultEvent.Value.(*Fault)
+	cell, err := cellStore.Get(ctx, ecgi)
+	assert.NoError(t, err)
+	assert.False(t, cell.InService())
+	_, ok := metricStore.Get(ctx, uint64(ecgi), "alarm.CellOutage")
+	assert.True(t, ok)
+
+	_, err = injector.Clear(ctx, fault.ID)
+	assert.NoError(t, err)
+	faultEvent = <-ch
+	assert.Equal(t, Cleared, faultEvent.Type)
+	cell, err = cellStore.Get(ctx, ecgi)
+	assert.NoError(t, err)
+	assert.True(t, cell.InService())
+	_, ok = metricStore.Get(ctx, uint64(ecgi), "alarm.CellOutage")
+	assert.False(t, ok)
+
+	// Overlapping outages keep the cell out of service until the last one is cleared
+	assert.NoError(t, injector.Inject(ctx, &Fault{Type: CellOutage, EntityID: uint64(ecgi)}))
+	first := (<-ch).Value.(*Fault)
+	assert.NoError(t, injector.Inject(ctx, &Fault{Type: CellOutage, EntityID: uint64(ecgi)}))
+	second := (<-ch).Value.(*Fault)
+	_, err = injector.Clear(ctx, first.ID)
+	assert.NoError(t, err)
+	<-ch
+	cell, err = cellStore.Get(ctx, ecgi)
+	assert.NoError(t, err)
+	assert.False(t, cell.InService())
+	_, ok = metricStore.Get(ctx, uint64(ecgi), "alarm.CellOutage")
+	assert.True(t, ok)
+	_, err = injector.Clear(ctx, second.ID)
+	assert.NoError(t, err)
+	<-ch
+	cell, err = cellStore.Get(ctx, ecgi)
+	assert.NoError(t, err)
+	assert.True(t, cell.InService())
+
+	err = injector.Inject(ctx, &Fault{Type: DegradedRSRP, EntityID: uint64(ecgi), Degradation: 6})
+	assert.NoError(t, err)
+	<-ch
+	cell, err = cellStore.Get(ctx, ecgi)
+	assert.NoError(t, err)
+	assert.Equal(t, -6.0, cell.TxPowerDB)
+	injector.ClearAll(ctx, DegradedRSRP, uint64(ecgi))
+	<-ch
+	cell, err = cellStore.Get(ctx, ecgi)
+	assert.NoError(t, err)
+	assert.Equal(t, 0.0, cell.TxPowerDB)
+
+	nodeList, err := nodeStore.List(ctx)
+	assert.NoError(t, err)
+	node := nodeList[0]
+	err = injector.Inject(ctx, &Fault{Type: NodeCrash, EntityID: uint64(node.EnbID)})
+	assert.NoError(t, err)
+	<-ch
+	assert.True(t, agents.stopped[node.EnbID])
+	cell, err = cellStore.Get(ctx, node.Cells[0])
+	assert.NoError(t, err)
+	assert.False(t, cell.InService())
+	assert.Len(t, injector.List(), 1)
+
+	_, err = injector.Clear(ctx, 1234)
+	assert.Error(t, err)
+}
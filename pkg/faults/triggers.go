@@ -0,0 +1,158 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package faults
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"strconv"
+	"time"
+
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/onosproject/ran-simulator/pkg/store/event"
+	"github.com/onosproject/ran-simulator/pkg/store/metrics"
+)
+
+// Entity attributes which inject faults on demand when set via the metrics API or RC control;
+// setting the attribute to zero or deleting it clears the fault
+const (
+	// OutageAttribute any non-zero value of this cell attribute takes the cell out of service
+	OutageAttribute = "fault.outage"
+	// RSRPAttribute non-zero value of this cell attribute degrades the cell transmit power by the given dB
+	RSRPAttribute = "fault.rsrp"
+	// E2FlapAttribute non-zero value of this node attribute drops the E2 connection for the given number of seconds
+	E2FlapAttribute = "fault.e2flap"
+	// CrashAttribute any non-zero value of this node attribute crashes the node
+	CrashAttribute = "fault.crash"
+
+	randomDegradation = 10.0
+)
+
+var attributeFaults = map[string]Type{
+	OutageAttribute: CellOutage,
+	RSRPAttribute:   DegradedRSRP,
+	E2FlapAttribute: E2Flap,
+	CrashAttribute:  NodeCrash,
+}
+
+// Start starts injecting faults on demand; if mtbf is non-zero, random faults are also injected
+// with exponentially distributed times between failures and times to repair with the given means
+func (i *Injector) Start(mtbf time.Duration, mttr time.Duration) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	ch := make(chan event.Event)
+	names := make([]string, 0, len(attributeFaults))
+	for name := range attributeFaults {
+		names = append(names, name)
+	}
+	if err := i.metricStore.Watch(ctx, ch, metrics.WatchOptions{Names: names}); err != nil {
+		cancel()
+		return err
+	}
+	i.cancel = cancel
+	go i.processMetricEvents(ch)
+	if mtbf > 0 {
+		log.Infof("Injecting random faults with MTBF %v and MTTR %v", mtbf, mttr)
+		go i.injectRandomFaults(ctx, mtbf, mttr)
+	}
+	return nil
+}
+
+// Stop stops injecting faults
+func (i *Injector) Stop() {
+	if i.cancel != nil {
+		i.cancel()
+	}
+}
+
+func (i *Injector) processMetricEvents(ch <-chan event.Event) {
+	ctx := context.Background()
+	for metricEvent := range ch {
+		key := metricEvent.Key.(metrics.Key)
+		faultType := attributeFaults[key.Name]
+		value := 0.0
+		if metricEvent.Type.(metrics.MetricEvent) == metrics.Updated {
+			value = toFloat(metricEvent.Value)
+		}
+
+		// Any previous fault raised by the attribute is superseded
+		i.ClearAll(ctx, faultType, key.EntityID)
+		if value == 0 {
+			continue
+		}
+		fault := &Fault{
+			Type:     faultType,
+			EntityID: key.EntityID,
+		}
+		switch faultType {
+		case DegradedRSRP:
+			fault.Degradation = value
+		case E2Flap:
+			fault.Duration = time.Duration(value * float64(time.Second))
+		}
+		if err := i.Inject(ctx, fault); err != nil {
+			log.Warnf("Unable to inject %s fault on entity %d: %v", faultType, key.EntityID, err)
+		}
+	}
+}
+
+func (i *Injector) injectRandomFaults(ctx context.Context, mtbf time.Duration, mttr time.Duration) {
+	for {
+		select {
+		case <-time.After(time.Duration(rand.ExpFloat64() * float64(mtbf))):
+			fault, err := i.randomFault(ctx, mttr)
+			if err != nil {
+				log.Warn(err)
+				continue
+			}
+			if err := i.Inject(ctx, fault); err != nil {
+				log.Warnf("Unable to inject random %s fault on entity %d: %v", fault.Type, fault.EntityID, err)
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (i *Injector) randomFault(ctx context.Context, mttr time.Duration) (*Fault, error) {
+	fault := &Fault{
+		Type:     Type(rand.Intn(int(NodeCrash) + 1)),
+		Duration: time.Duration(rand.ExpFloat64() * float64(mttr)),
+	}
+	// Make sure random faults always clear
+	if fault.Duration <= 0 {
+		fault.Duration = time.Second
+	}
+	switch fault.Type {
+	case CellOutage, DegradedRSRP:
+		cell, err := i.cellStore.GetRandomCell()
+		if err != nil {
+			return nil, err
+		}
+		fault.EntityID = uint64(cell.ECGI)
+		fault.Degradation = randomDegradation
+	case E2Flap, NodeCrash:
+		nodeList, err := i.nodeStore.List(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if len(nodeList) == 0 {
+			return nil, errors.New(errors.NotFound, "no nodes available for %s fault", fault.Type)
+		}
+		fault.EntityID = uint64(nodeList[rand.Intn(len(nodeList))].EnbID)
+	}
+	return fault, nil
+}
+
+func toFloat(value interface{}) float64 {
+	f, err := strconv.ParseFloat(fmt.Sprintf("%v", value), 64)
+	if err != nil {
+		if metrics.IsSet(value) {
+			return 1
+		}
+		return 0
+	}
+	return f
+}
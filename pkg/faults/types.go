@@ -0,0 +1,58 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package faults
+
+import (
+	"time"
+)
+
+// Type is a type of fault
+type Type int
+
+const (
+	// CellOutage cell goes out of service
+	CellOutage Type = iota
+	// DegradedRSRP cell coverage degrades, e.g. due to an antenna tilt fault
+	DegradedRSRP
+	// E2Flap E2 connection of the node drops and is re-established
+	E2Flap
+	// NodeCrash node crashes, taking down its E2 connection and all of its cells
+	NodeCrash
+)
+
+func (t Type) String() string {
+	return [...]string{"CellOutage", "DegradedRSRP", "E2Flap", "NodeCrash"}[t]
+}
+
+// Fault represents an injected fault
+type Fault struct {
+	ID   uint64
+	Type Type
+	// EntityID ECGI of the affected cell or EnbID of the affected node
+	EntityID uint64
+	// Duration after which the fault clears automatically; zero means the fault persists until cleared
+	Duration time.Duration
+	// Degradation reduction of the cell transmit power in dB; applies to DegradedRSRP only
+	Degradation float64
+	Raised      time.Time
+	// labelID is the ID of the ground-truth label of the fault
+	labelID uint64
+}
+
+// FaultEvent is a type of fault event
+type FaultEvent int
+
+const (
+	// None none fault event
+	None FaultEvent = iota
+	// Raised fault raised event
+	Raised
+	// Cleared fault cleared event
+	Cleared
+)
+
+func (e FaultEvent) String() string {
+	return [...]string{"None", "Raised", "Cleared"}[e]
+}
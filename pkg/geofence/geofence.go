@@ -0,0 +1,183 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package geofence
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/onosproject/onos-api/go/onos/ransim/types"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/onosproject/onos-lib-go/pkg/logging"
+	"github.com/onosproject/ran-simulator/pkg/journal"
+	"github.com/onosproject/ran-simulator/pkg/model"
+	"github.com/onosproject/ran-simulator/pkg/store/event"
+	"github.com/onosproject/ran-simulator/pkg/store/metrics"
+	"github.com/onosproject/ran-simulator/pkg/store/ues"
+)
+
+var log = logging.GetLogger("geofence")
+
+// EnterMetric returns the name of the per-cell counter of UEs served by the cell entering the named geofence,
+// e.g. GEO.Enter.stadium
+func EnterMetric(name string) string {
+	return fmt.Sprintf("GEO.Enter.%s", name)
+}
+
+// LeaveMetric returns the name of the per-cell counter of UEs served by the cell leaving the named geofence
+func LeaveMetric(name string) string {
+	return fmt.Sprintf("GEO.Leave.%s", name)
+}
+
+// Controller tracks the UEs entering and leaving the geofences of the model as they move, recording the transitions
+// in the journal and, if so configured, counting them by the metrics of the serving cells of the UEs
+type Controller struct {
+	geofences   map[string]model.Geofence
+	names       []string
+	ueStore     ues.Store
+	metricStore metrics.Store
+	inside      map[types.IMSI]map[string]bool
+	mu          sync.Mutex
+	cancel      context.CancelFunc
+}
+
+// NewController creates a new geofence controller
+func NewController(geofences map[string]model.Geofence, ueStore ues.Store, metricStore metrics.Store) *Controller {
+	names := make([]string, 0, len(geofences))
+	for name := range geofences {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return &Controller{
+		geofences:   geofences,
+		names:       names,
+		ueStore:     ueStore,
+		metricStore: metricStore,
+		inside:      make(map[types.IMSI]map[string]bool),
+	}
+}
+
+// Validate returns an error if any geofence is not a polygon
+func (c *Controller) Validate() error {
+	for _, name := range c.names {
+		if len(c.geofences[name].Polygon) < 3 {
+			return errors.New(errors.Invalid, "geofence %s must have at least 3 vertices", name)
+		}
+	}
+	return nil
+}
+
+// Counters lists the names of the metrics counting the UEs entering and leaving geofences
+func (c *Controller) Counters() []string {
+	var counters []string
+	for _, name := range c.names {
+		if c.geofences[name].Counters {
+			counters = append(counters, EnterMetric(name), LeaveMetric(name))
+		}
+	}
+	return counters
+}
+
+// Start starts tracking the UEs; nothing is tracked if there are no geofences
+func (c *Controller) Start() error {
+	if err := c.Validate(); err != nil {
+		return err
+	}
+	if len(c.geofences) == 0 {
+		return nil
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	ch := make(chan event.Event)
+	if err := c.ueStore.Watch(ctx, ch, ues.WatchOptions{Replay: true}); err != nil {
+		cancel()
+		return err
+	}
+	c.cancel = cancel
+	go c.processUEEvents(ch)
+	return nil
+}
+
+// Stop stops tracking the UEs
+func (c *Controller) Stop() {
+	if c.cancel != nil {
+		c.cancel()
+	}
+}
+
+func (c *Controller) processUEEvents(ch <-chan event.Event) {
+	ctx := context.Background()
+	for ueEvent := range ch {
+		ue, ok := ueEvent.Value.(*model.UE)
+		if !ok {
+			continue
+		}
+		if ueEvent.Type == ues.Deleted {
+			c.forget(ue.IMSI)
+			continue
+		}
+		c.update(ctx, ue)
+	}
+}
+
+// forget drops the state of a deleted UE without recording it as leaving its geofences
+func (c *Controller) forget(imsi types.IMSI) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.inside, imsi)
+}
+
+// update checks whether the UE entered or left any geofence since its last update; the initial position of a UE
+// only establishes which geofences it is in
+func (c *Controller) update(ctx context.Context, ue *model.UE) {
+	c.mu.Lock()
+	inside, known := c.inside[ue.IMSI]
+	if !known {
+		inside = make(map[string]bool)
+		c.inside[ue.IMSI] = inside
+	}
+	var entered, left []string
+	for _, name := range c.names {
+		geofence := c.geofences[name]
+		now := geofence.Contains(ue.Location)
+		if now == inside[name] {
+			continue
+		}
+		inside[name] = now
+		if !known {
+			continue
+		}
+		if now {
+			entered = append(entered, name)
+		} else {
+			left = append(left, name)
+		}
+	}
+	c.mu.Unlock()
+
+	var ecgi types.ECGI
+	if ue.Cell != nil {
+		ecgi = ue.Cell.ECGI
+	}
+	for _, name := range entered {
+		log.Debugf("UE %d entered geofence %s", ue.IMSI, name)
+		journal.Record(journal.GeofenceEntered, uint64(ue.IMSI), map[string]interface{}{"geofence": name, "ecgi": ecgi})
+		c.count(ctx, name, ecgi, EnterMetric(name))
+	}
+	for _, name := range left {
+		log.Debugf("UE %d left geofence %s", ue.IMSI, name)
+		journal.Record(journal.GeofenceLeft, uint64(ue.IMSI), map[string]interface{}{"geofence": name, "ecgi": ecgi})
+		c.count(ctx, name, ecgi, LeaveMetric(name))
+	}
+}
+
+// count increments the counter of the serving cell if counters are enabled for the geofence
+func (c *Controller) count(ctx context.Context, geofence string, ecgi types.ECGI, name string) {
+	if !c.geofences[geofence].Counters || ecgi == 0 {
+		return
+	}
+	_, _ = c.metricStore.Add(ctx, uint64(ecgi), name, 1)
+}
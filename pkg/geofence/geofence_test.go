@@ -0,0 +1,70 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package geofence
+
+import (
+	"context"
+	"io/ioutil"
+	"testing"
+
+	"github.com/onosproject/ran-simulator/pkg/model"
+	"github.com/onosproject/ran-simulator/pkg/store/cells"
+	"github.com/onosproject/ran-simulator/pkg/store/metrics"
+	"github.com/onosproject/ran-simulator/pkg/store/nodes"
+	"github.com/onosproject/ran-simulator/pkg/store/ues"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v2"
+)
+
+var stadium = model.Geofence{
+	Polygon: []model.Coordinate{{Lat: 46.0, Lng: 29.0}, {Lat: 46.0, Lng: 29.01}, {Lat: 46.01, Lng: 29.01}, {Lat: 46.01, Lng: 29.0}},
+}
+
+func TestContains(t *testing.T) {
+	assert.True(t, stadium.Contains(model.Coordinate{Lat: 46.005, Lng: 29.005}))
+	assert.False(t, stadium.Contains(model.Coordinate{Lat: 46.02, Lng: 29.005}))
+	assert.False(t, stadium.Contains(model.Coordinate{Lat: 46.005, Lng: 28.99}))
+
+	triangle := model.Geofence{Polygon: []model.Coordinate{{Lat: 0, Lng: 0}, {Lat: 0, Lng: 2}, {Lat: 2, Lng: 0}}}
+	assert.True(t, triangle.Contains(model.Coordinate{Lat: 0.5, Lng: 0.5}))
+	assert.False(t, triangle.Contains(model.Coordinate{Lat: 1.5, Lng: 1.5}))
+}
+
+func TestController(t *testing.T) {
+	ctx := context.Background()
+	m := model.Model{}
+	bytes, err := ioutil.ReadFile("../model/test.yaml")
+	assert.NoError(t, err)
+	assert.NoError(t, yaml.Unmarshal(bytes, &m))
+	ueStore := ues.NewUERegistry(1, cells.NewCellRegistry(m.Cells, nodes.NewNodeRegistry(m.Nodes)))
+	metricStore := metrics.NewMetricsStore()
+
+	counted := stadium
+	counted.Counters = true
+	controller := NewController(map[string]model.Geofence{"stadium": counted, "plain": stadium}, ueStore, metricStore)
+	assert.Equal(t, []string{"GEO.Enter.stadium", "GEO.Leave.stadium"}, controller.Counters())
+	assert.Error(t, NewController(map[string]model.Geofence{"line": {Polygon: stadium.Polygon[:2]}}, ueStore, metricStore).Validate())
+
+	ue := ueStore.ListAllUEs(ctx)[0]
+	assert.NoError(t, ueStore.MoveToCoordinate(ctx, ue.IMSI, model.Coordinate{Lat: 45.0, Lng: 29.0}, 0))
+
+	// The initial position only establishes the geofences the UE is in
+	controller.update(ctx, ue)
+	_, ok := metricStore.Get(ctx, uint64(ue.Cell.ECGI), EnterMetric("stadium"))
+	assert.False(t, ok)
+
+	// Entering and leaving are counted by the serving cell for geofences with counters only
+	ue.Location = model.Coordinate{Lat: 46.005, Lng: 29.005}
+	controller.update(ctx, ue)
+	ue.Location = model.Coordinate{Lat: 46.02, Lng: 29.005}
+	controller.update(ctx, ue)
+	controller.update(ctx, ue)
+	count, _ := metricStore.Get(ctx, uint64(ue.Cell.ECGI), EnterMetric("stadium"))
+	assert.Equal(t, uint64(1), count)
+	count, _ = metricStore.Get(ctx, uint64(ue.Cell.ECGI), LeaveMetric("stadium"))
+	assert.Equal(t, uint64(1), count)
+	_, ok = metricStore.Get(ctx, uint64(ue.Cell.ECGI), EnterMetric("plain"))
+	assert.False(t, ok)
+}
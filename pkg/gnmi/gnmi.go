@@ -0,0 +1,329 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package gnmi
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	liblog "github.com/onosproject/onos-lib-go/pkg/logging"
+	service "github.com/onosproject/onos-lib-go/pkg/northbound"
+	"github.com/onosproject/ran-simulator/pkg/store/cells"
+	"github.com/onosproject/ran-simulator/pkg/store/metrics"
+	"github.com/onosproject/ran-simulator/pkg/store/nodes"
+	"github.com/onosproject/ran-simulator/pkg/store/ues"
+	gnmiapi "github.com/openconfig/gnmi/proto/gnmi"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+)
+
+var log = liblog.GetLogger("gnmi")
+
+const (
+	gnmiVersion = "0.7.0"
+
+	defaultSampleInterval = time.Second
+	minSampleInterval     = 100 * time.Millisecond
+	// changeInterval is the period at which the state is checked for changes reported by on-change subscriptions
+	changeInterval = time.Second
+)
+
+// NewService returns a new gNMI telemetry Service
+func NewService(nodeStore nodes.Store, cellStore cells.Store, ueStore ues.Store, metricStore metrics.Store) service.Service {
+	return &Service{
+		nodeStore:   nodeStore,
+		cellStore:   cellStore,
+		ueStore:     ueStore,
+		metricStore: metricStore,
+	}
+}
+
+// Service is a Service implementation for the gNMI telemetry of the simulator state
+type Service struct {
+	service.Service
+	nodeStore   nodes.Store
+	cellStore   cells.Store
+	ueStore     ues.Store
+	metricStore metrics.Store
+}
+
+// Register registers the gNMI Service with the gRPC server.
+func (s *Service) Register(r *grpc.Server) {
+	gnmiapi.RegisterGNMIServer(r, &Server{
+		nodeStore:   s.nodeStore,
+		cellStore:   s.cellStore,
+		ueStore:     s.ueStore,
+		metricStore: s.metricStore,
+	})
+}
+
+// Server implements the gNMI service exposing the read-only state of the simulated nodes, cells and UEs
+type Server struct {
+	nodeStore   nodes.Store
+	cellStore   cells.Store
+	ueStore     ues.Store
+	metricStore metrics.Store
+}
+
+// Capabilities returns the schema model and the encodings supported by the server
+func (s *Server) Capabilities(ctx context.Context, request *gnmiapi.CapabilityRequest) (*gnmiapi.CapabilityResponse, error) {
+	return &gnmiapi.CapabilityResponse{
+		SupportedModels: []*gnmiapi.ModelData{{
+			Name:         ModelName,
+			Organization: ModelOrganization,
+			Version:      ModelVersion,
+		}},
+		SupportedEncodings: []gnmiapi.Encoding{gnmiapi.Encoding_PROTO},
+		GNMIVersion:        gnmiVersion,
+	}, nil
+}
+
+// Get returns a snapshot of the state leaves matching the requested paths
+func (s *Server) Get(ctx context.Context, request *gnmiapi.GetRequest) (*gnmiapi.GetResponse, error) {
+	log.Debugf("Received gNMI get request: %v", request)
+	if request.Type == gnmiapi.GetRequest_CONFIG {
+		return nil, errors.Status(errors.New(errors.NotSupported, "simulator state is not configurable")).Err()
+	}
+	leaves, err := s.collect(ctx)
+	if err != nil {
+		return nil, errors.Status(err).Err()
+	}
+	paths := request.Path
+	if len(paths) == 0 {
+		paths = []*gnmiapi.Path{{}}
+	}
+	response := &gnmiapi.GetResponse{}
+	for _, path := range paths {
+		pattern := join(request.Prefix, path)
+		response.Notification = append(response.Notification, notification(matching(leaves, pattern)))
+	}
+	return response, nil
+}
+
+// Set is not supported as the simulator state is read-only; configuration is done via O1
+func (s *Server) Set(ctx context.Context, request *gnmiapi.SetRequest) (*gnmiapi.SetResponse, error) {
+	return nil, errors.Status(errors.New(errors.NotSupported, "simulator state is read-only")).Err()
+}
+
+// Subscribe streams the state leaves matching the subscriptions once, on each poll or, for streaming
+// subscriptions, periodically or whenever they change
+func (s *Server) Subscribe(stream gnmiapi.GNMI_SubscribeServer) error {
+	request, err := stream.Recv()
+	if err != nil {
+		return err
+	}
+	log.Debugf("Received gNMI subscribe request: %v", request)
+	list := request.GetSubscribe()
+	if list == nil {
+		return errors.Status(errors.New(errors.Invalid, "first request must be a subscription list")).Err()
+	}
+	subs := make([]*subscription, 0, len(list.Subscription))
+	for _, sub := range list.Subscription {
+		subs = append(subs, newSubscription(list.Prefix, sub))
+	}
+	if len(subs) == 0 {
+		subs = append(subs, newSubscription(list.Prefix, &gnmiapi.Subscription{}))
+	}
+
+	switch list.Mode {
+	case gnmiapi.SubscriptionList_ONCE:
+		return s.sendAll(stream, subs)
+	case gnmiapi.SubscriptionList_POLL:
+		return s.poll(stream, subs)
+	default:
+		return s.stream(stream, subs, list.UpdatesOnly)
+	}
+}
+
+// sendAll sends the current values of all leaves matching the subscriptions followed by a sync response
+func (s *Server) sendAll(stream gnmiapi.GNMI_SubscribeServer, subs []*subscription) error {
+	leaves, err := s.collect(stream.Context())
+	if err != nil {
+		return errors.Status(err).Err()
+	}
+	for _, sub := range subs {
+		sub.update(leaves)
+		if err := sendNotification(stream, notification(matching(leaves, sub.pattern))); err != nil {
+			return err
+		}
+	}
+	return sendSync(stream)
+}
+
+func (s *Server) poll(stream gnmiapi.GNMI_SubscribeServer, subs []*subscription) error {
+	if err := s.sendAll(stream, subs); err != nil {
+		return err
+	}
+	for {
+		request, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		if request.GetPoll() == nil {
+			return errors.Status(errors.New(errors.Invalid, "only poll requests are allowed in poll mode")).Err()
+		}
+		if err := s.sendAll(stream, subs); err != nil {
+			return err
+		}
+	}
+}
+
+func (s *Server) stream(stream gnmiapi.GNMI_SubscribeServer, subs []*subscription, updatesOnly bool) error {
+	ctx, cancel := context.WithCancel(stream.Context())
+	defer cancel()
+	go func() {
+		// Streaming subscriptions last until the client closes the stream
+		for {
+			if _, err := stream.Recv(); err != nil {
+				cancel()
+				return
+			}
+		}
+	}()
+
+	if updatesOnly {
+		leaves, err := s.collect(ctx)
+		if err != nil {
+			return errors.Status(err).Err()
+		}
+		for _, sub := range subs {
+			sub.update(leaves)
+		}
+		if err := sendSync(stream); err != nil {
+			return err
+		}
+	} else if err := s.sendAll(stream, subs); err != nil {
+		return err
+	}
+
+	interval := changeInterval
+	for _, sub := range subs {
+		if sub.sampleInterval > 0 && sub.sampleInterval < interval {
+			interval = sub.sampleInterval
+		}
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case now := <-ticker.C:
+			leaves, err := s.collect(ctx)
+			if err != nil {
+				return errors.Status(err).Err()
+			}
+			for _, sub := range subs {
+				if n := sub.next(leaves, now); n != nil {
+					if err := sendNotification(stream, n); err != nil {
+						return err
+					}
+				}
+			}
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+// subscription tracks the state of a single subscription of a subscription list
+type subscription struct {
+	pattern *gnmiapi.Path
+	// sampleInterval is the period of sampled subscriptions; zero for on-change subscriptions
+	sampleInterval    time.Duration
+	suppressRedundant bool
+	nextSample        time.Time
+	// last holds the leaves last sent, keyed by their path
+	last map[string]*leaf
+}
+
+func newSubscription(prefix *gnmiapi.Path, sub *gnmiapi.Subscription) *subscription {
+	result := &subscription{
+		pattern:           join(prefix, sub.Path),
+		suppressRedundant: sub.SuppressRedundant,
+		last:              make(map[string]*leaf),
+	}
+	if sub.Mode == gnmiapi.SubscriptionMode_SAMPLE {
+		result.sampleInterval = time.Duration(sub.SampleInterval)
+		if result.sampleInterval <= 0 {
+			result.sampleInterval = defaultSampleInterval
+		} else if result.sampleInterval < minSampleInterval {
+			result.sampleInterval = minSampleInterval
+		}
+	}
+	return result
+}
+
+// update records the given leaves as sent
+func (s *subscription) update(leaves []*leaf) {
+	s.nextSample = time.Now().Add(s.sampleInterval)
+	s.last = make(map[string]*leaf)
+	for _, l := range matching(leaves, s.pattern) {
+		s.last[pathString(l.path)] = l
+	}
+}
+
+// next returns the notification due at the given time, if any; sampled subscriptions report all matching leaves
+// once per interval, unless redundant values are suppressed, while on-change subscriptions report changed and deleted leaves
+func (s *subscription) next(leaves []*leaf, now time.Time) *gnmiapi.Notification {
+	if s.sampleInterval > 0 {
+		if now.Before(s.nextSample) {
+			return nil
+		}
+		s.nextSample = now.Add(s.sampleInterval)
+	}
+
+	current := matching(leaves, s.pattern)
+	n := notification(nil)
+	present := make(map[string]bool)
+	for _, l := range current {
+		key := pathString(l.path)
+		present[key] = true
+		last, ok := s.last[key]
+		if s.sampleInterval > 0 && !s.suppressRedundant || !ok || !proto.Equal(last.value, l.value) {
+			n.Update = append(n.Update, &gnmiapi.Update{Path: l.path, Val: l.value})
+		}
+		s.last[key] = l
+	}
+	for key, l := range s.last {
+		if !present[key] {
+			n.Delete = append(n.Delete, l.path)
+			delete(s.last, key)
+		}
+	}
+	if len(n.Update) == 0 && len(n.Delete) == 0 {
+		return nil
+	}
+	return n
+}
+
+// matching returns the leaves matching the given pattern
+func matching(leaves []*leaf, pattern *gnmiapi.Path) []*leaf {
+	result := make([]*leaf, 0)
+	for _, l := range leaves {
+		if matches(pattern, l.path) {
+			result = append(result, l)
+		}
+	}
+	return result
+}
+
+func notification(leaves []*leaf) *gnmiapi.Notification {
+	n := &gnmiapi.Notification{Timestamp: time.Now().UnixNano()}
+	for _, l := range leaves {
+		n.Update = append(n.Update, &gnmiapi.Update{Path: l.path, Val: l.value})
+	}
+	return n
+}
+
+func sendNotification(stream gnmiapi.GNMI_SubscribeServer, n *gnmiapi.Notification) error {
+	return stream.Send(&gnmiapi.SubscribeResponse{Response: &gnmiapi.SubscribeResponse_Update{Update: n}})
+}
+
+func sendSync(stream gnmiapi.GNMI_SubscribeServer) error {
+	return stream.Send(&gnmiapi.SubscribeResponse{Response: &gnmiapi.SubscribeResponse_SyncResponse{SyncResponse: true}})
+}
@@ -0,0 +1,180 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package gnmi
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/onosproject/ran-simulator/pkg/model"
+	"github.com/onosproject/ran-simulator/pkg/store/cells"
+	"github.com/onosproject/ran-simulator/pkg/store/metrics"
+	"github.com/onosproject/ran-simulator/pkg/store/nodes"
+	"github.com/onosproject/ran-simulator/pkg/store/ues"
+	gnmiapi "github.com/openconfig/gnmi/proto/gnmi"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func newTestClient(t *testing.T) (gnmiapi.GNMIClient, metrics.Store) {
+	m := &model.Model{}
+	assert.NoError(t, model.LoadConfig(m, "../model/test"))
+	nodeStore := nodes.NewNodeRegistry(m.Nodes)
+	cellStore := cells.NewCellRegistry(m.Cells, nodeStore)
+	ueStore := ues.NewUERegistry(m.UECount, cellStore)
+	metricStore := metrics.NewMetricsStore()
+
+	lis := bufconn.Listen(1024 * 1024)
+	server := grpc.NewServer()
+	NewService(nodeStore, cellStore, ueStore, metricStore).Register(server)
+	go func() {
+		_ = server.Serve(lis)
+	}()
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.DialContext(context.Background(), "bufnet", grpc.WithInsecure(),
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) {
+			return lis.Dial()
+		}))
+	assert.NoError(t, err)
+	return gnmiapi.NewGNMIClient(conn), metricStore
+}
+
+func path(elems ...*gnmiapi.PathElem) *gnmiapi.Path {
+	return &gnmiapi.Path{Elem: elems}
+}
+
+func TestGet(t *testing.T) {
+	ctx := context.Background()
+	client, _ := newTestClient(t)
+
+	capabilities, err := client.Capabilities(ctx, &gnmiapi.CapabilityRequest{})
+	assert.NoError(t, err)
+	assert.Equal(t, ModelName, capabilities.SupportedModels[0].Name)
+
+	// UE counts of all cells add up to the total number of UEs
+	response, err := client.Get(ctx, &gnmiapi.GetRequest{
+		Prefix: path(&gnmiapi.PathElem{Name: ModelName}),
+		Path: []*gnmiapi.Path{
+			path(&gnmiapi.PathElem{Name: "cells"}, &gnmiapi.PathElem{Name: "cell", Key: map[string]string{"ecgi": "*"}},
+				&gnmiapi.PathElem{Name: "state"}, &gnmiapi.PathElem{Name: "ue-count"}),
+			path(&gnmiapi.PathElem{Name: "ues"}, &gnmiapi.PathElem{Name: "state"}, &gnmiapi.PathElem{Name: "total"}),
+		},
+	})
+	assert.NoError(t, err)
+	assert.Len(t, response.Notification, 2)
+	assert.Len(t, response.Notification[0].Update, 4)
+	var count uint64
+	for _, update := range response.Notification[0].Update {
+		count += update.Val.GetUintVal()
+	}
+	assert.Len(t, response.Notification[1].Update, 1)
+	assert.Equal(t, count, response.Notification[1].Update[0].Val.GetUintVal())
+
+	// Keys and multi-level wildcards select the leaves of individual entities
+	response, err = client.Get(ctx, &gnmiapi.GetRequest{
+		Path: []*gnmiapi.Path{path(&gnmiapi.PathElem{Name: ModelName}, &gnmiapi.PathElem{Name: "..."},
+			&gnmiapi.PathElem{Name: "node", Key: map[string]string{"enb-id": "144470"}}, &gnmiapi.PathElem{Name: "..."},
+			&gnmiapi.PathElem{Name: "status"})},
+	})
+	assert.NoError(t, err)
+	assert.Len(t, response.Notification[0].Update, 1)
+	assert.Equal(t, "/ransim/nodes/node[enb-id=144470]/state/status", pathString(response.Notification[0].Update[0].Path))
+
+	// UEs are listed by IMSI
+	response, err = client.Get(ctx, &gnmiapi.GetRequest{
+		Path: []*gnmiapi.Path{path(&gnmiapi.PathElem{Name: ModelName}, &gnmiapi.PathElem{Name: "ues"},
+			&gnmiapi.PathElem{Name: "ue"}, &gnmiapi.PathElem{Name: "state"}, &gnmiapi.PathElem{Name: "speed"})},
+	})
+	assert.NoError(t, err)
+	assert.Len(t, response.Notification[0].Update, int(count))
+
+	_, err = client.Set(ctx, &gnmiapi.SetRequest{})
+	assert.Error(t, err)
+}
+
+func TestSubscribe(t *testing.T) {
+	ctx := context.Background()
+	client, metricStore := newTestClient(t)
+	metricPath := path(&gnmiapi.PathElem{Name: ModelName}, &gnmiapi.PathElem{Name: "cells"},
+		&gnmiapi.PathElem{Name: "cell", Key: map[string]string{"ecgi": "84325717505"}},
+		&gnmiapi.PathElem{Name: "metrics"}, &gnmiapi.PathElem{Name: "metric", Key: map[string]string{"name": "RRC.Conn.Avg"}})
+	assert.NoError(t, metricStore.Set(ctx, 84325717505, "RRC.Conn.Avg", uint64(1)))
+
+	// Once subscriptions report the current values followed by a sync response
+	stream, err := client.Subscribe(ctx)
+	assert.NoError(t, err)
+	assert.NoError(t, stream.Send(&gnmiapi.SubscribeRequest{Request: &gnmiapi.SubscribeRequest_Subscribe{
+		Subscribe: &gnmiapi.SubscriptionList{
+			Mode:         gnmiapi.SubscriptionList_ONCE,
+			Subscription: []*gnmiapi.Subscription{{Path: metricPath}},
+		},
+	}}))
+	response, err := stream.Recv()
+	assert.NoError(t, err)
+	assert.Len(t, response.GetUpdate().Update, 1)
+	assert.Equal(t, uint64(1), response.GetUpdate().Update[0].Val.GetUintVal())
+	response, err = stream.Recv()
+	assert.NoError(t, err)
+	assert.True(t, response.GetSyncResponse())
+
+	// On-change subscriptions report changed values
+	stream, err = client.Subscribe(ctx)
+	assert.NoError(t, err)
+	assert.NoError(t, stream.Send(&gnmiapi.SubscribeRequest{Request: &gnmiapi.SubscribeRequest_Subscribe{
+		Subscribe: &gnmiapi.SubscriptionList{
+			Mode:         gnmiapi.SubscriptionList_STREAM,
+			UpdatesOnly:  true,
+			Subscription: []*gnmiapi.Subscription{{Path: metricPath, Mode: gnmiapi.SubscriptionMode_ON_CHANGE}},
+		},
+	}}))
+	response, err = stream.Recv()
+	assert.NoError(t, err)
+	assert.True(t, response.GetSyncResponse())
+	assert.NoError(t, metricStore.Set(ctx, 84325717505, "RRC.Conn.Avg", uint64(2)))
+	response, err = stream.Recv()
+	assert.NoError(t, err)
+	assert.Len(t, response.GetUpdate().Update, 1)
+	assert.Equal(t, uint64(2), response.GetUpdate().Update[0].Val.GetUintVal())
+
+	// Deleted values are reported as deletes
+	assert.NoError(t, metricStore.Delete(ctx, 84325717505, "RRC.Conn.Avg"))
+	response, err = stream.Recv()
+	assert.NoError(t, err)
+	assert.Len(t, response.GetUpdate().Delete, 1)
+	assert.NoError(t, stream.CloseSend())
+}
+
+func TestMatches(t *testing.T) {
+	leafPath := newLeaf([]*gnmiapi.PathElem{{Name: ModelName}, {Name: "cells"},
+		{Name: "cell", Key: map[string]string{"ecgi": "1"}}, {Name: "state"}}, "ue-count", uint64(0)).path
+	assert.True(t, matches(path(), leafPath))
+	assert.True(t, matches(path(&gnmiapi.PathElem{Name: ModelName}, &gnmiapi.PathElem{Name: "cells"}), leafPath))
+	assert.True(t, matches(path(&gnmiapi.PathElem{Name: "*"}, &gnmiapi.PathElem{Name: "cells"},
+		&gnmiapi.PathElem{Name: "cell"}), leafPath))
+	assert.False(t, matches(path(&gnmiapi.PathElem{Name: ModelName}, &gnmiapi.PathElem{Name: "cells"},
+		&gnmiapi.PathElem{Name: "cell", Key: map[string]string{"ecgi": "2"}}), leafPath))
+	assert.True(t, matches(path(&gnmiapi.PathElem{Name: "..."}, &gnmiapi.PathElem{Name: "ue-count"}), leafPath))
+	assert.False(t, matches(path(&gnmiapi.PathElem{Name: "..."}, &gnmiapi.PathElem{Name: "tac"}), leafPath))
+}
+
+func TestNodeLeaves(t *testing.T) {
+	node := &model.Node{
+		EnbID:       144470,
+		Controllers: []string{"e2t-1"},
+		Connections: map[string]model.Connection{"e2t-1": {State: model.ConnectionReconnecting, Since: time.Unix(1, 0)}},
+	}
+	leaves := nodeLeaves(node)
+	values := make(map[string]*gnmiapi.TypedValue, len(leaves))
+	for _, leaf := range leaves {
+		values[pathString(leaf.path)] = leaf.value
+	}
+	connectionPath := "/ransim/nodes/node[enb-id=144470]/connections/connection[controller=e2t-1]/state/"
+	assert.Equal(t, "Reconnecting", values[connectionPath+"state"].GetStringVal())
+	assert.Equal(t, int64(time.Second), values[connectionPath+"since"].GetIntVal())
+}
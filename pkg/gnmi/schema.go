@@ -0,0 +1,321 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package gnmi
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/onosproject/ran-simulator/pkg/model"
+	"github.com/onosproject/ran-simulator/pkg/radio"
+	gnmiapi "github.com/openconfig/gnmi/proto/gnmi"
+)
+
+// The simulator state is exposed as an OpenConfig-like tree of read-only state leaves:
+//
+//	/ransim/nodes/node[enb-id=<enbID>]/state/...
+//	/ransim/nodes/node[enb-id=<enbID>]/connections/connection[controller=<id>]/state/...
+//	/ransim/cells/cell[ecgi=<ecgi>]/state/...
+//	/ransim/cells/cell[ecgi=<ecgi>]/metrics/metric[name=<name>]/state/value
+//	/ransim/ues/state/...
+//	/ransim/ues/ue[imsi=<imsi>]/state/...
+const (
+	// ModelName is the name of the schema model supported by the server
+	ModelName = "ransim"
+	// ModelOrganization is the organization publishing the schema model
+	ModelOrganization = "Open Networking Foundation"
+	// ModelVersion is the version of the schema model
+	ModelVersion = "0.1.0"
+
+	// wildcard matches any element name or key value
+	wildcard = "*"
+	// multiLevelWildcard matches any number of elements
+	multiLevelWildcard = "..."
+)
+
+// leaf is a single value of the state tree
+type leaf struct {
+	path  *gnmiapi.Path
+	value *gnmiapi.TypedValue
+}
+
+// collect returns all the leaves of the state tree in a deterministic order
+func (s *Server) collect(ctx context.Context) ([]*leaf, error) {
+	leaves := make([]*leaf, 0)
+
+	nodeList, err := s.nodeStore.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(nodeList, func(i, j int) bool {
+		return nodeList[i].EnbID < nodeList[j].EnbID
+	})
+	for _, node := range nodeList {
+		leaves = append(leaves, nodeLeaves(node)...)
+	}
+
+	cellList, err := s.cellStore.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(cellList, func(i, j int) bool {
+		return cellList[i].ECGI < cellList[j].ECGI
+	})
+	states := make(map[model.RrcState]uint64)
+	for _, cell := range cellList {
+		ueList := s.ueStore.ListUEs(ctx, cell.ECGI)
+		var connected uint64
+		for _, ue := range ueList {
+			if ue.RrcState == model.RrcConnected {
+				connected++
+			}
+		}
+		leaves = append(leaves, cellLeaves(cell, uint64(len(ueList)), connected)...)
+		leaves = append(leaves, s.metricLeaves(ctx, cell)...)
+	}
+
+	ueList := s.ueStore.ListAllUEs(ctx)
+	sort.Slice(ueList, func(i, j int) bool {
+		return ueList[i].IMSI < ueList[j].IMSI
+	})
+	for _, ue := range ueList {
+		states[ue.RrcState]++
+	}
+	uesPath := []*gnmiapi.PathElem{{Name: ModelName}, {Name: "ues"}, {Name: "state"}}
+	leaves = append(leaves,
+		newLeaf(uesPath, "total", uint64(len(ueList))),
+		newLeaf(uesPath, "connected", states[model.RrcConnected]),
+		newLeaf(uesPath, "inactive", states[model.RrcInactive]),
+		newLeaf(uesPath, "idle", states[model.RrcIdle]))
+	for _, ue := range ueList {
+		leaves = append(leaves, ueLeaves(ue)...)
+	}
+	return leaves, nil
+}
+
+func nodeLeaves(node *model.Node) []*leaf {
+	statePath := []*gnmiapi.PathElem{
+		{Name: ModelName},
+		{Name: "nodes"},
+		{Name: "node", Key: map[string]string{"enb-id": strconv.FormatUint(uint64(node.EnbID), 10)}},
+		{Name: "state"},
+	}
+	cells := make([]interface{}, 0, len(node.Cells))
+	for _, ecgi := range node.Cells {
+		cells = append(cells, uint64(ecgi))
+	}
+	leaves := []*leaf{
+		newLeaf(statePath, "enb-id", uint64(node.EnbID)),
+		newLeaf(statePath, "status", node.Status),
+		newLeaf(statePath, "cells", cells),
+		newLeaf(statePath, "service-models", strings.Join(node.ServiceModels, ",")),
+		newLeaf(statePath, "controllers", strings.Join(node.Controllers, ",")),
+	}
+	controllers := make([]string, 0, len(node.Connections))
+	for controller := range node.Connections {
+		controllers = append(controllers, controller)
+	}
+	sort.Strings(controllers)
+	for _, controller := range controllers {
+		connection := node.Connections[controller]
+		connectionPath := []*gnmiapi.PathElem{
+			statePath[0], statePath[1], statePath[2],
+			{Name: "connections"},
+			{Name: "connection", Key: map[string]string{"controller": controller}},
+			{Name: "state"},
+		}
+		leaves = append(leaves,
+			newLeaf(connectionPath, "controller", controller),
+			newLeaf(connectionPath, "state", string(connection.State)),
+			newLeaf(connectionPath, "since", connection.Since.UnixNano()))
+	}
+	return leaves
+}
+
+func cellLeaves(cell *model.Cell, ues uint64, connected uint64) []*leaf {
+	statePath := []*gnmiapi.PathElem{
+		{Name: ModelName},
+		{Name: "cells"},
+		{Name: "cell", Key: map[string]string{"ecgi": strconv.FormatUint(uint64(cell.ECGI), 10)}},
+		{Name: "state"},
+	}
+	return []*leaf{
+		newLeaf(statePath, "ecgi", uint64(cell.ECGI)),
+		newLeaf(statePath, "latitude", cell.Sector.Center.Lat),
+		newLeaf(statePath, "longitude", cell.Sector.Center.Lng),
+		newLeaf(statePath, "azimuth", int64(cell.Sector.Azimuth)),
+		newLeaf(statePath, "arc", int64(cell.Sector.Arc)),
+		newLeaf(statePath, "tx-power", cell.TxPowerDB),
+		newLeaf(statePath, "max-ues", uint64(cell.MaxUEs)),
+		newLeaf(statePath, "tac", uint64(cell.TAC)),
+		newLeaf(statePath, "earfcn", uint64(cell.Earfcn)),
+		newLeaf(statePath, "frequency", radio.Frequency(cell)),
+		newLeaf(statePath, "environment", string(cell.Environment)),
+		newLeaf(statePath, "locked", cell.Locked),
+		newLeaf(statePath, "barred", cell.Barred),
+		newLeaf(statePath, "in-service", cell.InService()),
+		newLeaf(statePath, "status", string(cell.Status)),
+		newLeaf(statePath, "ue-count", ues),
+		newLeaf(statePath, "connected-ue-count", connected),
+	}
+}
+
+func ueLeaves(ue *model.UE) []*leaf {
+	statePath := []*gnmiapi.PathElem{
+		{Name: ModelName},
+		{Name: "ues"},
+		{Name: "ue", Key: map[string]string{"imsi": strconv.FormatUint(uint64(ue.IMSI), 10)}},
+		{Name: "state"},
+	}
+	var ecgi uint64
+	if ue.Cell != nil {
+		ecgi = uint64(ue.Cell.ECGI)
+	}
+	return []*leaf{
+		newLeaf(statePath, "imsi", uint64(ue.IMSI)),
+		newLeaf(statePath, "type", string(ue.Type)),
+		newLeaf(statePath, "latitude", ue.Location.Lat),
+		newLeaf(statePath, "longitude", ue.Location.Lng),
+		newLeaf(statePath, "altitude", ue.Location.Alt),
+		newLeaf(statePath, "indoor", ue.Indoor),
+		newLeaf(statePath, "heading", ue.Heading),
+		newLeaf(statePath, "speed", ue.Speed),
+		newLeaf(statePath, "ecgi", ecgi),
+		newLeaf(statePath, "rrc-state", ue.RrcState.String()),
+	}
+}
+
+// metricLeaves returns the KPIs and attributes of the cell maintained in the metrics store
+func (s *Server) metricLeaves(ctx context.Context, cell *model.Cell) []*leaf {
+	metrics, err := s.metricStore.List(ctx, uint64(cell.ECGI))
+	if err != nil {
+		return nil
+	}
+	names := make([]string, 0, len(metrics))
+	for name := range metrics {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	leaves := make([]*leaf, 0, len(names))
+	for _, name := range names {
+		leaves = append(leaves, newLeaf([]*gnmiapi.PathElem{
+			{Name: ModelName},
+			{Name: "cells"},
+			{Name: "cell", Key: map[string]string{"ecgi": strconv.FormatUint(uint64(cell.ECGI), 10)}},
+			{Name: "metrics"},
+			{Name: "metric", Key: map[string]string{"name": name}},
+			{Name: "state"},
+		}, "value", metrics[name]))
+	}
+	return leaves
+}
+
+func newLeaf(parent []*gnmiapi.PathElem, name string, value interface{}) *leaf {
+	elems := make([]*gnmiapi.PathElem, 0, len(parent)+1)
+	elems = append(elems, parent...)
+	elems = append(elems, &gnmiapi.PathElem{Name: name})
+	return &leaf{path: &gnmiapi.Path{Elem: elems}, value: typedValue(value)}
+}
+
+// typedValue converts the given value to its gNMI representation; values of unknown types are rendered as strings
+func typedValue(value interface{}) *gnmiapi.TypedValue {
+	switch v := value.(type) {
+	case string:
+		return &gnmiapi.TypedValue{Value: &gnmiapi.TypedValue_StringVal{StringVal: v}}
+	case bool:
+		return &gnmiapi.TypedValue{Value: &gnmiapi.TypedValue_BoolVal{BoolVal: v}}
+	case int:
+		return &gnmiapi.TypedValue{Value: &gnmiapi.TypedValue_IntVal{IntVal: int64(v)}}
+	case int32:
+		return &gnmiapi.TypedValue{Value: &gnmiapi.TypedValue_IntVal{IntVal: int64(v)}}
+	case int64:
+		return &gnmiapi.TypedValue{Value: &gnmiapi.TypedValue_IntVal{IntVal: v}}
+	case uint32:
+		return &gnmiapi.TypedValue{Value: &gnmiapi.TypedValue_UintVal{UintVal: uint64(v)}}
+	case uint64:
+		return &gnmiapi.TypedValue{Value: &gnmiapi.TypedValue_UintVal{UintVal: v}}
+	case float32:
+		return &gnmiapi.TypedValue{Value: &gnmiapi.TypedValue_FloatVal{FloatVal: v}}
+	case float64:
+		return &gnmiapi.TypedValue{Value: &gnmiapi.TypedValue_FloatVal{FloatVal: float32(v)}}
+	case []interface{}:
+		elements := make([]*gnmiapi.TypedValue, 0, len(v))
+		for _, e := range v {
+			elements = append(elements, typedValue(e))
+		}
+		return &gnmiapi.TypedValue{Value: &gnmiapi.TypedValue_LeaflistVal{LeaflistVal: &gnmiapi.ScalarArray{Element: elements}}}
+	default:
+		return &gnmiapi.TypedValue{Value: &gnmiapi.TypedValue_StringVal{StringVal: fmt.Sprintf("%v", v)}}
+	}
+}
+
+// join returns the path resulting from appending the elements of the given path to the prefix
+func join(prefix *gnmiapi.Path, path *gnmiapi.Path) *gnmiapi.Path {
+	elems := make([]*gnmiapi.PathElem, 0)
+	if prefix != nil {
+		elems = append(elems, prefix.Elem...)
+	}
+	if path != nil {
+		elems = append(elems, path.Elem...)
+	}
+	return &gnmiapi.Path{Elem: elems}
+}
+
+// matches returns true if the path is the same as or a descendant of the pattern; the pattern may contain
+// wildcards in element names and key values, omitted keys match any value
+func matches(pattern *gnmiapi.Path, path *gnmiapi.Path) bool {
+	return matchElems(pattern.Elem, path.Elem)
+}
+
+func matchElems(pattern []*gnmiapi.PathElem, elems []*gnmiapi.PathElem) bool {
+	if len(pattern) == 0 {
+		return true
+	}
+	if pattern[0].Name == multiLevelWildcard {
+		for i := 0; i <= len(elems); i++ {
+			if matchElems(pattern[1:], elems[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(elems) == 0 || !matchElem(pattern[0], elems[0]) {
+		return false
+	}
+	return matchElems(pattern[1:], elems[1:])
+}
+
+func matchElem(pattern *gnmiapi.PathElem, elem *gnmiapi.PathElem) bool {
+	if pattern.Name != wildcard && pattern.Name != elem.Name {
+		return false
+	}
+	for key, value := range pattern.Key {
+		if value != wildcard && elem.Key[key] != value {
+			return false
+		}
+	}
+	return true
+}
+
+// pathString returns the canonical string form of the path, e.g. /ransim/cells/cell[ecgi=1]/state/ue-count
+func pathString(path *gnmiapi.Path) string {
+	var b strings.Builder
+	for _, elem := range path.Elem {
+		b.WriteString("/")
+		b.WriteString(elem.Name)
+		keys := make([]string, 0, len(elem.Key))
+		for key := range elem.Key {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			fmt.Fprintf(&b, "[%s=%s]", key, elem.Key[key])
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,147 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package health
+
+import (
+	"context"
+	"time"
+
+	"github.com/onosproject/onos-api/go/onos/ransim/types"
+	"github.com/onosproject/onos-lib-go/pkg/logging"
+	"github.com/onosproject/ran-simulator/pkg/energy"
+	"github.com/onosproject/ran-simulator/pkg/model"
+	"github.com/onosproject/ran-simulator/pkg/store/cells"
+	"github.com/onosproject/ran-simulator/pkg/store/event"
+	"github.com/onosproject/ran-simulator/pkg/store/metrics"
+	"github.com/onosproject/ran-simulator/pkg/store/ues"
+)
+
+var log = logging.GetLogger("health")
+
+const (
+	// loadedThreshold load above which a cell is considered heavily loaded
+	loadedThreshold = 0.7
+	// congestedThreshold load above which a cell is considered congested
+	congestedThreshold = 0.9
+
+	defaultInterval = time.Second
+)
+
+// colors maps the cell status to the color of the cell shown by visualizations
+var colors = map[model.CellStatus]string{
+	model.CellNormal:    "green",
+	model.CellLoaded:    "yellow",
+	model.CellCongested: "red",
+	model.CellAsleep:    "gray",
+	model.CellDown:      "black",
+}
+
+// Status returns the status of the cell given its load and energy-saving state
+func Status(cell *model.Cell, load float64, asleep bool) model.CellStatus {
+	switch {
+	case cell.Locked || cell.Outage:
+		return model.CellDown
+	case asleep || cell.Asleep:
+		return model.CellAsleep
+	case load >= congestedThreshold:
+		return model.CellCongested
+	case load >= loadedThreshold:
+		return model.CellLoaded
+	default:
+		return model.CellNormal
+	}
+}
+
+// Color returns the color representing the given cell status
+func Color(status model.CellStatus) string {
+	return colors[status]
+}
+
+// Controller periodically computes the status of cells from their state and load and updates the cells whose status
+// changed, so that the cell watch events carry the current status and color of the cells
+type Controller struct {
+	cellStore   cells.Store
+	ueStore     ues.Store
+	metricStore metrics.Store
+	interval    time.Duration
+	cancel      context.CancelFunc
+}
+
+// NewController creates a new cell health controller
+func NewController(cellStore cells.Store, ueStore ues.Store, metricStore metrics.Store) *Controller {
+	return &Controller{
+		cellStore:   cellStore,
+		ueStore:     ueStore,
+		metricStore: metricStore,
+		interval:    defaultInterval,
+	}
+}
+
+// Start computes the initial status of all cells and starts tracking it; changes of the administrative and
+// operational state of cells are reflected immediately
+func (c *Controller) Start() error {
+	ctx, cancel := context.WithCancel(context.Background())
+	ch := make(chan event.Event)
+	if err := c.cellStore.Watch(ctx, ch, cells.WatchOptions{Types: []cells.CellEvent{cells.UpdatedAdminState}}); err != nil {
+		cancel()
+		return err
+	}
+	c.cancel = cancel
+	c.updateAll(ctx)
+	go c.run(ctx, ch)
+	return nil
+}
+
+// Stop stops tracking the status of cells
+func (c *Controller) Stop() {
+	if c.cancel != nil {
+		c.cancel()
+	}
+}
+
+func (c *Controller) run(ctx context.Context, ch <-chan event.Event) {
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case cellEvent, ok := <-ch:
+			if !ok {
+				return
+			}
+			if cell, err := c.cellStore.Get(ctx, cellEvent.Key.(types.ECGI)); err == nil {
+				c.update(ctx, cell)
+			}
+		case <-ticker.C:
+			c.updateAll(ctx)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (c *Controller) updateAll(ctx context.Context) {
+	cellList, err := c.cellStore.List(ctx)
+	if err != nil {
+		log.Warn(err)
+		return
+	}
+	for _, cell := range cellList {
+		c.update(ctx, cell)
+	}
+}
+
+// update updates the status and color of the cell if its status changed; only these fields are written, so that
+// concurrent changes of the other fields of the cell are preserved
+func (c *Controller) update(ctx context.Context, cell *model.Cell) {
+	load := cell.Load(len(c.ueStore.ListUEs(ctx, cell.ECGI)))
+	status := Status(cell, load, energy.IsAsleep(ctx, c.metricStore, uint64(cell.ECGI)))
+	if status == cell.Status {
+		return
+	}
+	log.Debugf("Cell %d is %s", cell.ECGI, status)
+	if err := c.cellStore.UpdateStatus(ctx, cell.ECGI, status, Color(status)); err != nil {
+		log.Warn(err)
+	}
+}
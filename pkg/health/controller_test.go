@@ -0,0 +1,60 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package health
+
+import (
+	"context"
+	"testing"
+
+	"github.com/onosproject/onos-api/go/onos/ransim/types"
+	"github.com/onosproject/ran-simulator/pkg/energy"
+	"github.com/onosproject/ran-simulator/pkg/model"
+	"github.com/onosproject/ran-simulator/pkg/store/cells"
+	"github.com/onosproject/ran-simulator/pkg/store/metrics"
+	"github.com/onosproject/ran-simulator/pkg/store/nodes"
+	"github.com/onosproject/ran-simulator/pkg/store/ues"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStatus(t *testing.T) {
+	cell := &model.Cell{}
+	assert.Equal(t, model.CellNormal, Status(cell, 0.5, false))
+	assert.Equal(t, model.CellLoaded, Status(cell, 0.7, false))
+	assert.Equal(t, model.CellCongested, Status(cell, 1, false))
+	assert.Equal(t, model.CellAsleep, Status(cell, 1, true))
+	cell.Asleep = true
+	assert.Equal(t, model.CellAsleep, Status(cell, 1, false))
+	cell.Outage = true
+	assert.Equal(t, model.CellDown, Status(cell, 0, true))
+	assert.Equal(t, "black", Color(model.CellDown))
+}
+
+func TestController(t *testing.T) {
+	ctx := context.Background()
+	m := &model.Model{}
+	assert.NoError(t, model.LoadConfig(m, "../model/test"))
+	nodeStore := nodes.NewNodeRegistry(m.Nodes)
+	cellStore := cells.NewCellRegistry(m.Cells, nodeStore)
+	ueStore := ues.NewUERegistry(0, cellStore)
+	metricStore := metrics.NewMetricsStore()
+	controller := NewController(cellStore, ueStore, metricStore)
+	ecgi := types.ECGI(84325717505)
+
+	// The initial status of all cells is computed upon start and reflected by their color
+	assert.NoError(t, controller.Start())
+	defer controller.Stop()
+	cell, err := cellStore.Get(ctx, ecgi)
+	assert.NoError(t, err)
+	assert.Equal(t, model.CellNormal, cell.Status)
+	assert.Equal(t, "green", cell.Color)
+
+	// Cells change their status along with their state
+	assert.NoError(t, metricStore.Set(ctx, uint64(ecgi), energy.SleepAttribute, int32(1)))
+	controller.update(ctx, cell)
+	cell, err = cellStore.Get(ctx, ecgi)
+	assert.NoError(t, err)
+	assert.Equal(t, model.CellAsleep, cell.Status)
+	assert.Equal(t, "gray", cell.Color)
+}
@@ -0,0 +1,227 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package iot implements the massive IoT mode, simulating millions of NB-IoT style devices which only send mobile
+// originated uplink reports. Unlike UEs, the devices are held in a compact representation of a few bytes each and are
+// only reported by aggregate per-cell KPIs.
+package iot
+
+import (
+	"context"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/onosproject/onos-api/go/onos/ransim/types"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/onosproject/onos-lib-go/pkg/logging"
+	"github.com/onosproject/ran-simulator/pkg/model"
+	"github.com/onosproject/ran-simulator/pkg/store/cells"
+	"github.com/onosproject/ran-simulator/pkg/store/metrics"
+)
+
+var log = logging.GetLogger("iot")
+
+// Per-cell aggregate metrics of the massive IoT devices maintained in the metrics store and reported via KPM
+const (
+	// Devices number of devices in the cell
+	Devices = "IOT.Devices"
+	// ConnectedDevices number of devices of the cell connected at the last step
+	ConnectedDevices = "IOT.ConnectedDevices"
+	// ConnEstabAtt number of connection establishments attempted by the devices of the cell
+	ConnEstabAtt = "IOT.ConnEstabAtt"
+	// ConnEstabSucc number of connections established by the devices of the cell
+	ConnEstabSucc = "IOT.ConnEstabSucc"
+	// VolumeUL uplink volume reported by the devices of the cell in kbit
+	VolumeUL = "IOT.VolumeUL"
+)
+
+const (
+	defaultReportInterval     = time.Hour
+	defaultPayloadSize        = 100
+	defaultConnectionDuration = 5 * time.Second
+
+	iotUpdateInterval = time.Second
+)
+
+// cellStats accumulates the aggregate KPIs of the devices of a cell during a step
+type cellStats struct {
+	attempts  uint64
+	successes uint64
+	connected uint64
+	bits      float64
+}
+
+// Controller steps the populations of massive IoT devices, each device waking up to connect and send an uplink
+// report, provided its cell is in service, and being released at the end of its connection
+type Controller struct {
+	cellStore   cells.Store
+	metricStore metrics.Store
+	config      model.MassiveIoTConfig
+	mu          sync.Mutex
+	populations []*population
+	// residualBits holds the volume of the cells not yet counted as a whole kbit
+	residualBits map[types.ECGI]float64
+	cancel       context.CancelFunc
+}
+
+// NewController creates a new massive IoT controller; unset report intervals, payload sizes and connection durations
+// are replaced by defaults
+func NewController(cellStore cells.Store, metricStore metrics.Store, config model.MassiveIoTConfig) *Controller {
+	classes := make(map[model.UEType]model.IoTClass, len(config.Classes))
+	for ueType, class := range config.Classes {
+		if class.ReportInterval <= 0 {
+			class.ReportInterval = defaultReportInterval
+		}
+		if class.PayloadSize == 0 {
+			class.PayloadSize = defaultPayloadSize
+		}
+		if class.ConnectionDuration <= 0 {
+			class.ConnectionDuration = defaultConnectionDuration
+		}
+		classes[ueType] = class
+	}
+	config.Classes = classes
+	return &Controller{
+		cellStore:    cellStore,
+		metricStore:  metricStore,
+		config:       config,
+		residualBits: make(map[types.ECGI]float64),
+	}
+}
+
+// Counters lists the names of the metrics maintained by the controller
+func Counters() []string {
+	return []string{Devices, ConnectedDevices, ConnEstabAtt, ConnEstabSucc, VolumeUL}
+}
+
+// Start creates the devices of all classes, spread over their cells, and starts stepping them
+func (c *Controller) Start() error {
+	ctx, cancel := context.WithCancel(context.Background())
+	if err := c.populate(ctx, time.Now()); err != nil {
+		cancel()
+		return err
+	}
+	c.cancel = cancel
+	go c.run(ctx)
+	return nil
+}
+
+// Stop stops stepping the devices
+func (c *Controller) Stop() {
+	if c.cancel != nil {
+		c.cancel()
+	}
+}
+
+// Devices returns the number of devices of all classes
+func (c *Controller) Devices() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	count := 0
+	for _, p := range c.populations {
+		count += p.size()
+	}
+	return count
+}
+
+// populate creates the devices of all classes in the order of their names and sets the number of devices per cell
+func (c *Controller) populate(ctx context.Context, now time.Time) error {
+	cellList, err := c.cellStore.List(ctx)
+	if err != nil {
+		return err
+	}
+	allCells := make([]types.ECGI, 0, len(cellList))
+	for _, cell := range cellList {
+		allCells = append(allCells, cell.ECGI)
+	}
+	sort.Slice(allCells, func(i, j int) bool {
+		return allCells[i] < allCells[j]
+	})
+	ueTypes := make([]string, 0, len(c.config.Classes))
+	for ueType := range c.config.Classes {
+		ueTypes = append(ueTypes, string(ueType))
+	}
+	sort.Strings(ueTypes)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	devices := make(map[types.ECGI]uint64)
+	for _, ueType := range ueTypes {
+		class := c.config.Classes[model.UEType(ueType)]
+		classCells := class.Cells
+		if len(classCells) == 0 {
+			classCells = allCells
+		}
+		for _, ecgi := range classCells {
+			if _, err := c.cellStore.Get(ctx, ecgi); err != nil {
+				return errors.New(errors.NotFound, "cell %d of IoT class %s not found", ecgi, ueType)
+			}
+		}
+		if len(classCells) == 0 {
+			return errors.New(errors.Invalid, "no cells for IoT class %s", ueType)
+		}
+		p := newPopulation(model.UEType(ueType), class, classCells, now)
+		for i, count := range p.cellSizes() {
+			devices[p.cells[i]] += count
+		}
+		c.populations = append(c.populations, p)
+		log.Infof("Created %d IoT devices of class %s over %d cells", class.Count, ueType, len(classCells))
+	}
+	for ecgi, count := range devices {
+		if err := c.metricStore.Set(ctx, uint64(ecgi), Devices, count); err != nil {
+			log.Warn(err)
+		}
+	}
+	return nil
+}
+
+func (c *Controller) run(ctx context.Context) {
+	ticker := time.NewTicker(iotUpdateInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case now := <-ticker.C:
+			c.step(ctx, now)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// step advances all devices to the given time and updates the aggregate KPIs of their cells
+func (c *Controller) step(ctx context.Context, now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	inService := make(map[types.ECGI]bool)
+	if cellList, err := c.cellStore.List(ctx); err == nil {
+		for _, cell := range cellList {
+			inService[cell.ECGI] = cell.InService()
+		}
+	} else {
+		log.Warn(err)
+	}
+	stats := make(map[types.ECGI]*cellStats)
+	for _, p := range c.populations {
+		p.step(now, inService, stats)
+	}
+	for ecgi, s := range stats {
+		c.add(ctx, ecgi, ConnEstabAtt, s.attempts)
+		c.add(ctx, ecgi, ConnEstabSucc, s.successes)
+		bits := c.residualBits[ecgi] + s.bits
+		kbits := math.Floor(bits / 1000)
+		c.residualBits[ecgi] = bits - 1000*kbits
+		c.add(ctx, ecgi, VolumeUL, uint64(kbits))
+		if err := c.metricStore.Set(ctx, uint64(ecgi), ConnectedDevices, s.connected); err != nil {
+			log.Warn(err)
+		}
+	}
+}
+
+func (c *Controller) add(ctx context.Context, ecgi types.ECGI, name string, delta uint64) {
+	if delta > 0 {
+		_, _ = c.metricStore.Add(ctx, uint64(ecgi), name, delta)
+	}
+}
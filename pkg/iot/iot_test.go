@@ -0,0 +1,92 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package iot
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/onosproject/onos-api/go/onos/ransim/types"
+	"github.com/onosproject/ran-simulator/pkg/model"
+	"github.com/onosproject/ran-simulator/pkg/store/cells"
+	"github.com/onosproject/ran-simulator/pkg/store/metrics"
+	"github.com/onosproject/ran-simulator/pkg/store/nodes"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPopulation(t *testing.T) {
+	class := model.IoTClass{Count: 1000, ReportInterval: 10 * time.Second, Periodic: true, PayloadSize: 100,
+		ConnectionDuration: 2 * time.Second}
+	ecgi1, ecgi2 := types.ECGI(1), types.ECGI(2)
+	start := time.Now()
+	p := newPopulation("meter", class, []types.ECGI{ecgi1, ecgi2}, start)
+	assert.Equal(t, 1000, p.size())
+	sizes := p.cellSizes()
+	assert.Equal(t, uint64(1000), sizes[0]+sizes[1])
+
+	// Periodic devices report once per interval; those of cells out of service fail to connect
+	inService := map[types.ECGI]bool{ecgi1: true}
+	stats := make(map[types.ECGI]*cellStats)
+	for s := 0; s < 100; s++ {
+		p.step(start.Add(time.Duration(s)*time.Second), inService, stats)
+		if s == 0 {
+			// Only the devices which reported at the first step are connected yet
+			stats[ecgi1].connected = 0
+			continue
+		}
+		assert.InDelta(t, 2*float64(sizes[0])/10, float64(stats[ecgi1].connected), 0.1*float64(sizes[0]))
+		assert.Equal(t, uint64(0), stats[ecgi2].connected)
+		stats[ecgi1].connected, stats[ecgi2].connected = 0, 0
+	}
+	assert.Equal(t, 10*sizes[0], stats[ecgi1].attempts)
+	assert.Equal(t, 10*sizes[0], stats[ecgi1].successes)
+	assert.Equal(t, 800*float64(stats[ecgi1].successes), stats[ecgi1].bits)
+	assert.Equal(t, 10*sizes[1], stats[ecgi2].attempts)
+	assert.Equal(t, uint64(0), stats[ecgi2].successes)
+}
+
+func TestMassiveIoT(t *testing.T) {
+	ctx := context.Background()
+	m := model.Model{}
+	assert.NoError(t, model.LoadConfig(&m, "../model/test"))
+	cellStore := cells.NewCellRegistry(m.Cells, nodes.NewNodeRegistry(m.Nodes))
+	metricStore := metrics.NewMetricsStore()
+	ecgi := types.ECGI(84325717505)
+
+	// Classes of a million devices take a few bytes per device
+	controller := NewController(cellStore, metricStore, model.MassiveIoTConfig{
+		Classes: map[model.UEType]model.IoTClass{
+			"meter":  {Count: 1000000},
+			"sensor": {Count: 1000, Cells: []types.ECGI{ecgi}, ReportInterval: time.Second},
+		},
+	})
+	now := time.Now()
+	assert.NoError(t, controller.populate(ctx, now))
+	assert.Equal(t, 1001000, controller.Devices())
+	assert.Equal(t, model.UEType("meter"), controller.populations[0].ueType)
+	assert.Equal(t, uint32(defaultPayloadSize), controller.populations[0].class.PayloadSize)
+
+	devices, ok := metricStore.Get(ctx, uint64(ecgi), Devices)
+	assert.True(t, ok)
+	assert.InDelta(t, 1000000/4+1000, devices, 2000)
+
+	// Every sensor reports at every step, on top of the meters
+	controller.step(ctx, now.Add(time.Second))
+	attempts, _ := metricStore.Get(ctx, uint64(ecgi), ConnEstabAtt)
+	assert.GreaterOrEqual(t, attempts.(uint64), uint64(1000))
+	successes, _ := metricStore.Get(ctx, uint64(ecgi), ConnEstabSucc)
+	assert.Equal(t, attempts, successes)
+	volume, _ := metricStore.Get(ctx, uint64(ecgi), VolumeUL)
+	assert.Equal(t, successes.(uint64)*8/10, volume)
+	connected, _ := metricStore.Get(ctx, uint64(ecgi), ConnectedDevices)
+	assert.GreaterOrEqual(t, connected.(uint64), uint64(1000))
+
+	// Classes of unknown cells are rejected
+	controller = NewController(cellStore, metricStore, model.MassiveIoTConfig{
+		Classes: map[model.UEType]model.IoTClass{"meter": {Count: 1, Cells: []types.ECGI{1}}},
+	})
+	assert.Error(t, controller.populate(ctx, now))
+}
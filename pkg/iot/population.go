@@ -0,0 +1,129 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package iot
+
+import (
+	"math/rand"
+	"time"
+
+	"github.com/onosproject/onos-api/go/onos/ransim/types"
+	"github.com/onosproject/ran-simulator/pkg/model"
+)
+
+// population holds the devices of a class as parallel slices indexed by device, i.e. 12 bytes per device without any
+// per-device allocation; times are in whole seconds since the creation of the population
+type population struct {
+	ueType model.UEType
+	class  model.IoTClass
+	start  time.Time
+	cells  []types.ECGI
+	// cell holds the index of the cell of each device in cells
+	cell []uint32
+	// next holds the time of the next report of each device
+	next []uint32
+	// until holds the end of the connection of each device, which is idle once the end has passed
+	until []uint32
+}
+
+// newPopulation creates the devices of the class, spread uniformly over the given cells, with their first reports
+// spread uniformly over the report interval
+func newPopulation(ueType model.UEType, class model.IoTClass, cells []types.ECGI, now time.Time) *population {
+	p := &population{
+		ueType: ueType,
+		class:  class,
+		start:  now,
+		cells:  cells,
+		cell:   make([]uint32, class.Count),
+		next:   make([]uint32, class.Count),
+		until:  make([]uint32, class.Count),
+	}
+	interval := seconds(class.ReportInterval)
+	for i := range p.cell {
+		p.cell[i] = uint32(rand.Intn(len(cells)))
+		p.next[i] = uint32(rand.Int63n(int64(interval)))
+	}
+	return p
+}
+
+func (p *population) size() int {
+	return len(p.cell)
+}
+
+// cellSizes returns the number of devices of each cell, by cell index
+func (p *population) cellSizes() []uint64 {
+	sizes := make([]uint64, len(p.cells))
+	for _, i := range p.cell {
+		sizes[i]++
+	}
+	return sizes
+}
+
+// step advances the devices to the given time, adding their connection attempts, successes, connected devices and
+// reported volume to the stats of their cells; devices due to report connect if their cell is in service, and report
+// again after the next interval in any case
+func (p *population) step(now time.Time, inService map[types.ECGI]bool, stats map[types.ECGI]*cellStats) {
+	t := p.seconds(now)
+	payloadBits := 8 * float64(p.class.PayloadSize)
+	duration := seconds(p.class.ConnectionDuration)
+	perCell := make([]cellStats, len(p.cells))
+	cellInService := make([]bool, len(p.cells))
+	for i, ecgi := range p.cells {
+		cellInService[i] = inService[ecgi]
+	}
+	for i := range p.next {
+		s := &perCell[p.cell[i]]
+		if p.next[i] <= t {
+			s.attempts++
+			if cellInService[p.cell[i]] {
+				s.successes++
+				s.bits += payloadBits
+				p.until[i] = t + duration
+			}
+			p.next[i] = t + p.interval()
+		}
+		if p.until[i] > t {
+			s.connected++
+		}
+	}
+	for i, ecgi := range p.cells {
+		total, ok := stats[ecgi]
+		if !ok {
+			total = &cellStats{}
+			stats[ecgi] = total
+		}
+		total.attempts += perCell[i].attempts
+		total.successes += perCell[i].successes
+		total.connected += perCell[i].connected
+		total.bits += perCell[i].bits
+	}
+}
+
+// interval returns the time in seconds until the next report of a device
+func (p *population) interval() uint32 {
+	interval := seconds(p.class.ReportInterval)
+	if !p.class.Periodic {
+		interval = uint32(rand.ExpFloat64() * float64(interval))
+	}
+	if interval == 0 {
+		return 1
+	}
+	return interval
+}
+
+// seconds returns the whole seconds elapsed since the creation of the population
+func (p *population) seconds(now time.Time) uint32 {
+	if now.Before(p.start) {
+		return 0
+	}
+	return uint32(now.Sub(p.start) / time.Second)
+}
+
+// seconds returns the given duration in whole seconds, at least one
+func seconds(d time.Duration) uint32 {
+	if d < time.Second {
+		return 1
+	}
+	return uint32(d / time.Second)
+}
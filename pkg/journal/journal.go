@@ -0,0 +1,195 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package journal
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/onosproject/onos-lib-go/pkg/logging"
+)
+
+var log = logging.GetLogger("journal")
+
+// Kind is a kind of simulation milestone
+type Kind string
+
+const (
+	// UEAttached UE attached to a cell
+	UEAttached Kind = "UEAttached"
+	// UEDetached UE detached from the network
+	UEDetached Kind = "UEDetached"
+	// HandoverCompleted UE was handed over to another cell
+	HandoverCompleted Kind = "HandoverCompleted"
+	// HandoverFailed handover of a UE to another cell failed
+	HandoverFailed Kind = "HandoverFailed"
+	// RadioLinkFailed radio link of a UE to its serving cell failed
+	RadioLinkFailed Kind = "RadioLinkFailed"
+	// ConnectionReestablished UE re-established its RRC connection after a radio link failure
+	ConnectionReestablished Kind = "ConnectionReestablished"
+	// GeofenceEntered UE entered a geofence
+	GeofenceEntered Kind = "GeofenceEntered"
+	// GeofenceLeft UE left a geofence
+	GeofenceLeft Kind = "GeofenceLeft"
+	// UETransferred UE was handed over to a cell simulated by another simulator instance
+	UETransferred Kind = "UETransferred"
+	// UEContextTransferred context of a UE was transferred to another E2 node upon handover
+	UEContextTransferred Kind = "UEContextTransferred"
+	// XnHandoverFailed Xn handover preparation or execution of a UE between E2 nodes failed
+	XnHandoverFailed Kind = "XnHandoverFailed"
+	// UEAdmitted UE was admitted by its serving cell and allocated a C-RNTI
+	UEAdmitted Kind = "UEAdmitted"
+	// UEReleased UE was released by its serving cell
+	UEReleased Kind = "UEReleased"
+	// UERegistered UE registered with the core
+	UERegistered Kind = "UERegistered"
+	// RegistrationRejected registration of a UE was rejected by the core
+	RegistrationRejected Kind = "RegistrationRejected"
+	// PDUSessionEstablished PDU session of a UE was established
+	PDUSessionEstablished Kind = "PDUSessionEstablished"
+	// PDUSessionFailed PDU session establishment of a UE failed
+	PDUSessionFailed Kind = "PDUSessionFailed"
+	// PDUSessionReleased PDU session of a UE was released
+	PDUSessionReleased Kind = "PDUSessionReleased"
+	// AdmissionRejected UE was rejected by a cell
+	AdmissionRejected Kind = "AdmissionRejected"
+	// TrackingAreaUpdated idle UE updated its registration area
+	TrackingAreaUpdated Kind = "TrackingAreaUpdated"
+	// SubscriptionCreated RIC subscription was created
+	SubscriptionCreated Kind = "SubscriptionCreated"
+	// SubscriptionDeleted RIC subscription was deleted
+	SubscriptionDeleted Kind = "SubscriptionDeleted"
+	// NodeConnected E2 node completed the E2 setup
+	NodeConnected Kind = "NodeConnected"
+	// NodeDisconnected E2 node disconnected
+	NodeDisconnected Kind = "NodeDisconnected"
+	// IndicationFuzzed E2 node sent an indication with a deliberately corrupted payload
+	IndicationFuzzed Kind = "IndicationFuzzed"
+	// AnomalyInjected KPI anomaly was scheduled for a cell
+	AnomalyInjected Kind = "AnomalyInjected"
+	// AnomalyCancelled KPI anomaly was cancelled
+	AnomalyCancelled Kind = "AnomalyCancelled"
+	// SimulationReset simulation was returned to the initial state of its model
+	SimulationReset Kind = "SimulationReset"
+)
+
+const defaultCapacity = 10000
+
+// Entry is a journal entry
+type Entry struct {
+	Seq      uint64                 `json:"seq"`
+	Time     time.Time              `json:"time"`
+	Kind     Kind                   `json:"kind"`
+	EntityID uint64                 `json:"entityId"`
+	Details  map[string]interface{} `json:"details,omitempty"`
+}
+
+// Filter selects journal entries
+type Filter struct {
+	// Since selects entries with sequence number greater than this one
+	Since uint64
+	// Kinds selects entries of any of these kinds; empty selects all kinds
+	Kinds []Kind
+	// EntityID selects entries of this entity; zero selects all entities
+	EntityID uint64
+	// Limit is the maximum number of entries returned; zero means no limit
+	Limit int
+}
+
+// Journal keeps the most recent simulation milestones in memory and optionally
+// persists all of them as line-delimited JSON
+type Journal struct {
+	mu       sync.RWMutex
+	seq      uint64
+	capacity int
+	entries  []*Entry
+	encoder  *json.Encoder
+}
+
+// New creates a new journal keeping up to the specified number of most recent entries in memory
+func New(capacity int) *Journal {
+	return &Journal{
+		capacity: capacity,
+		entries:  make([]*Entry, 0),
+	}
+}
+
+// SetOutput sets the writer the entries are persisted to; nil disables persisting
+func (j *Journal) SetOutput(w io.Writer) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if w == nil {
+		j.encoder = nil
+		return
+	}
+	j.encoder = json.NewEncoder(w)
+}
+
+// Record records a milestone of the specified kind for the specified entity
+func (j *Journal) Record(kind Kind, entityID uint64, details map[string]interface{}) *Entry {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.seq++
+	entry := &Entry{
+		Seq:      j.seq,
+		Time:     time.Now(),
+		Kind:     kind,
+		EntityID: entityID,
+		Details:  details,
+	}
+	if len(j.entries) >= j.capacity {
+		j.entries = j.entries[1:]
+	}
+	j.entries = append(j.entries, entry)
+	if j.encoder != nil {
+		if err := j.encoder.Encode(entry); err != nil {
+			log.Warn(err)
+		}
+	}
+	return entry
+}
+
+// Query returns the entries matching the filter in the order of their sequence numbers
+func (j *Journal) Query(filter Filter) []*Entry {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+	result := make([]*Entry, 0)
+	for _, entry := range j.entries {
+		if entry.Seq <= filter.Since || (filter.EntityID != 0 && entry.EntityID != filter.EntityID) || !matchesKind(entry, filter.Kinds) {
+			continue
+		}
+		result = append(result, entry)
+		if filter.Limit > 0 && len(result) == filter.Limit {
+			break
+		}
+	}
+	return result
+}
+
+func matchesKind(entry *Entry, kinds []Kind) bool {
+	if len(kinds) == 0 {
+		return true
+	}
+	for _, kind := range kinds {
+		if entry.Kind == kind {
+			return true
+		}
+	}
+	return false
+}
+
+var defaultJournal = New(defaultCapacity)
+
+// Default returns the process-wide journal
+func Default() *Journal {
+	return defaultJournal
+}
+
+// Record records a milestone in the process-wide journal
+func Record(kind Kind, entityID uint64, details map[string]interface{}) {
+	defaultJournal.Record(kind, entityID, details)
+}
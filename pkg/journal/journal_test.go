@@ -0,0 +1,46 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package journal
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJournal(t *testing.T) {
+	j := New(3)
+	buf := &bytes.Buffer{}
+	j.SetOutput(buf)
+
+	j.Record(UEAttached, 1, map[string]interface{}{"ecgi": 10})
+	j.Record(HandoverCompleted, 1, map[string]interface{}{"source": 10, "target": 11})
+	j.Record(NodeConnected, 2, nil)
+	j.Record(HandoverCompleted, 3, nil)
+
+	// Only the most recent entries are kept in memory
+	entries := j.Query(Filter{})
+	assert.Len(t, entries, 3)
+	assert.Equal(t, uint64(2), entries[0].Seq)
+
+	entries = j.Query(Filter{Kinds: []Kind{HandoverCompleted}})
+	assert.Len(t, entries, 2)
+	entries = j.Query(Filter{Since: 2, EntityID: 3})
+	assert.Len(t, entries, 1)
+	assert.Equal(t, uint64(4), entries[0].Seq)
+	entries = j.Query(Filter{Limit: 1})
+	assert.Len(t, entries, 1)
+
+	// All entries are persisted
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	assert.Len(t, lines, 4)
+	entry := &Entry{}
+	assert.NoError(t, json.Unmarshal([]byte(lines[0]), entry))
+	assert.Equal(t, uint64(1), entry.Seq)
+	assert.Equal(t, UEAttached, entry.Kind)
+}
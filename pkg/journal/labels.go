@@ -0,0 +1,154 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package journal
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// LabelKind is a kind of ground-truth label
+type LabelKind string
+
+const (
+	// AnomalyLabel labels a KPI anomaly of a cell
+	AnomalyLabel LabelKind = "Anomaly"
+	// FaultLabel labels a fault of a cell or node
+	FaultLabel LabelKind = "Fault"
+	// PingPongLabel labels a UE handed over back to its previous cell shortly after a handover
+	PingPongLabel LabelKind = "HandoverPingPong"
+)
+
+// Label is a ground-truth label of a condition of the simulated RAN during a period of time
+type Label struct {
+	ID   uint64    `json:"id"`
+	Kind LabelKind `json:"kind"`
+	// Start and End delimit the period of the condition; End is nil while the condition persists
+	Start    time.Time              `json:"start"`
+	End      *time.Time             `json:"end,omitempty"`
+	Entities []uint64               `json:"entities"`
+	Details  map[string]interface{} `json:"details,omitempty"`
+}
+
+// Labels keeps the ground-truth labels of injected and generated conditions
+type Labels struct {
+	mu     sync.RWMutex
+	nextID uint64
+	labels []*Label
+}
+
+// NewLabels creates an empty set of labels
+func NewLabels() *Labels {
+	return &Labels{
+		nextID: 1,
+	}
+}
+
+var defaultLabels = NewLabels()
+
+// DefaultLabels returns the process-wide labels
+func DefaultLabels() *Labels {
+	return defaultLabels
+}
+
+// Add adds a label of a condition from start until end, unless zero, and returns its ID
+func (l *Labels) Add(kind LabelKind, start time.Time, end time.Time, entities []uint64, details map[string]interface{}) uint64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	label := &Label{
+		ID:       l.nextID,
+		Kind:     kind,
+		Start:    start,
+		Entities: entities,
+		Details:  details,
+	}
+	if !end.IsZero() {
+		label.End = &end
+	}
+	l.nextID++
+	l.labels = append(l.labels, label)
+	return label.ID
+}
+
+// End sets the end of the condition of the specified label
+func (l *Labels) End(id uint64, end time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, label := range l.labels {
+		if label.ID == id {
+			label.End = &end
+			return
+		}
+	}
+}
+
+// Remove removes the specified label, e.g. of a condition withdrawn before it started
+func (l *Labels) Remove(id uint64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for i, label := range l.labels {
+		if label.ID == id {
+			l.labels = append(l.labels[:i], l.labels[i+1:]...)
+			return
+		}
+	}
+}
+
+// List lists the labels whose condition persists or ended at or after the given time
+func (l *Labels) List(since time.Time) []Label {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	list := make([]Label, 0, len(l.labels))
+	for _, label := range l.labels {
+		if label.End == nil || !label.End.Before(since) {
+			list = append(list, *label)
+		}
+	}
+	return list
+}
+
+// WriteLabelsCSV writes the labels as CSV records of id, kind, start, end, space-separated entities and JSON details
+func WriteLabelsCSV(w io.Writer, labels []Label) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write([]string{"id", "kind", "start", "end", "entities", "details"}); err != nil {
+		return err
+	}
+	for _, label := range labels {
+		end := ""
+		if label.End != nil {
+			end = label.End.Format(time.RFC3339Nano)
+		}
+		entities := make([]string, 0, len(label.Entities))
+		for _, entity := range label.Entities {
+			entities = append(entities, strconv.FormatUint(entity, 10))
+		}
+		details := ""
+		if len(label.Details) > 0 {
+			bytes, err := json.Marshal(label.Details)
+			if err != nil {
+				return err
+			}
+			details = string(bytes)
+		}
+		record := []string{
+			strconv.FormatUint(label.ID, 10),
+			string(label.Kind),
+			label.Start.Format(time.RFC3339Nano),
+			end,
+			strings.Join(entities, " "),
+			details,
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
@@ -0,0 +1,45 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package journal
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLabels(t *testing.T) {
+	labels := NewLabels()
+	start := time.Date(2021, 6, 2, 12, 0, 0, 0, time.UTC)
+
+	fault := labels.Add(FaultLabel, start, time.Time{}, []uint64{1}, map[string]interface{}{"type": "CellOutage"})
+	anomaly := labels.Add(AnomalyLabel, start, start.Add(time.Minute), []uint64{2}, nil)
+	list := labels.List(time.Time{})
+	assert.Len(t, list, 2)
+	assert.Nil(t, list[0].End)
+
+	labels.End(fault, start.Add(time.Hour))
+	assert.Equal(t, start.Add(time.Hour), *labels.List(time.Time{})[0].End)
+	// Labels of conditions ended before the given time are omitted
+	assert.Len(t, labels.List(start.Add(2*time.Minute)), 1)
+	labels.Remove(anomaly)
+	assert.Len(t, labels.List(time.Time{}), 1)
+
+	labels.Add(PingPongLabel, start, start.Add(time.Second), []uint64{10, 2, 1}, nil)
+	list = labels.List(time.Time{})
+	assert.Len(t, list, 2)
+	assert.Equal(t, PingPongLabel, list[1].Kind)
+
+	buf := &bytes.Buffer{}
+	assert.NoError(t, WriteLabelsCSV(buf, list))
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	assert.Len(t, lines, 3)
+	assert.Equal(t, "id,kind,start,end,entities,details", lines[0])
+	assert.Equal(t, `1,Fault,2021-06-02T12:00:00Z,2021-06-02T13:00:00Z,1,"{""type"":""CellOutage""}"`, lines[1])
+	assert.Equal(t, "3,HandoverPingPong,2021-06-02T12:00:00Z,2021-06-02T12:00:01Z,10 2 1,", lines[2])
+}
@@ -0,0 +1,141 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package journal
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+)
+
+const (
+	journalPath = "/journal"
+	labelsPath  = "/labels"
+)
+
+// Server is an HTTP server for querying the journal and downloading the ground-truth labels
+type Server struct {
+	journal *Journal
+	labels  *Labels
+	server  *http.Server
+}
+
+// NewServer creates a new journal query server listening on the specified port
+func NewServer(journal *Journal, labels *Labels, port int) *Server {
+	s := &Server{
+		journal: journal,
+		labels:  labels,
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc(journalPath, s.query)
+	mux.HandleFunc(labelsPath, s.downloadLabels)
+	s.server = &http.Server{
+		Addr:    fmt.Sprintf(":%d", port),
+		Handler: mux,
+	}
+	return s
+}
+
+// Use wraps the handler of the journal server with the given middleware before it serves
+func (s *Server) Use(middleware func(http.Handler) http.Handler) {
+	s.server.Handler = middleware(s.server.Handler)
+}
+
+// Serve starts serving the journal queries in the background
+func (s *Server) Serve() {
+	go func() {
+		log.Info("Started journal server on ", s.server.Addr)
+		if err := s.server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Error(err)
+		}
+	}()
+}
+
+// Stop stops the journal server
+func (s *Server) Stop() {
+	if err := s.server.Shutdown(context.Background()); err != nil {
+		log.Error(err)
+	}
+}
+
+// query handles GET /journal?since=<seq>&kind=<kind>&entity=<id>&limit=<n> returning line-delimited JSON entries
+func (s *Server) query(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+	filter, err := parseFilter(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	encoder := json.NewEncoder(w)
+	for _, entry := range s.journal.Query(filter) {
+		if err := encoder.Encode(entry); err != nil {
+			log.Warn(err)
+			return
+		}
+	}
+}
+
+// downloadLabels handles GET /labels?since=<RFC 3339 time>&format={json|csv} returning the ground-truth labels
+func (s *Server) downloadLabels(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+	var since time.Time
+	if value := r.URL.Query().Get("since"); value != "" {
+		var err error
+		if since, err = time.Parse(time.RFC3339, value); err != nil {
+			http.Error(w, errors.New(errors.Invalid, "invalid since %s", value).Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	labels := s.labels.List(since)
+	if r.URL.Query().Get("format") == "csv" {
+		w.Header().Set("Content-Type", "text/csv")
+		w.Header().Set("Content-Disposition", "attachment; filename=labels.csv")
+		if err := WriteLabelsCSV(w, labels); err != nil {
+			log.Warn(err)
+		}
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(labels); err != nil {
+		log.Warn(err)
+	}
+}
+
+func parseFilter(r *http.Request) (Filter, error) {
+	filter := Filter{}
+	values := r.URL.Query()
+	var err error
+	if since := values.Get("since"); since != "" {
+		if filter.Since, err = strconv.ParseUint(since, 10, 64); err != nil {
+			return filter, errors.New(errors.Invalid, "invalid since: %v", err)
+		}
+	}
+	if entity := values.Get("entity"); entity != "" {
+		if filter.EntityID, err = strconv.ParseUint(entity, 10, 64); err != nil {
+			return filter, errors.New(errors.Invalid, "invalid entity: %v", err)
+		}
+	}
+	if limit := values.Get("limit"); limit != "" {
+		if filter.Limit, err = strconv.Atoi(limit); err != nil {
+			return filter, errors.New(errors.Invalid, "invalid limit: %v", err)
+		}
+	}
+	for _, kind := range values["kind"] {
+		filter.Kinds = append(filter.Kinds, Kind(kind))
+	}
+	return filter, nil
+}
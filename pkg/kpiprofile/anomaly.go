@@ -0,0 +1,169 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package kpiprofile
+
+import (
+	"sync"
+	"time"
+
+	"github.com/onosproject/onos-api/go/onos/ransim/types"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/onosproject/ran-simulator/pkg/journal"
+)
+
+// AnomalyKind is a kind of KPI anomaly
+type AnomalyKind string
+
+const (
+	// Drop scales the values abruptly by the factor of the anomaly
+	Drop AnomalyKind = "drop"
+	// Degradation scales the values by a factor decreasing linearly from 1 to the factor of the anomaly
+	Degradation AnomalyKind = "degradation"
+	// Missing withholds the values
+	Missing AnomalyKind = "missing"
+)
+
+// Anomaly is a KPI anomaly of a cell during a period of time
+type Anomaly struct {
+	ID   uint64      `json:"id"`
+	Kind AnomalyKind `json:"kind"`
+	ECGI types.ECGI  `json:"ecgi"`
+	// Measurements are the names of the affected measurements; all measurements are affected if empty
+	Measurements []string  `json:"measurements,omitempty"`
+	Start        time.Time `json:"start"`
+	End          time.Time `json:"end"`
+	// Factor is the factor the values are scaled by at the worst of drops and degradations
+	Factor float64 `json:"factor"`
+	// labelID is the ID of the ground-truth label of the anomaly
+	labelID uint64
+}
+
+// factor returns the factor the anomaly scales the values by at the given time
+func (a *Anomaly) factor(t time.Time) float64 {
+	if a.Kind == Degradation {
+		progress := float64(t.Sub(a.Start)) / float64(a.End.Sub(a.Start))
+		return 1 - (1-a.Factor)*progress
+	}
+	return a.Factor
+}
+
+// affects returns true if the anomaly affects the named measurement of the cell at the given time
+func (a *Anomaly) affects(ecgi types.ECGI, measName string, t time.Time) bool {
+	if a.ECGI != ecgi || t.Before(a.Start) || !t.Before(a.End) {
+		return false
+	}
+	return len(a.Measurements) == 0 || contains(a.Measurements, measName)
+}
+
+// Anomalies keeps the injected KPI anomalies, past ones included as ground truth
+type Anomalies struct {
+	anomalies []*Anomaly
+	nextID    uint64
+	mu        sync.RWMutex
+}
+
+// NewAnomalies creates a new, empty set of anomalies
+func NewAnomalies() *Anomalies {
+	return &Anomalies{nextID: 1}
+}
+
+// Inject schedules the given anomaly, starting now unless its start is given
+func (s *Anomalies) Inject(anomaly Anomaly) (Anomaly, error) {
+	if anomaly.Start.IsZero() {
+		anomaly.Start = time.Now()
+	}
+	switch {
+	case anomaly.Kind != Drop && anomaly.Kind != Degradation && anomaly.Kind != Missing:
+		return Anomaly{}, errors.New(errors.Invalid, "unsupported anomaly kind %s", anomaly.Kind)
+	case anomaly.ECGI == 0:
+		return Anomaly{}, errors.New(errors.Invalid, "cell of the anomaly is required")
+	case !anomaly.End.After(anomaly.Start):
+		return Anomaly{}, errors.New(errors.Invalid, "anomaly must end after its start")
+	case anomaly.Factor < 0:
+		return Anomaly{}, errors.New(errors.Invalid, "factor of the anomaly must not be negative")
+	}
+
+	s.mu.Lock()
+	anomaly.ID = s.nextID
+	s.nextID++
+	anomaly.labelID = journal.DefaultLabels().Add(journal.AnomalyLabel, anomaly.Start, anomaly.End, []uint64{uint64(anomaly.ECGI)}, map[string]interface{}{
+		"anomalyID":    anomaly.ID,
+		"kind":         anomaly.Kind,
+		"measurements": anomaly.Measurements,
+		"factor":       anomaly.Factor,
+	})
+	s.anomalies = append(s.anomalies, &anomaly)
+	s.mu.Unlock()
+
+	log.Infof("Injected %s anomaly %d of cell %d from %s to %s", anomaly.Kind, anomaly.ID, anomaly.ECGI, anomaly.Start, anomaly.End)
+	journal.Record(journal.AnomalyInjected, uint64(anomaly.ECGI), map[string]interface{}{
+		"anomalyID":    anomaly.ID,
+		"kind":         anomaly.Kind,
+		"measurements": anomaly.Measurements,
+		"start":        anomaly.Start,
+		"end":          anomaly.End,
+		"factor":       anomaly.Factor,
+	})
+	return anomaly, nil
+}
+
+// Cancel ends the specified anomaly now; anomalies not started yet are discarded
+func (s *Anomalies) Cancel(id uint64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	for i, anomaly := range s.anomalies {
+		if anomaly.ID != id {
+			continue
+		}
+		if !now.Before(anomaly.End) {
+			return errors.New(errors.Forbidden, "anomaly %d has already ended", id)
+		}
+		if now.Before(anomaly.Start) {
+			s.anomalies = append(s.anomalies[:i], s.anomalies[i+1:]...)
+			journal.DefaultLabels().Remove(anomaly.labelID)
+		} else {
+			anomaly.End = now
+			journal.DefaultLabels().End(anomaly.labelID, now)
+		}
+		journal.Record(journal.AnomalyCancelled, uint64(anomaly.ECGI), map[string]interface{}{
+			"anomalyID": id,
+		})
+		return nil
+	}
+	return errors.New(errors.NotFound, "anomaly %d not found", id)
+}
+
+// List lists all anomalies, past ones included
+func (s *Anomalies) List() []Anomaly {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	list := make([]Anomaly, 0, len(s.anomalies))
+	for _, anomaly := range s.anomalies {
+		list = append(list, *anomaly)
+	}
+	return list
+}
+
+// effect returns the factor the anomalies affecting the named measurement of the cell scale its value by at the
+// given time and false if the value is missing
+func (s *Anomalies) effect(ecgi types.ECGI, measName string, t time.Time) (float64, bool) {
+	if s == nil {
+		return 1, true
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	f := 1.0
+	for _, anomaly := range s.anomalies {
+		if !anomaly.affects(ecgi, measName, t) {
+			continue
+		}
+		if anomaly.Kind == Missing {
+			return 0, false
+		}
+		f *= anomaly.factor(t)
+	}
+	return f, true
+}
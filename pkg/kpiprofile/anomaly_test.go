@@ -0,0 +1,51 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package kpiprofile
+
+import (
+	"testing"
+	"time"
+
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/onosproject/ran-simulator/pkg/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAnomalies(t *testing.T) {
+	anomalies := NewAnomalies()
+	e := NewEngine(&model.Model{}, anomalies)
+	start := time.Now().Add(time.Hour)
+
+	_, err := anomalies.Inject(Anomaly{Kind: "spike", ECGI: 1, End: start})
+	assert.True(t, errors.IsInvalid(err))
+	_, err = anomalies.Inject(Anomaly{Kind: Drop, ECGI: 1, Start: start, End: start})
+	assert.True(t, errors.IsInvalid(err))
+
+	drop, err := anomalies.Inject(Anomaly{Kind: Drop, ECGI: 1, Measurements: []string{"DRB.UEThpDl"}, Start: start, End: start.Add(10 * time.Minute), Factor: 0.2})
+	assert.NoError(t, err)
+	degradation, err := anomalies.Inject(Anomaly{Kind: Degradation, ECGI: 2, Start: start, End: start.Add(10 * time.Minute)})
+	assert.NoError(t, err)
+	_, err = anomalies.Inject(Anomaly{Kind: Missing, ECGI: 3, Start: start, End: start.Add(10 * time.Minute)})
+	assert.NoError(t, err)
+	assert.Len(t, anomalies.List(), 3)
+
+	// Drops scale the affected measurements during the anomaly only
+	assert.Equal(t, 100.0, modulate(e, 1, "DRB.UEThpDl", 100.0, start.Add(-time.Second)))
+	assert.Equal(t, 20.0, modulate(e, 1, "DRB.UEThpDl", 100.0, start))
+	assert.Equal(t, 100.0, modulate(e, 1, "RRC.Conn.Max", 100.0, start))
+	assert.Equal(t, 100.0, modulate(e, 1, "DRB.UEThpDl", 100.0, drop.End))
+
+	// Degradations worsen gradually
+	assert.Equal(t, 50.0, modulate(e, 2, "DRB.UEThpDl", 100.0, start.Add(5*time.Minute)))
+
+	// Missing values are withheld
+	_, ok := e.Modulate(3, "DRB.UEThpDl", 100.0, start)
+	assert.False(t, ok)
+
+	// Anomalies not started yet are discarded when cancelled
+	assert.NoError(t, anomalies.Cancel(degradation.ID))
+	assert.Len(t, anomalies.List(), 2)
+	assert.True(t, errors.IsNotFound(anomalies.Cancel(degradation.ID)))
+}
@@ -0,0 +1,155 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package kpiprofile
+
+import (
+	"math"
+	"math/rand"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/onosproject/onos-api/go/onos/ransim/types"
+	"github.com/onosproject/onos-lib-go/pkg/logging"
+	"github.com/onosproject/ran-simulator/pkg/model"
+)
+
+var log = logging.GetLogger("kpiprofile")
+
+// counterSuffix marks cumulative counters, whose increments rather than values are modulated to keep them monotonic
+const counterSuffix = ".Tot"
+
+// counter tracks the modulated value of a cumulative counter
+type counter struct {
+	raw       uint64
+	modulated float64
+}
+
+type counterKey struct {
+	ecgi     types.ECGI
+	measName string
+}
+
+// Engine modulates the measurement values reported for cells according to their KPI profiles and anomalies
+type Engine struct {
+	profiles  map[types.ECGI]*model.KPIProfile
+	anomalies *Anomalies
+	counters  map[counterKey]*counter
+	mu        sync.Mutex
+}
+
+// NewEngine creates a KPI profile engine for the cells of the model referring to one of its KPI profiles, also
+// applying the given anomalies if any
+func NewEngine(m *model.Model, anomalies *Anomalies) *Engine {
+	e := &Engine{
+		profiles:  make(map[types.ECGI]*model.KPIProfile),
+		anomalies: anomalies,
+		counters:  make(map[counterKey]*counter),
+	}
+	for name, cell := range m.Cells {
+		if cell.KPIProfile == "" {
+			continue
+		}
+		profile, ok := m.KPIProfiles[cell.KPIProfile]
+		if !ok {
+			log.Warnf("Cell %s refers to unknown KPI profile %s", name, cell.KPIProfile)
+			continue
+		}
+		e.profiles[cell.ECGI] = &profile
+	}
+	return e
+}
+
+// Factor returns the factor the values of the specified cell are multiplied with at the given time, excluding noise
+func (e *Engine) Factor(ecgi types.ECGI, t time.Time) float64 {
+	profile, ok := e.profiles[ecgi]
+	if !ok {
+		return 1
+	}
+	return factor(profile, t)
+}
+
+// Modulate returns the modulated value of the named measurement of the specified cell at the given time and false
+// if an anomaly withholds it; values other than uint64 and float64 as well as those of cells without a KPI profile
+// or anomaly are returned unchanged. The increments of cumulative counters, i.e. measurements named *.Tot, are
+// modulated instead of their values.
+func (e *Engine) Modulate(ecgi types.ECGI, measName string, value interface{}, t time.Time) (interface{}, bool) {
+	f, ok := e.anomalies.effect(ecgi, measName, t)
+	if !ok {
+		return nil, false
+	}
+	if profile, ok := e.profiles[ecgi]; ok && applies(profile, measName) {
+		f *= factor(profile, t)
+		if profile.Noise > 0 {
+			f = math.Max(0, f*(1+rand.NormFloat64()*profile.Noise))
+		}
+	}
+	switch v := value.(type) {
+	case uint64:
+		if strings.HasSuffix(measName, counterSuffix) {
+			return e.modulateCounter(counterKey{ecgi: ecgi, measName: measName}, v, f), true
+		}
+		return uint64(math.Round(float64(v) * f)), true
+	case float64:
+		return v * f, true
+	}
+	return value, true
+}
+
+func (e *Engine) modulateCounter(key counterKey, raw uint64, f float64) uint64 {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	c, ok := e.counters[key]
+	if !ok {
+		// Counters start from their current value
+		e.counters[key] = &counter{raw: raw, modulated: float64(raw)}
+		return raw
+	}
+	switch {
+	case raw > c.raw:
+		c.modulated += float64(raw-c.raw) * f
+	case raw < c.raw:
+		// The counter was reset, so is its modulated value
+		c.modulated = float64(raw)
+	}
+	c.raw = raw
+	return uint64(math.Round(c.modulated))
+}
+
+// applies returns true if the profile modulates the named measurement
+func applies(profile *model.KPIProfile, measName string) bool {
+	return len(profile.Measurements) == 0 || contains(profile.Measurements, measName)
+}
+
+func contains(names []string, name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+// factor returns the factor of the profile at the given time
+func factor(profile *model.KPIProfile, t time.Time) float64 {
+	local := t.UTC().Add(time.Duration(profile.UTCOffset * float64(time.Hour)))
+	hour := float64(local.Hour()) + float64(local.Minute())/60 + float64(local.Second())/3600
+
+	f := 1 + profile.DailyAmplitude*math.Cos(2*math.Pi*(hour-profile.PeakHour)/24)
+	for _, busyHour := range profile.BusyHours {
+		width := busyHour.Width
+		if width <= 0 {
+			width = 1
+		}
+		// Distance to the busy hour across midnight
+		d := math.Abs(hour - busyHour.Hour)
+		d = math.Min(d, 24-d)
+		f *= 1 + (busyHour.Factor-1)*math.Exp(-d*d/(2*width*width))
+	}
+	if weekday := local.Weekday(); (weekday == time.Saturday || weekday == time.Sunday) && profile.WeekendFactor > 0 {
+		f *= profile.WeekendFactor
+	}
+	return math.Max(0, f)
+}
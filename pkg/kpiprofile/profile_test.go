@@ -0,0 +1,82 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package kpiprofile
+
+import (
+	"testing"
+	"time"
+
+	"github.com/onosproject/onos-api/go/onos/ransim/types"
+	"github.com/onosproject/ran-simulator/pkg/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFactor(t *testing.T) {
+	e := NewEngine(&model.Model{
+		Cells: map[string]model.Cell{
+			"cell1": {ECGI: 1, KPIProfile: "downtown"},
+			"cell2": {ECGI: 2},
+			"cell3": {ECGI: 3, KPIProfile: "unknown"},
+		},
+		KPIProfiles: map[string]model.KPIProfile{
+			"downtown": {
+				DailyAmplitude: 0.5,
+				PeakHour:       14,
+				BusyHours:      []model.BusyHour{{Hour: 18, Width: 1, Factor: 2}},
+				WeekendFactor:  0.5,
+				UTCOffset:      2,
+			},
+		},
+	}, nil)
+
+	// Wednesday 12:00 UTC is 14:00 local time, i.e. the peak of the daily sinusoid
+	wednesday := time.Date(2021, 6, 2, 12, 0, 0, 0, time.UTC)
+	assert.InDelta(t, 1.5, e.Factor(1, wednesday), 0.01)
+	// ...and the trough twelve hours later
+	assert.InDelta(t, 0.5, e.Factor(1, wednesday.Add(12*time.Hour)), 0.01)
+	// The busy hour at 18:00 local time doubles the sinusoid
+	assert.InDelta(t, 2*(1+0.5*0.5), e.Factor(1, wednesday.Add(4*time.Hour)), 0.01)
+	// Weekends are scaled
+	assert.InDelta(t, 0.75, e.Factor(1, wednesday.Add(3*24*time.Hour)), 0.01)
+
+	// Cells without a known profile are not modulated
+	assert.Equal(t, 1.0, e.Factor(2, wednesday))
+	assert.Equal(t, 1.0, e.Factor(3, wednesday))
+	value, ok := e.Modulate(2, "RRC.Conn.Max", uint64(10), wednesday)
+	assert.True(t, ok)
+	assert.Equal(t, uint64(10), value)
+}
+
+func TestModulate(t *testing.T) {
+	e := NewEngine(&model.Model{
+		Cells: map[string]model.Cell{"cell1": {ECGI: 1, KPIProfile: "flat"}},
+		KPIProfiles: map[string]model.KPIProfile{
+			"flat": {BusyHours: []model.BusyHour{{Hour: 12, Width: 0.001, Factor: 2}}, Measurements: []string{"RRC.Conn.Max", "PEE.AvgPower", "PAG.Att.Tot"}},
+		},
+	}, nil)
+	noon := time.Date(2021, 6, 2, 12, 0, 0, 0, time.UTC)
+	midnight := noon.Add(12 * time.Hour)
+
+	// Gauges are scaled
+	assert.Equal(t, uint64(20), modulate(e, 1, "RRC.Conn.Max", uint64(10), noon))
+	assert.Equal(t, 3.0, modulate(e, 1, "PEE.AvgPower", 1.5, noon))
+	assert.Equal(t, 1.5, modulate(e, 1, "PEE.AvgPower", 1.5, midnight))
+	// Measurements not listed by the profile are not
+	assert.Equal(t, uint64(10), modulate(e, 1, "RRC.Conn.Avg", uint64(10), noon))
+
+	// Counters start from their value and accumulate scaled increments
+	assert.Equal(t, uint64(100), modulate(e, 1, "PAG.Att.Tot", uint64(100), midnight))
+	assert.Equal(t, uint64(120), modulate(e, 1, "PAG.Att.Tot", uint64(110), noon))
+	assert.Equal(t, uint64(130), modulate(e, 1, "PAG.Att.Tot", uint64(120), midnight))
+	assert.Equal(t, uint64(130), modulate(e, 1, "PAG.Att.Tot", uint64(120), noon))
+	// Reset counters restart from their value
+	assert.Equal(t, uint64(0), modulate(e, 1, "PAG.Att.Tot", uint64(0), midnight))
+	assert.Equal(t, uint64(20), modulate(e, 1, "PAG.Att.Tot", uint64(10), noon))
+}
+
+func modulate(e *Engine, ecgi types.ECGI, measName string, value interface{}, t time.Time) interface{} {
+	value, _ = e.Modulate(ecgi, measName, value, t)
+	return value
+}
@@ -0,0 +1,15 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build linux
+// +build linux
+
+package logsink
+
+import "syscall"
+
+// dup2 makes the file descriptor newfd refer to the file of oldfd; Linux on arm64 provides dup3 only
+func dup2(oldfd int, newfd int) error {
+	return syscall.Dup3(oldfd, newfd, 0)
+}
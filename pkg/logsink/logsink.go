@@ -0,0 +1,213 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package logsink changes the levels of the loggers and redirects the log output of the simulator at runtime
+package logsink
+
+import (
+	"bufio"
+	"log/syslog"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
+
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/onosproject/onos-lib-go/pkg/logging"
+)
+
+var log = logging.GetLogger("logsink")
+
+// SinkType is the type of a log sink
+type SinkType string
+
+const (
+	// Stdout writes the logs to the standard output and error of the process, as configured at startup
+	Stdout SinkType = "stdout"
+	// File appends the logs to a file
+	File SinkType = "file"
+	// Syslog sends the logs to a syslog daemon, one message per line
+	Syslog SinkType = "syslog"
+)
+
+// Sink is the destination of the log output of the simulator
+type Sink struct {
+	Type SinkType `json:"type"`
+	// Path is the path of the file sink
+	Path string `json:"path,omitempty"`
+	// Address is the address of the syslog daemon, e.g. udp://localhost:514; the local daemon if empty
+	Address string `json:"address,omitempty"`
+	// Tag is the tag of the syslog messages; the name of the process if empty
+	Tag string `json:"tag,omitempty"`
+}
+
+// Confine resolves the path of the file sink relative to the specified directory, rejecting paths outside of it, so
+// that remote users can only write logs where the operator allows them to; file sinks are rejected altogether if no
+// directory is given. Other sinks are returned unchanged
+func Confine(sink Sink, dir string) (Sink, error) {
+	if sink.Type != File {
+		return sink, nil
+	}
+	if dir == "" {
+		return sink, errors.New(errors.Forbidden, "file sinks are disabled; no log directory is configured")
+	}
+	if sink.Path == "" {
+		return sink, errors.New(errors.Invalid, "file sink requires a path")
+	}
+	path := sink.Path
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(dir, path)
+	}
+	rel, err := filepath.Rel(filepath.Clean(dir), filepath.Clean(path))
+	if err != nil || rel == "." || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return sink, errors.New(errors.Forbidden, "log file %s is outside of the log directory %s", sink.Path, dir)
+	}
+	sink.Path = filepath.Join(dir, rel)
+	return sink, nil
+}
+
+// ParseLevel parses the logger level, i.e. debug, info, warn or error
+func ParseLevel(level string) (logging.Level, error) {
+	for _, l := range []logging.Level{logging.DebugLevel, logging.InfoLevel, logging.WarnLevel, logging.ErrorLevel} {
+		if strings.EqualFold(level, l.String()) {
+			return l, nil
+		}
+	}
+	return logging.EmptyLevel, errors.New(errors.Invalid, "unknown log level %s", level)
+}
+
+// SetLevel sets the level of the logger with the specified name, e.g. sm/kpm2, and of its descendants unless set
+func SetLevel(name string, level logging.Level) {
+	logging.GetLogger(name).SetLevel(level)
+	log.Infof("Set level of logger %s to %s", name, level)
+}
+
+// GetLevel returns the level of the logger with the specified name
+func GetLevel(name string) logging.Level {
+	return logging.GetLogger(name).GetLevel()
+}
+
+// stdFds are the file descriptors the loggers write to, i.e. the standard output and error
+var stdFds = []int{syscall.Stdout, syscall.Stderr}
+
+var (
+	mu      sync.Mutex
+	current = Sink{Type: Stdout}
+	// saved are the duplicates of the original standard output and error
+	saved []int
+)
+
+// Current returns the current sink
+func Current() Sink {
+	mu.Lock()
+	defer mu.Unlock()
+	return current
+}
+
+// Redirect redirects the standard output and error of the process, which the loggers write to, to the sink; the
+// file descriptors themselves are redirected as the onos-lib-go loggers keep writing to them once configured
+func Redirect(sink Sink) error {
+	mu.Lock()
+	defer mu.Unlock()
+
+	var target *os.File
+	switch sink.Type {
+	case Stdout:
+		if saved == nil {
+			current = sink
+			return nil
+		}
+	case File:
+		if sink.Path == "" {
+			return errors.New(errors.Invalid, "file sink requires a path")
+		}
+		file, err := os.OpenFile(sink.Path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+		if err != nil {
+			return errors.New(errors.Invalid, "unable to open log file %s: %v", sink.Path, err)
+		}
+		target = file
+	case Syslog:
+		writer, err := dialSyslog(sink)
+		if err != nil {
+			return err
+		}
+		reader, pipe, err := os.Pipe()
+		if err != nil {
+			_ = writer.Close()
+			return err
+		}
+		go forward(reader, writer)
+		target = pipe
+	default:
+		return errors.New(errors.Invalid, "unknown sink type %s", sink.Type)
+	}
+
+	if saved == nil {
+		for _, fd := range stdFds {
+			dup, err := syscall.Dup(fd)
+			if err != nil {
+				closeFile(target)
+				return err
+			}
+			saved = append(saved, dup)
+		}
+	}
+	for i, fd := range stdFds {
+		source := saved[i]
+		if target != nil {
+			source = int(target.Fd())
+		}
+		if err := dup2(source, fd); err != nil {
+			closeFile(target)
+			return err
+		}
+	}
+	// The standard output and error now refer to the sink on their own; the pipe of a syslog sink is closed for good,
+	// stopping its forwarder, once they are redirected elsewhere
+	closeFile(target)
+	current = sink
+	log.Infof("Redirected logs to %s sink", sink.Type)
+	return nil
+}
+
+func closeFile(file *os.File) {
+	if file != nil {
+		_ = file.Close()
+	}
+}
+
+// dialSyslog connects to the syslog daemon of the sink
+func dialSyslog(sink Sink) (*syslog.Writer, error) {
+	network, address := "", ""
+	if sink.Address != "" {
+		u, err := url.Parse(sink.Address)
+		if err != nil || u.Host == "" {
+			return nil, errors.New(errors.Invalid, "invalid syslog address %s", sink.Address)
+		}
+		network, address = u.Scheme, u.Host
+	}
+	writer, err := syslog.Dial(network, address, syslog.LOG_INFO|syslog.LOG_DAEMON, sink.Tag)
+	if err != nil {
+		return nil, errors.New(errors.Unavailable, "unable to connect to syslog: %v", err)
+	}
+	return writer, nil
+}
+
+// forward forwards the lines read from the pipe to syslog until the pipe is closed
+func forward(reader *os.File, writer *syslog.Writer) {
+	defer reader.Close()
+	defer writer.Close()
+	lines := bufio.NewReader(reader)
+	for {
+		line, err := lines.ReadString('\n')
+		if line = strings.TrimRight(line, "\n"); line != "" {
+			_ = writer.Info(line)
+		}
+		if err != nil {
+			return
+		}
+	}
+}
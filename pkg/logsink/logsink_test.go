@@ -0,0 +1,92 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package logsink
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/onosproject/onos-lib-go/pkg/logging"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLevels(t *testing.T) {
+	level, err := ParseLevel("Debug")
+	assert.NoError(t, err)
+	assert.Equal(t, logging.DebugLevel, level)
+	_, err = ParseLevel("verbose")
+	assert.Error(t, err)
+
+	SetLevel("logsink/test", logging.WarnLevel)
+	assert.Equal(t, logging.WarnLevel, GetLevel("logsink/test"))
+	assert.Equal(t, logging.WarnLevel, GetLevel("logsink/test/child"))
+}
+
+func TestRedirect(t *testing.T) {
+	dir, err := ioutil.TempDir("", "logsink")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "ransim.log")
+
+	assert.Error(t, Redirect(Sink{Type: File}))
+	assert.Error(t, Redirect(Sink{Type: "kafka"}))
+
+	assert.NoError(t, Redirect(Sink{Type: File, Path: path}))
+	assert.Equal(t, File, Current().Type)
+	fmt.Println("redirected to file")
+	assert.NoError(t, Redirect(Sink{Type: Stdout}))
+	fmt.Println("restored to stdout")
+	data, err := ioutil.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), "redirected to file")
+	assert.NotContains(t, string(data), "restored to stdout")
+
+	listener, err := net.ListenPacket("udp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	defer listener.Close()
+	assert.NoError(t, Redirect(Sink{Type: Syslog, Address: "udp://" + listener.LocalAddr().String(), Tag: "ransim"}))
+	fmt.Println("redirected to syslog")
+	assert.NoError(t, Redirect(Sink{Type: Stdout}))
+
+	buf := make([]byte, 4096)
+	found := false
+	for !found {
+		assert.NoError(t, listener.SetReadDeadline(time.Now().Add(5*time.Second)))
+		n, _, err := listener.ReadFrom(buf)
+		if !assert.NoError(t, err) {
+			break
+		}
+		found = strings.Contains(string(buf[:n]), "redirected to syslog")
+	}
+	assert.True(t, found)
+}
+
+func TestConfine(t *testing.T) {
+	sink, err := Confine(Sink{Type: File, Path: "ransim.log"}, "/var/log/ransim")
+	assert.NoError(t, err)
+	assert.Equal(t, "/var/log/ransim/ransim.log", sink.Path)
+	sink, err = Confine(Sink{Type: File, Path: "/var/log/ransim/run/1.log"}, "/var/log/ransim/")
+	assert.NoError(t, err)
+	assert.Equal(t, "/var/log/ransim/run/1.log", sink.Path)
+
+	_, err = Confine(Sink{Type: File, Path: "../ransim.log"}, "/var/log/ransim")
+	assert.Error(t, err)
+	_, err = Confine(Sink{Type: File, Path: "/etc/passwd"}, "/var/log/ransim")
+	assert.Error(t, err)
+	_, err = Confine(Sink{Type: File, Path: "/var/log/ransim"}, "/var/log/ransim")
+	assert.Error(t, err)
+	_, err = Confine(Sink{Type: File, Path: "ransim.log"}, "")
+	assert.Error(t, err)
+
+	sink, err = Confine(Sink{Type: Syslog, Tag: "ransim"}, "")
+	assert.NoError(t, err)
+	assert.Equal(t, "ransim", sink.Tag)
+}
@@ -6,47 +6,121 @@ package manager
 
 import (
 	"context"
+	"crypto/tls"
+	"fmt"
 	"github.com/onosproject/ran-simulator/pkg/store/routes"
+	"os"
+	"sync"
 	"time"
 
+	"github.com/onosproject/onos-api/go/onos/ransim/types"
+	topoapi "github.com/onosproject/onos-api/go/onos/topo"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
 	"github.com/onosproject/onos-lib-go/pkg/logging"
-	"github.com/onosproject/onos-lib-go/pkg/northbound"
+	service "github.com/onosproject/onos-lib-go/pkg/northbound"
+	"github.com/onosproject/onos-ric-sdk-go/pkg/e2/creds"
+	"github.com/onosproject/ran-simulator/pkg/a1"
+	"github.com/onosproject/ran-simulator/pkg/admin"
 	cellapi "github.com/onosproject/ran-simulator/pkg/api/cells"
 	metricsapi "github.com/onosproject/ran-simulator/pkg/api/metrics"
 	modelapi "github.com/onosproject/ran-simulator/pkg/api/model"
 	nodeapi "github.com/onosproject/ran-simulator/pkg/api/nodes"
+	routeapi "github.com/onosproject/ran-simulator/pkg/api/routes"
 	"github.com/onosproject/ran-simulator/pkg/api/trafficsim"
+	"github.com/onosproject/ran-simulator/pkg/auth"
+	"github.com/onosproject/ran-simulator/pkg/core"
+	"github.com/onosproject/ran-simulator/pkg/e2agent"
 	"github.com/onosproject/ran-simulator/pkg/e2agent/agents"
+	"github.com/onosproject/ran-simulator/pkg/energy"
+	"github.com/onosproject/ran-simulator/pkg/export"
+	"github.com/onosproject/ran-simulator/pkg/faults"
+	"github.com/onosproject/ran-simulator/pkg/geofence"
+	"github.com/onosproject/ran-simulator/pkg/gnmi"
+	"github.com/onosproject/ran-simulator/pkg/health"
+	"github.com/onosproject/ran-simulator/pkg/iot"
+	"github.com/onosproject/ran-simulator/pkg/journal"
+	"github.com/onosproject/ran-simulator/pkg/kpiprofile"
+	"github.com/onosproject/ran-simulator/pkg/mobility"
 	"github.com/onosproject/ran-simulator/pkg/model"
 	"github.com/onosproject/ran-simulator/pkg/modelplugins"
+	"github.com/onosproject/ran-simulator/pkg/northbound"
+	"github.com/onosproject/ran-simulator/pkg/o1"
+	"github.com/onosproject/ran-simulator/pkg/qos"
+	"github.com/onosproject/ran-simulator/pkg/scenario"
+	"github.com/onosproject/ran-simulator/pkg/servicemodel/kpm2"
 	"github.com/onosproject/ran-simulator/pkg/servicemodel/rc/pciload"
+	"github.com/onosproject/ran-simulator/pkg/shard"
+	"github.com/onosproject/ran-simulator/pkg/stats"
 	"github.com/onosproject/ran-simulator/pkg/store/cells"
+	"github.com/onosproject/ran-simulator/pkg/store/distributed"
 	"github.com/onosproject/ran-simulator/pkg/store/metrics"
 	"github.com/onosproject/ran-simulator/pkg/store/nodes"
+	"github.com/onosproject/ran-simulator/pkg/store/subscriptions"
 	"github.com/onosproject/ran-simulator/pkg/store/ues"
+	"github.com/onosproject/ran-simulator/pkg/store/watcher"
+	"github.com/onosproject/ran-simulator/pkg/tenant"
+	"github.com/onosproject/ran-simulator/pkg/topo"
+	"github.com/onosproject/ran-simulator/pkg/voice"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 )
 
 var log = logging.GetLogger("manager")
 
+// connectionPollInterval is the interval the connection states of the E2 nodes are checked at while waiting for them
+// to connect
+const connectionPollInterval = 100 * time.Millisecond
+
 // Config is a manager configuration
 type Config struct {
 	CAPath              string
 	KeyPath             string
 	CertPath            string
+	ClientAuth          bool
+	E2TLS               bool
+	Auth                auth.Config
 	GRPCPort            int
+	O1Port              int
+	A1Port              int
+	JournalPort         int
+	ScenarioPort        int
+	AdminPort           int
+	AdminPprof          bool
+	JournalPath         string
 	ServiceModelPlugins []string
 	ModelName           string
 	MetricName          string
+	FaultMTBF           time.Duration
+	FaultMTTR           time.Duration
+	ExportInterval      time.Duration
+	ExportCSVPath       string
+	ExportInfluxURL     string
+	TopoAddress         string
+	E2CaptureDir        string
+	LogDir              string
+	E2SetupParallelism  int
+	Shard               shard.Config
+	Store               distributed.Config
+	// Tenants are simulated next to the model of the manager, simulating the default tenant
+	Tenants []Tenant
+	// Tenant is the name of the tenant simulated by the manager of another manager, if any
+	Tenant string
+	// ModelPluginRegistry is the registry of the model plugins used instead of loading the service model plugins, e.g.
+	// in-memory plugins in tests
+	ModelPluginRegistry modelplugins.ModelRegistry
 }
 
 // NewManager creates a new manager
 func NewManager(config *Config) (*Manager, error) {
 	log.Info("Creating Manager")
 
-	modelPluginRegistry := modelplugins.NewModelRegistry()
-	for _, smp := range config.ServiceModelPlugins {
-		if _, _, err := modelPluginRegistry.RegisterModelPlugin(smp); err != nil {
-			log.Error(err)
+	modelPluginRegistry := config.ModelPluginRegistry
+	if modelPluginRegistry == nil {
+		modelPluginRegistry = modelplugins.NewModelRegistry()
+		for _, smp := range config.ServiceModelPlugins {
+			if _, _, err := modelPluginRegistry.RegisterModelPlugin(smp); err != nil {
+				log.Error(err)
+			}
 		}
 	}
 
@@ -55,6 +129,13 @@ func NewManager(config *Config) (*Manager, error) {
 		agents:              nil,
 		model:               &model.Model{},
 		modelPluginRegistry: modelPluginRegistry,
+		policyStore:         a1.NewStore(),
+		anomalies:           kpiprofile.NewAnomalies(),
+		authorizer:          auth.NewAuthorizer(config.Auth),
+		tenants:             make(map[string]*Manager),
+	}
+	if config.Tenant != "" || len(config.Tenants) > 0 {
+		mgr.backend = tenant.NewBackend()
 	}
 
 	return mgr, nil
@@ -63,16 +144,58 @@ func NewManager(config *Config) (*Manager, error) {
 // Manager is a manager for the E2T service
 type Manager struct {
 	modelapi.ManagementDelegate
-	config              Config
-	agents              *agents.E2Agents
-	model               *model.Model
-	modelPluginRegistry modelplugins.ModelRegistry
-	server              *northbound.Server
-	nodeStore           nodes.Store
-	cellStore           cells.Store
-	ueStore             ues.Store
-	routeStore          routes.Store
-	metricsStore        metrics.Store
+	config                Config
+	agents                *agents.E2Agents
+	model                 *model.Model
+	modelPluginRegistry   modelplugins.ModelRegistry
+	server                *northbound.Server
+	authorizer            *auth.Authorizer
+	nodeStore             nodes.Store
+	cellStore             cells.Store
+	ueStore               ues.Store
+	routeStore            routes.Store
+	metricsStore          metrics.Store
+	qosController         *qos.Controller
+	energyController      *energy.Controller
+	healthController      *health.Controller
+	cellStateController   *mobility.CellStateController
+	rrcController         *mobility.RrcController
+	uavController         *mobility.UAVController
+	core                  *core.Core
+	voice                 *voice.Controller
+	iot                   *iot.Controller
+	measurementController *mobility.MeasurementController
+	geofenceController    *geofence.Controller
+	faultInjector         *faults.Injector
+	o1Server              *o1.Server
+	a1Server              *a1.Server
+	policyStore           *a1.Store
+	anomalies             *kpiprofile.Anomalies
+	handover              *mobility.HandoverEngine
+	partition             *shard.Partition
+	transferrer           *shard.Transferrer
+	maps                  *distributed.Maps
+	exporter              *export.Exporter
+	journalServer         *journal.Server
+	journalFile           *os.File
+	scenarioServer        *scenario.Server
+	adminServer           *admin.Server
+	topoConn              *grpc.ClientConn
+	topoRegistrar         *topo.Registrar
+	start                 time.Time
+
+	// backend serves the northbound services of the tenant simulated by the manager, if any
+	backend      *tenant.Backend
+	router       *tenant.Router
+	routerServer *northbound.Server
+	tenants      map[string]*Manager
+	tenantConns  []*grpc.ClientConn
+
+	// runMu serializes the changes of the run lifecycle
+	runMu     sync.Mutex
+	running   bool
+	iteration uint64
+	runSince  time.Time
 }
 
 // Run starts the manager and the associated services
@@ -85,26 +208,58 @@ func (m *Manager) Run() {
 
 // Start starts the manager
 func (m *Manager) Start() error {
+	m.start = time.Now()
+
+	// Persist the simulation milestones, if requested
+	err := m.startJournal()
+	if err != nil {
+		return err
+	}
+
 	// Load the model data
-	err := model.Load(m.model, m.config.ModelName)
+	err = model.Load(m.model, m.config.ModelName)
 	if err != nil {
 		log.Error(err)
 		return err
 	}
+	if err := m.partitionModel(); err != nil {
+		log.Error(err)
+		return err
+	}
 
-	m.initModelStores()
+	if err := m.initModelStores(); err != nil {
+		log.Error(err)
+		return err
+	}
 	m.initMetricStore()
 
+	// Start the DRB, energy-saving and cell state controllers, the fault injector and the KPI exporter
+	err = m.startControllers()
+	if err != nil {
+		return err
+	}
+
 	// Start gRPC server
 	err = m.startNorthboundServer()
 	if err != nil {
 		return err
 	}
+	m.startO1Server()
+	m.startA1Server()
+	m.startScenarioServer()
+	m.startAdminServer()
 	// Start E2 agents
 	err = m.startE2Agents()
 	if err != nil {
 		return err
 	}
+	m.running = true
+	m.runSince = m.start
+
+	// Start the tenants simulated next to the model
+	if len(m.config.Tenants) > 0 {
+		return m.startTenants()
+	}
 
 	return nil
 }
@@ -112,22 +267,106 @@ func (m *Manager) Start() error {
 // Close kills the channels and manager related objects
 func (m *Manager) Close() {
 	log.Info("Closing Manager")
+	m.stopTenants()
 	m.stopE2Agents()
 	m.stopNorthboundServer()
+	m.stopO1Server()
+	m.stopA1Server()
+	m.stopScenarioServer()
+	m.stopAdminServer()
+	m.stopControllers()
+	m.stopJournal()
+	if m.topoConn != nil {
+		_ = m.topoConn.Close()
+	}
+	if m.transferrer != nil {
+		m.transferrer.Close()
+	}
+	m.closeMaps()
 }
 
-func (m *Manager) initModelStores() {
+// partitionModel restricts the model to the share of this instance if the model is shared by several instances
+func (m *Manager) partitionModel() error {
+	if !m.config.Shard.Enabled() {
+		return nil
+	}
+	partition, err := shard.NewPartition(m.config.Shard, m.model)
+	if err != nil {
+		return err
+	}
+	partition.Apply(m.model)
+	m.partition = partition
+	if m.transferrer != nil {
+		m.transferrer.Close()
+	}
+	var dialOpts []grpc.DialOption
+	if peerCreds, ok := m.authorizer.PeerCredentials(); ok {
+		dialOpts = append(dialOpts, grpc.WithPerRPCCredentials(peerCreds))
+	}
+	m.transferrer, err = shard.NewTransferrer(partition, dialOpts...)
+	return err
+}
+
+func (m *Manager) initModelStores() error {
+	// Create an empty route registry
+	m.routeStore = routes.NewRouteRegistry()
+
+	if m.config.Store.Enabled() {
+		return m.initDistributedStores()
+	}
+
 	// Create the node registry primed with the pre-loaded nodes
 	m.nodeStore = nodes.NewNodeRegistry(m.model.Nodes)
 
 	// Create the cell registry primed with the pre-loaded cells
 	m.cellStore = cells.NewCellRegistry(m.model.Cells, m.nodeStore)
 
-	// Create the UE registry primed with the specified number of UEs
-	m.ueStore = ues.NewUERegistry(m.model.UECount, m.cellStore)
+	// Create the UE registry primed with the specified number of UEs, compact for large UE counts
+	if threshold := m.model.UEStore.CompactThreshold; threshold > 0 && m.model.UECount >= threshold {
+		m.ueStore = ues.NewCompactUERegistry(m.model.UECount, m.cellStore, m.model.Placement)
+		return nil
+	}
+	m.ueStore = ues.NewUERegistryWithPlacement(m.model.UECount, m.cellStore, m.model.Placement)
+	return nil
+}
 
-	// Create an empty route registry
-	m.routeStore = routes.NewRouteRegistry()
+// initDistributedStores creates the node, cell and UE registries backed by the Atomix database, restoring the
+// state of the nodes and cells owned by this instance, if the database holds any, or priming it with the model
+func (m *Manager) initDistributedStores() error {
+	if err := m.config.Store.Validate(); err != nil {
+		return err
+	}
+	ctx := context.Background()
+	m.closeMaps()
+	maps, err := distributed.Open(ctx, m.config.Store)
+	if err != nil {
+		return err
+	}
+	m.maps = maps
+
+	ownsNode := func(enbID types.EnbID) bool {
+		return m.partition == nil || m.partition.Owns(enbID)
+	}
+	ownsCell := func(ecgi types.ECGI) bool {
+		return m.partition == nil || !m.partition.IsRemote(ecgi)
+	}
+	if m.nodeStore, err = nodes.NewAtomixNodeRegistry(ctx, maps.Nodes, m.model.Nodes, ownsNode); err != nil {
+		return err
+	}
+	if m.cellStore, err = cells.NewAtomixCellRegistry(ctx, maps.Cells, m.model.Cells, m.nodeStore, ownsCell); err != nil {
+		return err
+	}
+	m.ueStore, err = ues.NewAtomixUERegistry(ctx, maps.UEs, m.model.UECount, m.cellStore, m.model.Placement, ownsCell)
+	return err
+}
+
+func (m *Manager) closeMaps() {
+	if m.maps != nil {
+		if err := m.maps.Close(); err != nil {
+			log.Warn(err)
+		}
+		m.maps = nil
+	}
 }
 
 func (m *Manager) initMetricStore() {
@@ -140,25 +379,49 @@ func (m *Manager) initMetricStore() {
 
 // startSouthboundServer starts the northbound gRPC server
 func (m *Manager) startNorthboundServer() error {
-	m.server = northbound.NewServer(northbound.NewServerCfg(
-		m.config.CAPath,
-		m.config.KeyPath,
-		m.config.CertPath,
-		int16(m.config.GRPCPort),
-		true,
-		northbound.SecurityConfig{}))
+	m.server = northbound.NewServer(m.northboundConfig(),
+		grpc.ChainUnaryInterceptor(m.authorizer.UnaryServerInterceptor()),
+		grpc.ChainStreamInterceptor(m.authorizer.StreamServerInterceptor()))
 
 	m.server.AddService(logging.Service{})
-	m.server.AddService(nodeapi.NewService(m.nodeStore, m.model.PlmnID))
+	m.server.AddService(nodeapi.NewService(m.nodeStore, m.model.PlmnID, m))
+	m.server.AddService(routeapi.NewService(m.routeStore))
 	m.server.AddService(cellapi.NewService(m.cellStore))
-	m.server.AddService(trafficsim.NewService(m.model, m.cellStore, m.ueStore))
+	m.server.AddService(trafficsim.NewService(m.model, m.cellStore, m.ueStore, m.routeStore, m.metricsStore))
 	m.server.AddService(metricsapi.NewService(m.metricsStore))
 	m.server.AddService(modelapi.NewService(m))
+	m.server.AddService(gnmi.NewService(m.nodeStore, m.cellStore, m.ueStore, m.metricsStore))
+	if m.config.Shard.Enabled() {
+		m.server.AddService(shard.NewService(m.cellStore, m.ueStore))
+	}
+
+	// The services of tenants are only reachable in-process, through the tenant router
+	if m.backend != nil {
+		return serveNorthbound("NBI", func(started func(string)) error {
+			return m.server.ServeListener(m.backend.Listen(), started)
+		})
+	}
+	return serveNorthbound("NBI", m.server.Serve)
+}
 
+// northboundConfig returns the configuration of the northbound gRPC server; unless client authentication is
+// required, client certificates are only verified if presented
+func (m *Manager) northboundConfig() *service.ServerConfig {
+	return service.NewServerCfg(
+		m.config.CAPath,
+		m.config.KeyPath,
+		m.config.CertPath,
+		int16(m.config.GRPCPort),
+		!m.config.ClientAuth,
+		service.SecurityConfig{})
+}
+
+// serveNorthbound starts serving in the background, returning once the server listens or failed to
+func serveNorthbound(name string, serve func(started func(string)) error) error {
 	doneCh := make(chan error)
 	go func() {
-		err := m.server.Serve(func(started string) {
-			log.Info("Started NBI on ", started)
+		err := serve(func(started string) {
+			log.Infof("Started %s on %s", name, started)
 			close(doneCh)
 		})
 		if err != nil {
@@ -168,11 +431,134 @@ func (m *Manager) startNorthboundServer() error {
 	return <-doneCh
 }
 
+// startO1Server starts the O1 configuration server, unless disabled
+func (m *Manager) startO1Server() {
+	if m.config.O1Port == 0 {
+		return
+	}
+	m.o1Server = o1.NewServer(o1.NewDatastore(m.nodeStore, m.cellStore, m.metricsStore), m.config.O1Port)
+	m.o1Server.Use(m.authorizer.Handler)
+	m.o1Server.Serve()
+}
+
+func (m *Manager) stopO1Server() {
+	if m.o1Server != nil {
+		m.o1Server.Stop()
+	}
+}
+
+// startJournal starts persisting the journal and serving journal queries, as configured
+func (m *Manager) startJournal() error {
+	if m.config.JournalPath != "" {
+		file, err := os.OpenFile(m.config.JournalPath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+		if err != nil {
+			return err
+		}
+		m.journalFile = file
+		journal.Default().SetOutput(file)
+	}
+	if m.config.JournalPort != 0 {
+		m.journalServer = journal.NewServer(journal.Default(), journal.DefaultLabels(), m.config.JournalPort)
+		m.journalServer.Use(m.authorizer.Handler)
+		m.journalServer.Serve()
+	}
+	return nil
+}
+
+func (m *Manager) stopJournal() {
+	if m.journalServer != nil {
+		m.journalServer.Stop()
+	}
+	if m.journalFile != nil {
+		journal.Default().SetOutput(nil)
+		_ = m.journalFile.Close()
+	}
+}
+
+// startA1Server starts the A1 policy server, unless disabled
+func (m *Manager) startA1Server() {
+	if m.config.A1Port == 0 {
+		return
+	}
+	m.a1Server = a1.NewServer(m.policyStore, m.config.A1Port, m.applyPolicies)
+	m.a1Server.Use(m.authorizer.Handler)
+	m.a1Server.Serve()
+}
+
+func (m *Manager) stopA1Server() {
+	if m.a1Server != nil {
+		m.a1Server.Stop()
+	}
+}
+
+// startScenarioServer starts the scenario server, unless disabled
+func (m *Manager) startScenarioServer() {
+	if m.config.ScenarioPort == 0 {
+		return
+	}
+	m.scenarioServer = scenario.NewServer(m, m.anomalies, m.config.ScenarioPort)
+	m.scenarioServer.Use(m.authorizer.Handler)
+	m.scenarioServer.Serve()
+}
+
+func (m *Manager) stopScenarioServer() {
+	if m.scenarioServer != nil {
+		m.scenarioServer.Stop()
+	}
+}
+
+// startAdminServer starts the admin server, unless disabled
+func (m *Manager) startAdminServer() {
+	if m.config.AdminPort == 0 {
+		return
+	}
+	m.adminServer = admin.NewServer(m, m, m, m, m, m.config.AdminPort)
+	m.adminServer.SetLogDir(m.config.LogDir)
+	if m.config.AdminPprof {
+		m.adminServer.EnablePprof(m.authorizer.AdminHandler)
+	}
+	m.adminServer.Use(m.authorizer.Handler)
+	m.adminServer.Serve()
+}
+
+func (m *Manager) stopAdminServer() {
+	if m.adminServer != nil {
+		m.adminServer.Stop()
+	}
+}
+
+// applyPolicies hands over UEs as required by the current policies
+func (m *Manager) applyPolicies() {
+	if m.handover != nil {
+		m.handover.ApplyPolicies(context.Background())
+	}
+}
+
 func (m *Manager) startE2Agents() error {
+	// Capture the E2AP traffic of the nodes, if requested
+	if m.config.E2CaptureDir != "" {
+		if err := os.MkdirAll(m.config.E2CaptureDir, 0755); err != nil {
+			return err
+		}
+	}
+
+	// Secure the E2 connections with TLS, if requested
+	var tlsConfig *tls.Config
+	if m.config.E2TLS {
+		var err error
+		if tlsConfig, err = e2agent.NewTLSConfig(m.config.CAPath, m.config.KeyPath, m.config.CertPath); err != nil {
+			return err
+		}
+	}
+
 	// Create the E2 agents for all simulated nodes and specified controllers
 	var err error
 	m.agents, err = agents.NewE2Agents(m.model, m.modelPluginRegistry,
-		m.nodeStore, m.ueStore, m.cellStore, m.metricsStore)
+		m.nodeStore, m.ueStore, m.cellStore, m.metricsStore, m.config.E2SetupParallelism, e2agent.Options{
+			CaptureDir: m.config.E2CaptureDir,
+			TLSConfig:  tlsConfig,
+			Anomalies:  m.anomalies,
+		})
 	if err != nil {
 		log.Error(err)
 		return err
@@ -186,12 +572,308 @@ func (m *Manager) startE2Agents() error {
 	return nil
 }
 
+func (m *Manager) startControllers() error {
+	m.qosController = qos.NewController(m.ueStore, m.metricsStore)
+	for _, counter := range qos.Counters() {
+		if err := kpm2.RegisterMetricMeasType(counter); err != nil {
+			return err
+		}
+	}
+	if err := m.qosController.Start(); err != nil {
+		return err
+	}
+	m.energyController = energy.NewController(m.cellStore, m.ueStore, m.metricsStore)
+	if err := m.energyController.Start(); err != nil {
+		return err
+	}
+	m.healthController = health.NewController(m.cellStore, m.ueStore, m.metricsStore)
+	if err := m.healthController.Start(); err != nil {
+		return err
+	}
+	m.handover = mobility.NewHandoverEngine(m.cellStore, m.ueStore, m.metricsStore)
+	m.handover.SetPolicies(m.policyStore)
+	m.handover.SetHandoverConfig(m.model.Handover)
+	m.handover.SetRlfConfig(m.model.RLF)
+	if m.transferrer != nil {
+		m.handover.SetTransferrer(m.transferrer)
+	}
+	m.cellStateController = mobility.NewCellStateController(m.cellStore, m.metricsStore, m.handover)
+	if err := m.cellStateController.Start(); err != nil {
+		return err
+	}
+	m.rrcController = mobility.NewRrcController(m.cellStore, m.ueStore, m.metricsStore, m.model.RRC)
+	m.rrcController.Start()
+	m.uavController = mobility.NewUAVController(m.ueStore, m.model.Placement)
+	m.uavController.Start()
+	if m.model.Core.Enabled {
+		m.core = core.NewCore(m.ueStore, m.metricsStore, m.model.Core)
+		m.core.SetProfiles(m.model.RRC.Profiles)
+		m.core.SetPager(m.rrcController)
+		for _, counter := range core.Counters() {
+			if err := kpm2.RegisterMetricMeasType(counter); err != nil {
+				return err
+			}
+		}
+		m.core.Start()
+	}
+	if m.model.Voice.Enabled {
+		m.voice = voice.NewController(m.ueStore, m.metricsStore, m.model.Voice)
+		m.voice.SetConnector(m.rrcController)
+		for _, counter := range voice.Counters() {
+			if err := kpm2.RegisterMetricMeasType(counter); err != nil {
+				return err
+			}
+		}
+		m.voice.Start()
+	}
+	if len(m.model.MassiveIoT.Classes) > 0 {
+		m.iot = iot.NewController(m.cellStore, m.metricsStore, m.model.MassiveIoT)
+		for _, counter := range iot.Counters() {
+			if err := kpm2.RegisterMetricMeasType(counter); err != nil {
+				return err
+			}
+		}
+		if err := m.iot.Start(); err != nil {
+			return err
+		}
+	}
+	m.measurementController = mobility.NewMeasurementController(m.cellStore, m.ueStore, m.metricsStore)
+	m.measurementController.SetRadioLinkMonitoring(m.model.RLF, m.handover)
+	if m.partition != nil {
+		m.measurementController.SetRemoteCells(m.partition)
+	}
+	m.measurementController.SetBlockage(m.model.Blockage)
+	m.measurementController.SetIndoor(m.model.Indoor)
+	m.measurementController.SetMobility(m.model.Mobility)
+	m.measurementController.SetPowerSaving(m.model.RRC.Profiles)
+	m.measurementController.Start()
+	m.geofenceController = geofence.NewController(m.model.Geofences, m.ueStore, m.metricsStore)
+	for _, counter := range m.geofenceController.Counters() {
+		if err := kpm2.RegisterMetricMeasType(counter); err != nil {
+			return err
+		}
+	}
+	if err := m.geofenceController.Start(); err != nil {
+		return err
+	}
+	m.faultInjector = faults.NewInjector(m.cellStore, m.nodeStore, m.metricsStore, m, m.handover)
+	if err := m.faultInjector.Start(m.config.FaultMTBF, m.config.FaultMTTR); err != nil {
+		return err
+	}
+	if err := m.startTopoRegistrar(); err != nil {
+		return err
+	}
+	return m.startExporter()
+}
+
+// startTopoRegistrar starts registering the nodes and cells in onos-topo, if its address is configured
+func (m *Manager) startTopoRegistrar() error {
+	if m.config.TopoAddress == "" {
+		return nil
+	}
+	if m.topoConn == nil {
+		tlsConfig, err := creds.GetClientCredentials()
+		if err != nil {
+			return err
+		}
+		conn, err := grpc.DialContext(context.Background(), m.config.TopoAddress, grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig)))
+		if err != nil {
+			return err
+		}
+		m.topoConn = conn
+	}
+	m.topoRegistrar = topo.NewRegistrar(topoapi.CreateTopoClient(m.topoConn), m.nodeStore, m.cellStore, fmt.Sprint(m.config.Shard.Index))
+	return m.topoRegistrar.Start()
+}
+
+// startExporter starts exporting KPIs if any export destination is configured
+func (m *Manager) startExporter() error {
+	var writers []export.Writer
+	if m.config.ExportCSVPath != "" {
+		csvWriter, err := export.NewCSVFileWriter(m.config.ExportCSVPath)
+		if err != nil {
+			return err
+		}
+		writers = append(writers, csvWriter)
+	}
+	if m.config.ExportInfluxURL != "" {
+		writers = append(writers, export.NewInfluxWriter(m.config.ExportInfluxURL))
+	}
+	if len(writers) == 0 || m.config.ExportInterval <= 0 {
+		return nil
+	}
+	m.exporter = export.NewExporter(m.cellStore, m.ueStore, m.metricsStore, m.config.ExportInterval, writers...)
+	m.exporter.Start()
+	return nil
+}
+
+func (m *Manager) stopControllers() {
+	if m.qosController != nil {
+		m.qosController.Stop()
+	}
+	if m.energyController != nil {
+		m.energyController.Stop()
+	}
+	if m.healthController != nil {
+		m.healthController.Stop()
+	}
+	if m.cellStateController != nil {
+		m.cellStateController.Stop()
+	}
+	if m.rrcController != nil {
+		m.rrcController.Stop()
+	}
+	if m.uavController != nil {
+		m.uavController.Stop()
+	}
+	if m.core != nil {
+		m.core.Stop()
+	}
+	if m.voice != nil {
+		m.voice.Stop()
+	}
+	if m.iot != nil {
+		m.iot.Stop()
+	}
+	if m.measurementController != nil {
+		m.measurementController.Stop()
+	}
+	if m.geofenceController != nil {
+		m.geofenceController.Stop()
+	}
+	if m.faultInjector != nil {
+		m.faultInjector.Stop()
+	}
+	if m.topoRegistrar != nil {
+		m.topoRegistrar.Stop()
+	}
+	if m.exporter != nil {
+		m.exporter.Stop()
+	}
+}
+
+// StartAgent starts the E2 agent of the specified node
+func (m *Manager) StartAgent(enbID types.EnbID) error {
+	if m.agents == nil {
+		return errors.New(errors.Unavailable, "E2 agents are not running")
+	}
+	return m.agents.StartAgent(enbID)
+}
+
+// StopAgent stops the E2 agent of the specified node
+func (m *Manager) StopAgent(enbID types.EnbID) error {
+	if m.agents == nil {
+		return errors.New(errors.Unavailable, "E2 agents are not running")
+	}
+	return m.agents.StopAgent(enbID)
+}
+
+// AuditSubscriptions audits the subscriptions of the E2 agents created until the audit started
+func (m *Manager) AuditSubscriptions(known map[types.EnbID][]subscriptions.ID, clean bool, started time.Time) (map[types.EnbID]subscriptions.Audit, error) {
+	if m.agents == nil {
+		return nil, errors.New(errors.Unavailable, "E2 agents are not running")
+	}
+	return m.agents.AuditSubscriptions(known, clean, started)
+}
+
+// CellStore returns the cell store of the current model
+func (m *Manager) CellStore() cells.Store {
+	return m.cellStore
+}
+
+// UEStore returns the UE store of the current model
+func (m *Manager) UEStore() ues.Store {
+	return m.ueStore
+}
+
+// HandoverEngine returns the handover engine of the current simulation run; nil if the simulation has not been started
+// since it was reset
+func (m *Manager) HandoverEngine() *mobility.HandoverEngine {
+	return m.handover
+}
+
+// Stats returns a snapshot of the current statistics of the simulation
+func (m *Manager) Stats(ctx context.Context) (*stats.Snapshot, error) {
+	sources := stats.Sources{
+		NodeStore:   m.nodeStore,
+		CellStore:   m.cellStore,
+		UEStore:     m.ueStore,
+		MetricStore: m.metricsStore,
+		Events:      watcher.Stats(),
+		Start:       m.start,
+	}
+	if m.agents != nil {
+		agentStats, err := m.agents.Stats()
+		if err != nil {
+			return nil, err
+		}
+		sources.Agents = agentStats
+	}
+	if m.measurementController != nil {
+		sources.Mobility = m.measurementController.TickStats()
+	}
+	return stats.Collect(ctx, sources, time.Now())
+}
+
+// WaitConnected waits until all E2 nodes are connected to their E2T controller, failing with Unavailable as soon as any
+// node failed to connect and with Timeout once the context is done
+func (m *Manager) WaitConnected(ctx context.Context) error {
+	ticker := time.NewTicker(connectionPollInterval)
+	defer ticker.Stop()
+	for {
+		nodeList, err := m.nodeStore.List(ctx)
+		if err != nil {
+			return err
+		}
+		pending := 0
+		for _, node := range nodeList {
+			if node.Status == e2agent.StatusFailed {
+				return errors.New(errors.Unavailable, "E2 node %d failed to connect", node.EnbID)
+			}
+			if !node.IsConnected() {
+				pending++
+			}
+		}
+		if pending == 0 {
+			return nil
+		}
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return errors.New(errors.Timeout, "%d of %d E2 nodes are not connected", pending, len(nodeList))
+		}
+	}
+}
+
+// ResetCounters resets the named counters, or all counters if none is named, of the specified entity or of all
+// entities if the entity ID is zero
+func (m *Manager) ResetCounters(ctx context.Context, entityID uint64, names []string) error {
+	entityIDs := []uint64{entityID}
+	if entityID == 0 {
+		var err error
+		if entityIDs, err = m.metricsStore.ListEntities(ctx); err != nil {
+			return err
+		}
+	}
+	for _, id := range entityIDs {
+		if err := m.metricsStore.Reset(ctx, id, names...); err != nil {
+			return err
+		}
+	}
+	log.Infof("Reset the counters of %d entities", len(entityIDs))
+	return nil
+}
+
 func (m *Manager) stopE2Agents() {
-	_ = m.agents.Stop()
+	if m.agents != nil {
+		_ = m.agents.Stop()
+	}
 }
 
 func (m *Manager) stopNorthboundServer() {
-	m.server.Stop()
+	if m.server != nil {
+		m.server.Stop()
+	}
 }
 
 // PauseAndClear pauses simulation and clears the model
@@ -209,8 +891,20 @@ func (m *Manager) LoadModel(ctx context.Context, data []byte) error {
 	if err := model.LoadConfigFromBytes(m.model, data); err != nil {
 		return err
 	}
-	m.initModelStores()
-	return nil
+	if m.config.Store.Enabled() && m.ueStore != nil {
+		// Replace the UE population of the previous model in the distributed store
+		m.ueStore.SetUECount(ctx, 0)
+	}
+	if err := m.partitionModel(); err != nil {
+		return err
+	}
+	if err := m.initModelStores(); err != nil {
+		return err
+	}
+
+	// Restart the controllers against the new registries
+	m.stopControllers()
+	return m.startControllers()
 }
 
 // LoadMetrics loads new metrics into the simulator
@@ -231,6 +925,8 @@ func (m *Manager) Resume(ctx context.Context) {
 		log.Info("Restarting NBI...")
 		m.stopNorthboundServer()
 		_ = m.startNorthboundServer()
+		m.stopO1Server()
+		m.startO1Server()
 	}()
 	_ = m.startE2Agents()
 }
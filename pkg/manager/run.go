@@ -0,0 +1,153 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package manager
+
+import (
+	"context"
+	"time"
+
+	"github.com/onosproject/ran-simulator/pkg/admin"
+	"github.com/onosproject/ran-simulator/pkg/journal"
+)
+
+// RunState returns the state of the simulation run
+func (m *Manager) RunState() admin.RunState {
+	m.runMu.Lock()
+	defer m.runMu.Unlock()
+	return admin.RunState{Running: m.running, Iteration: m.iteration, Since: m.runSince}
+}
+
+// StartRun starts the E2 agents and the controllers of the simulation, unless running
+func (m *Manager) StartRun(ctx context.Context) error {
+	m.runMu.Lock()
+	defer m.runMu.Unlock()
+	return m.startRun()
+}
+
+// StopRun stops the E2 agents and the controllers of the simulation, unless stopped; the agents release their
+// subscriptions and close their E2 connections, the only way E2AP v1.01 lets the RIC learn about their deletion
+func (m *Manager) StopRun(ctx context.Context) error {
+	m.runMu.Lock()
+	defer m.runMu.Unlock()
+	m.stopRun()
+	return nil
+}
+
+// ResetRun stops the simulation, returns all stores to the initial state of the loaded model, i.e. recreates the
+// nodes, cells and UEs, zeroes the metrics and drops the routes, subscriptions and injected anomalies, and starts the
+// simulation again if requested. The entities of the previous stores are deleted first, notifying their watchers, and
+// the servers bound to the stores are restarted against the new ones right away, whether the simulation is started
+// or not.
+func (m *Manager) ResetRun(ctx context.Context, start bool) error {
+	m.runMu.Lock()
+	defer m.runMu.Unlock()
+	log.Infof("Resetting simulation run %d", m.iteration)
+	m.stopRun()
+	m.clearStores(ctx)
+	// The handover engine is bound to the previous stores until the simulation is started again
+	m.handover = nil
+	if err := m.initModelStores(); err != nil {
+		return err
+	}
+	m.initMetricStore()
+	for _, anomaly := range m.anomalies.List() {
+		_ = m.anomalies.Cancel(anomaly.ID)
+	}
+	m.iteration++
+	m.stopNorthboundServer()
+	if err := m.startNorthboundServer(); err != nil {
+		return err
+	}
+	m.stopO1Server()
+	m.startO1Server()
+	journal.Record(journal.SimulationReset, 0, map[string]interface{}{"iteration": m.iteration})
+	if !start {
+		return nil
+	}
+	return m.startRun()
+}
+
+// clearStores deletes the UEs, routes, cells and nodes of the stores one by one, so that the watchers of the stores,
+// e.g. the clients of the northbound API, learn about the deletions, and the entries of this instance are removed
+// from the distributed store rather than restored by the stores replacing these; the cells of other instances are
+// left to them
+func (m *Manager) clearStores(ctx context.Context) {
+	if m.ueStore != nil {
+		m.ueStore.SetUECount(ctx, 0)
+	}
+	if m.routeStore != nil {
+		for _, route := range m.routeStore.List(ctx) {
+			_, _ = m.routeStore.Delete(ctx, route.IMSI)
+		}
+	}
+	if m.cellStore != nil {
+		cellList, _ := m.cellStore.List(ctx)
+		for _, cell := range cellList {
+			if m.partition != nil && m.partition.IsRemote(cell.ECGI) {
+				continue
+			}
+			if _, err := m.cellStore.Delete(ctx, cell.ECGI); err != nil {
+				log.Warnf("Unable to delete cell %d: %v", cell.ECGI, err)
+			}
+		}
+	}
+	if m.nodeStore != nil {
+		nodeList, _ := m.nodeStore.List(ctx)
+		for _, node := range nodeList {
+			if _, err := m.nodeStore.Delete(ctx, node.EnbID); err != nil {
+				log.Warnf("Unable to delete node %d: %v", node.EnbID, err)
+			}
+		}
+	}
+}
+
+// PauseControllers stops the controllers of a running simulation until the returned function is called, which
+// restarts them, so that the controllers do not act on the stores while these are changed wholesale; the E2 agents
+// keep running. Starting, stopping and resetting the simulation run waits until the controllers are resumed.
+func (m *Manager) PauseControllers() func() error {
+	m.runMu.Lock()
+	if !m.running {
+		return func() error {
+			m.runMu.Unlock()
+			return nil
+		}
+	}
+	log.Info("Pausing the controllers of the simulation run")
+	m.stopControllers()
+	return func() error {
+		defer m.runMu.Unlock()
+		return m.startControllers()
+	}
+}
+
+func (m *Manager) startRun() error {
+	if m.running {
+		return nil
+	}
+	log.Info("Starting simulation run")
+	if err := m.startControllers(); err != nil {
+		return err
+	}
+	if err := m.startE2Agents(); err != nil {
+		return err
+	}
+	m.running = true
+	m.runSince = time.Now()
+	return nil
+}
+
+func (m *Manager) stopRun() {
+	if !m.running {
+		return
+	}
+	log.Info("Stopping simulation run")
+	if m.agents != nil {
+		m.stopE2Agents()
+		m.agents = nil
+	}
+	m.stopControllers()
+	m.running = false
+	m.runSince = time.Now()
+}
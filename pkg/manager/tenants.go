@@ -0,0 +1,114 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package manager
+
+import (
+	"context"
+	"path/filepath"
+
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/onosproject/ran-simulator/pkg/auth"
+	"github.com/onosproject/ran-simulator/pkg/northbound"
+	"github.com/onosproject/ran-simulator/pkg/shard"
+	"github.com/onosproject/ran-simulator/pkg/store/distributed"
+	"github.com/onosproject/ran-simulator/pkg/tenant"
+	"google.golang.org/grpc"
+)
+
+// Tenant is the configuration of a tenant simulating its own model next to the model of the manager
+type Tenant struct {
+	// Name is the name of the tenant given by the metadata of its northbound requests
+	Name string
+	// ModelName is the name of the model of the tenant
+	ModelName string
+}
+
+// startTenants starts simulating the tenants, each by a manager of its own, and serves their northbound services
+// along with the services of the manager, simulating the default tenant, by routing the requests by their metadata
+func (m *Manager) startTenants() error {
+	m.router = tenant.NewRouter()
+	conn, err := m.backend.Dial(context.Background())
+	if err != nil {
+		return err
+	}
+	m.tenantConns = append(m.tenantConns, conn)
+	m.router.Add(tenant.Default, conn)
+
+	for _, t := range m.config.Tenants {
+		if t.Name == "" || t.Name == tenant.Default {
+			return errors.New(errors.Invalid, "invalid tenant name '%s'", t.Name)
+		}
+		if _, ok := m.tenants[t.Name]; ok {
+			return errors.New(errors.Invalid, "duplicate tenant %s", t.Name)
+		}
+		log.Infof("Starting tenant %s simulating model %s", t.Name, t.ModelName)
+		config := m.tenantConfig(t)
+		mgr, err := NewManager(&config)
+		if err != nil {
+			return err
+		}
+		// The models are loaded one after the other, the model loader not being reentrant
+		if err := mgr.Start(); err != nil {
+			mgr.Close()
+			return err
+		}
+		m.tenants[t.Name] = mgr
+		conn, err := mgr.backend.Dial(context.Background())
+		if err != nil {
+			return err
+		}
+		m.tenantConns = append(m.tenantConns, conn)
+		m.router.Add(t.Name, conn)
+	}
+
+	// The requests are authorized before being routed to the tenants
+	opts := append(m.router.ServerOptions(), grpc.ChainStreamInterceptor(m.authorizer.StreamServerInterceptor()))
+	m.routerServer = northbound.NewServer(m.northboundConfig(), opts...)
+	return serveNorthbound("tenant router", m.routerServer.Serve)
+}
+
+// tenantConfig returns the configuration of the manager of the tenant; the tenant is only reachable through the
+// router, and shares neither the servers, the journal file, the exports nor the topology registration of the
+// manager. The E2AP traffic of its nodes is captured to a subdirectory named after the tenant, as the nodes of
+// different tenants may have the same IDs; the KPI anomalies and the E2 agent options are kept per manager, whereas
+// the events of all tenants are recorded in the in-memory journal of the process.
+func (m *Manager) tenantConfig(t Tenant) Config {
+	config := m.config
+	config.Tenant = t.Name
+	config.Tenants = nil
+	config.ModelName = t.ModelName
+	config.GRPCPort = 0
+	config.O1Port = 0
+	config.A1Port = 0
+	config.JournalPort = 0
+	config.ScenarioPort = 0
+	config.AdminPort = 0
+	config.JournalPath = ""
+	config.ExportCSVPath = ""
+	config.ExportInfluxURL = ""
+	if config.E2CaptureDir != "" {
+		config.E2CaptureDir = filepath.Join(config.E2CaptureDir, t.Name)
+	}
+	config.TopoAddress = ""
+	config.Shard = shard.Config{}
+	config.Store = distributed.Config{}
+	config.Auth = auth.Config{}
+	return config
+}
+
+func (m *Manager) stopTenants() {
+	if m.routerServer != nil {
+		m.routerServer.Stop()
+	}
+	for _, conn := range m.tenantConns {
+		_ = conn.Close()
+	}
+	m.tenantConns = nil
+	for name, mgr := range m.tenants {
+		log.Infof("Stopping tenant %s", name)
+		mgr.Close()
+		delete(m.tenants, name)
+	}
+}
@@ -0,0 +1,125 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package mobility
+
+import (
+	"context"
+	"strings"
+
+	"github.com/onosproject/ran-simulator/pkg/journal"
+	"github.com/onosproject/ran-simulator/pkg/model"
+)
+
+// RRC establishment causes of the connections established by UEs
+const (
+	// CauseEmergency connection established for an emergency call
+	CauseEmergency = "emergency"
+	// CauseHighPriorityAccess connection established by a high priority access class UE
+	CauseHighPriorityAccess = "highPriorityAccess"
+	// CauseMtAccess connection established in response to paging
+	CauseMtAccess = "mt-Access"
+	// CauseMoSignalling connection established for mobile originated signalling
+	CauseMoSignalling = "mo-Signalling"
+	// CauseMoData connection established for mobile originated data
+	CauseMoData = "mo-Data"
+	// CauseMoVoiceCall connection established for a mobile originated voice call
+	CauseMoVoiceCall = "mo-VoiceCall"
+)
+
+// RrcConnEstabFail number of RRC connection establishments rejected by the cell
+const RrcConnEstabFail = "RRC.ConnEstabFail.Tot"
+
+const defaultEstablishmentCause = CauseMoData
+
+var establishmentCauses = map[string]bool{
+	CauseEmergency:          true,
+	CauseHighPriorityAccess: true,
+	CauseMtAccess:           true,
+	CauseMoSignalling:       true,
+	CauseMoData:             true,
+	CauseMoVoiceCall:        true,
+}
+
+// isPriority returns true if connections established with the given cause are admitted regardless of the cell capacity
+func isPriority(cause string) bool {
+	return cause == CauseEmergency || cause == CauseHighPriorityAccess
+}
+
+// perCause returns the name of the per-cause subcounter of the specified total counter, e.g. RRC.ConnEstabAtt.mo-Data
+func perCause(name string, cause string) string {
+	return strings.TrimSuffix(name, "Tot") + cause
+}
+
+// admit runs the admission of the idle UE establishing an RRC connection via its serving cell with the given cause and
+// returns true if the UE was admitted. The cell rejects the UE if it is out of service, if it is barred or the UE is not
+// a member of its closed subscriber group, unless calling for an emergency, or if it has no capacity left for connected
+// UEs, unless calling for an emergency or with high priority access. Admitted UEs are allocated a C-RNTI by the cell.
+func (c *RrcController) admit(ctx context.Context, ue *model.UE, cause string) bool {
+	ecgi := uint64(ue.Cell.ECGI)
+	c.increment(ctx, ecgi, RrcConnEstabAtt)
+	c.increment(ctx, ecgi, perCause(RrcConnEstabAtt, cause))
+	if reason := c.rejection(ctx, ue, cause); reason != "" {
+		log.Debugf("Cell %d rejected UE %d: %s", ue.Cell.ECGI, ue.IMSI, reason)
+		c.increment(ctx, ecgi, RrcConnEstabFail)
+		journal.Record(journal.AdmissionRejected, uint64(ue.IMSI), map[string]interface{}{
+			"ecgi": ue.Cell.ECGI, "cause": reason, "establishmentCause": cause})
+		return false
+	}
+	if err := c.ueStore.AdmitUE(ctx, ue.IMSI); err != nil {
+		log.Warn(err)
+		return false
+	}
+	c.increment(ctx, ecgi, RrcConnEstabSucc)
+	c.increment(ctx, ecgi, perCause(RrcConnEstabSucc, cause))
+	// The C-RNTI is taken from the store, which may have returned a snapshot of the UE
+	crnti := ue.CRNTI
+	if admitted, err := c.ueStore.Get(ctx, ue.IMSI); err == nil {
+		crnti = admitted.CRNTI
+	}
+	journal.Record(journal.UEAdmitted, uint64(ue.IMSI), map[string]interface{}{
+		"ecgi": ue.Cell.ECGI, "crnti": crnti, "establishmentCause": cause})
+	return true
+}
+
+// rejection returns the reason for the serving cell to reject the UE, or an empty string if the UE is admitted
+func (c *RrcController) rejection(ctx context.Context, ue *model.UE, cause string) string {
+	cell, err := c.cellStore.Get(ctx, ue.Cell.ECGI)
+	if err != nil || !cell.InService() {
+		return "unavailable"
+	}
+	if cause != CauseEmergency {
+		if cell.Barred {
+			return "barred"
+		}
+		if !cell.Admits(ue.IMSI) {
+			return "CSG"
+		}
+	}
+	if cell.MaxUEs > 0 && !isPriority(cause) {
+		admitted := 0
+		for _, served := range c.ueStore.ListUEs(ctx, cell.ECGI) {
+			if served.IsAdmitted {
+				admitted++
+			}
+		}
+		if admitted >= int(cell.MaxUEs) {
+			return "capacity"
+		}
+	}
+	return ""
+}
+
+// release releases the admission of the UE entering the idle state
+func (c *RrcController) release(ctx context.Context, ue *model.UE) {
+	if !ue.IsAdmitted {
+		return
+	}
+	ecgi := ue.Cell.ECGI
+	if err := c.ueStore.ReleaseUE(ctx, ue.IMSI); err != nil {
+		log.Warn(err)
+		return
+	}
+	journal.Record(journal.UEReleased, uint64(ue.IMSI), map[string]interface{}{"ecgi": ecgi})
+}
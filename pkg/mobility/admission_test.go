@@ -0,0 +1,59 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package mobility
+
+import (
+	"context"
+	"testing"
+
+	"github.com/onosproject/onos-api/go/onos/ransim/types"
+	"github.com/onosproject/ran-simulator/pkg/model"
+	"github.com/onosproject/ran-simulator/pkg/store/metrics"
+	"github.com/onosproject/ran-simulator/pkg/store/ues"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAdmission(t *testing.T) {
+	ctx := context.Background()
+	cells := cellStore(t)
+	ueStore := ues.NewUERegistry(2, cells)
+	metricStore := metrics.NewMetricsStore()
+	controller := NewRrcController(cells, ueStore, metricStore, model.RrcConfig{})
+
+	ecgi := types.ECGI(84325717505)
+	for _, ue := range ueStore.ListAllUEs(ctx) {
+		assert.NoError(t, ueStore.MoveToCell(ctx, ue.IMSI, ecgi, 10))
+	}
+	cell, err := cells.Get(ctx, ecgi)
+	assert.NoError(t, err)
+	limited := *cell
+	limited.MaxUEs = 1
+	assert.NoError(t, cells.Update(ctx, &limited))
+
+	// The first UE is admitted and allocated a C-RNTI
+	list := ueStore.ListAllUEs(ctx)
+	ue1, ue2 := list[0], list[1]
+	assert.True(t, controller.admit(ctx, ue1, CauseMoData))
+	assert.True(t, ue1.IsAdmitted)
+	assert.NotZero(t, ue1.CRNTI)
+
+	// The cell has no capacity left for the second UE, unless it calls for an emergency
+	assert.False(t, controller.admit(ctx, ue2, CauseMoData))
+	assert.False(t, ue2.IsAdmitted)
+	assert.True(t, controller.admit(ctx, ue2, CauseEmergency))
+	assert.NotEqual(t, ue1.CRNTI, ue2.CRNTI)
+
+	count, _ := metricStore.Get(ctx, uint64(ecgi), RrcConnEstabAtt)
+	assert.Equal(t, uint64(3), count)
+	count, _ = metricStore.Get(ctx, uint64(ecgi), RrcConnEstabFail)
+	assert.Equal(t, uint64(1), count)
+	count, _ = metricStore.Get(ctx, uint64(ecgi), "RRC.ConnEstabSucc.emergency")
+	assert.Equal(t, uint64(1), count)
+
+	// Released UEs give up their C-RNTI
+	controller.release(ctx, ue1)
+	assert.False(t, ue1.IsAdmitted)
+	assert.Zero(t, ue1.CRNTI)
+}
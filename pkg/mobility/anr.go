@@ -0,0 +1,92 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package mobility
+
+import (
+	"context"
+	"time"
+
+	"github.com/onosproject/onos-api/go/onos/ransim/types"
+	"github.com/onosproject/ran-simulator/pkg/model"
+)
+
+const (
+	// NeighborRelationsAdded per-cell counter of the neighbor relations added automatically
+	NeighborRelationsAdded = "ANR.Add.Tot"
+	// NeighborRelationsRemoved per-cell counter of the neighbor relations added automatically which were removed
+	// again as no UE detected the neighbor for the neighbor relation timeout
+	NeighborRelationsRemoved = "ANR.Rem.Tot"
+
+	defaultNeighborRelationTimeout = 5 * time.Minute
+)
+
+// relation is a neighbor relation from a cell to one of its neighbors
+type relation struct {
+	ecgi     types.ECGI
+	neighbor types.ECGI
+}
+
+// maintainNeighbors adds the strongest cells in service at the location of the UE which are not yet neighbors of its
+// serving cell to the neighbors of the serving cell, if automatic neighbor relations are enabled, and returns the
+// serving cell as updated. Only the neighbors of the cell are written, so that concurrent changes of its other
+// fields are preserved. The relations added automatically are refreshed whenever a UE detects the neighbor again.
+func (c *MeasurementController) maintainNeighbors(ctx context.Context, ue *model.UE, serving *model.Cell, now time.Time) (*model.Cell, error) {
+	if c.mobility.NeighborRelations <= 0 {
+		return serving, nil
+	}
+	strongest, err := c.cellStore.GetStrongestCells(ctx, ue.Location, c.mobility.NeighborRelations, func(cell *model.Cell) bool {
+		return cell.ECGI != serving.ECGI && cell.InService()
+	})
+	if err != nil {
+		return nil, err
+	}
+	neighbors := make(map[types.ECGI]bool, len(serving.Neighbors))
+	for _, ecgi := range serving.Neighbors {
+		neighbors[ecgi] = true
+	}
+	var missing []types.ECGI
+	for _, cell := range strongest {
+		r := relation{ecgi: serving.ECGI, neighbor: cell.ECGI}
+		if _, ok := c.relations[r]; ok {
+			c.relations[r] = now
+		}
+		if !neighbors[cell.ECGI] {
+			missing = append(missing, cell.ECGI)
+		}
+	}
+	if len(missing) == 0 {
+		return serving, nil
+	}
+
+	added, err := c.cellStore.UpdateNeighbors(ctx, serving.ECGI, missing, nil)
+	if err != nil {
+		return nil, err
+	}
+	for _, ecgi := range added {
+		log.Infof("Added cell %d to the neighbors of cell %d detected by UE %d", ecgi, serving.ECGI, ue.IMSI)
+		c.relations[relation{ecgi: serving.ECGI, neighbor: ecgi}] = now
+		initNeighborAttributes(ctx, c.metricStore, serving.ECGI, ecgi)
+		c.count(ctx, serving.ECGI, NeighborRelationsAdded)
+	}
+	return c.cellStore.Get(ctx, serving.ECGI)
+}
+
+// ageNeighbors removes the neighbor relations added automatically which no UE detected for the neighbor relation
+// timeout; the relations configured by the model or the northbound API are kept
+func (c *MeasurementController) ageNeighbors(ctx context.Context, now time.Time) {
+	for r, detected := range c.relations {
+		if now.Sub(detected) < c.mobility.NeighborRelationTimeout {
+			continue
+		}
+		delete(c.relations, r)
+		if _, err := c.cellStore.UpdateNeighbors(ctx, r.ecgi, nil, []types.ECGI{r.neighbor}); err != nil {
+			log.Warn(err)
+			continue
+		}
+		log.Infof("Removed cell %d from the neighbors of cell %d as no UE detected it for %v", r.neighbor, r.ecgi,
+			c.mobility.NeighborRelationTimeout)
+		c.count(ctx, r.ecgi, NeighborRelationsRemoved)
+	}
+}
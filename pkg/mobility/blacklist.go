@@ -0,0 +1,39 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package mobility
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/onosproject/onos-api/go/onos/ransim/types"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+)
+
+// HandoverBlacklistAttribute returns the name of the cell attribute blacklisting the given neighbor as handover target
+// for UEs served by the cell, e.g. ho.blacklist.84325717506; any non-zero value blacklists the neighbor, zero
+// whitelists it again
+func HandoverBlacklistAttribute(neighbor types.ECGI) string {
+	return fmt.Sprintf("ho.blacklist.%d", neighbor)
+}
+
+// isBlacklisted returns true if the serving cell blacklists the candidate cell as handover target
+func (h *HandoverEngine) isBlacklisted(ctx context.Context, serving types.ECGI, candidate types.ECGI) bool {
+	value, ok := h.metricStore.Get(ctx, uint64(serving), HandoverBlacklistAttribute(candidate))
+	if !ok {
+		return false
+	}
+	blacklisted, _ := toFloat(value)
+	return blacklisted != 0
+}
+
+// checkBlacklist returns Forbidden if the cell serving the UE blacklists the target cell
+func (h *HandoverEngine) checkBlacklist(ctx context.Context, imsi types.IMSI, target types.ECGI) error {
+	ue, err := h.ueStore.Get(ctx, imsi)
+	if err != nil || ue.Cell == nil || !h.isBlacklisted(ctx, ue.Cell.ECGI, target) {
+		return nil
+	}
+	return errors.New(errors.Forbidden, "cell %d is blacklisted by cell %d serving UE %d", target, ue.Cell.ECGI, imsi)
+}
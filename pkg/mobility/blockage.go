@@ -0,0 +1,77 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package mobility
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"time"
+
+	"github.com/onosproject/onos-api/go/onos/ransim/types"
+	"github.com/onosproject/ran-simulator/pkg/model"
+)
+
+// Blockages per-cell counter of the blockages of the links between the cell and the UEs measuring it
+const Blockages = "MMW.Blockage.Tot"
+
+const (
+	defaultBlockageDuration = 2 * time.Second
+	defaultBlockageLoss     = 30.0
+)
+
+// link is the radio link between a UE and a cell
+type link struct {
+	imsi types.IMSI
+	ecgi types.ECGI
+}
+
+// SetBlockage enables the blockage of the links between UEs and mmWave cells; unset values are replaced by defaults
+func (c *MeasurementController) SetBlockage(config model.BlockageConfig) {
+	if config.Duration == 0 {
+		config.Duration = defaultBlockageDuration
+	}
+	if config.Loss == 0 {
+		config.Loss = defaultBlockageLoss
+	}
+	c.blockage = config
+}
+
+// blockageLoss returns the attenuation of the link between the UE and the cell at the given time. Each link of a
+// mmWave cell is blocked at random at the configured rate for an exponentially distributed duration.
+func (c *MeasurementController) blockageLoss(ctx context.Context, imsi types.IMSI, cell *model.Cell, now time.Time) float64 {
+	if c.blockage.Rate <= 0 || cell.Environment != model.MmWave {
+		return 0
+	}
+	l := link{imsi: imsi, ecgi: cell.ECGI}
+	if until, ok := c.blocked[l]; ok {
+		if now.Before(until) {
+			return c.blockage.Loss
+		}
+		delete(c.blocked, l)
+	}
+	if rand.Float64() >= 1-math.Exp(-c.blockage.Rate*c.interval.Seconds()) {
+		return 0
+	}
+	duration := time.Duration(rand.ExpFloat64() * float64(c.blockage.Duration))
+	log.Debugf("Link of UE %d to cell %d blocked for %s", imsi, cell.ECGI, duration)
+	c.blocked[l] = now.Add(duration)
+	c.count(ctx, cell.ECGI, Blockages)
+	return c.blockage.Loss
+}
+
+// purgeBlockages drops the blockages that ended before the given time
+func (c *MeasurementController) purgeBlockages(now time.Time) {
+	for l, until := range c.blocked {
+		if !now.Before(until) {
+			delete(c.blocked, l)
+		}
+	}
+}
+
+// count increments the specified per-cell counter
+func (c *MeasurementController) count(ctx context.Context, ecgi types.ECGI, name string) {
+	_, _ = c.metricStore.Add(ctx, uint64(ecgi), name, 1)
+}
@@ -0,0 +1,44 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package mobility
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/onosproject/onos-api/go/onos/ransim/types"
+	"github.com/onosproject/ran-simulator/pkg/model"
+	"github.com/onosproject/ran-simulator/pkg/store/metrics"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBlockage(t *testing.T) {
+	ctx := context.Background()
+	metricStore := metrics.NewMetricsStore()
+	controller := NewMeasurementController(nil, nil, metricStore)
+	imsi := types.IMSI(1234)
+	macro := &model.Cell{ECGI: 1, Environment: model.Urban}
+	mmWave := &model.Cell{ECGI: 2, Environment: model.MmWave}
+	now := time.Now()
+
+	// Links are not blocked unless a blockage rate is configured
+	controller.SetBlockage(model.BlockageConfig{})
+	assert.Equal(t, 0.0, controller.blockageLoss(ctx, imsi, mmWave, now))
+
+	// Only the links to mmWave cells are blocked
+	controller.SetBlockage(model.BlockageConfig{Rate: 1000, Duration: 24 * time.Hour, Loss: 40})
+	assert.Equal(t, 0.0, controller.blockageLoss(ctx, imsi, macro, now))
+	assert.Equal(t, 40.0, controller.blockageLoss(ctx, imsi, mmWave, now))
+	value, _ := metricStore.Get(ctx, 2, Blockages)
+	assert.Equal(t, uint64(1), value)
+
+	// The link stays blocked until the blockage ends
+	assert.Equal(t, 40.0, controller.blockageLoss(ctx, imsi, mmWave, now.Add(time.Second)))
+	value, _ = metricStore.Get(ctx, 2, Blockages)
+	assert.Equal(t, uint64(1), value)
+	controller.purgeBlockages(now.Add(10000 * time.Hour))
+	assert.Empty(t, controller.blocked)
+}
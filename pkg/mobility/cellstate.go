@@ -0,0 +1,132 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package mobility
+
+import (
+	"context"
+
+	"github.com/onosproject/onos-api/go/onos/ransim/types"
+	"github.com/onosproject/ran-simulator/pkg/energy"
+	"github.com/onosproject/ran-simulator/pkg/store/cells"
+	"github.com/onosproject/ran-simulator/pkg/store/event"
+	"github.com/onosproject/ran-simulator/pkg/store/metrics"
+)
+
+const (
+	// LockedAttribute is the cell attribute controlling the administrative state of the cell;
+	// any non-zero value locks the cell. It can be set via the metrics API or RC control.
+	LockedAttribute = "admin.locked"
+	// BarredAttribute is the cell attribute controlling whether the cell is barred
+	BarredAttribute = "admin.barred"
+)
+
+// CellStateController applies changes of the cell administrative, barred and energy-saving state
+// requested via the cell attributes and moves UEs away from locked and sleeping cells
+type CellStateController struct {
+	cellStore   cells.Store
+	metricStore metrics.Store
+	handover    *HandoverEngine
+	cancel      context.CancelFunc
+}
+
+// NewCellStateController creates a new cell state controller
+func NewCellStateController(cellStore cells.Store, metricStore metrics.Store, handover *HandoverEngine) *CellStateController {
+	return &CellStateController{
+		cellStore:   cellStore,
+		metricStore: metricStore,
+		handover:    handover,
+	}
+}
+
+// Start publishes the initial cell state and the handover offsets and blacklisting of the neighbors as cell
+// attributes and starts watching for changes of the state
+func (c *CellStateController) Start() error {
+	ctx, cancel := context.WithCancel(context.Background())
+	cellList, err := c.cellStore.List(ctx)
+	if err != nil {
+		cancel()
+		return err
+	}
+	for _, cell := range cellList {
+		// RC control only updates existing attributes; make sure the state attributes exist
+		_ = c.metricStore.Set(ctx, uint64(cell.ECGI), LockedAttribute, toInt32(cell.Locked))
+		_ = c.metricStore.Set(ctx, uint64(cell.ECGI), BarredAttribute, toInt32(cell.Barred))
+		for _, neighbor := range cell.Neighbors {
+			initNeighborAttributes(ctx, c.metricStore, cell.ECGI, neighbor)
+		}
+	}
+
+	ch := make(chan event.Event)
+	options := metrics.WatchOptions{Names: []string{LockedAttribute, BarredAttribute, energy.SleepAttribute}, Types: []metrics.MetricEvent{metrics.Updated}}
+	if err := c.metricStore.Watch(ctx, ch, options); err != nil {
+		cancel()
+		return err
+	}
+	c.cancel = cancel
+	go c.processMetricEvents(ch)
+	return nil
+}
+
+// Stop stops watching for cell state changes
+func (c *CellStateController) Stop() {
+	if c.cancel != nil {
+		c.cancel()
+	}
+}
+
+func (c *CellStateController) processMetricEvents(ch <-chan event.Event) {
+	ctx := context.Background()
+	for metricEvent := range ch {
+		key := metricEvent.Key.(metrics.Key)
+		if err := c.setCellState(ctx, types.ECGI(key.EntityID), key.Name, metrics.IsSet(metricEvent.Value)); err != nil {
+			log.Warnf("Unable to update state of cell %d: %v", key.EntityID, err)
+		}
+	}
+}
+
+func (c *CellStateController) setCellState(ctx context.Context, ecgi types.ECGI, name string, value bool) error {
+	cell, err := c.cellStore.Get(ctx, ecgi)
+	if err != nil {
+		return err
+	}
+	updated := *cell
+	switch name {
+	case LockedAttribute:
+		updated.Locked = value
+	case energy.SleepAttribute:
+		updated.Asleep = value
+	default:
+		updated.Barred = value
+	}
+	if updated.Locked == cell.Locked && updated.Barred == cell.Barred && updated.Asleep == cell.Asleep {
+		return nil
+	}
+
+	log.Infof("Cell %d is now locked=%t barred=%t asleep=%t", ecgi, updated.Locked, updated.Barred, updated.Asleep)
+	if err := c.cellStore.Update(ctx, &updated); err != nil {
+		return err
+	}
+	if updated.Locked || updated.Asleep {
+		return c.handover.EvacuateCell(ctx, ecgi)
+	}
+	return nil
+}
+
+// initNeighborAttributes makes sure the handover offset and blacklisting attributes of the cell for the neighbor
+// exist, so that RC control can update them
+func initNeighborAttributes(ctx context.Context, metricStore metrics.Store, ecgi types.ECGI, neighbor types.ECGI) {
+	for _, name := range []string{HandoverOffsetAttribute(neighbor), HandoverBlacklistAttribute(neighbor)} {
+		if _, ok := metricStore.Get(ctx, uint64(ecgi), name); !ok {
+			_ = metricStore.Set(ctx, uint64(ecgi), name, int32(0))
+		}
+	}
+}
+
+func toInt32(b bool) int32 {
+	if b {
+		return 1
+	}
+	return 0
+}
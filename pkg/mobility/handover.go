@@ -0,0 +1,436 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package mobility
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/onosproject/onos-api/go/onos/ransim/types"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/onosproject/onos-lib-go/pkg/logging"
+	"github.com/onosproject/ran-simulator/pkg/journal"
+	"github.com/onosproject/ran-simulator/pkg/model"
+	"github.com/onosproject/ran-simulator/pkg/qos"
+	"github.com/onosproject/ran-simulator/pkg/store/cells"
+	"github.com/onosproject/ran-simulator/pkg/store/metrics"
+	"github.com/onosproject/ran-simulator/pkg/store/txn"
+	"github.com/onosproject/ran-simulator/pkg/store/ues"
+)
+
+var log = logging.GetLogger("mobility")
+
+// CSGRejections per-cell counter of UEs rejected by closed subscriber group cells they are not a member of
+const CSGRejections = "CSG.Rej.Tot"
+
+const (
+	// HandoversOut per-cell counter of UEs handed over from the cell to another one
+	HandoversOut = "HO.Out.Tot"
+	// HandoversIn per-cell counter of UEs handed over to the cell from another one
+	HandoversIn = "HO.In.Tot"
+)
+
+// Transferrer hands UEs over to cells simulated by other simulator instances sharing the model
+type Transferrer interface {
+	// IsRemote returns true if the specified cell is simulated by another instance
+	IsRemote(ecgi types.ECGI) bool
+
+	// Transfer hands the UE over to the instance simulating its serving cell
+	Transfer(ctx context.Context, ue *model.UE) error
+}
+
+// HandoverEngine hands UEs over between cells
+type HandoverEngine struct {
+	cellStore    cells.Store
+	ueStore      ues.Store
+	metricStore  metrics.Store
+	policies     Policies
+	transferrer  Transferrer
+	transactions *txn.Transactions
+	rlf          model.RlfConfig
+	handovers    map[types.IMSI][]handoverRecord
+	purged       time.Time
+	config       model.HandoverConfig
+	mu           sync.Mutex
+}
+
+// NewHandoverEngine creates a new handover engine
+func NewHandoverEngine(cellStore cells.Store, ueStore ues.Store, metricStore metrics.Store) *HandoverEngine {
+	return &HandoverEngine{
+		cellStore:    cellStore,
+		ueStore:      ueStore,
+		metricStore:  metricStore,
+		policies:     noPolicies{},
+		transactions: txn.NewTransactions(),
+		rlf:          model.RlfConfig{T310: defaultT310, TooEarlyWindow: defaultTooEarlyWindow},
+		handovers:    make(map[types.IMSI][]handoverRecord),
+		config: model.HandoverConfig{
+			PingPongWindow: defaultPingPongWindow,
+			Xn:             model.XnConfig{TRelocPrep: defaultTRelocPrep, TRelocOverall: defaultTRelocOverall},
+		},
+	}
+}
+
+// SetPolicies sets the policies consulted when selecting handover targets
+func (h *HandoverEngine) SetPolicies(policies Policies) {
+	h.policies = policies
+}
+
+// SetTransactions sets the transactions the handovers take part in, shared with other updates spanning the same stores
+func (h *HandoverEngine) SetTransactions(transactions *txn.Transactions) {
+	h.transactions = transactions
+}
+
+// SetTransferrer sets the transferrer handing UEs over to cells simulated by other instances
+func (h *HandoverEngine) SetTransferrer(transferrer Transferrer) {
+	h.transferrer = transferrer
+}
+
+// Handover hands the specified UE over to the target cell, unless blacklisted by its serving cell
+func (h *HandoverEngine) Handover(ctx context.Context, imsi types.IMSI, target *model.UECell) error {
+	if err := h.checkBlacklist(ctx, imsi, target.ECGI); err != nil {
+		return err
+	}
+	if h.isRemote(target.ECGI) {
+		return h.transfer(ctx, imsi, target, false)
+	}
+	cell, err := h.cellStore.Get(ctx, target.ECGI)
+	if err != nil {
+		return err
+	}
+	if !cell.IsAvailable() {
+		return errors.New(errors.Forbidden, "cell %d is locked or barred", target.ECGI)
+	}
+	if !cell.Admits(imsi) {
+		h.rejectNonMember(ctx, imsi, cell)
+		return errors.New(errors.Forbidden, "UE %d is not a member of the closed subscriber group of cell %d", imsi, target.ECGI)
+	}
+	ue, err := h.ueStore.Get(ctx, imsi)
+	if err != nil {
+		return err
+	}
+	if ue.Cell == nil || ue.Cell.ECGI == target.ECGI {
+		return h.ueStore.MoveToCell(ctx, imsi, target.ECGI, target.Strength)
+	}
+	source := *ue.Cell
+	interNode := isInterNode(source.ECGI, target.ECGI)
+	if interNode {
+		if err := h.prepareXn(ctx, ue, target); err != nil {
+			return err
+		}
+	}
+	attempts, successes, failures := executionCounters(interNode)
+	h.count(ctx, source.ECGI, attempts)
+	if h.handoverFails(target) || interNode && h.executionFails(ctx, ue, target) {
+		h.count(ctx, source.ECGI, failures)
+		if err := h.failHandover(ctx, ue, target); err != nil {
+			return err
+		}
+		return errors.New(errors.Unavailable, "handover of UE %d to cell %d failed", imsi, target.ECGI)
+	}
+
+	// The UE may have moved on while the handover was prepared and executed over Xn
+	if interNode {
+		current, err := h.ueStore.Get(ctx, imsi)
+		if err != nil {
+			return err
+		}
+		if current.Cell == nil || current.Cell.ECGI != source.ECGI {
+			h.count(ctx, source.ECGI, failures)
+			return errors.New(errors.Conflict, "UE %d left cell %d during its handover to cell %d", imsi, source.ECGI, target.ECGI)
+		}
+	}
+
+	// The UE and the handover counters of both cells are updated at once
+	log.Debugf("Handing UE %d over to cell %d", imsi, target.ECGI)
+	drbs := ue.DRBs
+	err = h.transactions.Update(ctx, func(ctx context.Context, tx *txn.Txn) error {
+		if err := h.ueStore.MoveToCell(ctx, imsi, target.ECGI, target.Strength); err != nil {
+			return err
+		}
+		tx.OnRollback(func() {
+			_ = h.ueStore.MoveToCell(ctx, imsi, source.ECGI, source.Strength)
+		})
+		if err := h.increment(ctx, source.ECGI, HandoversOut); err != nil {
+			return err
+		}
+		if err := h.increment(ctx, target.ECGI, HandoversIn); err != nil {
+			return err
+		}
+		if err := h.increment(ctx, source.ECGI, successes); err != nil {
+			return err
+		}
+		if !interNode {
+			return nil
+		}
+		return h.transferContext(ctx, source.ECGI, target.ECGI, drbs)
+	})
+	if err != nil {
+		return err
+	}
+	if interNode {
+		h.contextTransferred(ctx, ue, source, *target)
+	}
+	h.recordHandover(ctx, imsi, source.ECGI, target.ECGI, false)
+	return nil
+}
+
+// transferContext counts the DRBs of a UE handed over between cells of different E2 nodes as released by the source
+// node and established by the target node, the UE context being transferred over X2/Xn; the handover itself is counted
+// by the Xn procedure counters
+func (h *HandoverEngine) transferContext(ctx context.Context, source types.ECGI, target types.ECGI, drbs []*model.DRB) error {
+	perFiveQI := make(map[int32]uint64)
+	for _, drb := range drbs {
+		perFiveQI[qos.FiveQI(drb)]++
+	}
+	for _, name := range []string{qos.DRBRelActNbr, qos.DRBEstabAtt, qos.DRBEstabSucc} {
+		ecgi := target
+		if name == qos.DRBRelActNbr {
+			ecgi = source
+		}
+		if err := h.add(ctx, ecgi, name, uint64(len(drbs))); err != nil {
+			return err
+		}
+		for fiveQI, count := range perFiveQI {
+			if err := h.add(ctx, ecgi, qos.PerFiveQI(name, fiveQI), count); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// contextTransferred resets the measurement configuration of a UE handed over to a cell of another E2 node, which
+// configures the measurements of the UE anew, and records the transfer of its context; the RRC state and the DRBs of
+// the UE are retained, whereas the neighbors measured in the source cell are dropped until measured in the target cell
+func (h *HandoverEngine) contextTransferred(ctx context.Context, ue *model.UE, source model.UECell, target model.UECell) {
+	if err := h.ueStore.UpdateMeasurements(ctx, ue.IMSI, target.ECGI, target.Strength, nil, false, nil); err != nil {
+		log.Warn(err)
+	}
+	journal.Record(journal.UEContextTransferred, uint64(ue.IMSI), map[string]interface{}{
+		"source":     source.ECGI,
+		"target":     target.ECGI,
+		"sourceNode": types.GetEnbID(uint64(source.ECGI)),
+		"targetNode": types.GetEnbID(uint64(target.ECGI)),
+		"rrcState":   ue.RrcState.String(),
+		"drbs":       len(ue.DRBs),
+	})
+}
+
+// increment increments the specified per-cell counter
+func (h *HandoverEngine) increment(ctx context.Context, ecgi types.ECGI, name string) error {
+	return h.add(ctx, ecgi, name, 1)
+}
+
+// add adds the delta to the specified per-cell counter; counters never decrease, hence they are not rolled back and
+// must be updated after the changes of a transaction which may fail
+func (h *HandoverEngine) add(ctx context.Context, ecgi types.ECGI, name string, delta uint64) error {
+	if delta == 0 {
+		return nil
+	}
+	_, err := h.metricStore.Add(ctx, uint64(ecgi), name, delta)
+	return err
+}
+
+// HandoverUE forces the handover of the specified UE to the target cell regardless of its mobility, i.e. releases
+// the UE from its serving cell, admits it to the target cell, provided the cell is available and has capacity left,
+// and reconfigures its RRC connection, bringing the UE into the connected state
+func (h *HandoverEngine) HandoverUE(ctx context.Context, imsi types.IMSI, ecgi types.ECGI) error {
+	ue, err := h.ueStore.Get(ctx, imsi)
+	if err != nil {
+		return err
+	}
+	if ue.Cell != nil && ue.Cell.ECGI == ecgi {
+		return errors.New(errors.Invalid, "UE %d is already served by cell %d", imsi, ecgi)
+	}
+	target := &model.UECell{ID: types.GEnbID(ecgi), ECGI: ecgi}
+	for _, candidate := range ue.Cells {
+		if candidate.ECGI == ecgi {
+			target.Strength = candidate.Strength
+		}
+	}
+	if h.isRemote(ecgi) {
+		if err := h.checkBlacklist(ctx, imsi, ecgi); err != nil {
+			return err
+		}
+		// Admission to the remote cell is checked by the instance simulating it
+		return h.transfer(ctx, imsi, target, true)
+	}
+	cell, err := h.cellStore.Get(ctx, ecgi)
+	if err != nil {
+		return err
+	}
+	if cell.MaxUEs > 0 && len(h.ueStore.ListUEs(ctx, ecgi)) >= int(cell.MaxUEs) {
+		return errors.New(errors.Forbidden, "cell %d has no capacity left", ecgi)
+	}
+	if err := h.Handover(ctx, imsi, target); err != nil {
+		return err
+	}
+	if !ue.IsAdmitted {
+		if err := h.ueStore.AdmitUE(ctx, imsi); err != nil {
+			return err
+		}
+	}
+	if ue.RrcState != model.RrcConnected {
+		return h.ueStore.UpdateRrcState(ctx, imsi, model.RrcConnected)
+	}
+	return nil
+}
+
+// EvacuateCell hands all UEs served by the specified cell over to the best available candidate cells
+func (h *HandoverEngine) EvacuateCell(ctx context.Context, ecgi types.ECGI) error {
+	cell, err := h.cellStore.Get(ctx, ecgi)
+	if err != nil {
+		return err
+	}
+	for _, ue := range h.ueStore.ListUEs(ctx, ecgi) {
+		target := h.selectTarget(ctx, ue, cell)
+		if target == nil {
+			log.Warnf("No available target cell for UE %d served by cell %d", ue.IMSI, ecgi)
+			continue
+		}
+		if err := h.Handover(ctx, ue.IMSI, target); err != nil {
+			log.Warn(err)
+		}
+	}
+	return nil
+}
+
+// ApplyPolicies hands over the UEs served by cells that are forbidden for them, or that
+// are not the cells they shall use, to the best candidate cell permitted by the policies
+func (h *HandoverEngine) ApplyPolicies(ctx context.Context) {
+	for _, ue := range h.ueStore.ListAllUEs(ctx) {
+		if ue.Cell == nil {
+			continue
+		}
+		serving, err := h.cellStore.Get(ctx, ue.Cell.ECGI)
+		if err != nil {
+			continue
+		}
+		preference := h.policies.Preference(ue.IMSI, serving.ECGI, serving.ECGI)
+		if preference == Shall || (preference != Forbid && !h.hasShallCell(ctx, ue, serving.ECGI)) {
+			continue
+		}
+		if target := h.selectTarget(ctx, ue, serving); target != nil {
+			if err := h.Handover(ctx, ue.IMSI, target); err != nil {
+				log.Warn(err)
+			}
+		}
+	}
+}
+
+// hasShallCell returns true if the UE has an available candidate cell it shall use
+func (h *HandoverEngine) hasShallCell(ctx context.Context, ue *model.UE, serving types.ECGI) bool {
+	for _, candidate := range ue.Cells {
+		if h.policies.Preference(ue.IMSI, serving, candidate.ECGI) == Shall && h.isAvailable(ctx, candidate.ECGI) {
+			return true
+		}
+	}
+	return false
+}
+
+// selectTarget picks the best permitted candidate cell of the UE, falling back to the first
+// permitted neighbor of the serving cell; candidates reported by the UE via a measurement event
+// are ranked ahead of the others, then by their signal strength adjusted by the cell pair offsets
+// and the UE preferences, the cells blacklisted by the serving cell are never picked
+func (h *HandoverEngine) selectTarget(ctx context.Context, ue *model.UE, serving *model.Cell) *model.UECell {
+	reported := make(map[types.ECGI]bool)
+	for _, report := range ue.MeasReports {
+		if report.Event != model.MeasEventA1 && report.Event != model.MeasEventA2 {
+			reported[report.ECGI] = true
+		}
+	}
+	var best *model.UECell
+	bestScore := 0.0
+	bestReported := false
+	for _, candidate := range ue.Cells {
+		if candidate.ECGI == serving.ECGI || h.isBlacklisted(ctx, serving.ECGI, candidate.ECGI) || !h.isPermitted(ctx, ue.IMSI, serving.ECGI, candidate.ECGI) {
+			continue
+		}
+		score := candidate.Strength + h.cellPairOffset(ctx, serving.ECGI, candidate.ECGI)
+		switch h.policies.Preference(ue.IMSI, serving.ECGI, candidate.ECGI) {
+		case Shall:
+			return candidate
+		case Prefer:
+			score += preferenceOffset
+		case Avoid:
+			score -= preferenceOffset
+		}
+		if best == nil || reported[candidate.ECGI] && !bestReported ||
+			reported[candidate.ECGI] == bestReported && score > bestScore {
+			best = candidate
+			bestScore = score
+			bestReported = reported[candidate.ECGI]
+		}
+	}
+	if best != nil {
+		return best
+	}
+	for _, neighbor := range serving.Neighbors {
+		if !h.isBlacklisted(ctx, serving.ECGI, neighbor) && h.isPermitted(ctx, ue.IMSI, serving.ECGI, neighbor) {
+			return &model.UECell{ID: types.GEnbID(neighbor), ECGI: neighbor}
+		}
+	}
+	return nil
+}
+
+func (h *HandoverEngine) isRemote(ecgi types.ECGI) bool {
+	return h.transferrer != nil && h.transferrer.IsRemote(ecgi)
+}
+
+// transfer hands the UE over to a cell simulated by another instance and removes it from this one;
+// the UE is brought into the connected state if requested
+func (h *HandoverEngine) transfer(ctx context.Context, imsi types.IMSI, target *model.UECell, connect bool) error {
+	ue, err := h.ueStore.Get(ctx, imsi)
+	if err != nil {
+		return err
+	}
+	transferred := *ue
+	transferred.Cell = target
+	if connect {
+		transferred.RrcState = model.RrcConnected
+		transferred.IsAdmitted = true
+	}
+	log.Debugf("Handing UE %d over to remote cell %d", imsi, target.ECGI)
+	if err := h.transferrer.Transfer(ctx, &transferred); err != nil {
+		return err
+	}
+	// A distributed UE store may already have dropped the UE upon its admission by the other instance
+	if _, err = h.ueStore.Delete(ctx, imsi); err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+// rejectNonMember counts and records the rejection of a UE by a closed subscriber group cell
+func (h *HandoverEngine) rejectNonMember(ctx context.Context, imsi types.IMSI, cell *model.Cell) {
+	log.Infof("Cell %d rejected UE %d as it is not a member of its closed subscriber group", cell.ECGI, imsi)
+	h.count(ctx, cell.ECGI, CSGRejections)
+	journal.Record(journal.AdmissionRejected, uint64(imsi), map[string]interface{}{"ecgi": cell.ECGI, "cause": "CSG"})
+}
+
+// isPermitted returns true if the cell is available, admits the UE, is not forbidden for the UE served by the serving
+// cell and below its target load; cells simulated by other instances are only checked against the policies, their
+// admission is up to the owning instance
+func (h *HandoverEngine) isPermitted(ctx context.Context, imsi types.IMSI, serving types.ECGI, ecgi types.ECGI) bool {
+	if h.isRemote(ecgi) {
+		return h.policies.Preference(imsi, serving, ecgi) != Forbid
+	}
+	cell, err := h.cellStore.Get(ctx, ecgi)
+	if err != nil || !cell.IsAvailable() || !cell.Admits(imsi) || h.policies.Preference(imsi, serving, ecgi) == Forbid {
+		return false
+	}
+	maxLoad := h.policies.MaxLoad(ecgi)
+	return maxLoad >= 1 || cell.Load(len(h.ueStore.ListUEs(ctx, ecgi))+1) <= maxLoad
+}
+
+func (h *HandoverEngine) isAvailable(ctx context.Context, ecgi types.ECGI) bool {
+	if h.isRemote(ecgi) {
+		return true
+	}
+	cell, err := h.cellStore.Get(ctx, ecgi)
+	return err == nil && cell.IsAvailable()
+}
@@ -0,0 +1,340 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package mobility
+
+import (
+	"context"
+	"io/ioutil"
+	"testing"
+	"time"
+
+	"github.com/onosproject/onos-api/go/onos/ransim/types"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/onosproject/ran-simulator/pkg/journal"
+	"github.com/onosproject/ran-simulator/pkg/model"
+	"github.com/onosproject/ran-simulator/pkg/qos"
+	"github.com/onosproject/ran-simulator/pkg/store/cells"
+	"github.com/onosproject/ran-simulator/pkg/store/metrics"
+	"github.com/onosproject/ran-simulator/pkg/store/nodes"
+	"github.com/onosproject/ran-simulator/pkg/store/ues"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v2"
+)
+
+func cellStore(t *testing.T) cells.Store {
+	m := model.Model{}
+	bytes, err := ioutil.ReadFile("../model/test.yaml")
+	assert.NoError(t, err)
+	err = yaml.Unmarshal(bytes, &m)
+	assert.NoError(t, err)
+	return cells.NewCellRegistry(m.Cells, nodes.NewNodeRegistry(m.Nodes))
+}
+
+func TestEvacuateCell(t *testing.T) {
+	ctx := context.Background()
+	cellStore := cellStore(t)
+	ueStore := ues.NewUERegistry(10, cellStore)
+	metricStore := metrics.NewMetricsStore()
+	handover := NewHandoverEngine(cellStore, ueStore, metricStore)
+
+	ecgi1 := types.ECGI(84325717505)
+	ecgi2 := types.ECGI(84325717506)
+	ecgi3 := types.ECGI(84325717761)
+	for _, ue := range ueStore.ListAllUEs(ctx) {
+		assert.NoError(t, ueStore.MoveToCell(ctx, ue.IMSI, ecgi1, 10))
+	}
+
+	cell1, err := cellStore.Get(ctx, ecgi1)
+	assert.NoError(t, err)
+	locked := *cell1
+	locked.Locked = true
+	locked.Neighbors = []types.ECGI{ecgi2, ecgi3}
+	assert.NoError(t, cellStore.Update(ctx, &locked))
+
+	cell2, err := cellStore.Get(ctx, ecgi2)
+	assert.NoError(t, err)
+	barred := *cell2
+	barred.Barred = true
+	assert.NoError(t, cellStore.Update(ctx, &barred))
+
+	// Handover to a barred cell is not allowed
+	imsi := ueStore.ListAllUEs(ctx)[0].IMSI
+	assert.Error(t, handover.Handover(ctx, imsi, &model.UECell{ECGI: ecgi2}))
+
+	// Nor is handover to a sleeping cell
+	cell3, err := cellStore.Get(ctx, ecgi3)
+	assert.NoError(t, err)
+	asleep := *cell3
+	asleep.Asleep = true
+	assert.NoError(t, cellStore.Update(ctx, &asleep))
+	assert.Error(t, handover.Handover(ctx, imsi, &model.UECell{ECGI: ecgi3}))
+	assert.NoError(t, cellStore.Update(ctx, cell3))
+
+	// UEs are moved to the first available neighbor
+	assert.NoError(t, handover.EvacuateCell(ctx, ecgi1))
+	assert.Equal(t, 0, len(ueStore.ListUEs(ctx, ecgi1)))
+	assert.Equal(t, 0, len(ueStore.ListUEs(ctx, ecgi2)))
+	assert.Equal(t, 10, len(ueStore.ListUEs(ctx, ecgi3)))
+
+	// Handovers are counted by both cells
+	count, _ := metricStore.Get(ctx, uint64(ecgi1), HandoversOut)
+	assert.Equal(t, uint64(10), count)
+	count, _ = metricStore.Get(ctx, uint64(ecgi3), HandoversIn)
+	assert.Equal(t, uint64(10), count)
+}
+
+type testPolicies struct {
+	preferences map[types.ECGI]Preference
+}
+
+func (p *testPolicies) Preference(imsi types.IMSI, serving types.ECGI, ecgi types.ECGI) Preference {
+	return p.preferences[ecgi]
+}
+
+func (p *testPolicies) MaxLoad(ecgi types.ECGI) float64 {
+	return 1
+}
+
+func TestApplyPolicies(t *testing.T) {
+	ctx := context.Background()
+	cellStore := cellStore(t)
+	ueStore := ues.NewUERegistry(4, cellStore)
+	handover := NewHandoverEngine(cellStore, ueStore, metrics.NewMetricsStore())
+
+	ecgi1 := types.ECGI(84325717505)
+	ecgi2 := types.ECGI(84325717506)
+	ecgi3 := types.ECGI(84325717761)
+	for _, ue := range ueStore.ListAllUEs(ctx) {
+		assert.NoError(t, ueStore.MoveToCell(ctx, ue.IMSI, ecgi1, 10))
+		ue.Cells = []*model.UECell{{ECGI: ecgi1, Strength: 10}, {ECGI: ecgi2, Strength: 8}, {ECGI: ecgi3, Strength: 5}}
+	}
+
+	handover.SetPolicies(&testPolicies{preferences: map[types.ECGI]Preference{ecgi1: Forbid, ecgi3: Prefer}})
+	handover.ApplyPolicies(ctx)
+	assert.Equal(t, 0, len(ueStore.ListUEs(ctx, ecgi1)))
+	assert.Equal(t, 4, len(ueStore.ListUEs(ctx, ecgi3)))
+
+	// The UEs handed over to another node measure their neighbors anew
+	for _, ue := range ueStore.ListAllUEs(ctx) {
+		assert.Empty(t, ue.Cells)
+		ue.Cells = []*model.UECell{{ECGI: ecgi2, Strength: 8}, {ECGI: ecgi1, Strength: 7}}
+	}
+	handover.SetPolicies(&testPolicies{preferences: map[types.ECGI]Preference{ecgi2: Shall}})
+	handover.ApplyPolicies(ctx)
+	assert.Equal(t, 4, len(ueStore.ListUEs(ctx, ecgi2)))
+}
+
+func TestHandoverUE(t *testing.T) {
+	ctx := context.Background()
+	cellStore := cellStore(t)
+	ueStore := ues.NewUERegistry(2, cellStore)
+	handover := NewHandoverEngine(cellStore, ueStore, metrics.NewMetricsStore())
+
+	ecgi1 := types.ECGI(84325717505)
+	ecgi2 := types.ECGI(84325717506)
+	ueList := ueStore.ListAllUEs(ctx)
+	ue, other := ueList[0], ueList[1]
+	assert.NoError(t, ueStore.MoveToCell(ctx, ue.IMSI, ecgi1, 10))
+	assert.NoError(t, ueStore.MoveToCell(ctx, other.IMSI, ecgi1, 10))
+	ue.Cells = []*model.UECell{{ECGI: ecgi2, Strength: 7}}
+
+	assert.NoError(t, handover.HandoverUE(ctx, ue.IMSI, ecgi2))
+	assert.Equal(t, ecgi2, ue.Cell.ECGI)
+	assert.Equal(t, 7.0, ue.Cell.Strength)
+	assert.Equal(t, model.RrcConnected, ue.RrcState)
+
+	assert.True(t, errors.IsInvalid(handover.HandoverUE(ctx, ue.IMSI, ecgi2)))
+	assert.True(t, errors.IsNotFound(handover.HandoverUE(ctx, ue.IMSI, 1)))
+
+	// Target cell without capacity left does not admit the UE
+	cell, err := cellStore.Get(ctx, ecgi1)
+	assert.NoError(t, err)
+	full := *cell
+	full.MaxUEs = 1
+	assert.NoError(t, cellStore.Update(ctx, &full))
+	assert.True(t, errors.IsForbidden(handover.HandoverUE(ctx, ue.IMSI, ecgi1)))
+	assert.Equal(t, ecgi2, ue.Cell.ECGI)
+}
+
+func TestInterNodeHandover(t *testing.T) {
+	ctx := context.Background()
+	cellStore := cellStore(t)
+	ueStore := ues.NewUERegistry(1, cellStore)
+	metricStore := metrics.NewMetricsStore()
+	handover := NewHandoverEngine(cellStore, ueStore, metricStore)
+
+	ecgi1 := types.ECGI(84325717505)
+	ecgi2 := types.ECGI(84325717506)
+	ecgi3 := types.ECGI(84325717761)
+	ue := ueStore.ListAllUEs(ctx)[0]
+	assert.NoError(t, ueStore.MoveToCell(ctx, ue.IMSI, ecgi1, 10))
+	assert.NoError(t, ueStore.UpdateRrcState(ctx, ue.IMSI, model.RrcConnected))
+	ue.DRBs = []*model.DRB{{ID: 1}, {ID: 2}}
+	ue.MeasGaps = true
+	ue.MeasReports = []*model.MeasReport{{Event: model.MeasEventA3, ECGI: ecgi3, Strength: 12}}
+	ue.Cells = []*model.UECell{{ECGI: ecgi3, Strength: 12}}
+	count := func(ecgi types.ECGI, name string) uint64 {
+		value, _ := metricStore.Get(ctx, uint64(ecgi), name)
+		c, _ := value.(uint64)
+		return c
+	}
+
+	// Handovers between cells of the same node keep the measurement configuration
+	assert.NoError(t, handover.Handover(ctx, ue.IMSI, &model.UECell{ECGI: ecgi2, Strength: 11}))
+	assert.Equal(t, 1, len(ue.MeasReports))
+	assert.Equal(t, 1, len(ue.Cells))
+	assert.Equal(t, uint64(0), count(ecgi2, XnResourceAllocationSuccesses))
+
+	// The context of the UE is transferred to the other node
+	assert.NoError(t, handover.Handover(ctx, ue.IMSI, &model.UECell{ECGI: ecgi3, Strength: 12}))
+	assert.Equal(t, ecgi3, ue.Cell.ECGI)
+	assert.Equal(t, model.RrcConnected, ue.RrcState)
+	assert.Equal(t, 2, len(ue.DRBs))
+	assert.False(t, ue.MeasGaps)
+	assert.Equal(t, 0, len(ue.MeasReports))
+	assert.Equal(t, 0, len(ue.Cells))
+
+	assert.Equal(t, uint64(1), count(ecgi2, XnExecutionSuccesses))
+	assert.Equal(t, uint64(1), count(ecgi3, XnResourceAllocationSuccesses))
+	assert.Equal(t, uint64(2), count(ecgi2, qos.DRBRelActNbr))
+	assert.Equal(t, uint64(2), count(ecgi3, qos.DRBEstabAtt))
+	assert.Equal(t, uint64(2), count(ecgi3, qos.DRBEstabSucc))
+	assert.Equal(t, uint64(2), count(ecgi2, qos.PerFiveQI(qos.DRBRelActNbr, 9)))
+	assert.Equal(t, uint64(2), count(ecgi3, qos.PerFiveQI(qos.DRBEstabSucc, 9)))
+
+	entries := journal.Default().Query(journal.Filter{EntityID: uint64(ue.IMSI), Kinds: []journal.Kind{journal.UEContextTransferred}})
+	if assert.Equal(t, 1, len(entries)) {
+		assert.Equal(t, types.GetEnbID(uint64(ecgi2)), entries[0].Details["sourceNode"])
+		assert.Equal(t, types.GetEnbID(uint64(ecgi3)), entries[0].Details["targetNode"])
+		assert.Equal(t, 2, entries[0].Details["drbs"])
+	}
+}
+
+func TestClosedSubscriberGroup(t *testing.T) {
+	ctx := context.Background()
+	cellStore := cellStore(t)
+	ueStore := ues.NewUERegistry(2, cellStore)
+	metricStore := metrics.NewMetricsStore()
+	handover := NewHandoverEngine(cellStore, ueStore, metricStore)
+
+	ecgi1 := types.ECGI(84325717505)
+	ecgi2 := types.ECGI(84325717506)
+	ueList := ueStore.ListAllUEs(ctx)
+	member, other := ueList[0], ueList[1]
+	cell2, err := cellStore.Get(ctx, ecgi2)
+	assert.NoError(t, err)
+	private := *cell2
+	private.CSG = true
+	private.AllowedIMSIs = []types.IMSI{member.IMSI}
+	assert.NoError(t, cellStore.Update(ctx, &private))
+
+	for _, ue := range ueList {
+		assert.NoError(t, ueStore.MoveToCell(ctx, ue.IMSI, ecgi1, 10))
+		ue.Cells = []*model.UECell{{ECGI: ecgi2, Strength: 20}}
+	}
+
+	// Non-members are rejected and the private cell is never selected as their target
+	assert.True(t, errors.IsForbidden(handover.HandoverUE(ctx, other.IMSI, ecgi2)))
+	count, ok := metricStore.Get(ctx, uint64(ecgi2), CSGRejections)
+	assert.True(t, ok)
+	assert.Equal(t, uint64(1), count)
+	assert.False(t, handover.isPermitted(ctx, other.IMSI, ecgi1, ecgi2))
+
+	assert.True(t, handover.isPermitted(ctx, member.IMSI, ecgi1, ecgi2))
+	assert.NoError(t, handover.HandoverUE(ctx, member.IMSI, ecgi2))
+}
+
+func TestPingPongs(t *testing.T) {
+	ctx := context.Background()
+	cellStore := cellStore(t)
+	ueStore := ues.NewUERegistry(1, cellStore)
+	metricStore := metrics.NewMetricsStore()
+	handover := NewHandoverEngine(cellStore, ueStore, metricStore)
+
+	ecgi1 := types.ECGI(84325717505)
+	ecgi2 := types.ECGI(84325717506)
+	ecgi3 := types.ECGI(84325717761)
+	ue := ueStore.ListAllUEs(ctx)[0]
+	assert.NoError(t, ueStore.MoveToCell(ctx, ue.IMSI, ecgi1, 10))
+
+	// Handing the UE back to the cell it came from within the window is a ping-pong of that cell
+	assert.NoError(t, handover.Handover(ctx, ue.IMSI, &model.UECell{ECGI: ecgi2}))
+	assert.NoError(t, handover.Handover(ctx, ue.IMSI, &model.UECell{ECGI: ecgi1}))
+	count, _ := metricStore.Get(ctx, uint64(ecgi1), PingPongs)
+	assert.Equal(t, uint64(1), count)
+	count, _ = metricStore.Get(ctx, uint64(ecgi1), PingPongMetric(ecgi2))
+	assert.Equal(t, uint64(1), count)
+	_, ok := metricStore.Get(ctx, uint64(ecgi2), PingPongs)
+	assert.False(t, ok)
+	labels := journal.DefaultLabels().List(time.Time{})
+	if assert.NotEmpty(t, labels) {
+		assert.Equal(t, journal.PingPongLabel, labels[len(labels)-1].Kind)
+		assert.Equal(t, []uint64{uint64(ue.IMSI), uint64(ecgi2), uint64(ecgi1)}, labels[len(labels)-1].Entities)
+	}
+
+	// Handovers to other cells or after the window are not
+	handover.SetHandoverConfig(model.HandoverConfig{PingPongWindow: time.Millisecond})
+	assert.NoError(t, handover.Handover(ctx, ue.IMSI, &model.UECell{ECGI: ecgi3}))
+	time.Sleep(2 * time.Millisecond)
+	assert.NoError(t, handover.Handover(ctx, ue.IMSI, &model.UECell{ECGI: ecgi1}))
+	count, _ = metricStore.Get(ctx, uint64(ecgi1), PingPongs)
+	assert.Equal(t, uint64(1), count)
+
+	// The histories of UEs not handed over within the windows are forgotten
+	handover.SetRlfConfig(model.RlfConfig{TooEarlyWindow: time.Millisecond})
+	time.Sleep(2 * time.Millisecond)
+	handover.purgeHandovers(time.Now())
+	assert.Empty(t, handover.handovers)
+
+	// Cell pair offsets set by the RIC bias the selection of handover targets
+	serving, err := cellStore.Get(ctx, ecgi1)
+	assert.NoError(t, err)
+	ue.Cells = []*model.UECell{{ECGI: ecgi2, Strength: 8}, {ECGI: ecgi3, Strength: 5}}
+	assert.Equal(t, ecgi2, handover.selectTarget(ctx, ue, serving).ECGI)
+	assert.NoError(t, metricStore.Set(ctx, uint64(ecgi1), HandoverOffsetAttribute(ecgi3), int32(4)))
+	assert.Equal(t, ecgi3, handover.selectTarget(ctx, ue, serving).ECGI)
+
+	// Neighbors reported via a measurement event are picked ahead of the others, serving cell events aside
+	assert.NoError(t, metricStore.Set(ctx, uint64(ecgi1), HandoverOffsetAttribute(ecgi3), int32(0)))
+	ue.MeasReports = []*model.MeasReport{{Event: model.MeasEventA2, ECGI: ecgi1}, {Event: model.MeasEventA4, ECGI: ecgi3}}
+	assert.Equal(t, ecgi3, handover.selectTarget(ctx, ue, serving).ECGI)
+	ue.MeasReports = []*model.MeasReport{{Event: model.MeasEventA2, ECGI: ecgi1}}
+	assert.Equal(t, ecgi2, handover.selectTarget(ctx, ue, serving).ECGI)
+}
+
+func TestBlacklist(t *testing.T) {
+	ctx := context.Background()
+	cellStore := cellStore(t)
+	ueStore := ues.NewUERegistry(1, cellStore)
+	metricStore := metrics.NewMetricsStore()
+	handover := NewHandoverEngine(cellStore, ueStore, metricStore)
+
+	ecgi1 := types.ECGI(84325717505)
+	ecgi2 := types.ECGI(84325717506)
+	ecgi3 := types.ECGI(84325717761)
+	ue := ueStore.ListAllUEs(ctx)[0]
+	assert.NoError(t, ueStore.MoveToCell(ctx, ue.IMSI, ecgi1, 10))
+	serving, err := cellStore.Get(ctx, ecgi1)
+	assert.NoError(t, err)
+	ue.Cells = []*model.UECell{{ECGI: ecgi2, Strength: 8}, {ECGI: ecgi3, Strength: 5}}
+
+	// Blacklisted neighbors are never targeted, neither when selected nor when forced
+	assert.NoError(t, metricStore.Set(ctx, uint64(ecgi1), HandoverBlacklistAttribute(ecgi2), int32(1)))
+	assert.Equal(t, ecgi3, handover.selectTarget(ctx, ue, serving).ECGI)
+	assert.True(t, errors.IsForbidden(handover.Handover(ctx, ue.IMSI, &model.UECell{ECGI: ecgi2})))
+	assert.True(t, errors.IsForbidden(handover.HandoverUE(ctx, ue.IMSI, ecgi2)))
+	moved, err := ueStore.Get(ctx, ue.IMSI)
+	assert.NoError(t, err)
+	assert.Equal(t, ecgi1, moved.Cell.ECGI)
+
+	// Only the serving cell's blacklist applies
+	assert.NoError(t, metricStore.Set(ctx, uint64(ecgi3), HandoverBlacklistAttribute(ecgi1), int32(1)))
+	assert.Equal(t, ecgi3, handover.selectTarget(ctx, ue, serving).ECGI)
+
+	// Whitelisting the neighbor again permits handovers to it
+	assert.NoError(t, metricStore.Set(ctx, uint64(ecgi1), HandoverBlacklistAttribute(ecgi2), int32(0)))
+	assert.Equal(t, ecgi2, handover.selectTarget(ctx, ue, serving).ECGI)
+	assert.NoError(t, handover.Handover(ctx, ue.IMSI, &model.UECell{ECGI: ecgi2}))
+}
@@ -0,0 +1,42 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package mobility
+
+import (
+	"context"
+
+	"github.com/onosproject/ran-simulator/pkg/model"
+)
+
+const defaultPenetrationLoss = 20.0
+
+// SetIndoor configures the buildings UEs can be inside of; unset values are replaced by defaults
+func (c *MeasurementController) SetIndoor(config model.IndoorConfig) {
+	if config.PenetrationLoss == 0 {
+		config.PenetrationLoss = defaultPenetrationLoss
+	}
+	c.indoor = config
+}
+
+// updateIndoor determines whether the UE is within a building, updating the UE if it entered or left one
+func (c *MeasurementController) updateIndoor(ctx context.Context, ue *model.UE) bool {
+	indoor := c.indoor.IsIndoor(ue.Location)
+	if indoor != ue.Indoor {
+		log.Debugf("UE %d indoor=%t", ue.IMSI, indoor)
+		if err := c.ueStore.UpdateIndoor(ctx, ue.IMSI, indoor); err != nil {
+			log.Warn(err)
+		}
+	}
+	return indoor
+}
+
+// penetrationLoss returns the attenuation by the building walls of the link between a UE and the cell, which applies
+// if only one of them is indoor; cells of the indoor environment are considered to be within a building
+func (c *MeasurementController) penetrationLoss(indoor bool, cell *model.Cell) float64 {
+	if len(c.indoor.Buildings) == 0 || indoor == (cell.Environment == model.Indoor) {
+		return 0
+	}
+	return c.indoor.PenetrationLoss
+}
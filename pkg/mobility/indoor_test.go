@@ -0,0 +1,49 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package mobility
+
+import (
+	"context"
+	"testing"
+
+	"github.com/onosproject/onos-api/go/onos/ransim/types"
+	"github.com/onosproject/ran-simulator/pkg/model"
+	"github.com/onosproject/ran-simulator/pkg/store/metrics"
+	"github.com/onosproject/ran-simulator/pkg/store/ues"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIndoor(t *testing.T) {
+	ctx := context.Background()
+	cells := cellStore(t)
+	ueStore := ues.NewUERegistry(1, cells)
+	controller := NewMeasurementController(cells, ueStore, metrics.NewMetricsStore())
+	ecgi1 := types.ECGI(84325717505)
+
+	ue := ueStore.ListAllUEs(ctx)[0]
+	assert.NoError(t, ueStore.MoveToCell(ctx, ue.IMSI, ecgi1, 0))
+	assert.NoError(t, ueStore.UpdateRrcState(ctx, ue.IMSI, model.RrcConnected))
+	assert.NoError(t, ueStore.MoveToCoordinate(ctx, ue.IMSI, model.Coordinate{Lat: 46.0, Lng: 29.0013}, 0))
+	controller.step(ctx)
+	outdoor := ue.Cell.Strength
+	assert.False(t, ue.Indoor)
+
+	// Entering a building attenuates the outdoor serving cell by the penetration loss
+	controller.SetIndoor(model.IndoorConfig{Buildings: [][]model.Coordinate{
+		{{Lat: 45.99, Lng: 28.99}, {Lat: 46.01, Lng: 28.99}, {Lat: 46.01, Lng: 29.01}, {Lat: 45.99, Lng: 29.01}},
+	}})
+	controller.step(ctx)
+	assert.True(t, ue.Indoor)
+	assert.InDelta(t, outdoor-defaultPenetrationLoss, ue.Cell.Strength, 1e-9)
+
+	// Indoor cells are not attenuated for indoor UEs
+	assert.Equal(t, 0.0, controller.penetrationLoss(true, &model.Cell{Environment: model.Indoor}))
+	assert.Equal(t, defaultPenetrationLoss, controller.penetrationLoss(false, &model.Cell{Environment: model.Indoor}))
+
+	// Leaving the building
+	assert.NoError(t, ueStore.MoveToCoordinate(ctx, ue.IMSI, model.Coordinate{Lat: 46.0, Lng: 29.02}, 0))
+	controller.step(ctx)
+	assert.False(t, ue.Indoor)
+}
@@ -0,0 +1,261 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package mobility
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/onosproject/onos-api/go/onos/ransim/types"
+	"github.com/onosproject/ran-simulator/pkg/model"
+	"github.com/onosproject/ran-simulator/pkg/store/metrics"
+)
+
+// Parameters of the measurement events, kept as cell attributes named after MeasEventAttribute so that they can be
+// configured via RC control or via the metrics API; attributes of a UE, keyed by its IMSI, override those of its
+// serving cell
+const (
+	// MeasEventEnabled any non-zero value enables the event
+	MeasEventEnabled = "enabled"
+	// MeasEventThreshold threshold in dBm of the A1, A2, A4 and B1 events, and the serving cell threshold of A5
+	MeasEventThreshold = "threshold"
+	// MeasEventThreshold2 neighbor threshold in dBm of the A5 event
+	MeasEventThreshold2 = "threshold2"
+	// MeasEventOffset offset in dB of the A3 and A6 events
+	MeasEventOffset = "offset"
+	// MeasEventHysteresis hysteresis in dB applied to the entering and leaving conditions
+	MeasEventHysteresis = "hysteresis"
+	// MeasEventTimeToTrigger time in milliseconds the entering condition has to hold for the event to trigger
+	MeasEventTimeToTrigger = "timeToTrigger"
+)
+
+// measEventParameters lists the parameters applicable to each event
+var measEventParameters = map[model.MeasEventType][]string{
+	model.MeasEventA1: {MeasEventEnabled, MeasEventThreshold, MeasEventHysteresis, MeasEventTimeToTrigger},
+	model.MeasEventA2: {MeasEventEnabled, MeasEventThreshold, MeasEventHysteresis, MeasEventTimeToTrigger},
+	model.MeasEventA3: {MeasEventEnabled, MeasEventOffset, MeasEventHysteresis, MeasEventTimeToTrigger},
+	model.MeasEventA4: {MeasEventEnabled, MeasEventThreshold, MeasEventHysteresis, MeasEventTimeToTrigger},
+	model.MeasEventA5: {MeasEventEnabled, MeasEventThreshold, MeasEventThreshold2, MeasEventHysteresis, MeasEventTimeToTrigger},
+	model.MeasEventA6: {MeasEventEnabled, MeasEventOffset, MeasEventHysteresis, MeasEventTimeToTrigger},
+	model.MeasEventB1: {MeasEventEnabled, MeasEventThreshold, MeasEventHysteresis, MeasEventTimeToTrigger},
+}
+
+// MeasEventAttribute returns the name of the attribute holding the given parameter of a measurement event,
+// e.g. measEvent.A3.offset
+func MeasEventAttribute(event model.MeasEventType, parameter string) string {
+	return fmt.Sprintf("measEvent.%s.%s", event, parameter)
+}
+
+// measEventConfig is the configuration of a measurement event in effect for a UE
+type measEventConfig struct {
+	event         model.MeasEventType
+	threshold     float64
+	threshold2    float64
+	offset        float64
+	hysteresis    float64
+	timeToTrigger time.Duration
+}
+
+// conditions returns whether the entering and the leaving conditions of the event are satisfied for the given
+// serving cell and neighbor RSRP
+func (e measEventConfig) conditions(ms float64, mn float64) (entering bool, leaving bool) {
+	hys := e.hysteresis
+	switch e.event {
+	case model.MeasEventA1:
+		return ms-hys > e.threshold, ms+hys < e.threshold
+	case model.MeasEventA2:
+		return ms+hys < e.threshold, ms-hys > e.threshold
+	case model.MeasEventA3, model.MeasEventA6:
+		return mn-hys > ms+e.offset, mn+hys < ms+e.offset
+	case model.MeasEventA4, model.MeasEventB1:
+		return mn-hys > e.threshold, mn+hys < e.threshold
+	case model.MeasEventA5:
+		return ms+hys < e.threshold && mn-hys > e.threshold2, ms-hys > e.threshold || mn+hys < e.threshold2
+	}
+	return false, false
+}
+
+// appliesTo returns true if the event is evaluated for the given candidate cell; A1 and A2 are only evaluated for
+// the serving cell, A6 only for intra-frequency and B1 only for inter-frequency neighbors, as the simulator models
+// neither secondary cells nor other radio access technologies
+func (e measEventConfig) appliesTo(candidate *model.UECell, serving types.ECGI) bool {
+	switch e.event {
+	case model.MeasEventA1, model.MeasEventA2:
+		return candidate.ECGI == serving
+	case model.MeasEventA6:
+		return candidate.ECGI != serving && !candidate.InterFrequency
+	case model.MeasEventB1:
+		return candidate.ECGI != serving && candidate.InterFrequency
+	}
+	return candidate.ECGI != serving
+}
+
+// measEventKey identifies an event tracked for a UE; events are tracked afresh after a change of the serving cell
+type measEventKey struct {
+	event   model.MeasEventType
+	serving types.ECGI
+	ecgi    types.ECGI
+}
+
+// measEventState tracks since when the entering condition of an event holds and whether the event has triggered
+type measEventState struct {
+	entered time.Time
+	report  *model.MeasReport
+}
+
+// initMeasEventAttributes makes sure the measurement event attributes exist for all cells, as RC control only
+// updates existing attributes
+func (c *MeasurementController) initMeasEventAttributes(ctx context.Context) error {
+	cellList, err := c.cellStore.List(ctx)
+	if err != nil {
+		return err
+	}
+	for _, cell := range cellList {
+		for event, parameters := range measEventParameters {
+			for _, parameter := range parameters {
+				name := MeasEventAttribute(event, parameter)
+				if _, ok := c.metricStore.Get(ctx, uint64(cell.ECGI), name); !ok {
+					_ = c.metricStore.Set(ctx, uint64(cell.ECGI), name, int32(0))
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// measEventConfigs returns the measurement events enabled for the UE on its serving cell
+func (c *MeasurementController) measEventConfigs(ctx context.Context, imsi types.IMSI, ecgi types.ECGI) []measEventConfig {
+	if c.metricStore == nil {
+		return nil
+	}
+	attribute := func(event model.MeasEventType, parameter string) (interface{}, bool) {
+		name := MeasEventAttribute(event, parameter)
+		if value, ok := c.metricStore.Get(ctx, uint64(imsi), name); ok {
+			return value, true
+		}
+		return c.metricStore.Get(ctx, uint64(ecgi), name)
+	}
+	parameter := func(event model.MeasEventType, parameter string) float64 {
+		value, _ := attribute(event, parameter)
+		f, _ := toFloat(value)
+		return f
+	}
+
+	var configs []measEventConfig
+	for _, event := range model.MeasEventTypes {
+		if enabled, ok := attribute(event, MeasEventEnabled); !ok || !metrics.IsSet(enabled) {
+			continue
+		}
+		configs = append(configs, measEventConfig{
+			event:         event,
+			threshold:     parameter(event, MeasEventThreshold),
+			threshold2:    parameter(event, MeasEventThreshold2),
+			offset:        parameter(event, MeasEventOffset),
+			hysteresis:    parameter(event, MeasEventHysteresis),
+			timeToTrigger: time.Duration(parameter(event, MeasEventTimeToTrigger)) * time.Millisecond,
+		})
+	}
+	return configs
+}
+
+// evaluateMeasEvents evaluates the configured events against the serving cell and the candidate cells measured by
+// the UE and returns the events that are triggered, i.e. whose entering condition has held for the time to trigger
+// and whose leaving condition has not been met since
+func (c *MeasurementController) evaluateMeasEvents(imsi types.IMSI, serving *model.UECell, candidates []*model.UECell,
+	configs []measEventConfig, now time.Time) []*model.MeasReport {
+	states := c.measEvents[imsi]
+	if states == nil {
+		states = make(map[measEventKey]*measEventState)
+		c.measEvents[imsi] = states
+	}
+
+	tracked := make(map[measEventKey]bool)
+	cells := append([]*model.UECell{serving}, candidates...)
+	for _, config := range configs {
+		for _, cell := range cells {
+			if !config.appliesTo(cell, serving.ECGI) {
+				continue
+			}
+			key := measEventKey{event: config.event, serving: serving.ECGI, ecgi: cell.ECGI}
+			entering, leaving := config.conditions(serving.Strength, cell.Strength)
+			state, ok := states[key]
+			switch {
+			case !ok && !entering:
+				continue
+			case !ok:
+				state = &measEventState{entered: now}
+				states[key] = state
+			case state.report == nil && !entering, state.report != nil && leaving:
+				delete(states, key)
+				continue
+			}
+			tracked[key] = true
+			if state.report == nil && now.Sub(state.entered) >= config.timeToTrigger {
+				log.Debugf("UE %d measurement event %s triggered for cell %d", imsi, config.event, cell.ECGI)
+				state.report = &model.MeasReport{Event: config.event, ECGI: cell.ECGI, Triggered: now}
+			}
+			if state.report != nil {
+				state.report.Strength = cell.Strength
+			}
+		}
+	}
+
+	var reports []*model.MeasReport
+	for key, state := range states {
+		if !tracked[key] {
+			delete(states, key)
+			continue
+		}
+		if state.report != nil {
+			report := *state.report
+			reports = append(reports, &report)
+		}
+	}
+	sort.Slice(reports, func(i, j int) bool {
+		if reports[i].Event != reports[j].Event {
+			return reports[i].Event < reports[j].Event
+		}
+		return reports[i].Strength > reports[j].Strength
+	})
+	return reports
+}
+
+// toFloat converts numeric and boolean attribute values to float
+func toFloat(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case int:
+		return float64(v), true
+	case int8:
+		return float64(v), true
+	case int16:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case uint:
+		return float64(v), true
+	case uint8:
+		return float64(v), true
+	case uint16:
+		return float64(v), true
+	case uint32:
+		return float64(v), true
+	case uint64:
+		return float64(v), true
+	case float32:
+		return float64(v), true
+	case float64:
+		return v, true
+	case bool:
+		if v {
+			return 1, true
+		}
+		return 0, true
+	}
+	return 0, false
+}
@@ -0,0 +1,106 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package mobility
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/onosproject/onos-api/go/onos/ransim/types"
+	"github.com/onosproject/ran-simulator/pkg/model"
+	"github.com/onosproject/ran-simulator/pkg/store/metrics"
+	"github.com/onosproject/ran-simulator/pkg/store/ues"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMeasEventConfigs(t *testing.T) {
+	ctx := context.Background()
+	cells := cellStore(t)
+	metricStore := metrics.NewMetricsStore()
+	controller := NewMeasurementController(cells, ues.NewUERegistry(1, cells), metricStore)
+	assert.NoError(t, controller.initMeasEventAttributes(ctx))
+
+	ecgi := types.ECGI(84325717505)
+	imsi := types.IMSI(1234)
+	value, ok := metricStore.Get(ctx, uint64(ecgi), "measEvent.A3.offset")
+	assert.True(t, ok)
+	assert.Equal(t, int32(0), value)
+	assert.Empty(t, controller.measEventConfigs(ctx, imsi, ecgi))
+
+	// Cell attributes set via RC control apply to all UEs of the cell...
+	assert.NoError(t, metricStore.Set(ctx, uint64(ecgi), MeasEventAttribute(model.MeasEventA3, MeasEventEnabled), int32(1)))
+	assert.NoError(t, metricStore.Set(ctx, uint64(ecgi), MeasEventAttribute(model.MeasEventA3, MeasEventOffset), int32(3)))
+	assert.NoError(t, metricStore.Set(ctx, uint64(ecgi), MeasEventAttribute(model.MeasEventA3, MeasEventTimeToTrigger), int32(640)))
+	configs := controller.measEventConfigs(ctx, imsi, ecgi)
+	assert.Len(t, configs, 1)
+	assert.Equal(t, model.MeasEventA3, configs[0].event)
+	assert.Equal(t, 3.0, configs[0].offset)
+	assert.Equal(t, 640*time.Millisecond, configs[0].timeToTrigger)
+
+	// ...unless overridden by the attributes of the UE
+	assert.NoError(t, metricStore.Set(ctx, uint64(imsi), MeasEventAttribute(model.MeasEventA3, MeasEventOffset), int64(-2)))
+	assert.NoError(t, metricStore.Set(ctx, uint64(imsi), MeasEventAttribute(model.MeasEventA2, MeasEventEnabled), true))
+	configs = controller.measEventConfigs(ctx, imsi, ecgi)
+	assert.Len(t, configs, 2)
+	assert.Equal(t, model.MeasEventA2, configs[0].event)
+	assert.Equal(t, -2.0, configs[1].offset)
+}
+
+func TestEvaluateMeasEvents(t *testing.T) {
+	controller := NewMeasurementController(nil, nil, nil)
+	imsi := types.IMSI(1234)
+	serving := &model.UECell{ECGI: 1, Strength: -90}
+	intra := &model.UECell{ECGI: 2, Strength: -85}
+	inter := &model.UECell{ECGI: 3, Strength: -95, InterFrequency: true}
+	configs := []measEventConfig{
+		{event: model.MeasEventA2, threshold: -95, hysteresis: 1},
+		{event: model.MeasEventA3, offset: 3, hysteresis: 1, timeToTrigger: 2 * time.Second},
+		{event: model.MeasEventA6, offset: 20},
+		{event: model.MeasEventB1, threshold: -100},
+	}
+	now := time.Now()
+
+	// A3 has to hold for the time to trigger; A2 and A6 are not satisfied, B1 only applies to the inter-frequency cell
+	reports := controller.evaluateMeasEvents(imsi, serving, []*model.UECell{intra, inter}, configs, now)
+	assert.Len(t, reports, 1)
+	assert.Equal(t, model.MeasEventB1, reports[0].Event)
+	assert.Equal(t, types.ECGI(3), reports[0].ECGI)
+
+	reports = controller.evaluateMeasEvents(imsi, serving, []*model.UECell{intra, inter}, configs, now.Add(2*time.Second))
+	assert.Len(t, reports, 2)
+	assert.Equal(t, model.MeasEventA3, reports[0].Event)
+	assert.Equal(t, types.ECGI(2), reports[0].ECGI)
+	assert.Equal(t, now.Add(2*time.Second), reports[0].Triggered)
+
+	// Within the hysteresis, the triggered event remains reported
+	intra.Strength = -88
+	reports = controller.evaluateMeasEvents(imsi, serving, []*model.UECell{intra}, configs, now.Add(3*time.Second))
+	assert.Len(t, reports, 1)
+	assert.Equal(t, -88.0, reports[0].Strength)
+
+	// ...until the leaving condition is met
+	intra.Strength = -93
+	reports = controller.evaluateMeasEvents(imsi, serving, []*model.UECell{intra}, configs, now.Add(4*time.Second))
+	assert.Empty(t, reports)
+
+	// The entering condition of A3 has to hold for the time to trigger again
+	intra.Strength = -80
+	serving.Strength = -97
+	reports = controller.evaluateMeasEvents(imsi, serving, []*model.UECell{intra}, configs, now.Add(5*time.Second))
+	assert.Len(t, reports, 1)
+	assert.Equal(t, model.MeasEventA2, reports[0].Event)
+	assert.Equal(t, types.ECGI(1), reports[0].ECGI)
+	reports = controller.evaluateMeasEvents(imsi, serving, []*model.UECell{intra}, configs, now.Add(7*time.Second))
+	assert.Len(t, reports, 2)
+
+	// Events are tracked afresh after a change of the serving cell
+	reports = controller.evaluateMeasEvents(imsi, &model.UECell{ECGI: 2, Strength: -80}, []*model.UECell{{ECGI: 1, Strength: -70}}, configs, now.Add(8*time.Second))
+	assert.Empty(t, reports)
+
+	// The events of deleted UEs are forgotten
+	controller.purgeUEs(nil)
+	assert.Empty(t, controller.measEvents)
+}
@@ -0,0 +1,269 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package mobility
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/onosproject/onos-api/go/onos/ransim/types"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/onosproject/ran-simulator/pkg/model"
+	"github.com/onosproject/ran-simulator/pkg/radio"
+	"github.com/onosproject/ran-simulator/pkg/store/cells"
+	"github.com/onosproject/ran-simulator/pkg/store/metrics"
+	"github.com/onosproject/ran-simulator/pkg/store/ues"
+)
+
+const (
+	// gapActivationThreshold serving cell RSRP in dBm below which measurement gaps are configured
+	gapActivationThreshold = -100.0
+	// gapHysteresis margin in dB above the activation threshold the serving cell RSRP has to exceed for the
+	// measurement gaps to be released again
+	gapHysteresis = 3.0
+)
+
+// MeasurementController measures the RSRP of the serving cell and its neighbors for connected UEs. Intra-frequency
+// neighbors are always measured, whereas inter-frequency neighbors can only be measured during measurement gaps,
+// which are configured while the serving cell is weak. The measurement events configured for the UE are evaluated
+// against the measured cells, yielding the measurement reports of the UE. If radio link monitoring is configured,
+// the radio link of a UE fails once the serving cell RSRP has stayed below Qout for T310. The links between indoor
+// UEs and outdoor cells, and vice versa, are attenuated by the building walls, whereas the links to mmWave cells may
+// be blocked temporarily, attenuating the RSRP sharply.
+type MeasurementController struct {
+	cellStore   cells.Store
+	ueStore     ues.Store
+	metricStore metrics.Store
+	measEvents  map[types.IMSI]map[measEventKey]*measEventState
+	rlf         model.RlfConfig
+	rlfHandler  RadioLinkFailureHandler
+	remoteCells RemoteCells
+	outOfSync   map[types.IMSI]time.Time
+	blockage    model.BlockageConfig
+	indoor      model.IndoorConfig
+	blocked     map[link]time.Time
+	mobility    model.MobilityConfig
+	// interval is the current tick interval, only changed by the ticks themselves
+	interval time.Duration
+	statsMu  sync.RWMutex
+	stats    TickStats
+	cancel   context.CancelFunc
+	// drxCycles are the connected DRX cycles by UE type; the UEs measure once per cycle
+	drxCycles map[model.UEType]time.Duration
+	// lastMeasured holds the time of the last measurement of the connected UEs with a DRX cycle
+	lastMeasured map[types.IMSI]time.Time
+	// relations holds the time the neighbor relations added automatically were last detected by a UE
+	relations map[relation]time.Time
+}
+
+// RemoteCells provides the cells simulated by other instances
+type RemoteCells interface {
+	// RemoteCell returns the specified cell if it is simulated by another instance
+	RemoteCell(ecgi types.ECGI) (*model.Cell, bool)
+}
+
+// RadioLinkFailureHandler handles the radio link failures detected by the measurements
+type RadioLinkFailureHandler interface {
+	// RadioLinkFailure handles the failure of the radio link of the UE to its serving cell
+	RadioLinkFailure(ctx context.Context, imsi types.IMSI) error
+}
+
+// NewMeasurementController creates a new measurement controller
+func NewMeasurementController(cellStore cells.Store, ueStore ues.Store, metricStore metrics.Store) *MeasurementController {
+	c := &MeasurementController{
+		cellStore:    cellStore,
+		ueStore:      ueStore,
+		metricStore:  metricStore,
+		measEvents:   make(map[types.IMSI]map[measEventKey]*measEventState),
+		outOfSync:    make(map[types.IMSI]time.Time),
+		blocked:      make(map[link]time.Time),
+		lastMeasured: make(map[types.IMSI]time.Time),
+		relations:    make(map[relation]time.Time),
+	}
+	c.SetMobility(model.MobilityConfig{})
+	return c
+}
+
+// SetRadioLinkMonitoring enables the detection of radio link failures, which are passed to the given handler;
+// radio link failures are only detected if Qout is configured
+func (c *MeasurementController) SetRadioLinkMonitoring(config model.RlfConfig, handler RadioLinkFailureHandler) {
+	if config.T310 == 0 {
+		config.T310 = defaultT310
+	}
+	c.rlf = config
+	c.rlfHandler = handler
+}
+
+// SetRemoteCells sets the cells simulated by other instances, which the UEs measure as neighbors of their serving
+// cells so that they can be handed over to them
+func (c *MeasurementController) SetRemoteCells(remoteCells RemoteCells) {
+	c.remoteCells = remoteCells
+}
+
+// Start starts measuring periodically
+func (c *MeasurementController) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	if err := c.initMeasEventAttributes(ctx); err != nil {
+		log.Warn(err)
+	}
+	c.cancel = cancel
+	go c.run(ctx)
+}
+
+// Stop stops measuring
+func (c *MeasurementController) Stop() {
+	if c.cancel != nil {
+		c.cancel()
+	}
+}
+
+func (c *MeasurementController) run(ctx context.Context) {
+	timer := time.NewTimer(c.interval)
+	defer timer.Stop()
+	for {
+		select {
+		case <-timer.C:
+			start := time.Now()
+			ueCount := c.step(ctx)
+			timer.Reset(c.ticked(ueCount, time.Since(start)))
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// step measures the cells of all connected UEs due to measure, returning the number of UEs measured
+func (c *MeasurementController) step(ctx context.Context) int {
+	now := time.Now()
+	c.purgeBlockages(now)
+	c.ageNeighbors(ctx, now)
+	ueCount := 0
+	ueList := c.ueStore.ListAllUEs(ctx)
+	c.purgeUEs(ueList)
+	for _, ue := range ueList {
+		if ue.RrcState != model.RrcConnected || ue.Cell == nil {
+			delete(c.measEvents, ue.IMSI)
+			delete(c.outOfSync, ue.IMSI)
+			delete(c.lastMeasured, ue.IMSI)
+			continue
+		}
+		if !c.measurementDue(ctx, ue, now) {
+			continue
+		}
+		ueCount++
+		if err := c.measure(ctx, ue); err != nil {
+			log.Warn(err)
+		}
+	}
+	return ueCount
+}
+
+// purgeUEs forgets the measurement state of the UEs that no longer exist
+func (c *MeasurementController) purgeUEs(ueList []*model.UE) {
+	exists := make(map[types.IMSI]bool, len(ueList))
+	for _, ue := range ueList {
+		exists[ue.IMSI] = true
+	}
+	for imsi := range c.measEvents {
+		if !exists[imsi] {
+			delete(c.measEvents, imsi)
+		}
+	}
+	for imsi := range c.outOfSync {
+		if !exists[imsi] {
+			delete(c.outOfSync, imsi)
+		}
+	}
+	for imsi := range c.lastMeasured {
+		if !exists[imsi] {
+			delete(c.lastMeasured, imsi)
+		}
+	}
+}
+
+// measure measures the serving cell and the neighbors of the UE, ordering the candidate cells by their strength and
+// keeping the strongest ones in the neighbor list of the UE
+func (c *MeasurementController) measure(ctx context.Context, ue *model.UE) error {
+	serving, err := c.cellStore.Get(ctx, ue.Cell.ECGI)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	if serving, err = c.maintainNeighbors(ctx, ue, serving, now); err != nil {
+		return err
+	}
+	indoor := c.updateIndoor(ctx, ue)
+	strength := radio.RSRP(serving, ue.Location) - c.penetrationLoss(indoor, serving) - c.blockageLoss(ctx, ue.IMSI, serving, now)
+	if c.radioLinkFailed(ue.IMSI, strength, now) {
+		return c.rlfHandler.RadioLinkFailure(ctx, ue.IMSI)
+	}
+	measGaps := strength < gapActivationThreshold || (ue.MeasGaps && strength < gapActivationThreshold+gapHysteresis)
+
+	candidates := make([]*model.UECell, 0, len(serving.Neighbors))
+	for _, ecgi := range serving.Neighbors {
+		neighbor, err := c.neighbor(ctx, ecgi)
+		if err != nil || !neighbor.InService() {
+			continue
+		}
+		interFrequency := !serving.IsIntraFrequency(neighbor)
+		if interFrequency && !measGaps {
+			continue
+		}
+		candidates = append(candidates, &model.UECell{
+			ID:             types.GEnbID(ecgi),
+			ECGI:           ecgi,
+			Strength:       radio.RSRP(neighbor, ue.Location) - c.penetrationLoss(indoor, neighbor) - c.blockageLoss(ctx, ue.IMSI, neighbor, now),
+			InterFrequency: interFrequency,
+		})
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		return candidates[i].Strength > candidates[j].Strength
+	})
+	if measGaps != ue.MeasGaps {
+		log.Debugf("UE %d measurement gaps configured=%t", ue.IMSI, measGaps)
+	}
+	configs := c.measEventConfigs(ctx, ue.IMSI, serving.ECGI)
+	reports := c.evaluateMeasEvents(ue.IMSI, &model.UECell{ID: ue.Cell.ID, ECGI: serving.ECGI, Strength: strength},
+		candidates, configs, now)
+	neighbors := selectNeighbors(ue.Cells, candidates, c.mobility.MaxNeighbors, c.mobility.NeighborHysteresis)
+	err = c.ueStore.UpdateMeasurements(ctx, ue.IMSI, serving.ECGI, strength, neighbors, measGaps, reports)
+	if errors.IsConflict(err) {
+		// The UE was handed over while being measured; it is measured in its new serving cell next time
+		log.Debug(err)
+		return nil
+	}
+	return err
+}
+
+// neighbor returns the specified neighbor cell, either simulated by this instance or by another one
+func (c *MeasurementController) neighbor(ctx context.Context, ecgi types.ECGI) (*model.Cell, error) {
+	cell, err := c.cellStore.Get(ctx, ecgi)
+	if err != nil && c.remoteCells != nil {
+		if remote, ok := c.remoteCells.RemoteCell(ecgi); ok {
+			return remote, nil
+		}
+	}
+	return cell, err
+}
+
+// radioLinkFailed returns true if the serving cell RSRP of the UE has stayed below Qout for T310
+func (c *MeasurementController) radioLinkFailed(imsi types.IMSI, strength float64, now time.Time) bool {
+	if c.rlfHandler == nil || c.rlf.Qout == 0 || strength >= c.rlf.Qout {
+		delete(c.outOfSync, imsi)
+		return false
+	}
+	since, ok := c.outOfSync[imsi]
+	if !ok {
+		c.outOfSync[imsi] = now
+		return false
+	}
+	if now.Sub(since) < c.rlf.T310 {
+		return false
+	}
+	delete(c.outOfSync, imsi)
+	return true
+}
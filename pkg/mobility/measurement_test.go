@@ -0,0 +1,186 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package mobility
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/onosproject/onos-api/go/onos/ransim/types"
+	"github.com/onosproject/ran-simulator/pkg/model"
+	"github.com/onosproject/ran-simulator/pkg/store/metrics"
+	"github.com/onosproject/ran-simulator/pkg/store/ues"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMeasurements(t *testing.T) {
+	ctx := context.Background()
+	cells := cellStore(t)
+	ueStore := ues.NewUERegistry(1, cells)
+	controller := NewMeasurementController(cells, ueStore, metrics.NewMetricsStore())
+
+	ecgi1 := types.ECGI(84325717505)
+	ecgi2 := types.ECGI(84325717506)
+	ecgi3 := types.ECGI(84325717761)
+	cell1, err := cells.Get(ctx, ecgi1)
+	assert.NoError(t, err)
+	updated := *cell1
+	updated.Neighbors = []types.ECGI{ecgi2, ecgi3}
+	assert.NoError(t, cells.Update(ctx, &updated))
+	cell3, err := cells.Get(ctx, ecgi3)
+	assert.NoError(t, err)
+	interFrequency := *cell3
+	interFrequency.Earfcn = 1850
+	assert.NoError(t, cells.Update(ctx, &interFrequency))
+
+	ue := ueStore.ListAllUEs(ctx)[0]
+	assert.NoError(t, ueStore.MoveToCell(ctx, ue.IMSI, ecgi1, 0))
+	assert.NoError(t, ueStore.MoveToCoordinate(ctx, ue.IMSI, model.Coordinate{Lat: 46.0, Lng: 29.0013}, 0))
+
+	// Idle UEs do not measure
+	controller.step(ctx)
+	assert.Empty(t, ue.Cells)
+
+	// Strong serving cell: only intra-frequency neighbors are measured
+	assert.NoError(t, ueStore.UpdateRrcState(ctx, ue.IMSI, model.RrcConnected))
+	controller.step(ctx)
+	assert.False(t, ue.MeasGaps)
+	assert.Less(t, ue.Cell.Strength, 0.0)
+	assert.Len(t, ue.Cells, 1)
+	assert.Equal(t, ecgi2, ue.Cells[0].ECGI)
+	assert.False(t, ue.Cells[0].InterFrequency)
+
+	// Weak serving cell: measurement gaps allow measuring inter-frequency neighbors
+	assert.NoError(t, ueStore.MoveToCoordinate(ctx, ue.IMSI, model.Coordinate{Lat: 46.0, Lng: 29.1}, 0))
+	controller.step(ctx)
+	assert.True(t, ue.MeasGaps)
+	assert.Len(t, ue.Cells, 2)
+	assert.True(t, ue.Cells[0].Strength >= ue.Cells[1].Strength)
+	for _, candidate := range ue.Cells {
+		assert.Equal(t, candidate.ECGI == ecgi3, candidate.InterFrequency)
+	}
+
+	// Neighbors simulated by other instances are measured as well
+	remoteECGI := types.ECGI(84325718017)
+	remote := *cell1
+	remote.ECGI = remoteECGI
+	controller.SetRemoteCells(remoteCellMap{remoteECGI: &remote})
+	updated.Neighbors = []types.ECGI{ecgi2, ecgi3, remoteECGI}
+	assert.NoError(t, cells.Update(ctx, &updated))
+	controller.step(ctx)
+	assert.Len(t, ue.Cells, 3)
+}
+
+type remoteCellMap map[types.ECGI]*model.Cell
+
+func (m remoteCellMap) RemoteCell(ecgi types.ECGI) (*model.Cell, bool) {
+	cell, ok := m[ecgi]
+	return cell, ok
+}
+
+func TestAdaptiveTicks(t *testing.T) {
+	controller := NewMeasurementController(cellStore(t), nil, metrics.NewMetricsStore())
+	assert.Equal(t, defaultTickInterval, controller.TickStats().Interval)
+
+	// Ticks missing their deadline drift unless adaptive
+	controller.SetMobility(model.MobilityConfig{TickInterval: 100 * time.Millisecond})
+	assert.Equal(t, time.Duration(0), controller.ticked(10, 150*time.Millisecond))
+	assert.Equal(t, 60*time.Millisecond, controller.ticked(10, 40*time.Millisecond))
+	stats := controller.TickStats()
+	assert.Equal(t, uint64(2), stats.Ticks)
+	assert.Equal(t, uint64(1), stats.MissedDeadlines)
+	assert.Equal(t, 10, stats.UEs)
+	assert.Equal(t, 40*time.Millisecond, stats.LastDuration)
+	assert.Equal(t, 150*time.Millisecond, stats.MaxDuration)
+	assert.Equal(t, 100*time.Millisecond, stats.Interval)
+
+	// Adaptive ticks lengthen the interval up to its maximum and shorten it again once keeping up
+	controller.SetMobility(model.MobilityConfig{TickInterval: 100 * time.Millisecond, MaxTickInterval: 300 * time.Millisecond, Adaptive: true})
+	assert.Equal(t, 50*time.Millisecond, controller.ticked(10, 150*time.Millisecond))
+	assert.Equal(t, 200*time.Millisecond, controller.TickStats().Interval)
+	assert.Equal(t, time.Duration(0), controller.ticked(10, 400*time.Millisecond))
+	assert.Equal(t, 300*time.Millisecond, controller.TickStats().Interval)
+	assert.Equal(t, 100*time.Millisecond, controller.ticked(10, 50*time.Millisecond))
+	assert.Equal(t, 150*time.Millisecond, controller.TickStats().Interval)
+	controller.ticked(10, 10*time.Millisecond)
+	assert.Equal(t, 100*time.Millisecond, controller.TickStats().Interval)
+	assert.Equal(t, uint64(3), controller.TickStats().MissedDeadlines)
+}
+
+func TestNeighborRelations(t *testing.T) {
+	ctx := context.Background()
+	cells := cellStore(t)
+	ueStore := ues.NewUERegistry(1, cells)
+	metricStore := metrics.NewMetricsStore()
+	controller := NewMeasurementController(cells, ueStore, metricStore)
+	controller.SetMobility(model.MobilityConfig{NeighborRelations: 1})
+
+	ecgi1 := types.ECGI(84325717505)
+	ecgi2 := types.ECGI(84325717506)
+	cell1, err := cells.Get(ctx, ecgi1)
+	assert.NoError(t, err)
+	updated := *cell1
+	updated.Neighbors = nil
+	assert.NoError(t, cells.Update(ctx, &updated))
+
+	ue := ueStore.ListAllUEs(ctx)[0]
+	assert.NoError(t, ueStore.MoveToCell(ctx, ue.IMSI, ecgi1, 0))
+	assert.NoError(t, ueStore.MoveToCoordinate(ctx, ue.IMSI, model.Coordinate{Lat: 46.0, Lng: 29.0013}, 0))
+	assert.NoError(t, ueStore.UpdateRrcState(ctx, ue.IMSI, model.RrcConnected))
+
+	// The strongest other cell at the location of the UE becomes a neighbor of its serving cell and is measured
+	for i := 0; i < 2; i++ {
+		controller.step(ctx)
+		cell1, err = cells.Get(ctx, ecgi1)
+		assert.NoError(t, err)
+		assert.Equal(t, []types.ECGI{ecgi2}, cell1.Neighbors)
+	}
+	assert.Len(t, ue.Cells, 1)
+	assert.Equal(t, ecgi2, ue.Cells[0].ECGI)
+	count, _ := metricStore.Get(ctx, uint64(ecgi1), NeighborRelationsAdded)
+	assert.Equal(t, uint64(1), count)
+	_, ok := metricStore.Get(ctx, uint64(ecgi1), HandoverOffsetAttribute(ecgi2))
+	assert.True(t, ok)
+
+	// The neighbor is removed again once no UE detected it for the timeout
+	controller.ageNeighbors(ctx, time.Now().Add(defaultNeighborRelationTimeout/2))
+	cell1, err = cells.Get(ctx, ecgi1)
+	assert.NoError(t, err)
+	assert.Equal(t, []types.ECGI{ecgi2}, cell1.Neighbors)
+	controller.ageNeighbors(ctx, time.Now().Add(defaultNeighborRelationTimeout))
+	cell1, err = cells.Get(ctx, ecgi1)
+	assert.NoError(t, err)
+	assert.Empty(t, cell1.Neighbors)
+	count, _ = metricStore.Get(ctx, uint64(ecgi1), NeighborRelationsRemoved)
+	assert.Equal(t, uint64(1), count)
+}
+
+func TestSelectNeighbors(t *testing.T) {
+	cell := func(ecgi types.ECGI, strength float64) *model.UECell {
+		return &model.UECell{ECGI: ecgi, Strength: strength}
+	}
+	ecgis := func(cells []*model.UECell) []types.ECGI {
+		list := make([]types.ECGI, 0, len(cells))
+		for _, c := range cells {
+			list = append(list, c.ECGI)
+		}
+		return list
+	}
+
+	// All measured cells are kept unless limited
+	measured := []*model.UECell{cell(1, -80), cell(2, -85), cell(3, -90)}
+	assert.Equal(t, []types.ECGI{1, 2, 3}, ecgis(selectNeighbors(nil, measured, 0, 3)))
+	listed := selectNeighbors(nil, measured, 2, 3)
+	assert.Equal(t, []types.ECGI{1, 2}, ecgis(listed))
+
+	// A listed cell is only replaced by a cell exceeding it by more than the hysteresis
+	measured = []*model.UECell{cell(3, -83), cell(1, -84), cell(2, -85)}
+	listed = selectNeighbors(listed, measured, 2, 3)
+	assert.Equal(t, []types.ECGI{1, 2}, ecgis(listed))
+	measured = []*model.UECell{cell(3, -78), cell(1, -84), cell(2, -85)}
+	listed = selectNeighbors(listed, measured, 2, 3)
+	assert.Equal(t, []types.ECGI{3, 1}, ecgis(listed))
+}
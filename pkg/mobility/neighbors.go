@@ -0,0 +1,42 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package mobility
+
+import (
+	"sort"
+
+	"github.com/onosproject/onos-api/go/onos/ransim/types"
+	"github.com/onosproject/ran-simulator/pkg/model"
+)
+
+// selectNeighbors returns the candidate cells kept in the neighbor list of the UE, strongest first, given the
+// measured cells ordered by strength and the cells listed so far. Unless the number of neighbors is unlimited, the
+// strongest cells are kept, the cells listed so far being favored by the hysteresis so that cells of about the same
+// strength do not keep replacing each other in the list.
+func selectNeighbors(listed []*model.UECell, measured []*model.UECell, maxNeighbors int, hysteresis float64) []*model.UECell {
+	if maxNeighbors <= 0 || len(measured) <= maxNeighbors {
+		return measured
+	}
+	wasListed := make(map[types.ECGI]bool, len(listed))
+	for _, cell := range listed {
+		wasListed[cell.ECGI] = true
+	}
+	score := func(cell *model.UECell) float64 {
+		if wasListed[cell.ECGI] {
+			return cell.Strength + hysteresis
+		}
+		return cell.Strength
+	}
+	ranked := make([]*model.UECell, len(measured))
+	copy(ranked, measured)
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return score(ranked[i]) > score(ranked[j])
+	})
+	kept := ranked[:maxNeighbors]
+	sort.SliceStable(kept, func(i, j int) bool {
+		return kept[i].Strength > kept[j].Strength
+	})
+	return kept
+}
@@ -0,0 +1,59 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package mobility
+
+import (
+	"context"
+	"time"
+
+	"github.com/onosproject/ran-simulator/pkg/model"
+)
+
+// Per-cell paging counters maintained in the metrics store and reported via KPM
+const (
+	// PagingAtt number of paging records broadcast by the cell
+	PagingAtt = "PAG.Att.Tot"
+	// PagingSucc number of paged UEs which responded via the cell
+	PagingSucc = "PAG.Succ.Tot"
+)
+
+// page pages the idle UE across the cells of its registration area and returns true if the UE responded, i.e. if its
+// serving cell is in service and its battery is not depleted; the paging attempt is counted by every cell of the area
+// and the success by the serving cell. A UE with a DRX cycle responds later, at its next paging occasion.
+func (c *RrcController) page(ctx context.Context, ue *model.UE, now time.Time) bool {
+	cellList, err := c.cellStore.List(ctx)
+	if err != nil {
+		log.Warn(err)
+		return false
+	}
+	reachable := !depleted(ctx, c.metricStore, ue.IMSI)
+	inService := false
+	for _, cell := range cellList {
+		if !cell.InService() || !inRegistrationArea(ue, cell.TAC) {
+			continue
+		}
+		c.increment(ctx, uint64(cell.ECGI), PagingAtt)
+		if cell.ECGI == ue.Cell.ECGI {
+			inService = true
+		}
+	}
+	if !reachable || !inService {
+		log.Debugf("Paging of UE %d failed", ue.IMSI)
+		return false
+	}
+	if delay := c.pagingDelay(ctx, ue); delay > 0 {
+		log.Debugf("UE %d to respond to paging in %s", ue.IMSI, delay)
+		c.ueActivity(ue, now).pagingResponse = now.Add(delay)
+		return false
+	}
+	c.respond(ctx, ue)
+	return true
+}
+
+// respond counts the response of the paged UE via its serving cell
+func (c *RrcController) respond(ctx context.Context, ue *model.UE) {
+	log.Debugf("UE %d responded to paging via cell %d", ue.IMSI, ue.Cell.ECGI)
+	c.increment(ctx, uint64(ue.Cell.ECGI), PagingSucc)
+}
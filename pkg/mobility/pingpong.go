@@ -0,0 +1,113 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package mobility
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/onosproject/onos-api/go/onos/ransim/types"
+	"github.com/onosproject/ran-simulator/pkg/journal"
+	"github.com/onosproject/ran-simulator/pkg/model"
+)
+
+// PingPongs per-cell counter of UEs handed back to the cell within the ping-pong window of their handover from it
+const PingPongs = "HO.PingPong.Tot"
+
+const (
+	defaultPingPongWindow = 5 * time.Second
+	// handoverHistoryLength number of handovers kept per UE
+	handoverHistoryLength = 8
+)
+
+// PingPongMetric returns the name of the per-cell counter of ping-pongs between the cell and the given neighbor,
+// e.g. HO.PingPong.84325717506
+func PingPongMetric(neighbor types.ECGI) string {
+	return fmt.Sprintf("HO.PingPong.%d", neighbor)
+}
+
+// HandoverOffsetAttribute returns the name of the cell attribute holding the offset in dB added to the strength of
+// the given neighbor when selecting handover targets for UEs served by the cell, e.g. ho.offset.84325717506; the
+// offsets can be set via RC control or the metrics API
+func HandoverOffsetAttribute(neighbor types.ECGI) string {
+	return fmt.Sprintf("ho.offset.%d", neighbor)
+}
+
+// SetHandoverConfig sets the configuration of the handover engine; unset times are replaced by defaults
+func (h *HandoverEngine) SetHandoverConfig(config model.HandoverConfig) {
+	if config.PingPongWindow == 0 {
+		config.PingPongWindow = defaultPingPongWindow
+	}
+	if config.Xn.TRelocPrep == 0 {
+		config.Xn.TRelocPrep = defaultTRelocPrep
+	}
+	if config.Xn.TRelocOverall == 0 {
+		config.Xn.TRelocOverall = defaultTRelocOverall
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.config = config
+}
+
+// recordHandover adds the handover to the history of the UE; successful handovers back to the cell the UE was
+// handed over from within the ping-pong window are counted as ping-pongs by that cell and labeled as such
+func (h *HandoverEngine) recordHandover(ctx context.Context, imsi types.IMSI, source types.ECGI, target types.ECGI, failed bool) {
+	now := time.Now()
+	h.mu.Lock()
+	h.purgeHandovers(now)
+	history := h.handovers[imsi]
+	var pingPong *handoverRecord
+	for i := len(history) - 1; i >= 0 && !failed; i-- {
+		if history[i].failed {
+			continue
+		}
+		if history[i].source == target && history[i].target == source && now.Sub(history[i].time) <= h.config.PingPongWindow {
+			pingPong = &history[i]
+		}
+		break
+	}
+	history = append(history, handoverRecord{source: source, target: target, failed: failed, time: now})
+	if len(history) > handoverHistoryLength {
+		history = history[len(history)-handoverHistoryLength:]
+	}
+	h.handovers[imsi] = history
+	h.mu.Unlock()
+
+	if pingPong != nil {
+		log.Debugf("UE %d ping-ponged between cells %d and %d", imsi, target, source)
+		h.count(ctx, target, PingPongs)
+		h.count(ctx, target, PingPongMetric(source))
+		journal.DefaultLabels().Add(journal.PingPongLabel, pingPong.time, now, []uint64{uint64(imsi), uint64(source), uint64(target)}, nil)
+	}
+}
+
+// purgeHandovers forgets the handover history of the UEs not handed over within the ping-pong and the too early
+// windows, e.g. of UEs deleted since, at most once per window; the history is guarded by the engine mutex
+func (h *HandoverEngine) purgeHandovers(now time.Time) {
+	window := h.config.PingPongWindow
+	if h.rlf.TooEarlyWindow > window {
+		window = h.rlf.TooEarlyWindow
+	}
+	if now.Sub(h.purged) < window {
+		return
+	}
+	h.purged = now
+	for imsi, history := range h.handovers {
+		if now.Sub(history[len(history)-1].time) > window {
+			delete(h.handovers, imsi)
+		}
+	}
+}
+
+// cellPairOffset returns the offset in dB configured for handovers from the serving cell to the candidate cell
+func (h *HandoverEngine) cellPairOffset(ctx context.Context, serving types.ECGI, candidate types.ECGI) float64 {
+	value, ok := h.metricStore.Get(ctx, uint64(serving), HandoverOffsetAttribute(candidate))
+	if !ok {
+		return 0
+	}
+	offset, _ := toFloat(value)
+	return offset
+}
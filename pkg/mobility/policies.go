@@ -0,0 +1,53 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package mobility
+
+import (
+	"github.com/onosproject/onos-api/go/onos/ransim/types"
+)
+
+// Preference is a preference of a UE for a cell as expressed by a traffic steering policy; of conflicting
+// preferences, the greater one wins
+type Preference int
+
+const (
+	// NoPreference the cell is neither preferred nor avoided
+	NoPreference Preference = iota
+	// Prefer the cell is preferred over the others
+	Prefer
+	// Avoid the cell is used only if no other cell is available
+	Avoid
+	// Shall the cell must be used if available
+	Shall
+	// Forbid the cell must not be used
+	Forbid
+)
+
+func (p Preference) String() string {
+	return [...]string{"NONE", "PREFER", "AVOID", "SHALL", "FORBID"}[p]
+}
+
+// strength bonus of preferred and penalty of avoided cells when selecting the handover target
+const preferenceOffset = 20.0
+
+// Policies provides the externally supplied policies consulted when selecting handover targets
+type Policies interface {
+	// Preference returns the preference of the specified UE served by the specified cell for the specified cell
+	Preference(imsi types.IMSI, serving types.ECGI, ecgi types.ECGI) Preference
+
+	// MaxLoad returns the target maximum load of the specified cell in range [0, 1]
+	MaxLoad(ecgi types.ECGI) float64
+}
+
+// noPolicies is used when no policies are supplied
+type noPolicies struct{}
+
+func (noPolicies) Preference(imsi types.IMSI, serving types.ECGI, ecgi types.ECGI) Preference {
+	return NoPreference
+}
+
+func (noPolicies) MaxLoad(ecgi types.ECGI) float64 {
+	return 1
+}
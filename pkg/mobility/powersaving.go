@@ -0,0 +1,134 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package mobility
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"time"
+
+	"github.com/onosproject/onos-api/go/onos/ransim/types"
+	"github.com/onosproject/ran-simulator/pkg/model"
+	"github.com/onosproject/ran-simulator/pkg/store/metrics"
+)
+
+// BatteryLevel is the UE metric holding the remaining charge of the battery of UEs with battery in percent
+const BatteryLevel = "battery"
+
+const (
+	// lowBatteryLevel battery level in percent below which UEs lengthen their DRX cycles to save power
+	lowBatteryLevel = 20.0
+	// lowBatteryFactor factor the DRX cycles of UEs are lengthened by on low battery
+	lowBatteryFactor = 4
+)
+
+// batteryLevel returns the battery level of the UE in percent, if it has a battery
+func batteryLevel(ctx context.Context, metricStore metrics.Store, imsi types.IMSI) (float64, bool) {
+	value, ok := metricStore.Get(ctx, uint64(imsi), BatteryLevel)
+	if !ok {
+		return 0, false
+	}
+	level, ok := value.(float64)
+	return level, ok
+}
+
+// depleted returns true if the battery of the UE is depleted, leaving the UE unreachable
+func depleted(ctx context.Context, metricStore metrics.Store, imsi types.IMSI) bool {
+	level, ok := batteryLevel(ctx, metricStore, imsi)
+	return ok && level <= 0
+}
+
+// drxCycle returns the given DRX cycle of the UE, lengthened while its battery is low
+func drxCycle(ctx context.Context, metricStore metrics.Store, imsi types.IMSI, cycle time.Duration) time.Duration {
+	if cycle <= 0 {
+		return 0
+	}
+	if level, ok := batteryLevel(ctx, metricStore, imsi); ok && level < lowBatteryLevel {
+		return lowBatteryFactor * cycle
+	}
+	return cycle
+}
+
+// drainBattery drains the battery of the UE by the current drawn in its RRC state over the given time and publishes
+// its level whenever it drops below the next integer percentage; a UE whose battery is depleted is released
+func (c *RrcController) drainBattery(ctx context.Context, ue *model.UE, elapsed time.Duration) {
+	saving := c.config.Profiles[ue.Type].PowerSaving
+	if saving.BatteryCapacity <= 0 {
+		return
+	}
+	previous, ok := c.battery[ue.IMSI]
+	if !ok {
+		previous = saving.BatteryCapacity
+	}
+	current := saving.IdleCurrent
+	if ue.RrcState == model.RrcConnected {
+		current = saving.ConnectedCurrent
+	}
+	charge := math.Max(0, previous-current*elapsed.Hours())
+	c.battery[ue.IMSI] = charge
+	level := 100 * charge / saving.BatteryCapacity
+	if ok && math.Ceil(level) == math.Ceil(100*previous/saving.BatteryCapacity) {
+		return
+	}
+	c.setMetric(ctx, uint64(ue.IMSI), BatteryLevel, level)
+	if charge == 0 && ue.RrcState != model.RrcIdle {
+		log.Infof("Battery of UE %d depleted", ue.IMSI)
+		c.setState(ctx, ue, model.RrcIdle)
+		c.release(ctx, ue)
+	}
+}
+
+// pagingDelay returns the time until the idle UE responds to paging at its next paging occasion, drawn uniformly
+// within its DRX cycle
+func (c *RrcController) pagingDelay(ctx context.Context, ue *model.UE) time.Duration {
+	cycle := drxCycle(ctx, c.metricStore, ue.IMSI, c.config.Profiles[ue.Type].PowerSaving.DRXCycle)
+	return time.Duration(rand.Float64() * float64(cycle))
+}
+
+// pagingPending returns true if the UE was paged and has yet to respond at its next paging occasion
+func (c *RrcController) pagingPending(imsi types.IMSI) bool {
+	activity, ok := c.activity[imsi]
+	return ok && !activity.pagingResponse.IsZero()
+}
+
+// respondToPaging connects the UE responding to paging at its paging occasion, provided it is still reachable, and
+// keeps it active at least for the inactivity timer
+func (c *RrcController) respondToPaging(ctx context.Context, ue *model.UE, activity *ueActivity, now time.Time) {
+	activity.pagingResponse = time.Time{}
+	if ue.RrcState != model.RrcIdle || ue.Cell == nil || depleted(ctx, c.metricStore, ue.IMSI) {
+		return
+	}
+	c.respond(ctx, ue)
+	c.connect(ctx, ue, CauseMtAccess)
+	if now.After(activity.activeUntil) {
+		activity.activeUntil = now
+	}
+}
+
+// SetPowerSaving sets the activity profiles by UE type, whose connected DRX cycle sets the interval between the
+// measurements of connected UEs
+func (c *MeasurementController) SetPowerSaving(profiles map[model.UEType]model.ActivityProfile) {
+	cycles := make(map[model.UEType]time.Duration)
+	for ueType, profile := range profiles {
+		if profile.PowerSaving.ConnectedDRXCycle > 0 {
+			cycles[ueType] = profile.PowerSaving.ConnectedDRXCycle
+		}
+	}
+	c.drxCycles = cycles
+}
+
+// measurementDue returns true if the connected UE is due to measure its cells, i.e. once per connected DRX cycle
+func (c *MeasurementController) measurementDue(ctx context.Context, ue *model.UE, now time.Time) bool {
+	cycle := drxCycle(ctx, c.metricStore, ue.IMSI, c.drxCycles[ue.Type])
+	if cycle <= 0 {
+		return true
+	}
+	if last, ok := c.lastMeasured[ue.IMSI]; ok && now.Sub(last) < cycle {
+		return false
+	}
+	c.lastMeasured[ue.IMSI] = now
+	return true
+}
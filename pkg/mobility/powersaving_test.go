@@ -0,0 +1,132 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package mobility
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/onosproject/onos-api/go/onos/ransim/types"
+	"github.com/onosproject/ran-simulator/pkg/model"
+	"github.com/onosproject/ran-simulator/pkg/store/metrics"
+	"github.com/onosproject/ran-simulator/pkg/store/ues"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBattery(t *testing.T) {
+	ctx := context.Background()
+	cells := cellStore(t)
+	ueStore := ues.NewUERegistry(1, cells)
+	metricStore := metrics.NewMetricsStore()
+	controller := NewRrcController(cells, ueStore, metricStore, model.RrcConfig{
+		Profiles: map[model.UEType]model.ActivityProfile{
+			"phone": {MeanInterval: 1000 * time.Hour, PowerSaving: model.PowerSavingProfile{
+				BatteryCapacity: 1, ConnectedCurrent: 100, IdleCurrent: 0.001}},
+		},
+	})
+	ue := ueStore.ListAllUEs(ctx)[0]
+	level := func() float64 {
+		level, ok := batteryLevel(ctx, metricStore, ue.IMSI)
+		assert.True(t, ok)
+		return level
+	}
+
+	// UEs start with a full battery, drained faster while connected
+	now := time.Now()
+	controller.step(ctx, now)
+	assert.Equal(t, 100.0, level())
+	assert.NoError(t, controller.Connect(ctx, ue.IMSI, CauseMoData, now.Add(time.Hour)))
+	controller.step(ctx, now.Add(30*time.Second))
+	assert.InDelta(t, 100.0/6, level(), 0.01)
+
+	// DRX cycles are lengthened on low battery
+	assert.Equal(t, lowBatteryFactor*time.Second, drxCycle(ctx, metricStore, ue.IMSI, time.Second))
+
+	// UEs whose battery is depleted are released and can no longer be reached
+	controller.step(ctx, now.Add(time.Minute))
+	assert.Equal(t, 0.0, level())
+	assert.Equal(t, model.RrcIdle, ue.RrcState)
+	assert.Error(t, controller.Connect(ctx, ue.IMSI, CauseMoData, now))
+	assert.Error(t, controller.Page(ctx, ue.IMSI))
+
+	// Deleted UEs are no longer tracked
+	_, err := ueStore.Delete(ctx, ue.IMSI)
+	assert.NoError(t, err)
+	controller.step(ctx, now.Add(2*time.Minute))
+	assert.Empty(t, controller.battery)
+	_, ok := metricStore.Get(ctx, uint64(ue.IMSI), BatteryLevel)
+	assert.False(t, ok)
+}
+
+func TestPagingDelay(t *testing.T) {
+	ctx := context.Background()
+	cells := cellStore(t)
+	ueStore := ues.NewUERegistry(1, cells)
+	metricStore := metrics.NewMetricsStore()
+	controller := NewRrcController(cells, ueStore, metricStore, model.RrcConfig{
+		Profiles: map[model.UEType]model.ActivityProfile{
+			"phone": {MeanInterval: 1000 * time.Hour, PowerSaving: model.PowerSavingProfile{DRXCycle: 10 * time.Second}},
+		},
+	})
+	ue := ueStore.ListAllUEs(ctx)[0]
+	now := time.Now()
+	controller.step(ctx, now)
+	ecgi := uint64(ue.Cell.ECGI)
+
+	// Paged UEs respond at their next paging occasion
+	assert.NoError(t, controller.Page(ctx, ue.IMSI))
+	assert.Equal(t, model.RrcIdle, ue.RrcState)
+	assert.True(t, controller.pagingPending(ue.IMSI))
+	attempts, _ := metricStore.Get(ctx, ecgi, PagingAtt)
+	assert.Equal(t, uint64(1), attempts)
+	_, ok := metricStore.Get(ctx, ecgi, PagingSucc)
+	assert.False(t, ok)
+
+	// Mobile terminated services fail until then, unlike mobile originated ones
+	assert.Error(t, controller.Connect(ctx, ue.IMSI, CauseMtAccess, now.Add(time.Minute)))
+	attempts, _ = metricStore.Get(ctx, ecgi, PagingAtt)
+	assert.Equal(t, uint64(1), attempts)
+
+	controller.step(ctx, now.Add(11*time.Second))
+	assert.Equal(t, model.RrcConnected, ue.RrcState)
+	assert.False(t, controller.pagingPending(ue.IMSI))
+	successes, ok := metricStore.Get(ctx, ecgi, PagingSucc)
+	assert.True(t, ok)
+	assert.Equal(t, uint64(1), successes)
+	assert.Equal(t, now.Add(time.Minute), controller.activity[ue.IMSI].activeUntil)
+}
+
+func TestMeasurementCadence(t *testing.T) {
+	ctx := context.Background()
+	cells := cellStore(t)
+	ueStore := ues.NewUERegistry(1, cells)
+	metricStore := metrics.NewMetricsStore()
+	controller := NewMeasurementController(cells, ueStore, metricStore)
+	controller.SetPowerSaving(map[model.UEType]model.ActivityProfile{
+		"phone": {PowerSaving: model.PowerSavingProfile{ConnectedDRXCycle: 10 * time.Second}},
+	})
+	ue := ueStore.ListAllUEs(ctx)[0]
+	assert.NoError(t, ueStore.MoveToCell(ctx, ue.IMSI, types.ECGI(84325717505), 0))
+	assert.NoError(t, ueStore.UpdateRrcState(ctx, ue.IMSI, model.RrcConnected))
+
+	// Connected UEs measure once per connected DRX cycle
+	assert.Equal(t, 1, controller.step(ctx))
+	assert.Equal(t, 0, controller.step(ctx))
+	now := time.Now()
+	controller.lastMeasured[ue.IMSI] = now
+	assert.False(t, controller.measurementDue(ctx, ue, now.Add(5*time.Second)))
+	assert.True(t, controller.measurementDue(ctx, ue, now.Add(10*time.Second)))
+
+	// and less often on low battery
+	assert.NoError(t, metricStore.Set(ctx, uint64(ue.IMSI), BatteryLevel, 10.0))
+	assert.False(t, controller.measurementDue(ctx, ue, now.Add(30*time.Second)))
+	assert.True(t, controller.measurementDue(ctx, ue, now.Add(50*time.Second)))
+
+	// Idle UEs are no longer tracked
+	assert.NoError(t, ueStore.UpdateRrcState(ctx, ue.IMSI, model.RrcIdle))
+	controller.step(ctx)
+	assert.Empty(t, controller.lastMeasured)
+}
@@ -0,0 +1,167 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package mobility
+
+import (
+	"context"
+	"math/rand"
+	"time"
+
+	"github.com/onosproject/onos-api/go/onos/ransim/types"
+	"github.com/onosproject/ran-simulator/pkg/journal"
+	"github.com/onosproject/ran-simulator/pkg/model"
+)
+
+const (
+	// HandoverFailures per-cell counter of handovers from the cell that failed
+	HandoverFailures = "HO.Fail.Tot"
+	// RadioLinkFailures per-cell counter of radio link failures of UEs served by the cell
+	RadioLinkFailures = "RLF.Tot"
+	// ReestablishmentAttempts per-cell counter of RRC connection re-establishments of UEs at the cell
+	ReestablishmentAttempts = "RRC.ConnReEstabAtt.Tot"
+	// ReestablishmentAttemptsHOFail per-cell counter of re-establishments at the cell due to handover failures
+	ReestablishmentAttemptsHOFail = "RRC.ConnReEstabAtt.HOFail"
+	// ReestablishmentAttemptsOther per-cell counter of re-establishments at the cell due to other radio link failures
+	ReestablishmentAttemptsOther = "RRC.ConnReEstabAtt.Other"
+	// TooLateHandovers per-cell counter of radio link failures that a handover from the cell should have prevented
+	TooLateHandovers = "HO.TooLate.Tot"
+	// TooEarlyHandovers per-cell counter of handovers from the cell followed by re-establishment at the cell
+	TooEarlyHandovers = "HO.TooEarly.Tot"
+	// WrongCellHandovers per-cell counter of handovers from the cell followed by re-establishment at a third cell
+	WrongCellHandovers = "HO.WrongCell.Tot"
+)
+
+const (
+	defaultT310           = time.Second
+	defaultTooEarlyWindow = 5 * time.Second
+)
+
+// handoverRecord is a handover of a UE, kept to attribute subsequent radio link failures and ping-pongs to it
+type handoverRecord struct {
+	source types.ECGI
+	target types.ECGI
+	failed bool
+	time   time.Time
+}
+
+// SetRlfConfig sets the configuration of handover and radio link failures; unset times are replaced by defaults
+func (h *HandoverEngine) SetRlfConfig(config model.RlfConfig) {
+	if config.T310 == 0 {
+		config.T310 = defaultT310
+	}
+	if config.TooEarlyWindow == 0 {
+		config.TooEarlyWindow = defaultTooEarlyWindow
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.rlf = config
+}
+
+func (h *HandoverEngine) rlfConfig() model.RlfConfig {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.rlf
+}
+
+// handoverFails returns true if the handover to the target cell fails, either as the target cell is too weak to be
+// in sync with or by chance
+func (h *HandoverEngine) handoverFails(target *model.UECell) bool {
+	config := h.rlfConfig()
+	if config.Qout != 0 && target.Strength != 0 && target.Strength < config.Qout {
+		return true
+	}
+	return config.HandoverFailureProbability > 0 && rand.Float64() < config.HandoverFailureProbability
+}
+
+// recentHandover returns the last handover of the UE if it took place within the too early window
+func (h *HandoverEngine) recentHandover(imsi types.IMSI) (handoverRecord, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	history := h.handovers[imsi]
+	if len(history) == 0 || time.Since(history[len(history)-1].time) > h.rlf.TooEarlyWindow {
+		return handoverRecord{}, false
+	}
+	return history[len(history)-1], true
+}
+
+// failHandover counts and records the failed handover of the UE to the target cell, upon which the UE re-establishes
+// its connection
+func (h *HandoverEngine) failHandover(ctx context.Context, ue *model.UE, target *model.UECell) error {
+	log.Infof("Handover of UE %d from cell %d to cell %d failed", ue.IMSI, ue.Cell.ECGI, target.ECGI)
+	h.count(ctx, ue.Cell.ECGI, HandoverFailures)
+	journal.Record(journal.HandoverFailed, uint64(ue.IMSI), map[string]interface{}{"source": ue.Cell.ECGI, "target": target.ECGI})
+	h.recordHandover(ctx, ue.IMSI, ue.Cell.ECGI, target.ECGI, true)
+	return h.reestablish(ctx, ue, ReestablishmentAttemptsHOFail)
+}
+
+// RadioLinkFailure handles the failure of the radio link of the UE to its serving cell, upon which the UE
+// re-establishes its connection at the strongest suitable cell or, if there is none, falls back to the idle state
+func (h *HandoverEngine) RadioLinkFailure(ctx context.Context, imsi types.IMSI) error {
+	ue, err := h.ueStore.Get(ctx, imsi)
+	if err != nil {
+		return err
+	}
+	if ue.Cell == nil {
+		return nil
+	}
+	log.Infof("Radio link of UE %d to cell %d failed", imsi, ue.Cell.ECGI)
+	h.count(ctx, ue.Cell.ECGI, RadioLinkFailures)
+	journal.Record(journal.RadioLinkFailed, uint64(imsi), map[string]interface{}{"ecgi": ue.Cell.ECGI})
+	return h.reestablish(ctx, ue, ReestablishmentAttemptsOther)
+}
+
+// reestablish re-establishes the connection of the UE at the strongest suitable cell, attributing the failure to
+// a too late, too early or wrong cell handover as far as possible
+func (h *HandoverEngine) reestablish(ctx context.Context, ue *model.UE, cause string) error {
+	failed := ue.Cell.ECGI
+	target := h.reestablishmentCell(ctx, ue)
+	if target == nil {
+		log.Infof("UE %d found no suitable cell to re-establish its connection", ue.IMSI)
+		return h.ueStore.UpdateRrcState(ctx, ue.IMSI, model.RrcIdle)
+	}
+
+	if record, ok := h.recentHandover(ue.IMSI); ok && (record.target == failed || record.failed && record.source == failed) {
+		switch target.ECGI {
+		case record.source:
+			h.count(ctx, record.source, TooEarlyHandovers)
+		case record.target:
+			h.count(ctx, record.source, TooLateHandovers)
+		default:
+			h.count(ctx, record.source, WrongCellHandovers)
+		}
+	} else if target.ECGI != failed {
+		h.count(ctx, failed, TooLateHandovers)
+	}
+
+	h.count(ctx, target.ECGI, ReestablishmentAttempts)
+	h.count(ctx, target.ECGI, cause)
+	journal.Record(journal.ConnectionReestablished, uint64(ue.IMSI), map[string]interface{}{"ecgi": target.ECGI, "cause": cause})
+	return h.ueStore.MoveToCell(ctx, ue.IMSI, target.ECGI, target.Strength)
+}
+
+// reestablishmentCell returns the strongest of the serving and the candidate cells of the UE that is simulated by
+// this instance, available, admits the UE and is in sync with it
+func (h *HandoverEngine) reestablishmentCell(ctx context.Context, ue *model.UE) *model.UECell {
+	qout := h.rlfConfig().Qout
+	var best *model.UECell
+	for _, candidate := range append([]*model.UECell{ue.Cell}, ue.Cells...) {
+		if h.isRemote(candidate.ECGI) || (qout != 0 && candidate.Strength < qout) {
+			continue
+		}
+		cell, err := h.cellStore.Get(ctx, candidate.ECGI)
+		if err != nil || !cell.IsAvailable() || !cell.Admits(ue.IMSI) {
+			continue
+		}
+		if best == nil || candidate.Strength > best.Strength {
+			best = candidate
+		}
+	}
+	return best
+}
+
+// count increments the specified per-cell counter
+func (h *HandoverEngine) count(ctx context.Context, ecgi types.ECGI, name string) {
+	_, _ = h.metricStore.Add(ctx, uint64(ecgi), name, 1)
+}
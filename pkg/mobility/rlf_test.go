@@ -0,0 +1,112 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package mobility
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/onosproject/onos-api/go/onos/ransim/types"
+	"github.com/onosproject/ran-simulator/pkg/model"
+	"github.com/onosproject/ran-simulator/pkg/store/metrics"
+	"github.com/onosproject/ran-simulator/pkg/store/ues"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRadioLinkFailures(t *testing.T) {
+	ctx := context.Background()
+	cellStore := cellStore(t)
+	ueStore := ues.NewUERegistry(1, cellStore)
+	metricStore := metrics.NewMetricsStore()
+	handover := NewHandoverEngine(cellStore, ueStore, metricStore)
+	handover.SetRlfConfig(model.RlfConfig{Qout: -110, HandoverFailureProbability: 1})
+
+	ecgi1 := types.ECGI(84325717505)
+	ecgi2 := types.ECGI(84325717506)
+	ecgi3 := types.ECGI(84325717761)
+	count := func(ecgi types.ECGI, name string) uint64 {
+		value, _ := metricStore.Get(ctx, uint64(ecgi), name)
+		count, _ := value.(uint64)
+		return count
+	}
+	ue := ueStore.ListAllUEs(ctx)[0]
+	measure := func(serving types.ECGI, strength float64, candidates ...*model.UECell) {
+		assert.NoError(t, ueStore.MoveToCell(ctx, ue.IMSI, serving, strength))
+		assert.NoError(t, ueStore.UpdateMeasurements(ctx, ue.IMSI, serving, strength, candidates, false, nil))
+	}
+
+	// A failed handover is followed by re-establishment at the strongest cell, here the source cell
+	measure(ecgi1, -80, &model.UECell{ECGI: ecgi2, Strength: -85})
+	assert.Error(t, handover.Handover(ctx, ue.IMSI, &model.UECell{ECGI: ecgi2, Strength: -85}))
+	assert.Equal(t, ecgi1, ue.Cell.ECGI)
+	assert.Equal(t, uint64(1), count(ecgi1, HandoverFailures))
+	assert.Equal(t, uint64(1), count(ecgi1, TooEarlyHandovers))
+	assert.Equal(t, uint64(1), count(ecgi1, ReestablishmentAttemptsHOFail))
+	assert.Equal(t, uint64(0), count(ecgi1, HandoversOut))
+
+	// Handovers to cells below Qout fail regardless of the failure probability
+	handover.SetRlfConfig(model.RlfConfig{Qout: -110})
+	assert.Error(t, handover.Handover(ctx, ue.IMSI, &model.UECell{ECGI: ecgi2, Strength: -115}))
+	assert.Equal(t, uint64(2), count(ecgi1, HandoverFailures))
+
+	// A radio link failure without a preceding handover re-establishing at another cell is a too late handover
+	measure(ecgi1, -115, &model.UECell{ECGI: ecgi2, Strength: -100})
+	assert.NoError(t, handover.RadioLinkFailure(ctx, ue.IMSI))
+	assert.Equal(t, ecgi2, ue.Cell.ECGI)
+	assert.Equal(t, uint64(1), count(ecgi1, RadioLinkFailures))
+	assert.Equal(t, uint64(1), count(ecgi1, TooLateHandovers))
+	assert.Equal(t, uint64(1), count(ecgi2, ReestablishmentAttempts))
+	assert.Equal(t, uint64(1), count(ecgi2, ReestablishmentAttemptsOther))
+
+	// A radio link failure shortly after a handover re-establishing at a third cell is a wrong cell handover
+	measure(ecgi1, -90)
+	assert.NoError(t, handover.Handover(ctx, ue.IMSI, &model.UECell{ECGI: ecgi2, Strength: -100}))
+	measure(ecgi2, -115, &model.UECell{ECGI: ecgi1, Strength: -120}, &model.UECell{ECGI: ecgi3, Strength: -105})
+	assert.NoError(t, handover.RadioLinkFailure(ctx, ue.IMSI))
+	assert.Equal(t, ecgi3, ue.Cell.ECGI)
+	assert.Equal(t, uint64(1), count(ecgi1, WrongCellHandovers))
+	assert.Equal(t, uint64(0), count(ecgi2, TooLateHandovers))
+
+	// Without a suitable cell, the UE falls back to the idle state
+	assert.NoError(t, ueStore.UpdateRrcState(ctx, ue.IMSI, model.RrcConnected))
+	measure(ecgi3, -115)
+	assert.NoError(t, handover.RadioLinkFailure(ctx, ue.IMSI))
+	assert.Equal(t, model.RrcIdle, ue.RrcState)
+}
+
+type testRlfHandler struct {
+	failures []types.IMSI
+}
+
+func (h *testRlfHandler) RadioLinkFailure(ctx context.Context, imsi types.IMSI) error {
+	h.failures = append(h.failures, imsi)
+	return nil
+}
+
+func TestRadioLinkMonitoring(t *testing.T) {
+	controller := NewMeasurementController(nil, nil, nil)
+	imsi := types.IMSI(1234)
+	now := time.Now()
+
+	// Radio link failures are not detected unless Qout is configured
+	handler := &testRlfHandler{}
+	controller.SetRadioLinkMonitoring(model.RlfConfig{}, handler)
+	assert.False(t, controller.radioLinkFailed(imsi, -150, now))
+	assert.False(t, controller.radioLinkFailed(imsi, -150, now.Add(time.Minute)))
+
+	// The radio link fails once it has been out of sync for T310
+	controller.SetRadioLinkMonitoring(model.RlfConfig{Qout: -110, T310: 2 * time.Second}, handler)
+	assert.False(t, controller.radioLinkFailed(imsi, -115, now))
+	assert.False(t, controller.radioLinkFailed(imsi, -115, now.Add(time.Second)))
+	assert.True(t, controller.radioLinkFailed(imsi, -115, now.Add(2*time.Second)))
+
+	// Getting back in sync restarts T310
+	assert.False(t, controller.radioLinkFailed(imsi, -115, now.Add(3*time.Second)))
+	assert.False(t, controller.radioLinkFailed(imsi, -100, now.Add(4*time.Second)))
+	assert.False(t, controller.radioLinkFailed(imsi, -115, now.Add(5*time.Second)))
+	assert.False(t, controller.radioLinkFailed(imsi, -115, now.Add(6*time.Second)))
+	assert.True(t, controller.radioLinkFailed(imsi, -115, now.Add(7*time.Second)))
+}
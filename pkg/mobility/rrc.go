@@ -0,0 +1,342 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package mobility
+
+import (
+	"context"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/onosproject/onos-api/go/onos/ransim/types"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/onosproject/ran-simulator/pkg/model"
+	"github.com/onosproject/ran-simulator/pkg/store/cells"
+	"github.com/onosproject/ran-simulator/pkg/store/metrics"
+	"github.com/onosproject/ran-simulator/pkg/store/ues"
+	"github.com/onosproject/ran-simulator/pkg/traffic"
+)
+
+// Per-cell RRC counters maintained in the metrics store and reported via KPM
+const (
+	// RrcConnEstabAtt number of RRC connection establishment attempts
+	RrcConnEstabAtt = "RRC.ConnEstabAtt.Tot"
+	// RrcConnEstabSucc number of successful RRC connection establishments
+	RrcConnEstabSucc = "RRC.ConnEstabSucc.Tot"
+)
+
+const (
+	defaultInactivityTimer = 10 * time.Second
+	defaultReleaseTimer    = 60 * time.Second
+	defaultDownlinkRatio   = 0.5
+
+	rrcUpdateInterval = time.Second
+)
+
+// ueActivity tracks the traffic activity of a UE
+type ueActivity struct {
+	// generator generates the traffic sessions of the UE
+	generator traffic.Generator
+	// active is true while a traffic session of the UE transfers data
+	active bool
+	// activeUntil is the end of the last traffic session, or of the service the UE was connected for
+	activeUntil time.Time
+	// pagingResponse is the time the paged UE responds at its next paging occasion, zero unless paged
+	pagingResponse time.Time
+}
+
+// RrcController drives the RRC state machine of UEs, i.e. IDLE, INACTIVE and CONNECTED, by the traffic
+// sessions generated per UE type and by the inactivity and release timers configured in the model
+type RrcController struct {
+	cellStore   cells.Store
+	ueStore     ues.Store
+	metricStore metrics.Store
+	config      model.RrcConfig
+	mu          sync.Mutex
+	activity    map[types.IMSI]*ueActivity
+	cancel      context.CancelFunc
+	// lastStep is the time of the last step, which the throughput of the cells is averaged since
+	lastStep time.Time
+	// throughputCells are the cells whose throughput was reported by the last step
+	throughputCells map[types.ECGI]bool
+	// residualBits holds the volume of the cells not yet counted as a whole kbit
+	residualBits map[types.ECGI]cellVolume
+	// battery holds the remaining charge of the battery of the UEs with battery in mAh
+	battery map[types.IMSI]float64
+	// tauFailures holds the cells out of service the last tracking area update of the UEs failed in
+	tauFailures map[types.IMSI]types.ECGI
+}
+
+// NewRrcController creates a new RRC controller; unset timers and profiles are replaced by defaults
+func NewRrcController(cellStore cells.Store, ueStore ues.Store, metricStore metrics.Store, config model.RrcConfig) *RrcController {
+	if config.InactivityTimer <= 0 {
+		config.InactivityTimer = defaultInactivityTimer
+	}
+	if config.ReleaseTimer <= 0 {
+		config.ReleaseTimer = defaultReleaseTimer
+	}
+	profiles := make(map[model.UEType]model.ActivityProfile, len(config.Profiles))
+	for ueType, profile := range config.Profiles {
+		if _, err := traffic.New(profile, time.Now()); err != nil {
+			log.Warnf("UE type %s: %v; using the %s generator", ueType, err, traffic.DefaultGenerator)
+			profile.Generator = ""
+		}
+		profiles[ueType] = profile
+	}
+	config.Profiles = profiles
+	return &RrcController{
+		cellStore:       cellStore,
+		ueStore:         ueStore,
+		metricStore:     metricStore,
+		config:          config,
+		activity:        make(map[types.IMSI]*ueActivity),
+		throughputCells: make(map[types.ECGI]bool),
+		residualBits:    make(map[types.ECGI]cellVolume),
+		battery:         make(map[types.IMSI]float64),
+		tauFailures:     make(map[types.IMSI]types.ECGI),
+	}
+}
+
+// Start starts driving the RRC state of UEs
+func (c *RrcController) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	c.cancel = cancel
+	go c.run(ctx)
+}
+
+// Stop stops driving the RRC state of UEs
+func (c *RrcController) Stop() {
+	if c.cancel != nil {
+		c.cancel()
+	}
+}
+
+func (c *RrcController) run(ctx context.Context) {
+	ticker := time.NewTicker(rrcUpdateInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case now := <-ticker.C:
+			c.step(ctx, now)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// step advances the RRC state machine of all UEs to the given time
+func (c *RrcController) step(ctx context.Context, now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var elapsed time.Duration
+	if !c.lastStep.IsZero() && now.After(c.lastStep) {
+		elapsed = now.Sub(c.lastStep)
+	}
+	present := make(map[types.IMSI]bool)
+	volumes := make(map[types.ECGI]*cellVolume)
+	for _, ue := range c.ueStore.ListAllUEs(ctx) {
+		present[ue.IMSI] = true
+		c.updateRegistration(ctx, ue)
+		c.drainBattery(ctx, ue, elapsed)
+		activity := c.ueActivity(ue, now)
+		if !activity.pagingResponse.IsZero() && !now.Before(activity.pagingResponse) {
+			c.respondToPaging(ctx, ue, activity, now)
+		}
+
+		for _, event := range activity.generator.Advance(now) {
+			switch event.Kind {
+			case traffic.SessionStarted:
+				activity.active = true
+				c.startSession(ctx, ue, now)
+			case traffic.SessionStopped:
+				activity.active = false
+				if event.Time.After(activity.activeUntil) {
+					activity.activeUntil = event.Time
+				}
+			case traffic.Volume:
+				// Only the traffic of connected UEs is transferred
+				if ue.RrcState == model.RrcConnected && ue.Cell != nil {
+					addVolume(volumes, ue.Cell.ECGI, event)
+				}
+			}
+		}
+		if activity.active {
+			continue
+		}
+
+		idle := now.Sub(activity.activeUntil)
+		switch {
+		case ue.RrcState == model.RrcConnected && idle > c.config.InactivityTimer:
+			c.setState(ctx, ue, model.RrcInactive)
+		case ue.RrcState == model.RrcInactive && idle > c.config.InactivityTimer+c.config.ReleaseTimer:
+			c.setState(ctx, ue, model.RrcIdle)
+			c.release(ctx, ue)
+		}
+	}
+	for imsi := range c.activity {
+		if !present[imsi] {
+			delete(c.activity, imsi)
+		}
+	}
+	for imsi := range c.tauFailures {
+		if !present[imsi] {
+			delete(c.tauFailures, imsi)
+		}
+	}
+	for imsi := range c.battery {
+		if !present[imsi] {
+			delete(c.battery, imsi)
+			_ = c.metricStore.Delete(ctx, uint64(imsi), BatteryLevel)
+		}
+	}
+	c.updateThroughput(ctx, volumes, now)
+}
+
+// startSession brings the UE into the connected state for a traffic session; idle UEs have to be paged first to
+// receive downlink traffic, and UEs whose battery is depleted or which have yet to respond to paging are left as is
+func (c *RrcController) startSession(ctx context.Context, ue *model.UE, now time.Time) {
+	if depleted(ctx, c.metricStore, ue.IMSI) || c.pagingPending(ue.IMSI) {
+		return
+	}
+	cause := c.profile(ue.Type).EstablishmentCause
+	if ue.RrcState == model.RrcIdle && rand.Float64() < c.profile(ue.Type).DownlinkRatio {
+		if !c.page(ctx, ue, now) {
+			return
+		}
+		cause = CauseMtAccess
+	}
+	if ue.RrcState != model.RrcConnected {
+		c.connect(ctx, ue, cause)
+	}
+}
+
+// ueActivity returns the activity of the UE, tracking it from the given time if not yet tracked
+func (c *RrcController) ueActivity(ue *model.UE, now time.Time) *ueActivity {
+	activity, ok := c.activity[ue.IMSI]
+	if !ok {
+		activity = &ueActivity{generator: c.generator(ue.Type, now), activeUntil: now}
+		c.activity[ue.IMSI] = activity
+	}
+	return activity
+}
+
+// generator creates the traffic generator of a UE of the given type
+func (c *RrcController) generator(ueType model.UEType, now time.Time) traffic.Generator {
+	generator, err := traffic.New(c.config.Profiles[ueType], now)
+	if err != nil {
+		log.Warn(err)
+		generator, _ = traffic.New(model.ActivityProfile{}, now)
+	}
+	return generator
+}
+
+// connect brings the UE into the connected state, i.e. resumes an inactive UE or establishes an RRC connection with
+// the given cause for an idle one, provided its serving cell admits it, and returns true if the UE is connected
+func (c *RrcController) connect(ctx context.Context, ue *model.UE, cause string) bool {
+	if ue.RrcState == model.RrcIdle && !c.admit(ctx, ue, cause) {
+		return false
+	}
+	return c.setState(ctx, ue, model.RrcConnected)
+}
+
+// Page pages the UE on behalf of the core, e.g. for downlink data of its PDU session; an idle UE responding to the
+// paging and an inactive UE are brought into the connected state and kept active for a traffic session. An idle UE
+// with a DRX cycle is brought into the connected state later, once it responds at its next paging occasion.
+func (c *RrcController) Page(ctx context.Context, imsi types.IMSI) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	ue, err := c.ueStore.Get(ctx, imsi)
+	if err != nil {
+		return err
+	}
+	if ue.Cell == nil {
+		return errors.New(errors.Unavailable, "UE %d is not served by any cell", imsi)
+	}
+	now := time.Now()
+	if ue.RrcState == model.RrcIdle && !c.reach(ctx, ue, now) {
+		return errors.New(errors.Unavailable, "UE %d did not respond to paging", imsi)
+	}
+	if activity, ok := c.activity[imsi]; ok {
+		activity.generator.Trigger(now)
+	}
+	if ue.RrcState != model.RrcConnected && !c.pagingPending(imsi) {
+		c.connect(ctx, ue, CauseMtAccess)
+	}
+	return nil
+}
+
+// reach pages the idle UE unless it was already paged and returns true if the UE responded or is to respond at its
+// next paging occasion
+func (c *RrcController) reach(ctx context.Context, ue *model.UE, now time.Time) bool {
+	return c.pagingPending(ue.IMSI) || c.page(ctx, ue, now) || c.pagingPending(ue.IMSI)
+}
+
+// Connect brings the UE into the connected state on behalf of a service, e.g. for a voice call, and keeps it active
+// until the given time; an idle UE establishes an RRC connection with the given cause, after being paged if the cause
+// is mobile terminated access. Services fail to connect UEs whose battery is depleted, and mobile terminated services
+// fail to connect UEs in power saving which have yet to respond to paging, which are then kept active until the
+// given time once they respond.
+func (c *RrcController) Connect(ctx context.Context, imsi types.IMSI, cause string, until time.Time) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	ue, err := c.ueStore.Get(ctx, imsi)
+	if err != nil {
+		return err
+	}
+	if ue.Cell == nil {
+		return errors.New(errors.Unavailable, "UE %d is not served by any cell", imsi)
+	}
+	if depleted(ctx, c.metricStore, imsi) {
+		return errors.New(errors.Unavailable, "battery of UE %d is depleted", imsi)
+	}
+	now := time.Now()
+	activity := c.ueActivity(ue, now)
+	if ue.RrcState == model.RrcIdle && cause == CauseMtAccess {
+		if !c.reach(ctx, ue, now) {
+			return errors.New(errors.Unavailable, "UE %d did not respond to paging", imsi)
+		}
+		if c.pagingPending(imsi) {
+			if until.After(activity.activeUntil) {
+				activity.activeUntil = until
+			}
+			return errors.New(errors.Unavailable, "UE %d has yet to respond to paging", imsi)
+		}
+	}
+	if ue.RrcState != model.RrcConnected && !c.connect(ctx, ue, cause) {
+		return errors.New(errors.Unavailable, "RRC connection of UE %d rejected", imsi)
+	}
+	if until.After(activity.activeUntil) {
+		activity.activeUntil = until
+	}
+	return nil
+}
+
+// setState updates the RRC state of the UE and returns true if it was updated; as UE stores may return snapshots of
+// UEs, the given UE is not assumed to reflect the update
+func (c *RrcController) setState(ctx context.Context, ue *model.UE, state model.RrcState) bool {
+	log.Debugf("UE %d RRC state %s -> %s", ue.IMSI, ue.RrcState, state)
+	if err := c.ueStore.UpdateRrcState(ctx, ue.IMSI, state); err != nil {
+		log.Warn(err)
+		return false
+	}
+	return true
+}
+
+// profile returns the activity profile of the given UE type; the session intervals, durations and rates are left to
+// the defaults of its traffic generator
+func (c *RrcController) profile(ueType model.UEType) model.ActivityProfile {
+	profile := c.config.Profiles[ueType]
+	if profile.DownlinkRatio <= 0 {
+		profile.DownlinkRatio = defaultDownlinkRatio
+	}
+	if !establishmentCauses[profile.EstablishmentCause] {
+		profile.EstablishmentCause = defaultEstablishmentCause
+	}
+	return profile
+}
+
+func (c *RrcController) increment(ctx context.Context, entityID uint64, name string) {
+	_, _ = c.metricStore.Add(ctx, entityID, name, 1)
+}
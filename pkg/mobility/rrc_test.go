@@ -0,0 +1,288 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package mobility
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/onosproject/ran-simulator/pkg/model"
+	"github.com/onosproject/ran-simulator/pkg/store/metrics"
+	"github.com/onosproject/ran-simulator/pkg/store/ues"
+	"github.com/onosproject/ran-simulator/pkg/traffic"
+	"github.com/stretchr/testify/assert"
+)
+
+// scriptedGenerator is a traffic generator emitting the events it is told to on the next step
+type scriptedGenerator struct {
+	events []traffic.Event
+}
+
+func (g *scriptedGenerator) Advance(now time.Time) []traffic.Event {
+	events := g.events
+	g.events = nil
+	return events
+}
+
+func (g *scriptedGenerator) Trigger(now time.Time) {
+	g.events = append(g.events, traffic.Event{Kind: traffic.SessionStarted, Time: now})
+}
+
+func (g *scriptedGenerator) Stop(now time.Time) {
+	g.events = append(g.events, traffic.Event{Kind: traffic.SessionStopped, Time: now})
+}
+
+func (g *scriptedGenerator) Transfer(now time.Time, downlinkBits float64, uplinkBits float64) {
+	g.events = append(g.events, traffic.Event{Kind: traffic.Volume, Time: now, DownlinkBits: downlinkBits, UplinkBits: uplinkBits})
+}
+
+func TestRrcController(t *testing.T) {
+	ctx := context.Background()
+	cells := cellStore(t)
+	ueStore := ues.NewUERegistry(1, cells)
+	metricStore := metrics.NewMetricsStore()
+	controller := NewRrcController(cells, ueStore, metricStore, model.RrcConfig{
+		InactivityTimer: 10 * time.Second,
+		ReleaseTimer:    time.Minute,
+		Profiles: map[model.UEType]model.ActivityProfile{
+			"phone": {MeanInterval: time.Hour, MeanDuration: time.Second, DownlinkRatio: 0.01},
+		},
+	})
+	ue := ueStore.ListAllUEs(ctx)[0]
+	assert.Equal(t, model.RrcIdle, ue.RrcState)
+
+	now := time.Now()
+	controller.step(ctx, now)
+	activity := controller.activity[ue.IMSI]
+	assert.NotNil(t, activity)
+
+	// Traffic session connects the idle UE
+	generator := &scriptedGenerator{}
+	activity.generator = generator
+	generator.Trigger(now)
+	controller.step(ctx, now)
+	assert.Equal(t, model.RrcConnected, ue.RrcState)
+	count, ok := metricStore.Get(ctx, uint64(ue.Cell.ECGI), RrcConnEstabSucc)
+	assert.True(t, ok)
+	assert.Equal(t, uint64(1), count)
+
+	// Inactivity timer suspends the UE and the release timer releases it
+	generator.Stop(now)
+	controller.step(ctx, now.Add(5*time.Second))
+	assert.Equal(t, model.RrcConnected, ue.RrcState)
+	controller.step(ctx, now.Add(11*time.Second))
+	assert.Equal(t, model.RrcInactive, ue.RrcState)
+	controller.step(ctx, now.Add(71*time.Second))
+	assert.Equal(t, model.RrcIdle, ue.RrcState)
+
+	// Deleted UEs are no longer tracked
+	_, err := ueStore.Delete(ctx, ue.IMSI)
+	assert.NoError(t, err)
+	controller.step(ctx, now)
+	assert.Empty(t, controller.activity)
+}
+
+func TestTrafficVolume(t *testing.T) {
+	ctx := context.Background()
+	cells := cellStore(t)
+	ueStore := ues.NewUERegistry(1, cells)
+	metricStore := metrics.NewMetricsStore()
+	controller := NewRrcController(cells, ueStore, metricStore, model.RrcConfig{
+		Profiles: map[model.UEType]model.ActivityProfile{
+			"phone": {Generator: "unknown", DownlinkRatio: 0.01},
+		},
+	})
+	// Unknown generators are replaced by the default one
+	assert.Equal(t, "", controller.config.Profiles["phone"].Generator)
+
+	ue := ueStore.ListAllUEs(ctx)[0]
+	now := time.Now()
+	controller.step(ctx, now)
+	generator := &scriptedGenerator{}
+	controller.activity[ue.IMSI].generator = generator
+
+	// Traffic of idle UEs is not transferred
+	generator.Transfer(now, 1000000, 1000000)
+	controller.step(ctx, now)
+	_, ok := metricStore.Get(ctx, uint64(ue.Cell.ECGI), PdcpSduVolumeDL)
+	assert.False(t, ok)
+
+	// Volumes of connected UEs are counted by their serving cell, which reports their throughput
+	generator.Trigger(now)
+	controller.step(ctx, now)
+	assert.Equal(t, model.RrcConnected, ue.RrcState)
+	generator.Transfer(now.Add(2*time.Second), 8000000, 1000000)
+	controller.step(ctx, now.Add(2*time.Second))
+	volume, ok := metricStore.Get(ctx, uint64(ue.Cell.ECGI), PdcpSduVolumeDL)
+	assert.True(t, ok)
+	assert.Equal(t, uint64(8000), volume)
+	volume, _ = metricStore.Get(ctx, uint64(ue.Cell.ECGI), PdcpSduVolumeUL)
+	assert.Equal(t, uint64(1000), volume)
+	throughput, ok := metricStore.Get(ctx, uint64(ue.Cell.ECGI), UEThpDl)
+	assert.True(t, ok)
+	assert.Equal(t, 4000.0, throughput)
+	throughput, _ = metricStore.Get(ctx, uint64(ue.Cell.ECGI), UEThpUl)
+	assert.Equal(t, 500.0, throughput)
+
+	// Volumes below a kbit are carried over until they add up to one
+	for i := 0; i < 4; i++ {
+		generator.Transfer(now.Add(2*time.Second), 400, 0)
+		controller.step(ctx, now.Add(2*time.Second))
+	}
+	volume, _ = metricStore.Get(ctx, uint64(ue.Cell.ECGI), PdcpSduVolumeDL)
+	assert.Equal(t, uint64(8001), volume)
+
+	// Throughput drops to zero without traffic
+	controller.step(ctx, now.Add(3*time.Second))
+	throughput, _ = metricStore.Get(ctx, uint64(ue.Cell.ECGI), UEThpDl)
+	assert.Equal(t, 0.0, throughput)
+	assert.Equal(t, model.RrcConnected, ue.RrcState)
+}
+
+func TestPaging(t *testing.T) {
+	ctx := context.Background()
+	cells := cellStore(t)
+	ueStore := ues.NewUERegistry(1, cells)
+	metricStore := metrics.NewMetricsStore()
+	controller := NewRrcController(cells, ueStore, metricStore, model.RrcConfig{
+		Profiles: map[model.UEType]model.ActivityProfile{
+			"phone": {MeanInterval: time.Hour, DownlinkRatio: 1},
+		},
+	})
+	ue := ueStore.ListAllUEs(ctx)[0]
+	now := time.Now()
+	controller.step(ctx, now)
+
+	// Downlink traffic for an idle UE is preceded by paging across the cells of its registration area
+	controller.activity[ue.IMSI].generator.Trigger(now)
+	controller.step(ctx, now)
+	assert.Equal(t, model.RrcConnected, ue.RrcState)
+	cellList, err := cells.List(ctx)
+	assert.NoError(t, err)
+	serving, err := cells.Get(ctx, ue.Cell.ECGI)
+	assert.NoError(t, err)
+	for _, cell := range cellList {
+		count, ok := metricStore.Get(ctx, uint64(cell.ECGI), PagingAtt)
+		assert.Equal(t, cell.TAC == serving.TAC, ok)
+		if ok {
+			assert.Equal(t, uint64(1), count)
+		}
+	}
+	count, ok := metricStore.Get(ctx, uint64(ue.Cell.ECGI), PagingSucc)
+	assert.True(t, ok)
+	assert.Equal(t, uint64(1), count)
+
+	// Paging fails while the serving cell is out of service
+	assert.NoError(t, ueStore.UpdateRrcState(ctx, ue.IMSI, model.RrcIdle))
+	locked := *serving
+	locked.Locked = true
+	assert.NoError(t, cells.Update(ctx, &locked))
+	controller.activity[ue.IMSI].generator.Trigger(now)
+	controller.step(ctx, now)
+	assert.Equal(t, model.RrcIdle, ue.RrcState)
+	count, _ = metricStore.Get(ctx, uint64(ue.Cell.ECGI), PagingSucc)
+	assert.Equal(t, uint64(1), count)
+}
+
+func TestConnect(t *testing.T) {
+	ctx := context.Background()
+	cells := cellStore(t)
+	ueStore := ues.NewUERegistry(1, cells)
+	metricStore := metrics.NewMetricsStore()
+	controller := NewRrcController(cells, ueStore, metricStore, model.RrcConfig{InactivityTimer: 10 * time.Second})
+	ue := ueStore.ListAllUEs(ctx)[0]
+	now := time.Now()
+
+	// An idle UE establishes an RRC connection with the cause of the service, e.g. a voice call
+	assert.NoError(t, controller.Connect(ctx, ue.IMSI, CauseMoVoiceCall, now.Add(time.Minute)))
+	assert.Equal(t, model.RrcConnected, ue.RrcState)
+	count, ok := metricStore.Get(ctx, uint64(ue.Cell.ECGI), perCause(RrcConnEstabSucc, CauseMoVoiceCall))
+	assert.True(t, ok)
+	assert.Equal(t, uint64(1), count)
+
+	// The UE is kept active until the end of the service despite its traffic sessions stopping
+	generator := &scriptedGenerator{}
+	controller.activity[ue.IMSI].generator = generator
+	generator.Trigger(now)
+	controller.step(ctx, now)
+	generator.Stop(now)
+	controller.step(ctx, now.Add(30*time.Second))
+	assert.Equal(t, model.RrcConnected, ue.RrcState)
+	controller.step(ctx, now.Add(71*time.Second))
+	assert.Equal(t, model.RrcInactive, ue.RrcState)
+
+	// Mobile terminated services page the UE first
+	assert.NoError(t, ueStore.UpdateRrcState(ctx, ue.IMSI, model.RrcIdle))
+	assert.NoError(t, controller.Connect(ctx, ue.IMSI, CauseMtAccess, now))
+	assert.Equal(t, model.RrcConnected, ue.RrcState)
+	count, ok = metricStore.Get(ctx, uint64(ue.Cell.ECGI), PagingSucc)
+	assert.True(t, ok)
+	assert.Equal(t, uint64(1), count)
+
+	assert.Error(t, controller.Connect(ctx, 1, CauseMoVoiceCall, now))
+}
+
+func TestTrackingAreaUpdate(t *testing.T) {
+	ctx := context.Background()
+	cells := cellStore(t)
+	ueStore := ues.NewUERegistry(1, cells)
+	metricStore := metrics.NewMetricsStore()
+	controller := NewRrcController(cells, ueStore, metricStore, model.RrcConfig{})
+	ue := ueStore.ListAllUEs(ctx)[0]
+	assert.NoError(t, ueStore.MoveToCell(ctx, ue.IMSI, 84325717505, 10))
+
+	// Initial registration does not count as a tracking area update
+	now := time.Now()
+	controller.step(ctx, now)
+	assert.Equal(t, []uint32{1}, ue.RegistrationArea)
+	_, ok := metricStore.Get(ctx, 84325717505, TauAtt)
+	assert.False(t, ok)
+
+	// Moving within the tracking area does not require an update
+	assert.NoError(t, ueStore.MoveToCell(ctx, ue.IMSI, 84325717506, 10))
+	controller.step(ctx, now)
+	_, ok = metricStore.Get(ctx, 84325717506, TauAtt)
+	assert.False(t, ok)
+
+	// Idle UE crossing the tracking area boundary performs an update
+	assert.NoError(t, ueStore.MoveToCell(ctx, ue.IMSI, 84325717761, 10))
+	controller.step(ctx, now)
+	assert.Equal(t, []uint32{2}, ue.RegistrationArea)
+	count, ok := metricStore.Get(ctx, 84325717761, TauSucc)
+	assert.True(t, ok)
+	assert.Equal(t, uint64(1), count)
+
+	// A failed update is counted once and attempted again once the cell returns to service
+	cell, err := cells.Get(ctx, 84325717505)
+	assert.NoError(t, err)
+	locked := *cell
+	locked.Locked = true
+	assert.NoError(t, cells.Update(ctx, &locked))
+	assert.NoError(t, ueStore.MoveToCell(ctx, ue.IMSI, 84325717505, 10))
+	controller.step(ctx, now)
+	controller.step(ctx, now.Add(time.Second))
+	assert.Equal(t, []uint32{2}, ue.RegistrationArea)
+	count, _ = metricStore.Get(ctx, 84325717505, TauAtt)
+	assert.Equal(t, uint64(1), count)
+	assert.NoError(t, cells.Update(ctx, cell))
+	controller.step(ctx, now.Add(2*time.Second))
+	assert.Equal(t, []uint32{1}, ue.RegistrationArea)
+	count, _ = metricStore.Get(ctx, 84325717505, TauAtt)
+	assert.Equal(t, uint64(2), count)
+	count, _ = metricStore.Get(ctx, 84325717505, TauSucc)
+	assert.Equal(t, uint64(1), count)
+	assert.NoError(t, ueStore.MoveToCell(ctx, ue.IMSI, 84325717761, 10))
+	controller.step(ctx, now.Add(3*time.Second))
+	assert.Equal(t, []uint32{2}, ue.RegistrationArea)
+
+	// Registration area of a connected UE is updated by the network
+	assert.NoError(t, ueStore.UpdateRrcState(ctx, ue.IMSI, model.RrcConnected))
+	assert.NoError(t, ueStore.MoveToCell(ctx, ue.IMSI, 84325717506, 10))
+	controller.step(ctx, now)
+	assert.Equal(t, []uint32{1}, ue.RegistrationArea)
+	_, ok = metricStore.Get(ctx, 84325717506, TauAtt)
+	assert.False(t, ok)
+}
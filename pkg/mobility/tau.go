@@ -0,0 +1,68 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package mobility
+
+import (
+	"context"
+
+	"github.com/onosproject/ran-simulator/pkg/journal"
+	"github.com/onosproject/ran-simulator/pkg/model"
+)
+
+// Per-cell tracking area update counters maintained in the metrics store and reported via KPM
+const (
+	// TauAtt number of tracking area update attempts
+	TauAtt = "TAU.Att.Tot"
+	// TauSucc number of successful tracking area updates
+	TauSucc = "TAU.Succ.Tot"
+)
+
+// updateRegistration keeps the registration area of the UE in line with the tracking area of its serving cell;
+// an idle UE entering a tracking area outside its registration area performs a tracking area update, whereas the
+// registration area of a connected or inactive UE is maintained by the network as the UE moves. An update failing in a
+// cell out of service is attempted again once the UE moves to another cell or the cell returns to service.
+func (c *RrcController) updateRegistration(ctx context.Context, ue *model.UE) {
+	if ue.Cell == nil {
+		return
+	}
+	cell, err := c.cellStore.Get(ctx, ue.Cell.ECGI)
+	if err != nil {
+		log.Warn(err)
+		return
+	}
+	if inRegistrationArea(ue, cell.TAC) {
+		return
+	}
+	if len(ue.RegistrationArea) > 0 && ue.RrcState == model.RrcIdle {
+		if failed, ok := c.tauFailures[ue.IMSI]; ok && failed == cell.ECGI && !cell.InService() {
+			return
+		}
+		log.Debugf("UE %d performs tracking area update to TAC %d", ue.IMSI, cell.TAC)
+		ecgi := uint64(cell.ECGI)
+		c.increment(ctx, ecgi, TauAtt)
+		if !cell.InService() {
+			c.tauFailures[ue.IMSI] = cell.ECGI
+			return
+		}
+		delete(c.tauFailures, ue.IMSI)
+		c.increment(ctx, ecgi, TauSucc)
+		journal.Record(journal.TrackingAreaUpdated, uint64(ue.IMSI), map[string]interface{}{
+			"ecgi": cell.ECGI, "previous": ue.RegistrationArea, "tac": cell.TAC,
+		})
+	}
+	if err := c.ueStore.UpdateRegistrationArea(ctx, ue.IMSI, []uint32{cell.TAC}); err != nil {
+		log.Warn(err)
+	}
+}
+
+// inRegistrationArea returns true if the given tracking area is part of the registration area of the UE
+func inRegistrationArea(ue *model.UE, tac uint32) bool {
+	for _, registered := range ue.RegistrationArea {
+		if registered == tac {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,94 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package mobility
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/onosproject/onos-api/go/onos/ransim/types"
+	"github.com/onosproject/ran-simulator/pkg/traffic"
+)
+
+// Per-cell traffic metrics maintained in the metrics store and reported via KPM
+const (
+	// PdcpSduVolumeDL downlink volume transferred to the UEs of the cell in kbit
+	PdcpSduVolumeDL = "DRB.PdcpSduVolumeDL"
+	// PdcpSduVolumeUL uplink volume transferred from the UEs of the cell in kbit
+	PdcpSduVolumeUL = "DRB.PdcpSduVolumeUL"
+	// UEThpDl mean downlink throughput of the UEs of the cell transferring data in kbit/s
+	UEThpDl = "DRB.UEThpDl"
+	// UEThpUl mean uplink throughput of the UEs of the cell transferring data in kbit/s
+	UEThpUl = "DRB.UEThpUl"
+)
+
+// cellVolume accumulates the volume transferred by the UEs of a cell during a step
+type cellVolume struct {
+	downlinkBits float64
+	uplinkBits   float64
+	// ues is the number of UEs which transferred data
+	ues int
+}
+
+// addVolume adds the volume of the traffic event of a UE to the volume of its serving cell
+func addVolume(volumes map[types.ECGI]*cellVolume, ecgi types.ECGI, event traffic.Event) {
+	volume, ok := volumes[ecgi]
+	if !ok {
+		volume = &cellVolume{}
+		volumes[ecgi] = volume
+	}
+	volume.downlinkBits += event.DownlinkBits
+	volume.uplinkBits += event.UplinkBits
+	volume.ues++
+}
+
+// updateThroughput counts the volumes transferred by the UEs of the cells since the last step and sets the mean
+// throughput of their UEs; the throughput of the cells without traffic since the last step drops to zero. The volumes
+// are counted in whole kbit, the remainder being carried over to the next step.
+func (c *RrcController) updateThroughput(ctx context.Context, volumes map[types.ECGI]*cellVolume, now time.Time) {
+	elapsed := now.Sub(c.lastStep).Seconds()
+	if c.lastStep.IsZero() || elapsed <= 0 {
+		elapsed = 0
+	}
+	if now.After(c.lastStep) {
+		c.lastStep = now
+	}
+	throughputCells := make(map[types.ECGI]bool, len(volumes))
+	for ecgi, volume := range volumes {
+		residual := c.residualBits[ecgi]
+		residual.downlinkBits = c.addKbits(ctx, uint64(ecgi), PdcpSduVolumeDL, residual.downlinkBits+volume.downlinkBits)
+		residual.uplinkBits = c.addKbits(ctx, uint64(ecgi), PdcpSduVolumeUL, residual.uplinkBits+volume.uplinkBits)
+		c.residualBits[ecgi] = residual
+		if elapsed == 0 {
+			continue
+		}
+		c.setMetric(ctx, uint64(ecgi), UEThpDl, volume.downlinkBits/1000/elapsed/float64(volume.ues))
+		c.setMetric(ctx, uint64(ecgi), UEThpUl, volume.uplinkBits/1000/elapsed/float64(volume.ues))
+		throughputCells[ecgi] = true
+	}
+	for ecgi := range c.throughputCells {
+		if !throughputCells[ecgi] {
+			c.setMetric(ctx, uint64(ecgi), UEThpDl, 0.0)
+			c.setMetric(ctx, uint64(ecgi), UEThpUl, 0.0)
+		}
+	}
+	c.throughputCells = throughputCells
+}
+
+// addKbits adds the whole kbits of the given volume to the metric, returning the remaining bits
+func (c *RrcController) addKbits(ctx context.Context, entityID uint64, name string, bits float64) float64 {
+	kbits := math.Floor(bits / 1000)
+	if kbits > 0 {
+		_, _ = c.metricStore.Add(ctx, entityID, name, uint64(kbits))
+	}
+	return bits - 1000*kbits
+}
+
+func (c *RrcController) setMetric(ctx context.Context, entityID uint64, name string, value float64) {
+	if err := c.metricStore.Set(ctx, entityID, name, value); err != nil {
+		log.Warn(err)
+	}
+}
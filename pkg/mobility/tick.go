@@ -0,0 +1,109 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package mobility
+
+import (
+	"time"
+
+	"github.com/onosproject/ran-simulator/pkg/model"
+)
+
+const (
+	defaultTickInterval = time.Second
+	// defaultMaxTickFactor bounds the adaptive tick interval relative to the configured interval
+	defaultMaxTickFactor = 8
+	// tickRecoveryFactor the tick duration has to stay below, relative to the current interval, for an adaptive
+	// interval to be shortened again
+	tickRecoveryFactor = 4
+)
+
+// TickStats are the statistics of the mobility ticks
+type TickStats struct {
+	// Ticks is the number of ticks so far
+	Ticks uint64
+	// MissedDeadlines is the number of ticks which took longer than the tick interval
+	MissedDeadlines uint64
+	// UEs is the number of connected UEs measured by the last tick
+	UEs int
+	// LastDuration is the duration of the last tick
+	LastDuration time.Duration
+	// MaxDuration is the longest duration of any tick
+	MaxDuration time.Duration
+	// Interval is the current tick interval, longer than configured while adaptively slowed down
+	Interval time.Duration
+}
+
+// SetMobility configures the mobility ticks; unset intervals and timeouts are replaced by defaults
+func (c *MeasurementController) SetMobility(config model.MobilityConfig) {
+	if config.TickInterval <= 0 {
+		config.TickInterval = defaultTickInterval
+	}
+	if config.MaxTickInterval < config.TickInterval {
+		config.MaxTickInterval = defaultMaxTickFactor * config.TickInterval
+	}
+	if config.NeighborRelationTimeout <= 0 {
+		config.NeighborRelationTimeout = defaultNeighborRelationTimeout
+	}
+	c.mobility = config
+	c.interval = config.TickInterval
+	c.statsMu.Lock()
+	defer c.statsMu.Unlock()
+	c.stats.Interval = c.interval
+}
+
+// TickStats returns the statistics of the mobility ticks
+func (c *MeasurementController) TickStats() TickStats {
+	c.statsMu.RLock()
+	defer c.statsMu.RUnlock()
+	return c.stats
+}
+
+// ticked records a tick having measured the given number of UEs and returns the delay until the next tick. Ticks
+// missing their deadline drift unless adaptive, in which case the interval is doubled, up to its maximum, so that
+// large simulations degrade gracefully; it is halved again, down to the configured interval, while the ticks keep up.
+func (c *MeasurementController) ticked(ueCount int, duration time.Duration) time.Duration {
+	missed := duration > c.interval
+	if missed {
+		log.Warnf("Mobility tick measuring %d UEs took %s, exceeding the interval of %s", ueCount, duration, c.interval)
+	}
+	if c.mobility.Adaptive {
+		switch {
+		case missed && c.interval < c.mobility.MaxTickInterval:
+			c.interval = minDuration(2*c.interval, c.mobility.MaxTickInterval)
+			log.Infof("Lengthening the mobility tick interval to %s", c.interval)
+		case !missed && c.interval > c.mobility.TickInterval && duration < c.interval/tickRecoveryFactor:
+			c.interval = maxDuration(c.interval/2, c.mobility.TickInterval)
+			log.Infof("Shortening the mobility tick interval to %s", c.interval)
+		}
+	}
+
+	c.statsMu.Lock()
+	c.stats.Ticks++
+	if missed {
+		c.stats.MissedDeadlines++
+	}
+	c.stats.UEs = ueCount
+	c.stats.LastDuration = duration
+	if duration > c.stats.MaxDuration {
+		c.stats.MaxDuration = duration
+	}
+	c.stats.Interval = c.interval
+	c.statsMu.Unlock()
+	return maxDuration(c.interval-duration, 0)
+}
+
+func minDuration(a, b time.Duration) time.Duration {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func maxDuration(a, b time.Duration) time.Duration {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,205 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package mobility
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/onosproject/onos-api/go/onos/ransim/types"
+	"github.com/onosproject/ran-simulator/pkg/model"
+	"github.com/onosproject/ran-simulator/pkg/radio"
+	"github.com/onosproject/ran-simulator/pkg/store/ues"
+)
+
+const (
+	defaultUAVSpeed = 15.0
+
+	uavUpdateInterval = time.Second
+)
+
+// flight is the progress of a UAV along its route
+type flight struct {
+	routes [][]model.Coordinate
+	// route and segment index the segment the UAV flies along
+	route   int
+	segment int
+	// forward is true while the UAV flies towards the end of the route
+	forward bool
+}
+
+// target returns the waypoint the UAV flies towards
+func (f *flight) target() model.Coordinate {
+	if f.forward {
+		return f.routes[f.route][f.segment+1]
+	}
+	return f.routes[f.route][f.segment]
+}
+
+// next moves on to the following segment once the target waypoint is reached, turning around at the ends of the route
+func (f *flight) next() {
+	last := len(f.routes[f.route]) - 2
+	switch {
+	case f.forward && f.segment < last:
+		f.segment++
+	case !f.forward && f.segment > 0:
+		f.segment--
+	default:
+		f.forward = !f.forward
+	}
+}
+
+// UAVController flies the UAVs along the UAV routes of the placement at the UAV speed, the altitude being
+// interpolated between the waypoints; a UAV first joins the route segment closest to it and turns around at the
+// ends of its route. UAVs are not moved if there are no UAV routes.
+type UAVController struct {
+	ueStore  ues.Store
+	routes   [][]model.Coordinate
+	speed    float64
+	flights  map[types.IMSI]*flight
+	lastStep time.Time
+	cancel   context.CancelFunc
+}
+
+// NewUAVController creates a new UAV controller flying the UAVs along the UAV routes of the placement
+func NewUAVController(ueStore ues.Store, placement model.PlacementConfig) *UAVController {
+	speed := placement.UAVSpeed
+	if speed <= 0 {
+		speed = defaultUAVSpeed
+	}
+	var routes [][]model.Coordinate
+	for _, route := range placement.UAVWaypoints() {
+		if len(route) > 1 {
+			routes = append(routes, route)
+		}
+	}
+	return &UAVController{
+		ueStore: ueStore,
+		routes:  routes,
+		speed:   speed,
+		flights: make(map[types.IMSI]*flight),
+	}
+}
+
+// Start starts flying the UAVs, unless there are no UAV routes
+func (c *UAVController) Start() {
+	if len(c.routes) == 0 {
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	c.cancel = cancel
+	go c.run(ctx)
+}
+
+// Stop stops flying the UAVs
+func (c *UAVController) Stop() {
+	if c.cancel != nil {
+		c.cancel()
+	}
+}
+
+func (c *UAVController) run(ctx context.Context) {
+	ticker := time.NewTicker(uavUpdateInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case now := <-ticker.C:
+			c.step(ctx, now)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// step moves all UAVs along their routes by the distance flown since the last step
+func (c *UAVController) step(ctx context.Context, now time.Time) {
+	var elapsed time.Duration
+	if !c.lastStep.IsZero() && now.After(c.lastStep) {
+		elapsed = now.Sub(c.lastStep)
+	}
+	c.lastStep = now
+	present := make(map[types.IMSI]bool)
+	for _, ue := range c.ueStore.ListAllUEs(ctx) {
+		if ue.Type != model.UAV {
+			continue
+		}
+		present[ue.IMSI] = true
+		f, ok := c.flights[ue.IMSI]
+		if !ok {
+			f = c.join(ue.Location)
+			c.flights[ue.IMSI] = f
+		}
+		location, heading := c.fly(f, ue.Location, c.speed*elapsed.Seconds())
+		if location == ue.Location {
+			continue
+		}
+		if err := c.ueStore.MoveToCoordinate(ctx, ue.IMSI, location, heading); err != nil {
+			log.Warn(err)
+		}
+	}
+	for imsi := range c.flights {
+		if !present[imsi] {
+			delete(c.flights, imsi)
+		}
+	}
+}
+
+// join returns the flight along the route segment closest to the given location
+func (c *UAVController) join(location model.Coordinate) *flight {
+	best := &flight{routes: c.routes, forward: true}
+	bestDistance := math.Inf(1)
+	for r, route := range c.routes {
+		for i := 0; i < len(route)-1; i++ {
+			if distance := radio.Distance(location, closestPoint(route[i], route[i+1], location)); distance < bestDistance {
+				best.route, best.segment = r, i
+				bestDistance = distance
+			}
+		}
+	}
+	return best
+}
+
+// fly moves the UAV from the given location by the given distance in meters along its route, returning its new
+// location and heading
+func (c *UAVController) fly(f *flight, location model.Coordinate, distance float64) (model.Coordinate, uint32) {
+	heading := radio.Bearing(location, f.target())
+	// The number of waypoints passed is bounded, e.g. for routes whose waypoints coincide
+	for i := 0; i < 2*len(f.routes[f.route]) && distance > 0; i++ {
+		target := f.target()
+		remaining := radio.Distance(location, target)
+		if remaining > distance {
+			fraction := distance / remaining
+			heading = radio.Bearing(location, target)
+			location = model.Coordinate{
+				Lat: location.Lat + fraction*(target.Lat-location.Lat),
+				Lng: location.Lng + fraction*(target.Lng-location.Lng),
+				Alt: location.Alt + fraction*(target.Alt-location.Alt),
+			}
+			break
+		}
+		distance -= remaining
+		location = target
+		f.next()
+		heading = radio.Bearing(location, f.target())
+	}
+	return location, uint32(math.Mod(math.Round(heading)+360, 360))
+}
+
+// closestPoint returns the point of the segment between the given waypoints closest to the location, treating the
+// coordinates as planar, which is accurate enough for the segments of a route
+func closestPoint(from model.Coordinate, to model.Coordinate, location model.Coordinate) model.Coordinate {
+	dLat, dLng := to.Lat-from.Lat, to.Lng-from.Lng
+	length := dLat*dLat + dLng*dLng
+	if length == 0 {
+		return from
+	}
+	t := math.Max(0, math.Min(1, ((location.Lat-from.Lat)*dLat+(location.Lng-from.Lng)*dLng)/length))
+	return model.Coordinate{
+		Lat: from.Lat + t*dLat,
+		Lng: from.Lng + t*dLng,
+		Alt: from.Alt + t*(to.Alt-from.Alt),
+	}
+}
@@ -0,0 +1,60 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package mobility
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/onosproject/ran-simulator/pkg/model"
+	"github.com/onosproject/ran-simulator/pkg/radio"
+	"github.com/onosproject/ran-simulator/pkg/store/ues"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUAVController(t *testing.T) {
+	ctx := context.Background()
+	cells := cellStore(t)
+	ueStore := ues.NewUERegistry(1, cells)
+	start := model.Coordinate{Lat: 52.52, Lng: 13.405}
+	end := model.Coordinate{Lat: 52.521, Lng: 13.405, Alt: 200}
+	controller := NewUAVController(ueStore, model.PlacementConfig{
+		UAVRoutes: [][]model.Coordinate{{start, end}},
+		UAVSpeed:  10,
+	})
+	length := radio.Distance(start, end)
+
+	ue := ueStore.ListAllUEs(ctx)[0]
+	ue.Type = model.UAV
+	assert.NoError(t, ueStore.MoveToCoordinate(ctx, ue.IMSI, controller.routes[0][0], 0))
+
+	// The waypoint without altitude is at the default UAV altitude and the UAV climbs towards the next one
+	now := time.Now()
+	controller.step(ctx, now)
+	controller.step(ctx, now.Add(5*time.Second))
+	assert.InDelta(t, 50, radio.Distance(controller.routes[0][0], ue.Location), 1)
+	assert.InDelta(t, model.DefaultUAVAltitude+50*100/length, ue.Location.Alt, 1)
+	assert.Equal(t, uint32(0), ue.Heading)
+
+	// The UAV turns around at the end of the route
+	overshoot := time.Duration((length + 20) / 10 * float64(time.Second))
+	controller.step(ctx, now.Add(5*time.Second+overshoot))
+	assert.InDelta(t, 70, radio.Distance(end, ue.Location), 1)
+	assert.Equal(t, uint32(180), ue.Heading)
+
+	// Deleted UAVs are no longer tracked
+	_, err := ueStore.Delete(ctx, ue.IMSI)
+	assert.NoError(t, err)
+	controller.step(ctx, now.Add(10*time.Second+overshoot))
+	assert.Empty(t, controller.flights)
+}
+
+func TestUAVControllerWithoutRoutes(t *testing.T) {
+	controller := NewUAVController(nil, model.PlacementConfig{UAVRoutes: [][]model.Coordinate{{{Lat: 52.52, Lng: 13.405}}}})
+	assert.Empty(t, controller.routes)
+	controller.Start()
+	assert.Nil(t, controller.cancel)
+}
@@ -0,0 +1,147 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package mobility
+
+import (
+	"context"
+	"math/rand"
+	"time"
+
+	"github.com/onosproject/onos-api/go/onos/ransim/types"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/onosproject/ran-simulator/pkg/journal"
+	"github.com/onosproject/ran-simulator/pkg/model"
+)
+
+// Per-cell counters of the handover procedures, named as in 3GPP TS 28.552; the inter-node handovers are prepared
+// over the emulated Xn interface, counted by the source cell, while the target cell counts the resource allocations
+const (
+	// XnPreparationAttempts per-cell counter of inter-node handover preparations requested by the cell
+	XnPreparationAttempts = "MM.HoPrepInterReq"
+	// XnPreparationSuccesses per-cell counter of inter-node handover preparations acknowledged by the target node
+	XnPreparationSuccesses = "MM.HoPrepInterSucc"
+	// XnPreparationFailures per-cell counter of inter-node handover preparations rejected or timed out
+	XnPreparationFailures = "MM.HoPrepInterFail"
+	// XnResourceAllocationAttempts per-cell counter of inter-node handover requests received by the cell
+	XnResourceAllocationAttempts = "MM.HoResAlloInterReq"
+	// XnResourceAllocationSuccesses per-cell counter of inter-node handover requests acknowledged by the cell
+	XnResourceAllocationSuccesses = "MM.HoResAlloInterSucc"
+	// XnResourceAllocationFailures per-cell counter of inter-node handover requests rejected by the cell
+	XnResourceAllocationFailures = "MM.HoResAlloInterFail"
+	// XnExecutionAttempts per-cell counter of prepared inter-node handovers executed from the cell
+	XnExecutionAttempts = "MM.HoExeInterReq"
+	// XnExecutionSuccesses per-cell counter of inter-node handovers from the cell completed by the UE context release
+	XnExecutionSuccesses = "MM.HoExeInterSucc"
+	// XnExecutionFailures per-cell counter of inter-node handovers from the cell failed during execution
+	XnExecutionFailures = "MM.HoExeInterFail"
+	// IntraNodeExecutionAttempts per-cell counter of handovers executed from the cell to a cell of the same node
+	IntraNodeExecutionAttempts = "MM.HoExeIntraReq"
+	// IntraNodeExecutionSuccesses per-cell counter of handovers from the cell to a cell of the same node completed
+	IntraNodeExecutionSuccesses = "MM.HoExeIntraSucc"
+	// IntraNodeExecutionFailures per-cell counter of handovers from the cell to a cell of the same node failed
+	IntraNodeExecutionFailures = "MM.HoExeIntraFail"
+)
+
+const (
+	defaultTRelocPrep    = time.Second
+	defaultTRelocOverall = 5 * time.Second
+)
+
+// isInterNode returns true if the cells belong to different E2 nodes, i.e. a handover between them goes over Xn
+func isInterNode(source types.ECGI, target types.ECGI) bool {
+	return types.GetEnbID(uint64(source)) != types.GetEnbID(uint64(target))
+}
+
+func (h *HandoverEngine) xnConfig() model.XnConfig {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.config.Xn
+}
+
+// awaitXn waits for the answer of the peer node to an Xn procedure, which comes after the given response time, while
+// the given timer of the source node guards the procedure; it returns false if the timer expires first
+func awaitXn(ctx context.Context, response time.Duration, timer time.Duration) (bool, error) {
+	answered := time.NewTimer(response)
+	defer answered.Stop()
+	expired := time.NewTimer(timer)
+	defer expired.Stop()
+	select {
+	case <-answered.C:
+		return true, nil
+	case <-expired.C:
+		return false, nil
+	case <-ctx.Done():
+		return false, ctx.Err()
+	}
+}
+
+// prepareXn emulates the Xn handover preparation of the UE, i.e. the handover request of the source node and its
+// acknowledgement by the target node after the preparation time; the preparation fails if the target node rejects
+// the request or if TXnRELOCprep expires before it answers, in which case the UE stays with its serving cell
+func (h *HandoverEngine) prepareXn(ctx context.Context, ue *model.UE, target *model.UECell) error {
+	config := h.xnConfig()
+	source := ue.Cell.ECGI
+	h.count(ctx, source, XnPreparationAttempts)
+	h.count(ctx, target.ECGI, XnResourceAllocationAttempts)
+
+	cause := ""
+	if config.PreparationFailureProbability > 0 && rand.Float64() < config.PreparationFailureProbability {
+		cause = "HandoverPreparationFailure"
+		h.count(ctx, target.ECGI, XnResourceAllocationFailures)
+	} else {
+		h.count(ctx, target.ECGI, XnResourceAllocationSuccesses)
+		answered, err := awaitXn(ctx, config.PreparationTime, config.TRelocPrep)
+		if err != nil {
+			cause = err.Error()
+		} else if !answered {
+			cause = "TXnRELOCprepExpiry"
+		}
+	}
+	if cause == "" {
+		h.count(ctx, source, XnPreparationSuccesses)
+		return nil
+	}
+	h.count(ctx, source, XnPreparationFailures)
+	h.recordXnFailure(ue, source, target.ECGI, "preparation", cause)
+	return errors.New(errors.Unavailable, "Xn handover preparation of UE %d to cell %d failed: %s", ue.IMSI, target.ECGI, cause)
+}
+
+// executionFails executes the prepared handover and returns true if it fails, i.e. if the UE fails to access the
+// target cell or TXnRELOCoverall expires before the target node releases the UE context after the execution time
+func (h *HandoverEngine) executionFails(ctx context.Context, ue *model.UE, target *model.UECell) bool {
+	config := h.xnConfig()
+	cause := ""
+	if config.ExecutionFailureProbability > 0 && rand.Float64() < config.ExecutionFailureProbability {
+		cause = "AccessFailure"
+	} else if released, err := awaitXn(ctx, config.ExecutionTime, config.TRelocOverall); err != nil {
+		cause = err.Error()
+	} else if !released {
+		cause = "TXnRELOCoverallExpiry"
+	} else {
+		return false
+	}
+	h.recordXnFailure(ue, ue.Cell.ECGI, target.ECGI, "execution", cause)
+	return true
+}
+
+func (h *HandoverEngine) recordXnFailure(ue *model.UE, source types.ECGI, target types.ECGI, procedure string, cause string) {
+	log.Infof("Xn handover %s of UE %d from cell %d to cell %d failed: %s", procedure, ue.IMSI, source, target, cause)
+	journal.Record(journal.XnHandoverFailed, uint64(ue.IMSI), map[string]interface{}{
+		"source":     source,
+		"target":     target,
+		"sourceNode": types.GetEnbID(uint64(source)),
+		"targetNode": types.GetEnbID(uint64(target)),
+		"procedure":  procedure,
+		"cause":      cause,
+	})
+}
+
+// executionCounters returns the per-cell counters of the attempted, successful and failed handover executions
+func executionCounters(interNode bool) (string, string, string) {
+	if interNode {
+		return XnExecutionAttempts, XnExecutionSuccesses, XnExecutionFailures
+	}
+	return IntraNodeExecutionAttempts, IntraNodeExecutionSuccesses, IntraNodeExecutionFailures
+}
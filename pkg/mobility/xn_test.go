@@ -0,0 +1,88 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package mobility
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/onosproject/onos-api/go/onos/ransim/types"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/onosproject/ran-simulator/pkg/journal"
+	"github.com/onosproject/ran-simulator/pkg/model"
+	"github.com/onosproject/ran-simulator/pkg/store/metrics"
+	"github.com/onosproject/ran-simulator/pkg/store/ues"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestXnHandover(t *testing.T) {
+	ctx := context.Background()
+	cellStore := cellStore(t)
+	ueStore := ues.NewUERegistry(1, cellStore)
+	metricStore := metrics.NewMetricsStore()
+	handover := NewHandoverEngine(cellStore, ueStore, metricStore)
+	count := func(ecgi types.ECGI, name string) uint64 {
+		value, _ := metricStore.Get(ctx, uint64(ecgi), name)
+		c, _ := value.(uint64)
+		return c
+	}
+
+	ecgi1 := types.ECGI(84325717505)
+	ecgi2 := types.ECGI(84325717506)
+	ecgi3 := types.ECGI(84325717761)
+	ue := ueStore.ListAllUEs(ctx)[0]
+	assert.NoError(t, ueStore.MoveToCell(ctx, ue.IMSI, ecgi1, 10))
+
+	// The target node rejects the handover request; the UE stays with its serving cell
+	handover.SetHandoverConfig(model.HandoverConfig{Xn: model.XnConfig{PreparationFailureProbability: 1}})
+	assert.True(t, errors.IsUnavailable(handover.Handover(ctx, ue.IMSI, &model.UECell{ECGI: ecgi3, Strength: 12})))
+	assert.Equal(t, ecgi1, ue.Cell.ECGI)
+	assert.Equal(t, uint64(1), count(ecgi1, XnPreparationAttempts))
+	assert.Equal(t, uint64(1), count(ecgi1, XnPreparationFailures))
+	assert.Equal(t, uint64(1), count(ecgi3, XnResourceAllocationAttempts))
+	assert.Equal(t, uint64(1), count(ecgi3, XnResourceAllocationFailures))
+	assert.Equal(t, uint64(0), count(ecgi1, XnExecutionAttempts))
+	assert.Equal(t, uint64(0), count(ecgi1, HandoverFailures))
+
+	// The target node answers after TXnRELOCprep expired
+	handover.SetHandoverConfig(model.HandoverConfig{Xn: model.XnConfig{PreparationTime: time.Second, TRelocPrep: 20 * time.Millisecond}})
+	start := time.Now()
+	assert.True(t, errors.IsUnavailable(handover.Handover(ctx, ue.IMSI, &model.UECell{ECGI: ecgi3, Strength: 12})))
+	assert.Equal(t, uint64(2), count(ecgi1, XnPreparationFailures))
+	assert.Equal(t, uint64(1), count(ecgi3, XnResourceAllocationSuccesses))
+	elapsed := time.Since(start)
+	assert.True(t, elapsed >= 20*time.Millisecond && elapsed < time.Second, elapsed)
+
+	entries := journal.Default().Query(journal.Filter{EntityID: uint64(ue.IMSI), Kinds: []journal.Kind{journal.XnHandoverFailed}})
+	if assert.Equal(t, 2, len(entries)) {
+		assert.Equal(t, "preparation", entries[1].Details["procedure"])
+		assert.Equal(t, "TXnRELOCprepExpiry", entries[1].Details["cause"])
+	}
+
+	// TXnRELOCoverall expires before the UE context is released; the handover fails
+	handover.SetHandoverConfig(model.HandoverConfig{Xn: model.XnConfig{ExecutionTime: time.Second, TRelocOverall: 20 * time.Millisecond}})
+	assert.True(t, errors.IsUnavailable(handover.Handover(ctx, ue.IMSI, &model.UECell{ECGI: ecgi3, Strength: 12})))
+	assert.Equal(t, uint64(1), count(ecgi1, XnPreparationSuccesses))
+	assert.Equal(t, uint64(1), count(ecgi1, XnExecutionAttempts))
+	assert.Equal(t, uint64(1), count(ecgi1, XnExecutionFailures))
+	assert.Equal(t, uint64(1), count(ecgi1, HandoverFailures))
+
+	// Handovers within a node are not prepared over Xn
+	handover.SetHandoverConfig(model.HandoverConfig{Xn: model.XnConfig{PreparationTime: 20 * time.Millisecond, ExecutionTime: 20 * time.Millisecond}})
+	assert.NoError(t, handover.Handover(ctx, ue.IMSI, &model.UECell{ECGI: ecgi2, Strength: 11}))
+	assert.Equal(t, uint64(1), count(ecgi1, IntraNodeExecutionAttempts))
+	assert.Equal(t, uint64(1), count(ecgi1, IntraNodeExecutionSuccesses))
+	assert.Equal(t, uint64(3), count(ecgi1, XnPreparationAttempts))
+
+	// Handovers between nodes take the time of their preparation and execution
+	start = time.Now()
+	assert.NoError(t, handover.Handover(ctx, ue.IMSI, &model.UECell{ECGI: ecgi3, Strength: 12}))
+	assert.True(t, time.Since(start) >= 40*time.Millisecond)
+	assert.Equal(t, ecgi3, ue.Cell.ECGI)
+	assert.Equal(t, uint64(1), count(ecgi2, XnPreparationSuccesses))
+	assert.Equal(t, uint64(1), count(ecgi2, XnExecutionSuccesses))
+	assert.Equal(t, uint64(3), count(ecgi3, XnResourceAllocationSuccesses))
+}
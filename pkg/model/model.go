@@ -5,6 +5,9 @@
 package model
 
 import (
+	"math"
+	"time"
+
 	"github.com/onosproject/onos-api/go/onos/ransim/types"
 	"github.com/onosproject/onos-lib-go/pkg/errors"
 )
@@ -19,12 +22,27 @@ type Model struct {
 	UECount       uint                    `mapstructure:"ueCount" yaml:"ueCount"`
 	Plmn          string                  `mapstructure:"plmnID" yaml:"plmnID"`
 	PlmnID        types.PlmnID            `mapstructure:"plmnNumber" yaml:"plmnNumber"` // overridden and derived post-load from "Plmn" field
+	RRC           RrcConfig               `mapstructure:"rrc" yaml:"rrc"`
+	Placement     PlacementConfig         `mapstructure:"placement" yaml:"placement"`
+	KPIProfiles   map[string]KPIProfile   `mapstructure:"kpiProfiles" yaml:"kpiProfiles"`
+	RLF           RlfConfig               `mapstructure:"rlf" yaml:"rlf"`
+	Handover      HandoverConfig          `mapstructure:"handover" yaml:"handover"`
+	Geofences     map[string]Geofence     `mapstructure:"geofences" yaml:"geofences"`
+	Blockage      BlockageConfig          `mapstructure:"blockage" yaml:"blockage"`
+	Indoor        IndoorConfig            `mapstructure:"indoor" yaml:"indoor"`
+	Core          CoreConfig              `mapstructure:"core" yaml:"core"`
+	Mobility      MobilityConfig          `mapstructure:"mobility" yaml:"mobility"`
+	Voice         VoiceConfig             `mapstructure:"voice" yaml:"voice"`
+	MassiveIoT    MassiveIoTConfig        `mapstructure:"massiveIoT" yaml:"massiveIoT"`
+	UEStore       UEStoreConfig           `mapstructure:"ueStore" yaml:"ueStore"`
 }
 
 // Coordinate represents a geographical location
 type Coordinate struct {
 	Lat float64 `mapstructure:"lat"`
 	Lng float64 `mapstructure:"lng"`
+	// Alt is the altitude above ground in meters, e.g. of UAVs; zero for locations on the ground
+	Alt float64 `mapstructure:"alt"`
 }
 
 // Sector represents a 2D arc emanating from a location
@@ -32,6 +50,62 @@ type Sector struct {
 	Center  Coordinate `mapstructure:"center"`
 	Azimuth int32      `mapstructure:"azimuth"`
 	Arc     int32      `mapstructure:"arc"`
+
+	// Height is the antenna height above ground in meters
+	Height float64 `mapstructure:"height"`
+	// ElectricalTilt is the electrical downtilt of the antenna in degrees
+	ElectricalTilt float64 `mapstructure:"electricalTilt"`
+	// MechanicalTilt is the mechanical downtilt of the antenna in degrees
+	MechanicalTilt float64 `mapstructure:"mechanicalTilt"`
+	// HBeamwidth is the horizontal half-power beamwidth of the antenna in degrees
+	HBeamwidth float64 `mapstructure:"hBeamwidth"`
+	// VBeamwidth is the vertical half-power beamwidth of the antenna in degrees
+	VBeamwidth float64 `mapstructure:"vBeamwidth"`
+}
+
+// Geofence is a named geographic area, e.g. a stadium, whose entering and leaving by UEs is tracked
+type Geofence struct {
+	// Polygon lists the vertices of the area in order; it is closed implicitly
+	Polygon []Coordinate `mapstructure:"polygon" yaml:"polygon"`
+	// Counters enables counting the UEs entering and leaving the area by the metrics of their serving cells
+	Counters bool `mapstructure:"counters" yaml:"counters"`
+}
+
+// Contains returns true if the location lies within the polygon of the geofence
+func (g *Geofence) Contains(location Coordinate) bool {
+	return polygonContains(g.Polygon, location)
+}
+
+// IndoorConfig configures the buildings of the simulated area; UEs within a building are indoor and their links to
+// outdoor cells, and vice versa, suffer from the penetration loss of the building walls
+type IndoorConfig struct {
+	// Buildings are the outlines of the buildings, given by the vertices of their polygons
+	Buildings [][]Coordinate `mapstructure:"buildings" yaml:"buildings"`
+	// PenetrationLoss is the attenuation in dB of the building walls; defaults to 20
+	PenetrationLoss float64 `mapstructure:"penetrationLoss" yaml:"penetrationLoss"`
+}
+
+// IsIndoor returns true if the location lies within any of the buildings
+func (c *IndoorConfig) IsIndoor(location Coordinate) bool {
+	for _, building := range c.Buildings {
+		if polygonContains(building, location) {
+			return true
+		}
+	}
+	return false
+}
+
+// polygonContains returns true if the location lies within the polygon, which is closed implicitly
+func polygonContains(polygon []Coordinate, location Coordinate) bool {
+	inside := false
+	for i, j := 0, len(polygon)-1; i < len(polygon); j, i = i, i+1 {
+		a, b := polygon[i], polygon[j]
+		if (a.Lat > location.Lat) != (b.Lat > location.Lat) &&
+			location.Lng < (b.Lng-a.Lng)*(location.Lat-a.Lat)/(b.Lat-a.Lat)+a.Lng {
+			inside = !inside
+		}
+	}
+	return inside
 }
 
 // Route represents a series of points for tracking movement of user-equipment
@@ -48,6 +122,69 @@ type Node struct {
 	ServiceModels []string     `mapstructure:"servicemodels"`
 	Cells         []types.ECGI `mapstructure:"cells"`
 	Status        string       `mapstructure:"status"`
+	// RanFunctions lists the names of the service models of the node advertised as RAN functions in its E2 setup;
+	// all its service models are advertised if empty
+	RanFunctions []string `mapstructure:"ranfunctions" yaml:"ranfunctions"`
+	// Admission limits the subscriptions the node admits
+	Admission AdmissionPolicy `mapstructure:"admission" yaml:"admission"`
+	// Connections are the states of the connections of the node to its controllers by controller ID; they are
+	// recorded by the E2 agent of the node at runtime
+	Connections map[string]Connection `mapstructure:"-" yaml:"-"`
+}
+
+// Advertises returns true if the node advertises the service model with the given name as a RAN function
+func (n *Node) Advertises(serviceModel string) bool {
+	if len(n.RanFunctions) == 0 {
+		return true
+	}
+	for _, name := range n.RanFunctions {
+		if name == serviceModel {
+			return true
+		}
+	}
+	return false
+}
+
+// IsConnected returns true if the node is connected to the controller its E2 agent connects to, i.e. its first one
+func (n *Node) IsConnected() bool {
+	if len(n.Controllers) == 0 {
+		return false
+	}
+	return n.Connections[n.Controllers[0]].State == ConnectionConnected
+}
+
+// ConnectionState is the state of the connection of an E2 node to an E2T controller
+type ConnectionState string
+
+const (
+	// ConnectionConnecting is the state of a connection being established for the first time
+	ConnectionConnecting ConnectionState = "Connecting"
+	// ConnectionReconnecting is the state of a connection being established again after it was closed
+	ConnectionReconnecting ConnectionState = "Reconnecting"
+	// ConnectionConnected is the state of a connection over which the E2 setup completed
+	ConnectionConnected ConnectionState = "Connected"
+	// ConnectionFailed is the state of a connection which could not be established or set up
+	ConnectionFailed ConnectionState = "Failed"
+	// ConnectionDisconnected is the state of a connection closed by the node
+	ConnectionDisconnected ConnectionState = "Disconnected"
+)
+
+// Connection is the state of the connection of an E2 node to an E2T controller
+type Connection struct {
+	State ConnectionState `json:"state"`
+	// Since is the time the connection entered the state
+	Since time.Time `json:"since"`
+}
+
+// AdmissionPolicy limits the subscriptions an E2 node admits; zero values mean no limit
+type AdmissionPolicy struct {
+	// MaxSubscriptions is the maximum number of subscriptions of the node across all its service models
+	MaxSubscriptions int `mapstructure:"maxSubscriptions" yaml:"maxSubscriptions"`
+	// MaxActions is the maximum number of actions of a subscription
+	MaxActions int `mapstructure:"maxActions" yaml:"maxActions"`
+	// ActionTypes lists the action types admitted per service model name, i.e. report, insert or policy; all
+	// action types supported by the service models not listed are admitted
+	ActionTypes map[string][]string `mapstructure:"actionTypes" yaml:"actionTypes"`
 }
 
 // Controller E2T endpoint information
@@ -64,17 +201,162 @@ type Cell struct {
 	Color     string       `mapstructure:"color"`
 	MaxUEs    uint32       `mapstructure:"maxUEs"`
 	Neighbors []types.ECGI `mapstructure:"neighbors"`
-	TxPowerDB float64      `mapstructure:"txPower"`
+	TxPowerDB float64      `mapstructure:"txPower" yaml:"txPower"`
+	Locked    bool         `mapstructure:"locked"`
+	Barred    bool         `mapstructure:"barred"`
+	TAC       uint32       `mapstructure:"tac"`
+	Earfcn    uint32       `mapstructure:"earfcn"`
+	Band      uint32       `mapstructure:"band"`
+	Bandwidth uint32       `mapstructure:"bandwidth"` // channel bandwidth in MHz
+	Outage    bool         `mapstructure:"-"`
+	// Asleep marks cells in the energy-saving state, which neither serve UEs nor accept them
+	Asleep bool `mapstructure:"-"`
+
+	// CSG marks closed subscriber group cells, i.e. private cells only admitting the UEs in AllowedIMSIs
+	CSG          bool         `mapstructure:"csg"`
+	AllowedIMSIs []types.IMSI `mapstructure:"allowedIMSIs"`
+
+	// KPIProfile is the name of the KPI profile modulating the measurements reported for the cell, if any
+	KPIProfile string `mapstructure:"kpiProfile"`
+
+	// Environment selects the propagation model of the cell; the default urban model is used if not specified
+	Environment Environment `mapstructure:"environment"`
+	// Frequency is the downlink carrier frequency in MHz; if not specified, it is derived from the EARFCN
+	Frequency float64 `mapstructure:"frequency"`
+
+	// Status is the health of the cell computed from its state and load; its color is reflected by Color
+	Status CellStatus `mapstructure:"-"`
+}
+
+// Environment represents the propagation environment of a cell
+type Environment string
+
+const (
+	// Urban urban macro cell
+	Urban Environment = "urban"
+	// Suburban suburban macro cell
+	Suburban Environment = "suburban"
+	// Rural rural macro cell
+	Rural Environment = "rural"
+	// Indoor indoor small cell
+	Indoor Environment = "indoor"
+	// MmWave FR2 millimeter wave small cell, subject to blockage
+	MmWave Environment = "mmwave"
+)
+
+// CellStatus represents the health of a cell
+type CellStatus string
+
+const (
+	// CellNormal cell is in service and not heavily loaded
+	CellNormal CellStatus = "normal"
+	// CellLoaded cell is in service and heavily loaded
+	CellLoaded CellStatus = "loaded"
+	// CellCongested cell is in service and (nearly) full
+	CellCongested CellStatus = "congested"
+	// CellAsleep cell is in the energy-saving state
+	CellAsleep CellStatus = "asleep"
+	// CellDown cell is out of service, i.e. locked or in outage
+	CellDown CellStatus = "down"
+)
+
+// InService returns true if the cell is neither administratively locked, in outage nor asleep
+func (c *Cell) InService() bool {
+	return !c.Locked && !c.Outage && !c.Asleep
+}
+
+// IsAvailable returns true if the cell is in service and not barred and can therefore accept UEs
+func (c *Cell) IsAvailable() bool {
+	return c.InService() && !c.Barred
+}
+
+// Admits returns true if the UE may access the cell, i.e. if the cell is not a closed subscriber group cell
+// or the UE is a member of its group
+func (c *Cell) Admits(imsi types.IMSI) bool {
+	if !c.CSG {
+		return true
+	}
+	for _, allowed := range c.AllowedIMSIs {
+		if allowed == imsi {
+			return true
+		}
+	}
+	return false
+}
+
+// IsIntraFrequency returns true if the other cell operates on the same carrier frequency, comparing the configured
+// frequencies of the cells if both have one and their EARFCNs otherwise
+func (c *Cell) IsIntraFrequency(other *Cell) bool {
+	if c.Frequency != 0 && other.Frequency != 0 {
+		return c.Frequency == other.Frequency
+	}
+	return c.Earfcn == other.Earfcn
+}
+
+// Load returns the load of the cell given the number of UEs it serves, i.e. the ratio of served UEs to the maximum number of UEs
+func (c *Cell) Load(ueCount int) float64 {
+	if c.MaxUEs == 0 {
+		return 0
+	}
+	return math.Min(1, float64(ueCount)/float64(c.MaxUEs))
 }
 
 // UEType represents type of user-equipment
 type UEType string
 
+// UAV type of unmanned aerial vehicles, i.e. UEs flying above the ground
+const UAV UEType = "uav"
+
 // UECell represents UE-cell relationship
 type UECell struct {
 	ID       types.GEnbID
 	ECGI     types.ECGI // Auxiliary form of association
 	Strength float64
+
+	// InterFrequency is true if the cell operates on another carrier frequency than the serving cell
+	InterFrequency bool
+}
+
+// MeasEventType is a measurement report triggering event of 3GPP TS 36.331
+type MeasEventType string
+
+const (
+	// MeasEventA1 serving cell becomes better than a threshold
+	MeasEventA1 MeasEventType = "A1"
+	// MeasEventA2 serving cell becomes worse than a threshold
+	MeasEventA2 MeasEventType = "A2"
+	// MeasEventA3 neighbor becomes offset better than the serving cell
+	MeasEventA3 MeasEventType = "A3"
+	// MeasEventA4 neighbor becomes better than a threshold
+	MeasEventA4 MeasEventType = "A4"
+	// MeasEventA5 serving cell becomes worse than a first and neighbor better than a second threshold
+	MeasEventA5 MeasEventType = "A5"
+	// MeasEventA6 intra-frequency neighbor becomes offset better than the serving cell
+	MeasEventA6 MeasEventType = "A6"
+	// MeasEventB1 inter-frequency neighbor becomes better than a threshold
+	MeasEventB1 MeasEventType = "B1"
+)
+
+// MeasEventTypes lists all supported measurement event types
+var MeasEventTypes = []MeasEventType{MeasEventA1, MeasEventA2, MeasEventA3, MeasEventA4, MeasEventA5, MeasEventA6, MeasEventB1}
+
+// MeasReport is a measurement event triggered for a UE; the cell is the serving cell for the A1 and A2
+// events and the reported neighbor otherwise
+type MeasReport struct {
+	Event     MeasEventType
+	ECGI      types.ECGI
+	Strength  float64
+	Triggered time.Time
+}
+
+// TrajectoryPoint is a position of a UE along with its serving cell at the time
+type TrajectoryPoint struct {
+	Time     time.Time
+	Location Coordinate
+	Heading  uint32
+	// Speed is the speed of the UE in m/s at the time
+	Speed float64
+	ECGI  types.ECGI
 }
 
 // UE represents user-equipment, i.e. phone, IoT device, etc.
@@ -83,12 +365,389 @@ type UE struct {
 	Type     UEType
 	Location Coordinate
 	Heading  uint32
+	// Speed is the speed of the UE in m/s, derived from its last two positions
+	Speed float64
 
 	Cell  *UECell
 	CRNTI types.CRNTI
 	Cells []*UECell
 
 	IsAdmitted bool
+	RrcState   RrcState
+	// Indoor is true if the UE is within a building
+	Indoor bool
+	// MeasGaps is true if measurement gaps are configured, allowing the UE to measure inter-frequency neighbors
+	MeasGaps bool
+	// MeasReports lists the measurement events currently triggered for the UE
+	MeasReports []*MeasReport
+
+	// RegistrationArea lists the tracking area codes the UE is registered in
+	RegistrationArea []uint32
+
+	DRBs []*DRB
+	// PDUSessions are the PDU sessions of the UE established by the core
+	PDUSessions []*PDUSession
+}
+
+// RrcState represents the RRC state of a UE
+type RrcState int
+
+const (
+	// RrcIdle UE is not connected and only reachable via paging
+	RrcIdle RrcState = iota
+	// RrcInactive UE is suspended with its context retained by the RAN
+	RrcInactive
+	// RrcConnected UE is connected and able to transfer data
+	RrcConnected
+)
+
+func (s RrcState) String() string {
+	return [...]string{"IDLE", "INACTIVE", "CONNECTED"}[s]
+}
+
+// RrcConfig configures the RRC state machine of UEs
+type RrcConfig struct {
+	// InactivityTimer is the time without traffic after which a connected UE is suspended into the inactive state
+	InactivityTimer time.Duration `mapstructure:"inactivityTimer" yaml:"inactivityTimer"`
+	// ReleaseTimer is the time after which an inactive UE is released into the idle state
+	ReleaseTimer time.Duration `mapstructure:"releaseTimer" yaml:"releaseTimer"`
+	// Profiles are the traffic activity profiles of the UEs by UE type
+	Profiles map[UEType]ActivityProfile `mapstructure:"profiles" yaml:"profiles"`
+}
+
+// CoreConfig configures the core stub handling the registration and the PDU sessions of the UEs
+type CoreConfig struct {
+	// Enabled enables the core stub
+	Enabled bool `mapstructure:"enabled" yaml:"enabled"`
+	// RegistrationFailureProbability is the probability of a registration of a UE being rejected
+	RegistrationFailureProbability float64 `mapstructure:"registrationFailureProbability" yaml:"registrationFailureProbability"`
+	// SessionFailureProbability is the probability of a PDU session establishment failing
+	SessionFailureProbability float64 `mapstructure:"sessionFailureProbability" yaml:"sessionFailureProbability"`
+	// MeanSessionDuration is the mean duration of the PDU sessions, unless specified by their session profile
+	MeanSessionDuration time.Duration `mapstructure:"meanSessionDuration" yaml:"meanSessionDuration"`
+	// MeanSessionInterval is the mean time between the release of a PDU session of a UE and its next establishment,
+	// unless specified by its session profile
+	MeanSessionInterval time.Duration `mapstructure:"meanSessionInterval" yaml:"meanSessionInterval"`
+	// MeanDownlinkInterval is the mean time between downlink data arrivals for idle UEs with a PDU session, which
+	// trigger their paging; zero disables paging by the core
+	MeanDownlinkInterval time.Duration `mapstructure:"meanDownlinkInterval" yaml:"meanDownlinkInterval"`
+	// RetryInterval is the time after which failed registrations and PDU session establishments are retried
+	RetryInterval time.Duration `mapstructure:"retryInterval" yaml:"retryInterval"`
+	// FiveQI is the 5QI of the QoS flow of the PDU sessions
+	FiveQI int32 `mapstructure:"fiveQI" yaml:"fiveQI"`
+	// SNSSAI is the S-NSSAI of the slice of the PDU sessions
+	SNSSAI string `mapstructure:"snssai" yaml:"snssai"`
+}
+
+// VoiceConfig configures the voice call model of the UEs, i.e. their VoNR or VoLTE calls
+type VoiceConfig struct {
+	// Enabled enables the voice call model
+	Enabled bool `mapstructure:"enabled" yaml:"enabled"`
+	// UETypes are the types of the UEs making calls; all UEs make calls if empty
+	UETypes []UEType `mapstructure:"ueTypes" yaml:"ueTypes"`
+	// MeanCallInterval is the mean time between the end of a call of a UE and the arrival of its next call
+	MeanCallInterval time.Duration `mapstructure:"meanCallInterval" yaml:"meanCallInterval"`
+	// MeanCallDuration is the mean duration of the calls
+	MeanCallDuration time.Duration `mapstructure:"meanCallDuration" yaml:"meanCallDuration"`
+	// MobileTerminatedRatio is the ratio of calls received by the UEs, which are paged if idle, rather than made by them
+	MobileTerminatedRatio float64 `mapstructure:"mobileTerminatedRatio" yaml:"mobileTerminatedRatio"`
+	// SetupFailureProbability is the probability of a call setup failing irrespective of the radio conditions
+	SetupFailureProbability float64 `mapstructure:"setupFailureProbability" yaml:"setupFailureProbability"`
+	// DropProbability is the probability of a call being dropped per minute irrespective of the radio conditions
+	DropProbability float64 `mapstructure:"dropProbability" yaml:"dropProbability"`
+	// MinSINR is the serving cell SINR in dB below which calls fail to set up, around which established calls drop
+	MinSINR float64 `mapstructure:"minSinr" yaml:"minSinr"`
+}
+
+// MassiveIoTConfig configures the massive IoT mode, simulating large populations of NB-IoT style devices in a compact
+// representation rather than as UEs, only reported by aggregate per-cell KPIs
+type MassiveIoTConfig struct {
+	// Classes are the device classes by UE type
+	Classes map[UEType]IoTClass `mapstructure:"classes" yaml:"classes"`
+}
+
+// IoTClass describes a population of massive IoT devices, which stay put in their cell and only connect to send
+// mobile originated uplink reports
+type IoTClass struct {
+	// Count is the number of devices
+	Count uint `mapstructure:"count" yaml:"count"`
+	// Cells lists the ECGIs of the cells the devices are spread over; all cells if not specified
+	Cells []types.ECGI `mapstructure:"cells" yaml:"cells"`
+	// ReportInterval is the mean interval between the reports of a device
+	ReportInterval time.Duration `mapstructure:"reportInterval" yaml:"reportInterval"`
+	// Periodic devices report at fixed intervals rather than as a Poisson process
+	Periodic bool `mapstructure:"periodic" yaml:"periodic"`
+	// PayloadSize is the size of a report in bytes
+	PayloadSize uint32 `mapstructure:"payloadSize" yaml:"payloadSize"`
+	// ConnectionDuration is the time a device stays connected per report before being released
+	ConnectionDuration time.Duration `mapstructure:"connectionDuration" yaml:"connectionDuration"`
+}
+
+// MobilityConfig configures the mobility tick, i.e. the periodic measurements of the connected UEs evaluating their
+// measurement events and radio link failures
+type MobilityConfig struct {
+	// TickInterval is the interval of the ticks; defaults to 1s
+	TickInterval time.Duration `mapstructure:"tickInterval" yaml:"tickInterval"`
+	// Adaptive lengthens the tick interval while the ticks miss their deadline, i.e. take longer than the interval,
+	// and shortens it back to the configured interval once they keep up again
+	Adaptive bool `mapstructure:"adaptive" yaml:"adaptive"`
+	// MaxTickInterval bounds the adaptive tick interval; defaults to 8 times the tick interval
+	MaxTickInterval time.Duration `mapstructure:"maxTickInterval" yaml:"maxTickInterval"`
+	// NeighborRelations is the number of strongest cells at the location of each connected UE which are added to
+	// the neighbors of its serving cell on every tick, maintaining the neighbor lists automatically; zero disables
+	// the automatic neighbor relations
+	NeighborRelations int `mapstructure:"neighborRelations" yaml:"neighborRelations"`
+	// NeighborRelationTimeout is the time after which a neighbor relation added automatically is removed again if
+	// no UE detected the neighbor in the meantime; defaults to 5m
+	NeighborRelationTimeout time.Duration `mapstructure:"neighborRelationTimeout" yaml:"neighborRelationTimeout"`
+	// MaxNeighbors is the maximum number of neighbor cells kept in the measured candidate cells of each UE; zero
+	// keeps all measured neighbors
+	MaxNeighbors int `mapstructure:"maxNeighbors" yaml:"maxNeighbors"`
+	// NeighborHysteresis is the margin in dB a measured cell has to exceed a cell kept in the neighbor list of a UE
+	// by to replace it
+	NeighborHysteresis float64 `mapstructure:"neighborHysteresis" yaml:"neighborHysteresis"`
+}
+
+// HandoverConfig configures the handover engine
+type HandoverConfig struct {
+	// PingPongWindow is the time within which a UE handed back to the cell it was handed over from is a ping-pong
+	PingPongWindow time.Duration `mapstructure:"pingPongWindow" yaml:"pingPongWindow"`
+	// Xn configures the handovers between cells of different E2 nodes
+	Xn XnConfig `mapstructure:"xn" yaml:"xn"`
+}
+
+// XnConfig configures the emulation of the Xn/X2 handover preparation and execution between E2 nodes
+type XnConfig struct {
+	// PreparationTime is the time the target node takes to acknowledge a handover request
+	PreparationTime time.Duration `mapstructure:"preparationTime" yaml:"preparationTime"`
+	// ExecutionTime is the time from the handover command until the target node releases the UE context at the source
+	ExecutionTime time.Duration `mapstructure:"executionTime" yaml:"executionTime"`
+	// TRelocPrep is the TXnRELOCprep timer of the source node guarding the preparation
+	TRelocPrep time.Duration `mapstructure:"tRelocPrep" yaml:"tRelocPrep"`
+	// TRelocOverall is the TXnRELOCoverall timer of the source node guarding the execution
+	TRelocOverall time.Duration `mapstructure:"tRelocOverall" yaml:"tRelocOverall"`
+	// PreparationFailureProbability is the probability of the target node rejecting a handover request
+	PreparationFailureProbability float64 `mapstructure:"preparationFailureProbability" yaml:"preparationFailureProbability"`
+	// ExecutionFailureProbability is the probability of a UE failing to access the target cell
+	ExecutionFailureProbability float64 `mapstructure:"executionFailureProbability" yaml:"executionFailureProbability"`
+}
+
+// RlfConfig configures the simulation of handover and radio link failures
+type RlfConfig struct {
+	// Qout is the RSRP in dBm below which the radio link of a UE is out of sync; zero disables radio link failures
+	Qout float64 `mapstructure:"qout" yaml:"qout"`
+	// T310 is the time the radio link has to stay out of sync before it fails
+	T310 time.Duration `mapstructure:"t310" yaml:"t310"`
+	// HandoverFailureProbability is the probability of a handover to fail regardless of the radio conditions
+	HandoverFailureProbability float64 `mapstructure:"handoverFailureProbability" yaml:"handoverFailureProbability"`
+	// TooEarlyWindow is the time after a handover within which a radio link failure is attributed to the handover
+	TooEarlyWindow time.Duration `mapstructure:"tooEarlyWindow" yaml:"tooEarlyWindow"`
+}
+
+// BlockageConfig configures the blockage of the links between UEs and mmWave cells, e.g. by vehicles or bodies
+type BlockageConfig struct {
+	// Rate is the mean number of blockages per second of each link; zero disables blockage
+	Rate float64 `mapstructure:"rate" yaml:"rate"`
+	// Duration is the mean duration of a blockage
+	Duration time.Duration `mapstructure:"duration" yaml:"duration"`
+	// Loss is the attenuation in dB of a blocked link
+	Loss float64 `mapstructure:"loss" yaml:"loss"`
+}
+
+// ActivityProfile describes the traffic activity of a class of UEs as sessions of data transfer generated by the
+// traffic generator of the class
+type ActivityProfile struct {
+	// MeanInterval is the mean time between the arrivals of traffic sessions, overriding the generator default
+	MeanInterval time.Duration `mapstructure:"meanInterval" yaml:"meanInterval"`
+	// MeanDuration is the mean duration of a traffic session, overriding the generator default
+	MeanDuration time.Duration `mapstructure:"meanDuration" yaml:"meanDuration"`
+	// DownlinkRatio is the share of traffic sessions initiated by downlink traffic, which requires idle UEs to be paged
+	DownlinkRatio float64 `mapstructure:"downlinkRatio" yaml:"downlinkRatio"`
+	// EstablishmentCause is the cause of the RRC connections established by the UEs for uplink traffic, e.g. mo-Data
+	EstablishmentCause string `mapstructure:"establishmentCause" yaml:"establishmentCause"`
+	// Sessions are the PDU sessions established by the UEs via the core stub; a single session per the core
+	// configuration if not specified
+	Sessions []SessionProfile `mapstructure:"sessions" yaml:"sessions"`
+	// Generator is the name of the traffic generator of the UEs, e.g. web, streaming or iot; bursts if not specified
+	Generator string `mapstructure:"generator" yaml:"generator"`
+	// DownlinkRate is the mean downlink bit rate of the traffic sessions in kbit/s, overriding the generator default
+	DownlinkRate float64 `mapstructure:"downlinkRate" yaml:"downlinkRate"`
+	// UplinkRate is the mean uplink bit rate of the traffic sessions in kbit/s, overriding the generator default
+	UplinkRate float64 `mapstructure:"uplinkRate" yaml:"uplinkRate"`
+	// PowerSaving describes the DRX cycles and the battery of the UEs; UEs without power saving if not specified
+	PowerSaving PowerSavingProfile `mapstructure:"powerSaving" yaml:"powerSaving"`
+}
+
+// PowerSavingProfile describes the power saving of a class of UEs, e.g. IoT devices, by their DRX cycles and battery
+type PowerSavingProfile struct {
+	// DRXCycle is the paging cycle of idle UEs, which respond to paging at their next paging occasion, e.g. minutes
+	// for extended DRX; paged UEs respond immediately if not specified
+	DRXCycle time.Duration `mapstructure:"drxCycle" yaml:"drxCycle"`
+	// ConnectedDRXCycle is the DRX cycle of connected UEs, which measure and report their cells once per cycle;
+	// connected UEs measure at every mobility tick if not specified
+	ConnectedDRXCycle time.Duration `mapstructure:"connectedDrxCycle" yaml:"connectedDrxCycle"`
+	// BatteryCapacity is the capacity of the battery of the UEs in mAh; UEs without battery if not specified
+	BatteryCapacity float64 `mapstructure:"batteryCapacity" yaml:"batteryCapacity"`
+	// ConnectedCurrent is the mean current drawn by connected UEs in mA
+	ConnectedCurrent float64 `mapstructure:"connectedCurrent" yaml:"connectedCurrent"`
+	// IdleCurrent is the mean current drawn by idle and inactive UEs in mA
+	IdleCurrent float64 `mapstructure:"idleCurrent" yaml:"idleCurrent"`
+}
+
+// SessionProfile describes the churn of a PDU session of UEs bound to a network slice
+type SessionProfile struct {
+	// SNSSAI is the S-NSSAI of the slice as the decimal SST optionally followed by the hexadecimal SD, e.g. 1-010203
+	SNSSAI string `mapstructure:"snssai" yaml:"snssai"`
+	// FiveQI is the 5QI of the QoS flow of the session
+	FiveQI int32 `mapstructure:"fiveQI" yaml:"fiveQI"`
+	// MeanDuration is the mean duration of the session
+	MeanDuration time.Duration `mapstructure:"meanDuration" yaml:"meanDuration"`
+	// MeanInterval is the mean time between the release of the session and its next establishment
+	MeanInterval time.Duration `mapstructure:"meanInterval" yaml:"meanInterval"`
+}
+
+// KPIProfile modulates the measurement values reported for cells over time by multiplying them with a factor
+// following the time of day and week, evaluated in local time
+type KPIProfile struct {
+	// DailyAmplitude is the amplitude of the daily sinusoid as a fraction of the mean value
+	DailyAmplitude float64 `mapstructure:"dailyAmplitude" yaml:"dailyAmplitude"`
+	// PeakHour is the hour of the day the daily sinusoid peaks at, e.g. 14.5 for 14:30
+	PeakHour float64 `mapstructure:"peakHour" yaml:"peakHour"`
+	// BusyHours are additional peaks on top of the daily sinusoid
+	BusyHours []BusyHour `mapstructure:"busyHours" yaml:"busyHours"`
+	// WeekendFactor scales the values on Saturdays and Sundays; defaults to 1
+	WeekendFactor float64 `mapstructure:"weekendFactor" yaml:"weekendFactor"`
+	// Noise is the standard deviation of the multiplicative Gaussian noise
+	Noise float64 `mapstructure:"noise" yaml:"noise"`
+	// UTCOffset is the offset of the local time from UTC in hours
+	UTCOffset float64 `mapstructure:"utcOffset" yaml:"utcOffset"`
+	// Measurements restricts the profile to the named measurements; all measurements are modulated if empty
+	Measurements []string `mapstructure:"measurements" yaml:"measurements"`
+}
+
+// BusyHour is a peak of the values around an hour of the day
+type BusyHour struct {
+	// Hour is the hour of the day the values peak at
+	Hour float64 `mapstructure:"hour" yaml:"hour"`
+	// Width is the standard deviation of the bell-shaped peak in hours; defaults to 1
+	Width float64 `mapstructure:"width" yaml:"width"`
+	// Factor scales the values at the peak
+	Factor float64 `mapstructure:"factor" yaml:"factor"`
+}
+
+// Placement distributions of UEs
+const (
+	// PlacementUniform places UEs uniformly over the coverage area of the cells
+	PlacementUniform = "uniform"
+	// PlacementHotspots places UEs around hotspots with a Gaussian spread
+	PlacementHotspots = "hotspots"
+	// PlacementRoutes places UEs along routes
+	PlacementRoutes = "routes"
+)
+
+// PlacementConfig configures the distribution of the initial locations of UEs
+type PlacementConfig struct {
+	// Distribution is the placement distribution; defaults to uniform
+	Distribution string `mapstructure:"distribution" yaml:"distribution"`
+	// CellRadius is the radius of the coverage area of each cell in meters for the uniform distribution
+	CellRadius float64 `mapstructure:"cellRadius" yaml:"cellRadius"`
+	// Hotspots are the hotspots of the hotspots distribution
+	Hotspots []Hotspot `mapstructure:"hotspots" yaml:"hotspots"`
+	// Routes are the routes, given by their waypoints, of the routes distribution
+	Routes [][]Coordinate `mapstructure:"routes" yaml:"routes"`
+	// UAVShare is the share of UEs created as UAVs
+	UAVShare float64 `mapstructure:"uavShare" yaml:"uavShare"`
+	// UAVRoutes are the 3D routes, given by their waypoints including their altitude, UAVs are placed on; without
+	// UAV routes, UAVs are placed as per the distribution at the UAV altitude
+	UAVRoutes [][]Coordinate `mapstructure:"uavRoutes" yaml:"uavRoutes"`
+	// UAVAltitude is the altitude of UAVs in meters if there are no UAV routes, and of the waypoints of UAV routes
+	// without altitude; defaults to 100
+	UAVAltitude float64 `mapstructure:"uavAltitude" yaml:"uavAltitude"`
+	// UAVSpeed is the speed in m/s UAVs fly along the UAV routes at; defaults to 15
+	UAVSpeed float64 `mapstructure:"uavSpeed" yaml:"uavSpeed"`
+}
+
+// DefaultUAVAltitude is the altitude of UAVs in meters unless configured otherwise
+const DefaultUAVAltitude = 100.0
+
+// UAVWaypoints returns the UAV routes with the waypoints without altitude at the UAV altitude
+func (c PlacementConfig) UAVWaypoints() [][]Coordinate {
+	altitude := c.UAVAltitude
+	if altitude <= 0 {
+		altitude = DefaultUAVAltitude
+	}
+	routes := make([][]Coordinate, 0, len(c.UAVRoutes))
+	for _, route := range c.UAVRoutes {
+		waypoints := make([]Coordinate, 0, len(route))
+		for _, waypoint := range route {
+			if waypoint.Alt <= 0 {
+				waypoint.Alt = altitude
+			}
+			waypoints = append(waypoints, waypoint)
+		}
+		routes = append(routes, waypoints)
+	}
+	return routes
+}
+
+// UEStoreConfig configures the representation of the UEs held by the simulator
+type UEStoreConfig struct {
+	// CompactThreshold is the UE count from which the UEs are held in the compact UE store, cutting the memory footprint
+	// of very large numbers of UEs; zero keeps the regular UE store for any UE count
+	CompactThreshold uint `mapstructure:"compactThreshold" yaml:"compactThreshold"`
+}
+
+// Hotspot represents a cluster of UEs around a center location
+type Hotspot struct {
+	Center Coordinate `mapstructure:"center"`
+	// Sigma is the standard deviation of the distance of UEs from the center in meters
+	Sigma float64 `mapstructure:"sigma"`
+	// Weight is the relative share of UEs placed around this hotspot; defaults to 1
+	Weight float64 `mapstructure:"weight"`
+}
+
+// QoSFlow represents a QoS flow with its QoS characteristics
+type QoSFlow struct {
+	QFI    int32
+	FiveQI int32
+	GBR    bool
+	ARP    int32
+}
+
+// DRB represents a data radio bearer established for a UE and the QoS flows mapped onto it
+type DRB struct {
+	ID       int32
+	QoSFlows []*QoSFlow
+}
+
+// GetDRB gets a data radio bearer of the UE based on a given ID
+func (ue *UE) GetDRB(id int32) (*DRB, bool) {
+	for _, drb := range ue.DRBs {
+		if drb.ID == id {
+			return drb, true
+		}
+	}
+	return nil, false
+}
+
+// PDUSession is a PDU session of a UE bound to a network slice
+type PDUSession struct {
+	ID int32
+	// SNSSAI is the S-NSSAI of the slice as the decimal SST optionally followed by the hexadecimal SD, e.g. 1-010203
+	SNSSAI string
+	FiveQI int32
+	// DRB is the ID of the DRB carrying the QoS flow of the session
+	DRB int32
+}
+
+// GetPDUSession gets a PDU session of the UE based on a given ID
+func (ue *UE) GetPDUSession(id int32) (*PDUSession, bool) {
+	for _, session := range ue.PDUSessions {
+		if session.ID == id {
+			return session, true
+		}
+	}
+	return nil, false
 }
 
 // ServiceModel service model information
@@ -96,6 +755,23 @@ type ServiceModel struct {
 	ID          int    `mapstructure:"id"`
 	Description string `mapstructure:"description"`
 	Version     string `mapstructure:"version"`
+	// MinReportPeriod and MaxReportPeriod bound the report period in milliseconds accepted for subscriptions;
+	// zero selects the default bound
+	MinReportPeriod uint32 `mapstructure:"minReportPeriod" yaml:"minReportPeriod"`
+	MaxReportPeriod uint32 `mapstructure:"maxReportPeriod" yaml:"maxReportPeriod"`
+	// ClampReportPeriod clamps out-of-range report periods to the bounds instead of rejecting the subscription
+	ClampReportPeriod bool `mapstructure:"clampReportPeriod" yaml:"clampReportPeriod"`
+	// TimestampResolution selects the encoding of the timestamps in indication headers, e.g. unix, ntp or ntpShort
+	TimestampResolution string `mapstructure:"timestampResolution" yaml:"timestampResolution"`
+	// SamplingPeriod is the period in milliseconds the measurements aggregated over their granularity period are
+	// sampled at; zero selects the default period
+	SamplingPeriod uint32 `mapstructure:"samplingPeriod" yaml:"samplingPeriod"`
+	// MaxDescribedCells is the maximum number of cells of a node listed as measurement objects in the RAN function
+	// description; zero lists all cells and a negative value none
+	MaxDescribedCells int `mapstructure:"maxDescribedCells" yaml:"maxDescribedCells"`
+	// DescribedCellsPerItem is the maximum number of cells listed by each RIC KPM node item of the RAN function
+	// description, the cells of a node being split across several items; zero selects the maximum of the E2SM
+	DescribedCellsPerItem int `mapstructure:"describedCellsPerItem" yaml:"describedCellsPerItem"`
 }
 
 // GetServiceModel gets a service model based on a given name.
@@ -33,6 +33,12 @@ func TestModel(t *testing.T) {
 	assert.Equal(t, 2, len(model.Nodes["node1"].Cells))
 	assert.Equal(t, 44.0, model.Cells["cell3"].Sector.Center.Lat)
 
+	node := model.Nodes["node1"]
+	assert.True(t, node.Advertises("rc"))
+	node.RanFunctions = []string{"kpm"}
+	assert.True(t, node.Advertises("kpm"))
+	assert.False(t, node.Advertises("rc"))
+
 	assert.Equal(t, true, model.MapLayout.FadeMap)
 	assert.Equal(t, 45.0, model.MapLayout.Center.Lat)
 }
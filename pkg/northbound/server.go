@@ -0,0 +1,113 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package northbound serves the northbound gRPC services of the simulator; unlike the server of onos-lib-go it accepts
+// additional server options, e.g. the interceptors authorizing the requests
+package northbound
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net"
+
+	"github.com/onosproject/onos-lib-go/pkg/certs"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/onosproject/onos-lib-go/pkg/logging"
+	service "github.com/onosproject/onos-lib-go/pkg/northbound"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+)
+
+var log = logging.GetLogger("northbound")
+
+// Server is the northbound gRPC server
+type Server struct {
+	cfg      *service.ServerConfig
+	opts     []grpc.ServerOption
+	services []service.Service
+	server   *grpc.Server
+}
+
+// NewServer creates a new server with the given configuration and additional server options
+func NewServer(cfg *service.ServerConfig, opts ...grpc.ServerOption) *Server {
+	return &Server{
+		cfg:  cfg,
+		opts: opts,
+	}
+}
+
+// AddService adds a service to the server to be registered on Serve
+func (s *Server) AddService(r service.Service) {
+	s.services = append(s.services, r)
+}
+
+// Serve starts serving the services with TLS, calling started once listening
+func (s *Server) Serve(started func(string)) error {
+	tlsCfg, err := s.tlsConfig()
+	if err != nil {
+		return err
+	}
+	lis, err := net.Listen("tcp", fmt.Sprintf(":%d", s.cfg.Port))
+	if err != nil {
+		return err
+	}
+	return s.serve(lis, started, grpc.Creds(credentials.NewTLS(tlsCfg)))
+}
+
+// ServeListener starts serving the services without TLS on the given listener, e.g. the in-process listener of the
+// services of a tenant, calling started once listening
+func (s *Server) ServeListener(lis net.Listener, started func(string)) error {
+	return s.serve(lis, started)
+}
+
+func (s *Server) serve(lis net.Listener, started func(string), opts ...grpc.ServerOption) error {
+	s.server = grpc.NewServer(append(opts, s.opts...)...)
+	for i := range s.services {
+		s.services[i].Register(s.server)
+	}
+	started(lis.Addr().String())
+
+	log.Infof("Starting RPC server on address: %s", lis.Addr().String())
+	return s.server.Serve(lis)
+}
+
+// tlsConfig returns the TLS configuration of the server, using the default localhost certificate and ONF CA for the
+// paths left empty; client certificates are only verified if presented unless the configuration is secure
+func (s *Server) tlsConfig() (*tls.Config, error) {
+	var err error
+	tlsCfg := &tls.Config{}
+	var cert tls.Certificate
+	if *s.cfg.CertPath == "" && *s.cfg.KeyPath == "" {
+		cert, err = tls.X509KeyPair([]byte(certs.DefaultLocalhostCrt), []byte(certs.DefaultLocalhostKey))
+	} else {
+		log.Infof("Loading certs: %s %s", *s.cfg.CertPath, *s.cfg.KeyPath)
+		cert, err = tls.LoadX509KeyPair(*s.cfg.CertPath, *s.cfg.KeyPath)
+	}
+	if err != nil {
+		return nil, errors.New(errors.Invalid, "invalid northbound key pair: %v", err)
+	}
+	tlsCfg.Certificates = []tls.Certificate{cert}
+
+	if s.cfg.Insecure {
+		tlsCfg.ClientAuth = tls.VerifyClientCertIfGiven
+	} else {
+		tlsCfg.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+	if *s.cfg.CaPath == "" {
+		tlsCfg.ClientCAs, err = certs.GetCertPoolDefault()
+	} else {
+		tlsCfg.ClientCAs, err = certs.GetCertPool(*s.cfg.CaPath)
+	}
+	if err != nil {
+		return nil, errors.New(errors.Invalid, "invalid northbound CA certificate: %v", err)
+	}
+	return tlsCfg, nil
+}
+
+// Stop stops the server
+func (s *Server) Stop() {
+	if s.server != nil {
+		s.server.Stop()
+	}
+}
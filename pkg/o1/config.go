@@ -0,0 +1,88 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package o1
+
+import (
+	"github.com/onosproject/onos-api/go/onos/ransim/types"
+	"github.com/onosproject/ran-simulator/pkg/model"
+)
+
+// Config is the configuration of the simulated RAN exposed via the O1 interface
+type Config struct {
+	Nodes []*NodeConfig `json:"nodes"`
+	Cells []*CellConfig `json:"cells"`
+}
+
+// NodeConfig is the configuration of an E2 node
+type NodeConfig struct {
+	EnbID       types.EnbID  `json:"enbID"`
+	Controllers []string     `json:"controllers,omitempty"`
+	Cells       []types.ECGI `json:"cells,omitempty"`
+}
+
+// CellConfig is the configuration of a cell; fields left unset in an edit are not changed
+type CellConfig struct {
+	ECGI      types.ECGI   `json:"ecgi"`
+	TxPowerDB *float64     `json:"txPower,omitempty"`
+	MaxUEs    *uint32      `json:"maxUEs,omitempty"`
+	Neighbors []types.ECGI `json:"neighbors,omitempty"`
+	Locked    *bool        `json:"locked,omitempty"`
+	Barred    *bool        `json:"barred,omitempty"`
+
+	ElectricalTilt *float64 `json:"electricalTilt,omitempty"`
+	MechanicalTilt *float64 `json:"mechanicalTilt,omitempty"`
+}
+
+// Operation is a configuration edit operation
+type Operation string
+
+const (
+	// Merge merges the supplied configuration into the existing one
+	Merge Operation = "merge"
+)
+
+// Edit is a single edit of a configuration transaction
+type Edit struct {
+	Operation Operation   `json:"operation"`
+	Node      *NodeConfig `json:"node,omitempty"`
+	Cell      *CellConfig `json:"cell,omitempty"`
+}
+
+// Transaction is a committed configuration transaction
+type Transaction struct {
+	ID    uint64  `json:"id"`
+	Edits []*Edit `json:"edits"`
+
+	// pre-images of the modified nodes and cells used for the rollback
+	nodes []*model.Node
+	cells []*model.Cell
+}
+
+func newNodeConfig(node *model.Node) *NodeConfig {
+	return &NodeConfig{
+		EnbID:       node.EnbID,
+		Controllers: node.Controllers,
+		Cells:       node.Cells,
+	}
+}
+
+func newCellConfig(cell *model.Cell) *CellConfig {
+	txPower := cell.TxPowerDB
+	maxUEs := cell.MaxUEs
+	locked := cell.Locked
+	barred := cell.Barred
+	electricalTilt := cell.Sector.ElectricalTilt
+	mechanicalTilt := cell.Sector.MechanicalTilt
+	return &CellConfig{
+		ECGI:           cell.ECGI,
+		TxPowerDB:      &txPower,
+		MaxUEs:         &maxUEs,
+		Neighbors:      cell.Neighbors,
+		Locked:         &locked,
+		Barred:         &barred,
+		ElectricalTilt: &electricalTilt,
+		MechanicalTilt: &mechanicalTilt,
+	}
+}
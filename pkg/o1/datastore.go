@@ -0,0 +1,265 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package o1
+
+import (
+	"context"
+	"sync"
+
+	"github.com/onosproject/onos-api/go/onos/ransim/types"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/onosproject/onos-lib-go/pkg/logging"
+	"github.com/onosproject/ran-simulator/pkg/mobility"
+	"github.com/onosproject/ran-simulator/pkg/store/cells"
+	"github.com/onosproject/ran-simulator/pkg/store/metrics"
+	"github.com/onosproject/ran-simulator/pkg/store/nodes"
+)
+
+var log = logging.GetLogger("o1")
+
+// Datastore applies configuration transactions to the simulated RAN and keeps their history for the rollback
+type Datastore struct {
+	mu          sync.Mutex
+	nodeStore   nodes.Store
+	cellStore   cells.Store
+	metricStore metrics.Store
+	history     []*Transaction
+	nextID      uint64
+}
+
+// NewDatastore creates a new configuration datastore
+func NewDatastore(nodeStore nodes.Store, cellStore cells.Store, metricStore metrics.Store) *Datastore {
+	return &Datastore{
+		nodeStore:   nodeStore,
+		cellStore:   cellStore,
+		metricStore: metricStore,
+		nextID:      1,
+	}
+}
+
+// Get returns the running configuration
+func (d *Datastore) Get(ctx context.Context) (*Config, error) {
+	nodeList, err := d.nodeStore.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	cellList, err := d.cellStore.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	config := &Config{
+		Nodes: make([]*NodeConfig, 0, len(nodeList)),
+		Cells: make([]*CellConfig, 0, len(cellList)),
+	}
+	for _, node := range nodeList {
+		config.Nodes = append(config.Nodes, newNodeConfig(node))
+	}
+	for _, cell := range cellList {
+		config.Cells = append(config.Cells, newCellConfig(cell))
+	}
+	return config, nil
+}
+
+// GetCell returns the running configuration of the specified cell
+func (d *Datastore) GetCell(ctx context.Context, ecgi types.ECGI) (*CellConfig, error) {
+	cell, err := d.cellStore.Get(ctx, ecgi)
+	if err != nil {
+		return nil, err
+	}
+	return newCellConfig(cell), nil
+}
+
+// GetNode returns the running configuration of the specified node
+func (d *Datastore) GetNode(ctx context.Context, enbID types.EnbID) (*NodeConfig, error) {
+	node, err := d.nodeStore.Get(ctx, enbID)
+	if err != nil {
+		return nil, err
+	}
+	return newNodeConfig(node), nil
+}
+
+// Commit validates and applies all edits of a transaction; if any edit fails, the edits
+// already applied are rolled back so that the transaction is applied either entirely or not at all
+func (d *Datastore) Commit(ctx context.Context, edits []*Edit) (*Transaction, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	tx := &Transaction{Edits: edits}
+	if err := d.validate(ctx, edits); err != nil {
+		return nil, err
+	}
+	for _, edit := range edits {
+		if err := d.apply(ctx, tx, edit); err != nil {
+			log.Warnf("Transaction failed, rolling back: %v", err)
+			d.revert(ctx, tx)
+			return nil, err
+		}
+	}
+	tx.ID = d.nextID
+	d.nextID++
+	d.history = append(d.history, tx)
+	log.Infof("Committed configuration transaction %d", tx.ID)
+	return tx, nil
+}
+
+// Rollback reverts the specified transaction and all transactions committed after it
+func (d *Datastore) Rollback(ctx context.Context, id uint64) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	index := -1
+	for i, tx := range d.history {
+		if tx.ID == id {
+			index = i
+			break
+		}
+	}
+	if index < 0 {
+		return errors.New(errors.NotFound, "transaction %d not found", id)
+	}
+	for i := len(d.history) - 1; i >= index; i-- {
+		d.revert(ctx, d.history[i])
+	}
+	d.history = d.history[:index]
+	log.Infof("Rolled back configuration to before transaction %d", id)
+	return nil
+}
+
+// Transactions returns the history of the committed transactions
+func (d *Datastore) Transactions() []*Transaction {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	transactions := make([]*Transaction, len(d.history))
+	copy(transactions, d.history)
+	return transactions
+}
+
+func (d *Datastore) validate(ctx context.Context, edits []*Edit) error {
+	for _, edit := range edits {
+		if edit.Operation != Merge {
+			return errors.New(errors.Invalid, "unsupported operation %s", edit.Operation)
+		}
+		if (edit.Cell == nil) == (edit.Node == nil) {
+			return errors.New(errors.Invalid, "each edit must target exactly one node or cell")
+		}
+		if edit.Cell != nil {
+			if _, err := d.cellStore.Get(ctx, edit.Cell.ECGI); err != nil {
+				return err
+			}
+			for _, ecgi := range edit.Cell.Neighbors {
+				if _, err := d.cellStore.Get(ctx, ecgi); err != nil {
+					return errors.New(errors.Invalid, "neighbor cell %d not found", ecgi)
+				}
+			}
+			for _, tilt := range []*float64{edit.Cell.ElectricalTilt, edit.Cell.MechanicalTilt} {
+				if tilt != nil && (*tilt < -90 || *tilt > 90) {
+					return errors.New(errors.Invalid, "tilt %f out of range", *tilt)
+				}
+			}
+		}
+		if edit.Node != nil {
+			if _, err := d.nodeStore.Get(ctx, edit.Node.EnbID); err != nil {
+				return err
+			}
+			for _, ecgi := range edit.Node.Cells {
+				if _, err := d.cellStore.Get(ctx, ecgi); err != nil {
+					return errors.New(errors.Invalid, "cell %d not found", ecgi)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func (d *Datastore) apply(ctx context.Context, tx *Transaction, edit *Edit) error {
+	if edit.Cell != nil {
+		cell, err := d.cellStore.Get(ctx, edit.Cell.ECGI)
+		if err != nil {
+			return err
+		}
+		preImage := *cell
+		tx.cells = append(tx.cells, &preImage)
+
+		updated := *cell
+		if edit.Cell.TxPowerDB != nil {
+			updated.TxPowerDB = *edit.Cell.TxPowerDB
+		}
+		if edit.Cell.MaxUEs != nil {
+			updated.MaxUEs = *edit.Cell.MaxUEs
+		}
+		if edit.Cell.Neighbors != nil {
+			updated.Neighbors = edit.Cell.Neighbors
+		}
+		if edit.Cell.ElectricalTilt != nil {
+			updated.Sector.ElectricalTilt = *edit.Cell.ElectricalTilt
+		}
+		if edit.Cell.MechanicalTilt != nil {
+			updated.Sector.MechanicalTilt = *edit.Cell.MechanicalTilt
+		}
+		if err := d.cellStore.Update(ctx, &updated); err != nil {
+			return err
+		}
+		return d.setAdminState(ctx, cell.ECGI, edit.Cell.Locked, edit.Cell.Barred)
+	}
+
+	node, err := d.nodeStore.Get(ctx, edit.Node.EnbID)
+	if err != nil {
+		return err
+	}
+	preImage := *node
+	tx.nodes = append(tx.nodes, &preImage)
+
+	updated := *node
+	if edit.Node.Controllers != nil {
+		updated.Controllers = edit.Node.Controllers
+	}
+	if edit.Node.Cells != nil {
+		updated.Cells = edit.Node.Cells
+	}
+	return d.nodeStore.Update(ctx, &updated)
+}
+
+// setAdminState changes the administrative state via the cell attributes so that the UEs
+// are handed over from a locked cell the same way as for the other northbound interfaces
+func (d *Datastore) setAdminState(ctx context.Context, ecgi types.ECGI, locked *bool, barred *bool) error {
+	if locked != nil {
+		if err := d.metricStore.Set(ctx, uint64(ecgi), mobility.LockedAttribute, toInt32(*locked)); err != nil {
+			return err
+		}
+	}
+	if barred != nil {
+		if err := d.metricStore.Set(ctx, uint64(ecgi), mobility.BarredAttribute, toInt32(*barred)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// revert restores the pre-images of the nodes and cells modified by the transaction in reverse order
+func (d *Datastore) revert(ctx context.Context, tx *Transaction) {
+	for i := len(tx.cells) - 1; i >= 0; i-- {
+		cell := *tx.cells[i]
+		if err := d.cellStore.Update(ctx, &cell); err != nil {
+			log.Warn(err)
+			continue
+		}
+		if err := d.setAdminState(ctx, cell.ECGI, &cell.Locked, &cell.Barred); err != nil {
+			log.Warn(err)
+		}
+	}
+	for i := len(tx.nodes) - 1; i >= 0; i-- {
+		node := *tx.nodes[i]
+		if err := d.nodeStore.Update(ctx, &node); err != nil {
+			log.Warn(err)
+		}
+	}
+}
+
+func toInt32(b bool) int32 {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,95 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package o1
+
+import (
+	"context"
+	"io/ioutil"
+	"testing"
+
+	"github.com/onosproject/onos-api/go/onos/ransim/types"
+	"github.com/onosproject/ran-simulator/pkg/mobility"
+	"github.com/onosproject/ran-simulator/pkg/model"
+	"github.com/onosproject/ran-simulator/pkg/store/cells"
+	"github.com/onosproject/ran-simulator/pkg/store/metrics"
+	"github.com/onosproject/ran-simulator/pkg/store/nodes"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v2"
+)
+
+func TestDatastore(t *testing.T) {
+	m := model.Model{}
+	bytes, err := ioutil.ReadFile("../model/test.yaml")
+	assert.NoError(t, err)
+	err = yaml.Unmarshal(bytes, &m)
+	assert.NoError(t, err)
+
+	ctx := context.Background()
+	nodeStore := nodes.NewNodeRegistry(m.Nodes)
+	cellStore := cells.NewCellRegistry(m.Cells, nodeStore)
+	metricStore := metrics.NewMetricsStore()
+	ds := NewDatastore(nodeStore, cellStore, metricStore)
+
+	config, err := ds.Get(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, config.Cells, 4)
+
+	ecgi1 := types.ECGI(84325717505)
+	ecgi2 := types.ECGI(84325717506)
+	txPower := 30.0
+	locked := true
+	tilt := 6.0
+	tx1, err := ds.Commit(ctx, []*Edit{
+		{Operation: Merge, Cell: &CellConfig{ECGI: ecgi1, TxPowerDB: &txPower, Locked: &locked, ElectricalTilt: &tilt}},
+	})
+	assert.NoError(t, err)
+	cell1, err := ds.GetCell(ctx, ecgi1)
+	assert.NoError(t, err)
+	assert.Equal(t, 30.0, *cell1.TxPowerDB)
+	assert.Equal(t, 6.0, *cell1.ElectricalTilt)
+	value, ok := metricStore.Get(ctx, uint64(ecgi1), mobility.LockedAttribute)
+	assert.True(t, ok)
+	assert.Equal(t, int32(1), value)
+
+	// Invalid transactions are rejected as a whole
+	maxUEs := uint32(10)
+	_, err = ds.Commit(ctx, []*Edit{
+		{Operation: Merge, Cell: &CellConfig{ECGI: ecgi2, MaxUEs: &maxUEs}},
+		{Operation: Merge, Cell: &CellConfig{ECGI: 1234}},
+	})
+	assert.Error(t, err)
+	_, err = ds.Commit(ctx, []*Edit{{Operation: "delete", Cell: &CellConfig{ECGI: ecgi2}}})
+	assert.Error(t, err)
+	invalidTilt := 120.0
+	_, err = ds.Commit(ctx, []*Edit{{Operation: Merge, Cell: &CellConfig{ECGI: ecgi2, MechanicalTilt: &invalidTilt}}})
+	assert.Error(t, err)
+	cell2, err := ds.GetCell(ctx, ecgi2)
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(0), *cell2.MaxUEs)
+
+	_, err = ds.Commit(ctx, []*Edit{
+		{Operation: Merge, Cell: &CellConfig{ECGI: ecgi2, MaxUEs: &maxUEs, Neighbors: []types.ECGI{ecgi1}}},
+	})
+	assert.NoError(t, err)
+	assert.Len(t, ds.Transactions(), 2)
+
+	// Rolling back the first transaction reverts the later ones as well
+	err = ds.Rollback(ctx, tx1.ID)
+	assert.NoError(t, err)
+	assert.Len(t, ds.Transactions(), 0)
+	cell1, err = ds.GetCell(ctx, ecgi1)
+	assert.NoError(t, err)
+	assert.Equal(t, 0.0, *cell1.TxPowerDB)
+	assert.Equal(t, 0.0, *cell1.ElectricalTilt)
+	cell2, err = ds.GetCell(ctx, ecgi2)
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(0), *cell2.MaxUEs)
+	assert.Len(t, cell2.Neighbors, 0)
+	value, _ = metricStore.Get(ctx, uint64(ecgi1), mobility.LockedAttribute)
+	assert.Equal(t, int32(0), value)
+
+	err = ds.Rollback(ctx, tx1.ID)
+	assert.Error(t, err)
+}
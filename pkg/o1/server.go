@@ -0,0 +1,181 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package o1
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/onosproject/onos-api/go/onos/ransim/types"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+)
+
+const (
+	dataPath         = "/restconf/data"
+	cellsPath        = dataPath + "/cells/"
+	nodesPath        = dataPath + "/nodes/"
+	commitPath       = "/restconf/operations/commit"
+	rollbackPath     = "/restconf/operations/rollback"
+	transactionsPath = "/restconf/operations/transactions"
+)
+
+// CommitRequest is the body of the commit operation
+type CommitRequest struct {
+	Edits []*Edit `json:"edits"`
+}
+
+// RollbackRequest is the body of the rollback operation
+type RollbackRequest struct {
+	ID uint64 `json:"id"`
+}
+
+// Server is a RESTCONF-style HTTP server exposing the configuration datastore
+type Server struct {
+	datastore *Datastore
+	server    *http.Server
+}
+
+// NewServer creates a new O1 configuration server listening on the specified port
+func NewServer(datastore *Datastore, port int) *Server {
+	s := &Server{
+		datastore: datastore,
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc(dataPath, s.getConfig)
+	mux.HandleFunc(cellsPath, s.getCell)
+	mux.HandleFunc(nodesPath, s.getNode)
+	mux.HandleFunc(commitPath, s.commit)
+	mux.HandleFunc(rollbackPath, s.rollback)
+	mux.HandleFunc(transactionsPath, s.transactions)
+	s.server = &http.Server{
+		Addr:    fmt.Sprintf(":%d", port),
+		Handler: mux,
+	}
+	return s
+}
+
+// Use wraps the handler of the O1 server with the given middleware before it serves
+func (s *Server) Use(middleware func(http.Handler) http.Handler) {
+	s.server.Handler = middleware(s.server.Handler)
+}
+
+// Serve starts serving the O1 requests in the background
+func (s *Server) Serve() {
+	go func() {
+		log.Info("Started O1 server on ", s.server.Addr)
+		if err := s.server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Error(err)
+		}
+	}()
+}
+
+// Stop stops the O1 server
+func (s *Server) Stop() {
+	if err := s.server.Shutdown(context.Background()); err != nil {
+		log.Error(err)
+	}
+}
+
+func (s *Server) getConfig(w http.ResponseWriter, r *http.Request) {
+	if !allowMethod(w, r, http.MethodGet) {
+		return
+	}
+	config, err := s.datastore.Get(r.Context())
+	writeResponse(w, config, err)
+}
+
+func (s *Server) getCell(w http.ResponseWriter, r *http.Request) {
+	if !allowMethod(w, r, http.MethodGet) {
+		return
+	}
+	id, err := strconv.ParseUint(strings.TrimPrefix(r.URL.Path, cellsPath), 10, 64)
+	if err != nil {
+		writeError(w, errors.New(errors.Invalid, "invalid ECGI"))
+		return
+	}
+	cell, err := s.datastore.GetCell(r.Context(), types.ECGI(id))
+	writeResponse(w, cell, err)
+}
+
+func (s *Server) getNode(w http.ResponseWriter, r *http.Request) {
+	if !allowMethod(w, r, http.MethodGet) {
+		return
+	}
+	id, err := strconv.ParseUint(strings.TrimPrefix(r.URL.Path, nodesPath), 10, 32)
+	if err != nil {
+		writeError(w, errors.New(errors.Invalid, "invalid eNB ID"))
+		return
+	}
+	node, err := s.datastore.GetNode(r.Context(), types.EnbID(id))
+	writeResponse(w, node, err)
+}
+
+func (s *Server) commit(w http.ResponseWriter, r *http.Request) {
+	if !allowMethod(w, r, http.MethodPost) {
+		return
+	}
+	request := &CommitRequest{}
+	if err := json.NewDecoder(r.Body).Decode(request); err != nil {
+		writeError(w, errors.New(errors.Invalid, "malformed commit request: %v", err))
+		return
+	}
+	tx, err := s.datastore.Commit(r.Context(), request.Edits)
+	writeResponse(w, tx, err)
+}
+
+func (s *Server) rollback(w http.ResponseWriter, r *http.Request) {
+	if !allowMethod(w, r, http.MethodPost) {
+		return
+	}
+	request := &RollbackRequest{}
+	if err := json.NewDecoder(r.Body).Decode(request); err != nil {
+		writeError(w, errors.New(errors.Invalid, "malformed rollback request: %v", err))
+		return
+	}
+	err := s.datastore.Rollback(r.Context(), request.ID)
+	writeResponse(w, request, err)
+}
+
+func (s *Server) transactions(w http.ResponseWriter, r *http.Request) {
+	if !allowMethod(w, r, http.MethodGet) {
+		return
+	}
+	writeResponse(w, s.datastore.Transactions(), nil)
+}
+
+func allowMethod(w http.ResponseWriter, r *http.Request, method string) bool {
+	if r.Method != method {
+		w.Header().Set("Allow", method)
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return false
+	}
+	return true
+}
+
+func writeResponse(w http.ResponseWriter, value interface{}, err error) {
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(value); err != nil {
+		log.Warn(err)
+	}
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.IsNotFound(err):
+		status = http.StatusNotFound
+	case errors.IsInvalid(err):
+		status = http.StatusBadRequest
+	}
+	http.Error(w, err.Error(), status)
+}
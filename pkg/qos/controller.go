@@ -0,0 +1,166 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package qos
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/onosproject/onos-api/go/onos/ransim/types"
+	"github.com/onosproject/onos-lib-go/pkg/logging"
+	"github.com/onosproject/ran-simulator/pkg/model"
+	"github.com/onosproject/ran-simulator/pkg/store/event"
+	"github.com/onosproject/ran-simulator/pkg/store/metrics"
+	"github.com/onosproject/ran-simulator/pkg/store/ues"
+)
+
+var log = logging.GetLogger("qos")
+
+// Per-cell DRB counters maintained in the metrics store and reported via KPM, along with their per-5QI subcounters
+const (
+	// DRBEstabAtt number of DRB setup attempts
+	DRBEstabAtt = "DRB.EstabAtt.Tot"
+	// DRBEstabSucc number of successfully established DRBs
+	DRBEstabSucc = "DRB.EstabSucc.Tot"
+	// DRBRelActNbr number of released DRBs
+	DRBRelActNbr = "DRB.RelActNbr.Tot"
+)
+
+// standardizedFiveQIs lists the standardized 5QI values of 3GPP TS 23.501 whose per-5QI subcounters are reported via KPM
+var standardizedFiveQIs = []int32{1, 2, 3, 4, 5, 6, 7, 8, 9, 65, 66, 67, 69, 70, 71, 72, 73, 74, 75, 76, 79, 80, 82, 83, 84, 85, 86}
+
+// PerFiveQI returns the name of the per-5QI subcounter of the specified total DRB counter, e.g. DRB.EstabAtt.9
+func PerFiveQI(name string, fiveQI int32) string {
+	return fmt.Sprintf("%s%d", strings.TrimSuffix(name, "Tot"), fiveQI)
+}
+
+// FiveQI returns the 5QI of the QoS flows mapped to the DRB, the default 5QI if none is mapped
+func FiveQI(drb *model.DRB) int32 {
+	if len(drb.QoSFlows) == 0 {
+		return defaultFiveQI
+	}
+	return drb.QoSFlows[0].FiveQI
+}
+
+// Counters lists the names of the per-5QI subcounters of the standardized 5QIs; the subcounters of other 5QIs are
+// maintained in the metrics store as well but not reported via KPM
+func Counters() []string {
+	counters := make([]string, 0, 3*len(standardizedFiveQIs))
+	for _, name := range []string{DRBEstabAtt, DRBEstabSucc, DRBRelActNbr} {
+		for _, fiveQI := range standardizedFiveQIs {
+			counters = append(counters, PerFiveQI(name, fiveQI))
+		}
+	}
+	return counters
+}
+
+// Controller establishes, modifies and releases UE data radio bearers in response to
+// DRB attributes being set or deleted on UE entities in the metrics store
+type Controller struct {
+	ueStore     ues.Store
+	metricStore metrics.Store
+	cancel      context.CancelFunc
+}
+
+// NewController creates a new DRB controller
+func NewController(ueStore ues.Store, metricStore metrics.Store) *Controller {
+	return &Controller{
+		ueStore:     ueStore,
+		metricStore: metricStore,
+	}
+}
+
+// Start starts processing the metric events
+func (c *Controller) Start() error {
+	ctx, cancel := context.WithCancel(context.Background())
+	ch := make(chan event.Event)
+	if err := c.metricStore.Watch(ctx, ch); err != nil {
+		cancel()
+		return err
+	}
+	c.cancel = cancel
+	go c.processMetricEvents(ch)
+	return nil
+}
+
+// Stop stops processing the metric events
+func (c *Controller) Stop() {
+	if c.cancel != nil {
+		c.cancel()
+	}
+}
+
+func (c *Controller) processMetricEvents(ch <-chan event.Event) {
+	ctx := context.Background()
+	for metricEvent := range ch {
+		key := metricEvent.Key.(metrics.Key)
+		if !IsDRBAttribute(key.Name) {
+			continue
+		}
+		imsi := types.IMSI(key.EntityID)
+		drbID, err := ParseDRBID(key.Name)
+		if err != nil {
+			log.Warn(err)
+			continue
+		}
+		switch metricEvent.Type.(metrics.MetricEvent) {
+		case metrics.Updated:
+			err = c.setupDRB(ctx, imsi, drbID, fmt.Sprintf("%v", metricEvent.Value))
+		case metrics.Deleted:
+			err = c.releaseDRB(ctx, imsi, drbID)
+		}
+		if err != nil {
+			log.Warnf("Unable to apply DRB %d configuration for UE %d: %v", drbID, imsi, err)
+		}
+	}
+}
+
+// setupDRB establishes a new DRB or modifies an existing one; malformed specifications are rejected before counting
+// the setup attempt
+func (c *Controller) setupDRB(ctx context.Context, imsi types.IMSI, drbID int32, spec string) error {
+	drb, err := ParseDRB(drbID, spec)
+	if err != nil {
+		return err
+	}
+	ue, err := c.ueStore.Get(ctx, imsi)
+	if err != nil {
+		return err
+	}
+	if _, ok := ue.GetDRB(drbID); ok {
+		log.Debugf("Modifying DRB %d of UE %d", drbID, imsi)
+		return c.ueStore.UpdateDRB(ctx, imsi, drb)
+	}
+
+	ecgi := uint64(ue.Cell.ECGI)
+	c.increment(ctx, ecgi, DRBEstabAtt, FiveQI(drb))
+	log.Debugf("Establishing DRB %d for UE %d", drbID, imsi)
+	if err := c.ueStore.AddDRB(ctx, imsi, drb); err != nil {
+		return err
+	}
+	c.increment(ctx, ecgi, DRBEstabSucc, FiveQI(drb))
+	return nil
+}
+
+// releaseDRB releases the specified DRB
+func (c *Controller) releaseDRB(ctx context.Context, imsi types.IMSI, drbID int32) error {
+	log.Debugf("Releasing DRB %d of UE %d", drbID, imsi)
+	drb, err := c.ueStore.DeleteDRB(ctx, imsi, drbID)
+	if err != nil {
+		return err
+	}
+	ue, err := c.ueStore.Get(ctx, imsi)
+	if err != nil {
+		return err
+	}
+	c.increment(ctx, uint64(ue.Cell.ECGI), DRBRelActNbr, FiveQI(drb))
+	return nil
+}
+
+// increment increments the specified total counter of the entity along with its subcounter of the 5QI
+func (c *Controller) increment(ctx context.Context, entityID uint64, name string, fiveQI int32) {
+	_, _ = c.metricStore.Add(ctx, entityID, name, 1)
+	_, _ = c.metricStore.Add(ctx, entityID, PerFiveQI(name, fiveQI), 1)
+}
@@ -0,0 +1,62 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package qos
+
+import (
+	"context"
+	"io/ioutil"
+	"testing"
+
+	"github.com/onosproject/ran-simulator/pkg/model"
+	"github.com/onosproject/ran-simulator/pkg/store/cells"
+	"github.com/onosproject/ran-simulator/pkg/store/metrics"
+	"github.com/onosproject/ran-simulator/pkg/store/nodes"
+	"github.com/onosproject/ran-simulator/pkg/store/ues"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v2"
+)
+
+func TestController(t *testing.T) {
+	ctx := context.Background()
+	m := model.Model{}
+	bytes, err := ioutil.ReadFile("../model/test.yaml")
+	assert.NoError(t, err)
+	assert.NoError(t, yaml.Unmarshal(bytes, &m))
+	ueStore := ues.NewUERegistry(1, cells.NewCellRegistry(m.Cells, nodes.NewNodeRegistry(m.Nodes)))
+	metricStore := metrics.NewMetricsStore()
+	controller := NewController(ueStore, metricStore)
+	ue := ueStore.ListAllUEs(ctx)[0]
+	ecgi := uint64(ue.Cell.ECGI)
+	count := func(name string) uint64 {
+		value, _ := metricStore.Get(ctx, ecgi, name)
+		c, _ := value.(uint64)
+		return c
+	}
+
+	// Malformed specifications are rejected without counting a setup attempt
+	assert.Error(t, controller.setupDRB(ctx, ue.IMSI, 1, "arp=16"))
+	assert.Error(t, controller.setupDRB(ctx, ue.IMSI, 33, ""))
+	assert.Equal(t, uint64(0), count(DRBEstabAtt))
+
+	// Setups and releases are counted in total and per 5QI; modifications are not counted
+	assert.NoError(t, controller.setupDRB(ctx, ue.IMSI, 1, "5qi=1"))
+	assert.NoError(t, controller.setupDRB(ctx, ue.IMSI, 2, ""))
+	assert.NoError(t, controller.setupDRB(ctx, ue.IMSI, 1, "5qi=1,arp=2"))
+	assert.Equal(t, uint64(2), count(DRBEstabAtt))
+	assert.Equal(t, uint64(2), count(DRBEstabSucc))
+	assert.Equal(t, uint64(1), count(PerFiveQI(DRBEstabSucc, 1)))
+	assert.Equal(t, uint64(1), count(PerFiveQI(DRBEstabSucc, 9)))
+
+	assert.NoError(t, controller.releaseDRB(ctx, ue.IMSI, 1))
+	assert.Equal(t, uint64(1), count(DRBRelActNbr))
+	assert.Equal(t, uint64(1), count(PerFiveQI(DRBRelActNbr, 1)))
+	assert.Equal(t, uint64(0), count(PerFiveQI(DRBRelActNbr, 9)))
+	ue, _ = ueStore.Get(ctx, ue.IMSI)
+	assert.Len(t, ue.DRBs, 1)
+
+	assert.Contains(t, Counters(), "DRB.EstabAtt.1")
+	assert.Contains(t, Counters(), "DRB.RelActNbr.9")
+	assert.NotContains(t, Counters(), DRBEstabAtt)
+}
@@ -0,0 +1,129 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package qos
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/onosproject/onos-api/go/onos/ransim/types"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/onosproject/ran-simulator/pkg/model"
+)
+
+const (
+	// DRBAttributePrefix is the prefix of UE attribute names carrying DRB configuration, e.g. "drb.1"
+	DRBAttributePrefix = "drb."
+	// UEDRBParameterPrefix is the prefix of RC control parameter names targeting a DRB of a UE, e.g. "ue.1234.drb.1"
+	UEDRBParameterPrefix = "ue."
+
+	minDRBID int32 = 1
+	maxDRBID int32 = 32
+
+	defaultFiveQI int32 = 9
+	defaultARP    int32 = 15
+)
+
+// IsDRBAttribute returns true if the specified attribute name carries DRB configuration
+func IsDRBAttribute(name string) bool {
+	return strings.HasPrefix(name, DRBAttributePrefix)
+}
+
+// ParseDRBID extracts the DRB ID from the specified attribute name
+func ParseDRBID(name string) (int32, error) {
+	id, err := strconv.ParseInt(strings.TrimPrefix(name, DRBAttributePrefix), 10, 32)
+	if err != nil {
+		return 0, errors.New(errors.Invalid, "invalid DRB attribute name %s", name)
+	}
+	return int32(id), nil
+}
+
+// DRBAttribute returns the name of the UE attribute carrying the configuration of the specified DRB
+func DRBAttribute(drbID int32) string {
+	return fmt.Sprintf("%s%d", DRBAttributePrefix, drbID)
+}
+
+// UEDRBParameter returns the name of the RC control parameter targeting the specified DRB of the UE
+func UEDRBParameter(imsi types.IMSI, drbID int32) string {
+	return fmt.Sprintf("%s%d.%s", UEDRBParameterPrefix, imsi, DRBAttribute(drbID))
+}
+
+// ParseUEDRBParameter extracts the IMSI of the UE and the DRB ID from the name of an RC control parameter targeting a
+// DRB of a UE; ok is false if the name does not target a DRB
+func ParseUEDRBParameter(name string) (imsi types.IMSI, drbID int32, ok bool) {
+	if !strings.HasPrefix(name, UEDRBParameterPrefix) {
+		return 0, 0, false
+	}
+	parts := strings.SplitN(strings.TrimPrefix(name, UEDRBParameterPrefix), ".", 2)
+	if len(parts) != 2 || !IsDRBAttribute(parts[1]) {
+		return 0, 0, false
+	}
+	id, err := strconv.ParseUint(parts[0], 10, 64)
+	if err != nil {
+		return 0, 0, false
+	}
+	drbID, err = ParseDRBID(parts[1])
+	if err != nil {
+		return 0, 0, false
+	}
+	return types.IMSI(id), drbID, true
+}
+
+// ParseDRB creates a DRB with a single QoS flow from the specified comma-separated
+// list of key=value pairs, e.g. "qfi=1,fiveQI=1,arp=2,gbr=true"
+func ParseDRB(drbID int32, spec string) (*model.DRB, error) {
+	if drbID < minDRBID || drbID > maxDRBID {
+		return nil, errors.New(errors.Invalid, "DRB ID must be in range [%d, %d]", minDRBID, maxDRBID)
+	}
+	flow := &model.QoSFlow{
+		QFI:    drbID,
+		FiveQI: defaultFiveQI,
+		ARP:    defaultARP,
+	}
+	for _, pair := range strings.Split(spec, ",") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+		kv := strings.SplitN(pair, "=", 2)
+		if len(kv) != 2 {
+			return nil, errors.New(errors.Invalid, "malformed DRB parameter %s", pair)
+		}
+		key, value := strings.TrimSpace(kv[0]), strings.TrimSpace(kv[1])
+		var err error
+		switch strings.ToLower(key) {
+		case "qfi":
+			flow.QFI, err = parseInt32(value)
+		case "5qi", "fiveqi":
+			flow.FiveQI, err = parseInt32(value)
+		case "arp":
+			flow.ARP, err = parseInt32(value)
+		case "gbr":
+			flow.GBR, err = strconv.ParseBool(value)
+		default:
+			return nil, errors.New(errors.Invalid, "unknown DRB parameter %s", key)
+		}
+		if err != nil {
+			return nil, errors.New(errors.Invalid, "invalid value for DRB parameter %s: %v", key, err)
+		}
+	}
+
+	if flow.ARP < 1 || flow.ARP > 15 {
+		return nil, errors.New(errors.Invalid, "ARP priority level must be in range [1, 15]")
+	}
+	if flow.FiveQI < 1 || flow.FiveQI > 255 {
+		return nil, errors.New(errors.Invalid, "5QI must be in range [1, 255]")
+	}
+
+	return &model.DRB{
+		ID:       drbID,
+		QoSFlows: []*model.QoSFlow{flow},
+	}, nil
+}
+
+func parseInt32(value string) (int32, error) {
+	i, err := strconv.ParseInt(value, 10, 32)
+	return int32(i), err
+}
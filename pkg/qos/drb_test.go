@@ -0,0 +1,56 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package qos
+
+import (
+	"testing"
+
+	"github.com/onosproject/onos-api/go/onos/ransim/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseDRB(t *testing.T) {
+	id, err := ParseDRBID("drb.3")
+	assert.NoError(t, err)
+	assert.Equal(t, int32(3), id)
+	_, err = ParseDRBID("drb.x")
+	assert.Error(t, err)
+
+	drb, err := ParseDRB(3, "")
+	assert.NoError(t, err)
+	assert.Equal(t, int32(3), drb.ID)
+	assert.Equal(t, int32(3), drb.QoSFlows[0].QFI)
+	assert.Equal(t, defaultFiveQI, drb.QoSFlows[0].FiveQI)
+	assert.Equal(t, defaultARP, drb.QoSFlows[0].ARP)
+
+	drb, err = ParseDRB(1, "qfi=5, 5qi=1, arp=2, gbr=true")
+	assert.NoError(t, err)
+	assert.Equal(t, int32(5), drb.QoSFlows[0].QFI)
+	assert.Equal(t, int32(1), drb.QoSFlows[0].FiveQI)
+	assert.Equal(t, int32(2), drb.QoSFlows[0].ARP)
+	assert.True(t, drb.QoSFlows[0].GBR)
+
+	_, err = ParseDRB(1, "arp=16")
+	assert.Error(t, err)
+	_, err = ParseDRB(1, "foo=1")
+	assert.Error(t, err)
+	_, err = ParseDRB(1, "gbr")
+	assert.Error(t, err)
+	_, err = ParseDRB(33, "")
+	assert.Error(t, err)
+}
+
+func TestParseUEDRBParameter(t *testing.T) {
+	assert.Equal(t, "ue.1234.drb.2", UEDRBParameter(1234, 2))
+	imsi, drbID, ok := ParseUEDRBParameter("ue.1234.drb.2")
+	assert.True(t, ok)
+	assert.Equal(t, types.IMSI(1234), imsi)
+	assert.Equal(t, int32(2), drbID)
+
+	for _, name := range []string{"pci", "drb.2", "ue.1234", "ue.x.drb.2", "ue.1234.drb.x", "ue.1234.pci"} {
+		_, _, ok = ParseUEDRBParameter(name)
+		assert.False(t, ok, name)
+	}
+}
@@ -0,0 +1,57 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package radio
+
+import (
+	"math"
+
+	"github.com/onosproject/ran-simulator/pkg/model"
+)
+
+// Antenna defaults and pattern parameters as per 3GPP TR 36.814 Table A.2.1.1-2
+const (
+	// maxAntennaGain maximum antenna gain in dBi
+	maxAntennaGain = 15.0
+	// frontToBackRatio maximum horizontal attenuation in dB
+	frontToBackRatio = 25.0
+	// sideLobeLevel maximum vertical attenuation in dB
+	sideLobeLevel = 20.0
+
+	defaultHeight     = 30.0
+	defaultHBeamwidth = 70.0
+	defaultVBeamwidth = 10.0
+
+	// ueHeight UE antenna height above ground in meters
+	ueHeight = 1.5
+)
+
+// AntennaGain returns the gain in dBi of the sector antenna towards the given location, using the 3GPP TR 36.814
+// horizontal and vertical antenna patterns; the horizontal pattern is centered on the middle of the sector arc, whereas
+// sectors without a proper arc are served by omni-directional antennas. The vertical pattern is centered on the sum
+// of the electrical and mechanical downtilt; the elevation angle accounts for the altitude of the location, so that
+// UEs flying above the antenna are only reached by its side lobes.
+func AntennaGain(sector model.Sector, location model.Coordinate) float64 {
+	horizontal := 0.0
+	if sector.Arc > 0 && sector.Arc < 360 {
+		boresight := float64(sector.Azimuth) + float64(sector.Arc)/2
+		phi := math.Mod(Bearing(sector.Center, location)-boresight+540, 360) - 180
+		horizontal = -math.Min(12*math.Pow(phi/orDefault(sector.HBeamwidth, defaultHBeamwidth), 2), frontToBackRatio)
+	}
+
+	height := orDefault(sector.Height, defaultHeight)
+	distance := math.Max(Distance(sector.Center, location), minDistance)
+	theta := math.Atan2(height-ueHeight-location.Alt, distance) * 180 / math.Pi
+	tilt := sector.ElectricalTilt + sector.MechanicalTilt
+	vertical := -math.Min(12*math.Pow((theta-tilt)/orDefault(sector.VBeamwidth, defaultVBeamwidth), 2), sideLobeLevel)
+
+	return maxAntennaGain - math.Min(-(horizontal+vertical), frontToBackRatio)
+}
+
+func orDefault(value float64, defaultValue float64) float64 {
+	if value <= 0 {
+		return defaultValue
+	}
+	return value
+}
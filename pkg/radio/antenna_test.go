@@ -0,0 +1,44 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package radio
+
+import (
+	"testing"
+
+	"github.com/onosproject/ran-simulator/pkg/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAntennaGain(t *testing.T) {
+	center := model.Coordinate{Lat: 45, Lng: 30}
+	sector := model.Sector{Center: center, Azimuth: 30, Arc: 120, Height: 30}
+
+	// Boresight of the sector is east; locations 1 km away
+	east := model.Coordinate{Lat: 45, Lng: 30.0127}
+	north := model.Coordinate{Lat: 45.009, Lng: 30}
+	west := model.Coordinate{Lat: 45, Lng: 29.9873}
+
+	boresight := AntennaGain(sector, east)
+	assert.InDelta(t, maxAntennaGain, boresight, 0.5)
+	assert.InDelta(t, maxAntennaGain-12*(90.0/70)*(90.0/70), AntennaGain(sector, north), 0.5)
+	assert.InDelta(t, maxAntennaGain-frontToBackRatio, AntennaGain(sector, west), 0.01)
+
+	// Downtilt steers the beam towards the ground, favoring nearby locations over distant ones
+	near := model.Coordinate{Lat: 45, Lng: 30.0025}
+	tilted := sector
+	tilted.ElectricalTilt = 4
+	tilted.MechanicalTilt = 2
+	assert.Less(t, AntennaGain(tilted, east), boresight)
+	assert.Greater(t, AntennaGain(tilted, near), AntennaGain(sector, near))
+
+	// UAVs flying above the antenna are only reached by its side lobes
+	above := east
+	above.Alt = 300
+	assert.Less(t, AntennaGain(sector, above), boresight-sideLobeLevel+0.5)
+
+	// Omni-directional antennas have no horizontal pattern
+	omni := model.Sector{Center: center, Arc: 360}
+	assert.InDelta(t, AntennaGain(omni, east), AntennaGain(omni, west), 0.01)
+}
@@ -0,0 +1,169 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package radio
+
+import (
+	"math"
+
+	"github.com/onosproject/ran-simulator/pkg/model"
+)
+
+// Kinds of coverage problems reported by the coverage analysis
+const (
+	// CoverageHole no cell is received above the coverage threshold
+	CoverageHole = "hole"
+	// CoverageOverlap too many cells are received within the overlap margin of the best cell
+	CoverageOverlap = "overlap"
+)
+
+const (
+	metersPerDegree = 2 * math.Pi * earthRadius / 360
+
+	maxRasterSquares = 250000
+)
+
+// CoverageParams parameterizes the coverage analysis
+type CoverageParams struct {
+	// Resolution is the edge length of the raster squares in meters; it is coarsened for vast areas so that the
+	// raster comprises at most 250000 squares
+	Resolution float64
+	// Margin is the distance in meters by which the analyzed area extends beyond the outermost cell sites
+	Margin float64
+	// Threshold is the minimum RSRP in dBm for a location to be covered by a cell
+	Threshold float64
+	// OverlapMargin is the margin in dB below the best cell within which other covering cells count as overlapping
+	OverlapMargin float64
+	// MaxOverlap is the maximum number of overlapping cells, including the best cell, before the overlap is excessive
+	MaxOverlap int
+}
+
+// DefaultCoverageParams returns the default coverage analysis parameters
+func DefaultCoverageParams() CoverageParams {
+	return CoverageParams{
+		Resolution:    100,
+		Margin:        1000,
+		Threshold:     -110,
+		OverlapMargin: 6,
+		MaxOverlap:    3,
+	}
+}
+
+// FeatureCollection is a GeoJSON feature collection
+type FeatureCollection struct {
+	Type     string     `json:"type"`
+	Features []*Feature `json:"features"`
+}
+
+// Feature is a GeoJSON feature
+type Feature struct {
+	Type       string                 `json:"type"`
+	Geometry   Geometry               `json:"geometry"`
+	Properties map[string]interface{} `json:"properties"`
+}
+
+// Geometry is a GeoJSON polygon geometry; positions are given as longitude and latitude
+type Geometry struct {
+	Type        string         `json:"type"`
+	Coordinates [][][2]float64 `json:"coordinates"`
+}
+
+// AnalyzeCoverage rasterizes the area spanned by the cells and reports the coverage holes and the regions of excessive
+// overlap as GeoJSON polygons; horizontally adjacent raster squares with the same problem are merged into one polygon.
+// Cells out of service are ignored.
+func AnalyzeCoverage(cells []*model.Cell, params CoverageParams) *FeatureCollection {
+	collection := &FeatureCollection{Type: "FeatureCollection", Features: []*Feature{}}
+	inService := make([]*model.Cell, 0, len(cells))
+	for _, cell := range cells {
+		if cell.InService() {
+			inService = append(inService, cell)
+		}
+	}
+	if len(inService) == 0 || params.Resolution <= 0 {
+		return collection
+	}
+
+	minLat, maxLat, minLng, maxLng := bounds(inService)
+	metersPerLngDegree := metersPerDegree * math.Cos((minLat+maxLat)/2*math.Pi/180)
+	minLat -= params.Margin / metersPerDegree
+	maxLat += params.Margin / metersPerDegree
+	minLng -= params.Margin / metersPerLngDegree
+	maxLng += params.Margin / metersPerLngDegree
+
+	// Coarsen the raster of vast areas to bound the analysis effort
+	resolution := params.Resolution
+	squares := (maxLat - minLat) * metersPerDegree * (maxLng - minLng) * metersPerLngDegree / (resolution * resolution)
+	if squares > maxRasterSquares {
+		resolution *= math.Sqrt(squares / maxRasterSquares)
+	}
+	latStep := resolution / metersPerDegree
+	lngStep := resolution / metersPerLngDegree
+
+	for lat := minLat; lat < maxLat; lat += latStep {
+		kind, start := "", 0.0
+		for lng := minLng; lng < maxLng+lngStep; lng += lngStep {
+			current := ""
+			if lng < maxLng {
+				current = classify(inService, model.Coordinate{Lat: lat + latStep/2, Lng: lng + lngStep/2}, params)
+			}
+			if current == kind {
+				continue
+			}
+			if kind != "" {
+				collection.Features = append(collection.Features, rectangle(kind, lat, start, lat+latStep, lng))
+			}
+			kind, start = current, lng
+		}
+	}
+	return collection
+}
+
+// classify returns the coverage problem at the given location, if any
+func classify(cells []*model.Cell, location model.Coordinate, params CoverageParams) string {
+	rsrps := make([]float64, 0, len(cells))
+	best := math.Inf(-1)
+	for _, cell := range cells {
+		rsrp := RSRP(cell, location)
+		rsrps = append(rsrps, rsrp)
+		best = math.Max(best, rsrp)
+	}
+	if best < params.Threshold {
+		return CoverageHole
+	}
+	overlapping := 0
+	for _, rsrp := range rsrps {
+		if rsrp >= params.Threshold && rsrp >= best-params.OverlapMargin {
+			overlapping++
+		}
+	}
+	if params.MaxOverlap > 0 && overlapping > params.MaxOverlap {
+		return CoverageOverlap
+	}
+	return ""
+}
+
+func bounds(cells []*model.Cell) (minLat float64, maxLat float64, minLng float64, maxLng float64) {
+	minLat, minLng = math.Inf(1), math.Inf(1)
+	maxLat, maxLng = math.Inf(-1), math.Inf(-1)
+	for _, cell := range cells {
+		minLat = math.Min(minLat, cell.Sector.Center.Lat)
+		maxLat = math.Max(maxLat, cell.Sector.Center.Lat)
+		minLng = math.Min(minLng, cell.Sector.Center.Lng)
+		maxLng = math.Max(maxLng, cell.Sector.Center.Lng)
+	}
+	return minLat, maxLat, minLng, maxLng
+}
+
+func rectangle(kind string, minLat float64, minLng float64, maxLat float64, maxLng float64) *Feature {
+	return &Feature{
+		Type: "Feature",
+		Geometry: Geometry{
+			Type: "Polygon",
+			Coordinates: [][][2]float64{{
+				{minLng, minLat}, {maxLng, minLat}, {maxLng, maxLat}, {minLng, maxLat}, {minLng, minLat},
+			}},
+		},
+		Properties: map[string]interface{}{"kind": kind},
+	}
+}
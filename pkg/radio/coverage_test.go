@@ -0,0 +1,56 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package radio
+
+import (
+	"testing"
+
+	"github.com/onosproject/ran-simulator/pkg/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRSRP(t *testing.T) {
+	cell := &model.Cell{
+		Sector:    model.Sector{Center: model.Coordinate{Lat: 45, Lng: 30}, Azimuth: 0, Arc: 120},
+		TxPowerDB: 11,
+	}
+	assert.InDelta(t, 111319, Distance(model.Coordinate{Lat: 45, Lng: 30}, model.Coordinate{Lat: 46, Lng: 30}), 100)
+	assert.InDelta(t, 90, Bearing(model.Coordinate{Lat: 0, Lng: 30}, model.Coordinate{Lat: 0, Lng: 31}), 0.001)
+
+	// Signal decreases with distance and is attenuated behind the antenna
+	near := RSRP(cell, model.Coordinate{Lat: 45.001, Lng: 30.001})
+	far := RSRP(cell, model.Coordinate{Lat: 45.01, Lng: 30.01})
+	behind := RSRP(cell, model.Coordinate{Lat: 44.99, Lng: 30.01})
+	assert.Greater(t, near, far)
+	assert.Greater(t, far-behind, 10.0)
+}
+
+func TestAnalyzeCoverage(t *testing.T) {
+	omni := func(lat float64, lng float64) *model.Cell {
+		return &model.Cell{Sector: model.Sector{Center: model.Coordinate{Lat: lat, Lng: lng}, Arc: 360}, TxPowerDB: 11}
+	}
+	params := DefaultCoverageParams()
+
+	// Two distant cells leave a coverage hole between them
+	collection := AnalyzeCoverage([]*model.Cell{omni(45, 30), omni(45, 30.1)}, params)
+	assert.Equal(t, "FeatureCollection", collection.Type)
+	assert.NotEmpty(t, collection.Features)
+	for _, feature := range collection.Features {
+		assert.Equal(t, CoverageHole, feature.Properties["kind"])
+		assert.Equal(t, "Polygon", feature.Geometry.Type)
+		assert.Len(t, feature.Geometry.Coordinates[0], 5)
+	}
+	assert.Equal(t, "", classify([]*model.Cell{omni(45, 30)}, model.Coordinate{Lat: 45.001, Lng: 30}, params))
+	assert.Equal(t, CoverageHole, classify([]*model.Cell{omni(45, 30)}, model.Coordinate{Lat: 45, Lng: 30.05}, params))
+
+	// Co-located cells overlap excessively
+	cells := []*model.Cell{omni(45, 30), omni(45, 30), omni(45, 30), omni(45, 30)}
+	assert.Equal(t, CoverageOverlap, classify(cells, model.Coordinate{Lat: 45.001, Lng: 30}, params))
+
+	// Cells out of service provide no coverage
+	outage := omni(45, 30)
+	outage.Outage = true
+	assert.Empty(t, AnalyzeCoverage([]*model.Cell{outage}, params).Features)
+}
@@ -0,0 +1,151 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package radio
+
+import (
+	"math"
+
+	"github.com/onosproject/onos-api/go/onos/ransim/types"
+	"github.com/onosproject/ran-simulator/pkg/model"
+)
+
+const (
+	// defaultFrequency carrier frequency in MHz assumed if neither the frequency nor a known EARFCN is configured
+	defaultFrequency = 2000.0
+	// defaultMmWaveFrequency carrier frequency in MHz assumed for mmWave cells without a configured frequency
+	defaultMmWaveFrequency = 28000.0
+	// aerialHeight altitude in meters above which UEs have a line of sight to the cell sites, as per 3GPP TR 36.777
+	aerialHeight = 22.5
+	// decorrelationDistance edge length in meters of the squares within which the shadowing of a cell is constant
+	decorrelationDistance = 50.0
+)
+
+// PropagationParams parameterizes the path loss of a propagation environment, i.e.
+// Intercept + Slope*log10(d/km) + FrequencyFactor*log10(f/2 GHz), and the standard deviation of the log-normal
+// shadowing in dB
+type PropagationParams struct {
+	Intercept       float64
+	Slope           float64
+	FrequencyFactor float64
+	Shadowing       float64
+}
+
+// environments lists the propagation parameters of the supported environments; the macro environments follow the
+// 3GPP TR 36.942 and Okumura-Hata models, the indoor environment follows the 3GPP TR 36.814 indoor hotspot model and
+// the mmWave environment follows the 3GPP TR 38.901 UMi street canyon line-of-sight model
+var environments = map[model.Environment]PropagationParams{
+	model.Urban:    {Intercept: 128.1, Slope: 37.6, FrequencyFactor: 21, Shadowing: 8},
+	model.Suburban: {Intercept: 115.8, Slope: 37.6, FrequencyFactor: 21, Shadowing: 8},
+	model.Rural:    {Intercept: 102.8, Slope: 34.1, FrequencyFactor: 21, Shadowing: 6},
+	model.Indoor:   {Intercept: 90.3, Slope: 17.3, FrequencyFactor: 20, Shadowing: 3},
+	model.MmWave:   {Intercept: 101.4, Slope: 21, FrequencyFactor: 20, Shadowing: 4},
+}
+
+// Propagation returns the propagation parameters of the environment; unspecified and unknown environments use the
+// urban model without shadowing
+func Propagation(environment model.Environment) PropagationParams {
+	if params, ok := environments[environment]; ok {
+		return params
+	}
+	params := environments[model.Urban]
+	params.Shadowing = 0
+	return params
+}
+
+// eutraBand is the downlink frequency range of an E-UTRA operating band as per 3GPP TS 36.101 Table 5.7.3-1
+type eutraBand struct {
+	low       float64
+	offset    uint32
+	maxEarfcn uint32
+}
+
+var eutraBands = []eutraBand{
+	{low: 2110, offset: 0, maxEarfcn: 599},       // band 1
+	{low: 1930, offset: 600, maxEarfcn: 1199},    // band 2
+	{low: 1805, offset: 1200, maxEarfcn: 1949},   // band 3
+	{low: 2110, offset: 1950, maxEarfcn: 2399},   // band 4
+	{low: 869, offset: 2400, maxEarfcn: 2649},    // band 5
+	{low: 2620, offset: 2750, maxEarfcn: 3449},   // band 7
+	{low: 925, offset: 3450, maxEarfcn: 3799},    // band 8
+	{low: 729, offset: 5010, maxEarfcn: 5179},    // band 12
+	{low: 746, offset: 5180, maxEarfcn: 5279},    // band 13
+	{low: 791, offset: 6150, maxEarfcn: 6449},    // band 20
+	{low: 758, offset: 9210, maxEarfcn: 9659},    // band 28
+	{low: 2570, offset: 37750, maxEarfcn: 38249}, // band 38
+	{low: 2300, offset: 38650, maxEarfcn: 39649}, // band 40
+	{low: 2496, offset: 39650, maxEarfcn: 41589}, // band 41
+	{low: 3400, offset: 41590, maxEarfcn: 43589}, // band 42
+	{low: 3600, offset: 43590, maxEarfcn: 45589}, // band 43
+}
+
+// EarfcnToFrequency returns the downlink carrier frequency in MHz of the given EARFCN, or false if the EARFCN does
+// not belong to a known band
+func EarfcnToFrequency(earfcn uint32) (float64, bool) {
+	for _, band := range eutraBands {
+		if earfcn >= band.offset && earfcn <= band.maxEarfcn {
+			return band.low + 0.1*float64(earfcn-band.offset), true
+		}
+	}
+	return 0, false
+}
+
+// Frequency returns the downlink carrier frequency of the cell in MHz, i.e. the configured frequency, the frequency
+// of its EARFCN or 2 GHz (28 GHz for mmWave cells), in this order
+func Frequency(cell *model.Cell) float64 {
+	if cell.Frequency > 0 {
+		return cell.Frequency
+	}
+	if cell.Environment == model.MmWave {
+		return defaultMmWaveFrequency
+	}
+	if cell.Earfcn > 0 {
+		if frequency, ok := EarfcnToFrequency(cell.Earfcn); ok {
+			return frequency
+		}
+	}
+	return defaultFrequency
+}
+
+// aerial are the propagation parameters of UEs flying above the aerial height, following the 3GPP TR 36.777 UMa-AV
+// line-of-sight model; the distance is the 3D distance to the antenna
+var aerial = PropagationParams{Intercept: 100.0, Slope: 22, FrequencyFactor: 20, Shadowing: 4}
+
+// CellPathLoss returns the path loss in dB between the cell and the given location as per the propagation environment
+// and carrier frequency of the cell, including the shadowing at the location. Locations above the aerial height are
+// in line of sight of the cell regardless of its environment.
+func CellPathLoss(cell *model.Cell, location model.Coordinate) float64 {
+	params := Propagation(cell.Environment)
+	distance := math.Max(Distance(cell.Sector.Center, location), minDistance)
+	if location.Alt > aerialHeight {
+		params = aerial
+		distance = math.Hypot(distance, location.Alt-orDefault(cell.Sector.Height, defaultHeight))
+	}
+	loss := params.Intercept + params.Slope*math.Log10(distance/1000) +
+		params.FrequencyFactor*math.Log10(Frequency(cell)/defaultFrequency)
+	if params.Shadowing > 0 {
+		loss += params.Shadowing * shadowing(cell.ECGI, location)
+	}
+	return loss
+}
+
+// shadowing returns a standard normal variate that is constant within each square of the decorrelation distance and
+// independent between cells and squares, so that the shadowing is random but reproducible
+func shadowing(ecgi types.ECGI, location model.Coordinate) float64 {
+	x := int64(math.Floor(location.Lng * metersPerDegree * math.Cos(location.Lat*math.Pi/180) / decorrelationDistance))
+	y := int64(math.Floor(location.Lat * metersPerDegree / decorrelationDistance))
+	h := splitmix(uint64(ecgi) ^ splitmix(uint64(x)^splitmix(uint64(y))))
+	// Box-Muller transform of two uniform variates in (0, 1]
+	u1 := float64(h>>32+1) / (1 << 32)
+	u2 := float64(h&0xffffffff) / (1 << 32)
+	return math.Sqrt(-2*math.Log(u1)) * math.Cos(2*math.Pi*u2)
+}
+
+// splitmix returns a well-mixed hash of the given value
+func splitmix(x uint64) uint64 {
+	x += 0x9e3779b97f4a7c15
+	x = (x ^ (x >> 30)) * 0xbf58476d1ce4e5b9
+	x = (x ^ (x >> 27)) * 0x94d049bb133111eb
+	return x ^ (x >> 31)
+}
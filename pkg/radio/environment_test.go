@@ -0,0 +1,73 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package radio
+
+import (
+	"math"
+	"testing"
+
+	"github.com/onosproject/ran-simulator/pkg/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFrequency(t *testing.T) {
+	frequency, ok := EarfcnToFrequency(1575)
+	assert.True(t, ok)
+	assert.InDelta(t, 1842.5, frequency, 1e-9)
+	frequency, ok = EarfcnToFrequency(6300)
+	assert.True(t, ok)
+	assert.InDelta(t, 806, frequency, 1e-9)
+	_, ok = EarfcnToFrequency(70000)
+	assert.False(t, ok)
+
+	assert.Equal(t, 3500.0, Frequency(&model.Cell{Earfcn: 1575, Frequency: 3500}))
+	assert.InDelta(t, 1842.5, Frequency(&model.Cell{Earfcn: 1575}), 1e-9)
+	assert.Equal(t, 2000.0, Frequency(&model.Cell{}))
+}
+
+func TestCellPathLoss(t *testing.T) {
+	center := model.Coordinate{Lat: 45, Lng: 30}
+	location := model.Coordinate{Lat: 45.01, Lng: 30}
+	cell := func(environment model.Environment) *model.Cell {
+		return &model.Cell{ECGI: 1, Sector: model.Sector{Center: center, Arc: 360}, Environment: environment}
+	}
+
+	// Without an environment, the urban macro path loss at 2 GHz applies without shadowing
+	assert.InDelta(t, PathLoss(Distance(center, location)), CellPathLoss(cell(""), location), 1e-9)
+
+	// Rural cells reach further than urban ones, on average
+	var urban, rural float64
+	for i := 0; i < 100; i++ {
+		l := model.Coordinate{Lat: 45.01 + float64(i)*0.001, Lng: 30}
+		urban += CellPathLoss(cell(model.Urban), l)
+		rural += CellPathLoss(cell(model.Rural), l)
+	}
+	assert.Greater(t, urban-rural, 100*20.0)
+
+	// The shadowing is reproducible and constant within squares of the decorrelation distance
+	assert.Equal(t, CellPathLoss(cell(model.Urban), location), CellPathLoss(cell(model.Urban), location))
+	assert.Equal(t, shadowing(1, model.Coordinate{Lat: 45.0001, Lng: 30.0001}), shadowing(1, model.Coordinate{Lat: 45.0002, Lng: 30.0002}))
+
+	// The shadowing is standard normal
+	var sum, squares float64
+	n := 10000
+	for i := 0; i < n; i++ {
+		s := shadowing(1, model.Coordinate{Lat: 45 + float64(i)*0.001, Lng: 30})
+		sum += s
+		squares += s * s
+	}
+	assert.InDelta(t, 0, sum/float64(n), 0.05)
+	assert.InDelta(t, 1, math.Sqrt(squares/float64(n)), 0.05)
+
+	// UEs flying above the aerial height are in line of sight of the cell
+	aerial := location
+	aerial.Alt = 100
+	assert.Less(t, CellPathLoss(cell(model.Urban), aerial), CellPathLoss(cell(""), location)-10)
+
+	// Higher frequencies are attenuated more
+	high := cell("")
+	high.Frequency = 3500
+	assert.Greater(t, CellPathLoss(high, location), CellPathLoss(cell(""), location))
+}
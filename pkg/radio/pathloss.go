@@ -0,0 +1,56 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package radio
+
+import (
+	"math"
+
+	"github.com/onosproject/ran-simulator/pkg/model"
+)
+
+const (
+	// earthRadius Earth radius in meters
+	earthRadius = 6378100
+
+	// minDistance distance in meters below which the path loss no longer decreases
+	minDistance = 10.0
+)
+
+// PathLoss returns the path loss in dB at the given distance in meters, as per the 3GPP TR 36.942 urban macro
+// propagation model for a 2 GHz carrier
+func PathLoss(distance float64) float64 {
+	return 128.1 + 37.6*math.Log10(math.Max(distance, minDistance)/1000)
+}
+
+// RSRP returns the reference signal received power in dBm of the cell at the given location,
+// i.e. the cell transmit power plus the antenna gain towards the location less the path loss in the propagation
+// environment of the cell
+func RSRP(cell *model.Cell, location model.Coordinate) float64 {
+	return cell.TxPowerDB + AntennaGain(cell.Sector, location) - CellPathLoss(cell, location)
+}
+
+// Distance returns the great-circle distance between the given locations in meters
+func Distance(c1 model.Coordinate, c2 model.Coordinate) float64 {
+	la1 := c1.Lat * math.Pi / 180
+	lo1 := c1.Lng * math.Pi / 180
+	la2 := c2.Lat * math.Pi / 180
+	lo2 := c2.Lng * math.Pi / 180
+	h := hsin(la2-la1) + math.Cos(la1)*math.Cos(la2)*hsin(lo2-lo1)
+	return 2 * earthRadius * math.Asin(math.Sqrt(h))
+}
+
+// Bearing returns the initial compass bearing in degrees from one location towards another
+func Bearing(from model.Coordinate, to model.Coordinate) float64 {
+	la1 := from.Lat * math.Pi / 180
+	la2 := to.Lat * math.Pi / 180
+	dLng := (to.Lng - from.Lng) * math.Pi / 180
+	y := math.Sin(dLng) * math.Cos(la2)
+	x := math.Cos(la1)*math.Sin(la2) - math.Sin(la1)*math.Cos(la2)*math.Cos(dLng)
+	return math.Mod(math.Atan2(y, x)*180/math.Pi+360, 360)
+}
+
+func hsin(theta float64) float64 {
+	return math.Pow(math.Sin(theta/2), 2)
+}
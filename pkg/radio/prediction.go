@@ -0,0 +1,67 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package radio
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/onosproject/onos-api/go/onos/ransim/types"
+	"github.com/onosproject/ran-simulator/pkg/model"
+)
+
+// Prediction is a cell predicted to become a handover candidate of a moving UE
+type Prediction struct {
+	ECGI types.ECGI `json:"ecgi"`
+	// Strength is the RSRP in dBm of the cell at the predicted location of the UE
+	Strength float64 `json:"strength"`
+	// Gain is the change of the RSRP in dB from the current to the predicted location of the UE
+	Gain float64 `json:"gain"`
+	// RadialSpeed is the speed in m/s at which the UE approaches the cell site, i.e. the speed that determines the
+	// Doppler shift of the signal of the cell; it is negative if the UE moves away from the cell site
+	RadialSpeed float64 `json:"radialSpeed"`
+}
+
+// PredictCells extrapolates the location of a UE moving along the heading in degrees at the speed in m/s over the
+// horizon and returns that location along with the cells other than the serving cell whose signal gets stronger on
+// the way, strongest first. The prediction only relies on the movement of the UE and the cell geometry, providing a
+// baseline for handover prediction algorithms. Cells out of service are ignored.
+func PredictCells(cells []*model.Cell, serving types.ECGI, location model.Coordinate, heading float64, speed float64,
+	horizon time.Duration) (model.Coordinate, []Prediction) {
+	predicted := Offset(location, heading, speed*horizon.Seconds())
+	predictions := make([]Prediction, 0)
+	for _, cell := range cells {
+		if cell.ECGI == serving || !cell.InService() {
+			continue
+		}
+		strength := RSRP(cell, predicted)
+		gain := strength - RSRP(cell, location)
+		if gain <= 0 {
+			continue
+		}
+		predictions = append(predictions, Prediction{
+			ECGI:        cell.ECGI,
+			Strength:    strength,
+			Gain:        gain,
+			RadialSpeed: speed * math.Cos((Bearing(location, cell.Sector.Center)-heading)*math.Pi/180),
+		})
+	}
+	sort.Slice(predictions, func(i, j int) bool {
+		return predictions[i].Strength > predictions[j].Strength
+	})
+	return predicted, predictions
+}
+
+// Offset returns the location at the given distance in meters and bearing in degrees from the origin, using an
+// equirectangular approximation which is accurate for the distances within a simulated network
+func Offset(origin model.Coordinate, bearing float64, distance float64) model.Coordinate {
+	rad := bearing * math.Pi / 180
+	return model.Coordinate{
+		Lat: origin.Lat + distance*math.Cos(rad)/metersPerDegree,
+		Lng: origin.Lng + distance*math.Sin(rad)/(metersPerDegree*math.Cos(origin.Lat*math.Pi/180)),
+		Alt: origin.Alt,
+	}
+}
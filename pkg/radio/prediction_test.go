@@ -0,0 +1,43 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package radio
+
+import (
+	"testing"
+	"time"
+
+	"github.com/onosproject/onos-api/go/onos/ransim/types"
+	"github.com/onosproject/ran-simulator/pkg/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPredictCells(t *testing.T) {
+	omni := func(ecgi types.ECGI, lat float64, lng float64) *model.Cell {
+		return &model.Cell{ECGI: ecgi, Sector: model.Sector{Center: model.Coordinate{Lat: lat, Lng: lng}, Arc: 360}, TxPowerDB: 11}
+	}
+	serving := omni(1, 45, 30)
+	east := omni(2, 45, 30.02)
+	west := omni(3, 45, 29.98)
+	cells := []*model.Cell{serving, east, west}
+	location := model.Coordinate{Lat: 45, Lng: 30.005}
+
+	// Moving east at 20 m/s, the UE approaches the eastern cell and leaves the western one behind
+	predicted, predictions := PredictCells(cells, serving.ECGI, location, 90, 20, 10*time.Second)
+	assert.InDelta(t, 200, Distance(location, predicted), 1)
+	assert.Len(t, predictions, 1)
+	assert.Equal(t, east.ECGI, predictions[0].ECGI)
+	assert.Greater(t, predictions[0].Gain, 0.0)
+	assert.InDelta(t, 20, predictions[0].RadialSpeed, 0.1)
+
+	// Stationary UEs are not predicted to change their cell
+	predicted, predictions = PredictCells(cells, serving.ECGI, location, 90, 0, 10*time.Second)
+	assert.Equal(t, location, predicted)
+	assert.Empty(t, predictions)
+
+	// Cells out of service are ignored
+	east.Locked = true
+	_, predictions = PredictCells(cells, serving.ECGI, location, 90, 20, 10*time.Second)
+	assert.Empty(t, predictions)
+}
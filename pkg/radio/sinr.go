@@ -0,0 +1,44 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package radio
+
+import (
+	"math"
+
+	"github.com/onosproject/ran-simulator/pkg/model"
+)
+
+// NoiseFloor is the thermal noise power in dBm received by a UE over a 20 MHz carrier, i.e. -174 dBm/Hz over the
+// bandwidth plus a 7 dB noise figure
+const NoiseFloor = -94.0
+
+// SINR returns the signal to interference plus noise ratio in dB of a signal received at the given power in dBm,
+// interfered by the signals received at the given powers in dBm on top of the noise floor
+func SINR(signal float64, interference ...float64) float64 {
+	total := milliwatts(NoiseFloor)
+	for _, power := range interference {
+		total += milliwatts(power)
+	}
+	return signal - 10*math.Log10(total)
+}
+
+// ServingSINR returns the SINR in dB of the serving cell of the UE as last measured, interfered by the measured
+// neighbor cells on the same carrier frequency; the SINR of a UE without serving cell is minus infinity
+func ServingSINR(ue *model.UE) float64 {
+	if ue.Cell == nil {
+		return math.Inf(-1)
+	}
+	interference := make([]float64, 0, len(ue.Cells))
+	for _, neighbor := range ue.Cells {
+		if !neighbor.InterFrequency {
+			interference = append(interference, neighbor.Strength)
+		}
+	}
+	return SINR(ue.Cell.Strength, interference...)
+}
+
+func milliwatts(dBm float64) float64 {
+	return math.Pow(10, dBm/10)
+}
@@ -0,0 +1,33 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package radio
+
+import (
+	"math"
+	"testing"
+
+	"github.com/onosproject/ran-simulator/pkg/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSINR(t *testing.T) {
+	// Without interference the SINR is the SNR
+	assert.InDelta(t, 20, SINR(NoiseFloor+20), 0.001)
+
+	// An interferer at the same power as the signal, well above the noise floor, caps the SINR at 0 dB
+	assert.InDelta(t, 0, SINR(-40, -40), 0.001)
+	assert.InDelta(t, -3, SINR(-40, -40, -40), 0.02)
+
+	ue := &model.UE{
+		Cell: &model.UECell{Strength: -50},
+		Cells: []*model.UECell{
+			{Strength: -60},
+			{Strength: -45, InterFrequency: true},
+		},
+	}
+	assert.InDelta(t, 10, ServingSINR(ue), 0.01)
+	ue.Cell = nil
+	assert.True(t, math.IsInf(ServingSINR(ue), -1))
+}
@@ -0,0 +1,98 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package scenario
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/onosproject/onos-api/go/onos/ransim/types"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/onosproject/ran-simulator/pkg/kpiprofile"
+)
+
+// anomalyRequest is the body of a request injecting a KPI anomaly
+type anomalyRequest struct {
+	Kind         kpiprofile.AnomalyKind `json:"kind"`
+	ECGI         types.ECGI             `json:"ecgi"`
+	Measurements []string               `json:"measurements"`
+	// Start is the start of the anomaly; the anomaly starts immediately if omitted
+	Start time.Time `json:"start"`
+	// Duration is the duration of the anomaly, e.g. 10m
+	Duration string  `json:"duration"`
+	Factor   float64 `json:"factor"`
+}
+
+// handleAnomalies handles GET /anomalies listing all injected KPI anomalies as ground truth and POST /anomalies
+// injecting an anomaly
+func (s *Server) handleAnomalies(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(s.anomalies.List()); err != nil {
+			log.Warn(err)
+		}
+	case http.MethodPost:
+		var request anomalyRequest
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			writeError(w, errors.New(errors.Invalid, err.Error()))
+			return
+		}
+		duration, err := time.ParseDuration(request.Duration)
+		if err != nil {
+			writeError(w, errors.New(errors.Invalid, "invalid duration %s", request.Duration))
+			return
+		}
+		if _, err := s.simulation.CellStore().Get(r.Context(), request.ECGI); err != nil {
+			writeError(w, err)
+			return
+		}
+		start := request.Start
+		if start.IsZero() {
+			start = time.Now()
+		}
+		anomaly, err := s.anomalies.Inject(kpiprofile.Anomaly{
+			Kind:         request.Kind,
+			ECGI:         request.ECGI,
+			Measurements: request.Measurements,
+			Start:        start,
+			End:          start.Add(duration),
+			Factor:       request.Factor,
+		})
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		if err := json.NewEncoder(w).Encode(anomaly); err != nil {
+			log.Warn(err)
+		}
+	default:
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+	}
+}
+
+// handleAnomaly handles DELETE /anomalies/{id} cancelling an anomaly
+func (s *Server) handleAnomaly(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+	idParam := strings.Trim(strings.TrimPrefix(r.URL.Path, anomaliesPath), "/")
+	id, err := strconv.ParseUint(idParam, 10, 64)
+	if err != nil {
+		writeError(w, errors.New(errors.Invalid, "invalid anomaly ID %s", idParam))
+		return
+	}
+	if err := s.anomalies.Cancel(id); err != nil {
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,77 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package scenario
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/onosproject/onos-api/go/onos/ransim/types"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/onosproject/ran-simulator/pkg/radio"
+)
+
+// defaultPredictionHorizon is the time over which the movement of UEs is extrapolated unless specified
+const defaultPredictionHorizon = 10 * time.Second
+
+// CellPrediction lists the cells predicted to become handover candidates of a UE based on its movement
+type CellPrediction struct {
+	IMSI    types.IMSI         `json:"imsi"`
+	Serving types.ECGI         `json:"serving"`
+	Lat     float64            `json:"lat"`
+	Lng     float64            `json:"lng"`
+	Heading uint32             `json:"heading"`
+	Speed   float64            `json:"speed"`
+	Horizon float64            `json:"horizon"`
+	Cells   []radio.Prediction `json:"cells"`
+}
+
+// predictCells handles GET /ues/{imsi}/prediction?horizon={s} returning the cells predicted to become handover
+// candidates of the UE within the given number of seconds, extrapolating its location from its heading and speed
+func (s *Server) predictCells(w http.ResponseWriter, r *http.Request, imsi types.IMSI) {
+	if r.Method != http.MethodGet {
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+	horizon := defaultPredictionHorizon
+	if value := r.URL.Query().Get("horizon"); value != "" {
+		seconds, err := strconv.ParseFloat(value, 64)
+		if err != nil || seconds < 0 {
+			writeError(w, errors.New(errors.Invalid, "invalid horizon %s", value))
+			return
+		}
+		horizon = time.Duration(seconds * float64(time.Second))
+	}
+	ue, err := s.simulation.UEStore().Get(r.Context(), imsi)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	cellList, err := s.simulation.CellStore().List(r.Context())
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	prediction := CellPrediction{
+		IMSI:    ue.IMSI,
+		Heading: ue.Heading,
+		Speed:   ue.Speed,
+		Horizon: horizon.Seconds(),
+	}
+	if ue.Cell != nil {
+		prediction.Serving = ue.Cell.ECGI
+	}
+	location, cells := radio.PredictCells(cellList, prediction.Serving, ue.Location, float64(ue.Heading), ue.Speed, horizon)
+	prediction.Lat = location.Lat
+	prediction.Lng = location.Lng
+	prediction.Cells = cells
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(prediction); err != nil {
+		log.Warn(err)
+	}
+}
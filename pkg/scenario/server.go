@@ -0,0 +1,258 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package scenario
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/onosproject/onos-api/go/onos/ransim/types"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/onosproject/onos-lib-go/pkg/logging"
+	"github.com/onosproject/ran-simulator/pkg/kpiprofile"
+	"github.com/onosproject/ran-simulator/pkg/mobility"
+	"github.com/onosproject/ran-simulator/pkg/radio"
+	"github.com/onosproject/ran-simulator/pkg/store/cells"
+	"github.com/onosproject/ran-simulator/pkg/store/ues"
+)
+
+var log = logging.GetLogger("scenario")
+
+const (
+	uesPath        = "/ues"
+	coveragePath   = "/coverage"
+	anomaliesPath  = "/anomalies"
+	csvContentType = "text/csv"
+)
+
+// Simulation provides the stores and the handover engine of the simulation, which are replaced whenever the model
+// is loaded or the simulation run is reset
+type Simulation interface {
+	// CellStore returns the current cell store
+	CellStore() cells.Store
+	// UEStore returns the current UE store
+	UEStore() ues.Store
+	// HandoverEngine returns the current handover engine; nil if the mobility controllers are not running
+	HandoverEngine() *mobility.HandoverEngine
+	// PauseControllers stops the controllers until the returned function is called
+	PauseControllers() func() error
+}
+
+// Server is an HTTP server allowing tests to steer the simulated scenario deterministically, e.g. to force handovers
+// or to start from a known radio situation
+type Server struct {
+	simulation Simulation
+	anomalies  *kpiprofile.Anomalies
+	server     *http.Server
+}
+
+// NewServer creates a new scenario server listening on the specified port; the stores and the handover engine are
+// resolved through the simulation on every request
+func NewServer(simulation Simulation, anomalies *kpiprofile.Anomalies, port int) *Server {
+	s := &Server{
+		simulation: simulation,
+		anomalies:  anomalies,
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc(uesPath, s.handleUEs)
+	mux.HandleFunc(uesPath+"/", s.handleUE)
+	mux.HandleFunc(coveragePath, s.analyzeCoverage)
+	mux.HandleFunc(anomaliesPath, s.handleAnomalies)
+	mux.HandleFunc(anomaliesPath+"/", s.handleAnomaly)
+	s.server = &http.Server{
+		Addr:    fmt.Sprintf(":%d", port),
+		Handler: mux,
+	}
+	return s
+}
+
+// Use wraps the handler of the scenario server with the given middleware before it serves
+func (s *Server) Use(middleware func(http.Handler) http.Handler) {
+	s.server.Handler = middleware(s.server.Handler)
+}
+
+// Serve starts serving the scenario requests in the background
+func (s *Server) Serve() {
+	go func() {
+		log.Info("Started scenario server on ", s.server.Addr)
+		if err := s.server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Error(err)
+		}
+	}()
+}
+
+// Stop stops the scenario server
+func (s *Server) Stop() {
+	if err := s.server.Shutdown(context.Background()); err != nil {
+		log.Error(err)
+	}
+}
+
+// handleUEs handles GET /ues?format={json|csv} exporting and PUT /ues importing a snapshot of the UE population;
+// imported snapshots are parsed as CSV if their content type is text/csv and as JSON otherwise
+func (s *Server) handleUEs(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		snapshots := Export(r.Context(), s.simulation.UEStore())
+		if r.URL.Query().Get("format") == "csv" {
+			w.Header().Set("Content-Type", csvContentType)
+			if err := WriteCSV(w, snapshots); err != nil {
+				log.Warn(err)
+			}
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(snapshots); err != nil {
+			log.Warn(err)
+		}
+	case http.MethodPut:
+		var snapshots []UESnapshot
+		var err error
+		if strings.HasPrefix(r.Header.Get("Content-Type"), csvContentType) {
+			snapshots, err = ReadCSV(r.Body)
+		} else if err = json.NewDecoder(r.Body).Decode(&snapshots); err != nil {
+			err = errors.New(errors.Invalid, err.Error())
+		}
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		// The controllers would act on the UEs while the population is being replaced otherwise
+		resume := s.simulation.PauseControllers()
+		err = Import(r.Context(), s.simulation.UEStore(), s.simulation.CellStore(), snapshots)
+		if resumeErr := resume(); err == nil {
+			err = resumeErr
+		}
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		log.Infof("Imported snapshot of %d UEs", len(snapshots))
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+	}
+}
+
+// handleUE handles requests for /ues/{imsi}/handover, /ues/{imsi}/trajectory and /ues/{imsi}/prediction
+func (s *Server) handleUE(w http.ResponseWriter, r *http.Request) {
+	parts := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, uesPath), "/"), "/")
+	if len(parts) != 2 {
+		http.NotFound(w, r)
+		return
+	}
+	imsi, err := strconv.ParseUint(parts[0], 10, 64)
+	if err != nil {
+		writeError(w, errors.New(errors.Invalid, "invalid IMSI %s", parts[0]))
+		return
+	}
+	switch parts[1] {
+	case "handover":
+		s.handoverUE(w, r, types.IMSI(imsi))
+	case "trajectory":
+		s.getTrajectory(w, r, types.IMSI(imsi))
+	case "prediction":
+		s.predictCells(w, r, types.IMSI(imsi))
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// handoverUE handles POST /ues/{imsi}/handover?target={ecgi}
+func (s *Server) handoverUE(w http.ResponseWriter, r *http.Request, imsi types.IMSI) {
+	if r.Method != http.MethodPost {
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+	target, err := strconv.ParseUint(r.URL.Query().Get("target"), 10, 64)
+	if err != nil {
+		writeError(w, errors.New(errors.Invalid, "invalid target ECGI %s", r.URL.Query().Get("target")))
+		return
+	}
+	handover := s.simulation.HandoverEngine()
+	if handover == nil {
+		writeError(w, errors.New(errors.Unavailable, "simulation is not running"))
+		return
+	}
+	if err := handover.HandoverUE(r.Context(), imsi, types.ECGI(target)); err != nil {
+		writeError(w, err)
+		return
+	}
+	log.Infof("UE %d handed over to cell %d", imsi, target)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// analyzeCoverage handles GET /coverage?resolution={m}&margin={m}&threshold={dBm}&overlapMargin={dB}&maxOverlap={n}
+// returning the coverage holes and regions of excessive overlap as a GeoJSON feature collection
+func (s *Server) analyzeCoverage(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+	params, err := parseCoverageParams(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	cellList, err := s.simulation.CellStore().List(r.Context())
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/geo+json")
+	if err := json.NewEncoder(w).Encode(radio.AnalyzeCoverage(cellList, params)); err != nil {
+		log.Warn(err)
+	}
+}
+
+func parseCoverageParams(r *http.Request) (radio.CoverageParams, error) {
+	params := radio.DefaultCoverageParams()
+	values := r.URL.Query()
+	floats := map[string]*float64{
+		"resolution":    &params.Resolution,
+		"margin":        &params.Margin,
+		"threshold":     &params.Threshold,
+		"overlapMargin": &params.OverlapMargin,
+	}
+	for name, param := range floats {
+		if value := values.Get(name); value != "" {
+			parsed, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				return params, errors.New(errors.Invalid, "invalid %s %s", name, value)
+			}
+			*param = parsed
+		}
+	}
+	if value := values.Get("maxOverlap"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil {
+			return params, errors.New(errors.Invalid, "invalid maxOverlap %s", value)
+		}
+		params.MaxOverlap = parsed
+	}
+	if params.Resolution <= 0 {
+		return params, errors.New(errors.Invalid, "resolution must be positive")
+	}
+	return params, nil
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.IsNotFound(err):
+		status = http.StatusNotFound
+	case errors.IsInvalid(err):
+		status = http.StatusBadRequest
+	case errors.IsForbidden(err):
+		status = http.StatusConflict
+	case errors.IsUnavailable(err):
+		status = http.StatusServiceUnavailable
+	}
+	http.Error(w, err.Error(), status)
+}
@@ -0,0 +1,236 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package scenario
+
+import (
+	"context"
+	"encoding/csv"
+	"io"
+	"sort"
+	"strconv"
+
+	"github.com/onosproject/onos-api/go/onos/ransim/types"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/onosproject/ran-simulator/pkg/model"
+	"github.com/onosproject/ran-simulator/pkg/store/cells"
+	"github.com/onosproject/ran-simulator/pkg/store/ues"
+)
+
+var csvHeader = []string{"imsi", "type", "lat", "lng", "heading", "ecgi", "strength", "rrcState", "alt"}
+
+// UESnapshot captures the radio situation of a UE, i.e. its position, its serving cell, if any, and its RRC state;
+// a zero ECGI denotes a UE without serving cell
+type UESnapshot struct {
+	IMSI     types.IMSI `json:"imsi"`
+	Type     string     `json:"type"`
+	Lat      float64    `json:"lat"`
+	Lng      float64    `json:"lng"`
+	Heading  uint32     `json:"heading"`
+	ECGI     types.ECGI `json:"ecgi"`
+	Strength float64    `json:"strength"`
+	RrcState string     `json:"rrcState"`
+	Alt      float64    `json:"alt,omitempty"`
+}
+
+// Export returns the snapshot of the entire UE population ordered by IMSI
+func Export(ctx context.Context, ueStore ues.Store) []UESnapshot {
+	snapshots := make([]UESnapshot, 0, ueStore.Len(ctx))
+	for _, ue := range ueStore.ListAllUEs(ctx) {
+		snapshot := UESnapshot{
+			IMSI:     ue.IMSI,
+			Type:     string(ue.Type),
+			Lat:      ue.Location.Lat,
+			Lng:      ue.Location.Lng,
+			Heading:  ue.Heading,
+			RrcState: ue.RrcState.String(),
+			Alt:      ue.Location.Alt,
+		}
+		if ue.Cell != nil {
+			snapshot.ECGI = ue.Cell.ECGI
+			snapshot.Strength = ue.Cell.Strength
+		}
+		snapshots = append(snapshots, snapshot)
+	}
+	sort.Slice(snapshots, func(i, j int) bool {
+		return snapshots[i].IMSI < snapshots[j].IMSI
+	})
+	return snapshots
+}
+
+// Import replaces the UE population by the one captured in the snapshots; UEs missing from the snapshots are
+// deleted and the others are created or updated to match their snapshot exactly; UEs without serving cell, either
+// in the store or in their snapshot, are created anew. The snapshots are validated before any UE is changed.
+func Import(ctx context.Context, ueStore ues.Store, cellStore cells.Store, snapshots []UESnapshot) error {
+	states := make(map[types.IMSI]model.RrcState, len(snapshots))
+	for _, snapshot := range snapshots {
+		if _, ok := states[snapshot.IMSI]; ok {
+			return errors.New(errors.Invalid, "duplicate UE %d", snapshot.IMSI)
+		}
+		state, err := parseRrcState(snapshot.RrcState)
+		if err != nil {
+			return err
+		}
+		states[snapshot.IMSI] = state
+		if snapshot.ECGI == 0 {
+			continue
+		}
+		cell, err := cellStore.Get(ctx, snapshot.ECGI)
+		if err != nil {
+			return errors.New(errors.Invalid, "UE %d: unknown cell %d", snapshot.IMSI, snapshot.ECGI)
+		}
+		if !cell.Admits(snapshot.IMSI) {
+			return errors.New(errors.Invalid, "UE %d: not a member of the closed subscriber group of cell %d", snapshot.IMSI, snapshot.ECGI)
+		}
+	}
+
+	for _, ue := range ueStore.ListAllUEs(ctx) {
+		if _, ok := states[ue.IMSI]; !ok {
+			if _, err := ueStore.Delete(ctx, ue.IMSI); err != nil {
+				return err
+			}
+		}
+	}
+	for _, snapshot := range snapshots {
+		location := model.Coordinate{Lat: snapshot.Lat, Lng: snapshot.Lng, Alt: snapshot.Alt}
+		ue, err := ueStore.Get(ctx, snapshot.IMSI)
+		if err == nil && (ue.Cell == nil || snapshot.ECGI == 0) {
+			// The stores can neither attach a UE to its first serving cell nor detach it from its serving cell
+			if _, err = ueStore.Delete(ctx, ue.IMSI); err != nil {
+				return err
+			}
+			err = errors.New(errors.NotFound, "UE %d not served by a cell", ue.IMSI)
+		}
+		if errors.IsNotFound(err) {
+			ue = &model.UE{
+				IMSI:     snapshot.IMSI,
+				Type:     model.UEType(snapshot.Type),
+				Location: location,
+				Heading:  snapshot.Heading,
+				RrcState: states[snapshot.IMSI],
+			}
+			if snapshot.ECGI != 0 {
+				ue.Cell = &model.UECell{ID: types.GEnbID(snapshot.ECGI), ECGI: snapshot.ECGI, Strength: snapshot.Strength}
+			}
+			if err := ueStore.Add(ctx, ue); err != nil {
+				return err
+			}
+			continue
+		} else if err != nil {
+			return err
+		}
+		ue.Type = model.UEType(snapshot.Type)
+		if err := ueStore.MoveToCoordinate(ctx, ue.IMSI, location, snapshot.Heading); err != nil {
+			return err
+		}
+		if err := ueStore.MoveToCell(ctx, ue.IMSI, snapshot.ECGI, snapshot.Strength); err != nil {
+			return err
+		}
+		if err := ueStore.UpdateRrcState(ctx, ue.IMSI, states[snapshot.IMSI]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WriteCSV writes the snapshots as CSV records preceded by a header
+func WriteCSV(w io.Writer, snapshots []UESnapshot) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(csvHeader); err != nil {
+		return err
+	}
+	for _, snapshot := range snapshots {
+		record := []string{
+			strconv.FormatUint(uint64(snapshot.IMSI), 10),
+			snapshot.Type,
+			strconv.FormatFloat(snapshot.Lat, 'f', -1, 64),
+			strconv.FormatFloat(snapshot.Lng, 'f', -1, 64),
+			strconv.FormatUint(uint64(snapshot.Heading), 10),
+			strconv.FormatUint(uint64(snapshot.ECGI), 10),
+			strconv.FormatFloat(snapshot.Strength, 'f', -1, 64),
+			snapshot.RrcState,
+			strconv.FormatFloat(snapshot.Alt, 'f', -1, 64),
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// ReadCSV reads snapshots from CSV records preceded by a header, as written by WriteCSV; the trailing alt column
+// may be omitted
+func ReadCSV(r io.Reader) ([]UESnapshot, error) {
+	reader := csv.NewReader(r)
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, errors.New(errors.Invalid, err.Error())
+	}
+	if len(records) == 0 {
+		return nil, errors.New(errors.Invalid, "missing CSV header")
+	}
+	if fields := len(records[0]); fields != len(csvHeader) && fields != len(csvHeader)-1 {
+		return nil, errors.New(errors.Invalid, "invalid CSV header")
+	}
+	snapshots := make([]UESnapshot, 0, len(records)-1)
+	for i, record := range records[1:] {
+		if len(record) != len(records[0]) {
+			return nil, errors.New(errors.Invalid, "record %d: wrong number of fields", i+1)
+		}
+		snapshot, err := parseRecord(record)
+		if err != nil {
+			return nil, errors.New(errors.Invalid, "record %d: %s", i+1, err.Error())
+		}
+		snapshots = append(snapshots, snapshot)
+	}
+	return snapshots, nil
+}
+
+func parseRecord(record []string) (UESnapshot, error) {
+	snapshot := UESnapshot{Type: record[1], RrcState: record[7]}
+	imsi, err := strconv.ParseUint(record[0], 10, 64)
+	if err != nil {
+		return snapshot, err
+	}
+	snapshot.IMSI = types.IMSI(imsi)
+	if snapshot.Lat, err = strconv.ParseFloat(record[2], 64); err != nil {
+		return snapshot, err
+	}
+	if snapshot.Lng, err = strconv.ParseFloat(record[3], 64); err != nil {
+		return snapshot, err
+	}
+	heading, err := strconv.ParseUint(record[4], 10, 32)
+	if err != nil {
+		return snapshot, err
+	}
+	snapshot.Heading = uint32(heading)
+	ecgi, err := strconv.ParseUint(record[5], 10, 64)
+	if err != nil {
+		return snapshot, err
+	}
+	snapshot.ECGI = types.ECGI(ecgi)
+	if snapshot.Strength, err = strconv.ParseFloat(record[6], 64); err != nil {
+		return snapshot, err
+	}
+	if len(record) > 8 {
+		if snapshot.Alt, err = strconv.ParseFloat(record[8], 64); err != nil {
+			return snapshot, err
+		}
+	}
+	return snapshot, nil
+}
+
+// parseRrcState parses the name of an RRC state; an empty name denotes the idle state
+func parseRrcState(name string) (model.RrcState, error) {
+	for _, state := range []model.RrcState{model.RrcIdle, model.RrcInactive, model.RrcConnected} {
+		if name == state.String() {
+			return state, nil
+		}
+	}
+	if name == "" {
+		return model.RrcIdle, nil
+	}
+	return model.RrcIdle, errors.New(errors.Invalid, "unknown RRC state %s", name)
+}
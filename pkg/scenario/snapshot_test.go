@@ -0,0 +1,77 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package scenario
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/onosproject/ran-simulator/pkg/model"
+	"github.com/onosproject/ran-simulator/pkg/store/cells"
+	"github.com/onosproject/ran-simulator/pkg/store/nodes"
+	"github.com/onosproject/ran-simulator/pkg/store/ues"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v2"
+)
+
+func cellStore(t *testing.T) cells.Store {
+	m := model.Model{}
+	bytes, err := ioutil.ReadFile("../model/test.yaml")
+	assert.NoError(t, err)
+	err = yaml.Unmarshal(bytes, &m)
+	assert.NoError(t, err)
+	return cells.NewCellRegistry(m.Cells, nodes.NewNodeRegistry(m.Nodes))
+}
+
+func TestSnapshot(t *testing.T) {
+	ctx := context.Background()
+	cellStore := cellStore(t)
+	ueStore := ues.NewUERegistry(3, cellStore)
+	kept := ueStore.ListAllUEs(ctx)[0]
+
+	snapshots := []UESnapshot{
+		{IMSI: kept.IMSI, Type: "phone", Lat: 46.1, Lng: 29.2, Heading: 90, ECGI: 84325717761, Strength: 12.5, RrcState: "CONNECTED"},
+		{IMSI: 999999, Type: "uav", Lat: 44.1, Lng: 31.2, Heading: 180, ECGI: 84325717505, Strength: 3, RrcState: "IDLE", Alt: 120},
+	}
+	var buf bytes.Buffer
+	assert.NoError(t, WriteCSV(&buf, snapshots))
+	parsed, err := ReadCSV(&buf)
+	assert.NoError(t, err)
+	assert.Equal(t, snapshots, parsed)
+
+	// The altitude may be omitted
+	legacy, err := ReadCSV(strings.NewReader("imsi,type,lat,lng,heading,ecgi,strength,rrcState\n1,phone,46,29,0,84325717505,0,IDLE\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, 0.0, legacy[0].Alt)
+	_, err = ReadCSV(strings.NewReader("imsi,type\n1,phone\n"))
+	assert.True(t, errors.IsInvalid(err))
+
+	assert.NoError(t, Import(ctx, ueStore, cellStore, parsed))
+	assert.Equal(t, 2, ueStore.Len(ctx))
+	assert.Equal(t, model.RrcConnected, kept.RrcState)
+	assert.Equal(t, 46.1, kept.Location.Lat)
+	assert.Equal(t, []UESnapshot{snapshots[1], snapshots[0]}, Export(ctx, ueStore))
+
+	// Invalid snapshots leave the UE population untouched
+	invalid := append(snapshots, UESnapshot{IMSI: 7654321, ECGI: 1})
+	assert.True(t, errors.IsInvalid(Import(ctx, ueStore, cellStore, invalid)))
+	invalid = []UESnapshot{{IMSI: 7654321, ECGI: 84325717505, RrcState: "DORMANT"}}
+	assert.True(t, errors.IsInvalid(Import(ctx, ueStore, cellStore, invalid)))
+	assert.Equal(t, 2, ueStore.Len(ctx))
+
+	// UEs without serving cell round-trip, and may be served by a cell again
+	detached := []UESnapshot{snapshots[1], {IMSI: kept.IMSI, Type: "phone", Lat: 46.1, Lng: 29.2, RrcState: "IDLE"}}
+	assert.NoError(t, Import(ctx, ueStore, cellStore, detached))
+	assert.Equal(t, detached, Export(ctx, ueStore))
+	ue, err := ueStore.Get(ctx, kept.IMSI)
+	assert.NoError(t, err)
+	assert.Nil(t, ue.Cell)
+	assert.NoError(t, Import(ctx, ueStore, cellStore, snapshots))
+	assert.Equal(t, []UESnapshot{snapshots[1], snapshots[0]}, Export(ctx, ueStore))
+}
@@ -0,0 +1,70 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package scenario
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/onosproject/onos-api/go/onos/ransim/types"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/onosproject/ran-simulator/pkg/model"
+)
+
+// TrajectoryPoint is a recorded position of a UE along with its serving cell at the time
+type TrajectoryPoint struct {
+	Time    time.Time  `json:"time"`
+	Lat     float64    `json:"lat"`
+	Lng     float64    `json:"lng"`
+	Alt     float64    `json:"alt,omitempty"`
+	Heading uint32     `json:"heading"`
+	Speed   float64    `json:"speed"`
+	ECGI    types.ECGI `json:"ecgi"`
+}
+
+func trajectoryToAPI(points []model.TrajectoryPoint) []TrajectoryPoint {
+	trajectory := make([]TrajectoryPoint, 0, len(points))
+	for _, point := range points {
+		trajectory = append(trajectory, TrajectoryPoint{
+			Time:    point.Time,
+			Lat:     point.Location.Lat,
+			Lng:     point.Location.Lng,
+			Alt:     point.Location.Alt,
+			Heading: point.Heading,
+			Speed:   point.Speed,
+			ECGI:    point.ECGI,
+		})
+	}
+	return trajectory
+}
+
+// getTrajectory handles GET /ues/{imsi}/trajectory?minutes={n} returning the positions and serving cells of the UE
+// recorded over the last n minutes, or all the recorded ones if not specified
+func (s *Server) getTrajectory(w http.ResponseWriter, r *http.Request, imsi types.IMSI) {
+	if r.Method != http.MethodGet {
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+	var since time.Time
+	if value := r.URL.Query().Get("minutes"); value != "" {
+		minutes, err := strconv.ParseFloat(value, 64)
+		if err != nil || minutes < 0 {
+			writeError(w, errors.New(errors.Invalid, "invalid minutes %s", value))
+			return
+		}
+		since = time.Now().Add(-time.Duration(minutes * float64(time.Minute)))
+	}
+	points, err := s.simulation.UEStore().Trajectory(r.Context(), imsi, since)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(trajectoryToAPI(points)); err != nil {
+		log.Warn(err)
+	}
+}
@@ -26,6 +26,7 @@ import (
 	"github.com/onosproject/ran-simulator/pkg/modelplugins"
 
 	"github.com/onosproject/onos-e2-sm/servicemodels/e2sm_kpm/pdubuilder"
+	e2sm_kpm_ies "github.com/onosproject/onos-e2-sm/servicemodels/e2sm_kpm/v1beta1/e2sm-kpm-ies"
 	indicationutils "github.com/onosproject/ran-simulator/pkg/utils/e2ap/indication"
 	subutils "github.com/onosproject/ran-simulator/pkg/utils/e2ap/subscription"
 	subdeleteutils "github.com/onosproject/ran-simulator/pkg/utils/e2ap/subscriptiondelete"
@@ -62,10 +63,16 @@ func NewServiceModel(node model.Node, model *model.Model, modelPluginRegistry mo
 	subStore *subscriptions.Subscriptions, nodeStore nodes.Store, ueStore ues.Store) (registry.ServiceModel, error) {
 	modelName := e2smtypes.ShortName(modelName)
 	kpmSm := registry.ServiceModel{
-		RanFunctionID:       registry.Kpm,
-		ModelName:           modelName,
-		Revision:            1,
-		OID:                 modelOID,
+		RanFunctionID: registry.Kpm,
+		ModelName:     modelName,
+		Revision:      1,
+		OID:           modelOID,
+		Payloads: registry.Payloads{
+			registry.EventTriggerPayload:      func() proto.Message { return &e2sm_kpm_ies.E2SmKpmEventTriggerDefinition{} },
+			registry.ActionDefinitionPayload:  func() proto.Message { return &e2sm_kpm_ies.E2SmKpmActionDefinition{} },
+			registry.IndicationHeaderPayload:  func() proto.Message { return &e2sm_kpm_ies.E2SmKpmIndicationHeader{} },
+			registry.IndicationMessagePayload: func() proto.Message { return &e2sm_kpm_ies.E2SmKpmIndicationMessage{} },
+		},
 		Version:             version,
 		ModelPluginRegistry: modelPluginRegistry,
 		Node:                node,
@@ -164,23 +171,27 @@ func (sm *Client) reportIndication(ctx context.Context, interval int32, subscrip
 		select {
 		case <-sub.Ticker.C:
 			log.Debug("Sending Indication Report for subscription:", sub.ID)
-			indication := indicationutils.NewIndication(
-				indicationutils.WithRicInstanceID(subscription.GetRicInstanceID()),
-				indicationutils.WithRanFuncID(subscription.GetRanFuncID()),
-				indicationutils.WithRequestID(subscription.GetReqID()),
-				indicationutils.WithIndicationHeader(indicationHeaderAsn1Bytes),
-				indicationutils.WithIndicationMessage(indicationMessageBytes))
-
-			ricIndication, err := indication.Build()
-			if err != nil {
-				log.Error("creating indication message is failed", err)
-				return err
-			}
-
-			err = sub.E2Channel.RICIndication(ctx, ricIndication)
-			if err != nil {
-				log.Error("Sending indication report is failed:", err)
-				return err
+			// Each report action is reported independently
+			for _, action := range sub.ReportActions() {
+				indication := indicationutils.NewIndication(
+					indicationutils.WithRicInstanceID(subscription.GetRicInstanceID()),
+					indicationutils.WithRanFuncID(subscription.GetRanFuncID()),
+					indicationutils.WithRequestID(subscription.GetReqID()),
+					indicationutils.WithRicActionID(int32(action.ID)),
+					indicationutils.WithIndicationHeader(indicationHeaderAsn1Bytes),
+					indicationutils.WithIndicationMessage(indicationMessageBytes))
+
+				ricIndication, err := indication.Build()
+				if err != nil {
+					log.Error("creating indication message is failed", err)
+					return err
+				}
+
+				err = sub.E2Channel.RICIndication(ctx, ricIndication)
+				if err != nil {
+					log.Error("Sending indication report is failed:", err)
+					return err
+				}
 			}
 
 		case <-sub.E2Channel.Context().Done():
@@ -188,6 +199,9 @@ func (sm *Client) reportIndication(ctx context.Context, interval int32, subscrip
 			sub.Ticker.Stop()
 			return nil
 
+		case <-ctx.Done():
+			sub.Ticker.Stop()
+			return nil
 		}
 	}
 }
@@ -252,14 +266,28 @@ func (sm *Client) RICSubscription(ctx context.Context, request *e2appducontents.
 		}
 		return nil, subscriptionFailure, nil
 	}
+	reportInterval, cause := sm.ServiceModel.CheckReportPeriod(reportInterval)
+	if cause != nil {
+		subscriptionFailure, err := subutils.NewSubscriptionFailure(request, cause)
+		if err != nil {
+			return nil, nil, err
+		}
+		return nil, subscriptionFailure, nil
+	}
+
+	sub, err := sm.ServiceModel.Subscriptions.Get(subscriptions.NewID(ricInstanceID, reqID, ranFuncID))
+	if err != nil {
+		return nil, nil, err
+	}
+	sub.AcceptActions(ricActionsAccepted)
 
 	subscriptionResponse, err := subscription.BuildSubscriptionResponse()
 	if err != nil {
 		return nil, nil, err
 	}
+	ctx, done := sm.ServiceModel.Subscriptions.StartReport(context.Background(), sub.ID)
 	go func() {
-		ctx, cancel := context.WithCancel(context.Background())
-		defer cancel()
+		defer done()
 		err := sm.reportIndication(ctx, reportInterval, subscription)
 		if err != nil {
 			return
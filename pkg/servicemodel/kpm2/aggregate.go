@@ -0,0 +1,204 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package kpm2
+
+import (
+	"context"
+	"math"
+	"strings"
+	"time"
+
+	ransimtypes "github.com/onosproject/onos-api/go/onos/ransim/types"
+	e2smkpmv2 "github.com/onosproject/onos-e2-sm/servicemodels/e2sm_kpm_v2/v2/e2sm-kpm-v2"
+	"github.com/onosproject/ran-simulator/pkg/store/subscriptions"
+)
+
+// defaultSamplingPeriod is the period the aggregated measurements are sampled at unless configured otherwise
+const defaultSamplingPeriod = 100 * time.Millisecond
+
+// aggregation is the statistic a measurement reports over its granularity period
+type aggregation int
+
+const (
+	// noAggregation reports the value of the measurement at report time
+	noAggregation aggregation = iota
+	// avgAggregation reports the average of the samples of the measurement
+	avgAggregation
+	// minAggregation reports the minimum of the samples of the measurement
+	minAggregation
+	// maxAggregation reports the maximum of the samples of the measurement
+	maxAggregation
+)
+
+// measAggregation returns the statistic the named measurement reports, following the naming conventions of
+// 3GPP TS 28.552 for gauges, e.g. RRC.Conn.Avg and RRC.Conn.Max
+func measAggregation(measName string) aggregation {
+	switch {
+	case strings.HasSuffix(measName, ".Avg"), strings.HasSuffix(measName, ".Mean"):
+		return avgAggregation
+	case strings.HasSuffix(measName, ".Min"):
+		return minAggregation
+	case strings.HasSuffix(measName, ".Max"):
+		return maxAggregation
+	}
+	return noAggregation
+}
+
+// sample is the value of a measurement at a given time
+type sample struct {
+	time    time.Time
+	value   float64
+	integer bool
+}
+
+// sampleKey identifies the samples of a measurement of a cell
+type sampleKey struct {
+	ecgi     ransimtypes.ECGI
+	measName string
+}
+
+// sampler accumulates the samples of the aggregated measurements reported by a subscription; it is only used by the
+// report loop of the subscription
+type sampler struct {
+	measNames []string
+	samples   map[sampleKey][]sample
+}
+
+// newSampler creates a sampler of the aggregated measurements requested by the given actions, all measurements
+// being requested by actions without definition; nil is returned if none is requested
+func newSampler(actions []*subscriptions.Action, actionDefinitions actionDefinitions) *sampler {
+	names := make(map[string]bool)
+	for _, action := range actions {
+		actionDefinition, ok := actionDefinitions[action.ID]
+		if !ok {
+			for _, measType := range listMeasTypes() {
+				names[measType.measTypeName] = true
+			}
+			continue
+		}
+		for _, measName := range requestedMeasNames(actionDefinition) {
+			names[measName] = true
+		}
+	}
+	s := &sampler{samples: make(map[sampleKey][]sample)}
+	for measName := range names {
+		if measAggregation(measName) != noAggregation {
+			s.measNames = append(s.measNames, measName)
+		}
+	}
+	if len(s.measNames) == 0 {
+		return nil
+	}
+	return s
+}
+
+// requestedMeasNames returns the names of the measurements requested by the action definition
+func requestedMeasNames(actionDefinition *e2smkpmv2.E2SmKpmActionDefinition) []string {
+	var measNames []string
+	switch {
+	case actionDefinition.GetActionDefinitionFormat1() != nil:
+		for _, measInfo := range actionDefinition.GetActionDefinitionFormat1().GetMeasInfoList().GetValue() {
+			measNames = append(measNames, measInfo.GetMeasType().GetMeasName().GetValue())
+		}
+	case actionDefinition.GetActionDefinitionFormat2() != nil:
+		for _, measInfo := range actionDefinition.GetActionDefinitionFormat2().GetSubscriptInfo().GetMeasInfoList().GetValue() {
+			measNames = append(measNames, measInfo.GetMeasType().GetMeasName().GetValue())
+		}
+	case actionDefinition.GetActionDefinitionFormat3() != nil:
+		for _, measCond := range actionDefinition.GetActionDefinitionFormat3().GetMeasCondList().GetValue() {
+			measNames = append(measNames, measCond.GetMeasType().GetMeasName().GetValue())
+		}
+	}
+	return measNames
+}
+
+// add records the given value of the named measurement of the cell sampled at the given time
+func (s *sampler) add(ecgi ransimtypes.ECGI, measName string, value interface{}, t time.Time) {
+	key := sampleKey{ecgi: ecgi, measName: measName}
+	switch v := value.(type) {
+	case uint64:
+		s.samples[key] = append(s.samples[key], sample{time: t, value: float64(v), integer: true})
+	case float64:
+		s.samples[key] = append(s.samples[key], sample{time: t, value: v})
+	}
+}
+
+// aggregate returns the statistic of the named measurement of the cell over its samples taken since the given
+// time; false is returned if the measurement is not aggregated or has not been sampled
+func (s *sampler) aggregate(ecgi ransimtypes.ECGI, measName string, since time.Time) (interface{}, bool) {
+	if s == nil {
+		return nil, false
+	}
+	kind := measAggregation(measName)
+	if kind == noAggregation {
+		return nil, false
+	}
+	var result, sum float64
+	count := 0
+	integer := true
+	for _, sample := range s.samples[sampleKey{ecgi: ecgi, measName: measName}] {
+		if sample.time.Before(since) {
+			continue
+		}
+		switch {
+		case count == 0:
+			result = sample.value
+		case kind == minAggregation:
+			result = math.Min(result, sample.value)
+		case kind == maxAggregation:
+			result = math.Max(result, sample.value)
+		}
+		sum += sample.value
+		integer = integer && sample.integer
+		count++
+	}
+	if count == 0 {
+		return nil, false
+	}
+	if kind == avgAggregation {
+		result = sum / float64(count)
+	}
+	if integer {
+		return uint64(math.Round(result)), true
+	}
+	return result, true
+}
+
+// prune discards the samples taken before the given time
+func (s *sampler) prune(before time.Time) {
+	for key, samples := range s.samples {
+		i := 0
+		for i < len(samples) && samples[i].time.Before(before) {
+			i++
+		}
+		if i == len(samples) {
+			delete(s.samples, key)
+		} else {
+			s.samples[key] = samples[i:]
+		}
+	}
+}
+
+// sample records the current values of the aggregated measurements of all the cells of the node
+func (sm *Client) sample(ctx context.Context, s *sampler, t time.Time) {
+	if s == nil {
+		return
+	}
+	for _, ecgi := range sm.cells() {
+		for _, measName := range s.measNames {
+			if value, ok := getMeasDriver(measName).Value(ctx, sm.ServiceModel, ecgi, measName); ok {
+				s.add(ecgi, measName, value, t)
+			}
+		}
+	}
+}
+
+// samplingPeriod returns the period the aggregated measurements are sampled at
+func (sm *Client) samplingPeriod() time.Duration {
+	if config, ok := sm.ServiceModel.Config(); ok && config.SamplingPeriod > 0 {
+		return time.Duration(config.SamplingPeriod) * time.Millisecond
+	}
+	return defaultSamplingPeriod
+}
@@ -0,0 +1,76 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package kpm2
+
+import (
+	"testing"
+	"time"
+
+	ransimtypes "github.com/onosproject/onos-api/go/onos/ransim/types"
+	e2smkpmv2 "github.com/onosproject/onos-e2-sm/servicemodels/e2sm_kpm_v2/v2/e2sm-kpm-v2"
+	"github.com/onosproject/ran-simulator/pkg/store/subscriptions"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMeasAggregation(t *testing.T) {
+	assert.Equal(t, avgAggregation, measAggregation(RRCConnAvg.String()))
+	assert.Equal(t, maxAggregation, measAggregation(RRCConnMax.String()))
+	assert.Equal(t, minAggregation, measAggregation("DRB.UEThpDl.Min"))
+	assert.Equal(t, avgAggregation, measAggregation("DRB.UEThpDl.Mean"))
+	assert.Equal(t, noAggregation, measAggregation(RRCConnEstabAttTot.String()))
+	assert.Equal(t, noAggregation, measAggregation(PEEAvgPower.String()))
+}
+
+func TestSampler(t *testing.T) {
+	ecgi := ransimtypes.ECGI(84325717505)
+	format1 := &e2smkpmv2.E2SmKpmActionDefinition{
+		E2SmKpmActionDefinition: &e2smkpmv2.E2SmKpmActionDefinition_ActionDefinitionFormat1{
+			ActionDefinitionFormat1: &e2smkpmv2.E2SmKpmActionDefinitionFormat1{
+				MeasInfoList: measInfoList(RRCConnEstabAttTot.String(), RRCConnMax.String()),
+			},
+		},
+	}
+	s := newSampler([]*subscriptions.Action{{ID: 1}}, actionDefinitions{1: format1})
+	assert.Equal(t, []string{RRCConnMax.String()}, s.measNames)
+	assert.Nil(t, newSampler([]*subscriptions.Action{{ID: 1}}, actionDefinitions{1: &e2smkpmv2.E2SmKpmActionDefinition{
+		E2SmKpmActionDefinition: &e2smkpmv2.E2SmKpmActionDefinition_ActionDefinitionFormat1{
+			ActionDefinitionFormat1: &e2smkpmv2.E2SmKpmActionDefinitionFormat1{
+				MeasInfoList: measInfoList(RRCConnEstabAttTot.String()),
+			},
+		},
+	}}))
+	// Actions without definition report all measurements
+	s = newSampler([]*subscriptions.Action{{ID: 1}}, actionDefinitions{})
+	assert.ElementsMatch(t, []string{RRCConnAvg.String(), RRCConnMax.String()}, s.measNames)
+
+	now := time.Now()
+	for i, value := range []uint64{4, 1, 6, 2} {
+		at := now.Add(time.Duration(i) * 100 * time.Millisecond)
+		s.add(ecgi, RRCConnAvg.String(), value, at)
+		s.add(ecgi, RRCConnMax.String(), value, at)
+		s.add(ecgi, "DRB.UEThpDl.Min", float64(value)/2, at)
+	}
+	value, ok := s.aggregate(ecgi, RRCConnAvg.String(), now)
+	assert.True(t, ok)
+	assert.Equal(t, uint64(3), value)
+	value, _ = s.aggregate(ecgi, RRCConnMax.String(), now)
+	assert.Equal(t, uint64(6), value)
+	value, _ = s.aggregate(ecgi, "DRB.UEThpDl.Min", now)
+	assert.Equal(t, 0.5, value)
+
+	// Only the samples of the granularity period are aggregated
+	value, _ = s.aggregate(ecgi, RRCConnAvg.String(), now.Add(200*time.Millisecond))
+	assert.Equal(t, uint64(4), value)
+	_, ok = s.aggregate(ecgi, RRCConnAvg.String(), now.Add(time.Second))
+	assert.False(t, ok)
+	_, ok = s.aggregate(ecgi, RRCConnEstabAttTot.String(), now)
+	assert.False(t, ok)
+
+	s.prune(now.Add(250 * time.Millisecond))
+	value, _ = s.aggregate(ecgi, RRCConnMax.String(), now)
+	assert.Equal(t, uint64(2), value)
+	s.prune(now.Add(time.Second))
+	assert.Empty(t, s.samples)
+}
@@ -0,0 +1,214 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package kpm2
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"sync"
+	"time"
+
+	ransimtypes "github.com/onosproject/onos-api/go/onos/ransim/types"
+	e2smkpmv2 "github.com/onosproject/onos-e2-sm/servicemodels/e2sm_kpm_v2/v2/e2sm-kpm-v2"
+	"github.com/onosproject/ran-simulator/pkg/utils/e2sm/kpm2/measurments"
+)
+
+const (
+	// CPUBudgetAttribute is the name of the node attribute limiting the number of measurement records per second
+	// the node can compute for its KPM v2 reports
+	CPUBudgetAttribute = "e2.cpuBudget"
+
+	// IndicationsDelayed is the name of the node metric counting the indications delayed for lack of compute budget
+	IndicationsDelayed = "E2.IndicationsDelayed"
+	// IndicationsIncomplete is the name of the node metric counting the indications flagged as incomplete for lack
+	// of compute budget
+	IndicationsIncomplete = "E2.IndicationsIncomplete"
+	// IndicationsSkipped is the name of the node metric counting the indications skipped for lack of compute budget
+	IndicationsSkipped = "E2.IndicationsSkipped"
+)
+
+// unlimitedGrant lets a node compute all the measurement records of a report
+const unlimitedGrant reportGrant = math.MaxInt32
+
+// reportGrant is the number of measurement records a node can compute for a report
+type reportGrant int
+
+// measReport tracks the completeness of the measurement data of a report while it is built
+type measReport struct {
+	// grant is the number of measurement records the node can compute for the report
+	grant reportGrant
+	// incomplete is set once a requested measurement is missing from the report
+	incomplete bool
+	// samples are the samples of the aggregated measurements, reported over the samples taken since the given time
+	samples *sampler
+	since   time.Time
+}
+
+// newMeasReport creates the tracker of a report for which the node can compute the given number of records
+func newMeasReport(grant reportGrant) *measReport {
+	return &measReport{grant: grant}
+}
+
+// omit notes that the named measurement requested is missing from the report for the given reason
+func (r *measReport) omit(measName string, reason string) {
+	log.Debugf("Measurement %s missing from report: %s", measName, reason)
+	r.incomplete = true
+}
+
+// incompleteFlag reports the measurement records beyond the grant without value and returns the incomplete flag of
+// the measurement data item holding the record, i.e. none if all requested measurements are reported
+func (r *measReport) incompleteFlag(record *e2smkpmv2.MeasurementRecord) e2smkpmv2.IncompleteFlag {
+	if len(record.GetValue()) > int(r.grant) {
+		for i := int(r.grant); i < len(record.Value); i++ {
+			record.Value[i] = measurments.NewMeasurementRecordItemNoValue()
+		}
+		r.incomplete = true
+	}
+	if r.incomplete {
+		return e2smkpmv2.IncompleteFlag_INCOMPLETE_FLAG_TRUE
+	}
+	return measurments.NoIncompleteFlag
+}
+
+// computeBudget tracks the compute credit of a node, accrued at the rate of its budget up to one second worth of
+// budget and spent on the measurement records of its reports; reports may be delayed into debt
+type computeBudget struct {
+	mu     sync.Mutex
+	credit float64
+	last   time.Time
+}
+
+// plan spends the credit for a report of the given cost at the given time; it returns the delay after which the
+// report can be sent complete if it does not exceed maxDelay, otherwise no delay and the number of measurement
+// records the node can compute right away. A non-positive budget means the node is not limited at all
+func (b *computeBudget) plan(budget float64, cost int, maxDelay time.Duration, now time.Time) (time.Duration, reportGrant) {
+	if budget <= 0 {
+		return 0, unlimitedGrant
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.last.IsZero() {
+		b.credit = budget
+	} else {
+		b.credit = math.Min(budget, b.credit+now.Sub(b.last).Seconds()*budget)
+	}
+	b.last = now
+	if b.credit >= float64(cost) {
+		b.credit -= float64(cost)
+		return 0, reportGrant(cost)
+	}
+	delay := time.Duration((float64(cost) - b.credit) / budget * float64(time.Second))
+	if delay <= maxDelay {
+		b.credit -= float64(cost)
+		return delay, reportGrant(cost)
+	}
+	granted := math.Max(0, math.Floor(b.credit))
+	b.credit -= granted
+	return 0, reportGrant(granted)
+}
+
+// cpuBudget returns the compute budget configured for the node, or zero if there is none
+func (sm *Client) cpuBudget(ctx context.Context) float64 {
+	if sm.ServiceModel.MetricStore == nil {
+		return 0
+	}
+	value, ok := sm.ServiceModel.MetricStore.Get(ctx, uint64(sm.ServiceModel.Node.EnbID), CPUBudgetAttribute)
+	if !ok {
+		return 0
+	}
+	budget, err := strconv.ParseFloat(fmt.Sprintf("%v", value), 64)
+	if err != nil {
+		log.Warnf("Invalid value %v of attribute %s: %v", value, CPUBudgetAttribute, err)
+		return 0
+	}
+	return budget
+}
+
+// reportCost returns the number of measurement records of the report requested by the given action definition for
+// the given cell; zero if the action does not report on the cell
+func (sm *Client) reportCost(ctx context.Context, cellECGI ransimtypes.ECGI, actionDefinition *e2smkpmv2.E2SmKpmActionDefinition) int {
+	cellObjectID := strconv.FormatUint(uint64(cellECGI), 10)
+	switch {
+	case actionDefinition == nil:
+		return len(listMeasTypes())
+	case actionDefinition.GetActionDefinitionFormat1() != nil:
+		if actionDefinition.GetActionDefinitionFormat1().GetCellObjId().GetValue() == cellObjectID {
+			return measInfoCost(actionDefinition.GetActionDefinitionFormat1().GetMeasInfoList())
+		}
+	case actionDefinition.GetActionDefinitionFormat2() != nil:
+		subscriptInfo := actionDefinition.GetActionDefinitionFormat2().GetSubscriptInfo()
+		imsi, err := strconv.ParseUint(actionDefinition.GetActionDefinitionFormat2().GetUeId().GetValue(), 10, 64)
+		if err != nil || subscriptInfo.GetCellObjId().GetValue() != cellObjectID {
+			return 0
+		}
+		if ue, err := sm.ServiceModel.UEs.Get(ctx, ransimtypes.IMSI(imsi)); err == nil && ue.Cell != nil && ue.Cell.ECGI == cellECGI {
+			return measInfoCost(subscriptInfo.GetMeasInfoList())
+		}
+	case actionDefinition.GetActionDefinitionFormat3() != nil:
+		if actionDefinition.GetActionDefinitionFormat3().GetCellObjId().GetValue() == cellObjectID {
+			return len(actionDefinition.GetActionDefinitionFormat3().GetMeasCondList().GetValue())
+		}
+	}
+	return 0
+}
+
+// measInfoCost returns the number of measurement records reporting the given measurements, one per requested label
+func measInfoCost(measInfoList *e2smkpmv2.MeasurementInfoList) int {
+	cost := 0
+	for _, measInfo := range measInfoList.GetValue() {
+		if labels := len(measInfo.GetLabelInfoList().GetValue()); labels > 0 {
+			cost += labels
+		} else {
+			cost++
+		}
+	}
+	return cost
+}
+
+// countNodeMetric increments the given node metric
+func (sm *Client) countNodeMetric(ctx context.Context, name string) {
+	if sm.ServiceModel.MetricStore == nil {
+		return
+	}
+	nodeID := uint64(sm.ServiceModel.Node.EnbID)
+	_, _ = sm.ServiceModel.MetricStore.Add(ctx, nodeID, name, 1)
+}
+
+// planReport spends the compute budget of the node on the report requested by the given action definition for the
+// given cell and returns the number of measurement records the node can compute for it. The report is delayed by up
+// to half the report period until the node can afford it; beyond that, it is built with the records the node can
+// compute right away and flagged as incomplete, or skipped if there are none. An error is returned if the context
+// is done while delaying the report
+func (sm *Client) planReport(ctx context.Context, cellECGI ransimtypes.ECGI, actionDefinition *e2smkpmv2.E2SmKpmActionDefinition, reportPeriod time.Duration) (reportGrant, error) {
+	budget := sm.cpuBudget(ctx)
+	if budget <= 0 {
+		return unlimitedGrant, nil
+	}
+	cost := sm.reportCost(ctx, cellECGI, actionDefinition)
+	if cost == 0 {
+		// No report is due for the cell; let the report builder decide
+		return unlimitedGrant, nil
+	}
+	delay, grant := sm.compute.plan(budget, cost, reportPeriod/2, time.Now())
+	switch {
+	case delay > 0:
+		log.Debugf("E2 node %d delaying report of cell %d by %v", sm.ServiceModel.Node.EnbID, cellECGI, delay)
+		sm.countNodeMetric(ctx, IndicationsDelayed)
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return 0, ctx.Err()
+		}
+	case grant == 0:
+		log.Debugf("E2 node %d skipping report of cell %d", sm.ServiceModel.Node.EnbID, cellECGI)
+		sm.countNodeMetric(ctx, IndicationsSkipped)
+	case int(grant) < cost:
+		log.Debugf("E2 node %d reporting %d of %d measurement records of cell %d", sm.ServiceModel.Node.EnbID, grant, cost, cellECGI)
+		sm.countNodeMetric(ctx, IndicationsIncomplete)
+	}
+	return grant, nil
+}
@@ -0,0 +1,105 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package kpm2
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	e2smkpmv2 "github.com/onosproject/onos-e2-sm/servicemodels/e2sm_kpm_v2/v2/e2sm-kpm-v2"
+	"github.com/onosproject/ran-simulator/pkg/model"
+	"github.com/onosproject/ran-simulator/pkg/servicemodel/registry"
+	"github.com/onosproject/ran-simulator/pkg/store/metrics"
+	"github.com/onosproject/ran-simulator/pkg/utils/e2sm/kpm2/measurments"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestComputeBudget(t *testing.T) {
+	budget := &computeBudget{}
+	now := time.Now()
+
+	delay, grant := budget.plan(0, 100, 0, now)
+	assert.Equal(t, time.Duration(0), delay)
+	assert.Equal(t, unlimitedGrant, grant)
+
+	// The node affords reports within its credit right away
+	delay, grant = budget.plan(10, 4, 500*time.Millisecond, now)
+	assert.Equal(t, time.Duration(0), delay)
+	assert.Equal(t, reportGrant(4), grant)
+
+	// Reports exceeding its credit are delayed until the node can afford them
+	delay, grant = budget.plan(10, 8, 500*time.Millisecond, now)
+	assert.Equal(t, 200*time.Millisecond, delay)
+	assert.Equal(t, reportGrant(8), grant)
+
+	// Reports which cannot be delayed long enough are skipped while in debt, and cut short otherwise
+	delay, grant = budget.plan(10, 8, 500*time.Millisecond, now)
+	assert.Equal(t, time.Duration(0), delay)
+	assert.Equal(t, reportGrant(0), grant)
+	delay, grant = budget.plan(10, 20, 500*time.Millisecond, now.Add(time.Second))
+	assert.Equal(t, time.Duration(0), delay)
+	assert.Equal(t, reportGrant(8), grant)
+}
+
+func TestMeasReport(t *testing.T) {
+	record := &e2smkpmv2.MeasurementRecord{
+		Value: []*e2smkpmv2.MeasurementRecordItem{
+			measurments.NewMeasurementRecordItemInteger(measurments.WithIntegerValue(1)).Build(),
+			measurments.NewMeasurementRecordItemInteger(measurments.WithIntegerValue(2)).Build(),
+			measurments.NewMeasurementRecordItemInteger(measurments.WithIntegerValue(3)).Build(),
+		},
+	}
+	assert.Equal(t, measurments.NoIncompleteFlag, newMeasReport(3).incompleteFlag(record))
+	assert.Equal(t, int64(3), record.Value[2].GetInteger())
+
+	// The records beyond the grant are not computed
+	assert.Equal(t, e2smkpmv2.IncompleteFlag_INCOMPLETE_FLAG_TRUE, newMeasReport(1).incompleteFlag(record))
+	assert.Equal(t, int64(1), record.Value[0].GetInteger())
+	assert.IsType(t, &e2smkpmv2.MeasurementRecordItem_NoValue{}, record.Value[1].GetMeasurementRecordItem())
+	assert.IsType(t, &e2smkpmv2.MeasurementRecordItem_NoValue{}, record.Value[2].GetMeasurementRecordItem())
+
+	report := newMeasReport(unlimitedGrant)
+	report.omit("DRB.Unknown", "not supported")
+	assert.Equal(t, e2smkpmv2.IncompleteFlag_INCOMPLETE_FLAG_TRUE, report.incompleteFlag(record))
+}
+
+func TestPlanReport(t *testing.T) {
+	ctx := context.Background()
+	metricStore := metrics.NewMetricsStore()
+	client := &Client{
+		ServiceModel: &registry.ServiceModel{
+			Node:        model.Node{EnbID: 144470},
+			MetricStore: metricStore,
+		},
+	}
+	count := func(name string) uint64 {
+		value, _ := metricStore.Get(ctx, 144470, name)
+		c, _ := value.(uint64)
+		return c
+	}
+
+	grant, err := client.planReport(ctx, 84325717505, nil, time.Second)
+	assert.NoError(t, err)
+	assert.Equal(t, unlimitedGrant, grant)
+
+	// A node short of budget reports what it can compute and then skips its reports
+	assert.NoError(t, metricStore.Set(ctx, 144470, CPUBudgetAttribute, 1.0))
+	grant, err = client.planReport(ctx, 84325717505, nil, time.Second)
+	assert.NoError(t, err)
+	assert.Equal(t, reportGrant(1), grant)
+	grant, err = client.planReport(ctx, 84325717505, nil, time.Second)
+	assert.NoError(t, err)
+	assert.Equal(t, reportGrant(0), grant)
+	assert.Equal(t, uint64(1), count(IndicationsIncomplete))
+	assert.Equal(t, uint64(1), count(IndicationsSkipped))
+
+	// A node slightly short of budget delays its reports
+	assert.NoError(t, metricStore.Set(ctx, 144470, CPUBudgetAttribute, "1000"))
+	grant, err = client.planReport(ctx, 84325717505, nil, time.Second)
+	assert.NoError(t, err)
+	assert.Equal(t, reportGrant(len(listMeasTypes())), grant)
+	assert.Equal(t, uint64(1), count(IndicationsDelayed))
+}
@@ -24,6 +24,70 @@ const (
 	RRCConnAvg
 	// RRCConnMax  the max number of users in RRC connected mode during each granularity period.
 	RRCConnMax
+	// DRBEstabAttTot total number of DRB setup attempts
+	DRBEstabAttTot
+	// DRBEstabSuccTot total number of successfully established DRBs
+	DRBEstabSuccTot
+	// DRBRelActNbrTot total number of released DRBs
+	DRBRelActNbrTot
+	// PEEAvgPower average power consumed by the cell in Watts
+	PEEAvgPower
+	// ESSleepTransTot total number of transitions into the energy-saving state
+	ESSleepTransTot
+	// ESWakeTransTot total number of transitions out of the energy-saving state
+	ESWakeTransTot
+	// PAGAttTot total number of paging records broadcast by the cell
+	PAGAttTot
+	// PAGSuccTot total number of paged UEs which responded via the cell
+	PAGSuccTot
+	// TAUAttTot total number of tracking area update attempts
+	TAUAttTot
+	// TAUSuccTot total number of successful tracking area updates
+	TAUSuccTot
+	// CSGRejTot total number of UEs rejected as non-members of the closed subscriber group of the cell
+	CSGRejTot
+	// HOFailTot total number of failed handovers from the cell
+	HOFailTot
+	// RLFTot total number of radio link failures of UEs served by the cell
+	RLFTot
+	// HOTooLateTot total number of radio link failures a handover from the cell should have prevented
+	HOTooLateTot
+	// HOTooEarlyTot total number of handovers from the cell followed by re-establishment at the cell
+	HOTooEarlyTot
+	// HOWrongCellTot total number of handovers from the cell followed by re-establishment at another cell
+	HOWrongCellTot
+	// MMHoPrepInterReq total number of inter-node handover preparations requested by the cell
+	MMHoPrepInterReq
+	// MMHoPrepInterSucc total number of inter-node handover preparations of the cell acknowledged by the target node
+	MMHoPrepInterSucc
+	// MMHoPrepInterFail total number of inter-node handover preparations of the cell rejected or timed out
+	MMHoPrepInterFail
+	// MMHoResAlloInterReq total number of inter-node handover requests received by the cell
+	MMHoResAlloInterReq
+	// MMHoResAlloInterSucc total number of inter-node handover requests acknowledged by the cell
+	MMHoResAlloInterSucc
+	// MMHoResAlloInterFail total number of inter-node handover requests rejected by the cell
+	MMHoResAlloInterFail
+	// MMHoExeInterReq total number of prepared inter-node handovers executed from the cell
+	MMHoExeInterReq
+	// MMHoExeInterSucc total number of successful inter-node handovers from the cell
+	MMHoExeInterSucc
+	// MMHoExeInterFail total number of inter-node handovers from the cell failed during execution
+	MMHoExeInterFail
+	// MMHoExeIntraReq total number of handovers executed from the cell to a cell of the same node
+	MMHoExeIntraReq
+	// MMHoExeIntraSucc total number of successful handovers from the cell to a cell of the same node
+	MMHoExeIntraSucc
+	// MMHoExeIntraFail total number of failed handovers from the cell to a cell of the same node
+	MMHoExeIntraFail
+	// DRBPdcpSduVolumeDL downlink volume transferred to the UEs of the cell in kbit
+	DRBPdcpSduVolumeDL
+	// DRBPdcpSduVolumeUL uplink volume transferred from the UEs of the cell in kbit
+	DRBPdcpSduVolumeUL
+	// DRBUEThpDl mean downlink throughput of the UEs of the cell in kbit/s
+	DRBUEThpDl
+	// DRBUEThpUl mean uplink throughput of the UEs of the cell in kbit/s
+	DRBUEThpUl
 )
 
 func (m MeasTypeName) String() string {
@@ -34,46 +98,207 @@ func (m MeasTypeName) String() string {
 		"RRC.ConnReEstabAtt.HOFail",
 		"RRC.ConnReEstabAtt.Other",
 		"RRC.Conn.Avg",
-		"RRC.Conn.Max"}[m]
+		"RRC.Conn.Max",
+		"DRB.EstabAtt.Tot",
+		"DRB.EstabSucc.Tot",
+		"DRB.RelActNbr.Tot",
+		"PEE.AvgPower",
+		"ES.SleepTrans.Tot",
+		"ES.WakeTrans.Tot",
+		"PAG.Att.Tot",
+		"PAG.Succ.Tot",
+		"TAU.Att.Tot",
+		"TAU.Succ.Tot",
+		"CSG.Rej.Tot",
+		"HO.Fail.Tot",
+		"RLF.Tot",
+		"HO.TooLate.Tot",
+		"HO.TooEarly.Tot",
+		"HO.WrongCell.Tot",
+		"MM.HoPrepInterReq",
+		"MM.HoPrepInterSucc",
+		"MM.HoPrepInterFail",
+		"MM.HoResAlloInterReq",
+		"MM.HoResAlloInterSucc",
+		"MM.HoResAlloInterFail",
+		"MM.HoExeInterReq",
+		"MM.HoExeInterSucc",
+		"MM.HoExeInterFail",
+		"MM.HoExeIntraReq",
+		"MM.HoExeIntraSucc",
+		"MM.HoExeIntraFail",
+		"DRB.PdcpSduVolumeDL",
+		"DRB.PdcpSduVolumeUL",
+		"DRB.UEThpDl",
+		"DRB.UEThpUl"}[m]
 }
 
 // MeasType meas type
 type MeasType struct {
-	measTypeName MeasTypeName
+	measTypeName string
 	measTypeID   int32
 }
 
+// measTypes are the built-in measurement types; custom ones are added by registering a measurement driver
 var measTypes = []MeasType{
 	{
-		measTypeName: RRCConnEstabAttTot,
+		measTypeName: RRCConnEstabAttTot.String(),
 		measTypeID:   1,
 	},
 	{
-		measTypeName: RRCConnEstabSuccTot,
+		measTypeName: RRCConnEstabSuccTot.String(),
 		measTypeID:   2,
 	},
 	{
-		measTypeName: RRCConnReEstabAttTot,
+		measTypeName: RRCConnReEstabAttTot.String(),
 		measTypeID:   3,
 	},
 	{
-		measTypeName: RRCConnReEstabAttreconfigFail,
+		measTypeName: RRCConnReEstabAttreconfigFail.String(),
 		measTypeID:   4,
 	},
 	{
-		measTypeName: RRCConnReEstabAttHOFail,
+		measTypeName: RRCConnReEstabAttHOFail.String(),
 		measTypeID:   5,
 	},
 	{
-		measTypeName: RRCConnReEstabAttOther,
+		measTypeName: RRCConnReEstabAttOther.String(),
 		measTypeID:   6,
 	},
 	{
-		measTypeName: RRCConnAvg,
+		measTypeName: RRCConnAvg.String(),
 		measTypeID:   7,
 	},
 	{
-		measTypeName: RRCConnMax,
+		measTypeName: RRCConnMax.String(),
 		measTypeID:   8,
 	},
+	{
+		measTypeName: DRBEstabAttTot.String(),
+		measTypeID:   9,
+	},
+	{
+		measTypeName: DRBEstabSuccTot.String(),
+		measTypeID:   10,
+	},
+	{
+		measTypeName: DRBRelActNbrTot.String(),
+		measTypeID:   11,
+	},
+	{
+		measTypeName: PEEAvgPower.String(),
+		measTypeID:   12,
+	},
+	{
+		measTypeName: ESSleepTransTot.String(),
+		measTypeID:   13,
+	},
+	{
+		measTypeName: ESWakeTransTot.String(),
+		measTypeID:   14,
+	},
+	{
+		measTypeName: PAGAttTot.String(),
+		measTypeID:   15,
+	},
+	{
+		measTypeName: PAGSuccTot.String(),
+		measTypeID:   16,
+	},
+	{
+		measTypeName: TAUAttTot.String(),
+		measTypeID:   17,
+	},
+	{
+		measTypeName: TAUSuccTot.String(),
+		measTypeID:   18,
+	},
+	{
+		measTypeName: CSGRejTot.String(),
+		measTypeID:   19,
+	},
+	{
+		measTypeName: HOFailTot.String(),
+		measTypeID:   20,
+	},
+	{
+		measTypeName: RLFTot.String(),
+		measTypeID:   21,
+	},
+	{
+		measTypeName: HOTooLateTot.String(),
+		measTypeID:   22,
+	},
+	{
+		measTypeName: HOTooEarlyTot.String(),
+		measTypeID:   23,
+	},
+	{
+		measTypeName: HOWrongCellTot.String(),
+		measTypeID:   24,
+	},
+	{
+		measTypeName: MMHoPrepInterReq.String(),
+		measTypeID:   25,
+	},
+	{
+		measTypeName: MMHoPrepInterSucc.String(),
+		measTypeID:   26,
+	},
+	{
+		measTypeName: MMHoPrepInterFail.String(),
+		measTypeID:   27,
+	},
+	{
+		measTypeName: MMHoResAlloInterReq.String(),
+		measTypeID:   28,
+	},
+	{
+		measTypeName: MMHoResAlloInterSucc.String(),
+		measTypeID:   29,
+	},
+	{
+		measTypeName: MMHoResAlloInterFail.String(),
+		measTypeID:   30,
+	},
+	{
+		measTypeName: MMHoExeInterReq.String(),
+		measTypeID:   31,
+	},
+	{
+		measTypeName: MMHoExeInterSucc.String(),
+		measTypeID:   32,
+	},
+	{
+		measTypeName: MMHoExeInterFail.String(),
+		measTypeID:   33,
+	},
+	{
+		measTypeName: MMHoExeIntraReq.String(),
+		measTypeID:   34,
+	},
+	{
+		measTypeName: MMHoExeIntraSucc.String(),
+		measTypeID:   35,
+	},
+	{
+		measTypeName: MMHoExeIntraFail.String(),
+		measTypeID:   36,
+	},
+	{
+		measTypeName: DRBPdcpSduVolumeDL.String(),
+		measTypeID:   37,
+	},
+	{
+		measTypeName: DRBPdcpSduVolumeUL.String(),
+		measTypeID:   38,
+	},
+	{
+		measTypeName: DRBUEThpDl.String(),
+		measTypeID:   39,
+	},
+	{
+		measTypeName: DRBUEThpUl.String(),
+		measTypeID:   40,
+	},
 }
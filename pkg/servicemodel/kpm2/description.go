@@ -0,0 +1,198 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package kpm2
+
+import (
+	"strconv"
+
+	ransimtypes "github.com/onosproject/onos-api/go/onos/ransim/types"
+	"github.com/onosproject/onos-e2-sm/servicemodels/e2sm_kpm_v2/pdubuilder"
+	e2smkpmv2 "github.com/onosproject/onos-e2-sm/servicemodels/e2sm_kpm_v2/v2/e2sm-kpm-v2"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/onosproject/ran-simulator/pkg/model"
+	"github.com/onosproject/ran-simulator/pkg/modelplugins"
+	"github.com/onosproject/ran-simulator/pkg/servicemodel/registry"
+	kpm2gNBID "github.com/onosproject/ran-simulator/pkg/utils/e2sm/kpm2/id/gnbid"
+	"github.com/onosproject/ran-simulator/pkg/utils/e2sm/kpm2/measobjectitem"
+	"github.com/onosproject/ran-simulator/pkg/utils/e2sm/kpm2/measurments"
+	"github.com/onosproject/ran-simulator/pkg/utils/e2sm/kpm2/nodeitem"
+	"github.com/onosproject/ran-simulator/pkg/utils/e2sm/kpm2/ranfuncdescription"
+	"github.com/onosproject/ran-simulator/pkg/utils/e2sm/kpm2/reportstyle"
+	"google.golang.org/protobuf/proto"
+)
+
+// Limits of the RIC KPM node list of the RAN function description as per E2SM-KPM v2
+const (
+	// maxKPMNodes is the maximum number of RIC KPM node items, i.e. maxnoofKPMNodes
+	maxKPMNodes = 1024
+	// maxCellsPerKPMNode is the maximum number of cell measurement objects of a RIC KPM node item, i.e.
+	// maxCellingNBorNodes
+	maxCellsPerKPMNode = 16384
+)
+
+// descriptionBuilder returns the builder of the RAN function description of the nodes of the model
+func descriptionBuilder(m *model.Model, modelPluginRegistry modelplugins.ModelRegistry) func(node model.Node) ([]byte, error) {
+	return func(node model.Node) ([]byte, error) {
+		return buildRanFunctionDescription(node, m, modelPluginRegistry)
+	}
+}
+
+// buildRanFunctionDescription builds the ASN.1 encoded RAN function description of the node, listing its cells as
+// measurement objects
+func buildRanFunctionDescription(node model.Node, m *model.Model, modelPluginRegistry modelplugins.ModelRegistry) ([]byte, error) {
+	ranFuncDescPdu, err := ranFunctionDescriptionPdu(node, m)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+
+	protoBytes, err := proto.Marshal(ranFuncDescPdu)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+	kpmModelPlugin, _ := modelPluginRegistry.GetPlugin(ranFunctionE2SmOid)
+	if kpmModelPlugin == nil {
+		return nil, errors.New(errors.Invalid, "model plugin is nil")
+	}
+	return kpmModelPlugin.RanFuncDescriptionProtoToASN1(protoBytes)
+}
+
+// describedCells returns the cells of the node listed as measurement objects in the RAN function description, split
+// into the cells of each RIC KPM node item of the node. At most maxDescribedCells cells are listed, all of them if it
+// is zero and none if it is negative, and at most cellsPerItem cells are listed by each item, up to the maximum of
+// the E2SM if it is not positive.
+func describedCells(cells []ransimtypes.ECGI, maxDescribedCells int, cellsPerItem int) [][]ransimtypes.ECGI {
+	if maxDescribedCells < 0 {
+		return [][]ransimtypes.ECGI{nil}
+	}
+	if cellsPerItem <= 0 || cellsPerItem > maxCellsPerKPMNode {
+		cellsPerItem = maxCellsPerKPMNode
+	}
+	limit := maxKPMNodes * cellsPerItem
+	if maxDescribedCells > 0 && maxDescribedCells < limit {
+		limit = maxDescribedCells
+	}
+	if len(cells) > limit {
+		cells = cells[:limit]
+	}
+	chunks := make([][]ransimtypes.ECGI, 0, len(cells)/cellsPerItem+1)
+	for len(cells) > cellsPerItem {
+		chunks = append(chunks, cells[:cellsPerItem])
+		cells = cells[cellsPerItem:]
+	}
+	return append(chunks, cells)
+}
+
+// ranFunctionDescriptionPdu creates the RAN function description of the node; the cells of the node are listed as
+// measurement objects as configured for the service model
+func ranFunctionDescriptionPdu(node model.Node, m *model.Model) (*e2smkpmv2.E2SmKpmRanfunctionDescription, error) {
+	plmnID := ransimtypes.NewUint24(uint32(m.PlmnID))
+
+	// Creates an indication header
+	gNBID := &e2smkpmv2.BitString{
+		Value: uint64(node.EnbID),
+		Len:   22,
+	}
+
+	globalKPMNodeID, err := kpm2gNBID.NewGlobalGNBID(
+		kpm2gNBID.WithPlmnID(plmnID.Value()),
+		kpm2gNBID.WithGNBIDChoice(gNBID)).Build()
+	if err != nil {
+		return nil, err
+	}
+
+	config := serviceModelConfig(m)
+	chunks := describedCells(node.Cells, config.MaxDescribedCells, config.DescribedCellsPerItem)
+	described := 0
+	for _, chunk := range chunks {
+		described += len(chunk)
+	}
+	if described < len(node.Cells) {
+		log.Warnf("RAN function description of E2 node %d lists %d of its %d cells", node.EnbID, described, len(node.Cells))
+	}
+	reportKpmNodeList := make([]*e2smkpmv2.RicKpmnodeItem, 0, len(chunks))
+	for _, chunk := range chunks {
+		cellMeasObjectItems := make([]*e2smkpmv2.CellMeasurementObjectItem, 0, len(chunk))
+		for _, cellEcgi := range chunk {
+			eci := ransimtypes.GetECI(uint64(cellEcgi))
+			eciBitString := &e2smkpmv2.BitString{
+				Value: uint64(eci),
+				Len:   28,
+			}
+
+			cellGlobalID, err := pdubuilder.CreateCellGlobalIDEUTRACGI(plmnID.ToBytes(), eciBitString)
+			if err != nil {
+				return nil, err
+			}
+
+			cellMeasObjItem := measobjectitem.NewCellMeasObjectItem(
+				measobjectitem.WithCellObjectID(strconv.FormatUint(uint64(cellEcgi), 10)),
+				measobjectitem.WithCellGlobalID(cellGlobalID)).
+				Build()
+
+			cellMeasObjectItems = append(cellMeasObjectItems, cellMeasObjItem)
+		}
+		if len(cellMeasObjectItems) == 0 {
+			// The cell measurement object list is optional but must not be empty
+			cellMeasObjectItems = nil
+		}
+
+		kpmNodeItem := nodeitem.NewNodeItem(
+			nodeitem.WithGlobalKpmNodeID(globalKPMNodeID),
+			nodeitem.WithCellMeasurementObjectItems(cellMeasObjectItems)).
+			Build()
+		reportKpmNodeList = append(reportKpmNodeList, kpmNodeItem)
+	}
+
+	ricEventTriggerStyleItem := pdubuilder.CreateRicEventTriggerStyleItem(ricStyleType, ricStyleName, ricFormatType)
+
+	ricEventTriggerStyleList := make([]*e2smkpmv2.RicEventTriggerStyleItem, 0)
+	ricEventTriggerStyleList = append(ricEventTriggerStyleList, ricEventTriggerStyleItem)
+
+	measInfoActionList := e2smkpmv2.MeasurementInfoActionList{
+		Value: make([]*e2smkpmv2.MeasurementInfoActionItem, 0),
+	}
+
+	for _, measType := range listMeasTypes() {
+		log.Debug("Measurement Name and ID:", measType.measTypeName, measType.measTypeID)
+		measInfoActionItem, _ := measurments.NewMeasurementInfoActionItem(
+			measurments.WithMeasTypeName(measType.measTypeName),
+			measurments.WithMeasTypeID(measType.measTypeID)).Build()
+
+		measInfoActionList.Value = append(measInfoActionList.Value, measInfoActionItem)
+
+	}
+
+	ricReportStyleList := make([]*e2smkpmv2.RicReportStyleItem, 0, len(reportStyles))
+	for _, style := range reportStyles {
+		reportStyleItem := reportstyle.NewReportStyleItem(
+			reportstyle.WithRICStyleType(style.styleType),
+			reportstyle.WithRICStyleName(style.name),
+			reportstyle.WithRICFormatType(style.actionFormatType),
+			reportstyle.WithMeasInfoActionList(&measInfoActionList),
+			reportstyle.WithIndicationHdrFormatType(ricIndHdrFormat),
+			reportstyle.WithIndicationMsgFormatType(style.indMsgFormatType)).
+			Build()
+		ricReportStyleList = append(ricReportStyleList, reportStyleItem)
+	}
+
+	return ranfuncdescription.NewRANFunctionDescription(
+		ranfuncdescription.WithRANFunctionShortName(ranFunctionShortName),
+		ranfuncdescription.WithRANFunctionE2SmOID(ranFunctionE2SmOid),
+		ranfuncdescription.WithRANFunctionDescription(ranFunctionDescription),
+		ranfuncdescription.WithRANFunctionInstance(ranFunctionInstance),
+		ranfuncdescription.WithRICKPMNodeList(reportKpmNodeList),
+		ranfuncdescription.WithRICEventTriggerStyleList(ricEventTriggerStyleList),
+		ranfuncdescription.WithRICReportStyleList(ricReportStyleList)).
+		Build()
+}
+
+// serviceModelConfig returns the configuration of the KPM v2 service model in the model, if any
+func serviceModelConfig(m *model.Model) model.ServiceModel {
+	sm := registry.ServiceModel{RanFunctionID: registry.Kpm2, Model: m}
+	config, _ := sm.Config()
+	return config
+}
@@ -0,0 +1,103 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package kpm2
+
+import (
+	"testing"
+
+	ransimtypes "github.com/onosproject/onos-api/go/onos/ransim/types"
+	e2smkpmv2 "github.com/onosproject/onos-e2-sm/servicemodels/e2sm_kpm_v2/v2/e2sm-kpm-v2"
+	"github.com/onosproject/ran-simulator/pkg/model"
+	"github.com/onosproject/ran-simulator/pkg/servicemodel/registry"
+	smtest "github.com/onosproject/ran-simulator/pkg/servicemodel/test"
+	"github.com/onosproject/ran-simulator/pkg/store/subscriptions"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+)
+
+func generateCells(n int) []ransimtypes.ECGI {
+	cells := make([]ransimtypes.ECGI, n)
+	for i := range cells {
+		cells[i] = ransimtypes.ECGI(84325717505 + i)
+	}
+	return cells
+}
+
+func TestDescribedCells(t *testing.T) {
+	cells := generateCells(10)
+	assert.Equal(t, [][]ransimtypes.ECGI{cells}, describedCells(cells, 0, 0))
+	assert.Equal(t, [][]ransimtypes.ECGI{nil}, describedCells(cells, -1, 0))
+	assert.Equal(t, [][]ransimtypes.ECGI{cells[:4]}, describedCells(cells, 4, 0))
+	assert.Equal(t, [][]ransimtypes.ECGI{cells[:4], cells[4:8], cells[8:]}, describedCells(cells, 0, 4))
+	assert.Equal(t, [][]ransimtypes.ECGI{cells[:4], cells[4:6]}, describedCells(cells, 6, 4))
+	assert.Equal(t, [][]ransimtypes.ECGI{nil}, describedCells(nil, 0, 0))
+
+	// The number of items is bounded by the E2SM
+	chunks := describedCells(generateCells(maxKPMNodes+10), 0, 1)
+	assert.Len(t, chunks, maxKPMNodes)
+}
+
+func TestMaximumSizeDescription(t *testing.T) {
+	m := &model.Model{
+		PlmnID: 314628,
+		ServiceModels: map[string]model.ServiceModel{
+			"kpm2": {ID: int(registry.Kpm2)},
+		},
+	}
+	node := model.Node{EnbID: 144470, Cells: generateCells(2*maxCellsPerKPMNode + 5)}
+
+	// The cells exceeding the maximum of an item are listed by additional items of the node
+	description, err := ranFunctionDescriptionPdu(node, m)
+	assert.NoError(t, err)
+	assert.NoError(t, description.Validate())
+	items := description.GetRicKpmNodeList()
+	assert.Len(t, items, 3)
+	assert.Len(t, items[0].GetCellMeasurementObjectList(), maxCellsPerKPMNode)
+	assert.Len(t, items[1].GetCellMeasurementObjectList(), maxCellsPerKPMNode)
+	assert.Len(t, items[2].GetCellMeasurementObjectList(), 5)
+	assert.Equal(t, "84325717505", items[0].GetCellMeasurementObjectList()[0].GetCellObjectId().GetValue())
+	assert.Equal(t, items[0].GetRicKpmnodeType(), items[2].GetRicKpmnodeType())
+
+	config := m.ServiceModels["kpm2"]
+	config.MaxDescribedCells = 100
+	config.DescribedCellsPerItem = 30
+	m.ServiceModels["kpm2"] = config
+	description, err = ranFunctionDescriptionPdu(node, m)
+	assert.NoError(t, err)
+	items = description.GetRicKpmNodeList()
+	assert.Len(t, items, 4)
+	assert.Len(t, items[3].GetCellMeasurementObjectList(), 10)
+
+	// Without cells the node is described without measurement objects
+	config.MaxDescribedCells = -1
+	m.ServiceModels["kpm2"] = config
+	description, err = ranFunctionDescriptionPdu(node, m)
+	assert.NoError(t, err)
+	items = description.GetRicKpmNodeList()
+	assert.Len(t, items, 1)
+	assert.Nil(t, items[0].GetCellMeasurementObjectList())
+}
+
+func TestServiceModelDescription(t *testing.T) {
+	m := &model.Model{}
+	assert.NoError(t, model.LoadConfig(m, "../../model/test"))
+	modelPluginRegistry := smtest.NewModelRegistry(smtest.NewModelPlugin(ranFunctionShortName, modelVersion, ranFunctionE2SmOid))
+	node := m.Nodes["node1"]
+	sm, err := NewServiceModel(node, m, modelPluginRegistry, subscriptions.NewStore(), nil, nil, nil, nil)
+	assert.NoError(t, err)
+
+	// The pass-through plugin leaves the description encoded as a protobuf message
+	description := &e2smkpmv2.E2SmKpmRanfunctionDescription{}
+	assert.NoError(t, proto.Unmarshal(sm.Description, description))
+	cells := description.GetRicKpmNodeList()[0].GetCellMeasurementObjectList()
+	assert.Len(t, cells, len(node.Cells))
+	assert.Equal(t, "84325717505", cells[0].GetCellObjectId().GetValue())
+
+	// The description is rebuilt once the cells of the node change
+	node.Cells = node.Cells[:1]
+	rebuilt, err := sm.DescriptionBuilder(node)
+	assert.NoError(t, err)
+	assert.NotEqual(t, sm.Description, rebuilt)
+}
@@ -6,18 +6,11 @@ package kpm2
 
 import (
 	"context"
-	"encoding/binary"
+	"math"
 	"strconv"
+	"sync"
 	"time"
 
-	"github.com/onosproject/ran-simulator/pkg/utils/e2sm/kpm2/measobjectitem"
-
-	"github.com/onosproject/ran-simulator/pkg/utils/e2sm/kpm2/reportstyle"
-
-	"github.com/onosproject/ran-simulator/pkg/utils/e2sm/kpm2/ranfuncdescription"
-
-	"github.com/onosproject/ran-simulator/pkg/utils/e2sm/kpm2/nodeitem"
-
 	"github.com/onosproject/ran-simulator/pkg/utils/e2sm/kpm2/measurments"
 
 	e2smtypes "github.com/onosproject/onos-api/go/onos/e2t/e2sm"
@@ -26,17 +19,18 @@ import (
 	kpm2MessageFormat1 "github.com/onosproject/ran-simulator/pkg/utils/e2sm/kpm2/indication/messageformat1"
 
 	ransimtypes "github.com/onosproject/onos-api/go/onos/ransim/types"
-	"github.com/onosproject/onos-e2-sm/servicemodels/e2sm_kpm_v2/pdubuilder"
 	e2smkpmv2 "github.com/onosproject/onos-e2-sm/servicemodels/e2sm_kpm_v2/v2/e2sm-kpm-v2"
 	e2apies "github.com/onosproject/onos-e2t/api/e2ap/v1beta2/e2ap-ies"
 	e2appducontents "github.com/onosproject/onos-e2t/api/e2ap/v1beta2/e2ap-pdu-contents"
 	e2aptypes "github.com/onosproject/onos-e2t/pkg/southbound/e2ap101/types"
 	"github.com/onosproject/onos-lib-go/pkg/errors"
 	"github.com/onosproject/onos-lib-go/pkg/logging"
+	"github.com/onosproject/ran-simulator/pkg/kpiprofile"
 	"github.com/onosproject/ran-simulator/pkg/model"
 	"github.com/onosproject/ran-simulator/pkg/modelplugins"
 	"github.com/onosproject/ran-simulator/pkg/servicemodel"
 	"github.com/onosproject/ran-simulator/pkg/servicemodel/registry"
+	"github.com/onosproject/ran-simulator/pkg/store/metrics"
 	"github.com/onosproject/ran-simulator/pkg/store/nodes"
 	"github.com/onosproject/ran-simulator/pkg/store/subscriptions"
 	"github.com/onosproject/ran-simulator/pkg/store/ues"
@@ -55,7 +49,6 @@ const (
 	ricStyleType           = 1
 	ricStyleName           = "Periodic Report"
 	ricFormatType          = 1
-	ricIndMsgFormat        = 1
 	ricIndHdrFormat        = 1
 	ranFunctionDescription = "KPM 2.0 Monitor"
 	ranFunctionShortName   = "ORAN-E2SM-KPM"
@@ -75,16 +68,43 @@ const (
 // Client kpm service model client
 type Client struct {
 	ServiceModel *registry.ServiceModel
+	// profiles modulates the reported measurement values over time
+	profiles *kpiprofile.Engine
+	// compute tracks the compute budget the node spends on its reports
+	compute computeBudget
+	// cellsMu guards the cells of the node, which change while the node is reporting
+	cellsMu sync.RWMutex
+}
+
+// cells returns the current cells of the node
+func (sm *Client) cells() []ransimtypes.ECGI {
+	sm.cellsMu.RLock()
+	defer sm.cellsMu.RUnlock()
+	return sm.ServiceModel.Node.Cells
 }
 
-// NewServiceModel creates a new service model
+// updateNode updates the cells of the node, so that new cells are reported and may be subscribed to
+func (sm *Client) updateNode(node model.Node) {
+	sm.cellsMu.Lock()
+	defer sm.cellsMu.Unlock()
+	sm.ServiceModel.Node.Cells = append([]ransimtypes.ECGI(nil), node.Cells...)
+}
+
+// NewServiceModel creates a new service model applying the given KPI anomalies, if any, to the reported measurements
 func NewServiceModel(node model.Node, model *model.Model, modelPluginRegistry modelplugins.ModelRegistry,
-	subStore *subscriptions.Subscriptions, nodeStore nodes.Store, ueStore ues.Store) (registry.ServiceModel, error) {
+	subStore *subscriptions.Subscriptions, nodeStore nodes.Store, ueStore ues.Store, metricStore metrics.Store,
+	anomalies *kpiprofile.Anomalies) (registry.ServiceModel, error) {
 	kpmSm := registry.ServiceModel{
-		RanFunctionID:       registry.Kpm2,
-		ModelName:           ranFunctionShortName,
-		Revision:            1,
-		OID:                 ranFunctionE2SmOid,
+		RanFunctionID: registry.Kpm2,
+		ModelName:     ranFunctionShortName,
+		Revision:      1,
+		OID:           ranFunctionE2SmOid,
+		Payloads: registry.Payloads{
+			registry.EventTriggerPayload:      func() proto.Message { return &e2smkpmv2.E2SmKpmEventTriggerDefinition{} },
+			registry.ActionDefinitionPayload:  func() proto.Message { return &e2smkpmv2.E2SmKpmActionDefinition{} },
+			registry.IndicationHeaderPayload:  func() proto.Message { return &e2smkpmv2.E2SmKpmIndicationHeader{} },
+			registry.IndicationMessagePayload: func() proto.Message { return &e2smkpmv2.E2SmKpmIndicationMessage{} },
+		},
 		Version:             modelVersion,
 		ModelPluginRegistry: modelPluginRegistry,
 		Node:                node,
@@ -92,121 +112,27 @@ func NewServiceModel(node model.Node, model *model.Model, modelPluginRegistry mo
 		Subscriptions:       subStore,
 		Nodes:               nodeStore,
 		UEs:                 ueStore,
+		MetricStore:         metricStore,
 	}
 	kpmClient := &Client{
 		ServiceModel: &kpmSm,
+		profiles:     kpiprofile.NewEngine(model, anomalies),
 	}
 
 	kpmSm.Client = kpmClient
-
-	plmnID := ransimtypes.NewUint24(uint32(kpmSm.Model.PlmnID))
-
-	cells := node.Cells
-	cellMeasObjectItems := make([]*e2smkpmv2.CellMeasurementObjectItem, 0)
-	for _, cellEcgi := range cells {
-		eci := ransimtypes.GetECI(uint64(cellEcgi))
-		eciBitString := &e2smkpmv2.BitString{
-			Value: uint64(eci),
-			Len:   28,
-		}
-
-		cellGlobalID, err := pdubuilder.CreateCellGlobalIDEUTRACGI(plmnID.ToBytes(), eciBitString)
-		if err != nil {
-			log.Error(err)
-			return registry.ServiceModel{}, err
-		}
-
-		cellMeasObjItem := measobjectitem.NewCellMeasObjectItem(
-			measobjectitem.WithCellObjectID(strconv.FormatUint(uint64(cellEcgi), 10)),
-			measobjectitem.WithCellGlobalID(cellGlobalID)).
-			Build()
-
-		cellMeasObjectItems = append(cellMeasObjectItems, cellMeasObjItem)
-	}
-
-	// Creates an indication header
-	gNBID := &e2smkpmv2.BitString{
-		Value: uint64(node.EnbID),
-		Len:   22,
-	}
-
-	globalKPMNodeID, err := kpm2gNBID.NewGlobalGNBID(
-		kpm2gNBID.WithPlmnID(plmnID.Value()),
-		kpm2gNBID.WithGNBIDChoice(gNBID)).Build()
-	if err != nil {
-		log.Error(err)
-		return registry.ServiceModel{}, err
-	}
-
-	kpmNodeItem := nodeitem.NewNodeItem(
-		nodeitem.WithGlobalKpmNodeID(globalKPMNodeID),
-		nodeitem.WithCellMeasurementObjectItems(cellMeasObjectItems)).
-		Build()
-
-	reportKpmNodeList := make([]*e2smkpmv2.RicKpmnodeItem, 0)
-	reportKpmNodeList = append(reportKpmNodeList, kpmNodeItem)
-
-	ricEventTriggerStyleItem := pdubuilder.CreateRicEventTriggerStyleItem(ricStyleType, ricStyleName, ricFormatType)
-
-	ricEventTriggerStyleList := make([]*e2smkpmv2.RicEventTriggerStyleItem, 0)
-	ricEventTriggerStyleList = append(ricEventTriggerStyleList, ricEventTriggerStyleItem)
-
-	measInfoActionList := e2smkpmv2.MeasurementInfoActionList{
-		Value: make([]*e2smkpmv2.MeasurementInfoActionItem, 0),
-	}
-
-	for _, measType := range measTypes {
-		log.Debug("Measurement Name and ID:", measType.measTypeName, measType.measTypeID)
-		measInfoActionItem, _ := measurments.NewMeasurementInfoActionItem(
-			measurments.WithMeasTypeName(measType.measTypeName.String()),
-			measurments.WithMeasTypeID(measType.measTypeID)).Build()
-
-		measInfoActionList.Value = append(measInfoActionList.Value, measInfoActionItem)
-
-	}
-
-	reportStyleItem := reportstyle.NewReportStyleItem(
-		reportstyle.WithRICStyleType(ricStyleType),
-		reportstyle.WithRICStyleName(ricStyleName),
-		reportstyle.WithRICFormatType(ricFormatType),
-		reportstyle.WithMeasInfoActionList(&measInfoActionList),
-		reportstyle.WithIndicationHdrFormatType(ricIndHdrFormat),
-		reportstyle.WithIndicationMsgFormatType(ricIndMsgFormat)).
-		Build()
-
-	ricReportStyleList := make([]*e2smkpmv2.RicReportStyleItem, 0)
-	ricReportStyleList = append(ricReportStyleList, reportStyleItem)
-
-	ranFuncDescPdu, err := ranfuncdescription.NewRANFunctionDescription(
-		ranfuncdescription.WithRANFunctionShortName(ranFunctionShortName),
-		ranfuncdescription.WithRANFunctionE2SmOID(ranFunctionE2SmOid),
-		ranfuncdescription.WithRANFunctionDescription(ranFunctionDescription),
-		ranfuncdescription.WithRANFunctionInstance(ranFunctionInstance),
-		ranfuncdescription.WithRICKPMNodeList(reportKpmNodeList),
-		ranfuncdescription.WithRICEventTriggerStyleList(ricEventTriggerStyleList),
-		ranfuncdescription.WithRICReportStyleList(ricReportStyleList)).
-		Build()
-
-	if err != nil {
-		log.Error(err)
-		return registry.ServiceModel{}, err
+	if resolution := kpmClient.timestampResolution(); !checkTimestampResolution(resolution) {
+		log.Warnf("Unsupported timestamp resolution %s; Unix seconds are reported instead", resolution)
 	}
 
-	protoBytes, err := proto.Marshal(ranFuncDescPdu)
+	description, err := buildRanFunctionDescription(node, model, modelPluginRegistry)
 	if err != nil {
 		log.Error(err)
 		return registry.ServiceModel{}, err
 	}
-	kpmModelPlugin, _ := modelPluginRegistry.GetPlugin(ranFunctionE2SmOid)
-	if kpmModelPlugin == nil {
-		return registry.ServiceModel{}, errors.New(errors.Invalid, "model plugin is nil")
-	}
-	ranFuncDescBytes, err := kpmModelPlugin.RanFuncDescriptionProtoToASN1(protoBytes)
-	if err != nil {
-		log.Error(err)
-		return registry.ServiceModel{}, err
-	}
-	kpmSm.Description = ranFuncDescBytes
+	kpmSm.Description = description
+	// The description lists the cells of the node, so it is rebuilt whenever they change
+	kpmSm.DescriptionBuilder = descriptionBuilder(model, modelPluginRegistry)
+	kpmSm.NodeUpdater = kpmClient.updateNode
 	return kpmSm, nil
 }
 
@@ -215,14 +141,13 @@ func (sm *Client) createDefaultMeasInfoList() (*e2smkpmv2.MeasurementInfoList, e
 	measInfoList := e2smkpmv2.MeasurementInfoList{
 		Value: make([]*e2smkpmv2.MeasurementInfoItem, 0),
 	}
-	labelInfoList, err := sm.createInfoLabelList()
-	if err != nil {
-		return nil, err
+	labelInfoList := &e2smkpmv2.LabelInfoList{
+		Value: []*e2smkpmv2.LabelInfoItem{{MeasLabel: sm.createPlmnLabel()}},
 	}
 
-	for _, measType := range measTypes {
+	for _, measType := range listMeasTypes() {
 		measTypeName, _ := measurments.NewMeasurementTypeMeasName(
-			measurments.WithMeasurementName(measType.measTypeName.String())).
+			measurments.WithMeasurementName(measType.measTypeName)).
 			Build()
 		measInfoItem, _ := measurments.NewMeasurementInfoItem(
 			measurments.WithMeasType(measTypeName),
@@ -235,39 +160,21 @@ func (sm *Client) createDefaultMeasInfoList() (*e2smkpmv2.MeasurementInfoList, e
 
 }
 
-func (sm *Client) createMeasDefaultData(ctx context.Context) (*e2smkpmv2.MeasurementData, error) {
+func (sm *Client) createMeasDefaultData(ctx context.Context, cellECGI ransimtypes.ECGI, report *measReport) (*e2smkpmv2.MeasurementData, error) {
 	measData := e2smkpmv2.MeasurementData{
 		Value: make([]*e2smkpmv2.MeasurementDataItem, 0),
 	}
 	measRecord := e2smkpmv2.MeasurementRecord{
 		Value: make([]*e2smkpmv2.MeasurementRecordItem, 0),
 	}
-	for _, measType := range measTypes {
-		log.Debug("Creating measurement data for:", measType.measTypeName.String())
+	for _, measType := range listMeasTypes() {
+		log.Debug("Creating measurement data for:", measType.measTypeName)
 		// Creates meas record
-		switch measType.measTypeName {
-		case RRCConnMax:
-			log.Debug("Max number of UEs set for RRC Con Max:", sm.ServiceModel.UEs.Len(ctx))
-			measRecordInteger := measurments.NewMeasurementRecordItemInteger(
-				measurments.WithIntegerValue(int64(sm.ServiceModel.UEs.Len(ctx)))).
-				Build()
-			measRecord.Value = append(measRecord.Value, measRecordInteger)
-		case RRCConnAvg:
-			log.Debug("Avg number of UEs set for RRC Con Avg:", sm.ServiceModel.UEs.Len(ctx))
-			measRecordInteger := measurments.NewMeasurementRecordItemInteger(
-				measurments.WithIntegerValue(int64(sm.ServiceModel.UEs.Len(ctx)))).
-				Build()
-			measRecord.Value = append(measRecord.Value, measRecordInteger)
-		default:
-			measRecordNoValue := measurments.NewMeasurementRecordItemNoValue()
-			measRecord.Value = append(measRecord.Value, measRecordNoValue)
-
-		}
-
+		measRecord.Value = append(measRecord.Value, sm.createMeasRecordItem(ctx, cellECGI, measType.measTypeName, sm.createPlmnLabel(), nil, report))
 	}
 	measDataItem, err := measurments.NewMeasurementDataItem(
 		measurments.WithMeasurementRecord(&measRecord),
-		measurments.WithIncompleteFlag(e2smkpmv2.IncompleteFlag_INCOMPLETE_FLAG_TRUE)).
+		measurments.WithIncompleteFlag(report.incompleteFlag(&measRecord))).
 		Build()
 	if err != nil {
 		log.Warn(err)
@@ -279,13 +186,58 @@ func (sm *Client) createMeasDefaultData(ctx context.Context) (*e2smkpmv2.Measure
 
 }
 
-func (sm *Client) createDefaultIndicationMsgFormat1(ctx context.Context, cellECGI ransimtypes.ECGI, subscription *subutils.Subscription) ([]byte, error) {
+// createMeasRecordItem creates a measurement record item holding the current value of the specified measurement
+// for the given cell, restricted to the share of the UEs matching the given label and UE scope if any; values
+// withheld by an anomaly are missing from the given report
+func (sm *Client) createMeasRecordItem(ctx context.Context, cellECGI ransimtypes.ECGI, measTypeName string, label *e2smkpmv2.MeasurementLabel, scope ueScope, report *measReport) *e2smkpmv2.MeasurementRecordItem {
+	share, ok := sm.labelShare(ctx, cellECGI, label, scope)
+	if !ok {
+		return measurments.NewMeasurementRecordItemNoValue()
+	}
+
+	if sliceID := label.GetSliceId(); sliceID != nil {
+		// Per-slice measurements are only available from the metrics store
+		value, ok := metricsDriver{}.Value(ctx, sm.ServiceModel, cellECGI, SliceMeasName(measTypeName, sliceID.GetSSt(), sliceID.GetSD()))
+		return newMeasRecordItem(value, ok, share)
+	}
+	if scope != nil && (measTypeName == RRCConnMax.String() || measTypeName == RRCConnAvg.String()) {
+		// UE-level measurements count the UEs in scope among those served by the cell
+		return measurments.NewMeasurementRecordItemInteger(
+			measurments.WithIntegerValue(int64(math.Round(float64(len(sm.ServiceModel.UEs.ListUEs(ctx, cellECGI))) * share)))).
+			Build()
+	}
+	value, ok := report.samples.aggregate(cellECGI, measTypeName, report.since)
+	if !ok {
+		value, ok = getMeasDriver(measTypeName).Value(ctx, sm.ServiceModel, cellECGI, measTypeName)
+	}
+	if ok && sm.profiles != nil {
+		value, ok = sm.profiles.Modulate(cellECGI, measTypeName, value, time.Now())
+		if !ok {
+			report.omit(measTypeName, "withheld by an anomaly")
+		}
+	}
+	return newMeasRecordItem(value, ok, share)
+}
+
+// createMeasRecordItems creates the measurement record items of the given measurement, one per requested label
+func (sm *Client) createMeasRecordItems(ctx context.Context, cellECGI ransimtypes.ECGI, measTypeName string, labelInfoList *e2smkpmv2.LabelInfoList, scope ueScope, report *measReport) []*e2smkpmv2.MeasurementRecordItem {
+	if len(labelInfoList.GetValue()) == 0 {
+		return []*e2smkpmv2.MeasurementRecordItem{sm.createMeasRecordItem(ctx, cellECGI, measTypeName, nil, scope, report)}
+	}
+	items := make([]*e2smkpmv2.MeasurementRecordItem, 0, len(labelInfoList.GetValue()))
+	for _, labelInfo := range labelInfoList.GetValue() {
+		items = append(items, sm.createMeasRecordItem(ctx, cellECGI, measTypeName, labelInfo.GetMeasLabel(), scope, report))
+	}
+	return items
+}
+
+func (sm *Client) createDefaultIndicationMsgFormat1(ctx context.Context, cellECGI ransimtypes.ECGI, subscription *subutils.Subscription, report *measReport) ([]byte, error) {
 	measInfoList, err := sm.createDefaultMeasInfoList()
 	if err != nil {
 		return nil, err
 	}
 
-	measData, err := sm.createMeasDefaultData(ctx)
+	measData, err := sm.createMeasDefaultData(ctx, cellECGI, report)
 	if err != nil {
 		return nil, err
 	}
@@ -315,94 +267,104 @@ func (sm *Client) createDefaultIndicationMsgFormat1(ctx context.Context, cellECG
 
 }
 
-func (sm *Client) createRequestedIndMsgFormat1(ctx context.Context, cellECGI ransimtypes.ECGI, actionDefinitions []*e2smkpmv2.E2SmKpmActionDefinition) ([]byte, error) {
-	log.Debug("Create Indication message format 1 based on action defs")
-	for _, action := range actionDefinitions {
-		if action.GetActionDefinitionFormat1() != nil {
-			cellObjectID := action.GetActionDefinitionFormat1().GetCellObjId().Value
-			if cellObjectID == strconv.FormatUint(uint64(cellECGI), 10) {
-				measInfoList := action.GetActionDefinitionFormat1().GetMeasInfoList()
-				measRecord := e2smkpmv2.MeasurementRecord{
-					Value: make([]*e2smkpmv2.MeasurementRecordItem, 0),
-				}
-				measData := &e2smkpmv2.MeasurementData{
-					Value: make([]*e2smkpmv2.MeasurementDataItem, 0),
-				}
-				for _, measInfo := range measInfoList.Value {
-					for _, measType := range measTypes {
-						if measType.measTypeName.String() == measInfo.MeasType.GetMeasName().Value {
-							switch measType.measTypeName {
-							case RRCConnMax:
-								log.Debug("Max number of UEs set for RRC Con Max:", sm.ServiceModel.UEs.Len(ctx))
-								measRecordInteger := measurments.NewMeasurementRecordItemInteger(
-									measurments.WithIntegerValue(int64(sm.ServiceModel.UEs.Len(ctx)))).
-									Build()
-								measRecord.Value = append(measRecord.Value, measRecordInteger)
-							case RRCConnAvg:
-								log.Debug("Avg number of UEs set for RRC Con Avg:", sm.ServiceModel.UEs.Len(ctx))
-								measRecordInteger := measurments.NewMeasurementRecordItemInteger(
-									measurments.WithIntegerValue(int64(sm.ServiceModel.UEs.Len(ctx)))).
-									Build()
-								measRecord.Value = append(measRecord.Value, measRecordInteger)
-							default:
-								measRecordNoValue := measurments.NewMeasurementRecordItemNoValue()
-								measRecord.Value = append(measRecord.Value, measRecordNoValue)
-
-							}
-
-						}
-					}
+func (sm *Client) createRequestedIndMsg(ctx context.Context, cellECGI ransimtypes.ECGI, action *e2smkpmv2.E2SmKpmActionDefinition, report *measReport) ([]byte, error) {
+	log.Debug("Create Indication message based on action def")
+	cellObjectID := strconv.FormatUint(uint64(cellECGI), 10)
+	switch {
+	case action.GetActionDefinitionFormat1() != nil:
+		if action.GetActionDefinitionFormat1().GetCellObjId().GetValue() == cellObjectID {
+			return sm.createMeasIndMsgFormat1(ctx, cellECGI, action.GetActionDefinitionFormat1(), nil, report)
+		}
+	case action.GetActionDefinitionFormat2() != nil:
+		if action.GetActionDefinitionFormat2().GetSubscriptInfo().GetCellObjId().GetValue() == cellObjectID {
+			return sm.createUEIndMsgFormat1(ctx, cellECGI, action.GetActionDefinitionFormat2(), report)
+		}
+	case action.GetActionDefinitionFormat3() != nil:
+		if action.GetActionDefinitionFormat3().GetCellObjId().GetValue() == cellObjectID {
+			return sm.createCondIndMsgFormat2(ctx, cellECGI, action.GetActionDefinitionFormat3(), report)
+		}
+	}
+	return nil, nil
+}
 
-				}
-				measDataItem, err := measurments.NewMeasurementDataItem(
-					measurments.WithMeasurementRecord(&measRecord),
-					measurments.WithIncompleteFlag(e2smkpmv2.IncompleteFlag_INCOMPLETE_FLAG_TRUE)).
-					Build()
-				if err != nil {
-					log.Warn(err)
-					return nil, err
-				}
+// createMeasIndMsgFormat1 creates an indication message format 1 reporting the measurements requested by the
+// given action definition for the given cell, restricted to the UEs in the given scope if any
+func (sm *Client) createMeasIndMsgFormat1(ctx context.Context, cellECGI ransimtypes.ECGI, actionDefinition *e2smkpmv2.E2SmKpmActionDefinitionFormat1, scope ueScope, report *measReport) ([]byte, error) {
+	measInfoList := actionDefinition.GetMeasInfoList()
+	measData, err := sm.createMeasData(ctx, cellECGI, measInfoList, scope, report)
+	if err != nil {
+		return nil, err
+	}
+	subID := actionDefinition.SubscriptId.GetValue()
+	granularity := actionDefinition.GetGranulPeriod().Value
+	// Creating an indication message format 1
+	indicationMessage := kpm2MessageFormat1.NewIndicationMessage(
+		kpm2MessageFormat1.WithCellObjID(strconv.FormatUint(uint64(cellECGI), 10)),
+		kpm2MessageFormat1.WithGranularity(granularity),
+		kpm2MessageFormat1.WithSubscriptionID(subID),
+		kpm2MessageFormat1.WithMeasData(measData),
+		kpm2MessageFormat1.WithMeasInfoList(measInfoList))
 
-				measData.Value = append(measData.Value, measDataItem)
-				subID := action.GetActionDefinitionFormat1().SubscriptId.GetValue()
-				granularity := action.GetActionDefinitionFormat1().GetGranulPeriod().Value
-				// Creating an indication message format 1
-				indicationMessage := kpm2MessageFormat1.NewIndicationMessage(
-					kpm2MessageFormat1.WithCellObjID(strconv.FormatUint(uint64(cellECGI), 10)),
-					kpm2MessageFormat1.WithGranularity(granularity),
-					kpm2MessageFormat1.WithSubscriptionID(subID),
-					kpm2MessageFormat1.WithMeasData(measData),
-					kpm2MessageFormat1.WithMeasInfoList(measInfoList))
-
-				kpmModelPlugin, err := sm.ServiceModel.ModelPluginRegistry.GetPlugin(e2smtypes.OID(sm.ServiceModel.OID))
-				if err != nil {
-					return nil, err
-				}
-				indicationMessageBytes, err := indicationMessage.ToAsn1Bytes(kpmModelPlugin)
-				if err != nil {
-					log.Warn(err)
-					return nil, err
-				}
+	kpmModelPlugin, err := sm.ServiceModel.ModelPluginRegistry.GetPlugin(e2smtypes.OID(sm.ServiceModel.OID))
+	if err != nil {
+		return nil, err
+	}
+	indicationMessageBytes, err := indicationMessage.ToAsn1Bytes(kpmModelPlugin)
+	if err != nil {
+		log.Warn(err)
+		return nil, err
+	}
+
+	return indicationMessageBytes, nil
+}
 
-				return indicationMessageBytes, nil
+// createMeasData creates the measurement data of the given measurements for the given cell, restricted to the UEs
+// in the given scope if any; unsupported measurements have no value and are missing from the given report
+func (sm *Client) createMeasData(ctx context.Context, cellECGI ransimtypes.ECGI, measInfoList *e2smkpmv2.MeasurementInfoList, scope ueScope, report *measReport) (*e2smkpmv2.MeasurementData, error) {
+	measRecord := e2smkpmv2.MeasurementRecord{
+		Value: make([]*e2smkpmv2.MeasurementRecordItem, 0),
+	}
+	for _, measInfo := range measInfoList.GetValue() {
+		measName := measInfo.GetMeasType().GetMeasName().GetValue()
+		if !isMeasTypeSupported(measName) {
+			// Unsupported measurements keep their place in the record, one item without value per requested label
+			report.omit(measName, "not supported")
+			items := len(measInfo.GetLabelInfoList().GetValue())
+			if items == 0 {
+				items = 1
+			}
+			for i := 0; i < items; i++ {
+				measRecord.Value = append(measRecord.Value, measurments.NewMeasurementRecordItemNoValue())
 			}
+			continue
 		}
+		measRecord.Value = append(measRecord.Value, sm.createMeasRecordItems(ctx, cellECGI, measName, measInfo.GetLabelInfoList(), scope, report)...)
 	}
-	return nil, nil
+	measDataItem, err := measurments.NewMeasurementDataItem(
+		measurments.WithMeasurementRecord(&measRecord),
+		measurments.WithIncompleteFlag(report.incompleteFlag(&measRecord))).
+		Build()
+	if err != nil {
+		log.Warn(err)
+		return nil, err
+	}
+	return &e2smkpmv2.MeasurementData{
+		Value: []*e2smkpmv2.MeasurementDataItem{measDataItem},
+	}, nil
 }
 
-func (sm *Client) createIndicationMessageFormat1(ctx context.Context, cellECGI ransimtypes.ECGI, subscription *subutils.Subscription, actionDefinitions []*e2smkpmv2.E2SmKpmActionDefinition) ([]byte, error) {
+func (sm *Client) createIndicationMessage(ctx context.Context, cellECGI ransimtypes.ECGI, subscription *subutils.Subscription, actionDefinition *e2smkpmv2.E2SmKpmActionDefinition, report *measReport) ([]byte, error) {
 	// If there is no action definition then reports all of the stats
-	if len(actionDefinitions) == 0 {
+	if actionDefinition == nil {
 		log.Debug("No action definitions, reporting all of the stats")
-		indicationMessageASNBytes, err := sm.createDefaultIndicationMsgFormat1(ctx, cellECGI, subscription)
+		indicationMessageASNBytes, err := sm.createDefaultIndicationMsgFormat1(ctx, cellECGI, subscription, report)
 		if err != nil {
 			return nil, err
 		}
 		return indicationMessageASNBytes, nil
 	}
 
-	indicationMessageASNBytes, err := sm.createRequestedIndMsgFormat1(ctx, cellECGI, actionDefinitions)
+	indicationMessageASNBytes, err := sm.createRequestedIndMsg(ctx, cellECGI, actionDefinition, report)
 	if err != nil {
 		return nil, err
 	}
@@ -410,7 +372,7 @@ func (sm *Client) createIndicationMessageFormat1(ctx context.Context, cellECGI r
 
 }
 
-func (sm *Client) createIndicationHeaderBytes() ([]byte, error) {
+func (sm *Client) createIndicationHeaderBytes(collectionStartTime time.Time) ([]byte, error) {
 	// Creates an indication header
 	plmnID := ransimtypes.NewUint24(uint32(sm.ServiceModel.Model.PlmnID))
 	gNBID := &e2smkpmv2.BitString{
@@ -426,8 +388,7 @@ func (sm *Client) createIndicationHeaderBytes() ([]byte, error) {
 		log.Warn(err)
 		return nil, err
 	}
-	timestamp := make([]byte, 4)
-	binary.BigEndian.PutUint32(timestamp, uint32(time.Now().Unix()))
+	timestamp := encodeTimestamp(collectionStartTime, sm.timestampResolution())
 	header := kpm2IndicationHeader.NewIndicationHeader(
 		kpm2IndicationHeader.WithGlobalKpmNodeID(kpmNodeID),
 		kpm2IndicationHeader.WithFileFormatVersion(fileFormatVersion),
@@ -450,9 +411,9 @@ func (sm *Client) createIndicationHeaderBytes() ([]byte, error) {
 
 }
 
-func (sm *Client) createRicIndication(ctx context.Context, ecgi ransimtypes.ECGI, subscription *subutils.Subscription, actionDefinitions []*e2smkpmv2.E2SmKpmActionDefinition) (*e2appducontents.Ricindication, error) {
-	// Creates indication message format 1
-	indicationMessageBytes, err := sm.createIndicationMessageFormat1(ctx, ecgi, subscription, actionDefinitions)
+func (sm *Client) createRicIndication(ctx context.Context, ecgi ransimtypes.ECGI, subscription *subutils.Subscription, actionID e2aptypes.RicActionID, actionDefinition *e2smkpmv2.E2SmKpmActionDefinition, collectionStartTime time.Time, report *measReport) (*e2appducontents.Ricindication, error) {
+	// Creates the indication message in the format of the requested report style
+	indicationMessageBytes, err := sm.createIndicationMessage(ctx, ecgi, subscription, actionDefinition, report)
 	if err != nil {
 		log.Warn(err)
 		return nil, err
@@ -462,7 +423,7 @@ func (sm *Client) createRicIndication(ctx context.Context, ecgi ransimtypes.ECGI
 		return nil, nil
 	}
 
-	indicationHeaderAsn1Bytes, err := sm.createIndicationHeaderBytes()
+	indicationHeaderAsn1Bytes, err := sm.createIndicationHeaderBytes(collectionStartTime)
 	if err != nil {
 		log.Warn(err)
 		return nil, err
@@ -472,6 +433,7 @@ func (sm *Client) createRicIndication(ctx context.Context, ecgi ransimtypes.ECGI
 		e2apIndicationUtils.WithRicInstanceID(subscription.GetRicInstanceID()),
 		e2apIndicationUtils.WithRanFuncID(subscription.GetRanFuncID()),
 		e2apIndicationUtils.WithRequestID(subscription.GetReqID()),
+		e2apIndicationUtils.WithRicActionID(int32(actionID)),
 		e2apIndicationUtils.WithIndicationHeader(indicationHeaderAsn1Bytes),
 		e2apIndicationUtils.WithIndicationMessage(indicationMessageBytes))
 
@@ -483,34 +445,60 @@ func (sm *Client) createRicIndication(ctx context.Context, ecgi ransimtypes.ECGI
 	return ricIndication, nil
 }
 
-func (sm *Client) sendRicIndication(ctx context.Context, subscription *subutils.Subscription, actionDefinitions []*e2smkpmv2.E2SmKpmActionDefinition) error {
+func (sm *Client) sendRicIndication(ctx context.Context, subscription *subutils.Subscription, actionDefinitions actionDefinitions, reportPeriod time.Duration, samples *sampler) error {
 	subID := subscriptions.NewID(subscription.GetRicInstanceID(), subscription.GetReqID(), subscription.GetRanFuncID())
 	sub, err := sm.ServiceModel.Subscriptions.Get(subID)
 	if err != nil {
 		return err
 	}
 
-	node := sm.ServiceModel.Node
-	// Creates and sends an indication message for each cell in the node
-	for _, ecgi := range node.Cells {
-		ricIndication, err := sm.createRicIndication(ctx, ecgi, subscription, actionDefinitions)
-		if err != nil {
-			log.Error(err)
-			return err
+	now := monotonicNow()
+	sm.sample(ctx, samples, now)
+	// Aggregated measurements are reported over the granularity period of the action, or else the report period
+	window := reportPeriod
+	// Creates and sends an indication message for each report action and cell in the node
+	for _, action := range sub.ReportActions() {
+		actionDefinition := actionDefinitions[action.ID]
+		startTime := collectionStartTime(now, reportPeriod, granularityPeriod(actionDefinition))
+		since := now.Add(-reportPeriod)
+		if granularity := granularityPeriod(actionDefinition); granularity > 0 {
+			since = now.Add(-granularity)
+			if granularity > window {
+				window = granularity
+			}
 		}
-
-		if ricIndication != nil {
-			err = sub.E2Channel.RICIndication(ctx, ricIndication)
+		for _, ecgi := range sm.cells() {
+			grant, err := sm.planReport(ctx, ecgi, actionDefinition, reportPeriod)
+			if err != nil {
+				return err
+			}
+			if grant == 0 {
+				continue
+			}
+			report := newMeasReport(grant)
+			report.samples, report.since = samples, since
+			ricIndication, err := sm.createRicIndication(ctx, ecgi, subscription, action.ID, actionDefinition, startTime, report)
 			if err != nil {
 				log.Error(err)
 				return err
 			}
+
+			if ricIndication != nil {
+				err = sub.E2Channel.RICIndication(ctx, ricIndication)
+				if err != nil {
+					log.Error(err)
+					return err
+				}
+			}
 		}
 	}
+	if samples != nil {
+		samples.prune(now.Add(-window))
+	}
 	return nil
 }
 
-func (sm *Client) reportIndication(ctx context.Context, interval int32, subscription *subutils.Subscription, actionDefinitions []*e2smkpmv2.E2SmKpmActionDefinition) error {
+func (sm *Client) reportIndication(ctx context.Context, interval int32, subscription *subutils.Subscription, actionDefinitions actionDefinitions) error {
 	subID := subscriptions.NewID(subscription.GetRicInstanceID(), subscription.GetReqID(), subscription.GetRanFuncID())
 	// Creates an indication header
 
@@ -521,11 +509,28 @@ func (sm *Client) reportIndication(ctx context.Context, interval int32, subscrip
 		return err
 	}
 	sub.Ticker = time.NewTicker(intervalDuration * time.Millisecond)
+	// Measurements aggregated over their granularity period are sampled in between the reports
+	var sampling <-chan time.Time
+	samples := newSampler(sub.ReportActions(), actionDefinitions)
+	if samples != nil {
+		samplingTicker := time.NewTicker(sm.samplingPeriod())
+		defer samplingTicker.Stop()
+		sampling = samplingTicker.C
+	}
 	for {
 		select {
+		case <-sampling:
+			sm.sample(ctx, samples, monotonicNow())
+
 		case <-sub.Ticker.C:
 			log.Debug("Sending Indication Report for subscription:", sub.ID)
-			err = sm.sendRicIndication(ctx, subscription, actionDefinitions)
+			err = sm.sendRicIndication(ctx, subscription, actionDefinitions, intervalDuration*time.Millisecond, samples)
+			if err != nil && ctx.Err() != nil {
+				// The subscription was deleted while a report was delayed
+				log.Debugf("Stopped reporting for subscription %v: %v", sub.ID, err)
+				sub.Ticker.Stop()
+				return nil
+			}
 			if err != nil {
 				log.Error("creating indication message is failed", err)
 				return err
@@ -536,6 +541,9 @@ func (sm *Client) reportIndication(ctx context.Context, interval int32, subscrip
 			sub.Ticker.Stop()
 			return nil
 
+		case <-ctx.Done():
+			sub.Ticker.Stop()
+			return nil
 		}
 	}
 }
@@ -593,27 +601,81 @@ func (sm *Client) RICSubscription(ctx context.Context, request *e2appducontents.
 		return nil, subscriptionFailure, nil
 	}
 
+	// Undecodable event trigger or action definitions are rejected as falsely constructed messages
+	malformedCause := &e2apies.Cause{
+		Cause: &e2apies.Cause_Protocol{
+			Protocol: e2apies.CauseProtocol_CAUSE_PROTOCOL_ABSTRACT_SYNTAX_ERROR_FALSELY_CONSTRUCTED_MESSAGE,
+		},
+	}
 	reportInterval, err := sm.getReportPeriod(request)
 	if err != nil {
-		subscriptionFailure, err := subscription.BuildSubscriptionFailure()
+		log.Warn(err)
+		subscriptionFailure, err := subutils.NewSubscriptionFailure(request, malformedCause)
+		if err != nil {
+			return nil, nil, err
+		}
+		return nil, subscriptionFailure, nil
+	}
+	reportInterval, cause := sm.ServiceModel.CheckReportPeriod(reportInterval)
+	if cause != nil {
+		subscriptionFailure, err := subutils.NewSubscriptionFailure(request, cause)
 		if err != nil {
 			return nil, nil, err
 		}
 		return nil, subscriptionFailure, nil
 	}
 
-	actionDefinitions, err := sm.getActionDefinition(actionList, ricActionsAccepted)
+	sub, err := sm.ServiceModel.Subscriptions.Get(subscriptions.NewID(ricInstanceID, reqID, ranFuncID))
+	if err != nil {
+		return nil, nil, err
+	}
+	sub.AcceptActions(ricActionsAccepted)
+
+	actionDefinitions, err := sm.getActionDefinitions(sub.Actions)
 	if err != nil {
 		log.Warn(err)
+		subscriptionFailure, err := subutils.NewSubscriptionFailure(request, malformedCause)
+		if err != nil {
+			return nil, nil, err
+		}
+		return nil, subscriptionFailure, nil
+	}
+	for _, actionDefinition := range actionDefinitions {
+		if err := checkActionDefinition(actionDefinition); err != nil {
+			log.Warn(err)
+			subscriptionFailure, err := subutils.NewSubscriptionFailure(request, &e2apies.Cause{
+				Cause: &e2apies.Cause_RicRequest{
+					RicRequest: e2apies.CauseRic_CAUSE_RIC_ACTION_NOT_SUPPORTED,
+				},
+			})
+			if err != nil {
+				return nil, nil, err
+			}
+			return nil, subscriptionFailure, nil
+		}
+		// Actions on cells of other nodes would never be reported
+		if !sm.servesCellObject(actionDefinition) {
+			log.Warnf("Cell object %s requested from E2 node %d is not one of its cells", cellObjectID(actionDefinition), sm.ServiceModel.Node.EnbID)
+			sm.countNodeMetric(ctx, UnknownCellObjects)
+			subscriptionFailure, err := subutils.NewSubscriptionFailure(request, &e2apies.Cause{
+				Cause: &e2apies.Cause_RicRequest{
+					RicRequest: e2apies.CauseRic_CAUSE_RIC_ACTION_NOT_SUPPORTED,
+				},
+			})
+			if err != nil {
+				return nil, nil, err
+			}
+			return nil, subscriptionFailure, nil
+		}
 	}
 
 	subscriptionResponse, err := subscription.BuildSubscriptionResponse()
 	if err != nil {
 		return nil, nil, err
 	}
+	ctx, done := sm.ServiceModel.Subscriptions.StartReport(context.Background(), sub.ID)
 	go func() {
-		ctx, cancel := context.WithCancel(context.Background())
-		defer cancel()
+		defer done()
 		err := sm.reportIndication(ctx, reportInterval, subscription, actionDefinitions)
 		if err != nil {
 			return
@@ -0,0 +1,90 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package kpm2
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	ransimtypes "github.com/onosproject/onos-api/go/onos/ransim/types"
+	e2smkpmv2 "github.com/onosproject/onos-e2-sm/servicemodels/e2sm_kpm_v2/v2/e2sm-kpm-v2"
+	"github.com/onosproject/ran-simulator/pkg/kpiprofile"
+	"github.com/onosproject/ran-simulator/pkg/model"
+	"github.com/onosproject/ran-simulator/pkg/servicemodel/registry"
+	"github.com/onosproject/ran-simulator/pkg/store/cells"
+	"github.com/onosproject/ran-simulator/pkg/store/metrics"
+	"github.com/onosproject/ran-simulator/pkg/store/nodes"
+	"github.com/onosproject/ran-simulator/pkg/store/ues"
+	"github.com/onosproject/ran-simulator/pkg/utils/e2sm/kpm2/measurments"
+	"github.com/stretchr/testify/assert"
+)
+
+func measInfoList(measNames ...string) *e2smkpmv2.MeasurementInfoList {
+	list := &e2smkpmv2.MeasurementInfoList{}
+	for _, measName := range measNames {
+		list.Value = append(list.Value, &e2smkpmv2.MeasurementInfoItem{
+			MeasType: &e2smkpmv2.MeasurementType{
+				MeasurementType: &e2smkpmv2.MeasurementType_MeasName{
+					MeasName: &e2smkpmv2.MeasurementTypeName{Value: measName},
+				},
+			},
+		})
+	}
+	return list
+}
+
+func TestIncompleteFlag(t *testing.T) {
+	ctx := context.Background()
+	ecgi := ransimtypes.ECGI(84325717505)
+	m := &model.Model{}
+	assert.NoError(t, model.LoadConfig(m, "../../model/test"))
+	cellStore := cells.NewCellRegistry(m.Cells, nodes.NewNodeRegistry(m.Nodes))
+	metricStore := metrics.NewMetricsStore()
+	anomalies := kpiprofile.NewAnomalies()
+	client := &Client{
+		ServiceModel: &registry.ServiceModel{
+			Node:        model.Node{EnbID: 144470},
+			Model:       m,
+			UEs:         ues.NewUERegistry(1, cellStore),
+			MetricStore: metricStore,
+		},
+		profiles: kpiprofile.NewEngine(m, anomalies),
+	}
+	assert.NoError(t, metricStore.Set(ctx, uint64(ecgi), RRCConnEstabAttTot.String(), uint64(10)))
+	flag := func(measData *e2smkpmv2.MeasurementData, err error) e2smkpmv2.IncompleteFlag {
+		assert.NoError(t, err)
+		return measData.GetValue()[0].GetIncompleteFlag()
+	}
+
+	// Complete reports leave the flag out, even for measurements without value
+	assert.Equal(t, measurments.NoIncompleteFlag, flag(client.createMeasDefaultData(ctx, ecgi, newMeasReport(unlimitedGrant))))
+	measInfo := measInfoList(RRCConnEstabAttTot.String(), RRCConnEstabSuccTot.String())
+	assert.Equal(t, measurments.NoIncompleteFlag, flag(client.createMeasData(ctx, ecgi, measInfo, nil, newMeasReport(unlimitedGrant))))
+
+	// Reports cut short for lack of compute budget are flagged
+	assert.Equal(t, e2smkpmv2.IncompleteFlag_INCOMPLETE_FLAG_TRUE, flag(client.createMeasData(ctx, ecgi, measInfo, nil, newMeasReport(1))))
+	assert.Equal(t, e2smkpmv2.IncompleteFlag_INCOMPLETE_FLAG_TRUE, flag(client.createMeasDefaultData(ctx, ecgi, newMeasReport(1))))
+
+	// Reports missing unsupported measurements are flagged
+	unsupported := measInfoList(RRCConnEstabAttTot.String(), "DRB.Unknown")
+	measData, err := client.createMeasData(ctx, ecgi, unsupported, nil, newMeasReport(unlimitedGrant))
+	assert.Equal(t, e2smkpmv2.IncompleteFlag_INCOMPLETE_FLAG_TRUE, flag(measData, err))
+	// The record holds an item for every measurement requested so that it stays aligned with the measurement info list
+	if records := measData.GetValue()[0].GetMeasRecord().GetValue(); assert.Equal(t, 2, len(records)) {
+		assert.IsType(t, &e2smkpmv2.MeasurementRecordItem_NoValue{}, records[1].GetMeasurementRecordItem())
+	}
+
+	// Reports missing measurements withheld by a fault are flagged
+	_, err = anomalies.Inject(kpiprofile.Anomaly{
+		Kind:         kpiprofile.Missing,
+		ECGI:         ecgi,
+		Measurements: []string{RRCConnEstabAttTot.String()},
+		End:          time.Now().Add(time.Hour),
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, e2smkpmv2.IncompleteFlag_INCOMPLETE_FLAG_TRUE, flag(client.createMeasData(ctx, ecgi, measInfo, nil, newMeasReport(unlimitedGrant))))
+	assert.Equal(t, measurments.NoIncompleteFlag, flag(client.createMeasData(ctx, ecgi, measInfoList(RRCConnEstabSuccTot.String()), nil, newMeasReport(unlimitedGrant))))
+}
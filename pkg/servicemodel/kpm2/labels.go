@@ -0,0 +1,113 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package kpm2
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+
+	ransimtypes "github.com/onosproject/onos-api/go/onos/ransim/types"
+	e2smkpmv2 "github.com/onosproject/onos-e2-sm/servicemodels/e2sm_kpm_v2/v2/e2sm-kpm-v2"
+	"github.com/onosproject/ran-simulator/pkg/model"
+)
+
+// createPlmnLabel creates a measurement label identifying the PLMN of the simulated network
+func (sm *Client) createPlmnLabel() *e2smkpmv2.MeasurementLabel {
+	plmnID := ransimtypes.NewUint24(uint32(sm.ServiceModel.Model.PlmnID))
+	return &e2smkpmv2.MeasurementLabel{
+		PlmnId: &e2smkpmv2.PlmnIdentity{
+			Value: plmnID.ToBytes(),
+		},
+	}
+}
+
+// SliceMeasName returns the name of the per-slice variant of the given measurement, i.e. the measurement name
+// suffixed with the S-NSSAI formatted as the decimal SST optionally followed by the hexadecimal SD, e.g. "RRC.Conn.Avg.1-010203";
+// per-slice measurements are maintained per cell in the metrics store under these names
+func SliceMeasName(measName string, sst []byte, sd []byte) string {
+	var snssai string
+	if len(sst) > 0 {
+		snssai = strconv.Itoa(int(sst[0]))
+	}
+	if len(sd) > 0 {
+		snssai = fmt.Sprintf("%s-%s", snssai, hex.EncodeToString(sd))
+	}
+	return fmt.Sprintf("%s.%s", measName, snssai)
+}
+
+// labelShare returns the share of the cell measurements attributable to the UEs matching the given label and
+// UE scope; false is returned if no measurements exist for the label at all, e.g. for a foreign PLMN
+func (sm *Client) labelShare(ctx context.Context, cellECGI ransimtypes.ECGI, label *e2smkpmv2.MeasurementLabel, scope ueScope) (float64, bool) {
+	if !sm.isLocalPlmn(label) {
+		return 0, false
+	}
+	qosFilter := label != nil && hasQoSFilter(label)
+	if scope == nil && !qosFilter {
+		return 1, true
+	}
+
+	ueList := sm.ServiceModel.UEs.ListUEs(ctx, cellECGI)
+	if len(ueList) == 0 {
+		return 0, true
+	}
+	matching := 0
+	for _, ue := range ueList {
+		if (scope == nil || scope(ue)) && (!qosFilter || ueMatchesLabel(ue, label)) {
+			matching++
+		}
+	}
+	return float64(matching) / float64(len(ueList)), true
+}
+
+// isLocalPlmn returns false if the given label is for a PLMN other than the simulated one
+func (sm *Client) isLocalPlmn(label *e2smkpmv2.MeasurementLabel) bool {
+	if label.GetPlmnId() == nil {
+		return true
+	}
+	plmnID := ransimtypes.NewUint24(uint32(sm.ServiceModel.Model.PlmnID))
+	return bytes.Equal(label.GetPlmnId().GetValue(), plmnID.ToBytes())
+}
+
+// hasQoSFilter returns true if the label restricts the measurements to QoS flows with certain characteristics
+func hasQoSFilter(label *e2smkpmv2.MeasurementLabel) bool {
+	return label.FiveQi != nil || label.QFi != nil || label.QCi != nil || label.QCimin != nil ||
+		label.QCimax != nil || label.ARpmin != nil || label.ARpmax != nil
+}
+
+// ueMatchesLabel returns true if the UE has a QoS flow matching all the QoS characteristics of the label;
+// QCI labels are matched against the 5QI of the flows as their standardized values coincide
+func ueMatchesLabel(ue *model.UE, label *e2smkpmv2.MeasurementLabel) bool {
+	for _, drb := range ue.DRBs {
+		for _, flow := range drb.QoSFlows {
+			if flowMatchesLabel(flow, label) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func flowMatchesLabel(flow *model.QoSFlow, label *e2smkpmv2.MeasurementLabel) bool {
+	switch {
+	case label.FiveQi != nil && flow.FiveQI != label.FiveQi.Value:
+		return false
+	case label.QFi != nil && flow.QFI != label.QFi.Value:
+		return false
+	case label.QCi != nil && flow.FiveQI != label.QCi.Value:
+		return false
+	case label.QCimin != nil && flow.FiveQI < label.QCimin.Value:
+		return false
+	case label.QCimax != nil && flow.FiveQI > label.QCimax.Value:
+		return false
+	case label.ARpmin != nil && flow.ARP < label.ARpmin.Value:
+		return false
+	case label.ARpmax != nil && flow.ARP > label.ARpmax.Value:
+		return false
+	}
+	return true
+}
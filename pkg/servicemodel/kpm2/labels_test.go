@@ -0,0 +1,46 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package kpm2
+
+import (
+	"testing"
+
+	e2smkpmv2 "github.com/onosproject/onos-e2-sm/servicemodels/e2sm_kpm_v2/v2/e2sm-kpm-v2"
+	"github.com/onosproject/ran-simulator/pkg/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUEMatchesLabel(t *testing.T) {
+	ue := &model.UE{
+		DRBs: []*model.DRB{
+			{ID: 1, QoSFlows: []*model.QoSFlow{{QFI: 1, FiveQI: 9, ARP: 15}}},
+			{ID: 2, QoSFlows: []*model.QoSFlow{{QFI: 2, FiveQI: 1, ARP: 2, GBR: true}}},
+		},
+	}
+	assert.False(t, hasQoSFilter(&e2smkpmv2.MeasurementLabel{}))
+	assert.True(t, hasQoSFilter(&e2smkpmv2.MeasurementLabel{FiveQi: &e2smkpmv2.FiveQi{Value: 9}}))
+
+	assert.True(t, ueMatchesLabel(ue, &e2smkpmv2.MeasurementLabel{FiveQi: &e2smkpmv2.FiveQi{Value: 9}}))
+	assert.True(t, ueMatchesLabel(ue, &e2smkpmv2.MeasurementLabel{QFi: &e2smkpmv2.Qfi{Value: 2}}))
+	assert.False(t, ueMatchesLabel(ue, &e2smkpmv2.MeasurementLabel{FiveQi: &e2smkpmv2.FiveQi{Value: 5}}))
+
+	// All characteristics must be satisfied by the same QoS flow
+	assert.False(t, ueMatchesLabel(ue, &e2smkpmv2.MeasurementLabel{
+		FiveQi: &e2smkpmv2.FiveQi{Value: 9},
+		ARpmax: &e2smkpmv2.Arp{Value: 5},
+	}))
+	assert.True(t, ueMatchesLabel(ue, &e2smkpmv2.MeasurementLabel{
+		QCimin: &e2smkpmv2.Qci{Value: 1},
+		QCimax: &e2smkpmv2.Qci{Value: 4},
+		ARpmax: &e2smkpmv2.Arp{Value: 5},
+	}))
+
+	assert.False(t, ueMatchesLabel(&model.UE{}, &e2smkpmv2.MeasurementLabel{FiveQi: &e2smkpmv2.FiveQi{Value: 9}}))
+}
+
+func TestSliceMeasName(t *testing.T) {
+	assert.Equal(t, "PEE.AvgPower.1-010203", SliceMeasName("PEE.AvgPower", []byte{0x01}, []byte{0x01, 0x02, 0x03}))
+	assert.Equal(t, "RRC.Conn.Avg.2", SliceMeasName("RRC.Conn.Avg", []byte{0x02}, nil))
+}
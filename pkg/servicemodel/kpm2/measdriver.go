@@ -0,0 +1,137 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package kpm2
+
+import (
+	"context"
+	"math"
+	"sync"
+
+	ransimtypes "github.com/onosproject/onos-api/go/onos/ransim/types"
+	e2smkpmv2 "github.com/onosproject/onos-e2-sm/servicemodels/e2sm_kpm_v2/v2/e2sm-kpm-v2"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/onosproject/ran-simulator/pkg/servicemodel/registry"
+	"github.com/onosproject/ran-simulator/pkg/utils/e2sm/kpm2/measurments"
+)
+
+// MeasDriver generates the values of a measurement type
+type MeasDriver interface {
+	// Value returns the current value of the named measurement for the given cell of the E2 node of the service
+	// model, either as uint64 or float64; false is returned if no value is available
+	Value(ctx context.Context, sm *registry.ServiceModel, cellECGI ransimtypes.ECGI, measName string) (interface{}, bool)
+}
+
+// MeasDriverFunc is an adapter allowing the use of ordinary functions as measurement drivers
+type MeasDriverFunc func(ctx context.Context, sm *registry.ServiceModel, cellECGI ransimtypes.ECGI, measName string) (interface{}, bool)
+
+// Value calls the function
+func (f MeasDriverFunc) Value(ctx context.Context, sm *registry.ServiceModel, cellECGI ransimtypes.ECGI, measName string) (interface{}, bool) {
+	return f(ctx, sm, cellECGI, measName)
+}
+
+// metricsDriver reads the values of the measurements maintained per cell in the metrics store; it drives all
+// measurement types without a registered driver
+type metricsDriver struct{}
+
+func (d metricsDriver) Value(ctx context.Context, sm *registry.ServiceModel, cellECGI ransimtypes.ECGI, measName string) (interface{}, bool) {
+	return sm.MetricStore.Get(ctx, uint64(cellECGI), measName)
+}
+
+// ueCountDriver counts the simulated UEs
+type ueCountDriver struct{}
+
+func (d ueCountDriver) Value(ctx context.Context, sm *registry.ServiceModel, cellECGI ransimtypes.ECGI, measName string) (interface{}, bool) {
+	log.Debugf("Number of UEs set for %s: %d", measName, sm.UEs.Len(ctx))
+	return uint64(sm.UEs.Len(ctx)), true
+}
+
+var (
+	measDriversMu sync.RWMutex
+	measDrivers   = map[string]MeasDriver{
+		RRCConnMax.String(): ueCountDriver{},
+		RRCConnAvg.String(): ueCountDriver{},
+	}
+	// customMeasTypes are the measurement types added by registering a driver
+	customMeasTypes []MeasType
+)
+
+// RegisterMeasDriver registers the driver generating the values of the named measurement type, replacing the
+// driver of a built-in type. Types not built in are added to the measurement types advertised by the E2 nodes
+// created afterwards.
+func RegisterMeasDriver(measName string, driver MeasDriver) error {
+	if measName == "" || driver == nil {
+		return errors.New(errors.Invalid, "measurement name and driver are required")
+	}
+	measDriversMu.Lock()
+	defer measDriversMu.Unlock()
+	measDrivers[measName] = driver
+	for _, measType := range listMeasTypesLocked() {
+		if measType.measTypeName == measName {
+			return nil
+		}
+	}
+	customMeasTypes = append(customMeasTypes, MeasType{
+		measTypeName: measName,
+		measTypeID:   int32(len(measTypes) + len(customMeasTypes) + 1),
+	})
+	log.Infof("Registered measurement type %s", measName)
+	return nil
+}
+
+// RegisterMetricMeasType adds the named measurement type, whose values are read from the metrics store, to the
+// measurement types advertised by the E2 nodes created afterwards
+func RegisterMetricMeasType(measName string) error {
+	return RegisterMeasDriver(measName, metricsDriver{})
+}
+
+// getMeasDriver returns the driver of the named measurement type
+func getMeasDriver(measName string) MeasDriver {
+	measDriversMu.RLock()
+	defer measDriversMu.RUnlock()
+	if driver, ok := measDrivers[measName]; ok {
+		return driver
+	}
+	return metricsDriver{}
+}
+
+// listMeasTypes lists the built-in and custom measurement types
+func listMeasTypes() []MeasType {
+	measDriversMu.RLock()
+	defer measDriversMu.RUnlock()
+	return listMeasTypesLocked()
+}
+
+func listMeasTypesLocked() []MeasType {
+	list := make([]MeasType, 0, len(measTypes)+len(customMeasTypes))
+	list = append(list, measTypes...)
+	return append(list, customMeasTypes...)
+}
+
+// isMeasTypeSupported returns true if the named measurement type is built in or custom
+func isMeasTypeSupported(measName string) bool {
+	for _, measType := range listMeasTypes() {
+		if measType.measTypeName == measName {
+			return true
+		}
+	}
+	return false
+}
+
+// newMeasRecordItem creates a measurement record item holding the given share of the value generated by a driver
+func newMeasRecordItem(value interface{}, ok bool, share float64) *e2smkpmv2.MeasurementRecordItem {
+	if ok {
+		switch v := value.(type) {
+		case uint64:
+			return measurments.NewMeasurementRecordItemInteger(
+				measurments.WithIntegerValue(int64(math.Round(float64(v) * share)))).
+				Build()
+		case float64:
+			return measurments.NewMeasurementRecordItemReal(
+				measurments.WithRealValue(v * share)).
+				Build()
+		}
+	}
+	return measurments.NewMeasurementRecordItemNoValue()
+}
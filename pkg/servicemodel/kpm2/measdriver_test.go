@@ -0,0 +1,46 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package kpm2
+
+import (
+	"context"
+	"testing"
+
+	ransimtypes "github.com/onosproject/onos-api/go/onos/ransim/types"
+	e2smkpmv2 "github.com/onosproject/onos-e2-sm/servicemodels/e2sm_kpm_v2/v2/e2sm-kpm-v2"
+	"github.com/onosproject/ran-simulator/pkg/servicemodel/registry"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegisterMeasDriver(t *testing.T) {
+	latency := MeasDriverFunc(func(ctx context.Context, sm *registry.ServiceModel, cellECGI ransimtypes.ECGI, measName string) (interface{}, bool) {
+		return 12.5, true
+	})
+	assert.Error(t, RegisterMeasDriver("", latency))
+	assert.Error(t, RegisterMeasDriver("DRB.Latency.Avg", nil))
+
+	// Custom measurement types are advertised after the built-in ones, once
+	assert.NoError(t, RegisterMeasDriver("DRB.Latency.Avg", latency))
+	assert.NoError(t, RegisterMeasDriver("DRB.Latency.Avg", latency))
+	list := listMeasTypes()
+	assert.Len(t, list, len(measTypes)+1)
+	assert.Equal(t, MeasType{measTypeName: "DRB.Latency.Avg", measTypeID: int32(len(measTypes) + 1)}, list[len(list)-1])
+	value, ok := getMeasDriver("DRB.Latency.Avg").Value(context.Background(), nil, 0, "DRB.Latency.Avg")
+	assert.True(t, ok)
+	assert.Equal(t, 12.5, value)
+
+	// Built-in measurement types are driven by the UE count or the metrics store unless overridden
+	assert.Equal(t, ueCountDriver{}, getMeasDriver(RRCConnMax.String()))
+	assert.Equal(t, metricsDriver{}, getMeasDriver(PAGAttTot.String()))
+	assert.NoError(t, RegisterMeasDriver(PAGAttTot.String(), latency))
+	assert.Len(t, listMeasTypes(), len(measTypes)+1)
+}
+
+func TestNewMeasRecordItem(t *testing.T) {
+	assert.Equal(t, int64(5), newMeasRecordItem(uint64(10), true, 0.5).GetInteger())
+	assert.Equal(t, 2.5, newMeasRecordItem(5.0, true, 0.5).GetReal())
+	assert.IsType(t, &e2smkpmv2.MeasurementRecordItem_NoValue{}, newMeasRecordItem(nil, false, 1).GetMeasurementRecordItem())
+	assert.IsType(t, &e2smkpmv2.MeasurementRecordItem_NoValue{}, newMeasRecordItem("invalid", true, 1).GetMeasurementRecordItem())
+}
@@ -0,0 +1,230 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package kpm2
+
+import (
+	"context"
+	"strconv"
+
+	e2smtypes "github.com/onosproject/onos-api/go/onos/e2t/e2sm"
+	ransimtypes "github.com/onosproject/onos-api/go/onos/ransim/types"
+	e2smkpmv2 "github.com/onosproject/onos-e2-sm/servicemodels/e2sm_kpm_v2/v2/e2sm-kpm-v2"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/onosproject/ran-simulator/pkg/model"
+	kpm2MessageFormat2 "github.com/onosproject/ran-simulator/pkg/utils/e2sm/kpm2/indication/messageformat2"
+	"github.com/onosproject/ran-simulator/pkg/utils/e2sm/kpm2/measurments"
+)
+
+// reportStyle is a RIC report style advertised in the RAN function description
+type reportStyle struct {
+	styleType        int32
+	name             string
+	actionFormatType int32
+	indMsgFormatType int32
+}
+
+const (
+	cellReportStyleType = 1
+	ueReportStyleType   = 2
+	condReportStyleType = 3
+)
+
+// reportStyles lists the supported report styles along with the formats of their action definitions and
+// indication messages
+var reportStyles = []reportStyle{
+	{styleType: cellReportStyleType, name: "E2 Node Measurement", actionFormatType: 1, indMsgFormatType: 1},
+	{styleType: ueReportStyleType, name: "E2 Node Measurement for a single UE", actionFormatType: 2, indMsgFormatType: 1},
+	{styleType: condReportStyleType, name: "Condition-based, UE-level E2 Node Measurement", actionFormatType: 3, indMsgFormatType: 2},
+}
+
+// ueScope restricts the measurements of a cell to the UEs it returns true for
+type ueScope func(ue *model.UE) bool
+
+// checkActionDefinition returns an error if the action definition is not of a supported report style or its
+// format does not match the style
+func checkActionDefinition(actionDefinition *e2smkpmv2.E2SmKpmActionDefinition) error {
+	var format int32
+	switch {
+	case actionDefinition.GetActionDefinitionFormat1() != nil:
+		format = 1
+	case actionDefinition.GetActionDefinitionFormat2() != nil:
+		format = 2
+	case actionDefinition.GetActionDefinitionFormat3() != nil:
+		format = 3
+	}
+	styleType := actionDefinition.GetRicStyleType().GetValue()
+	for _, style := range reportStyles {
+		if style.styleType != styleType {
+			continue
+		}
+		if style.actionFormatType != format {
+			return errors.New(errors.Invalid, "action definition format %d does not match report style %d", format, styleType)
+		}
+		return nil
+	}
+	return errors.New(errors.NotSupported, "report style %d is not supported", styleType)
+}
+
+// UnknownCellObjects is the name of the node metric counting the subscriptions rejected for requesting a cell object
+// which is not a cell of the node
+const UnknownCellObjects = "E2.UnknownCellObjects"
+
+// cellObjectID returns the ID of the cell object the action definition reports on
+func cellObjectID(actionDefinition *e2smkpmv2.E2SmKpmActionDefinition) string {
+	switch {
+	case actionDefinition.GetActionDefinitionFormat1() != nil:
+		return actionDefinition.GetActionDefinitionFormat1().GetCellObjId().GetValue()
+	case actionDefinition.GetActionDefinitionFormat2() != nil:
+		return actionDefinition.GetActionDefinitionFormat2().GetSubscriptInfo().GetCellObjId().GetValue()
+	case actionDefinition.GetActionDefinitionFormat3() != nil:
+		return actionDefinition.GetActionDefinitionFormat3().GetCellObjId().GetValue()
+	}
+	return ""
+}
+
+// servesCellObject returns true if the cell object the action definition reports on is a cell of the node
+func (sm *Client) servesCellObject(actionDefinition *e2smkpmv2.E2SmKpmActionDefinition) bool {
+	objectID := cellObjectID(actionDefinition)
+	for _, ecgi := range sm.cells() {
+		if strconv.FormatUint(uint64(ecgi), 10) == objectID {
+			return true
+		}
+	}
+	return false
+}
+
+// createUEIndMsgFormat1 creates an indication message format 1 reporting the measurements of the single UE
+// requested by the given action definition; nil is returned if the UE is not served by the given cell
+func (sm *Client) createUEIndMsgFormat1(ctx context.Context, cellECGI ransimtypes.ECGI, actionDefinition *e2smkpmv2.E2SmKpmActionDefinitionFormat2, report *measReport) ([]byte, error) {
+	imsi, err := strconv.ParseUint(actionDefinition.GetUeId().GetValue(), 10, 64)
+	if err != nil {
+		return nil, errors.New(errors.Invalid, "invalid UE identity %s", actionDefinition.GetUeId().GetValue())
+	}
+	ue, err := sm.ServiceModel.UEs.Get(ctx, ransimtypes.IMSI(imsi))
+	if err != nil || ue.Cell == nil || ue.Cell.ECGI != cellECGI {
+		return nil, nil
+	}
+	return sm.createMeasIndMsgFormat1(ctx, cellECGI, actionDefinition.GetSubscriptInfo(), func(candidate *model.UE) bool {
+		return candidate.IMSI == ue.IMSI
+	}, report)
+}
+
+// createCondIndMsgFormat2 creates an indication message format 2 reporting the measurements requested by the given
+// action definition over the UEs of the given cell satisfying the matching conditions of each measurement
+func (sm *Client) createCondIndMsgFormat2(ctx context.Context, cellECGI ransimtypes.ECGI, actionDefinition *e2smkpmv2.E2SmKpmActionDefinitionFormat3, report *measReport) ([]byte, error) {
+	measCondUEList := &e2smkpmv2.MeasurementCondUeidList{
+		Value: make([]*e2smkpmv2.MeasurementCondUeidItem, 0),
+	}
+	measRecord := e2smkpmv2.MeasurementRecord{
+		Value: make([]*e2smkpmv2.MeasurementRecordItem, 0),
+	}
+	ueList := sm.ServiceModel.UEs.ListUEs(ctx, cellECGI)
+	for _, measCond := range actionDefinition.GetMeasCondList().GetValue() {
+		measName := measCond.GetMeasType().GetMeasName().GetValue()
+		if !isMeasTypeSupported(measName) {
+			report.omit(measName, "not supported")
+			continue
+		}
+		scope := sm.conditionScope(measCond.GetMatchingCond())
+		item := &e2smkpmv2.MeasurementCondUeidItem{
+			MeasType:     measCond.GetMeasType(),
+			MatchingCond: measCond.GetMatchingCond(),
+		}
+		for _, ue := range ueList {
+			if scope(ue) {
+				if item.MatchingUeidList == nil {
+					item.MatchingUeidList = &e2smkpmv2.MatchingUeidList{}
+				}
+				item.MatchingUeidList.Value = append(item.MatchingUeidList.Value, &e2smkpmv2.MatchingUeidItem{
+					UeId: &e2smkpmv2.UeIdentity{Value: strconv.FormatUint(uint64(ue.IMSI), 10)},
+				})
+			}
+		}
+		measCondUEList.Value = append(measCondUEList.Value, item)
+		measRecord.Value = append(measRecord.Value, sm.createMeasRecordItem(ctx, cellECGI, measName, nil, scope, report))
+	}
+
+	measDataItem, err := measurments.NewMeasurementDataItem(
+		measurments.WithMeasurementRecord(&measRecord),
+		measurments.WithIncompleteFlag(report.incompleteFlag(&measRecord))).
+		Build()
+	if err != nil {
+		log.Warn(err)
+		return nil, err
+	}
+	indicationMessage := kpm2MessageFormat2.NewIndicationMessage(
+		kpm2MessageFormat2.WithCellObjID(strconv.FormatUint(uint64(cellECGI), 10)),
+		kpm2MessageFormat2.WithGranularity(actionDefinition.GetGranulPeriod().GetValue()),
+		kpm2MessageFormat2.WithSubscriptionID(actionDefinition.GetSubscriptId().GetValue()),
+		kpm2MessageFormat2.WithMeasCondUEList(measCondUEList),
+		kpm2MessageFormat2.WithMeasData(&e2smkpmv2.MeasurementData{
+			Value: []*e2smkpmv2.MeasurementDataItem{measDataItem},
+		}))
+
+	kpmModelPlugin, err := sm.ServiceModel.ModelPluginRegistry.GetPlugin(e2smtypes.OID(sm.ServiceModel.OID))
+	if err != nil {
+		return nil, err
+	}
+	indicationMessageBytes, err := indicationMessage.ToAsn1Bytes(kpmModelPlugin)
+	if err != nil {
+		log.Warn(err)
+		return nil, err
+	}
+	return indicationMessageBytes, nil
+}
+
+// conditionScope returns the scope of the UEs satisfying all the given matching conditions
+func (sm *Client) conditionScope(conditions *e2smkpmv2.MatchingCondList) ueScope {
+	return func(ue *model.UE) bool {
+		for _, condition := range conditions.GetValue() {
+			if label := condition.GetMeasLabel(); label != nil {
+				if !sm.isLocalPlmn(label) || (hasQoSFilter(label) && !ueMatchesLabel(ue, label)) {
+					return false
+				}
+			}
+			if test := condition.GetTestCondInfo(); test != nil && !ueMatchesTest(ue, test) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// ueMatchesTest returns true if the UE satisfies the given test condition; only GBR and RSRP tests are supported,
+// the latter comparing the strength of the serving cell in dBm
+func ueMatchesTest(ue *model.UE, test *e2smkpmv2.TestCondInfo) bool {
+	switch test.GetTestType().GetTestCondType().(type) {
+	case *e2smkpmv2.TestCondType_GBr:
+		return ueHasGBRFlow(ue)
+	case *e2smkpmv2.TestCondType_RSrp:
+		if ue.Cell == nil {
+			return false
+		}
+		value := float64(test.GetTestValue().GetValueInt())
+		switch test.GetTestExpr() {
+		case e2smkpmv2.TestCondExpression_TEST_COND_EXPRESSION_EQUAL:
+			return ue.Cell.Strength == value
+		case e2smkpmv2.TestCondExpression_TEST_COND_EXPRESSION_GREATERTHAN:
+			return ue.Cell.Strength > value
+		case e2smkpmv2.TestCondExpression_TEST_COND_EXPRESSION_LESSTHAN:
+			return ue.Cell.Strength < value
+		case e2smkpmv2.TestCondExpression_TEST_COND_EXPRESSION_PRESENT:
+			return true
+		}
+	}
+	return false
+}
+
+// ueHasGBRFlow returns true if the UE has a guaranteed bit rate QoS flow
+func ueHasGBRFlow(ue *model.UE) bool {
+	for _, drb := range ue.DRBs {
+		for _, flow := range drb.QoSFlows {
+			if flow.GBR {
+				return true
+			}
+		}
+	}
+	return false
+}
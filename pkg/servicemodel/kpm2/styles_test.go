@@ -0,0 +1,118 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package kpm2
+
+import (
+	"testing"
+
+	ransimtypes "github.com/onosproject/onos-api/go/onos/ransim/types"
+	e2smkpmv2 "github.com/onosproject/onos-e2-sm/servicemodels/e2sm_kpm_v2/v2/e2sm-kpm-v2"
+	"github.com/onosproject/ran-simulator/pkg/model"
+	"github.com/onosproject/ran-simulator/pkg/servicemodel/registry"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckActionDefinition(t *testing.T) {
+	format1 := &e2smkpmv2.E2SmKpmActionDefinition_ActionDefinitionFormat1{
+		ActionDefinitionFormat1: &e2smkpmv2.E2SmKpmActionDefinitionFormat1{},
+	}
+	format3 := &e2smkpmv2.E2SmKpmActionDefinition_ActionDefinitionFormat3{
+		ActionDefinitionFormat3: &e2smkpmv2.E2SmKpmActionDefinitionFormat3{},
+	}
+	assert.NoError(t, checkActionDefinition(&e2smkpmv2.E2SmKpmActionDefinition{
+		RicStyleType:            &e2smkpmv2.RicStyleType{Value: cellReportStyleType},
+		E2SmKpmActionDefinition: format1,
+	}))
+	assert.NoError(t, checkActionDefinition(&e2smkpmv2.E2SmKpmActionDefinition{
+		RicStyleType:            &e2smkpmv2.RicStyleType{Value: condReportStyleType},
+		E2SmKpmActionDefinition: format3,
+	}))
+	assert.Error(t, checkActionDefinition(&e2smkpmv2.E2SmKpmActionDefinition{
+		RicStyleType:            &e2smkpmv2.RicStyleType{Value: ueReportStyleType},
+		E2SmKpmActionDefinition: format1,
+	}))
+	assert.Error(t, checkActionDefinition(&e2smkpmv2.E2SmKpmActionDefinition{
+		RicStyleType:            &e2smkpmv2.RicStyleType{Value: 4},
+		E2SmKpmActionDefinition: format1,
+	}))
+}
+
+func TestConditionScope(t *testing.T) {
+	sm := &Client{}
+	gbr := &model.UE{
+		Cell: &model.UECell{Strength: -70},
+		DRBs: []*model.DRB{{ID: 1, QoSFlows: []*model.QoSFlow{{QFI: 1, FiveQI: 1, GBR: true}}}},
+	}
+	nonGBR := &model.UE{
+		Cell: &model.UECell{Strength: -100},
+		DRBs: []*model.DRB{{ID: 1, QoSFlows: []*model.QoSFlow{{QFI: 1, FiveQI: 9}}}},
+	}
+
+	gbrTest := &e2smkpmv2.MatchingCondItem{
+		MatchingCondItem: &e2smkpmv2.MatchingCondItem_TestCondInfo{
+			TestCondInfo: &e2smkpmv2.TestCondInfo{
+				TestType: &e2smkpmv2.TestCondType{TestCondType: &e2smkpmv2.TestCondType_GBr{}},
+				TestExpr: e2smkpmv2.TestCondExpression_TEST_COND_EXPRESSION_PRESENT,
+			},
+		},
+	}
+	rsrpTest := &e2smkpmv2.MatchingCondItem{
+		MatchingCondItem: &e2smkpmv2.MatchingCondItem_TestCondInfo{
+			TestCondInfo: &e2smkpmv2.TestCondInfo{
+				TestType:  &e2smkpmv2.TestCondType{TestCondType: &e2smkpmv2.TestCondType_RSrp{}},
+				TestExpr:  e2smkpmv2.TestCondExpression_TEST_COND_EXPRESSION_LESSTHAN,
+				TestValue: &e2smkpmv2.TestCondValue{TestCondValue: &e2smkpmv2.TestCondValue_ValueInt{ValueInt: -90}},
+			},
+		},
+	}
+	labelCond := &e2smkpmv2.MatchingCondItem{
+		MatchingCondItem: &e2smkpmv2.MatchingCondItem_MeasLabel{
+			MeasLabel: &e2smkpmv2.MeasurementLabel{FiveQi: &e2smkpmv2.FiveQi{Value: 9}},
+		},
+	}
+
+	scope := sm.conditionScope(&e2smkpmv2.MatchingCondList{Value: []*e2smkpmv2.MatchingCondItem{gbrTest}})
+	assert.True(t, scope(gbr))
+	assert.False(t, scope(nonGBR))
+
+	// All conditions must be satisfied
+	scope = sm.conditionScope(&e2smkpmv2.MatchingCondList{Value: []*e2smkpmv2.MatchingCondItem{rsrpTest, labelCond}})
+	assert.False(t, scope(gbr))
+	assert.True(t, scope(nonGBR))
+
+	scope = sm.conditionScope(&e2smkpmv2.MatchingCondList{})
+	assert.True(t, scope(gbr))
+}
+
+func TestServesCellObject(t *testing.T) {
+	sm := &Client{
+		ServiceModel: &registry.ServiceModel{
+			Node: model.Node{EnbID: 144470, Cells: []ransimtypes.ECGI{84325717505, 84325717506}},
+		},
+	}
+	format1 := func(cellObjectID string) *e2smkpmv2.E2SmKpmActionDefinition {
+		return &e2smkpmv2.E2SmKpmActionDefinition{
+			E2SmKpmActionDefinition: &e2smkpmv2.E2SmKpmActionDefinition_ActionDefinitionFormat1{
+				ActionDefinitionFormat1: &e2smkpmv2.E2SmKpmActionDefinitionFormat1{
+					CellObjId: &e2smkpmv2.CellObjectId{Value: cellObjectID},
+				},
+			},
+		}
+	}
+	assert.True(t, sm.servesCellObject(format1("84325717506")))
+	assert.False(t, sm.servesCellObject(format1("84325717761")))
+	assert.False(t, sm.servesCellObject(format1("")))
+
+	format2 := &e2smkpmv2.E2SmKpmActionDefinition{
+		E2SmKpmActionDefinition: &e2smkpmv2.E2SmKpmActionDefinition_ActionDefinitionFormat2{
+			ActionDefinitionFormat2: &e2smkpmv2.E2SmKpmActionDefinitionFormat2{
+				SubscriptInfo: &e2smkpmv2.E2SmKpmActionDefinitionFormat1{
+					CellObjId: &e2smkpmv2.CellObjectId{Value: "84325717505"},
+				},
+			},
+		},
+	}
+	assert.True(t, sm.servesCellObject(format2))
+}
@@ -0,0 +1,91 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package kpm2
+
+import (
+	"encoding/binary"
+	"time"
+
+	e2smkpmv2 "github.com/onosproject/onos-e2-sm/servicemodels/e2sm_kpm_v2/v2/e2sm-kpm-v2"
+)
+
+// Resolutions of the 4 octet timestamps of the indication headers; the E2SM-KPM v2 TimeStamp is limited to 4 octets
+const (
+	// unixTimestampResolution encodes the seconds since the Unix epoch
+	unixTimestampResolution = "unix"
+	// ntpTimestampResolution encodes the seconds since the NTP epoch as defined by the E2SM-KPM specification
+	ntpTimestampResolution = "ntp"
+	// ntpShortTimestampResolution encodes the NTP short format of RFC 5905, i.e. 16 bit seconds since the NTP
+	// epoch modulo 2^16 followed by a 16 bit fraction of a second, i.e. a resolution of about 15 µs
+	ntpShortTimestampResolution = "ntpShort"
+)
+
+// ntpEpochOffset is the number of seconds from the NTP epoch, 1900-01-01, to the Unix epoch
+const ntpEpochOffset = 2208988800
+
+// clockOrigin is the wall clock time the simulator started at
+var clockOrigin = time.Now()
+
+// monotonicNow returns the current time as advanced by the monotonic clock from the start of the simulator, so that
+// the collection start times reported never go backwards when the wall clock is stepped
+func monotonicNow() time.Time {
+	return clockOrigin.Add(time.Since(clockOrigin))
+}
+
+// collectionStartTime returns the start of the collection of the measurements reported at the given time every
+// report period, aligned down to a boundary of the granularity period counted from the Unix epoch
+func collectionStartTime(now time.Time, reportPeriod time.Duration, granularity time.Duration) time.Time {
+	start := now.Add(-reportPeriod)
+	if granularity <= 0 {
+		return start
+	}
+	ns := start.UnixNano()
+	return time.Unix(0, ns-ns%int64(granularity))
+}
+
+// granularityPeriod returns the granularity period requested by the action definition; zero if none was given
+func granularityPeriod(actionDefinition *e2smkpmv2.E2SmKpmActionDefinition) time.Duration {
+	var period int32
+	switch {
+	case actionDefinition.GetActionDefinitionFormat1() != nil:
+		period = actionDefinition.GetActionDefinitionFormat1().GetGranulPeriod().GetValue()
+	case actionDefinition.GetActionDefinitionFormat2() != nil:
+		period = actionDefinition.GetActionDefinitionFormat2().GetSubscriptInfo().GetGranulPeriod().GetValue()
+	case actionDefinition.GetActionDefinitionFormat3() != nil:
+		period = actionDefinition.GetActionDefinitionFormat3().GetGranulPeriod().GetValue()
+	}
+	return time.Duration(period) * time.Millisecond
+}
+
+// encodeTimestamp encodes the time as a 4 octet timestamp of the given resolution
+func encodeTimestamp(t time.Time, resolution string) []byte {
+	timestamp := make([]byte, 4)
+	switch resolution {
+	case ntpTimestampResolution:
+		binary.BigEndian.PutUint32(timestamp, uint32(t.Unix()+ntpEpochOffset))
+	case ntpShortTimestampResolution:
+		seconds := uint32(t.Unix()+ntpEpochOffset) & 0xffff
+		fraction := uint32((uint64(t.Nanosecond()) << 16) / uint64(time.Second))
+		binary.BigEndian.PutUint32(timestamp, seconds<<16|fraction)
+	default:
+		binary.BigEndian.PutUint32(timestamp, uint32(t.Unix()))
+	}
+	return timestamp
+}
+
+// timestampResolution returns the timestamp resolution configured for the service model
+func (sm *Client) timestampResolution() string {
+	config, _ := sm.ServiceModel.Config()
+	return config.TimestampResolution
+}
+
+// checkTimestampResolution returns false if the resolution is not supported
+func checkTimestampResolution(resolution string) bool {
+	switch resolution {
+	case "", unixTimestampResolution, ntpTimestampResolution, ntpShortTimestampResolution:
+		return true
+	}
+	return false
+}
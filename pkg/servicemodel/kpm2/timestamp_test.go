@@ -0,0 +1,42 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package kpm2
+
+import (
+	"testing"
+	"time"
+
+	e2smkpmv2 "github.com/onosproject/onos-e2-sm/servicemodels/e2sm_kpm_v2/v2/e2sm-kpm-v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCollectionStartTime(t *testing.T) {
+	now := time.Unix(1000, int64(750*time.Millisecond))
+	assert.Equal(t, time.Unix(999, int64(750*time.Millisecond)), collectionStartTime(now, time.Second, 0))
+	assert.Equal(t, time.Unix(999, int64(500*time.Millisecond)), collectionStartTime(now, time.Second, 500*time.Millisecond))
+	assert.Equal(t, time.Unix(990, 0), collectionStartTime(now, time.Second, 10*time.Second))
+
+	assert.Equal(t, 100*time.Millisecond, granularityPeriod(&e2smkpmv2.E2SmKpmActionDefinition{
+		E2SmKpmActionDefinition: &e2smkpmv2.E2SmKpmActionDefinition_ActionDefinitionFormat1{
+			ActionDefinitionFormat1: &e2smkpmv2.E2SmKpmActionDefinitionFormat1{
+				GranulPeriod: &e2smkpmv2.GranularityPeriod{Value: 100},
+			},
+		},
+	}))
+	assert.Equal(t, time.Duration(0), granularityPeriod(nil))
+	assert.False(t, monotonicNow().Before(clockOrigin))
+}
+
+func TestEncodeTimestamp(t *testing.T) {
+	now := time.Unix(1600000000, int64(500*time.Millisecond))
+	assert.Equal(t, []byte{0x5f, 0x5e, 0x10, 0x00}, encodeTimestamp(now, ""))
+	assert.Equal(t, []byte{0x5f, 0x5e, 0x10, 0x00}, encodeTimestamp(now, unixTimestampResolution))
+	// 1600000000 + 2208988800 = 0xe3088e80
+	assert.Equal(t, []byte{0xe3, 0x08, 0x8e, 0x80}, encodeTimestamp(now, ntpTimestampResolution))
+	assert.Equal(t, []byte{0x8e, 0x80, 0x80, 0x00}, encodeTimestamp(now, ntpShortTimestampResolution))
+
+	assert.True(t, checkTimestampResolution(ntpShortTimestampResolution))
+	assert.False(t, checkTimestampResolution("ns"))
+}
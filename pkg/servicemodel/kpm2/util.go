@@ -5,47 +5,47 @@
 package kpm2
 
 import (
-	ransimtypes "github.com/onosproject/onos-api/go/onos/ransim/types"
 	e2smkpmv2 "github.com/onosproject/onos-e2-sm/servicemodels/e2sm_kpm_v2/v2/e2sm-kpm-v2"
 	e2appducontents "github.com/onosproject/onos-e2t/api/e2ap/v1beta2/e2ap-pdu-contents"
 	e2aptypes "github.com/onosproject/onos-e2t/pkg/southbound/e2ap101/types"
 	"github.com/onosproject/onos-lib-go/pkg/errors"
 	"github.com/onosproject/ran-simulator/pkg/modelplugins"
-	"github.com/onosproject/ran-simulator/pkg/utils/e2sm/kpm2/labelinfo"
+	"github.com/onosproject/ran-simulator/pkg/store/subscriptions"
 	"google.golang.org/protobuf/proto"
 )
 
-func (sm *Client) getActionDefinition(actionList []*e2appducontents.RicactionToBeSetupItemIes, ricActionsAccepted []*e2aptypes.RicActionID) ([]*e2smkpmv2.E2SmKpmActionDefinition, error) {
+// actionDefinitions are the decoded action definitions of the actions of a subscription
+type actionDefinitions map[e2aptypes.RicActionID]*e2smkpmv2.E2SmKpmActionDefinition
+
+// getActionDefinitions decodes the action definitions of the given accepted actions; actions without
+// an action definition report all measurements
+func (sm *Client) getActionDefinitions(actions []*subscriptions.Action) (actionDefinitions, error) {
 	modelPlugin, err := sm.getModelPlugin()
 	if err != nil {
 		log.Warn(err)
 		return nil, err
 	}
 
-	var actionDefinitions []*e2smkpmv2.E2SmKpmActionDefinition
-	for _, action := range actionList {
-		for _, acceptedActionID := range ricActionsAccepted {
-			if action.Value.RicActionId.Value == int32(*acceptedActionID) {
-				actionDefinitionBytes := action.Value.RicActionDefinition.Value
-				actionDefinitionProtoBytes, err := modelPlugin.ActionDefinitionASN1toProto(actionDefinitionBytes)
-				if err != nil {
-					log.Warn(err)
-					return nil, err
-				}
-
-				actionDefinition := &e2smkpmv2.E2SmKpmActionDefinition{}
-				err = proto.Unmarshal(actionDefinitionProtoBytes, actionDefinition)
-				if err != nil {
-					log.Warn(err)
-					return nil, err
-				}
-
-				actionDefinitions = append(actionDefinitions, actionDefinition)
+	definitions := make(actionDefinitions, len(actions))
+	for _, action := range actions {
+		if len(action.Definition) == 0 {
+			continue
+		}
+		actionDefinitionProtoBytes, err := modelPlugin.ActionDefinitionASN1toProto(action.Definition)
+		if err != nil {
+			log.Warn(err)
+			return nil, err
+		}
 
-			}
+		actionDefinition := &e2smkpmv2.E2SmKpmActionDefinition{}
+		err = proto.Unmarshal(actionDefinitionProtoBytes, actionDefinition)
+		if err != nil {
+			log.Warn(err)
+			return nil, err
 		}
+		definitions[action.ID] = actionDefinition
 	}
-	return actionDefinitions, nil
+	return definitions, nil
 }
 
 // getReportPeriod extracts report period
@@ -77,56 +77,3 @@ func (sm *Client) getModelPlugin() (modelplugins.ServiceModel, error) {
 
 	return modelPlugin, nil
 }
-
-func (sm *Client) createInfoLabelList() (*e2smkpmv2.LabelInfoList, error) {
-	plmnID := ransimtypes.NewUint24(uint32(sm.ServiceModel.Model.PlmnID))
-	var fiveQI int32 = 10
-	var qfi int32 = 62
-	var qci int32 = 15
-	var qciMin int32 = 1
-	var qciMax int32 = 15
-	var arpMax int32 = 15
-	var arpMin int32 = 10
-	var bitrateRange int32 = 251
-	var layerMuMimo int32 = 5
-	var distX int32 = 123
-	var distY int32 = 456
-	var distZ int32 = 789
-	startEndIndication := e2smkpmv2.StartEndInd_START_END_IND_START
-	sst := []byte{0x01}
-	sd := []byte{0x01, 0x02, 0x03}
-
-	// Creates label information
-	labelInfo, err := labelinfo.NewLabelInfo(labelinfo.WithFiveQI(fiveQI),
-		labelinfo.WithPlmnID(plmnID.Value()),
-		labelinfo.WithArpMin(arpMin),
-		labelinfo.WithArpMax(arpMax),
-		labelinfo.WithBitRateRange(bitrateRange),
-		labelinfo.WithDistX(distX),
-		labelinfo.WithDistY(distY),
-		labelinfo.WithDistZ(distZ),
-		labelinfo.WithLayerMuMimo(layerMuMimo),
-		labelinfo.WithQCI(qci),
-		labelinfo.WithQCIMin(qciMin),
-		labelinfo.WithQCIMax(qciMax),
-		labelinfo.WithQFI(qfi),
-		labelinfo.WithStartEndIndication(startEndIndication),
-		labelinfo.WithSD(sd),
-		labelinfo.WithSST(sst))
-
-	if err != nil {
-		log.Warn(err)
-		return nil, err
-	}
-	labelInfoItem, err := labelInfo.Build()
-	if err != nil {
-		log.Warn(err)
-		return nil, err
-	}
-	labelInfoList := e2smkpmv2.LabelInfoList{
-		Value: make([]*e2smkpmv2.LabelInfoItem, 0),
-	}
-	labelInfoList.Value = append(labelInfoList.Value, labelInfoItem)
-
-	return &labelInfoList, nil
-}
@@ -6,6 +6,7 @@ package rc
 
 import (
 	"context"
+	"sync"
 	"time"
 
 	e2smtypes "github.com/onosproject/onos-api/go/onos/e2t/e2sm"
@@ -36,6 +37,7 @@ import (
 	"github.com/onosproject/onos-e2-sm/servicemodels/e2sm_rc_pre/pdubuilder"
 	"github.com/onosproject/ran-simulator/pkg/model"
 	"github.com/onosproject/ran-simulator/pkg/modelplugins"
+	"github.com/onosproject/ran-simulator/pkg/qos"
 	"google.golang.org/protobuf/proto"
 
 	e2appducontents "github.com/onosproject/onos-e2t/api/e2ap/v1beta2/e2ap-pdu-contents"
@@ -59,6 +61,22 @@ const (
 // Client rc service model client
 type Client struct {
 	ServiceModel *registry.ServiceModel
+	// cellsMu guards the cells of the node, which change while the node is reporting
+	cellsMu sync.RWMutex
+}
+
+// cells returns the current cells of the node
+func (sm *Client) cells() []ransimtypes.ECGI {
+	sm.cellsMu.RLock()
+	defer sm.cellsMu.RUnlock()
+	return sm.ServiceModel.Node.Cells
+}
+
+// updateNode updates the cells of the node, so that new cells are reported
+func (sm *Client) updateNode(node model.Node) {
+	sm.cellsMu.Lock()
+	defer sm.cellsMu.Unlock()
+	sm.ServiceModel.Node.Cells = append([]ransimtypes.ECGI(nil), node.Cells...)
 }
 
 func (sm *Client) reportPeriodicIndication(ctx context.Context, interval int32, subscription *subutils.Subscription) error {
@@ -83,6 +101,10 @@ func (sm *Client) reportPeriodicIndication(ctx context.Context, interval int32,
 		case <-sub.E2Channel.Context().Done():
 			sub.Ticker.Stop()
 			return nil
+
+		case <-ctx.Done():
+			sub.Ticker.Stop()
+			return nil
 		}
 	}
 }
@@ -94,18 +116,24 @@ func (sm *Client) sendRicIndication(ctx context.Context, subscription *subutils.
 		return err
 	}
 
-	node := sm.ServiceModel.Node
 	// Creates and sends an indication message for each cell in the node
-	for _, ecgi := range node.Cells {
-		ricIndication, err := sm.createRicIndication(ctx, ecgi, subscription)
-		if err != nil {
-			log.Error(err)
-			return err
+	for _, ecgi := range sm.cells() {
+		// Cells that are locked or in outage do not report
+		if cell, err := sm.ServiceModel.CellStore.Get(ctx, ecgi); err == nil && !cell.InService() {
+			continue
 		}
-		err = sub.E2Channel.RICIndication(ctx, ricIndication)
-		if err != nil {
-			log.Error(err)
-			return err
+		// Each report action is reported independently
+		for _, action := range sub.ReportActions() {
+			ricIndication, err := sm.createRicIndication(ctx, ecgi, subscription, action.ID)
+			if err != nil {
+				log.Error(err)
+				return err
+			}
+			err = sub.E2Channel.RICIndication(ctx, ricIndication)
+			if err != nil {
+				log.Error(err)
+				return err
+			}
 		}
 	}
 	return nil
@@ -120,12 +148,21 @@ func (sm *Client) reportIndicationOnChange(ctx context.Context, subscription *su
 	}
 	cellEventCh := make(chan event.Event)
 	metricEventCh := make(chan event.Event)
-	nodeCells := sm.ServiceModel.Node.Cells
-	err = sm.ServiceModel.CellStore.Watch(context.Background(), cellEventCh)
+	nodeCells := sm.cells()
+	err = sm.ServiceModel.CellStore.Watch(context.Background(), cellEventCh, cells.WatchOptions{
+		Types: []cells.CellEvent{cells.UpdatedNeighbors, cells.UpdatedAdminState},
+	})
 	if err != nil {
 		return err
 	}
-	err = sm.ServiceModel.MetricStore.Watch(context.Background(), metricEventCh)
+	nodeCellIDs := make([]uint64, 0, len(nodeCells))
+	for _, nodeCell := range nodeCells {
+		nodeCellIDs = append(nodeCellIDs, uint64(nodeCell))
+	}
+	err = sm.ServiceModel.MetricStore.Watch(context.Background(), metricEventCh, metrics.WatchOptions{
+		EntityIDs: nodeCellIDs,
+		Names:     []string{"pci"},
+	})
 	if err != nil {
 		return err
 	}
@@ -143,7 +180,7 @@ func (sm *Client) reportIndicationOnChange(ctx context.Context, subscription *su
 			cellEventType := cellEvent.Type.(cells.CellEvent)
 			if cellEventType == cells.UpdatedNeighbors {
 				cell := cellEvent.Value.(*model.Cell)
-				for _, nodeCell := range nodeCells {
+				for _, nodeCell := range sm.cells() {
 					if nodeCell == cell.ECGI {
 						err = sm.sendRicIndication(ctx, subscription)
 						if err != nil {
@@ -151,22 +188,29 @@ func (sm *Client) reportIndicationOnChange(ctx context.Context, subscription *su
 						}
 					}
 				}
-			}
-		case metricEvent := <-metricEventCh:
-			log.Debug("Received metric event:", metricEvent)
-			metricKey := metricEvent.Key.(metrics.Key)
-			for _, nodeCell := range nodeCells {
-				if uint64(nodeCell) == metricKey.EntityID && metricKey.Name == "pci" {
+			} else if cellEventType == cells.UpdatedAdminState {
+				// Changes of the cell state change the neighbour lists of the adjacent cells
+				cell := cellEvent.Value.(*model.Cell)
+				if sm.isNodeCellOrNeighbour(ctx, cell.ECGI) {
 					err = sm.sendRicIndication(ctx, subscription)
 					if err != nil {
 						log.Error(err)
 					}
 				}
 			}
+		case metricEvent := <-metricEventCh:
+			log.Debug("Received metric event:", metricEvent)
+			err = sm.sendRicIndication(ctx, subscription)
+			if err != nil {
+				log.Error(err)
+			}
 
 		case <-sub.E2Channel.Context().Done():
 			log.Debug("E2 channel context is done")
 			return nil
+
+		case <-ctx.Done():
+			return nil
 		}
 	}
 }
@@ -178,10 +222,18 @@ func NewServiceModel(node model.Node, model *model.Model,
 	ueStore ues.Store, cellStore cells.Store, metricStore metrics.Store) (registry.ServiceModel, error) {
 	modelName := e2smtypes.ShortName(modelFullName)
 	rcSm := registry.ServiceModel{
-		RanFunctionID:       registry.Rc,
-		ModelName:           modelName,
-		Revision:            1,
-		OID:                 modelOID,
+		RanFunctionID: registry.Rc,
+		ModelName:     modelName,
+		Revision:      1,
+		OID:           modelOID,
+		Payloads: registry.Payloads{
+			registry.EventTriggerPayload:      func() proto.Message { return &e2sm_rc_pre_ies.E2SmRcPreEventTriggerDefinition{} },
+			registry.IndicationHeaderPayload:  func() proto.Message { return &e2sm_rc_pre_ies.E2SmRcPreIndicationHeader{} },
+			registry.IndicationMessagePayload: func() proto.Message { return &e2sm_rc_pre_ies.E2SmRcPreIndicationMessage{} },
+			registry.ControlHeaderPayload:     func() proto.Message { return &e2sm_rc_pre_ies.E2SmRcPreControlHeader{} },
+			registry.ControlMessagePayload:    func() proto.Message { return &e2sm_rc_pre_ies.E2SmRcPreControlMessage{} },
+			registry.ControlOutcomePayload:    func() proto.Message { return &e2sm_rc_pre_ies.E2SmRcPreControlOutcome{} },
+		},
 		Version:             version,
 		ModelPluginRegistry: modelPluginRegistry,
 		Node:                node,
@@ -198,6 +250,7 @@ func NewServiceModel(node model.Node, model *model.Model,
 	}
 
 	rcSm.Client = rcClient
+	rcSm.NodeUpdater = rcClient.updateNode
 
 	var ranFunctionShortName = string(modelFullName)
 	var ranFunctionE2SmOid = modelOID
@@ -275,6 +328,11 @@ func (sm *Client) RICControl(ctx context.Context, request *e2appducontents.Ricco
 	parameterName := controlMessage.GetControlMessage().ParameterType.RanParameterName.Value
 	parameterID := controlMessage.GetControlMessage().ParameterType.RanParameterId.Value
 
+	// Parameters targeting a DRB of a UE set the DRB attribute of the UE rather than a cell metric
+	if imsi, drbID, ok := qos.ParseUEDRBParameter(parameterName); ok {
+		return sm.controlDRB(ctx, request, ecgi, imsi, drbID, parameterID, controlMessage.GetControlMessage())
+	}
+
 	oldValue, found := sm.ServiceModel.MetricStore.Get(ctx, uint64(ecgi), parameterName)
 	log.Debugf("Current value for ecgi %d is %v", ecgi, oldValue)
 	if !found {
@@ -345,6 +403,71 @@ func (sm *Client) RICControl(ctx context.Context, request *e2appducontents.Ricco
 	return response, nil, nil
 }
 
+// controlDRB sets up, modifies or releases a DRB of a UE served by the cell, as requested by setting the
+// ue.<imsi>.drb.<id> parameter to a printable string: a DRB specification, e.g. "5qi=1,arp=2,gbr=true", is validated and
+// set as the drb.<id> attribute of the UE, creating it if needed, for the QoS controller to apply, while an empty string
+// deletes the attribute, releasing the DRB; the control outcome reports the DRB ID
+func (sm *Client) controlDRB(ctx context.Context, request *e2appducontents.RiccontrolRequest, ecgi ransimtypes.ECGI, imsi ransimtypes.IMSI, drbID int32,
+	parameterID int32, message *e2sm_rc_pre_ies.E2SmRcPreControlMessageFormat1) (*e2appducontents.RiccontrolAcknowledge, *e2appducontents.RiccontrolFailure, error) {
+	modelPlugin, err := sm.getModelPlugin()
+	if err != nil {
+		log.Error(err)
+		return nil, nil, err
+	}
+	outcomeAsn1Bytes, err := controloutcome.NewControlOutcome(
+		controloutcome.WithRanParameterID(parameterID),
+		controloutcome.WithRanParameterValue(drbID)).
+		ToAsn1Bytes(modelPlugin)
+	if err != nil {
+		return nil, nil, err
+	}
+	control := controlutils.NewControl(
+		controlutils.WithRanFuncID(controlutils.GetRanFunctionID(request)),
+		controlutils.WithRequestID(controlutils.GetRequesterID(request)),
+		controlutils.WithRicInstanceID(controlutils.GetRicInstanceID(request)),
+		controlutils.WithRicControlOutcome(outcomeAsn1Bytes))
+
+	if err := sm.setDRB(ctx, ecgi, imsi, drbID, message); err != nil {
+		log.Warnf("Unable to control DRB %d of UE %d: %v", drbID, imsi, err)
+		failure, err := control.BuildControlFailure()
+		if err != nil {
+			return nil, nil, err
+		}
+		return nil, failure, nil
+	}
+	response, err := control.BuildControlAcknowledge()
+	if err != nil {
+		return nil, nil, err
+	}
+	return response, nil, nil
+}
+
+// setDRB sets or deletes the DRB attribute of the UE as requested by the control message
+func (sm *Client) setDRB(ctx context.Context, ecgi ransimtypes.ECGI, imsi ransimtypes.IMSI, drbID int32, message *e2sm_rc_pre_ies.E2SmRcPreControlMessageFormat1) error {
+	if message.GetParameterType().GetRanParameterType() != e2sm_rc_pre_ies.RanparameterType_RANPARAMETER_TYPE_PRINTABLE_STRING {
+		return errors.New(errors.Invalid, "DRB parameters must be printable strings")
+	}
+	ue, err := sm.ServiceModel.UEs.Get(ctx, imsi)
+	if err != nil {
+		return err
+	}
+	if ue.Cell == nil || ue.Cell.ECGI != ecgi {
+		return errors.New(errors.NotFound, "UE %d is not served by cell %d", imsi, ecgi)
+	}
+	attribute := qos.DRBAttribute(drbID)
+	spec := message.GetParameterVal().GetValuePrtS()
+	if spec == "" {
+		if _, found := sm.ServiceModel.MetricStore.Get(ctx, uint64(imsi), attribute); !found {
+			return errors.New(errors.NotFound, "DRB %d of UE %d not found", drbID, imsi)
+		}
+		return sm.ServiceModel.MetricStore.Delete(ctx, uint64(imsi), attribute)
+	}
+	if _, err := qos.ParseDRB(drbID, spec); err != nil {
+		return err
+	}
+	return sm.ServiceModel.MetricStore.Set(ctx, uint64(imsi), attribute, spec)
+}
+
 // RICSubscription implements subscription handler for RC service model
 func (sm *Client) RICSubscription(ctx context.Context, request *e2appducontents.RicsubscriptionRequest) (response *e2appducontents.RicsubscriptionResponse, failure *e2appducontents.RicsubscriptionFailure, err error) {
 	log.Infof("Ric Subscription Request is received for service model %v and e2 node with ID:%d", sm.ServiceModel.ModelName, sm.ServiceModel.Node.EnbID)
@@ -391,6 +514,12 @@ func (sm *Client) RICSubscription(ctx context.Context, request *e2appducontents.
 		return nil, subscriptionFailure, nil
 	}
 
+	sub, err := sm.ServiceModel.Subscriptions.Get(subscriptions.NewID(ricInstanceID, reqID, ranFuncID))
+	if err != nil {
+		return nil, nil, err
+	}
+	sub.AcceptActions(ricActionsAccepted)
+
 	response, err = subscription.BuildSubscriptionResponse()
 	if err != nil {
 		return nil, nil, err
@@ -404,9 +533,9 @@ func (sm *Client) RICSubscription(ctx context.Context, request *e2appducontents.
 	switch eventTriggerType {
 	case e2sm_rc_pre_ies.RcPreTriggerType_RC_PRE_TRIGGER_TYPE_UPON_CHANGE:
 		log.Debug("Received on change report subscription request")
+		ctx, done := sm.ServiceModel.Subscriptions.StartReport(context.Background(), sub.ID)
 		go func() {
-			ctx, cancel := context.WithCancel(context.Background())
-			defer cancel()
+			defer done()
 			err = sm.reportIndicationOnChange(ctx, subscription)
 			if err != nil {
 				return
@@ -414,9 +543,9 @@ func (sm *Client) RICSubscription(ctx context.Context, request *e2appducontents.
 		}()
 	case e2sm_rc_pre_ies.RcPreTriggerType_RC_PRE_TRIGGER_TYPE_PERIODIC:
 		log.Debug("Received periodic report subscription request")
+		ctx, done := sm.ServiceModel.Subscriptions.StartReport(context.Background(), sub.ID)
 		go func() {
-			ctx, cancel := context.WithCancel(context.Background())
-			defer cancel()
+			defer done()
 			interval, err := sm.getReportPeriod(request)
 			if err != nil {
 				log.Error(err)
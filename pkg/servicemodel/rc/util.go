@@ -24,6 +24,7 @@ import (
 	e2sm_rc_pre_ies "github.com/onosproject/onos-e2-sm/servicemodels/e2sm_rc_pre/v1/e2sm-rc-pre-ies"
 	e2smrcpreies "github.com/onosproject/onos-e2-sm/servicemodels/e2sm_rc_pre/v1/e2sm-rc-pre-ies"
 	e2appducontents "github.com/onosproject/onos-e2t/api/e2ap/v1beta2/e2ap-pdu-contents"
+	e2aptypes "github.com/onosproject/onos-e2t/pkg/southbound/e2ap101/types"
 	"github.com/onosproject/onos-lib-go/pkg/errors"
 	"github.com/onosproject/ran-simulator/pkg/modelplugins"
 	"google.golang.org/protobuf/proto"
@@ -95,6 +96,25 @@ func (sm *Client) getModelPlugin() (modelplugins.ServiceModel, error) {
 	return modelPlugin, nil
 }
 
+// isNodeCellOrNeighbour returns true if the specified cell is served by the node or is a neighbour of one of its cells
+func (sm *Client) isNodeCellOrNeighbour(ctx context.Context, ecgi ransimtypes.ECGI) bool {
+	for _, nodeCell := range sm.cells() {
+		if nodeCell == ecgi {
+			return true
+		}
+		cell, err := sm.ServiceModel.CellStore.Get(ctx, nodeCell)
+		if err != nil {
+			continue
+		}
+		for _, neighbour := range cell.Neighbors {
+			if neighbour == ecgi {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 func (sm *Client) getPlmnID() ransimtypes.Uint24 {
 	plmnIDUint24 := ransimtypes.Uint24{}
 	plmnIDUint24.Set(uint32(sm.ServiceModel.Model.PlmnID))
@@ -154,7 +174,12 @@ func (sm *Client) getCellPci(ctx context.Context, ecgi ransimtypes.ECGI) (int32,
 func (sm *Client) getEarfcn(ctx context.Context, ecgi ransimtypes.ECGI) (int32, error) {
 	earfcn, found := sm.ServiceModel.MetricStore.Get(ctx, uint64(ecgi), "earfcn")
 	if !found {
-		return 0, errors.New(errors.NotFound, "earfc value is not found for cell:", ecgi)
+		// Fall back to the carrier frequency of the cell model
+		cell, err := sm.ServiceModel.CellStore.Get(ctx, ecgi)
+		if err != nil || cell.Earfcn == 0 {
+			return 0, errors.New(errors.NotFound, "earfc value is not found for cell:", ecgi)
+		}
+		return int32(cell.Earfcn), nil
 	}
 
 	return int32(earfcn.(uint32)), nil
@@ -197,8 +222,8 @@ func (sm *Client) getReportPeriod(request *e2appducontents.RicsubscriptionReques
 	return reportPeriod, nil
 }
 
-// createRicIndication creates ric indication  for each cell in the node
-func (sm *Client) createRicIndication(ctx context.Context, ecgi ransimtypes.ECGI, subscription *subutils.Subscription) (*e2appducontents.Ricindication, error) {
+// createRicIndication creates ric indication for the given cell and report action
+func (sm *Client) createRicIndication(ctx context.Context, ecgi ransimtypes.ECGI, subscription *subutils.Subscription, actionID e2aptypes.RicActionID) (*e2appducontents.Ricindication, error) {
 	plmnID := sm.getPlmnID()
 	var neighbourList []*e2sm_rc_pre_ies.Nrt
 	neighbourList = make([]*e2sm_rc_pre_ies.Nrt, 0)
@@ -220,7 +245,11 @@ func (sm *Client) createRicIndication(ctx context.Context, ecgi ransimtypes.ECGI
 	if err != nil {
 		return nil, err
 	}
-	for index, neighbourEcgi := range cell.Neighbors {
+	for _, neighbourEcgi := range cell.Neighbors {
+		// Cells that are locked or in outage are not reported as neighbours
+		if neighbourCell, err := sm.ServiceModel.CellStore.Get(ctx, neighbourEcgi); err == nil && !neighbourCell.InService() {
+			continue
+		}
 		neighbourCellPci, err := sm.getCellPci(ctx, neighbourEcgi)
 		if err != nil {
 			log.Error(err)
@@ -236,7 +265,7 @@ func (sm *Client) createRicIndication(ctx context.Context, ecgi ransimtypes.ECGI
 		}
 		neighbourEci := ransimtypes.GetECI(uint64(neighbourEcgi))
 		neighbour, err := nrt.NewNeighbour(
-			nrt.WithNrIndex(int32(index)),
+			nrt.WithNrIndex(int32(len(neighbourList))),
 			nrt.WithPci(neighbourCellPci),
 			nrt.WithEutraCellIdentity(uint64(neighbourEci)),
 			nrt.WithEarfcn(neighbourEarfcn),
@@ -296,6 +325,7 @@ func (sm *Client) createRicIndication(ctx context.Context, ecgi ransimtypes.ECGI
 		indicationutils.WithRicInstanceID(subscription.GetRicInstanceID()),
 		indicationutils.WithRanFuncID(subscription.GetRanFuncID()),
 		indicationutils.WithRequestID(subscription.GetReqID()),
+		indicationutils.WithRicActionID(int32(actionID)),
 		indicationutils.WithIndicationHeader(indicationHeaderAsn1Bytes),
 		indicationutils.WithIndicationMessage(indicationMessageAsn1Bytes))
 
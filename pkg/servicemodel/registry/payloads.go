@@ -0,0 +1,75 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package registry
+
+import (
+	e2smtypes "github.com/onosproject/onos-api/go/onos/e2t/e2sm"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"google.golang.org/protobuf/proto"
+)
+
+// PayloadKind is the kind of an E2SM payload nested in an E2AP message
+type PayloadKind string
+
+const (
+	// EventTriggerPayload RIC event trigger definition of a subscription request
+	EventTriggerPayload PayloadKind = "eventTrigger"
+	// ActionDefinitionPayload RIC action definition of a subscription request
+	ActionDefinitionPayload PayloadKind = "actionDefinition"
+	// IndicationHeaderPayload RIC indication header
+	IndicationHeaderPayload PayloadKind = "indicationHeader"
+	// IndicationMessagePayload RIC indication message
+	IndicationMessagePayload PayloadKind = "indicationMessage"
+	// ControlHeaderPayload RIC control header
+	ControlHeaderPayload PayloadKind = "controlHeader"
+	// ControlMessagePayload RIC control message
+	ControlMessagePayload PayloadKind = "controlMessage"
+	// ControlOutcomePayload RIC control outcome of a control acknowledge
+	ControlOutcomePayload PayloadKind = "controlOutcome"
+)
+
+// Payloads creates the protobuf messages the E2SM payloads of a service model are decoded into by its model plugin
+type Payloads map[PayloadKind]func() proto.Message
+
+// Decode decodes the ASN.1 encoded E2SM payload of the given kind via the model plugin of the service model
+func (sm *ServiceModel) Decode(kind PayloadKind, asn1Bytes []byte) (proto.Message, error) {
+	newMessage, ok := sm.Payloads[kind]
+	if !ok {
+		return nil, errors.New(errors.NotSupported, "%s payloads of service model %s are not decoded", kind, sm.ModelName)
+	}
+	if sm.ModelPluginRegistry == nil {
+		return nil, errors.New(errors.NotFound, "model plugin for model %s not found", sm.ModelName)
+	}
+	plugin, err := sm.ModelPluginRegistry.GetPlugin(e2smtypes.OID(sm.OID))
+	if err != nil {
+		return nil, errors.New(errors.NotFound, "model plugin for model %s not found", sm.ModelName)
+	}
+
+	var protoBytes []byte
+	switch kind {
+	case EventTriggerPayload:
+		protoBytes, err = plugin.EventTriggerDefinitionASN1toProto(asn1Bytes)
+	case ActionDefinitionPayload:
+		protoBytes, err = plugin.ActionDefinitionASN1toProto(asn1Bytes)
+	case IndicationHeaderPayload:
+		protoBytes, err = plugin.IndicationHeaderASN1toProto(asn1Bytes)
+	case IndicationMessagePayload:
+		protoBytes, err = plugin.IndicationMessageASN1toProto(asn1Bytes)
+	case ControlHeaderPayload:
+		protoBytes, err = plugin.ControlHeaderASN1toProto(asn1Bytes)
+	case ControlMessagePayload:
+		protoBytes, err = plugin.ControlMessageASN1toProto(asn1Bytes)
+	case ControlOutcomePayload:
+		protoBytes, err = plugin.ControlOutcomeASN1toProto(asn1Bytes)
+	}
+	if err != nil {
+		return nil, err
+	}
+	message := newMessage()
+	if err := proto.Unmarshal(protoBytes, message); err != nil {
+		return nil, err
+	}
+	return message, nil
+}
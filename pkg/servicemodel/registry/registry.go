@@ -5,6 +5,7 @@
 package registry
 
 import (
+	"bytes"
 	"sync"
 
 	e2smtypes "github.com/onosproject/onos-api/go/onos/e2t/e2sm"
@@ -45,6 +46,7 @@ type ServiceModel struct {
 	Description         []byte // ASN1 bytes from Service Model
 	Revision            int
 	OID                 ModelOid
+	Payloads            Payloads
 	Client              servicemodel.Client
 	ModelPluginRegistry modelplugins.ModelRegistry
 	Node                model.Node
@@ -54,6 +56,25 @@ type ServiceModel struct {
 	UEs                 ues.Store
 	CellStore           cells.Store
 	MetricStore         metrics.Store
+	// DescriptionBuilder rebuilds the description of the RAN function for the given node, e.g. after its cells
+	// changed; descriptions that do not depend on the node are not rebuilt if nil
+	DescriptionBuilder func(node model.Node) ([]byte, error)
+	// NodeUpdater passes the changed node, e.g. with new cells, to the client, which otherwise keeps serving the node
+	// it was created for
+	NodeUpdater func(node model.Node)
+}
+
+// Config returns the configuration of the service model in the simulation model, if any
+func (sm *ServiceModel) Config() (model.ServiceModel, bool) {
+	if sm.Model == nil {
+		return model.ServiceModel{}, false
+	}
+	for _, config := range sm.Model.ServiceModels {
+		if RanFunctionID(config.ID) == sm.RanFunctionID {
+			return config, true
+		}
+	}
+	return model.ServiceModel{}, false
 }
 
 // NewServiceModelRegistry creates a service model registry
@@ -104,5 +125,47 @@ func (s *ServiceModelRegistry) GetServiceModels() map[RanFunctionID]ServiceModel
 
 // GetRanFunctions returns the list of registered ran functions
 func (s *ServiceModelRegistry) GetRanFunctions() e2aptypes.RanFunctions {
-	return s.ranFunctions
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	ranFunctions := make(e2aptypes.RanFunctions, len(s.ranFunctions))
+	for id, item := range s.ranFunctions {
+		ranFunctions[id] = item
+	}
+	return ranFunctions
+}
+
+// UpdateDescriptions passes the given node to the clients of the registered service models, rebuilds their
+// descriptions and increments the revision of the RAN functions whose description changed, which are returned
+func (s *ServiceModelRegistry) UpdateDescriptions(node model.Node) ([]RanFunctionID, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var updated []RanFunctionID
+	for id, sm := range s.serviceModels {
+		sm.Node = node
+		s.serviceModels[id] = sm
+		if sm.NodeUpdater != nil {
+			sm.NodeUpdater(node)
+		}
+		if sm.DescriptionBuilder == nil {
+			continue
+		}
+		description, err := sm.DescriptionBuilder(node)
+		if err != nil {
+			return updated, err
+		}
+		if bytes.Equal(description, sm.Description) {
+			continue
+		}
+		sm.Description = description
+		sm.Revision++
+		s.serviceModels[id] = sm
+		s.ranFunctions[e2aptypes.RanFunctionID(id)] = e2aptypes.RanFunctionItem{
+			Description: sm.Description,
+			Revision:    e2aptypes.RanFunctionRevision(sm.Revision),
+			OID:         e2aptypes.RanFunctionOID(sm.OID),
+		}
+		log.Infof("RAN function %d of E2 node %d updated to revision %d", id, node.EnbID, sm.Revision)
+		updated = append(updated, id)
+	}
+	return updated, nil
 }
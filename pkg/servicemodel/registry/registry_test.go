@@ -8,10 +8,13 @@ import (
 	"context"
 	"testing"
 
+	"github.com/onosproject/onos-api/go/onos/ransim/types"
+	"github.com/onosproject/ran-simulator/pkg/model"
 	"github.com/onosproject/ran-simulator/pkg/servicemodel"
 	"github.com/stretchr/testify/assert"
 
 	e2appducontents "github.com/onosproject/onos-e2t/api/e2ap/v1beta2/e2ap-pdu-contents"
+	e2aptypes "github.com/onosproject/onos-e2t/pkg/southbound/e2ap101/types"
 )
 
 var _ servicemodel.Client = &mockServiceModel{}
@@ -66,3 +69,49 @@ func TestRegisterServiceModel(t *testing.T) {
 	assert.Equal(t, len(ranFunctions), 1)
 
 }
+
+func TestUpdateDescriptions(t *testing.T) {
+	registry := NewServiceModelRegistry()
+	assert.NoError(t, registry.RegisterServiceModel(ServiceModel{
+		RanFunctionID: Kpm2,
+		Client:        &mockServiceModel{t: t},
+		Description:   []byte{1},
+		Revision:      1,
+		DescriptionBuilder: func(node model.Node) ([]byte, error) {
+			return []byte{byte(len(node.Cells))}, nil
+		},
+	}))
+	var rcCells []types.ECGI
+	assert.NoError(t, registry.RegisterServiceModel(ServiceModel{
+		RanFunctionID: Rc,
+		Client:        &mockServiceModel{t: t},
+		Description:   []byte{1},
+		Revision:      1,
+		NodeUpdater: func(node model.Node) {
+			rcCells = node.Cells
+		},
+	}))
+
+	// The description is unchanged as long as the node has one cell
+	updated, err := registry.UpdateDescriptions(model.Node{EnbID: 1, Cells: []types.ECGI{1}})
+	assert.NoError(t, err)
+	assert.Empty(t, updated)
+	assert.Equal(t, e2aptypes.RanFunctionRevision(1), registry.GetRanFunctions()[e2aptypes.RanFunctionID(Kpm2)].Revision)
+
+	updated, err = registry.UpdateDescriptions(model.Node{EnbID: 1, Cells: []types.ECGI{1, 2}})
+	assert.NoError(t, err)
+	assert.Equal(t, []RanFunctionID{Kpm2}, updated)
+	ranFunctions := registry.GetRanFunctions()
+	assert.Equal(t, e2aptypes.RanFunctionRevision(2), ranFunctions[e2aptypes.RanFunctionID(Kpm2)].Revision)
+	assert.Equal(t, e2aptypes.RanFunctionDescription([]byte{2}), ranFunctions[e2aptypes.RanFunctionID(Kpm2)].Description)
+	assert.Equal(t, e2aptypes.RanFunctionRevision(1), ranFunctions[e2aptypes.RanFunctionID(Rc)].Revision)
+	sm, err := registry.GetServiceModel(Kpm2)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, sm.Revision)
+
+	// The clients serve the new cells even if their description does not depend on them
+	assert.Equal(t, []types.ECGI{1, 2}, rcCells)
+	sm, err = registry.GetServiceModel(Rc)
+	assert.NoError(t, err)
+	assert.Equal(t, []types.ECGI{1, 2}, sm.Node.Cells)
+}
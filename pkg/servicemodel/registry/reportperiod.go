@@ -0,0 +1,56 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package registry
+
+import (
+	e2apies "github.com/onosproject/onos-e2t/api/e2ap/v1beta2/e2ap-ies"
+)
+
+// DefaultMinReportPeriod is the shortest report period in milliseconds accepted unless configured otherwise
+const DefaultMinReportPeriod = 10
+
+// reportPeriodBounds returns the report period bounds in milliseconds configured for the service model;
+// a max of zero means the period is unbounded above
+func (sm *ServiceModel) reportPeriodBounds() (min int32, max int32, clamp bool) {
+	min = DefaultMinReportPeriod
+	config, ok := sm.Config()
+	if !ok {
+		return min, 0, false
+	}
+	if config.MinReportPeriod > 0 {
+		min = int32(config.MinReportPeriod)
+	}
+	return min, int32(config.MaxReportPeriod), config.ClampReportPeriod
+}
+
+// CheckReportPeriod validates the report period in milliseconds requested by a subscription against the bounds
+// of the service model. Out-of-range periods are clamped to the bounds if so configured; otherwise the cause
+// for rejecting the subscription is returned.
+func (sm *ServiceModel) CheckReportPeriod(period int32) (int32, *e2apies.Cause) {
+	min, max, clamp := sm.reportPeriodBounds()
+	switch {
+	case period < min && clamp:
+		log.Infof("Report period %d ms clamped to %d ms", period, min)
+		return min, nil
+	case period < min:
+		log.Warnf("Report period %d ms is shorter than %d ms", period, min)
+		return 0, &e2apies.Cause{
+			Cause: &e2apies.Cause_RicRequest{
+				RicRequest: e2apies.CauseRic_CAUSE_RIC_FUNCTION_RESOURCE_LIMIT,
+			},
+		}
+	case max > 0 && period > max && clamp:
+		log.Infof("Report period %d ms clamped to %d ms", period, max)
+		return max, nil
+	case max > 0 && period > max:
+		log.Warnf("Report period %d ms is longer than %d ms", period, max)
+		return 0, &e2apies.Cause{
+			Cause: &e2apies.Cause_RicRequest{
+				RicRequest: e2apies.CauseRic_CAUSE_RIC_UNSPECIFIED,
+			},
+		}
+	}
+	return period, nil
+}
@@ -0,0 +1,54 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package registry
+
+import (
+	"testing"
+
+	e2apies "github.com/onosproject/onos-e2t/api/e2ap/v1beta2/e2ap-ies"
+	"github.com/onosproject/ran-simulator/pkg/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckReportPeriod(t *testing.T) {
+	sm := &ServiceModel{
+		RanFunctionID: Kpm2,
+		Model: &model.Model{
+			ServiceModels: map[string]model.ServiceModel{
+				"kpm2": {ID: int(Kpm2), MinReportPeriod: 100, MaxReportPeriod: 10000},
+			},
+		},
+	}
+
+	// Periods within the bounds are accepted as requested
+	period, cause := sm.CheckReportPeriod(1000)
+	assert.Nil(t, cause)
+	assert.Equal(t, int32(1000), period)
+
+	// Out-of-range periods are rejected
+	_, cause = sm.CheckReportPeriod(1)
+	assert.Equal(t, e2apies.CauseRic_CAUSE_RIC_FUNCTION_RESOURCE_LIMIT, cause.GetRicRequest())
+	_, cause = sm.CheckReportPeriod(60000)
+	assert.Equal(t, e2apies.CauseRic_CAUSE_RIC_UNSPECIFIED, cause.GetRicRequest())
+
+	// ...or clamped to the bounds if so configured
+	config := sm.Model.ServiceModels["kpm2"]
+	config.ClampReportPeriod = true
+	sm.Model.ServiceModels["kpm2"] = config
+	period, cause = sm.CheckReportPeriod(1)
+	assert.Nil(t, cause)
+	assert.Equal(t, int32(100), period)
+	period, cause = sm.CheckReportPeriod(60000)
+	assert.Nil(t, cause)
+	assert.Equal(t, int32(10000), period)
+
+	// Service models without configured bounds reject only periods below the default minimum
+	sm.RanFunctionID = Kpm
+	_, cause = sm.CheckReportPeriod(1)
+	assert.NotNil(t, cause)
+	period, cause = sm.CheckReportPeriod(60000)
+	assert.Nil(t, cause)
+	assert.Equal(t, int32(60000), period)
+}
@@ -0,0 +1,118 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package test provides test doubles for unit testing service models without an SCTP connection to E2T or model
+// plugins built as shared objects
+package test
+
+import (
+	"context"
+	"net"
+	"sync"
+
+	e2appducontents "github.com/onosproject/onos-e2t/api/e2ap/v1beta2/e2ap-pdu-contents"
+	e2 "github.com/onosproject/onos-e2t/pkg/protocols/e2ap101"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+)
+
+var _ e2.ClientChannel = &Channel{}
+
+// Channel is a fake E2 channel recording the E2 setup requests and the indications sent through it
+type Channel struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	mu            sync.RWMutex
+	setupRequests []*e2appducontents.E2SetupRequest
+	indications   []*e2appducontents.Ricindication
+	// changed is closed and replaced whenever an indication is recorded
+	changed chan struct{}
+}
+
+// NewChannel creates a new fake E2 channel
+func NewChannel() *Channel {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Channel{
+		ctx:     ctx,
+		cancel:  cancel,
+		changed: make(chan struct{}),
+	}
+}
+
+// Context returns the context of the channel, which is done once the channel is closed
+func (c *Channel) Context() context.Context {
+	return c.ctx
+}
+
+// Close closes the channel
+func (c *Channel) Close() error {
+	c.cancel()
+	return nil
+}
+
+// LocalAddr returns the local address of the channel
+func (c *Channel) LocalAddr() net.Addr {
+	return &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1)}
+}
+
+// RemoteAddr returns the remote address of the channel
+func (c *Channel) RemoteAddr() net.Addr {
+	return &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 36421}
+}
+
+// E2Setup records the setup request and responds with an empty response
+func (c *Channel) E2Setup(ctx context.Context, request *e2appducontents.E2SetupRequest) (*e2appducontents.E2SetupResponse, *e2appducontents.E2SetupFailure, error) {
+	if c.ctx.Err() != nil {
+		return nil, nil, errors.New(errors.Unavailable, "channel closed")
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.setupRequests = append(c.setupRequests, request)
+	return &e2appducontents.E2SetupResponse{}, nil, nil
+}
+
+// RICIndication records the indication
+func (c *Channel) RICIndication(ctx context.Context, request *e2appducontents.Ricindication) error {
+	if c.ctx.Err() != nil {
+		return errors.New(errors.Unavailable, "channel closed")
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.indications = append(c.indications, request)
+	close(c.changed)
+	c.changed = make(chan struct{})
+	return nil
+}
+
+// SetupRequests returns the E2 setup requests sent through the channel
+func (c *Channel) SetupRequests() []*e2appducontents.E2SetupRequest {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return append([]*e2appducontents.E2SetupRequest(nil), c.setupRequests...)
+}
+
+// Indications returns the indications sent through the channel
+func (c *Channel) Indications() []*e2appducontents.Ricindication {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return append([]*e2appducontents.Ricindication(nil), c.indications...)
+}
+
+// WaitForIndications waits until at least n indications were sent through the channel and returns them; a timeout
+// error is returned if the context is done first
+func (c *Channel) WaitForIndications(ctx context.Context, n int) ([]*e2appducontents.Ricindication, error) {
+	for {
+		c.mu.RLock()
+		indications, changed := c.indications, c.changed
+		c.mu.RUnlock()
+		if len(indications) >= n {
+			return append([]*e2appducontents.Ricindication(nil), indications...), nil
+		}
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			return nil, errors.New(errors.Timeout, "%d of %d indications received", len(indications), n)
+		}
+	}
+}
@@ -0,0 +1,53 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	e2appducontents "github.com/onosproject/onos-e2t/api/e2ap/v1beta2/e2ap-pdu-contents"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChannel(t *testing.T) {
+	channel := NewChannel()
+	go func() {
+		for i := 0; i < 3; i++ {
+			assert.NoError(t, channel.RICIndication(context.Background(), &e2appducontents.Ricindication{}))
+		}
+	}()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	indications, err := channel.WaitForIndications(ctx, 3)
+	assert.NoError(t, err)
+	assert.Len(t, indications, 3)
+
+	ctx, cancel = context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err = channel.WaitForIndications(ctx, 4)
+	assert.True(t, errors.IsTimeout(err))
+
+	assert.NoError(t, channel.Close())
+	assert.Error(t, channel.Context().Err())
+	assert.True(t, errors.IsUnavailable(channel.RICIndication(context.Background(), &e2appducontents.Ricindication{})))
+	assert.Len(t, channel.Indications(), 3)
+}
+
+func TestModelRegistry(t *testing.T) {
+	registry := NewModelRegistry(NewModelPlugin("ORAN-E2SM-KPM", "v2", "1.3.6.1.4.1.53148.1.2.2.2"))
+	plugin, err := registry.GetPlugin("1.3.6.1.4.1.53148.1.2.2.2")
+	assert.NoError(t, err)
+	bytes, err := plugin.IndicationMessageProtoToASN1([]byte{1, 2, 3})
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{1, 2, 3}, bytes)
+
+	_, err = registry.GetPlugin("1.3.6.1.4.1.53148.1.1.2.3")
+	assert.True(t, errors.IsNotFound(err))
+	_, _, err = registry.RegisterModelPlugin("e2sm_kpm_v2.so.1.0.0")
+	assert.True(t, errors.IsNotSupported(err))
+}
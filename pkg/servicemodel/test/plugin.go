@@ -0,0 +1,179 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package test
+
+import (
+	"sync"
+
+	e2smtypes "github.com/onosproject/onos-api/go/onos/e2t/e2sm"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/onosproject/ran-simulator/pkg/modelplugins"
+)
+
+var _ modelplugins.ServiceModel = &ModelPlugin{}
+
+// ModelPlugin is an in-memory model plugin whose conversions between the ASN.1 and protobuf encodings pass the bytes
+// through unchanged, so that the payloads exchanged by the service models are their marshalled protobuf messages
+type ModelPlugin struct {
+	Data e2smtypes.ServiceModelData
+}
+
+// NewModelPlugin creates a pass-through model plugin for the service model with the given name, version and OID
+func NewModelPlugin(name e2smtypes.ShortName, version e2smtypes.Version, oid e2smtypes.OID) *ModelPlugin {
+	return &ModelPlugin{
+		Data: e2smtypes.ServiceModelData{
+			Name:    name,
+			Version: version,
+			OID:     oid,
+		},
+	}
+}
+
+func passThrough(bytes []byte) ([]byte, error) {
+	return append([]byte(nil), bytes...), nil
+}
+
+// ServiceModelData returns the name, version and OID of the service model
+func (p *ModelPlugin) ServiceModelData() e2smtypes.ServiceModelData {
+	return p.Data
+}
+
+// IndicationHeaderASN1toProto returns the indication header unchanged
+func (p *ModelPlugin) IndicationHeaderASN1toProto(asn1Bytes []byte) ([]byte, error) {
+	return passThrough(asn1Bytes)
+}
+
+// IndicationHeaderProtoToASN1 returns the indication header unchanged
+func (p *ModelPlugin) IndicationHeaderProtoToASN1(protoBytes []byte) ([]byte, error) {
+	return passThrough(protoBytes)
+}
+
+// IndicationMessageASN1toProto returns the indication message unchanged
+func (p *ModelPlugin) IndicationMessageASN1toProto(asn1Bytes []byte) ([]byte, error) {
+	return passThrough(asn1Bytes)
+}
+
+// IndicationMessageProtoToASN1 returns the indication message unchanged
+func (p *ModelPlugin) IndicationMessageProtoToASN1(protoBytes []byte) ([]byte, error) {
+	return passThrough(protoBytes)
+}
+
+// RanFuncDescriptionASN1toProto returns the RAN function description unchanged
+func (p *ModelPlugin) RanFuncDescriptionASN1toProto(asn1Bytes []byte) ([]byte, error) {
+	return passThrough(asn1Bytes)
+}
+
+// RanFuncDescriptionProtoToASN1 returns the RAN function description unchanged
+func (p *ModelPlugin) RanFuncDescriptionProtoToASN1(protoBytes []byte) ([]byte, error) {
+	return passThrough(protoBytes)
+}
+
+// EventTriggerDefinitionASN1toProto returns the event trigger definition unchanged
+func (p *ModelPlugin) EventTriggerDefinitionASN1toProto(asn1Bytes []byte) ([]byte, error) {
+	return passThrough(asn1Bytes)
+}
+
+// EventTriggerDefinitionProtoToASN1 returns the event trigger definition unchanged
+func (p *ModelPlugin) EventTriggerDefinitionProtoToASN1(protoBytes []byte) ([]byte, error) {
+	return passThrough(protoBytes)
+}
+
+// ActionDefinitionASN1toProto returns the action definition unchanged
+func (p *ModelPlugin) ActionDefinitionASN1toProto(asn1Bytes []byte) ([]byte, error) {
+	return passThrough(asn1Bytes)
+}
+
+// ActionDefinitionProtoToASN1 returns the action definition unchanged
+func (p *ModelPlugin) ActionDefinitionProtoToASN1(protoBytes []byte) ([]byte, error) {
+	return passThrough(protoBytes)
+}
+
+// DecodeRanFunctionDescription is not supported, as the description is specific to the service model
+func (p *ModelPlugin) DecodeRanFunctionDescription(asn1bytes []byte) (*e2smtypes.RanfunctionNameDef, *e2smtypes.RicEventTriggerList, *e2smtypes.RicReportList, error) {
+	return nil, nil, nil, errors.New(errors.NotSupported, "RAN function descriptions are not decoded by pass-through plugins")
+}
+
+// ControlHeaderASN1toProto returns the control header unchanged
+func (p *ModelPlugin) ControlHeaderASN1toProto(asn1Bytes []byte) ([]byte, error) {
+	return passThrough(asn1Bytes)
+}
+
+// ControlHeaderProtoToASN1 returns the control header unchanged
+func (p *ModelPlugin) ControlHeaderProtoToASN1(protoBytes []byte) ([]byte, error) {
+	return passThrough(protoBytes)
+}
+
+// ControlMessageASN1toProto returns the control message unchanged
+func (p *ModelPlugin) ControlMessageASN1toProto(asn1Bytes []byte) ([]byte, error) {
+	return passThrough(asn1Bytes)
+}
+
+// ControlMessageProtoToASN1 returns the control message unchanged
+func (p *ModelPlugin) ControlMessageProtoToASN1(protoBytes []byte) ([]byte, error) {
+	return passThrough(protoBytes)
+}
+
+// ControlOutcomeASN1toProto returns the control outcome unchanged
+func (p *ModelPlugin) ControlOutcomeASN1toProto(asn1Bytes []byte) ([]byte, error) {
+	return passThrough(asn1Bytes)
+}
+
+// ControlOutcomeProtoToASN1 returns the control outcome unchanged
+func (p *ModelPlugin) ControlOutcomeProtoToASN1(protoBytes []byte) ([]byte, error) {
+	return passThrough(protoBytes)
+}
+
+var _ modelplugins.ModelRegistry = &ModelRegistry{}
+
+// ModelRegistry is an in-memory model registry of model plugins which are not loaded from shared objects
+type ModelRegistry struct {
+	mu      sync.RWMutex
+	plugins map[e2smtypes.OID]modelplugins.ServiceModel
+}
+
+// NewModelRegistry creates a model registry holding the given plugins
+func NewModelRegistry(plugins ...modelplugins.ServiceModel) *ModelRegistry {
+	r := &ModelRegistry{
+		plugins: make(map[e2smtypes.OID]modelplugins.ServiceModel),
+	}
+	for _, plugin := range plugins {
+		r.Add(plugin)
+	}
+	return r
+}
+
+// Add adds the plugin to the registry, replacing any plugin with the same OID
+func (r *ModelRegistry) Add(plugin modelplugins.ServiceModel) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.plugins[plugin.ServiceModelData().OID] = plugin
+}
+
+// GetPlugins returns the plugins by OID
+func (r *ModelRegistry) GetPlugins() map[e2smtypes.OID]modelplugins.ServiceModel {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	plugins := make(map[e2smtypes.OID]modelplugins.ServiceModel, len(r.plugins))
+	for oid, plugin := range r.plugins {
+		plugins[oid] = plugin
+	}
+	return plugins
+}
+
+// GetPlugin returns the plugin with the given OID
+func (r *ModelRegistry) GetPlugin(oid e2smtypes.OID) (modelplugins.ServiceModel, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	plugin, ok := r.plugins[oid]
+	if !ok {
+		return nil, errors.NewNotFound("Model plugin '%s' not found", oid)
+	}
+	return plugin, nil
+}
+
+// RegisterModelPlugin is not supported, as the plugins of the registry are not loaded from shared objects
+func (r *ModelRegistry) RegisterModelPlugin(moduleName string) (e2smtypes.ShortName, e2smtypes.Version, error) {
+	return "", "", errors.New(errors.NotSupported, "model plugin %s cannot be loaded by an in-memory registry", moduleName)
+}
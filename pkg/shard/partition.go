@@ -0,0 +1,197 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package shard
+
+import (
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/onosproject/onos-api/go/onos/ransim/types"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	liblog "github.com/onosproject/onos-lib-go/pkg/logging"
+	"github.com/onosproject/ran-simulator/pkg/model"
+)
+
+var log = liblog.GetLogger("shard")
+
+// Strategies for assigning the nodes of the model to the simulator instances
+const (
+	// StrategyIndex assigns the nodes, ordered by their E2 node ID, to the instances in a round-robin fashion
+	StrategyIndex = "index"
+	// StrategyHash assigns the nodes to the instances by the hash of their E2 node ID
+	StrategyHash = "hash"
+)
+
+// Config configures the partitioning of a model shared by several simulator instances
+type Config struct {
+	// Index is the index of this instance, from zero to Count-1
+	Index int
+	// Count is the number of instances; sharding is disabled for fewer than two instances
+	Count int
+	// Strategy selects how nodes are assigned to instances unless Nodes are given explicitly
+	Strategy string
+	// Nodes explicitly lists the nodes owned by this instance
+	Nodes []types.EnbID
+	// Peers are the northbound addresses of all instances ordered by their index; a single address containing
+	// "%d" is expanded for every index, e.g. "ran-simulator-%d.ran-simulator:5150"
+	Peers []string
+}
+
+// Enabled returns true if the model is partitioned among several instances
+func (c Config) Enabled() bool {
+	return c.Count > 1 || len(c.Nodes) > 0
+}
+
+// IndexFromHostname returns the ordinal of a Kubernetes stateful set pod from its hostname, e.g. 2 for "ran-simulator-2"
+func IndexFromHostname(hostname string) (int, error) {
+	i := strings.LastIndex(hostname, "-")
+	if i < 0 {
+		return 0, errors.New(errors.Invalid, "hostname %s has no ordinal suffix", hostname)
+	}
+	index, err := strconv.Atoi(hostname[i+1:])
+	if err != nil || index < 0 {
+		return 0, errors.New(errors.Invalid, "hostname %s has no ordinal suffix", hostname)
+	}
+	return index, nil
+}
+
+// Partition is the share of the model simulated by this instance
+type Partition struct {
+	config Config
+	peers  []string
+	// owners maps the nodes of the model to the index of the owning instance; -1 if unknown
+	owners map[types.EnbID]int
+	// cellNodes maps the cells of the model to their node
+	cellNodes map[types.ECGI]types.EnbID
+	// remoteCells holds the cells of the model simulated by other instances, kept when applying the partition
+	remoteCells map[types.ECGI]*model.Cell
+}
+
+// NewPartition assigns the nodes of the model to the simulator instances according to the configuration
+func NewPartition(config Config, m *model.Model) (*Partition, error) {
+	if config.Strategy == "" {
+		config.Strategy = StrategyIndex
+	}
+	if config.Strategy != StrategyIndex && config.Strategy != StrategyHash {
+		return nil, errors.New(errors.Invalid, "unknown sharding strategy %s", config.Strategy)
+	}
+	if len(config.Nodes) == 0 && (config.Index < 0 || config.Index >= config.Count) {
+		return nil, errors.New(errors.Invalid, "shard index %d out of range for %d shards", config.Index, config.Count)
+	}
+
+	p := &Partition{
+		config:      config,
+		peers:       expandPeers(config.Peers, config.Count),
+		owners:      make(map[types.EnbID]int),
+		cellNodes:   make(map[types.ECGI]types.EnbID),
+		remoteCells: make(map[types.ECGI]*model.Cell),
+	}
+	enbIDs := make([]types.EnbID, 0, len(m.Nodes))
+	for _, node := range m.Nodes {
+		enbIDs = append(enbIDs, node.EnbID)
+		for _, ecgi := range node.Cells {
+			p.cellNodes[ecgi] = node.EnbID
+		}
+	}
+	sort.Slice(enbIDs, func(i, j int) bool {
+		return enbIDs[i] < enbIDs[j]
+	})
+
+	if len(config.Nodes) > 0 {
+		// Owners of the nodes not listed are unknown; handoffs to them are offered to all peers
+		for _, enbID := range enbIDs {
+			p.owners[enbID] = -1
+		}
+		for _, enbID := range config.Nodes {
+			if _, ok := p.owners[enbID]; !ok {
+				return nil, errors.New(errors.NotFound, "node %d not found in the model", enbID)
+			}
+			p.owners[enbID] = config.Index
+		}
+		return p, nil
+	}
+	for i, enbID := range enbIDs {
+		if config.Strategy == StrategyHash {
+			h := fnv.New32a()
+			_, _ = h.Write([]byte(strconv.FormatUint(uint64(enbID), 10)))
+			p.owners[enbID] = int(h.Sum32() % uint32(config.Count))
+		} else {
+			p.owners[enbID] = i % config.Count
+		}
+	}
+	return p, nil
+}
+
+func expandPeers(peers []string, count int) []string {
+	if len(peers) != 1 || !strings.Contains(peers[0], "%d") {
+		return peers
+	}
+	expanded := make([]string, 0, count)
+	for i := 0; i < count; i++ {
+		expanded = append(expanded, fmt.Sprintf(peers[0], i))
+	}
+	return expanded
+}
+
+// Owns returns true if the specified node is simulated by this instance
+func (p *Partition) Owns(enbID types.EnbID) bool {
+	return p.owners[enbID] == p.config.Index
+}
+
+// IsRemote returns true if the specified cell is part of the model but simulated by another instance
+func (p *Partition) IsRemote(ecgi types.ECGI) bool {
+	enbID, ok := p.cellNodes[ecgi]
+	return ok && !p.Owns(enbID)
+}
+
+// RemoteCell returns the model of the specified cell if it is simulated by another instance, allowing the UEs of
+// this instance to measure it
+func (p *Partition) RemoteCell(ecgi types.ECGI) (*model.Cell, bool) {
+	cell, ok := p.remoteCells[ecgi]
+	return cell, ok
+}
+
+// Peers returns the northbound addresses of the instances that may simulate the specified cell
+func (p *Partition) Peers(ecgi types.ECGI) []string {
+	if owner, ok := p.owners[p.cellNodes[ecgi]]; ok && owner >= 0 {
+		if owner < len(p.peers) {
+			return []string{p.peers[owner]}
+		}
+		return nil
+	}
+	peers := make([]string, 0, len(p.peers))
+	for i, peer := range p.peers {
+		if i != p.config.Index {
+			peers = append(peers, peer)
+		}
+	}
+	return peers
+}
+
+// Apply restricts the model to the nodes owned by this instance and their cells, keeping the cells of the other
+// instances as remote cells; the number of UEs is reduced in proportion to the share of the cells simulated by
+// this instance
+func (p *Partition) Apply(m *model.Model) {
+	totalCells := len(m.Cells)
+	for name, node := range m.Nodes {
+		if !p.Owns(node.EnbID) {
+			delete(m.Nodes, name)
+		}
+	}
+	for name, cell := range m.Cells {
+		if p.IsRemote(cell.ECGI) {
+			remote := cell
+			p.remoteCells[cell.ECGI] = &remote
+			delete(m.Cells, name)
+		}
+	}
+	if totalCells > 0 {
+		m.UECount = m.UECount * uint(len(m.Cells)) / uint(totalCells)
+	}
+	log.Infof("Simulating %d nodes, %d cells and %d UEs as shard %d", len(m.Nodes), len(m.Cells), m.UECount, p.config.Index)
+}
@@ -0,0 +1,143 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package shard
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/onosproject/onos-api/go/onos/ransim/types"
+	"github.com/onosproject/ran-simulator/pkg/mobility"
+	"github.com/onosproject/ran-simulator/pkg/model"
+	"github.com/onosproject/ran-simulator/pkg/store/cells"
+	"github.com/onosproject/ran-simulator/pkg/store/metrics"
+	"github.com/onosproject/ran-simulator/pkg/store/nodes"
+	"github.com/onosproject/ran-simulator/pkg/store/ues"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func loadModel(t *testing.T) *model.Model {
+	m := &model.Model{}
+	assert.NoError(t, model.LoadConfig(m, "../model/test"))
+	return m
+}
+
+func TestPartition(t *testing.T) {
+	m := loadModel(t)
+	_, err := NewPartition(Config{Index: 2, Count: 2}, m)
+	assert.Error(t, err)
+	_, err = NewPartition(Config{Count: 2, Strategy: "random"}, m)
+	assert.Error(t, err)
+
+	// Nodes are assigned round-robin by their ID
+	partition, err := NewPartition(Config{Index: 1, Count: 2, Peers: []string{"ransim-%d:5150"}}, m)
+	assert.NoError(t, err)
+	assert.False(t, partition.Owns(144470))
+	assert.True(t, partition.Owns(144471))
+	assert.True(t, partition.IsRemote(84325717505))
+	assert.False(t, partition.IsRemote(84325717761))
+	assert.False(t, partition.IsRemote(1))
+	assert.Equal(t, []string{"ransim-0:5150"}, partition.Peers(84325717505))
+
+	m.UECount = 10
+	partition.Apply(m)
+	assert.Len(t, m.Nodes, 1)
+	assert.Len(t, m.Cells, 2)
+	assert.Equal(t, uint(5), m.UECount)
+	// The cells of the other instance are kept as remote cells
+	remote, ok := partition.RemoteCell(84325717505)
+	assert.True(t, ok)
+	assert.Equal(t, types.ECGI(84325717505), remote.ECGI)
+	_, ok = partition.RemoteCell(84325717761)
+	assert.False(t, ok)
+
+	// Hashing assigns every node to exactly one instance
+	owned := 0
+	for i := 0; i < 3; i++ {
+		partition, err = NewPartition(Config{Index: i, Count: 3, Strategy: StrategyHash}, loadModel(t))
+		assert.NoError(t, err)
+		for _, enbID := range []types.EnbID{144470, 144471} {
+			if partition.Owns(enbID) {
+				owned++
+			}
+		}
+	}
+	assert.Equal(t, 2, owned)
+
+	// Handoffs to nodes of unknown owners are offered to all other instances
+	_, err = NewPartition(Config{Nodes: []types.EnbID{1}}, loadModel(t))
+	assert.Error(t, err)
+	partition, err = NewPartition(Config{Nodes: []types.EnbID{144470}, Peers: []string{"a:5150", "b:5150", "c:5150"}}, loadModel(t))
+	assert.NoError(t, err)
+	assert.True(t, partition.Owns(144470))
+	assert.False(t, partition.Owns(144471))
+	assert.Equal(t, []string{"b:5150", "c:5150"}, partition.Peers(84325717761))
+}
+
+func TestIndexFromHostname(t *testing.T) {
+	index, err := IndexFromHostname("ran-simulator-12")
+	assert.NoError(t, err)
+	assert.Equal(t, 12, index)
+	_, err = IndexFromHostname("ran-simulator")
+	assert.Error(t, err)
+	_, err = IndexFromHostname("ransim")
+	assert.Error(t, err)
+}
+
+// newShard creates the stores of the given shard of the test model
+func newShard(t *testing.T, index int) (*Partition, cells.Store, ues.Store) {
+	m := loadModel(t)
+	partition, err := NewPartition(Config{Index: index, Count: 2, Peers: []string{"shard-%d"}}, m)
+	assert.NoError(t, err)
+	partition.Apply(m)
+	cellStore := cells.NewCellRegistry(m.Cells, nodes.NewNodeRegistry(m.Nodes))
+	return partition, cellStore, ues.NewUERegistry(2, cellStore)
+}
+
+func TestTransfer(t *testing.T) {
+	ctx := context.Background()
+	partition, cellStore, ueStore := newShard(t, 0)
+	_, remoteCells, remoteUEs := newShard(t, 1)
+
+	lis := bufconn.Listen(1024 * 1024)
+	server := grpc.NewServer()
+	NewService(remoteCells, remoteUEs).Register(server)
+	go func() {
+		_ = server.Serve(lis)
+	}()
+	defer server.Stop()
+	transferrer := newTransferrer(partition, grpc.WithInsecure(), grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) {
+		return lis.Dial()
+	}))
+	defer transferrer.Close()
+
+	handover := mobility.NewHandoverEngine(cellStore, ueStore, metrics.NewMetricsStore())
+	handover.SetTransferrer(transferrer)
+	ue := ueStore.ListAllUEs(ctx)[0]
+
+	// UE handed over to a cell of the other instance moves to that instance
+	assert.NoError(t, handover.HandoverUE(ctx, ue.IMSI, 84325717761))
+	_, err := ueStore.Get(ctx, ue.IMSI)
+	assert.Error(t, err)
+	transferred, err := remoteUEs.Get(ctx, ue.IMSI)
+	assert.NoError(t, err)
+	assert.Equal(t, types.ECGI(84325717761), transferred.Cell.ECGI)
+	assert.Equal(t, model.RrcConnected, transferred.RrcState)
+	assert.Equal(t, 3, remoteUEs.Len(ctx))
+
+	// Handoffs rejected by the other instance leave the UE in place
+	cell, err := remoteCells.Get(ctx, 84325717762)
+	assert.NoError(t, err)
+	locked := *cell
+	locked.Locked = true
+	assert.NoError(t, remoteCells.Update(ctx, &locked))
+	ue = ueStore.ListAllUEs(ctx)[0]
+	assert.Error(t, handover.HandoverUE(ctx, ue.IMSI, 84325717762))
+	_, err = ueStore.Get(ctx, ue.IMSI)
+	assert.NoError(t, err)
+}
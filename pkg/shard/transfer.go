@@ -0,0 +1,228 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package shard
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+
+	"github.com/onosproject/onos-api/go/onos/ransim/types"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	service "github.com/onosproject/onos-lib-go/pkg/northbound"
+	"github.com/onosproject/onos-ric-sdk-go/pkg/e2/creds"
+	"github.com/onosproject/ran-simulator/pkg/journal"
+	"github.com/onosproject/ran-simulator/pkg/model"
+	"github.com/onosproject/ran-simulator/pkg/store/cells"
+	"github.com/onosproject/ran-simulator/pkg/store/ues"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/encoding"
+)
+
+// The handoff of UEs between instances is a plain gRPC service whose messages are encoded as JSON,
+// as the simulator does not own any protobuf definitions of its own
+const (
+	codecName      = "json"
+	serviceName    = "onos.ransim.shard.Shard"
+	transferMethod = "TransferUE"
+)
+
+func init() {
+	encoding.RegisterCodec(jsonCodec{})
+}
+
+type jsonCodec struct{}
+
+func (jsonCodec) Marshal(v interface{}) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (jsonCodec) Unmarshal(data []byte, v interface{}) error {
+	return json.Unmarshal(data, v)
+}
+
+func (jsonCodec) Name() string {
+	return codecName
+}
+
+// TransferRequest hands a UE over to a cell simulated by the receiving instance; the serving cell of the UE is the target cell
+type TransferRequest struct {
+	UE *model.UE `json:"ue"`
+}
+
+// TransferResponse acknowledges the handoff of a UE
+type TransferResponse struct{}
+
+// shardServer is the handler type of the shard gRPC service
+type shardServer interface {
+	TransferUE(ctx context.Context, request *TransferRequest) (*TransferResponse, error)
+}
+
+var serviceDesc = grpc.ServiceDesc{
+	ServiceName: serviceName,
+	HandlerType: (*shardServer)(nil),
+	Methods: []grpc.MethodDesc{{
+		MethodName: transferMethod,
+		Handler:    transferHandler,
+	}},
+	Metadata: "shard",
+}
+
+func transferHandler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	request := &TransferRequest{}
+	if err := dec(request); err != nil {
+		return nil, err
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(shardServer).TransferUE(ctx, req.(*TransferRequest))
+	}
+	if interceptor == nil {
+		return handler(ctx, request)
+	}
+	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: "/" + serviceName + "/" + transferMethod}
+	return interceptor(ctx, request, info, handler)
+}
+
+// NewService returns a new shard Service accepting the UEs handed over by other instances
+func NewService(cellStore cells.Store, ueStore ues.Store) service.Service {
+	return &Service{
+		cellStore: cellStore,
+		ueStore:   ueStore,
+	}
+}
+
+// Service is a Service implementation for the handoff of UEs between instances
+type Service struct {
+	service.Service
+	cellStore cells.Store
+	ueStore   ues.Store
+}
+
+// Register registers the shard Service with the gRPC server.
+func (s *Service) Register(r *grpc.Server) {
+	r.RegisterService(&serviceDesc, &Server{
+		cellStore: s.cellStore,
+		ueStore:   s.ueStore,
+	})
+}
+
+// Server accepts the UEs handed over by other instances
+type Server struct {
+	cellStore cells.Store
+	ueStore   ues.Store
+}
+
+// TransferUE admits the UE to the target cell, provided the cell is simulated by this instance, available,
+// admits the UE and has capacity left
+func (s *Server) TransferUE(ctx context.Context, request *TransferRequest) (*TransferResponse, error) {
+	ue := request.UE
+	if ue == nil || ue.Cell == nil {
+		return nil, errors.Status(errors.New(errors.Invalid, "UE and target cell are required")).Err()
+	}
+	cell, err := s.cellStore.Get(ctx, ue.Cell.ECGI)
+	if err != nil {
+		return nil, errors.Status(err).Err()
+	}
+	if !cell.IsAvailable() {
+		return nil, errors.Status(errors.New(errors.Forbidden, "cell %d is locked or barred", cell.ECGI)).Err()
+	}
+	if !cell.Admits(ue.IMSI) {
+		return nil, errors.Status(errors.New(errors.Forbidden, "UE %d is not a member of the closed subscriber group of cell %d", ue.IMSI, cell.ECGI)).Err()
+	}
+	if cell.MaxUEs > 0 && len(s.ueStore.ListUEs(ctx, cell.ECGI)) >= int(cell.MaxUEs) {
+		return nil, errors.Status(errors.New(errors.Forbidden, "cell %d has no capacity left", cell.ECGI)).Err()
+	}
+	if err := s.ueStore.Add(ctx, ue); err != nil {
+		return nil, errors.Status(err).Err()
+	}
+	log.Infof("Accepted UE %d handed over to cell %d", ue.IMSI, cell.ECGI)
+	return &TransferResponse{}, nil
+}
+
+// Transferrer hands UEs over to the cells simulated by other instances
+type Transferrer struct {
+	partition *Partition
+	mu        sync.Mutex
+	conns     map[string]*grpc.ClientConn
+	dialOpts  []grpc.DialOption
+}
+
+// NewTransferrer creates a new transferrer handing UEs over to the instances of the partitioned model, dialing the
+// instances with the given additional options, e.g. their credentials
+func NewTransferrer(partition *Partition, dialOpts ...grpc.DialOption) (*Transferrer, error) {
+	tlsConfig, err := creds.GetClientCredentials()
+	if err != nil {
+		return nil, err
+	}
+	return newTransferrer(partition, append([]grpc.DialOption{grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig))}, dialOpts...)...), nil
+}
+
+func newTransferrer(partition *Partition, dialOpts ...grpc.DialOption) *Transferrer {
+	return &Transferrer{
+		partition: partition,
+		conns:     make(map[string]*grpc.ClientConn),
+		dialOpts:  dialOpts,
+	}
+}
+
+// IsRemote returns true if the specified cell is simulated by another instance
+func (t *Transferrer) IsRemote(ecgi types.ECGI) bool {
+	return t.partition.IsRemote(ecgi)
+}
+
+// Transfer hands the UE over to the instance simulating its serving cell; instances not simulating the cell
+// are skipped when the owner of the cell is not known
+func (t *Transferrer) Transfer(ctx context.Context, ue *model.UE) error {
+	peers := t.partition.Peers(ue.Cell.ECGI)
+	if len(peers) == 0 {
+		return errors.New(errors.Unavailable, "no peer known for cell %d", ue.Cell.ECGI)
+	}
+	var err error
+	for _, peer := range peers {
+		if err = t.transfer(ctx, peer, ue); err == nil {
+			journal.Record(journal.UETransferred, uint64(ue.IMSI), map[string]interface{}{"ecgi": ue.Cell.ECGI, "peer": peer})
+			return nil
+		}
+		if !errors.IsNotFound(err) {
+			return err
+		}
+	}
+	return err
+}
+
+func (t *Transferrer) transfer(ctx context.Context, peer string, ue *model.UE) error {
+	conn, err := t.connection(ctx, peer)
+	if err != nil {
+		return err
+	}
+	err = conn.Invoke(ctx, "/"+serviceName+"/"+transferMethod, &TransferRequest{UE: ue}, &TransferResponse{},
+		grpc.CallContentSubtype(codecName))
+	return errors.FromGRPC(err)
+}
+
+func (t *Transferrer) connection(ctx context.Context, peer string) (*grpc.ClientConn, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if conn, ok := t.conns[peer]; ok {
+		return conn, nil
+	}
+	conn, err := grpc.DialContext(ctx, peer, t.dialOpts...)
+	if err != nil {
+		return nil, err
+	}
+	t.conns[peer] = conn
+	return conn, nil
+}
+
+// Close closes the connections to the other instances
+func (t *Transferrer) Close() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for peer, conn := range t.conns {
+		_ = conn.Close()
+		delete(t.conns, peer)
+	}
+}
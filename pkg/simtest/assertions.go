@@ -0,0 +1,50 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package simtest
+
+import (
+	"context"
+	"time"
+
+	"github.com/onosproject/onos-api/go/onos/ransim/types"
+	e2appducontents "github.com/onosproject/onos-e2t/api/e2ap/v1beta2/e2ap-pdu-contents"
+	"github.com/onosproject/ran-simulator/pkg/journal"
+	"github.com/stretchr/testify/require"
+)
+
+// ExpectIndicationWithin expects the node to send an indication within the given time and returns it; each call
+// returns the next indication of the node, i.e. the first one not returned by a previous call
+func (s *Simulation) ExpectIndicationWithin(enbID types.EnbID, timeout time.Duration) *e2appducontents.Ricindication {
+	node := s.Node(enbID)
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	next := s.consumed[enbID]
+	indications, err := node.WaitForIndications(ctx, next+1)
+	require.NoError(s.t, err, "no indication from E2 node %d within %v", enbID, timeout)
+	s.consumed[enbID] = next + 1
+	return indications[next]
+}
+
+// ExpectHandover expects the UE to be handed over within the given time, or any UE if the IMSI is zero, and returns
+// the journal entry of the handover; handovers completed since the simulation started are taken into account
+func (s *Simulation) ExpectHandover(imsi types.IMSI, timeout time.Duration) *journal.Entry {
+	filter := journal.Filter{
+		Since:    s.since,
+		Kinds:    []journal.Kind{journal.HandoverCompleted},
+		EntityID: uint64(imsi),
+		Limit:    1,
+	}
+	deadline := time.Now().Add(timeout)
+	for {
+		if entries := journal.Default().Query(filter); len(entries) > 0 {
+			return entries[0]
+		}
+		if time.Now().After(deadline) {
+			require.Failf(s.t, "no handover", "UE %d was not handed over within %v", imsi, timeout)
+			return nil
+		}
+		time.Sleep(journalPollInterval)
+	}
+}
@@ -0,0 +1,218 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package simtest
+
+import (
+	"context"
+	"net"
+	"sync"
+
+	e2smtypes "github.com/onosproject/onos-api/go/onos/e2t/e2sm"
+	"github.com/onosproject/onos-api/go/onos/ransim/types"
+	e2appducontents "github.com/onosproject/onos-e2t/api/e2ap/v1beta2/e2ap-pdu-contents"
+	"github.com/onosproject/onos-e2t/pkg/protocols/e2ap101/channels"
+	"github.com/onosproject/onos-e2t/pkg/protocols/e2ap101/procedures"
+	"github.com/onosproject/onos-e2t/pkg/southbound/e2ap101/pdubuilder"
+	"github.com/onosproject/onos-e2t/pkg/southbound/e2ap101/pdudecoder"
+	e2aptypes "github.com/onosproject/onos-e2t/pkg/southbound/e2ap101/types"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+)
+
+// ricID is the identifier of the RIC the E2T stub responds to the E2 setup requests with
+var ricID = e2aptypes.RicIdentifier{
+	RicIdentifierValue: 0xABCDE,
+	RicIdentifierLen:   20,
+}
+
+// Subscription is a subscription installed through the E2T stub
+type Subscription struct {
+	// RanFunctionID is the ID of the RAN function the subscription is for
+	RanFunctionID e2aptypes.RanFunctionID
+	// EventTrigger is the event trigger definition, i.e. the marshalled protobuf message with pass-through plugins
+	EventTrigger []byte
+	// Actions are the actions to be set up
+	Actions []e2aptypes.RicActionDef
+}
+
+// E2T is a lightweight E2T stub terminating the E2 connections of the nodes in-process, over pipes instead of SCTP
+// associations; it accepts all RAN functions of the nodes and records the indications they send
+type E2T struct {
+	mu        sync.RWMutex
+	nodes     map[types.EnbID]*Node
+	requestID int32
+}
+
+// NewE2T creates a new E2T stub
+func NewE2T() *E2T {
+	return &E2T{
+		nodes: make(map[types.EnbID]*Node),
+	}
+}
+
+// Dial connects the E2 node to the E2T stub regardless of the address of its controller; it is the dialer of the E2
+// agents, see e2agent.SetDialer
+func (e *E2T) Dial(ctx context.Context, enbID types.EnbID, address string) (net.Conn, error) {
+	node := e.node(enbID)
+	nodeConn, e2tConn := net.Pipe()
+	channels.NewRICChannel(e2tConn, func(channel channels.RICChannel) procedures.RICProcedures {
+		node.connect(channel)
+		return node
+	})
+	return nodeConn, nil
+}
+
+// node returns the node with the given ID, creating it on its first connection
+func (e *E2T) node(enbID types.EnbID) *Node {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	node, ok := e.nodes[enbID]
+	if !ok {
+		node = &Node{
+			EnbID:   enbID,
+			changed: make(chan struct{}),
+		}
+		e.nodes[enbID] = node
+	}
+	return node
+}
+
+// Node returns the node with the given ID if it ever connected to the E2T stub
+func (e *E2T) Node(enbID types.EnbID) (*Node, error) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	node, ok := e.nodes[enbID]
+	if !ok {
+		return nil, errors.New(errors.NotFound, "E2 node %d never connected", enbID)
+	}
+	return node, nil
+}
+
+// Subscribe installs the subscription on the node, allocating its RIC request ID
+func (e *E2T) Subscribe(ctx context.Context, enbID types.EnbID, sub Subscription) (*e2appducontents.RicsubscriptionResponse, *e2appducontents.RicsubscriptionFailure, error) {
+	node, err := e.Node(enbID)
+	if err != nil {
+		return nil, nil, err
+	}
+	e.mu.Lock()
+	e.requestID++
+	ricRequest := e2aptypes.RicRequest{
+		RequestorID: e2aptypes.RicRequestorID(e.requestID),
+		InstanceID:  1,
+	}
+	e.mu.Unlock()
+
+	actions := make(map[e2aptypes.RicActionID]e2aptypes.RicActionDef, len(sub.Actions))
+	for _, action := range sub.Actions {
+		actions[action.RicActionID] = action
+	}
+	request, err := pdubuilder.NewRicSubscriptionRequest(ricRequest, sub.RanFunctionID, sub.EventTrigger, actions)
+	if err != nil {
+		return nil, nil, err
+	}
+	channel, err := node.Channel()
+	if err != nil {
+		return nil, nil, err
+	}
+	return channel.RICSubscription(ctx, request)
+}
+
+var _ procedures.RICProcedures = &Node{}
+
+// Node is an E2 node connected to the E2T stub
+type Node struct {
+	EnbID types.EnbID
+
+	mu           sync.RWMutex
+	channel      channels.RICChannel
+	ranFunctions e2aptypes.RanFunctions
+	indications  []*e2appducontents.Ricindication
+	// changed is closed and replaced whenever an indication is recorded
+	changed chan struct{}
+}
+
+// connect replaces the channel of the node on a new connection
+func (n *Node) connect(channel channels.RICChannel) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.channel = channel
+}
+
+// Channel returns the channel of the current connection of the node, once set up
+func (n *Node) Channel() (channels.RICChannel, error) {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+	if n.channel == nil || n.ranFunctions == nil {
+		return nil, errors.New(errors.Unavailable, "E2 node %d is not set up", n.EnbID)
+	}
+	return n.channel, nil
+}
+
+// RanFunctionID returns the ID of the RAN function of the service model with the given OID the node advertised in its
+// last E2 setup
+func (n *Node) RanFunctionID(oid e2smtypes.OID) (e2aptypes.RanFunctionID, error) {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+	for id, ranFunction := range n.ranFunctions {
+		if string(ranFunction.OID) == string(oid) {
+			return id, nil
+		}
+	}
+	return 0, errors.New(errors.NotFound, "E2 node %d does not advertise service model %s", n.EnbID, oid)
+}
+
+// Indications returns the indications the node sent
+func (n *Node) Indications() []*e2appducontents.Ricindication {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+	return append([]*e2appducontents.Ricindication(nil), n.indications...)
+}
+
+// WaitForIndications waits until the node sent at least count indications and returns them; a timeout error is
+// returned if the context is done first
+func (n *Node) WaitForIndications(ctx context.Context, count int) ([]*e2appducontents.Ricindication, error) {
+	for {
+		n.mu.RLock()
+		indications, changed := n.indications, n.changed
+		n.mu.RUnlock()
+		if len(indications) >= count {
+			return append([]*e2appducontents.Ricindication(nil), indications...), nil
+		}
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			return nil, errors.New(errors.Timeout, "E2 node %d sent %d of %d indications", n.EnbID, len(indications), count)
+		}
+	}
+}
+
+// E2Setup accepts all RAN functions of the node
+func (n *Node) E2Setup(ctx context.Context, request *e2appducontents.E2SetupRequest) (*e2appducontents.E2SetupResponse, *e2appducontents.E2SetupFailure, error) {
+	nodeID, ranFunctions, err := pdudecoder.DecodeE2SetupRequest(request)
+	if err != nil {
+		return nil, nil, err
+	}
+	accepted := make(e2aptypes.RanFunctionRevisions)
+	for id, ranFunction := range *ranFunctions {
+		accepted[id] = ranFunction.Revision
+	}
+	response, err := pdubuilder.NewE2SetupResponse(nodeID.Plmn, ricID, accepted, make(e2aptypes.RanFunctionCauses))
+	if err != nil {
+		return nil, nil, err
+	}
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.ranFunctions = *ranFunctions
+	return response, nil, nil
+}
+
+// RICIndication records the indication
+func (n *Node) RICIndication(ctx context.Context, request *e2appducontents.Ricindication) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.indications = append(n.indications, request)
+	close(n.changed)
+	n.changed = make(chan struct{})
+	return nil
+}
@@ -0,0 +1,181 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package simtest runs the simulator in-process for end-to-end tests of service models: the nodes of a model connect to
+// a lightweight E2T stub, the tests install subscriptions through it and assert on the indications and the journal
+package simtest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	e2smtypes "github.com/onosproject/onos-api/go/onos/e2t/e2sm"
+	"github.com/onosproject/onos-api/go/onos/ransim/types"
+	e2appducontents "github.com/onosproject/onos-e2t/api/e2ap/v1beta2/e2ap-pdu-contents"
+	e2aptypes "github.com/onosproject/onos-e2t/pkg/southbound/e2ap101/types"
+	"github.com/onosproject/ran-simulator/pkg/e2agent"
+	"github.com/onosproject/ran-simulator/pkg/journal"
+	"github.com/onosproject/ran-simulator/pkg/manager"
+	"github.com/onosproject/ran-simulator/pkg/modelplugins"
+	smtest "github.com/onosproject/ran-simulator/pkg/servicemodel/test"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	// defaultConnectTimeout bounds the time the nodes take to connect to the E2T stub
+	defaultConnectTimeout = 30 * time.Second
+	// journalPollInterval is the interval the journal is checked at while expecting a milestone
+	journalPollInterval = 50 * time.Millisecond
+)
+
+// OIDs of the service models of the simulator
+const (
+	KpmOID  e2smtypes.OID = "1.3.6.1.4.1.53148.1.1.2.2"
+	Kpm2OID e2smtypes.OID = "1.3.6.1.4.1.53148.1.2.2.2"
+	RcOID   e2smtypes.OID = "1.3.6.1.4.1.53148.1.1.2.100"
+)
+
+// DefaultModelPlugins returns pass-through model plugins of the service models of the simulator
+func DefaultModelPlugins() []modelplugins.ServiceModel {
+	return []modelplugins.ServiceModel{
+		smtest.NewModelPlugin("e2sm_kpm", "v1", KpmOID),
+		smtest.NewModelPlugin("e2sm_kpm_v2", "v2", Kpm2OID),
+		smtest.NewModelPlugin("e2sm_rc_pre", "v1", RcOID),
+	}
+}
+
+type options struct {
+	plugins        []modelplugins.ServiceModel
+	connectTimeout time.Duration
+	configure      func(config *manager.Config)
+}
+
+// Option configures a simulation
+type Option func(options *options)
+
+// WithModelPlugins sets the model plugins of the service models instead of the default pass-through plugins
+func WithModelPlugins(plugins ...modelplugins.ServiceModel) Option {
+	return func(options *options) {
+		options.plugins = plugins
+	}
+}
+
+// WithConnectTimeout sets the time the nodes are given to connect to the E2T stub
+func WithConnectTimeout(timeout time.Duration) Option {
+	return func(options *options) {
+		options.connectTimeout = timeout
+	}
+}
+
+// WithManagerConfig adjusts the configuration of the manager running the simulation
+func WithManagerConfig(configure func(config *manager.Config)) Option {
+	return func(options *options) {
+		options.configure = configure
+	}
+}
+
+// Simulation is a simulation running in-process, its nodes connected to an E2T stub. The nodes dial the stub through
+// the process-wide dialer of the E2 agents, so only one simulation may run at a time.
+type Simulation struct {
+	t       *testing.T
+	manager *manager.Manager
+	e2t     *E2T
+	// since is the sequence number of the last journal entry recorded before the simulation started
+	since uint64
+	// consumed counts the indications of each node returned by ExpectIndicationWithin
+	consumed map[types.EnbID]int
+}
+
+// Start loads the named model, e.g. a path relative to the test package such as "../model/test", runs the simulator
+// in-process and waits for all nodes to connect to the E2T stub; the simulation is stopped once the test completes
+func Start(t *testing.T, modelName string, opts ...Option) *Simulation {
+	options := &options{
+		plugins:        DefaultModelPlugins(),
+		connectTimeout: defaultConnectTimeout,
+	}
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	config := &manager.Config{
+		ModelName:           modelName,
+		ModelPluginRegistry: smtest.NewModelRegistry(options.plugins...),
+	}
+	if options.configure != nil {
+		options.configure(config)
+	}
+
+	e2t := NewE2T()
+	e2agent.SetDialer(e2t.Dial)
+	sim := &Simulation{
+		t:        t,
+		e2t:      e2t,
+		since:    lastSeq(),
+		consumed: make(map[types.EnbID]int),
+	}
+	t.Cleanup(sim.stop)
+
+	mgr, err := manager.NewManager(config)
+	require.NoError(t, err)
+	sim.manager = mgr
+	require.NoError(t, mgr.Start())
+
+	ctx, cancel := context.WithTimeout(context.Background(), options.connectTimeout)
+	defer cancel()
+	require.NoError(t, mgr.WaitConnected(ctx))
+	return sim
+}
+
+// lastSeq returns the sequence number of the last entry of the process-wide journal
+func lastSeq() uint64 {
+	var seq uint64
+	for _, entry := range journal.Default().Query(journal.Filter{}) {
+		seq = entry.Seq
+	}
+	return seq
+}
+
+// stop stops the simulation and restores the SCTP dialer of the E2 agents
+func (s *Simulation) stop() {
+	if s.manager != nil {
+		s.manager.Close()
+	}
+	e2agent.SetDialer(nil)
+}
+
+// Manager returns the manager running the simulation
+func (s *Simulation) Manager() *manager.Manager {
+	return s.manager
+}
+
+// E2T returns the E2T stub the nodes are connected to
+func (s *Simulation) E2T() *E2T {
+	return s.e2t
+}
+
+// Node returns the node with the given ID connected to the E2T stub
+func (s *Simulation) Node(enbID types.EnbID) *Node {
+	node, err := s.e2t.Node(enbID)
+	require.NoError(s.t, err)
+	return node
+}
+
+// RanFunctionID returns the ID of the RAN function of the service model with the given OID advertised by the node
+func (s *Simulation) RanFunctionID(enbID types.EnbID, oid e2smtypes.OID) e2aptypes.RanFunctionID {
+	id, err := s.Node(enbID).RanFunctionID(oid)
+	require.NoError(s.t, err)
+	return id
+}
+
+// Subscribe installs the subscription on the node, failing the test unless the node accepts it
+func (s *Simulation) Subscribe(enbID types.EnbID, sub Subscription) *e2appducontents.RicsubscriptionResponse {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultConnectTimeout)
+	defer cancel()
+	response, failure, err := s.e2t.Subscribe(ctx, enbID, sub)
+	require.NoError(s.t, err)
+	require.Nil(s.t, failure, "E2 node %d rejected the subscription", enbID)
+	require.NotNil(s.t, response)
+	return response
+}
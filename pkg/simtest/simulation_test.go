@@ -0,0 +1,81 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package simtest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	kpmpdubuilder "github.com/onosproject/onos-e2-sm/servicemodels/e2sm_kpm/pdubuilder"
+	e2sm_kpm_ies "github.com/onosproject/onos-e2-sm/servicemodels/e2sm_kpm/v1beta1/e2sm-kpm-ies"
+	e2apies "github.com/onosproject/onos-e2t/api/e2ap/v1beta2/e2ap-ies"
+	e2aptypes "github.com/onosproject/onos-e2t/pkg/southbound/e2ap101/types"
+	"github.com/onosproject/ran-simulator/pkg/journal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestSimulation(t *testing.T) {
+	sim := Start(t, "../model/test")
+
+	// Both nodes of the model advertise KPM; only node1 advertises RC
+	kpmID := sim.RanFunctionID(144470, KpmOID)
+	sim.RanFunctionID(144471, KpmOID)
+	sim.RanFunctionID(144470, RcOID)
+	_, err := sim.Node(144471).RanFunctionID(RcOID)
+	assert.Error(t, err)
+
+	eventTrigger, err := kpmpdubuilder.CreateE2SmKpmEventTriggerDefinition(int32(e2sm_kpm_ies.RtPeriodIe_RT_PERIOD_IE_MS10))
+	require.NoError(t, err)
+	eventTriggerBytes, err := proto.Marshal(eventTrigger)
+	require.NoError(t, err)
+
+	sim.Subscribe(144470, Subscription{
+		RanFunctionID: kpmID,
+		EventTrigger:  eventTriggerBytes,
+		Actions: []e2aptypes.RicActionDef{
+			{
+				RicActionID:         1,
+				RicActionType:       e2apies.RicactionType_RICACTION_TYPE_REPORT,
+				RicSubsequentAction: e2apies.RicsubsequentActionType_RICSUBSEQUENT_ACTION_TYPE_CONTINUE,
+				Ricttw:              e2apies.RictimeToWait_RICTIME_TO_WAIT_ZERO,
+			},
+		},
+	})
+
+	first := sim.ExpectIndicationWithin(144470, 10*time.Second)
+	second := sim.ExpectIndicationWithin(144470, 10*time.Second)
+	assert.NotSame(t, first, second)
+	assert.Equal(t, int32(kpmID), second.GetProtocolIes().GetE2ApProtocolIes5().GetValue().GetValue())
+	assert.Empty(t, sim.Node(144471).Indications())
+
+	// Handovers are expected from the journal
+	journal.Record(journal.HandoverCompleted, 315010999990001, map[string]interface{}{"target": 84325717506})
+	entry := sim.ExpectHandover(315010999990001, time.Second)
+	assert.Equal(t, uint64(315010999990001), entry.EntityID)
+	assert.NotNil(t, sim.ExpectHandover(0, time.Second))
+}
+
+func TestE2TNodes(t *testing.T) {
+	e2t := NewE2T()
+	_, err := e2t.Node(144470)
+	assert.Error(t, err)
+
+	conn, err := e2t.Dial(context.Background(), 144470, "onos-e2t:36421")
+	require.NoError(t, err)
+	defer conn.Close()
+	node, err := e2t.Node(144470)
+	require.NoError(t, err)
+
+	// The node is not usable before its E2 setup
+	_, err = node.Channel()
+	assert.Error(t, err)
+	_, err = node.RanFunctionID(KpmOID)
+	assert.Error(t, err)
+	_, _, err = e2t.Subscribe(context.Background(), 144470, Subscription{})
+	assert.Error(t, err)
+}
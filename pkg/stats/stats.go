@@ -0,0 +1,162 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package stats collects simulation-wide statistics snapshots, e.g. for CI assertions and dashboards
+package stats
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/onosproject/onos-api/go/onos/ransim/types"
+	"github.com/onosproject/ran-simulator/pkg/e2agent"
+	"github.com/onosproject/ran-simulator/pkg/mobility"
+	"github.com/onosproject/ran-simulator/pkg/model"
+	"github.com/onosproject/ran-simulator/pkg/store/cells"
+	"github.com/onosproject/ran-simulator/pkg/store/metrics"
+	"github.com/onosproject/ran-simulator/pkg/store/nodes"
+	"github.com/onosproject/ran-simulator/pkg/store/ues"
+	"github.com/onosproject/ran-simulator/pkg/store/watcher"
+)
+
+// Snapshot is a snapshot of the statistics of the simulation
+type Snapshot struct {
+	Time          time.Time `json:"time"`
+	Start         time.Time `json:"start"`
+	UptimeSeconds float64   `json:"uptimeSeconds"`
+	Nodes         []Node    `json:"nodes"`
+	Cells         []Cell    `json:"cells"`
+	// UEs is the number of UEs, including those not served by any cell
+	UEs             int       `json:"ues"`
+	Subscriptions   int       `json:"subscriptions"`
+	IndicationsSent uint64    `json:"indicationsSent"`
+	Handovers       Handovers `json:"handovers"`
+	Mobility        Mobility  `json:"mobility"`
+	// Events are the statistics of the store events by topic, e.g. ues
+	Events map[string]watcher.TopicStats `json:"events"`
+}
+
+// Node are the statistics of an E2 node
+type Node struct {
+	EnbID types.EnbID `json:"enbID"`
+	// Status is the status of the agent of the node, e.g. Running or Stopped
+	Status string `json:"status"`
+	// Connected is true if the node completed the E2 setup and has not been stopped since
+	Connected       bool   `json:"connected"`
+	Subscriptions   int    `json:"subscriptions"`
+	IndicationsSent uint64 `json:"indicationsSent"`
+	// Connections are the states of the connections of the node to its controllers by controller ID
+	Connections map[string]model.Connection `json:"connections,omitempty"`
+}
+
+// Cell are the statistics of a cell
+type Cell struct {
+	ECGI types.ECGI `json:"ecgi"`
+	UEs  int        `json:"ues"`
+	// Load is the ratio of the UEs served to the maximum number of UEs of the cell
+	Load float64 `json:"load"`
+}
+
+// Handovers are the handover totals across all cells
+type Handovers struct {
+	Completed uint64 `json:"completed"`
+	Failed    uint64 `json:"failed"`
+}
+
+// Mobility are the statistics of the mobility ticks
+type Mobility struct {
+	Ticks uint64 `json:"ticks"`
+	// MissedDeadlines is the number of ticks which took longer than the tick interval
+	MissedDeadlines uint64 `json:"missedDeadlines"`
+	// UEs is the number of connected UEs measured by the last tick
+	UEs                 int     `json:"ues"`
+	LastTickSeconds     float64 `json:"lastTickSeconds"`
+	MaxTickSeconds      float64 `json:"maxTickSeconds"`
+	TickIntervalSeconds float64 `json:"tickIntervalSeconds"`
+}
+
+// Sources are the sources of the statistics
+type Sources struct {
+	NodeStore   nodes.Store
+	CellStore   cells.Store
+	UEStore     ues.Store
+	MetricStore metrics.Store
+	// Agents are the statistics of the running E2 agents by node
+	Agents map[types.EnbID]e2agent.Stats
+	// Mobility are the statistics of the mobility ticks
+	Mobility mobility.TickStats
+	// Events are the statistics of the store events by topic
+	Events map[string]watcher.TopicStats
+	// Start is the time the simulation was started
+	Start time.Time
+}
+
+// Collect collects a snapshot of the statistics at the given time
+func Collect(ctx context.Context, sources Sources, now time.Time) (*Snapshot, error) {
+	snapshot := &Snapshot{
+		Time:          now,
+		Start:         sources.Start,
+		UptimeSeconds: now.Sub(sources.Start).Seconds(),
+		Nodes:         make([]Node, 0),
+		Cells:         make([]Cell, 0),
+		Events:        sources.Events,
+		Mobility: Mobility{
+			Ticks:               sources.Mobility.Ticks,
+			MissedDeadlines:     sources.Mobility.MissedDeadlines,
+			UEs:                 sources.Mobility.UEs,
+			LastTickSeconds:     sources.Mobility.LastDuration.Seconds(),
+			MaxTickSeconds:      sources.Mobility.MaxDuration.Seconds(),
+			TickIntervalSeconds: sources.Mobility.Interval.Seconds(),
+		},
+	}
+
+	nodeList, err := sources.NodeStore.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, node := range nodeList {
+		stats := Node{EnbID: node.EnbID, Status: node.Status, Connections: node.Connections}
+		if agent, ok := sources.Agents[node.EnbID]; ok {
+			stats.Connected = agent.Connected
+			stats.Subscriptions = agent.Subscriptions
+			stats.IndicationsSent = agent.IndicationsSent
+		}
+		snapshot.Subscriptions += stats.Subscriptions
+		snapshot.IndicationsSent += stats.IndicationsSent
+		snapshot.Nodes = append(snapshot.Nodes, stats)
+	}
+	sort.Slice(snapshot.Nodes, func(i, j int) bool {
+		return snapshot.Nodes[i].EnbID < snapshot.Nodes[j].EnbID
+	})
+
+	cellList, err := sources.CellStore.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, cell := range cellList {
+		ueCount := len(sources.UEStore.ListUEs(ctx, cell.ECGI))
+		snapshot.Cells = append(snapshot.Cells, Cell{ECGI: cell.ECGI, UEs: ueCount, Load: cell.Load(ueCount)})
+		snapshot.Handovers.Completed += counter(ctx, sources.MetricStore, cell.ECGI, mobility.HandoversOut)
+		snapshot.Handovers.Failed += counter(ctx, sources.MetricStore, cell.ECGI, mobility.HandoverFailures)
+	}
+	sort.Slice(snapshot.Cells, func(i, j int) bool {
+		return snapshot.Cells[i].ECGI < snapshot.Cells[j].ECGI
+	})
+	snapshot.UEs = len(sources.UEStore.ListAllUEs(ctx))
+	return snapshot, nil
+}
+
+// counter returns the value of the per-cell counter, zero if not set
+func counter(ctx context.Context, metricStore metrics.Store, ecgi types.ECGI, name string) uint64 {
+	if metricStore == nil {
+		return 0
+	}
+	value, ok := metricStore.Get(ctx, uint64(ecgi), name)
+	if !ok {
+		return 0
+	}
+	count, _ := value.(uint64)
+	return count
+}
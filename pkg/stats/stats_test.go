@@ -0,0 +1,69 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package stats
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/onosproject/onos-api/go/onos/ransim/types"
+	"github.com/onosproject/ran-simulator/pkg/e2agent"
+	"github.com/onosproject/ran-simulator/pkg/mobility"
+	"github.com/onosproject/ran-simulator/pkg/model"
+	"github.com/onosproject/ran-simulator/pkg/store/cells"
+	"github.com/onosproject/ran-simulator/pkg/store/metrics"
+	"github.com/onosproject/ran-simulator/pkg/store/nodes"
+	"github.com/onosproject/ran-simulator/pkg/store/ues"
+	"github.com/onosproject/ran-simulator/pkg/store/watcher"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCollect(t *testing.T) {
+	m := &model.Model{}
+	assert.NoError(t, model.LoadConfig(m, "../model/test"))
+
+	ctx := context.Background()
+	nodeStore := nodes.NewNodeRegistry(m.Nodes)
+	cellStore := cells.NewCellRegistry(m.Cells, nodeStore)
+	ueStore := ues.NewUERegistry(3, cellStore)
+	metricStore := metrics.NewMetricsStore()
+	assert.NoError(t, metricStore.Set(ctx, 84325717505, mobility.HandoversOut, uint64(4)))
+	assert.NoError(t, metricStore.Set(ctx, 84325717506, mobility.HandoversOut, uint64(2)))
+	assert.NoError(t, metricStore.Set(ctx, 84325717506, mobility.HandoverFailures, uint64(1)))
+
+	start := time.Unix(1600000000, 0)
+	snapshot, err := Collect(ctx, Sources{
+		NodeStore:   nodeStore,
+		CellStore:   cellStore,
+		UEStore:     ueStore,
+		MetricStore: metricStore,
+		Agents: map[types.EnbID]e2agent.Stats{
+			144470: {Connected: true, Subscriptions: 2, IndicationsSent: 10},
+		},
+		Mobility: mobility.TickStats{Ticks: 60, MissedDeadlines: 2, UEs: 3, LastDuration: 200 * time.Millisecond, Interval: time.Second},
+		Events:   map[string]watcher.TopicStats{"ues": {Watchers: 2, Delivered: 5}},
+		Start:    start,
+	}, start.Add(time.Minute))
+	assert.NoError(t, err)
+
+	assert.Equal(t, 60.0, snapshot.UptimeSeconds)
+	assert.Len(t, snapshot.Nodes, len(m.Nodes))
+	for i := 1; i < len(snapshot.Nodes); i++ {
+		assert.True(t, snapshot.Nodes[i-1].EnbID < snapshot.Nodes[i].EnbID)
+	}
+	assert.Len(t, snapshot.Cells, len(m.Cells))
+	assert.Equal(t, 3, snapshot.UEs)
+	ueCount := 0
+	for _, cell := range snapshot.Cells {
+		ueCount += cell.UEs
+	}
+	assert.Equal(t, 3, ueCount)
+	assert.Equal(t, 2, snapshot.Subscriptions)
+	assert.Equal(t, uint64(10), snapshot.IndicationsSent)
+	assert.Equal(t, Handovers{Completed: 6, Failed: 1}, snapshot.Handovers)
+	assert.Equal(t, Mobility{Ticks: 60, MissedDeadlines: 2, UEs: 3, LastTickSeconds: 0.2, TickIntervalSeconds: 1}, snapshot.Mobility)
+	assert.Equal(t, watcher.TopicStats{Watchers: 2, Delivered: 5}, snapshot.Events["ues"])
+}
@@ -0,0 +1,167 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cells
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+
+	"github.com/onosproject/onos-api/go/onos/ransim/types"
+	"github.com/onosproject/ran-simulator/pkg/model"
+	"github.com/onosproject/ran-simulator/pkg/store/distributed"
+	"github.com/onosproject/ran-simulator/pkg/store/nodes"
+)
+
+// atomixStore is a cell store whose cells are mirrored in a distributed map; the in-memory store serves as
+// the local cache of the cells simulated by this instance
+type atomixStore struct {
+	*store
+	entries *distributed.Map
+	owns    func(ecgi types.ECGI) bool
+}
+
+// NewAtomixCellRegistry creates a new cell store backed by the specified distributed map. The cells owned by
+// this instance are restored from the map, if any; otherwise the map is primed with the specified cells.
+// Changes of owned cells made by other instances are applied to the store as they occur.
+func NewAtomixCellRegistry(ctx context.Context, entries *distributed.Map, cells map[string]model.Cell, nodeStore nodes.Store, owns func(ecgi types.ECGI) bool) (Store, error) {
+	s := &atomixStore{
+		store:   NewCellRegistry(nil, nodeStore).(*store),
+		entries: entries,
+		owns:    owns,
+	}
+
+	restored := make(map[string]model.Cell)
+	err := entries.List(ctx, func(key string, value []byte) error {
+		cell := model.Cell{}
+		if err := json.Unmarshal(value, &cell); err != nil {
+			return err
+		}
+		if owns(cell.ECGI) {
+			restored[key] = cell
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(restored) > 0 {
+		log.Infof("Restored %d cells from the distributed store", len(restored))
+		s.store.Load(ctx, restored)
+	} else {
+		s.Load(ctx, cells)
+	}
+
+	if err := entries.Watch(s.apply); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func cellKey(ecgi types.ECGI) string {
+	return strconv.FormatUint(uint64(ecgi), 10)
+}
+
+// put mirrors the present state of the specified cell
+func (s *atomixStore) put(ctx context.Context, ecgi types.ECGI) error {
+	s.store.mu.RLock()
+	cell, ok := s.store.cells[ecgi]
+	if !ok {
+		s.store.mu.RUnlock()
+		return nil
+	}
+	value, err := json.Marshal(cell)
+	s.store.mu.RUnlock()
+	if err != nil {
+		return err
+	}
+	return s.entries.Put(ctx, cellKey(ecgi), value)
+}
+
+// apply applies the change of a cell made by another instance to the local cache
+func (s *atomixStore) apply(key string, value []byte) {
+	ctx := context.Background()
+	if value == nil {
+		ecgi, err := strconv.ParseUint(key, 10, 64)
+		if err == nil {
+			_, _ = s.store.Delete(ctx, types.ECGI(ecgi))
+		}
+		return
+	}
+	cell := &model.Cell{}
+	if err := json.Unmarshal(value, cell); err != nil {
+		log.Warnf("Unable to decode cell %s: %v", key, err)
+		return
+	}
+	if !s.owns(cell.ECGI) {
+		return
+	}
+	if err := s.store.Update(ctx, cell); err != nil {
+		_ = s.store.Add(ctx, cell)
+	}
+}
+
+// Load add all cells from the specified cell map; no events will be generated
+func (s *atomixStore) Load(ctx context.Context, cells map[string]model.Cell) {
+	s.store.Load(ctx, cells)
+	for _, cell := range cells {
+		if err := s.put(ctx, cell.ECGI); err != nil {
+			log.Warnf("Unable to store cell %d: %v", cell.ECGI, err)
+		}
+	}
+}
+
+// Clear removes all cells; no events will be generated
+func (s *atomixStore) Clear(ctx context.Context) {
+	list, _ := s.store.List(ctx)
+	s.store.Clear(ctx)
+	for _, cell := range list {
+		if err := s.entries.Remove(ctx, cellKey(cell.ECGI)); err != nil {
+			log.Warnf("Unable to remove cell %d: %v", cell.ECGI, err)
+		}
+	}
+}
+
+// Add adds a cell
+func (s *atomixStore) Add(ctx context.Context, cell *model.Cell) error {
+	if err := s.store.Add(ctx, cell); err != nil {
+		return err
+	}
+	return s.put(ctx, cell.ECGI)
+}
+
+// Update updates a cell
+func (s *atomixStore) Update(ctx context.Context, cell *model.Cell) error {
+	if err := s.store.Update(ctx, cell); err != nil {
+		return err
+	}
+	return s.put(ctx, cell.ECGI)
+}
+
+// UpdateStatus sets the status and color of a cell
+func (s *atomixStore) UpdateStatus(ctx context.Context, ecgi types.ECGI, status model.CellStatus, color string) error {
+	if err := s.store.UpdateStatus(ctx, ecgi, status, color); err != nil {
+		return err
+	}
+	return s.put(ctx, ecgi)
+}
+
+// UpdateNeighbors adds and removes neighbors of a cell
+func (s *atomixStore) UpdateNeighbors(ctx context.Context, ecgi types.ECGI, add []types.ECGI, remove []types.ECGI) ([]types.ECGI, error) {
+	added, err := s.store.UpdateNeighbors(ctx, ecgi, add, remove)
+	if err != nil {
+		return nil, err
+	}
+	return added, s.put(ctx, ecgi)
+}
+
+// Delete deletes a cell
+func (s *atomixStore) Delete(ctx context.Context, ecgi types.ECGI) (*model.Cell, error) {
+	cell, err := s.store.Delete(ctx, ecgi)
+	if err != nil {
+		return nil, err
+	}
+	return cell, s.entries.Remove(ctx, cellKey(ecgi))
+}
@@ -10,8 +10,6 @@ import (
 	"reflect"
 	"sync"
 
-	"github.com/google/uuid"
-
 	"github.com/onosproject/ran-simulator/pkg/store/event"
 
 	"github.com/onosproject/ran-simulator/pkg/store/watcher"
@@ -36,6 +34,13 @@ type Store interface {
 	// Update updates the cell
 	Update(ctx context.Context, Cell *model.Cell) error
 
+	// UpdateStatus sets the status and color of the cell with the specified ECGI, leaving its other fields as they are
+	UpdateStatus(ctx context.Context, ecgi types.ECGI, status model.CellStatus, color string) error
+
+	// UpdateNeighbors adds the given cells missing from the neighbors of the cell with the specified ECGI and removes
+	// the given cells from them, leaving its other fields as they are; the cells added are returned
+	UpdateNeighbors(ctx context.Context, ecgi types.ECGI, add []types.ECGI, remove []types.ECGI) ([]types.ECGI, error)
+
 	// Delete deletes the cell with the specified ECGI
 	Delete(ctx context.Context, ecgi types.ECGI) (*model.Cell, error)
 
@@ -48,6 +53,20 @@ type Store interface {
 	// GetRandomCell retrieves a random cell from the registry
 	GetRandomCell() (*model.Cell, error)
 
+	// Nearest retrieves the cell whose sector center is closest to the given location among the cells accepted by
+	// the filter, or all cells if the filter is nil; the cells are looked up in a spatial index in O(log n)
+	Nearest(ctx context.Context, location model.Coordinate, filter func(*model.Cell) bool) (*model.Cell, error)
+
+	// WithinRadius lists the cells whose sector center is within the given radius in meters of the location,
+	// closest first
+	WithinRadius(ctx context.Context, location model.Coordinate, radius float64) ([]*model.Cell, error)
+
+	// GetStrongestCells lists up to n cells accepted by the filter, or all cells if the filter is nil, whose RSRP at
+	// the given location is the highest according to the propagation model, strongest first; only the cells within
+	// the smallest radius around the location holding n accepted cells are evaluated, so a stronger cell farther
+	// away may be missed
+	GetStrongestCells(ctx context.Context, location model.Coordinate, n int, filter func(*model.Cell) bool) ([]*model.Cell, error)
+
 	// Load add all cells from the specified cell map; no events will be generated
 	Load(ctx context.Context, nodes map[string]model.Cell)
 
@@ -59,6 +78,35 @@ type Store interface {
 type WatchOptions struct {
 	Replay  bool
 	Monitor bool
+	// ECGIs restricts the events to the specified cells, if any
+	ECGIs []types.ECGI
+	// Types restricts the events to the specified event types, if any; replayed cells are always sent
+	Types []CellEvent
+}
+
+// filter returns the filter of the events matching the options, or nil if all events are watched
+func (o WatchOptions) filter() watcher.Filter {
+	if len(o.ECGIs) == 0 && len(o.Types) == 0 {
+		return nil
+	}
+	ecgis := o.ecgis()
+	eventTypes := make(map[CellEvent]bool, len(o.Types))
+	for _, t := range o.Types {
+		eventTypes[t] = true
+	}
+	return func(e event.Event) bool {
+		return (len(ecgis) == 0 || ecgis[e.Key.(types.ECGI)]) &&
+			(len(eventTypes) == 0 || eventTypes[e.Type.(CellEvent)])
+	}
+}
+
+// ecgis returns the set of the watched cells; all cells are watched if empty
+func (o WatchOptions) ecgis() map[types.ECGI]bool {
+	ecgis := make(map[types.ECGI]bool, len(o.ECGIs))
+	for _, ecgi := range o.ECGIs {
+		ecgis[ecgi] = true
+	}
+	return ecgis
 }
 
 type store struct {
@@ -66,12 +114,16 @@ type store struct {
 	cells     map[types.ECGI]*model.Cell
 	nodeStore nodes.Store
 	watchers  *watcher.Watchers
+	// index is the spatial index of the cell positions, built on demand; indexMu guards building it while the store
+	// is locked for reading
+	indexMu sync.Mutex
+	index   *kdTree
 }
 
 // NewCellRegistry creates a new store abstraction from the specified fixed cell map.
 func NewCellRegistry(cells map[string]model.Cell, nodeStore nodes.Store) Store {
 	log.Infof("Creating registry from model with %d cells", len(cells))
-	watchers := watcher.NewWatchers()
+	watchers := watcher.NewWatchers("cells")
 	reg := &store{
 		mu:        sync.RWMutex{},
 		cells:     make(map[types.ECGI]*model.Cell),
@@ -94,6 +146,7 @@ func (s *store) Load(ctx context.Context, cells map[string]model.Cell) {
 		cell := c // avoids scopelint issue
 		s.cells[cell.ECGI] = &cell
 	}
+	s.invalidateIndex()
 }
 
 // Clear removes all cells; no events will be generated
@@ -103,6 +156,7 @@ func (s *store) Clear(ctx context.Context) {
 	for id := range s.cells {
 		delete(s.cells, id)
 	}
+	s.invalidateIndex()
 }
 
 // Add adds a cell
@@ -114,6 +168,7 @@ func (s *store) Add(ctx context.Context, cell *model.Cell) error {
 	}
 
 	s.cells[cell.ECGI] = cell
+	s.invalidateIndex()
 	cellEvent := event.Event{
 		Key:   cell.ECGI,
 		Value: cell,
@@ -140,6 +195,9 @@ func (s *store) Update(ctx context.Context, cell *model.Cell) error {
 	defer s.mu.Unlock()
 	if prevCell, ok := s.cells[cell.ECGI]; ok {
 		s.cells[cell.ECGI] = cell
+		if prevCell.Sector.Center != cell.Sector.Center {
+			s.invalidateIndex()
+		}
 		prevNeighbors := prevCell.Neighbors
 		equalNeighborsResult := equalNeighbors(prevNeighbors, cell.Neighbors)
 		if !equalNeighborsResult {
@@ -150,6 +208,15 @@ func (s *store) Update(ctx context.Context, cell *model.Cell) error {
 			}
 			s.watchers.Send(cellEvent)
 		}
+		if prevCell.Locked != cell.Locked || prevCell.Barred != cell.Barred || prevCell.Outage != cell.Outage ||
+			prevCell.Asleep != cell.Asleep {
+			cellEvent := event.Event{
+				Key:   cell.ECGI,
+				Value: cell,
+				Type:  UpdatedAdminState,
+			}
+			s.watchers.Send(cellEvent)
+		}
 
 		cellEvent := event.Event{
 			Key:   cell.ECGI,
@@ -163,12 +230,80 @@ func (s *store) Update(ctx context.Context, cell *model.Cell) error {
 	return errors.New(errors.NotFound, "cell not found")
 }
 
+// UpdateStatus sets the status and color of a cell
+func (s *store) UpdateStatus(ctx context.Context, ecgi types.ECGI, status model.CellStatus, color string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	prevCell, ok := s.cells[ecgi]
+	if !ok {
+		return errors.New(errors.NotFound, "cell not found")
+	}
+	if prevCell.Status == status && prevCell.Color == color {
+		return nil
+	}
+	cell := *prevCell
+	cell.Status = status
+	cell.Color = color
+	s.cells[ecgi] = &cell
+	s.watchers.Send(event.Event{
+		Key:   ecgi,
+		Value: &cell,
+		Type:  Updated,
+	})
+	return nil
+}
+
+// UpdateNeighbors adds and removes neighbors of a cell
+func (s *store) UpdateNeighbors(ctx context.Context, ecgi types.ECGI, add []types.ECGI, remove []types.ECGI) ([]types.ECGI, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	prevCell, ok := s.cells[ecgi]
+	if !ok {
+		return nil, errors.New(errors.NotFound, "cell not found")
+	}
+	removed := make(map[types.ECGI]bool, len(remove))
+	for _, neighbor := range remove {
+		removed[neighbor] = true
+	}
+	present := make(map[types.ECGI]bool, len(prevCell.Neighbors))
+	neighbors := make([]types.ECGI, 0, len(prevCell.Neighbors)+len(add))
+	for _, neighbor := range prevCell.Neighbors {
+		if !removed[neighbor] {
+			present[neighbor] = true
+			neighbors = append(neighbors, neighbor)
+		}
+	}
+	var added []types.ECGI
+	for _, neighbor := range add {
+		if !present[neighbor] {
+			present[neighbor] = true
+			neighbors = append(neighbors, neighbor)
+			added = append(added, neighbor)
+		}
+	}
+	if len(added) == 0 && len(neighbors) == len(prevCell.Neighbors) {
+		return nil, nil
+	}
+	cell := *prevCell
+	cell.Neighbors = neighbors
+	s.cells[ecgi] = &cell
+	for _, eventType := range []CellEvent{UpdatedNeighbors, Updated} {
+		s.watchers.Send(event.Event{
+			Key:   ecgi,
+			Value: &cell,
+			Type:  eventType,
+		})
+	}
+	return added, nil
+}
+
 // Delete deletes a cell
 func (s *store) Delete(ctx context.Context, ecgi types.ECGI) (*model.Cell, error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 	if cell, ok := s.cells[ecgi]; ok {
 		delete(s.cells, ecgi)
+		s.invalidateIndex()
 		deleteEvent := event.Event{
 			Key:   cell.ECGI,
 			Value: cell,
@@ -187,24 +322,22 @@ func (s *store) Delete(ctx context.Context, ecgi types.ECGI) (*model.Cell, error
 // Watch watch cell events
 func (s *store) Watch(ctx context.Context, ch chan<- event.Event, options ...WatchOptions) error {
 	log.Debug("Watching cell changes")
-	replay := len(options) > 0 && options[0].Replay
-	id := uuid.New()
-	err := s.watchers.AddWatcher(id, ch)
-	if err != nil {
+	var watchOptions WatchOptions
+	if len(options) > 0 {
+		watchOptions = options[0]
+	}
+	if err := s.watchers.Watch(ctx, ch, watchOptions.filter()); err != nil {
 		log.Error(err)
+		return err
 	}
-	go func() {
-		<-ctx.Done()
-		err = s.watchers.RemoveWatcher(id)
-		if err != nil {
-			log.Error(err)
-		}
-		close(ch)
-	}()
 
-	if replay {
+	if watchOptions.Replay {
 		go func() {
+			ecgis := watchOptions.ecgis()
 			for _, cell := range s.cells {
+				if len(ecgis) > 0 && !ecgis[cell.ECGI] {
+					continue
+				}
 				ch <- event.Event{
 					Key:   cell.ECGI,
 					Value: cell,
@@ -52,6 +52,24 @@ func TestCells(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, ecgi1, cell1.ECGI)
 
+	// The status update leaves the other fields as they are
+	locked := *cell1
+	locked.Locked = true
+	assert.NoError(t, cellStore.Update(ctx, &locked))
+	for i := 0; i < 2; i++ {
+		<-ch
+	}
+	assert.NoError(t, cellStore.UpdateStatus(ctx, ecgi1, model.CellDown, "black"))
+	cellEvent = <-ch
+	assert.Equal(t, Updated, cellEvent.Type)
+	cell1, err = cellStore.Get(ctx, ecgi1)
+	assert.NoError(t, err)
+	assert.Equal(t, model.CellDown, cell1.Status)
+	assert.Equal(t, "black", cell1.Color)
+	assert.True(t, cell1.Locked)
+	assert.False(t, cell1.Outage)
+	assert.Error(t, cellStore.UpdateStatus(ctx, 1, model.CellDown, "black"))
+
 	_, err = cellStore.Delete(ctx, ecgi1)
 	assert.NoError(t, err)
 	cellEvent = <-ch
@@ -0,0 +1,237 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cells
+
+import (
+	"context"
+	"math"
+	"sort"
+
+	"github.com/onosproject/onos-api/go/onos/ransim/types"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/onosproject/ran-simulator/pkg/model"
+	"github.com/onosproject/ran-simulator/pkg/radio"
+)
+
+const (
+	earthRadius     = 6378100.0
+	metersPerDegree = 2 * math.Pi * earthRadius / 360
+
+	// strongestCellsRadius is the radius in meters the strongest cells are first searched within; it is doubled
+	// until enough cells are found
+	strongestCellsRadius = 2000.0
+)
+
+// point is the position of a cell site in meters on the plane the cells are projected onto
+type point struct {
+	ecgi types.ECGI
+	x, y float64
+}
+
+// kdTree is a 2-d tree over the sector centers of the cells, projected onto a plane using an equirectangular
+// approximation around their mean latitude, which is accurate for the distances within a simulated network; the
+// points are kept in a slice, each subtree being the median point of its range with the lower and upper halves of
+// the range as its children
+type kdTree struct {
+	points []point
+	// metersPerLngDegree is the length of a degree of longitude at the mean latitude of the cells
+	metersPerLngDegree float64
+}
+
+// newKDTree builds the tree over the given cells in O(n log² n)
+func newKDTree(cells map[types.ECGI]*model.Cell) *kdTree {
+	lat := 0.0
+	for _, cell := range cells {
+		lat += cell.Sector.Center.Lat
+	}
+	if len(cells) > 0 {
+		lat /= float64(len(cells))
+	}
+	t := &kdTree{
+		points:             make([]point, 0, len(cells)),
+		metersPerLngDegree: metersPerDegree * math.Cos(lat*math.Pi/180),
+	}
+	for _, cell := range cells {
+		p := t.project(cell.Sector.Center)
+		p.ecgi = cell.ECGI
+		t.points = append(t.points, p)
+	}
+	t.build(t.points, 0)
+	return t
+}
+
+func (t *kdTree) project(location model.Coordinate) point {
+	return point{x: location.Lng * t.metersPerLngDegree, y: location.Lat * metersPerDegree}
+}
+
+// build orders the points into the subtree splitting by x at even and by y at odd depths
+func (t *kdTree) build(points []point, depth int) {
+	if len(points) <= 1 {
+		return
+	}
+	sort.Slice(points, func(i, j int) bool {
+		return axis(points[i], depth) < axis(points[j], depth)
+	})
+	median := len(points) / 2
+	t.build(points[:median], depth+1)
+	t.build(points[median+1:], depth+1)
+}
+
+func axis(p point, depth int) float64 {
+	if depth%2 == 0 {
+		return p.x
+	}
+	return p.y
+}
+
+func distance(p, q point) float64 {
+	return math.Hypot(p.x-q.x, p.y-q.y)
+}
+
+// nearest returns the point closest to the target accepted by the filter, visiting O(log n) points on average if
+// most points are accepted
+func (t *kdTree) nearest(target point, accept func(types.ECGI) bool) (point, float64, bool) {
+	best, bestDistance, found := point{}, math.MaxFloat64, false
+	var search func(points []point, depth int)
+	search = func(points []point, depth int) {
+		if len(points) == 0 {
+			return
+		}
+		median := len(points) / 2
+		p := points[median]
+		if d := distance(p, target); d < bestDistance && accept(p.ecgi) {
+			best, bestDistance, found = p, d, true
+		}
+		delta := axis(target, depth) - axis(p, depth)
+		near, far := points[:median], points[median+1:]
+		if delta > 0 {
+			near, far = far, near
+		}
+		search(near, depth+1)
+		// The other side can only hold a closer point if the splitting line is closer than the best point so far
+		if math.Abs(delta) < bestDistance {
+			search(far, depth+1)
+		}
+	}
+	search(t.points, 0)
+	return best, bestDistance, found
+}
+
+// within returns the points within the radius of the target
+func (t *kdTree) within(target point, radius float64) []point {
+	var result []point
+	var search func(points []point, depth int)
+	search = func(points []point, depth int) {
+		if len(points) == 0 {
+			return
+		}
+		median := len(points) / 2
+		p := points[median]
+		if distance(p, target) <= radius {
+			result = append(result, p)
+		}
+		delta := axis(target, depth) - axis(p, depth)
+		if delta <= radius {
+			search(points[:median], depth+1)
+		}
+		if delta >= -radius {
+			search(points[median+1:], depth+1)
+		}
+	}
+	search(t.points, 0)
+	return result
+}
+
+// spatialIndex returns the index of the cell positions, building it if the cells changed since it was last built;
+// the store must be locked for reading
+func (s *store) spatialIndex() *kdTree {
+	s.indexMu.Lock()
+	defer s.indexMu.Unlock()
+	if s.index == nil {
+		s.index = newKDTree(s.cells)
+	}
+	return s.index
+}
+
+// invalidateIndex drops the index of the cell positions; the store must be locked for writing
+func (s *store) invalidateIndex() {
+	s.index = nil
+}
+
+// Nearest returns the cell whose sector center is closest to the given location among the cells accepted by the
+// filter, or all cells if the filter is nil
+func (s *store) Nearest(ctx context.Context, location model.Coordinate, filter func(*model.Cell) bool) (*model.Cell, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	index := s.spatialIndex()
+	p, _, ok := index.nearest(index.project(location), func(ecgi types.ECGI) bool {
+		return filter == nil || filter(s.cells[ecgi])
+	})
+	if !ok {
+		return nil, errors.New(errors.NotFound, "no cell found")
+	}
+	return s.cells[p.ecgi], nil
+}
+
+// WithinRadius returns the cells whose sector center is within the given radius in meters of the location, closest
+// first
+func (s *store) WithinRadius(ctx context.Context, location model.Coordinate, radius float64) ([]*model.Cell, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.withinRadius(location, radius), nil
+}
+
+// withinRadius returns the cells within the radius of the location, closest first; the store must be locked for
+// reading
+func (s *store) withinRadius(location model.Coordinate, radius float64) []*model.Cell {
+	index := s.spatialIndex()
+	target := index.project(location)
+	points := index.within(target, radius)
+	sort.Slice(points, func(i, j int) bool {
+		return distance(points[i], target) < distance(points[j], target)
+	})
+	list := make([]*model.Cell, 0, len(points))
+	for _, p := range points {
+		list = append(list, s.cells[p.ecgi])
+	}
+	return list
+}
+
+// GetStrongestCells returns up to n cells accepted by the filter, or all cells if the filter is nil, whose RSRP at
+// the location is the highest according to the propagation model, strongest first. The cells are searched within a
+// radius around the location looked up in the spatial index, which is doubled until it holds n accepted cells, so
+// that only the cells in the vicinity of the location are evaluated. The result is an approximation: a cell beyond
+// the final radius is missed even if it is stronger than some of the cells returned, e.g. due to a higher transmit
+// power, its antenna pointing at the location or the shadowing, which cannot be bounded tightly enough to prune the
+// search. The callers use the cells as candidates, e.g. for neighbor relations or the initial serving cell of a UE,
+// for which the nearby cells suffice.
+func (s *store) GetStrongestCells(ctx context.Context, location model.Coordinate, n int, filter func(*model.Cell) bool) ([]*model.Cell, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var candidates []*model.Cell
+	for radius := strongestCellsRadius; ; radius *= 2 {
+		candidates = candidates[:0]
+		within := s.withinRadius(location, radius)
+		for _, cell := range within {
+			if filter == nil || filter(cell) {
+				candidates = append(candidates, cell)
+			}
+		}
+		if len(candidates) >= n || len(within) == len(s.cells) {
+			break
+		}
+	}
+	rsrp := make(map[types.ECGI]float64, len(candidates))
+	for _, cell := range candidates {
+		rsrp[cell.ECGI] = radio.RSRP(cell, location)
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return rsrp[candidates[i].ECGI] > rsrp[candidates[j].ECGI]
+	})
+	if len(candidates) > n {
+		candidates = candidates[:n]
+	}
+	return candidates, nil
+}
@@ -0,0 +1,116 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cells
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/onosproject/onos-api/go/onos/ransim/types"
+	"github.com/onosproject/ran-simulator/pkg/model"
+	"github.com/onosproject/ran-simulator/pkg/radio"
+	"github.com/onosproject/ran-simulator/pkg/store/nodes"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSpatialIndex(t *testing.T) {
+	ctx := context.Background()
+	rng := rand.New(rand.NewSource(1))
+	random := func() model.Coordinate {
+		return model.Coordinate{Lat: 52.4 + rng.Float64()*0.2, Lng: 13.3 + rng.Float64()*0.3}
+	}
+	cellMap := make(map[string]model.Cell)
+	for i := 0; i < 2000; i++ {
+		ecgi := types.ECGI(84325717505 + i)
+		cellMap[fmt.Sprint(ecgi)] = model.Cell{ECGI: ecgi, Sector: model.Sector{Center: random()}}
+	}
+	cellStore := NewCellRegistry(cellMap, nodes.NewNodeRegistry(nil))
+	cellList, err := cellStore.List(ctx)
+	assert.NoError(t, err)
+
+	even := func(cell *model.Cell) bool {
+		return cell.ECGI%2 == 0
+	}
+	for i := 0; i < 200; i++ {
+		location := random()
+		nearest, err := cellStore.Nearest(ctx, location, nil)
+		assert.NoError(t, err)
+		nearestEven, err := cellStore.Nearest(ctx, location, even)
+		assert.NoError(t, err)
+		assert.True(t, even(nearestEven))
+		within, err := cellStore.WithinRadius(ctx, location, 1000)
+		assert.NoError(t, err)
+
+		minDistance, minEvenDistance, count := math.MaxFloat64, math.MaxFloat64, 0
+		for _, cell := range cellList {
+			d := radio.Distance(location, cell.Sector.Center)
+			minDistance = math.Min(minDistance, d)
+			if even(cell) {
+				minEvenDistance = math.Min(minEvenDistance, d)
+			}
+			if d <= 1000 {
+				count++
+			}
+		}
+		// The index projects the cells onto a plane, which is accurate to a few meters per kilometer over the area
+		assert.InDelta(t, minDistance, radio.Distance(location, nearest.Sector.Center), 5)
+		assert.InDelta(t, minEvenDistance, radio.Distance(location, nearestEven.Sector.Center), 5)
+		assert.InDelta(t, count, len(within), 2)
+		for j := 1; j < len(within); j++ {
+			assert.True(t, radio.Distance(location, within[j-1].Sector.Center) <= radio.Distance(location, within[j].Sector.Center)+5)
+		}
+	}
+
+	// The index follows the changes of the cells
+	location := model.Coordinate{Lat: 52.0, Lng: 13.0}
+	moved := &model.Cell{ECGI: 84325717505, Sector: model.Sector{Center: location}}
+	assert.NoError(t, cellStore.Update(ctx, moved))
+	nearest, err := cellStore.Nearest(ctx, location, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, moved.ECGI, nearest.ECGI)
+	_, err = cellStore.Delete(ctx, moved.ECGI)
+	assert.NoError(t, err)
+	nearest, err = cellStore.Nearest(ctx, location, nil)
+	assert.NoError(t, err)
+	assert.NotEqual(t, moved.ECGI, nearest.ECGI)
+
+	cellStore.Clear(ctx)
+	_, err = cellStore.Nearest(ctx, location, nil)
+	assert.Error(t, err)
+}
+
+func TestGetStrongestCells(t *testing.T) {
+	ctx := context.Background()
+	site := model.Coordinate{Lat: 52.5, Lng: 13.4}
+	cellMap := map[string]model.Cell{
+		"north": {ECGI: 1, TxPowerDB: 40, Sector: model.Sector{Center: site, Azimuth: 0, Arc: 120}},
+		"south": {ECGI: 2, TxPowerDB: 40, Sector: model.Sector{Center: site, Azimuth: 180, Arc: 120}},
+		"far":   {ECGI: 3, TxPowerDB: 40, Sector: model.Sector{Center: radio.Offset(site, 0, 20000), Azimuth: 180, Arc: 120}},
+	}
+	cellStore := NewCellRegistry(cellMap, nodes.NewNodeRegistry(nil))
+
+	// The cell facing the location is the strongest, although all cells of the site are equally close
+	location := radio.Offset(site, 0, 500)
+	strongest, err := cellStore.GetStrongestCells(ctx, location, 2, nil)
+	assert.NoError(t, err)
+	assert.Len(t, strongest, 2)
+	assert.Equal(t, types.ECGI(1), strongest[0].ECGI)
+	assert.Equal(t, types.ECGI(2), strongest[1].ECGI)
+
+	// The search radius grows until enough cells are accepted
+	strongest, err = cellStore.GetStrongestCells(ctx, location, 2, func(cell *model.Cell) bool {
+		return cell.ECGI != 1
+	})
+	assert.NoError(t, err)
+	assert.Len(t, strongest, 2)
+	assert.Equal(t, types.ECGI(2), strongest[0].ECGI)
+	assert.Equal(t, types.ECGI(3), strongest[1].ECGI)
+	strongest, err = cellStore.GetStrongestCells(ctx, location, 5, nil)
+	assert.NoError(t, err)
+	assert.Len(t, strongest, 3)
+}
@@ -16,10 +16,12 @@ const (
 	Updated
 	// UpdatedNeighbors updated cell neighbors event
 	UpdatedNeighbors
+	// UpdatedAdminState updated cell administrative (locked/barred) or operational (outage/asleep) state event
+	UpdatedAdminState
 	// Deleted deleted cell event
 	Deleted
 )
 
 func (e CellEvent) String() string {
-	return [...]string{"None", "Created", "Updated", "Deleted"}[e]
+	return [...]string{"None", "Created", "Updated", "UpdatedNeighbors", "UpdatedAdminState", "Deleted"}[e]
 }
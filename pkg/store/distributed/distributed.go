@@ -0,0 +1,102 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package distributed
+
+import (
+	"context"
+
+	"github.com/atomix/go-client/pkg/client"
+	"github.com/onosproject/onos-lib-go/pkg/atomix"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	liblog "github.com/onosproject/onos-lib-go/pkg/logging"
+)
+
+var log = liblog.GetLogger("store", "distributed")
+
+// Store backends
+const (
+	// BackendMemory keeps the state of the simulation in memory only; this is the default
+	BackendMemory = "memory"
+	// BackendAtomix mirrors the state of the simulation in the maps of an Atomix database
+	BackendAtomix = "atomix"
+)
+
+// Names of the maps backing the node, cell and UE stores
+const (
+	NodesMap = "ransim-nodes"
+	CellsMap = "ransim-cells"
+	UEsMap   = "ransim-ues"
+)
+
+// Config configures the backend of the node, cell and UE stores
+type Config struct {
+	// Backend selects the store implementation, BackendMemory unless specified
+	Backend string
+	// Atomix configures the client of the Atomix controller; unset fields are derived from the pod environment
+	Atomix atomix.Config
+	// Database is the name of the Atomix database holding the maps
+	Database string
+}
+
+// Enabled returns true if the stores are backed by a distributed database
+func (c Config) Enabled() bool {
+	return c.Backend == BackendAtomix
+}
+
+// Validate checks the configuration
+func (c Config) Validate() error {
+	switch c.Backend {
+	case "", BackendMemory:
+		return nil
+	case BackendAtomix:
+		if c.Database == "" {
+			return errors.New(errors.Invalid, "Atomix database is required")
+		}
+		return nil
+	default:
+		return errors.New(errors.Invalid, "unknown store backend %s", c.Backend)
+	}
+}
+
+// Maps are the distributed maps backing the node, cell and UE stores
+type Maps struct {
+	client *client.Client
+	Nodes  *Map
+	Cells  *Map
+	UEs    *Map
+}
+
+// Open connects to the Atomix controller and opens the maps backing the stores
+func Open(ctx context.Context, config Config) (*Maps, error) {
+	atomixClient, err := atomix.GetClient(config.Atomix)
+	if err != nil {
+		return nil, errors.FromAtomix(err)
+	}
+	database, err := atomixClient.GetDatabase(ctx, config.Database)
+	if err != nil {
+		_ = atomixClient.Close()
+		return nil, errors.FromAtomix(err)
+	}
+	maps := &Maps{client: atomixClient}
+	for name, m := range map[string]**Map{NodesMap: &maps.Nodes, CellsMap: &maps.Cells, UEsMap: &maps.UEs} {
+		entries, err := database.GetMap(ctx, name)
+		if err != nil {
+			_ = atomixClient.Close()
+			return nil, errors.FromAtomix(err)
+		}
+		*m = NewMap(entries)
+	}
+	log.Infof("Opened maps of Atomix database %s", config.Database)
+	return maps, nil
+}
+
+// Close closes the maps and the connection to the Atomix controller
+func (m *Maps) Close() error {
+	ctx := context.Background()
+	for _, entries := range []*Map{m.Nodes, m.Cells, m.UEs} {
+		_ = entries.Close(ctx)
+	}
+	return m.client.Close()
+}
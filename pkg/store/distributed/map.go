@@ -0,0 +1,185 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package distributed
+
+import (
+	"context"
+	"sync"
+
+	_map "github.com/atomix/go-client/pkg/client/map"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+)
+
+// Map mirrors the entities of a store as encoded values of a distributed map. The map remembers the versions of
+// the entries it wrote, so that its own changes are not reported back by Watch and entries overwritten by other
+// instances in the meantime are not removed.
+type Map struct {
+	entries _map.Map
+	ctx     context.Context
+	cancel  context.CancelFunc
+	mu      sync.Mutex
+	// written is signalled whenever a write of the map completes
+	written *sync.Cond
+	// pending counts the writes in flight per key, whose versions are not known yet
+	pending map[string]int
+	// own holds the versions written by the map per key which were not reported by Watch yet, in ascending order
+	own      map[string][]_map.Version
+	versions map[string]_map.Version
+	removing map[string]bool
+}
+
+// NewMap creates a new mirror of store entities backed by the specified distributed map
+func NewMap(entries _map.Map) *Map {
+	ctx, cancel := context.WithCancel(context.Background())
+	m := &Map{
+		entries:  entries,
+		ctx:      ctx,
+		cancel:   cancel,
+		pending:  make(map[string]int),
+		own:      make(map[string][]_map.Version),
+		versions: make(map[string]_map.Version),
+		removing: make(map[string]bool),
+	}
+	m.written = sync.NewCond(&m.mu)
+	return m
+}
+
+// Put writes the encoded entity under the specified key
+func (m *Map) Put(ctx context.Context, key string, value []byte) error {
+	// The write is recorded up front as the change may be reported before its version is known
+	m.mu.Lock()
+	m.pending[key]++
+	m.mu.Unlock()
+	entry, err := m.entries.Put(ctx, key, value)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.pending[key]--; m.pending[key] == 0 {
+		delete(m.pending, key)
+	}
+	defer m.written.Broadcast()
+	if err != nil {
+		return errors.FromAtomix(err)
+	}
+	m.versions[key] = entry.Version
+	m.own[key] = insertVersion(m.own[key], entry.Version)
+	return nil
+}
+
+// insertVersion inserts the version into the ascending versions
+func insertVersion(versions []_map.Version, version _map.Version) []_map.Version {
+	i := len(versions)
+	for i > 0 && versions[i-1] > version {
+		i--
+	}
+	versions = append(versions, 0)
+	copy(versions[i+1:], versions[i:])
+	versions[i] = version
+	return versions
+}
+
+// Remove removes the entity with the specified key, unless another instance has written it since this map did
+func (m *Map) Remove(ctx context.Context, key string) error {
+	m.mu.Lock()
+	version, ok := m.versions[key]
+	delete(m.versions, key)
+	m.removing[key] = true
+	m.mu.Unlock()
+
+	var opts []_map.RemoveOption
+	if ok {
+		opts = append(opts, _map.IfVersion(version))
+	}
+	_, err := m.entries.Remove(ctx, key, opts...)
+	if err = errors.FromAtomix(err); err != nil {
+		m.mu.Lock()
+		delete(m.removing, key)
+		m.mu.Unlock()
+		if !errors.IsNotFound(err) && !errors.IsConflict(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// List calls the function for all entries of the map
+func (m *Map) List(ctx context.Context, f func(key string, value []byte) error) error {
+	ch := make(chan *_map.Entry)
+	if err := m.entries.Entries(ctx, ch); err != nil {
+		return errors.FromAtomix(err)
+	}
+	var err error
+	for entry := range ch {
+		if err == nil {
+			m.mu.Lock()
+			m.versions[entry.Key] = entry.Version
+			m.mu.Unlock()
+			err = f(entry.Key, entry.Value)
+		}
+	}
+	return err
+}
+
+// Watch calls the function for all changes of the map made by other instances until the map is closed;
+// the value of removed entries is nil
+func (m *Map) Watch(f func(key string, value []byte)) error {
+	ch := make(chan *_map.Event)
+	if err := m.entries.Watch(m.ctx, ch); err != nil {
+		return errors.FromAtomix(err)
+	}
+	go func() {
+		for event := range ch {
+			if m.isOwnChange(event) {
+				continue
+			}
+			if event.Type == _map.EventRemoved {
+				f(event.Entry.Key, nil)
+			} else {
+				f(event.Entry.Key, event.Entry.Value)
+			}
+		}
+	}()
+	return nil
+}
+
+// isOwnChange returns true if the event reports a change made by this map, i.e. if this map wrote the version of
+// the entry; the events for a key wait for the writes of the key in flight, whose versions are not known yet
+func (m *Map) isOwnChange(event *_map.Event) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	key := event.Entry.Key
+	switch event.Type {
+	case _map.EventRemoved:
+		if m.removing[key] {
+			delete(m.removing, key)
+			return true
+		}
+		return false
+	case _map.EventInserted, _map.EventUpdated:
+		for m.pending[key] > 0 {
+			m.written.Wait()
+		}
+		// Events are reported in the order of the versions, so the versions up to that of the event are dropped
+		versions := m.own[key]
+		own := false
+		i := 0
+		for ; i < len(versions) && versions[i] <= event.Entry.Version; i++ {
+			own = own || versions[i] == event.Entry.Version
+		}
+		if i == len(versions) {
+			delete(m.own, key)
+		} else {
+			m.own[key] = versions[i:]
+		}
+		return own
+	default:
+		return true
+	}
+}
+
+// Close closes the map
+func (m *Map) Close(ctx context.Context) error {
+	m.cancel()
+	return errors.FromAtomix(m.entries.Close(ctx))
+}
@@ -0,0 +1,103 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package distributed
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	_map "github.com/atomix/go-client/pkg/client/map"
+	"github.com/atomix/go-client/pkg/client/primitive"
+	"github.com/atomix/go-client/pkg/client/test"
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestMaps returns two mirrors of the same map as used by two simulator instances
+func newTestMaps(t *testing.T) (*Map, *Map) {
+	partitions, closers := test.StartTestPartitions(1)
+	t.Cleanup(func() {
+		test.StopTestPartitions(closers)
+	})
+	maps := make([]*Map, 2)
+	for i := range maps {
+		sessions, err := test.OpenSessions(partitions)
+		assert.NoError(t, err)
+		t.Cleanup(func() {
+			test.CloseSessions(sessions)
+		})
+		entries, err := _map.New(context.Background(), primitive.NewName("default", "ransim", "default", NodesMap), sessions)
+		assert.NoError(t, err)
+		maps[i] = NewMap(entries)
+	}
+	return maps[0], maps[1]
+}
+
+type change struct {
+	key   string
+	value []byte
+}
+
+func TestMap(t *testing.T) {
+	ctx := context.Background()
+	local, remote := newTestMaps(t)
+	changes := make(chan change, 10)
+	assert.NoError(t, local.Watch(func(key string, value []byte) {
+		changes <- change{key: key, value: value}
+	}))
+
+	// Changes of other instances are reported, the own ones are not
+	assert.NoError(t, local.Put(ctx, "1", []byte("a")))
+	assert.NoError(t, remote.Put(ctx, "2", []byte("b")))
+	c := <-changes
+	assert.Equal(t, "2", c.key)
+	assert.Equal(t, []byte("b"), c.value)
+
+	// Entries overwritten by other instances are not removed
+	assert.NoError(t, remote.Put(ctx, "1", []byte("c")))
+	c = <-changes
+	assert.Equal(t, "1", c.key)
+	assert.NoError(t, remote.Remove(ctx, "2"))
+	c = <-changes
+	assert.Equal(t, "2", c.key)
+	assert.Nil(t, c.value)
+
+	assert.NoError(t, local.Put(ctx, "3", []byte("d")))
+	assert.NoError(t, remote.Put(ctx, "3", []byte("e")))
+	<-changes
+	assert.NoError(t, local.Remove(ctx, "3"))
+	entries := make(map[string]string)
+	assert.NoError(t, remote.List(ctx, func(key string, value []byte) error {
+		entries[key] = string(value)
+		return nil
+	}))
+	assert.Equal(t, map[string]string{"1": "c", "3": "e"}, entries)
+
+	// Successive own writes are not reported, but writes of other instances restoring an own value are
+	for _, value := range []string{"f", "g", "h"} {
+		assert.NoError(t, local.Put(ctx, "4", []byte(value)))
+	}
+	assert.NoError(t, remote.Put(ctx, "4", []byte("i")))
+	assert.NoError(t, remote.Put(ctx, "4", []byte("h")))
+	c = <-changes
+	assert.Equal(t, []byte("i"), c.value)
+	c = <-changes
+	assert.Equal(t, []byte("h"), c.value)
+
+	select {
+	case c = <-changes:
+		assert.Fail(t, "unexpected change", c.key)
+	case <-time.After(100 * time.Millisecond):
+	}
+	assert.NoError(t, local.Close(ctx))
+}
+
+func TestConfig(t *testing.T) {
+	assert.NoError(t, Config{}.Validate())
+	assert.False(t, Config{}.Enabled())
+	assert.Error(t, Config{Backend: BackendAtomix}.Validate())
+	assert.NoError(t, Config{Backend: BackendAtomix, Database: "ran-simulator"}.Validate())
+	assert.Error(t, Config{Backend: "etcd"}.Validate())
+}
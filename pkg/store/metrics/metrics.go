@@ -8,7 +8,7 @@ import (
 	"context"
 	"sync"
 
-	"github.com/google/uuid"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
 	liblog "github.com/onosproject/onos-lib-go/pkg/logging"
 	"github.com/onosproject/ran-simulator/pkg/store/event"
 	"github.com/onosproject/ran-simulator/pkg/store/watcher"
@@ -21,9 +21,23 @@ type Store interface {
 	// ListEntities retrieves all entities that presently have metrics associated with them
 	ListEntities(ctx context.Context) ([]uint64, error)
 
-	// Set applies the specified metric value on the given entity
+	// Set applies the specified metric value on the given entity; the values of counters must be uint64 and
+	// cannot decrease
 	Set(ctx context.Context, entityID uint64, name string, value interface{}) error
 
+	// Add atomically adds the delta to the specified counter of the given entity, declaring the metric as counter,
+	// and returns its new value
+	Add(ctx context.Context, entityID uint64, name string, delta uint64) (uint64, error)
+
+	// Reset resets the specified counters of the given entity, or all of its counters if none is specified, to zero
+	Reset(ctx context.Context, entityID uint64, names ...string) error
+
+	// Declare declares the kind of the metrics with the specified name
+	Declare(name string, kind Kind)
+
+	// Kind returns the kind of the metrics with the specified name; metrics are gauges unless declared otherwise
+	Kind(name string) Kind
+
 	// Get retrieves the specified metric value on the given entity
 	Get(ctx context.Context, entityID uint64, name string) (interface{}, bool)
 
@@ -45,26 +59,60 @@ type Store interface {
 
 // WatchOptions allows tailoring the WatchNodes behaviour
 type WatchOptions struct {
+	// EntityIDs restricts the events to the metrics of the specified entities, if any
+	EntityIDs []uint64
+	// Names restricts the events to the metrics with the specified names, if any
+	Names []string
+	// Types restricts the events to the specified event types, if any
+	Types []MetricEvent
+}
+
+// filter returns the filter of the events matching the options, or nil if all events are watched
+func (o WatchOptions) filter() watcher.Filter {
+	if len(o.EntityIDs) == 0 && len(o.Names) == 0 && len(o.Types) == 0 {
+		return nil
+	}
+	entityIDs := make(map[uint64]bool, len(o.EntityIDs))
+	for _, id := range o.EntityIDs {
+		entityIDs[id] = true
+	}
+	names := make(map[string]bool, len(o.Names))
+	for _, name := range o.Names {
+		names[name] = true
+	}
+	eventTypes := make(map[MetricEvent]bool, len(o.Types))
+	for _, t := range o.Types {
+		eventTypes[t] = true
+	}
+	return func(e event.Event) bool {
+		k := e.Key.(Key)
+		return (len(entityIDs) == 0 || entityIDs[k.EntityID]) &&
+			(len(names) == 0 || names[k.Name]) &&
+			(len(eventTypes) == 0 || eventTypes[e.Type.(MetricEvent)])
+	}
 }
 
 type store struct {
 	mu       sync.RWMutex
 	metrics  map[Key]interface{}
+	kinds    map[string]Kind
 	watchers *watcher.Watchers
 }
 
 // NewMetricsStore returns a newly created metric store
 func NewMetricsStore() Store {
 	log.Infof("Creating metrics store")
-	watchers := watcher.NewWatchers()
+	watchers := watcher.NewWatchers("metrics")
 	return &store{
 		mu:       sync.RWMutex{},
 		metrics:  make(map[Key]interface{}),
+		kinds:    make(map[string]Kind),
 		watchers: watchers,
 	}
 }
 
-// Clear clears all metrics; no events will be generated
+// Clear clears all metrics, i.e. counters restart from zero, but retains the kinds of the metrics; no events will
+// be generated
 func (s *store) Clear(ctx context.Context) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
@@ -113,11 +161,80 @@ func (s *store) Set(ctx context.Context, entityID uint64, name string, value int
 	s.mu.Lock()
 	defer s.mu.Unlock()
 	k := key(entityID, name)
+	if s.kinds[name] == Counter {
+		count, ok := value.(uint64)
+		if !ok {
+			return errors.New(errors.Invalid, "counter %s requires a uint64 value", name)
+		}
+		if current, ok := s.metrics[k].(uint64); ok && count < current {
+			return errors.New(errors.Invalid, "counter %s of entity %d cannot decrease from %d to %d; reset it instead", name, entityID, current, count)
+		}
+	}
 	s.metrics[k] = value
-	s.watchers.Send(metricEvent(k, value, Updated))
+	s.watchers.SendContext(ctx, metricEvent(k, value, Updated))
 	return nil
 }
 
+// Add atomically adds the delta to the specified counter of the given entity, declaring the metric as counter,
+// and returns its new value
+func (s *store) Add(ctx context.Context, entityID uint64, name string, delta uint64) (uint64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if kind, ok := s.kinds[name]; ok && kind != Counter {
+		return 0, errors.New(errors.Invalid, "metric %s is a %s", name, kind)
+	}
+	s.kinds[name] = Counter
+	k := key(entityID, name)
+	count, _ := s.metrics[k].(uint64)
+	count += delta
+	s.metrics[k] = count
+	s.watchers.SendContext(ctx, metricEvent(k, count, Updated))
+	return count, nil
+}
+
+// Reset resets the specified counters of the given entity, or all of its counters if none is specified, to zero
+func (s *store) Reset(ctx context.Context, entityID uint64, names ...string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, name := range names {
+		if s.kinds[name] != Counter {
+			return errors.New(errors.Invalid, "metric %s is not a counter", name)
+		}
+	}
+	reset := func(k Key) {
+		if _, ok := s.metrics[k]; ok {
+			s.metrics[k] = uint64(0)
+			s.watchers.SendContext(ctx, metricEvent(k, uint64(0), Updated))
+		}
+	}
+	if len(names) > 0 {
+		for _, name := range names {
+			reset(key(entityID, name))
+		}
+		return nil
+	}
+	for k := range s.metrics {
+		if k.EntityID == entityID && s.kinds[k.Name] == Counter {
+			reset(k)
+		}
+	}
+	return nil
+}
+
+// Declare declares the kind of the metrics with the specified name
+func (s *store) Declare(name string, kind Kind) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.kinds[name] = kind
+}
+
+// Kind returns the kind of the metrics with the specified name; metrics are gauges unless declared otherwise
+func (s *store) Kind(name string) Kind {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.kinds[name]
+}
+
 // Get retrieves the specified metric value on the given entity
 func (s *store) Get(ctx context.Context, entityID uint64, name string) (interface{}, bool) {
 	s.mu.RLock()
@@ -134,7 +251,7 @@ func (s *store) Delete(ctx context.Context, entityID uint64, name string) error
 	defer s.mu.Unlock()
 	k := key(entityID, name)
 	delete(s.metrics, k)
-	s.watchers.Send(metricEvent(k, nil, Deleted))
+	s.watchers.SendContext(ctx, metricEvent(k, nil, Deleted))
 	return nil
 }
 
@@ -145,7 +262,7 @@ func (s *store) DeleteAll(ctx context.Context, entityID uint64) error {
 	for k, v := range s.metrics {
 		if k.EntityID == entityID {
 			delete(s.metrics, k)
-			s.watchers.Send(metricEvent(k, v, Deleted))
+			s.watchers.SendContext(ctx, metricEvent(k, v, Deleted))
 		}
 	}
 	return nil
@@ -167,20 +284,13 @@ func (s *store) List(ctx context.Context, entityID uint64) (map[string]interface
 // WatchMetrics monitors changes to the metrics
 func (s *store) Watch(ctx context.Context, ch chan<- event.Event, options ...WatchOptions) error {
 	log.Debug("Watching metric changes")
-	id := uuid.New()
-	err := s.watchers.AddWatcher(id, ch)
-	if err != nil {
+	var watchOptions WatchOptions
+	if len(options) > 0 {
+		watchOptions = options[0]
+	}
+	if err := s.watchers.Watch(ctx, ch, watchOptions.filter()); err != nil {
 		log.Error(err)
 		return err
 	}
-	go func() {
-		<-ctx.Done()
-		err = s.watchers.RemoveWatcher(id)
-		if err != nil {
-			log.Error(err)
-		}
-		close(ch)
-
-	}()
 	return nil
 }
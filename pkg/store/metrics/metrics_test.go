@@ -7,7 +7,9 @@ package metrics
 import (
 	"context"
 	"testing"
+	"time"
 
+	"github.com/onosproject/onos-lib-go/pkg/errors"
 	"github.com/onosproject/ran-simulator/pkg/store/event"
 
 	"github.com/stretchr/testify/assert"
@@ -84,3 +86,72 @@ func TestMetrics(t *testing.T) {
 
 	ctx.Done()
 }
+
+func TestWatchOptions(t *testing.T) {
+	store := NewMetricsStore()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ch := make(chan event.Event, 10)
+	assert.NoError(t, store.Watch(ctx, ch, WatchOptions{EntityIDs: []uint64{123}, Names: []string{"foo"}, Types: []MetricEvent{Deleted}}))
+
+	_ = store.Set(ctx, 123, "foo", 6.28)
+	_ = store.Set(ctx, 123, "bar", 3.14)
+	_ = store.Delete(ctx, 321, "foo")
+	_ = store.Delete(ctx, 123, "foo")
+
+	metricEvent := <-ch
+	assert.Equal(t, Deleted, metricEvent.Type)
+	assert.Equal(t, Key{EntityID: 123, Name: "foo"}, metricEvent.Key)
+	select {
+	case metricEvent = <-ch:
+		assert.Fail(t, "unexpected event", metricEvent.Key)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestIsSet(t *testing.T) {
+	assert.False(t, IsSet(int32(0)))
+	assert.False(t, IsSet(false))
+	assert.False(t, IsSet(nil))
+	assert.False(t, IsSet(""))
+	assert.True(t, IsSet(int32(1)))
+	assert.True(t, IsSet(uint64(2)))
+	assert.True(t, IsSet(true))
+}
+
+func TestCounters(t *testing.T) {
+	store := NewMetricsStore()
+	ctx := context.Background()
+
+	assert.Equal(t, Gauge, store.Kind("foo.Tot"))
+	_ = store.Set(ctx, 123, "foo.Tot", uint64(5))
+	count, err := store.Add(ctx, 123, "foo.Tot", 2)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(7), count)
+	assert.Equal(t, Counter, store.Kind("foo.Tot"))
+	_, _ = store.Add(ctx, 321, "foo.Tot", 1)
+
+	// Counters never decrease unless reset
+	assert.True(t, errors.IsInvalid(store.Set(ctx, 123, "foo.Tot", uint64(6))))
+	assert.True(t, errors.IsInvalid(store.Set(ctx, 123, "foo.Tot", 8.0)))
+	assert.NoError(t, store.Set(ctx, 123, "foo.Tot", uint64(8)))
+	v, _ := store.Get(ctx, 123, "foo.Tot")
+	assert.Equal(t, uint64(8), v)
+
+	store.Declare("bar", Gauge)
+	_, err = store.Add(ctx, 123, "bar", 1)
+	assert.True(t, errors.IsInvalid(err))
+	assert.True(t, errors.IsInvalid(store.Reset(ctx, 123, "bar")))
+	_ = store.Set(ctx, 123, "bar", 3.14)
+
+	assert.NoError(t, store.Reset(ctx, 123))
+	v, _ = store.Get(ctx, 123, "foo.Tot")
+	assert.Equal(t, uint64(0), v)
+	v, _ = store.Get(ctx, 123, "bar")
+	assert.Equal(t, 3.14, v)
+	v, _ = store.Get(ctx, 321, "foo.Tot")
+	assert.Equal(t, uint64(1), v)
+	assert.NoError(t, store.Reset(ctx, 321, "foo.Tot"))
+	v, _ = store.Get(ctx, 321, "foo.Tot")
+	assert.Equal(t, uint64(0), v)
+}
@@ -25,3 +25,17 @@ type Key struct {
 	EntityID uint64
 	Name     string
 }
+
+// Kind is the semantics of the values of a metric
+type Kind int
+
+const (
+	// Gauge metrics hold the current value of a quantity, which may go up and down
+	Gauge Kind = iota
+	// Counter metrics are cumulative uint64 counts, which only decrease when reset to zero
+	Counter
+)
+
+func (k Kind) String() string {
+	return [...]string{"Gauge", "Counter"}[k]
+}
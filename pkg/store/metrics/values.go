@@ -0,0 +1,18 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package metrics
+
+import "fmt"
+
+// IsSet returns true if the specified flag-like metric value is neither zero, false nor empty;
+// such values may arrive as integers, enums, booleans or strings depending on whether they
+// were set via the metrics API or via RC control
+func IsSet(value interface{}) bool {
+	switch fmt.Sprintf("%v", value) {
+	case "", "0", "false", "<nil>":
+		return false
+	}
+	return true
+}
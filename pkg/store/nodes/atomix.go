@@ -0,0 +1,187 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package nodes
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+
+	"github.com/onosproject/onos-api/go/onos/ransim/types"
+	"github.com/onosproject/ran-simulator/pkg/model"
+	"github.com/onosproject/ran-simulator/pkg/store/distributed"
+)
+
+// atomixStore is a node store whose nodes are mirrored in a distributed map; the in-memory store serves as
+// the local cache of the nodes simulated by this instance
+type atomixStore struct {
+	*store
+	entries *distributed.Map
+	owns    func(enbID types.EnbID) bool
+}
+
+// NewAtomixNodeRegistry creates a new node store backed by the specified distributed map. The nodes owned by
+// this instance are restored from the map, if any; otherwise the map is primed with the specified nodes.
+// Changes of owned nodes made by other instances are applied to the store as they occur.
+func NewAtomixNodeRegistry(ctx context.Context, entries *distributed.Map, nodes map[string]model.Node, owns func(enbID types.EnbID) bool) (Store, error) {
+	s := &atomixStore{
+		store:   NewNodeRegistry(nil).(*store),
+		entries: entries,
+		owns:    owns,
+	}
+
+	restored := make(map[string]model.Node)
+	err := entries.List(ctx, func(key string, value []byte) error {
+		node := model.Node{}
+		if err := json.Unmarshal(value, &node); err != nil {
+			return err
+		}
+		if owns(node.EnbID) {
+			restored[key] = node
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(restored) > 0 {
+		log.Infof("Restored %d nodes from the distributed store", len(restored))
+		s.store.Load(ctx, restored)
+	} else {
+		s.Load(ctx, nodes)
+	}
+
+	if err := entries.Watch(s.apply); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func nodeKey(enbID types.EnbID) string {
+	return strconv.FormatUint(uint64(enbID), 10)
+}
+
+// put mirrors the present state of the specified node
+func (s *atomixStore) put(ctx context.Context, enbID types.EnbID) error {
+	s.store.mu.RLock()
+	node, ok := s.store.nodes[enbID]
+	if !ok {
+		s.store.mu.RUnlock()
+		return nil
+	}
+	value, err := json.Marshal(node)
+	s.store.mu.RUnlock()
+	if err != nil {
+		return err
+	}
+	return s.entries.Put(ctx, nodeKey(enbID), value)
+}
+
+// apply applies the change of a node made by another instance to the local cache
+func (s *atomixStore) apply(key string, value []byte) {
+	ctx := context.Background()
+	if value == nil {
+		enbID, err := strconv.ParseUint(key, 10, 64)
+		if err == nil {
+			_, _ = s.store.Delete(ctx, types.EnbID(enbID))
+		}
+		return
+	}
+	node := &model.Node{}
+	if err := json.Unmarshal(value, node); err != nil {
+		log.Warnf("Unable to decode node %s: %v", key, err)
+		return
+	}
+	if !s.owns(node.EnbID) {
+		return
+	}
+	if err := s.store.Update(ctx, node); err != nil {
+		_ = s.store.Add(ctx, node)
+	}
+}
+
+// Load add all nodes from the specified node map; no events will be generated
+func (s *atomixStore) Load(ctx context.Context, nodes map[string]model.Node) {
+	s.store.Load(ctx, nodes)
+	for _, node := range nodes {
+		if err := s.put(ctx, node.EnbID); err != nil {
+			log.Warnf("Unable to store node %d: %v", node.EnbID, err)
+		}
+	}
+}
+
+// Clear removes all nodes; no events will be generated
+func (s *atomixStore) Clear(ctx context.Context) {
+	list, _ := s.store.List(ctx)
+	s.store.Clear(ctx)
+	for _, node := range list {
+		if err := s.entries.Remove(ctx, nodeKey(node.EnbID)); err != nil {
+			log.Warnf("Unable to remove node %d: %v", node.EnbID, err)
+		}
+	}
+}
+
+// Add adds a new node
+func (s *atomixStore) Add(ctx context.Context, node *model.Node) error {
+	if err := s.store.Add(ctx, node); err != nil {
+		return err
+	}
+	return s.put(ctx, node.EnbID)
+}
+
+// Update updates a node
+func (s *atomixStore) Update(ctx context.Context, node *model.Node) error {
+	if err := s.store.Update(ctx, node); err != nil {
+		return err
+	}
+	return s.put(ctx, node.EnbID)
+}
+
+// Delete deletes a node
+func (s *atomixStore) Delete(ctx context.Context, enbID types.EnbID) (*model.Node, error) {
+	node, err := s.store.Delete(ctx, enbID)
+	if err != nil {
+		return nil, err
+	}
+	return node, s.entries.Remove(ctx, nodeKey(enbID))
+}
+
+// SetStatus changes the E2 node agent status value
+func (s *atomixStore) SetStatus(ctx context.Context, enbID types.EnbID, status string) error {
+	if err := s.store.SetStatus(ctx, enbID, status); err != nil {
+		return err
+	}
+	return s.put(ctx, enbID)
+}
+
+// SetConnectionState changes the state of the connection of the E2 node to the specified controller
+func (s *atomixStore) SetConnectionState(ctx context.Context, enbID types.EnbID, controller string, state model.ConnectionState) error {
+	if err := s.store.SetConnectionState(ctx, enbID, controller, state); err != nil {
+		return err
+	}
+	return s.put(ctx, enbID)
+}
+
+// PruneCell prunes a cell
+func (s *atomixStore) PruneCell(ctx context.Context, ecgi types.ECGI) error {
+	var pruned []types.EnbID
+	list, _ := s.store.List(ctx)
+	for _, node := range list {
+		for _, e := range node.Cells {
+			if e == ecgi {
+				pruned = append(pruned, node.EnbID)
+			}
+		}
+	}
+	if err := s.store.PruneCell(ctx, ecgi); err != nil {
+		return err
+	}
+	for _, enbID := range pruned {
+		if err := s.put(ctx, enbID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
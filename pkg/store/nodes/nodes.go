@@ -7,8 +7,7 @@ package nodes
 import (
 	"context"
 	"sync"
-
-	"github.com/google/uuid"
+	"time"
 
 	"github.com/onosproject/ran-simulator/pkg/store/event"
 
@@ -48,6 +47,10 @@ type Store interface {
 	// SetsStatus changes the E2 node agent status value
 	SetStatus(ctx context.Context, enbID types.EnbID, status string) error
 
+	// SetConnectionState changes the state of the connection of the E2 node to the specified controller, emitting
+	// an update event of the node
+	SetConnectionState(ctx context.Context, enbID types.EnbID, controller string, state model.ConnectionState) error
+
 	// PruneCell  the node that has the specified cell
 	PruneCell(ctx context.Context, ecgi types.ECGI) error
 
@@ -64,6 +67,8 @@ type WatchOptions struct {
 	Monitor bool
 }
 
+// store holds the nodes as copies which are replaced rather than modified, so that the nodes it returns can be read
+// without holding its lock; events carry copies of their own
 type store struct {
 	mu       sync.RWMutex
 	nodes    map[types.EnbID]*model.Node
@@ -73,7 +78,7 @@ type store struct {
 // NewNodeRegistry creates a new store abstraction from the specified fixed node map.
 func NewNodeRegistry(nodes map[string]model.Node) Store {
 	log.Infof("Creating registry from model with %d nodes", len(nodes))
-	watchers := watcher.NewWatchers()
+	watchers := watcher.NewWatchers("nodes")
 	reg := &store{
 		mu:       sync.RWMutex{},
 		nodes:    make(map[types.EnbID]*model.Node),
@@ -115,10 +120,10 @@ func (s *store) Add(ctx context.Context, node *model.Node) error {
 		return errors.New(errors.NotFound, "node with EnbID already exists")
 	}
 
-	s.nodes[node.EnbID] = node
+	s.nodes[node.EnbID] = clone(node)
 	addEvent := event.Event{
 		Key:   node.EnbID,
-		Value: node,
+		Value: clone(node),
 		Type:  Created,
 	}
 	s.watchers.Send(addEvent)
@@ -144,10 +149,10 @@ func (s *store) Update(ctx context.Context, node *model.Node) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 	if _, ok := s.nodes[node.EnbID]; ok {
-		s.nodes[node.EnbID] = node
+		s.nodes[node.EnbID] = clone(node)
 		updateEvent := event.Event{
 			Key:   node.EnbID,
-			Value: node,
+			Value: clone(node),
 			Type:  Updated,
 		}
 
@@ -165,10 +170,12 @@ func (s *store) PruneCell(ctx context.Context, ecgi types.ECGI) error {
 	for _, node := range s.nodes {
 		for i, e := range node.Cells {
 			if e == ecgi {
-				node.Cells = removeECGI(node.Cells, i)
+				updated := clone(node)
+				updated.Cells = removeECGI(updated.Cells, i)
+				s.nodes[node.EnbID] = updated
 				updateEvent := event.Event{
 					Key:   node.EnbID,
-					Value: node,
+					Value: clone(updated),
 					Type:  Updated,
 				}
 				s.watchers.Send(updateEvent)
@@ -179,14 +186,53 @@ func (s *store) PruneCell(ctx context.Context, ecgi types.ECGI) error {
 	return nil
 }
 
+// SetStatus changes the status of the node; the node is replaced by a changed copy rather than modified, as the
+// node may be read concurrently
 func (s *store) SetStatus(ctx context.Context, enbID types.EnbID, status string) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
-	if node, ok := s.nodes[enbID]; ok {
-		node.Status = status
-		return nil
+	node, ok := s.nodes[enbID]
+	if !ok {
+		return errors.New(errors.NotFound, "node not found")
 	}
-	return errors.New(errors.NotFound, "node not found")
+	updated := clone(node)
+	updated.Status = status
+	s.nodes[enbID] = updated
+	return nil
+}
+
+// SetConnectionState changes the state of the connection of the node to the controller; the node is replaced by a
+// changed copy rather than modified, as the node may be read concurrently
+func (s *store) SetConnectionState(ctx context.Context, enbID types.EnbID, controller string, state model.ConnectionState) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	node, ok := s.nodes[enbID]
+	if !ok {
+		return errors.New(errors.NotFound, "node not found")
+	}
+	updated := clone(node)
+	updated.Connections[controller] = model.Connection{State: state, Since: time.Now()}
+	s.nodes[enbID] = updated
+	s.watchers.Send(event.Event{
+		Key:   updated.EnbID,
+		Value: clone(updated),
+		Type:  Updated,
+	})
+	return nil
+}
+
+// clone returns a copy of the node whose slices and connections can be changed without affecting the node
+func clone(node *model.Node) *model.Node {
+	c := *node
+	c.Controllers = append([]string(nil), node.Controllers...)
+	c.ServiceModels = append([]string(nil), node.ServiceModels...)
+	c.Cells = append([]types.ECGI(nil), node.Cells...)
+	c.RanFunctions = append([]string(nil), node.RanFunctions...)
+	c.Connections = make(map[string]model.Connection, len(node.Connections)+1)
+	for id, connection := range node.Connections {
+		c.Connections[id] = connection
+	}
+	return &c
 }
 
 // Delete deletes a node
@@ -198,7 +244,7 @@ func (s *store) Delete(ctx context.Context, enbID types.EnbID) (*model.Node, err
 		delete(s.nodes, enbID)
 		deleteEvent := event.Event{
 			Key:   node.EnbID,
-			Value: node,
+			Value: clone(node),
 			Type:  Deleted,
 		}
 		s.watchers.Send(deleteEvent)
@@ -211,28 +257,20 @@ func (s *store) Delete(ctx context.Context, enbID types.EnbID) (*model.Node, err
 func (s *store) Watch(ctx context.Context, ch chan<- event.Event, options ...WatchOptions) error {
 	log.Debug("Watching node changes")
 	replay := len(options) > 0 && options[0].Replay
-	id := uuid.New()
-	err := s.watchers.AddWatcher(id, ch)
-	if err != nil {
+	if err := s.watchers.Watch(ctx, ch, nil); err != nil {
 		log.Error(err)
-		close(ch)
 		return err
 	}
-	go func() {
-		<-ctx.Done()
-		err = s.watchers.RemoveWatcher(id)
-		if err != nil {
-			log.Error(err)
-		}
-		close(ch)
-	}()
 
 	if replay {
-		wg := sync.WaitGroup{}
-		wg.Add(1)
+		s.mu.RLock()
+		replayed := make([]*model.Node, 0, len(s.nodes))
+		for _, node := range s.nodes {
+			replayed = append(replayed, clone(node))
+		}
+		s.mu.RUnlock()
 		go func() {
-			defer wg.Done()
-			for _, node := range s.nodes {
+			for _, node := range replayed {
 				ch <- event.Event{
 					Key:   node.EnbID,
 					Value: node,
@@ -257,6 +295,8 @@ func (s *store) List(ctx context.Context) ([]*model.Node, error) {
 
 // Len number of nodes
 func (s *store) Len(ctx context.Context) (int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
 	return len(s.nodes), nil
 }
 
@@ -7,8 +7,10 @@ package nodes
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/onosproject/onos-api/go/onos/ransim/types"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
 	"github.com/onosproject/ran-simulator/pkg/model"
 	"github.com/onosproject/ran-simulator/pkg/store/event"
 
@@ -70,3 +72,45 @@ func TestNodes(t *testing.T) {
 	ids, _ := nodeStore.List(ctx)
 	assert.Equal(t, 0, len(ids), "should be empty")
 }
+
+func TestConnectionState(t *testing.T) {
+	m := &model.Model{}
+	assert.NoError(t, model.LoadConfig(m, "../../model/test"))
+	ctx := context.Background()
+	nodeStore := NewNodeRegistry(m.Nodes)
+	enbID := types.EnbID(144470)
+	node, err := nodeStore.Get(ctx, enbID)
+	assert.NoError(t, err)
+	assert.Empty(t, node.Connections)
+	assert.False(t, node.IsConnected())
+
+	ch := make(chan event.Event)
+	assert.NoError(t, nodeStore.Watch(ctx, ch))
+	before := time.Now()
+	assert.NoError(t, nodeStore.SetConnectionState(ctx, enbID, node.Controllers[0], model.ConnectionConnecting))
+	nodeEvent := <-ch
+	assert.Equal(t, Updated, nodeEvent.Type.(NodeEvent))
+	updated := nodeEvent.Value.(*model.Node)
+	assert.False(t, updated.IsConnected())
+	connection := updated.Connections[node.Controllers[0]]
+	assert.Equal(t, model.ConnectionConnecting, connection.State)
+	assert.False(t, connection.Since.Before(before))
+
+	// Nodes are replaced by changed copies, leaving the nodes obtained before unchanged
+	assert.Empty(t, node.Connections)
+	assert.NoError(t, nodeStore.SetConnectionState(ctx, enbID, node.Controllers[0], model.ConnectionConnected))
+	<-ch
+	assert.False(t, updated.IsConnected())
+	current, err := nodeStore.Get(ctx, enbID)
+	assert.NoError(t, err)
+	assert.True(t, current.IsConnected())
+	assert.True(t, errors.IsNotFound(nodeStore.SetConnectionState(ctx, 1, node.Controllers[0], model.ConnectionConnected)))
+
+	status := current.Status
+	assert.NoError(t, nodeStore.SetStatus(ctx, enbID, "Running"))
+	assert.Equal(t, status, current.Status)
+	current, err = nodeStore.Get(ctx, enbID)
+	assert.NoError(t, err)
+	assert.Equal(t, "Running", current.Status)
+	assert.True(t, current.IsConnected())
+}
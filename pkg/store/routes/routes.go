@@ -8,7 +8,6 @@ import (
 	"context"
 	"sync"
 
-	"github.com/google/uuid"
 	"github.com/onosproject/ran-simulator/pkg/store/watcher"
 
 	"github.com/onosproject/ran-simulator/pkg/store/event"
@@ -60,7 +59,7 @@ type store struct {
 // NewRouteRegistry creates a new route registry
 func NewRouteRegistry() Store {
 	log.Infof("Creating route registry")
-	watchers := watcher.NewWatchers()
+	watchers := watcher.NewWatchers("routes")
 	store := &store{
 		mu:       sync.RWMutex{},
 		routes:   make(map[types.IMSI]*model.Route),
@@ -142,21 +141,10 @@ func (s *store) Watch(ctx context.Context, ch chan<- event.Event, options ...Wat
 	log.Debug("Watching route changes")
 	replay := len(options) > 0 && options[0].Replay
 
-	id := uuid.New()
-	err := s.watchers.AddWatcher(id, ch)
-	if err != nil {
+	if err := s.watchers.Watch(ctx, ch, nil); err != nil {
 		log.Error(err)
-		close(ch)
 		return err
 	}
-	go func() {
-		<-ctx.Done()
-		err = s.watchers.RemoveWatcher(id)
-		if err != nil {
-			log.Error(err)
-		}
-		close(ch)
-	}()
 
 	if replay {
 		wg := sync.WaitGroup{}
@@ -0,0 +1,111 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package subscriptions
+
+import (
+	"context"
+	"sort"
+	"time"
+)
+
+// reportLoop is a running goroutine reporting the indications of a subscription
+type reportLoop struct {
+	id     ID
+	cancel context.CancelFunc
+}
+
+// Audit is the result of cross-checking the stored subscriptions against the running report loops and
+// against the subscriptions known to E2T
+type Audit struct {
+	// Stale are the stored subscriptions without a running report loop
+	Stale []ID `json:"stale"`
+	// Leaked are the subscriptions with a running report loop that are no longer stored
+	Leaked []ID `json:"leaked"`
+	// UnknownToE2T are the stored subscriptions E2T does not know about
+	UnknownToE2T []ID `json:"unknownToE2T"`
+	// MissingLocally are the subscriptions known to E2T that are not stored
+	MissingLocally []ID `json:"missingLocally"`
+}
+
+// Clean returns true if no inconsistency has been found
+func (a Audit) Clean() bool {
+	return len(a.Stale) == 0 && len(a.Leaked) == 0 && len(a.UnknownToE2T) == 0 && len(a.MissingLocally) == 0
+}
+
+// StartReport registers a report loop of the specified subscription; the returned context is cancelled by
+// StopReports and the returned function must be called once the loop ends
+func (s *Subscriptions) StartReport(ctx context.Context, id ID) (context.Context, func()) {
+	ctx, cancel := context.WithCancel(ctx)
+	loop := &reportLoop{id: id, cancel: cancel}
+	s.mu.Lock()
+	s.reports[loop] = struct{}{}
+	s.mu.Unlock()
+	return ctx, func() {
+		cancel()
+		s.mu.Lock()
+		delete(s.reports, loop)
+		s.mu.Unlock()
+	}
+}
+
+// StopReports cancels the report loops of the specified subscription
+func (s *Subscriptions) StopReports(id ID) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for loop := range s.reports {
+		if loop.id == id {
+			loop.cancel()
+		}
+	}
+}
+
+// Audit cross-checks the stored subscriptions against the running report loops and, unless nil, against
+// the given subscriptions known to E2T; subscriptions created after the audit started are not audited, as their
+// report loops may not be running yet and E2T may have listed its subscriptions before
+func (s *Subscriptions) Audit(known []ID, started time.Time) Audit {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	audit := Audit{}
+	running := make(map[ID]bool)
+	for loop := range s.reports {
+		if !running[loop.id] {
+			running[loop.id] = true
+			if _, ok := s.subscriptions[loop.id]; !ok {
+				audit.Leaked = append(audit.Leaked, loop.id)
+			}
+		}
+	}
+	for id, sub := range s.subscriptions {
+		if !running[id] && !sub.Created.After(started) {
+			audit.Stale = append(audit.Stale, id)
+		}
+	}
+	if known != nil {
+		knownToE2T := make(map[ID]bool)
+		for _, id := range known {
+			knownToE2T[id] = true
+			if _, ok := s.subscriptions[id]; !ok {
+				audit.MissingLocally = append(audit.MissingLocally, id)
+			}
+		}
+		for id, sub := range s.subscriptions {
+			if !knownToE2T[id] && !sub.Created.After(started) {
+				audit.UnknownToE2T = append(audit.UnknownToE2T, id)
+			}
+		}
+	}
+	sortIDs(audit.Stale)
+	sortIDs(audit.Leaked)
+	sortIDs(audit.UnknownToE2T)
+	sortIDs(audit.MissingLocally)
+	return audit
+}
+
+func sortIDs(ids []ID) {
+	sort.Slice(ids, func(i, j int) bool {
+		return ids[i] < ids[j]
+	})
+}
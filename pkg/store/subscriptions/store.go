@@ -5,11 +5,13 @@
 package subscriptions
 
 import (
+	"bytes"
 	"fmt"
 	"sync"
 	"time"
 
 	"github.com/onosproject/onos-e2t/pkg/protocols/e2ap101"
+	"github.com/onosproject/onos-e2t/pkg/southbound/e2ap101/types"
 
 	"github.com/onosproject/onos-lib-go/pkg/errors"
 
@@ -28,6 +30,46 @@ type Subscription struct {
 	Details   *e2appducontents.RicsubscriptionDetails
 	E2Channel e2.ClientChannel
 	Ticker    *time.Ticker
+	// Actions are the actions accepted by the service model, each reported independently
+	Actions []*Action
+	// Created is the time the subscription was requested
+	Created time.Time
+}
+
+// Action is an accepted action of a subscription
+type Action struct {
+	ID   types.RicActionID
+	Type e2apies.RicactionType
+	// Definition is the ASN.1 encoded action definition; empty if none was given
+	Definition []byte
+}
+
+// AcceptActions records the specified actions of the subscription request as accepted
+func (s *Subscription) AcceptActions(actionIDs []*types.RicActionID) {
+	s.Actions = make([]*Action, 0, len(actionIDs))
+	for _, item := range s.Details.GetRicActionToBeSetupList().GetValue() {
+		action := item.GetValue()
+		for _, actionID := range actionIDs {
+			if types.RicActionID(action.GetRicActionId().GetValue()) == *actionID {
+				s.Actions = append(s.Actions, &Action{
+					ID:         *actionID,
+					Type:       action.GetRicActionType(),
+					Definition: action.GetRicActionDefinition().GetValue(),
+				})
+			}
+		}
+	}
+}
+
+// ReportActions returns the accepted report actions
+func (s *Subscription) ReportActions() []*Action {
+	actions := make([]*Action, 0, len(s.Actions))
+	for _, action := range s.Actions {
+		if action.Type == e2apies.RicactionType_RICACTION_TYPE_REPORT {
+			actions = append(actions, action)
+		}
+	}
+	return actions
 }
 
 // NewID returns the locally unique ID for the specified subscription add/delete request
@@ -46,21 +88,46 @@ func NewSubscription(id ID, e2apsub *e2appducontents.RicsubscriptionRequest, ch
 		FnID:      e2apsub.ProtocolIes.E2ApProtocolIes5.Value,
 		Details:   e2apsub.ProtocolIes.E2ApProtocolIes30.Value,
 		E2Channel: ch,
+		Created:   time.Now(),
 	}, nil
 }
 
+// sameDetails returns true if the subscription details have the same event trigger and actions
+func sameDetails(a, b *e2appducontents.RicsubscriptionDetails) bool {
+	if !bytes.Equal(a.GetRicEventTriggerDefinition().GetValue(), b.GetRicEventTriggerDefinition().GetValue()) {
+		return false
+	}
+	actionsA, actionsB := a.GetRicActionToBeSetupList().GetValue(), b.GetRicActionToBeSetupList().GetValue()
+	if len(actionsA) != len(actionsB) {
+		return false
+	}
+	for i := range actionsA {
+		actionA, actionB := actionsA[i].GetValue(), actionsB[i].GetValue()
+		if actionA.GetRicActionId().GetValue() != actionB.GetRicActionId().GetValue() ||
+			actionA.GetRicActionType() != actionB.GetRicActionType() ||
+			!bytes.Equal(actionA.GetRicActionDefinition().GetValue(), actionB.GetRicActionDefinition().GetValue()) {
+			return false
+		}
+	}
+	return true
+}
+
 // NewStore creates a new subscription store
 func NewStore() *Subscriptions {
 	return &Subscriptions{
 		subscriptions: make(map[ID]*Subscription),
+		reports:       make(map[*reportLoop]struct{}),
 		mu:            sync.RWMutex{},
 	}
 }
 
 // Store store interface
 type Store interface {
-	// Add   adds the specified subscription
+	// Add   adds the specified subscription unless it duplicates a stored subscription
 	Add(subscription *Subscription) error
+	// AddLimited adds the specified subscription unless it duplicates a stored subscription or the store already
+	// holds the given number of subscriptions
+	AddLimited(subscription *Subscription, limit int) error
 	// Remove removes the specified subscription
 	Remove(id ID) error
 	// Get gets a subscription based on a given ID
@@ -74,25 +141,60 @@ type Store interface {
 // Subscriptions data structure for storing subscriptions
 type Subscriptions struct {
 	subscriptions map[ID]*Subscription
+	reports       map[*reportLoop]struct{}
 	mu            sync.RWMutex
 }
 
 // Len number of subscriptions
 func (s *Subscriptions) Len() (int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
 	return len(s.subscriptions), nil
 }
 
-// Add adds the specified subscription
+// Add adds the specified subscription unless it duplicates a stored subscription
 func (s *Subscriptions) Add(sub *Subscription) error {
+	return s.AddLimited(sub, 0)
+}
+
+// AddLimited adds the specified subscription unless it duplicates a stored subscription, in which case an
+// AlreadyExists error is returned, or the store already holds limit subscriptions, in which case a Forbidden error is
+// returned; the checks and the insertion are atomic, and a limit of zero means no limit
+func (s *Subscriptions) AddLimited(sub *Subscription, limit int) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 	if sub.ID == "" {
 		return errors.New(errors.Invalid, "Subscription ID cannot be empty")
 	}
+	if existing, ok := s.duplicate(sub); ok {
+		return errors.New(errors.AlreadyExists, "subscription %s duplicates subscription %s", sub.ID, existing.ID)
+	}
+	if limit > 0 && len(s.subscriptions) >= limit {
+		return errors.New(errors.Forbidden, "limit of %d subscriptions reached", limit)
+	}
 	s.subscriptions[sub.ID] = sub
 	return nil
 }
 
+// duplicate returns the stored subscription the specified subscription duplicates, i.e. either with the same ID or
+// installed by the same RIC requester for the same RAN function with identical event trigger and actions
+func (s *Subscriptions) duplicate(sub *Subscription) (*Subscription, bool) {
+	if existing, ok := s.subscriptions[sub.ID]; ok {
+		return existing, true
+	}
+	if sub.ReqID == nil {
+		return nil, false
+	}
+	for _, existing := range s.subscriptions {
+		if existing.ReqID.GetRicRequestorId() == sub.ReqID.GetRicRequestorId() &&
+			existing.FnID.GetValue() == sub.FnID.GetValue() &&
+			sameDetails(existing.Details, sub.Details) {
+			return existing, true
+		}
+	}
+	return nil, false
+}
+
 // Remove removes the specified subscription
 func (s *Subscriptions) Remove(id ID) error {
 	s.mu.Lock()
@@ -5,9 +5,15 @@
 package subscriptions
 
 import (
+	"context"
 	"testing"
+	"time"
 
+	e2ap_commondatatypes "github.com/onosproject/onos-e2t/api/e2ap/v1beta2/e2ap-commondatatypes"
 	e2apies "github.com/onosproject/onos-e2t/api/e2ap/v1beta2/e2ap-ies"
+	e2appducontents "github.com/onosproject/onos-e2t/api/e2ap/v1beta2/e2ap-pdu-contents"
+	"github.com/onosproject/onos-e2t/pkg/southbound/e2ap101/types"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -69,3 +75,124 @@ func TestSubscriptions(t *testing.T) {
 	assert.Equal(t, 1, len(subscriptionList))
 
 }
+
+func TestAcceptActions(t *testing.T) {
+	action := func(id int32, actionType e2apies.RicactionType, definition []byte) *e2appducontents.RicactionToBeSetupItemIes {
+		item := &e2appducontents.RicactionToBeSetupItem{
+			RicActionId:   &e2apies.RicactionId{Value: id},
+			RicActionType: actionType,
+		}
+		if definition != nil {
+			item.RicActionDefinition = &e2ap_commondatatypes.RicactionDefinition{Value: definition}
+		}
+		return &e2appducontents.RicactionToBeSetupItemIes{Value: item}
+	}
+	sub := &Subscription{
+		ID: "sub1",
+		Details: &e2appducontents.RicsubscriptionDetails{
+			RicActionToBeSetupList: &e2appducontents.RicactionsToBeSetupList{
+				Value: []*e2appducontents.RicactionToBeSetupItemIes{
+					action(1, e2apies.RicactionType_RICACTION_TYPE_REPORT, []byte{0x01}),
+					action(2, e2apies.RicactionType_RICACTION_TYPE_POLICY, nil),
+					action(3, e2apies.RicactionType_RICACTION_TYPE_INSERT, nil),
+					action(4, e2apies.RicactionType_RICACTION_TYPE_REPORT, nil),
+				},
+			},
+		},
+	}
+
+	// Only the accepted actions are tracked, each with its own definition
+	accepted := []types.RicActionID{1, 3, 4}
+	sub.AcceptActions([]*types.RicActionID{&accepted[0], &accepted[1], &accepted[2]})
+	assert.Len(t, sub.Actions, 3)
+	assert.Equal(t, []byte{0x01}, sub.Actions[0].Definition)
+	assert.Empty(t, sub.Actions[2].Definition)
+
+	reports := sub.ReportActions()
+	assert.Len(t, reports, 2)
+	assert.Equal(t, types.RicActionID(1), reports[0].ID)
+	assert.Equal(t, types.RicActionID(4), reports[1].ID)
+}
+
+func TestAudit(t *testing.T) {
+	subStore := NewStore()
+	assert.NoError(t, subStore.Add(&Subscription{ID: "1-1-2"}))
+	assert.NoError(t, subStore.Add(&Subscription{ID: "1-2-2"}))
+
+	ctx, done := subStore.StartReport(context.Background(), "1-1-2")
+	defer done()
+	leakedCtx, leakedDone := subStore.StartReport(context.Background(), "1-3-2")
+	defer leakedDone()
+
+	audit := subStore.Audit(nil, time.Now())
+	assert.Equal(t, []ID{"1-2-2"}, audit.Stale)
+	assert.Equal(t, []ID{"1-3-2"}, audit.Leaked)
+	assert.Empty(t, audit.UnknownToE2T)
+	assert.Empty(t, audit.MissingLocally)
+	assert.False(t, audit.Clean())
+
+	audit = subStore.Audit([]ID{"1-1-2", "1-4-2"}, time.Now())
+	assert.Equal(t, []ID{"1-2-2"}, audit.UnknownToE2T)
+	assert.Equal(t, []ID{"1-4-2"}, audit.MissingLocally)
+
+	// Subscriptions created after the audit started are not audited
+	assert.NoError(t, subStore.Add(&Subscription{ID: "1-5-2", Created: time.Now().Add(time.Minute)}))
+	audit = subStore.Audit([]ID{"1-1-2", "1-4-2"}, time.Now())
+	assert.Equal(t, []ID{"1-2-2"}, audit.Stale)
+	assert.Equal(t, []ID{"1-2-2"}, audit.UnknownToE2T)
+	assert.NoError(t, subStore.Remove("1-5-2"))
+
+	// Stopping the reports cancels only the loops of the specified subscription
+	subStore.StopReports("1-3-2")
+	assert.Error(t, leakedCtx.Err())
+	assert.NoError(t, ctx.Err())
+
+	// Ended loops are deregistered
+	leakedDone()
+	assert.NoError(t, subStore.Remove("1-2-2"))
+	assert.True(t, subStore.Audit(nil, time.Now()).Clean())
+}
+
+func TestDuplicate(t *testing.T) {
+	details := func(trigger byte, definition byte) *e2appducontents.RicsubscriptionDetails {
+		return &e2appducontents.RicsubscriptionDetails{
+			RicEventTriggerDefinition: &e2ap_commondatatypes.RiceventTriggerDefinition{Value: []byte{trigger}},
+			RicActionToBeSetupList: &e2appducontents.RicactionsToBeSetupList{
+				Value: []*e2appducontents.RicactionToBeSetupItemIes{{
+					Value: &e2appducontents.RicactionToBeSetupItem{
+						RicActionId:         &e2apies.RicactionId{Value: 1},
+						RicActionType:       e2apies.RicactionType_RICACTION_TYPE_REPORT,
+						RicActionDefinition: &e2ap_commondatatypes.RicactionDefinition{Value: []byte{definition}},
+					},
+				}},
+			},
+		}
+	}
+	subscription := func(instanceID int32, requestorID int32, fnID int32, details *e2appducontents.RicsubscriptionDetails) *Subscription {
+		return &Subscription{
+			ID:      NewID(instanceID, requestorID, fnID),
+			ReqID:   &e2apies.RicrequestId{RicRequestorId: requestorID, RicInstanceId: instanceID},
+			FnID:    &e2apies.RanfunctionId{Value: fnID},
+			Details: details,
+		}
+	}
+	subStore := NewStore()
+	sub := subscription(1, 1, 2, details(1, 1))
+	assert.NoError(t, subStore.Add(sub))
+
+	// The limit of subscriptions is checked along with the insertion
+	assert.True(t, errors.IsForbidden(subStore.AddLimited(subscription(1, 3, 2, details(1, 1)), 1)))
+	assert.NoError(t, subStore.AddLimited(subscription(1, 3, 2, details(1, 1)), 2))
+	assert.NoError(t, subStore.Remove(NewID(1, 3, 2)))
+
+	// The same request ID or an identical subscription of the same requester is a duplicate
+	assert.True(t, errors.IsAlreadyExists(subStore.Add(subscription(1, 1, 2, details(2, 2)))))
+	assert.True(t, errors.IsAlreadyExists(subStore.Add(subscription(2, 1, 2, details(1, 1)))))
+
+	assert.NoError(t, subStore.Add(subscription(2, 1, 2, details(1, 2))))
+	assert.NoError(t, subStore.Add(subscription(2, 1, 3, details(1, 1))))
+	assert.NoError(t, subStore.Add(subscription(1, 2, 2, details(1, 1))))
+	count, err := subStore.Len()
+	assert.NoError(t, err)
+	assert.Equal(t, 4, count)
+}
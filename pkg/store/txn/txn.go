@@ -0,0 +1,75 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package txn
+
+import (
+	"context"
+	"sync"
+)
+
+// Txn is an update spanning several stores
+type Txn struct {
+	rollbacks []func()
+	mu        sync.Mutex
+	deferred  []func()
+}
+
+// txnKey is the context key of the transaction a change is made by
+type txnKey struct{}
+
+// OnRollback registers a function undoing a change made by the transaction; the registered functions are
+// called in reverse order if the transaction fails, and should make their changes in the context of the
+// transaction, so that their side effects are dropped along with those of the changes they undo
+func (tx *Txn) OnRollback(f func()) {
+	tx.rollbacks = append(tx.rollbacks, f)
+}
+
+// Defer defers the side effect of a change, e.g. sending its event or recording it in the journal, until the
+// transaction of the context commits, and drops it if the transaction is rolled back; outside of transactions, the
+// side effect takes effect immediately
+func Defer(ctx context.Context, f func()) {
+	tx, ok := ctx.Value(txnKey{}).(*Txn)
+	if !ok {
+		f()
+		return
+	}
+	tx.mu.Lock()
+	defer tx.mu.Unlock()
+	tx.deferred = append(tx.deferred, f)
+}
+
+// Transactions serializes updates spanning several stores
+type Transactions struct {
+	mu sync.Mutex
+}
+
+// NewTransactions creates a new transaction coordinator
+func NewTransactions() *Transactions {
+	return &Transactions{}
+}
+
+// Update runs the specified function as a transaction. The side effects of the changes the function makes in the
+// context passed to it, e.g. their events, are deferred until the function returns, so watchers observe either all
+// of its changes or none of them; the changes made by others in the meantime are not affected. If the function
+// fails, its changes are rolled back and their side effects dropped.
+func (t *Transactions) Update(ctx context.Context, f func(ctx context.Context, tx *Txn) error) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	tx := &Txn{}
+	if err := f(context.WithValue(ctx, txnKey{}, tx), tx); err != nil {
+		for i := len(tx.rollbacks) - 1; i >= 0; i-- {
+			tx.rollbacks[i]()
+		}
+		return err
+	}
+	tx.mu.Lock()
+	deferred := tx.deferred
+	tx.deferred = nil
+	tx.mu.Unlock()
+	for _, f := range deferred {
+		f()
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package txn
+
+import (
+	"context"
+	"testing"
+
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUpdate(t *testing.T) {
+	ctx := context.Background()
+	transactions := NewTransactions()
+	var effects []string
+	record := func(effect string) func() {
+		return func() {
+			effects = append(effects, effect)
+		}
+	}
+
+	// Side effects are deferred until the transaction completes, unless made outside of it
+	err := transactions.Update(ctx, func(txCtx context.Context, tx *Txn) error {
+		Defer(txCtx, record("a"))
+		Defer(ctx, record("other"))
+		Defer(txCtx, record("b"))
+		assert.Equal(t, []string{"other"}, effects)
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"other", "a", "b"}, effects)
+
+	// Failed transactions are rolled back without side effects, those of the rollback included
+	effects = nil
+	value := 1
+	err = transactions.Update(ctx, func(txCtx context.Context, tx *Txn) error {
+		value = 3
+		Defer(txCtx, record("set 3"))
+		tx.OnRollback(func() {
+			value = 1
+			Defer(txCtx, record("set 1"))
+		})
+		return errors.New(errors.Invalid, "failed")
+	})
+	assert.True(t, errors.IsInvalid(err))
+	assert.Equal(t, 1, value)
+	assert.Empty(t, effects)
+}
@@ -0,0 +1,283 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ues
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+
+	"github.com/onosproject/onos-api/go/onos/ransim/types"
+	"github.com/onosproject/ran-simulator/pkg/model"
+	"github.com/onosproject/ran-simulator/pkg/store/cells"
+	"github.com/onosproject/ran-simulator/pkg/store/distributed"
+	"github.com/onosproject/ran-simulator/pkg/store/event"
+)
+
+// atomixStore is a UE store whose UEs are mirrored in a distributed map; the in-memory store serves as the local
+// cache of the UEs served by the cells simulated by this instance
+type atomixStore struct {
+	*store
+	entries *distributed.Map
+	owns    func(ecgi types.ECGI) bool
+}
+
+// NewAtomixUERegistry creates a new UE store backed by the specified distributed map. The UEs served by cells owned
+// by this instance are restored from the map, if any; otherwise the specified number of UEs is created and placed.
+// UEs handed over to or from owned cells by other instances are added to or removed from the store as they occur.
+func NewAtomixUERegistry(ctx context.Context, entries *distributed.Map, count uint, cellStore cells.Store, placement model.PlacementConfig, owns func(ecgi types.ECGI) bool) (Store, error) {
+	s := &atomixStore{
+		store:   NewUERegistryWithPlacement(0, cellStore, placement).(*store),
+		entries: entries,
+		owns:    owns,
+	}
+
+	restored := 0
+	err := entries.List(ctx, func(key string, value []byte) error {
+		ue := &model.UE{}
+		if err := json.Unmarshal(value, ue); err != nil {
+			return err
+		}
+		if ue.Cell != nil && owns(ue.Cell.ECGI) {
+			s.store.mu.Lock()
+			s.store.ues[ue.IMSI] = ue
+			s.store.mu.Unlock()
+			restored++
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if restored > 0 {
+		log.Infof("Restored %d UEs from the distributed store", restored)
+	} else {
+		s.CreateUEs(ctx, count)
+	}
+
+	if err := entries.Watch(s.apply); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func ueKey(imsi types.IMSI) string {
+	return strconv.FormatUint(uint64(imsi), 10)
+}
+
+// put mirrors the present state of the specified UE
+func (s *atomixStore) put(ctx context.Context, imsi types.IMSI) error {
+	s.store.mu.RLock()
+	ue, ok := s.store.ues[imsi]
+	if !ok {
+		s.store.mu.RUnlock()
+		return nil
+	}
+	value, err := json.Marshal(ue)
+	s.store.mu.RUnlock()
+	if err != nil {
+		return err
+	}
+	return s.entries.Put(ctx, ueKey(imsi), value)
+}
+
+// imsis returns the IMSIs of all UEs in the local cache
+func (s *atomixStore) imsis() map[types.IMSI]bool {
+	s.store.mu.RLock()
+	defer s.store.mu.RUnlock()
+	imsis := make(map[types.IMSI]bool, len(s.store.ues))
+	for imsi := range s.store.ues {
+		imsis[imsi] = true
+	}
+	return imsis
+}
+
+// sync mirrors the UEs created and removed since the specified IMSIs were taken
+func (s *atomixStore) sync(ctx context.Context, before map[types.IMSI]bool) {
+	after := s.imsis()
+	for imsi := range after {
+		if !before[imsi] {
+			if err := s.put(ctx, imsi); err != nil {
+				log.Warnf("Unable to store UE %d: %v", imsi, err)
+			}
+		}
+	}
+	for imsi := range before {
+		if !after[imsi] {
+			if err := s.entries.Remove(ctx, ueKey(imsi)); err != nil {
+				log.Warnf("Unable to remove UE %d: %v", imsi, err)
+			}
+		}
+	}
+}
+
+// apply applies the change of a UE made by another instance to the local cache; UEs no longer served by
+// an owned cell are removed
+func (s *atomixStore) apply(key string, value []byte) {
+	ctx := context.Background()
+	imsi, err := strconv.ParseUint(key, 10, 64)
+	if err != nil {
+		return
+	}
+	ue := &model.UE{}
+	if value != nil {
+		if err := json.Unmarshal(value, ue); err != nil {
+			log.Warnf("Unable to decode UE %s: %v", key, err)
+			return
+		}
+	}
+	if value == nil || ue.Cell == nil || !s.owns(ue.Cell.ECGI) {
+		_, _ = s.store.Delete(ctx, types.IMSI(imsi))
+		return
+	}
+
+	s.store.mu.Lock()
+	cached, ok := s.store.ues[ue.IMSI]
+	if !ok {
+		s.store.mu.Unlock()
+		_ = s.store.Add(ctx, ue)
+		return
+	}
+	eventType := Updated
+	switch {
+	case cached.Cell == nil || cached.Cell.ECGI != ue.Cell.ECGI:
+		eventType = HandedOver
+	case !cached.IsAdmitted && ue.IsAdmitted:
+		eventType = Admitted
+	case cached.IsAdmitted && !ue.IsAdmitted:
+		eventType = Released
+	}
+	s.store.ues[ue.IMSI] = ue
+	s.store.mu.Unlock()
+	s.store.watchers.SendContext(ctx, event.Event{
+		Key:   ue.IMSI,
+		Value: ue,
+		Type:  eventType,
+	})
+}
+
+// SetUECount updates the UE count and creates or deletes new UEs as needed
+func (s *atomixStore) SetUECount(ctx context.Context, count uint) {
+	before := s.imsis()
+	s.store.SetUECount(ctx, count)
+	s.sync(ctx, before)
+}
+
+// CreateUEs creates the specified number of UEs
+func (s *atomixStore) CreateUEs(ctx context.Context, count uint) {
+	before := s.imsis()
+	s.store.CreateUEs(ctx, count)
+	s.sync(ctx, before)
+}
+
+// Add adds a UE with a given imsi
+func (s *atomixStore) Add(ctx context.Context, ue *model.UE) error {
+	if err := s.store.Add(ctx, ue); err != nil {
+		return err
+	}
+	return s.put(ctx, ue.IMSI)
+}
+
+// Delete deletes a UE based on a given imsi
+func (s *atomixStore) Delete(ctx context.Context, imsi types.IMSI) (*model.UE, error) {
+	ue, err := s.store.Delete(ctx, imsi)
+	if err != nil {
+		return nil, err
+	}
+	return ue, s.entries.Remove(ctx, ueKey(imsi))
+}
+
+func (s *atomixStore) MoveToCell(ctx context.Context, imsi types.IMSI, ecgi types.ECGI, strength float64) error {
+	if err := s.store.MoveToCell(ctx, imsi, ecgi, strength); err != nil {
+		return err
+	}
+	return s.put(ctx, imsi)
+}
+
+func (s *atomixStore) MoveToCoordinate(ctx context.Context, imsi types.IMSI, location model.Coordinate, heading uint32) error {
+	if err := s.store.MoveToCoordinate(ctx, imsi, location, heading); err != nil {
+		return err
+	}
+	return s.put(ctx, imsi)
+}
+
+func (s *atomixStore) UpdateRrcState(ctx context.Context, imsi types.IMSI, state model.RrcState) error {
+	if err := s.store.UpdateRrcState(ctx, imsi, state); err != nil {
+		return err
+	}
+	return s.put(ctx, imsi)
+}
+
+func (s *atomixStore) AdmitUE(ctx context.Context, imsi types.IMSI) error {
+	if err := s.store.AdmitUE(ctx, imsi); err != nil {
+		return err
+	}
+	return s.put(ctx, imsi)
+}
+
+func (s *atomixStore) ReleaseUE(ctx context.Context, imsi types.IMSI) error {
+	if err := s.store.ReleaseUE(ctx, imsi); err != nil {
+		return err
+	}
+	return s.put(ctx, imsi)
+}
+
+func (s *atomixStore) UpdateMeasurements(ctx context.Context, imsi types.IMSI, ecgi types.ECGI, strength float64, candidates []*model.UECell, measGaps bool, reports []*model.MeasReport) error {
+	if err := s.store.UpdateMeasurements(ctx, imsi, ecgi, strength, candidates, measGaps, reports); err != nil {
+		return err
+	}
+	return s.put(ctx, imsi)
+}
+
+func (s *atomixStore) UpdateRegistrationArea(ctx context.Context, imsi types.IMSI, tacs []uint32) error {
+	if err := s.store.UpdateRegistrationArea(ctx, imsi, tacs); err != nil {
+		return err
+	}
+	return s.put(ctx, imsi)
+}
+
+func (s *atomixStore) UpdateIndoor(ctx context.Context, imsi types.IMSI, indoor bool) error {
+	if err := s.store.UpdateIndoor(ctx, imsi, indoor); err != nil {
+		return err
+	}
+	return s.put(ctx, imsi)
+}
+
+func (s *atomixStore) AddDRB(ctx context.Context, imsi types.IMSI, drb *model.DRB) error {
+	if err := s.store.AddDRB(ctx, imsi, drb); err != nil {
+		return err
+	}
+	return s.put(ctx, imsi)
+}
+
+func (s *atomixStore) UpdateDRB(ctx context.Context, imsi types.IMSI, drb *model.DRB) error {
+	if err := s.store.UpdateDRB(ctx, imsi, drb); err != nil {
+		return err
+	}
+	return s.put(ctx, imsi)
+}
+
+func (s *atomixStore) AddPDUSession(ctx context.Context, imsi types.IMSI, session *model.PDUSession) error {
+	if err := s.store.AddPDUSession(ctx, imsi, session); err != nil {
+		return err
+	}
+	return s.put(ctx, imsi)
+}
+
+func (s *atomixStore) DeletePDUSession(ctx context.Context, imsi types.IMSI, sessionID int32) (*model.PDUSession, error) {
+	session, err := s.store.DeletePDUSession(ctx, imsi, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	return session, s.put(ctx, imsi)
+}
+
+func (s *atomixStore) DeleteDRB(ctx context.Context, imsi types.IMSI, drbID int32) (*model.DRB, error) {
+	drb, err := s.store.DeleteDRB(ctx, imsi, drbID)
+	if err != nil {
+		return nil, err
+	}
+	return drb, s.put(ctx, imsi)
+}
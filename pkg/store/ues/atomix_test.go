@@ -0,0 +1,81 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ues
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	_map "github.com/atomix/go-client/pkg/client/map"
+	"github.com/atomix/go-client/pkg/client/primitive"
+	"github.com/atomix/go-client/pkg/client/test"
+	"github.com/onosproject/onos-api/go/onos/ransim/types"
+	"github.com/onosproject/ran-simulator/pkg/model"
+	"github.com/onosproject/ran-simulator/pkg/store/distributed"
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestMap returns a mirror of the shared UE map as opened by a simulator instance
+func newTestMap(t *testing.T, partitions []primitive.Partition) *distributed.Map {
+	sessions, err := test.OpenSessions(partitions)
+	assert.NoError(t, err)
+	t.Cleanup(func() {
+		test.CloseSessions(sessions)
+	})
+	entries, err := _map.New(context.Background(), primitive.NewName("default", "ransim", "default", distributed.UEsMap), sessions)
+	assert.NoError(t, err)
+	m := distributed.NewMap(entries)
+	t.Cleanup(func() {
+		_ = m.Close(context.Background())
+	})
+	return m
+}
+
+func TestAtomixUERegistry(t *testing.T) {
+	ctx := context.Background()
+	partitions, closers := test.StartTestPartitions(1)
+	t.Cleanup(func() {
+		test.StopTestPartitions(closers)
+	})
+	all := func(types.ECGI) bool { return true }
+
+	// The first instance places the UEs; a restarted instance restores them
+	ueStore, err := NewAtomixUERegistry(ctx, newTestMap(t, partitions), 4, cellStore(t), model.PlacementConfig{}, all)
+	assert.NoError(t, err)
+	assert.Equal(t, 4, ueStore.Len(ctx))
+	ue := ueStore.ListAllUEs(ctx)[0]
+	assert.NoError(t, ueStore.UpdateRrcState(ctx, ue.IMSI, model.RrcConnected))
+
+	restarted, err := NewAtomixUERegistry(ctx, newTestMap(t, partitions), 10, cellStore(t), model.PlacementConfig{}, all)
+	assert.NoError(t, err)
+	assert.Equal(t, 4, restarted.Len(ctx))
+	restored, err := restarted.Get(ctx, ue.IMSI)
+	assert.NoError(t, err)
+	assert.Equal(t, model.RrcConnected, restored.RrcState)
+
+	// Changes of one instance are applied by the others
+	assert.NoError(t, ueStore.MoveToCell(ctx, ue.IMSI, 84325717762, 10))
+	assert.Eventually(t, func() bool {
+		restored, err := restarted.Get(ctx, ue.IMSI)
+		return err == nil && restored.Cell.ECGI == 84325717762
+	}, time.Second, 10*time.Millisecond)
+	_, err = ueStore.Delete(ctx, ue.IMSI)
+	assert.NoError(t, err)
+	assert.Eventually(t, func() bool {
+		return restarted.Len(ctx) == 3
+	}, time.Second, 10*time.Millisecond)
+
+	// Instances simulating a share of the cells restore only the UEs served by their cells
+	for _, other := range ueStore.ListAllUEs(ctx) {
+		assert.NoError(t, ueStore.MoveToCell(ctx, other.IMSI, 84325717505, 10))
+	}
+	assert.NoError(t, ueStore.MoveToCell(ctx, ueStore.ListAllUEs(ctx)[0].IMSI, 84325717761, 10))
+	shard, err := NewAtomixUERegistry(ctx, newTestMap(t, partitions), 10, cellStore(t), model.PlacementConfig{}, func(ecgi types.ECGI) bool {
+		return ecgi == 84325717761
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, shard.Len(ctx))
+}
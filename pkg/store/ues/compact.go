@@ -0,0 +1,795 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ues
+
+import (
+	"context"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/onosproject/onos-api/go/onos/ransim/types"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/onosproject/ran-simulator/pkg/journal"
+	"github.com/onosproject/ran-simulator/pkg/model"
+	"github.com/onosproject/ran-simulator/pkg/radio"
+	"github.com/onosproject/ran-simulator/pkg/store/cells"
+	"github.com/onosproject/ran-simulator/pkg/store/event"
+	"github.com/onosproject/ran-simulator/pkg/store/txn"
+	"github.com/onosproject/ran-simulator/pkg/store/watcher"
+)
+
+// Flags of the UEs of the compact store
+const (
+	// flagServed UE has a serving cell
+	flagServed uint8 = 1 << iota
+	// flagAdmitted UE is admitted by its serving cell
+	flagAdmitted
+	// flagIndoor UE is within a building
+	flagIndoor
+	// flagMeasGaps UE has measurement gaps configured
+	flagMeasGaps
+	// flagRegistered UE is registered in the single tracking area held in its slot
+	flagRegistered
+)
+
+// ueExtras holds the rarely set attributes of a UE of the compact store
+type ueExtras struct {
+	measReports      []*model.MeasReport
+	registrationArea []uint32
+	drbs             []*model.DRB
+	pduSessions      []*model.PDUSession
+}
+
+func (e *ueExtras) empty() bool {
+	return len(e.measReports) == 0 && len(e.registrationArea) == 0 && len(e.drbs) == 0 && len(e.pduSessions) == 0
+}
+
+// compactStore holds the UEs as a struct of arrays, i.e. their attributes in parallel slices indexed by slot, and the
+// rarely set ones aside, which keeps the number of heap objects independent of the number of UEs. Only the last
+// position of each UE is kept as its trajectory. UEs are returned as snapshots, so that UEs obtained from the store
+// do not reflect later changes; changes of UEs must always be made via the store.
+type compactStore struct {
+	mu sync.RWMutex
+	// placer places new UEs like the in-memory store
+	placer    *store
+	watchers  *watcher.Watchers
+	nextCRNTI types.CRNTI
+	// crntis holds the C-RNTIs allocated to the admitted UEs of each cell
+	crntis map[types.ECGI]map[types.CRNTI]bool
+
+	slots map[types.IMSI]int
+	free  []int
+	// ueTypes holds the UE types indexed by the type of the slots
+	ueTypes []model.UEType
+
+	imsi     []types.IMSI
+	ueType   []uint8
+	location []model.Coordinate
+	heading  []uint32
+	speed    []float64
+	// recorded holds the time the position was last recorded in nanoseconds since the epoch
+	recorded  []int64
+	ecgi      []types.ECGI
+	strength  []float64
+	crnti     []types.CRNTI
+	rrcState  []uint8
+	flags     []uint8
+	tac       []uint32
+	neighbors [][]model.UECell
+
+	extras map[types.IMSI]*ueExtras
+}
+
+// NewCompactUERegistry creates a new user-equipment registry primed with the specified number of UEs, placed as
+// configured by the placement distribution. The registry holds the UEs in parallel slices rather than as individual
+// objects, cutting the memory footprint and the GC pressure of very large numbers of UEs, at the expense of UEs being
+// returned as snapshots and of trajectories being limited to the last position.
+func NewCompactUERegistry(count uint, cellStore cells.Store, placement model.PlacementConfig) Store {
+	log.Infof("Creating compact registry from model with %d UEs", count)
+	store := &compactStore{
+		placer:   &store{cellStore: cellStore, placement: placement},
+		watchers: watcher.NewWatchers("ues"),
+		crntis:   make(map[types.ECGI]map[types.CRNTI]bool),
+		slots:    make(map[types.IMSI]int, count),
+		extras:   make(map[types.IMSI]*ueExtras),
+	}
+	ctx := context.Background()
+	store.CreateUEs(ctx, count)
+	log.Infof("Created compact registry primed with %d UEs", len(store.slots))
+	return store
+}
+
+func (s *compactStore) SetUECount(ctx context.Context, count uint) {
+	delta := s.Len(ctx) - int(count)
+	if delta < 0 {
+		s.CreateUEs(ctx, uint(-delta))
+	} else if delta > 0 {
+		s.removeSomeUEs(ctx, delta)
+	}
+}
+
+func (s *compactStore) Len(ctx context.Context) int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.slots)
+}
+
+func (s *compactStore) removeSomeUEs(ctx context.Context, count int) {
+	s.mu.RLock()
+	imsis := make([]types.IMSI, 0, count)
+	for imsi := range s.slots {
+		if len(imsis) == count {
+			break
+		}
+		imsis = append(imsis, imsi)
+	}
+	s.mu.RUnlock()
+	for _, imsi := range imsis {
+		_, _ = s.Delete(ctx, imsi)
+	}
+}
+
+func (s *compactStore) CreateUEs(ctx context.Context, count uint) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i := uint(0); i < count; i++ {
+		imsi := types.IMSI(rand.Int63n(maxIMSI-minIMSI) + minIMSI)
+		if _, ok := s.slots[imsi]; ok {
+			imsi = types.IMSI(rand.Int63n(maxIMSI-minIMSI) + minIMSI)
+		}
+
+		ueType := model.UEType("phone")
+		place := s.placer.place
+		if s.placer.placement.UAVShare > 0 && rand.Float64() < s.placer.placement.UAVShare {
+			ueType = model.UAV
+			place = s.placer.placeUAV
+		}
+		location, heading, cell, err := place(ctx, imsi)
+		if err != nil {
+			log.Error(err)
+			return
+		}
+		slot := s.allocate(imsi, ueType)
+		s.location[slot] = location
+		s.heading[slot] = heading
+		s.ecgi[slot] = cell.ECGI
+		s.strength[slot] = rand.Float64() * 100
+		s.setNeighbors(slot, s.placer.initialNeighbors(ctx, cell, location))
+		s.flags[slot] = flagServed
+		s.recorded[slot] = time.Now().UnixNano()
+		journal.Record(journal.UEAttached, uint64(imsi), map[string]interface{}{"ecgi": cell.ECGI})
+	}
+}
+
+// allocate allocates a slot to the UE, reusing the slots of deleted UEs; the store must be locked
+func (s *compactStore) allocate(imsi types.IMSI, ueType model.UEType) int {
+	typeIndex := -1
+	for i, t := range s.ueTypes {
+		if t == ueType {
+			typeIndex = i
+		}
+	}
+	if typeIndex < 0 {
+		typeIndex = len(s.ueTypes)
+		s.ueTypes = append(s.ueTypes, ueType)
+	}
+
+	var slot int
+	if n := len(s.free); n > 0 {
+		slot = s.free[n-1]
+		s.free = s.free[:n-1]
+	} else {
+		slot = len(s.imsi)
+		s.imsi = append(s.imsi, 0)
+		s.ueType = append(s.ueType, 0)
+		s.location = append(s.location, model.Coordinate{})
+		s.heading = append(s.heading, 0)
+		s.speed = append(s.speed, 0)
+		s.recorded = append(s.recorded, 0)
+		s.ecgi = append(s.ecgi, 0)
+		s.strength = append(s.strength, 0)
+		s.crnti = append(s.crnti, 0)
+		s.rrcState = append(s.rrcState, 0)
+		s.flags = append(s.flags, 0)
+		s.tac = append(s.tac, 0)
+		s.neighbors = append(s.neighbors, nil)
+	}
+	s.slots[imsi] = slot
+	s.imsi[slot] = imsi
+	s.ueType[slot] = uint8(typeIndex)
+	return slot
+}
+
+// clear clears the slot of a deleted UE for reuse; the store must be locked
+func (s *compactStore) clear(slot int) {
+	delete(s.slots, s.imsi[slot])
+	delete(s.extras, s.imsi[slot])
+	s.imsi[slot] = 0
+	s.location[slot] = model.Coordinate{}
+	s.heading[slot] = 0
+	s.speed[slot] = 0
+	s.recorded[slot] = 0
+	s.ecgi[slot] = 0
+	s.strength[slot] = 0
+	s.crnti[slot] = 0
+	s.rrcState[slot] = 0
+	s.flags[slot] = 0
+	s.tac[slot] = 0
+	s.neighbors[slot] = nil
+	s.free = append(s.free, slot)
+}
+
+// ue returns a snapshot of the UE held in the slot; the store must be locked
+func (s *compactStore) ue(slot int) *model.UE {
+	flags := s.flags[slot]
+	ue := &model.UE{
+		IMSI:       s.imsi[slot],
+		Type:       s.ueTypes[s.ueType[slot]],
+		Location:   s.location[slot],
+		Heading:    s.heading[slot],
+		Speed:      s.speed[slot],
+		CRNTI:      s.crnti[slot],
+		IsAdmitted: flags&flagAdmitted != 0,
+		RrcState:   model.RrcState(s.rrcState[slot]),
+		Indoor:     flags&flagIndoor != 0,
+		MeasGaps:   flags&flagMeasGaps != 0,
+	}
+	if flags&flagServed != 0 {
+		ue.Cell = &model.UECell{ID: types.GEnbID(s.ecgi[slot]), ECGI: s.ecgi[slot], Strength: s.strength[slot]}
+	}
+	if neighbors := s.neighbors[slot]; len(neighbors) > 0 {
+		cells := make([]model.UECell, len(neighbors))
+		copy(cells, neighbors)
+		ue.Cells = make([]*model.UECell, len(cells))
+		for i := range cells {
+			ue.Cells[i] = &cells[i]
+		}
+	}
+	if flags&flagRegistered != 0 {
+		ue.RegistrationArea = []uint32{s.tac[slot]}
+	}
+	if extras, ok := s.extras[ue.IMSI]; ok {
+		ue.MeasReports = append([]*model.MeasReport(nil), extras.measReports...)
+		if len(extras.registrationArea) > 0 {
+			ue.RegistrationArea = append([]uint32(nil), extras.registrationArea...)
+		}
+		ue.DRBs = append([]*model.DRB(nil), extras.drbs...)
+		ue.PDUSessions = append([]*model.PDUSession(nil), extras.pduSessions...)
+	}
+	return ue
+}
+
+// slot returns the slot of the UE; the store must be locked
+func (s *compactStore) slot(imsi types.IMSI) (int, error) {
+	if slot, ok := s.slots[imsi]; ok {
+		return slot, nil
+	}
+	return 0, errors.New(errors.NotFound, "UE not found")
+}
+
+// ueExtras returns the rarely set attributes of the UE, creating them if needed; the store must be locked
+func (s *compactStore) ueExtras(imsi types.IMSI) *ueExtras {
+	extras, ok := s.extras[imsi]
+	if !ok {
+		extras = &ueExtras{}
+		s.extras[imsi] = extras
+	}
+	return extras
+}
+
+// trimExtras drops the rarely set attributes of the UE once none is left; the store must be locked
+func (s *compactStore) trimExtras(imsi types.IMSI) {
+	if extras, ok := s.extras[imsi]; ok && extras.empty() {
+		delete(s.extras, imsi)
+	}
+}
+
+// send sends an event of the UE held in the slot, provided the store is watched; the store must be locked
+func (s *compactStore) send(ctx context.Context, slot int, eventType UeEvent) {
+	if !s.watchers.Watched() {
+		return
+	}
+	ue := s.ue(slot)
+	s.watchers.SendContext(ctx, event.Event{
+		Key:   ue.IMSI,
+		Value: ue,
+		Type:  eventType,
+	})
+}
+
+// Add adds a UE with a given imsi
+func (s *compactStore) Add(ctx context.Context, ue *model.UE) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.slots[ue.IMSI]; ok {
+		return errors.New(errors.AlreadyExists, "UE %d already exists", ue.IMSI)
+	}
+	slot := s.allocate(ue.IMSI, ue.Type)
+	s.location[slot] = ue.Location
+	s.heading[slot] = ue.Heading
+	s.speed[slot] = ue.Speed
+	s.recorded[slot] = time.Now().UnixNano()
+	s.rrcState[slot] = uint8(ue.RrcState)
+	var flags uint8
+	if ue.Cell != nil {
+		flags |= flagServed
+		s.ecgi[slot] = ue.Cell.ECGI
+		s.strength[slot] = ue.Cell.Strength
+		// UEs admitted elsewhere, e.g. handed over by another instance, keep their C-RNTI unless taken in their cell
+		if ue.IsAdmitted {
+			flags |= flagAdmitted
+			crnti := ue.CRNTI
+			if crnti == 0 || s.crntis[ue.Cell.ECGI][crnti] {
+				crnti = s.allocateCRNTI(ue.Cell.ECGI)
+			}
+			s.assignCRNTI(slot, crnti)
+		}
+	}
+	if ue.Indoor {
+		flags |= flagIndoor
+	}
+	if ue.MeasGaps {
+		flags |= flagMeasGaps
+	}
+	s.flags[slot] = flags
+	s.setNeighbors(slot, ue.Cells)
+	s.setRegistrationArea(slot, ue.RegistrationArea)
+	if len(ue.MeasReports) > 0 || len(ue.DRBs) > 0 || len(ue.PDUSessions) > 0 {
+		extras := s.ueExtras(ue.IMSI)
+		extras.measReports = ue.MeasReports
+		extras.drbs = append([]*model.DRB(nil), ue.DRBs...)
+		extras.pduSessions = append([]*model.PDUSession(nil), ue.PDUSessions...)
+	}
+	s.send(ctx, slot, Created)
+	if ue.Cell != nil {
+		journal.Record(journal.UEAttached, uint64(ue.IMSI), map[string]interface{}{"ecgi": ue.Cell.ECGI})
+	}
+	return nil
+}
+
+// Get gets a snapshot of a UE based on a given imsi
+func (s *compactStore) Get(ctx context.Context, imsi types.IMSI) (*model.UE, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	slot, err := s.slot(imsi)
+	if err != nil {
+		return nil, err
+	}
+	return s.ue(slot), nil
+}
+
+// Delete deletes a UE based on a given imsi
+func (s *compactStore) Delete(ctx context.Context, imsi types.IMSI) (*model.UE, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	slot, err := s.slot(imsi)
+	if err != nil {
+		return nil, err
+	}
+	ue := s.ue(slot)
+	s.releaseCRNTI(slot)
+	s.clear(slot)
+	s.watchers.SendContext(ctx, event.Event{
+		Key:   imsi,
+		Value: ue,
+		Type:  Deleted,
+	})
+	journal.Record(journal.UEDetached, uint64(imsi), nil)
+	return ue, nil
+}
+
+func (s *compactStore) ListAllUEs(ctx context.Context) []*model.UE {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	list := make([]*model.UE, 0, len(s.slots))
+	for slot, imsi := range s.imsi {
+		if imsi != 0 {
+			list = append(list, s.ue(slot))
+		}
+	}
+	return list
+}
+
+func (s *compactStore) ListUEs(ctx context.Context, ecgi types.ECGI) []*model.UE {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	list := make([]*model.UE, 0)
+	for slot, imsi := range s.imsi {
+		if imsi != 0 && s.flags[slot]&flagServed != 0 && s.ecgi[slot] == ecgi {
+			list = append(list, s.ue(slot))
+		}
+	}
+	return list
+}
+
+func (s *compactStore) MoveToCell(ctx context.Context, imsi types.IMSI, ecgi types.ECGI, strength float64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	slot, err := s.slot(imsi)
+	if err != nil {
+		return err
+	}
+	if s.flags[slot]&flagServed == 0 {
+		return errors.New(errors.Invalid, "UE %d has no serving cell", imsi)
+	}
+	eventType := Updated
+	if source := s.ecgi[slot]; source != ecgi {
+		txn.Defer(ctx, func() {
+			journal.Record(journal.HandoverCompleted, uint64(imsi), map[string]interface{}{"source": source, "target": ecgi})
+		})
+		eventType = HandedOver
+		// The target cell allocates a new C-RNTI to admitted UEs
+		if s.flags[slot]&flagAdmitted != 0 {
+			s.releaseCRNTI(slot)
+			s.ecgi[slot] = ecgi
+			s.assignCRNTI(slot, s.allocateCRNTI(ecgi))
+		}
+	}
+	s.ecgi[slot] = ecgi
+	s.strength[slot] = strength
+	s.send(ctx, slot, eventType)
+	return nil
+}
+
+func (s *compactStore) MoveToCoordinate(ctx context.Context, imsi types.IMSI, location model.Coordinate, heading uint32) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	slot, err := s.slot(imsi)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	if elapsed := now.Sub(time.Unix(0, s.recorded[slot])); elapsed > 0 {
+		s.speed[slot] = radio.Distance(s.location[slot], location) / elapsed.Seconds()
+	}
+	s.location[slot] = location
+	s.heading[slot] = heading
+	s.recorded[slot] = now.UnixNano()
+	s.send(ctx, slot, Updated)
+	return nil
+}
+
+func (s *compactStore) UpdateRrcState(ctx context.Context, imsi types.IMSI, state model.RrcState) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	slot, err := s.slot(imsi)
+	if err != nil {
+		return err
+	}
+	s.rrcState[slot] = uint8(state)
+	s.send(ctx, slot, Updated)
+	return nil
+}
+
+func (s *compactStore) AdmitUE(ctx context.Context, imsi types.IMSI) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	slot, err := s.slot(imsi)
+	if err != nil {
+		return err
+	}
+	if s.flags[slot]&flagServed == 0 {
+		return errors.New(errors.Invalid, "UE %d has no serving cell", imsi)
+	}
+	if s.flags[slot]&flagAdmitted != 0 {
+		return nil
+	}
+	s.assignCRNTI(slot, s.allocateCRNTI(s.ecgi[slot]))
+	s.flags[slot] |= flagAdmitted
+	s.send(ctx, slot, Admitted)
+	return nil
+}
+
+func (s *compactStore) ReleaseUE(ctx context.Context, imsi types.IMSI) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	slot, err := s.slot(imsi)
+	if err != nil {
+		return err
+	}
+	if s.flags[slot]&flagAdmitted == 0 {
+		return nil
+	}
+	s.releaseCRNTI(slot)
+	s.flags[slot] &^= flagAdmitted
+	s.send(ctx, slot, Released)
+	return nil
+}
+
+// allocateCRNTI allocates the next C-RNTI not used by the UEs admitted by the specified cell, round robin like the
+// in-memory store; zero is returned if none is left. The store must be locked.
+func (s *compactStore) allocateCRNTI(ecgi types.ECGI) types.CRNTI {
+	used := s.crntis[ecgi]
+	for i := minCRNTI; i <= maxCRNTI; i++ {
+		if s.nextCRNTI < minCRNTI || s.nextCRNTI > maxCRNTI {
+			s.nextCRNTI = minCRNTI
+		}
+		crnti := s.nextCRNTI
+		s.nextCRNTI++
+		if !used[crnti] {
+			return crnti
+		}
+	}
+	return 0
+}
+
+// assignCRNTI assigns the C-RNTI to the UE held in the slot, within its serving cell; the store must be locked
+func (s *compactStore) assignCRNTI(slot int, crnti types.CRNTI) {
+	s.crnti[slot] = crnti
+	if crnti == 0 {
+		return
+	}
+	used, ok := s.crntis[s.ecgi[slot]]
+	if !ok {
+		used = make(map[types.CRNTI]bool)
+		s.crntis[s.ecgi[slot]] = used
+	}
+	used[crnti] = true
+}
+
+// releaseCRNTI releases the C-RNTI of the UE held in the slot, if any; the store must be locked
+func (s *compactStore) releaseCRNTI(slot int) {
+	if s.crnti[slot] == 0 {
+		return
+	}
+	if used, ok := s.crntis[s.ecgi[slot]]; ok {
+		delete(used, s.crnti[slot])
+		if len(used) == 0 {
+			delete(s.crntis, s.ecgi[slot])
+		}
+	}
+	s.crnti[slot] = 0
+}
+
+// setNeighbors copies the candidate cells into the slot, reusing its slice; the store must be locked
+func (s *compactStore) setNeighbors(slot int, candidates []*model.UECell) {
+	neighbors := s.neighbors[slot][:0]
+	for _, candidate := range candidates {
+		neighbors = append(neighbors, *candidate)
+	}
+	if len(neighbors) == 0 {
+		neighbors = nil
+	}
+	s.neighbors[slot] = neighbors
+}
+
+// setRegistrationArea holds a registration area of a single tracking area in the slot and larger ones aside; the
+// store must be locked
+func (s *compactStore) setRegistrationArea(slot int, tacs []uint32) {
+	imsi := s.imsi[slot]
+	s.flags[slot] &^= flagRegistered
+	s.tac[slot] = 0
+	if extras, ok := s.extras[imsi]; ok {
+		extras.registrationArea = nil
+	}
+	switch {
+	case len(tacs) == 1:
+		s.flags[slot] |= flagRegistered
+		s.tac[slot] = tacs[0]
+	case len(tacs) > 1:
+		s.ueExtras(imsi).registrationArea = append([]uint32(nil), tacs...)
+	}
+	s.trimExtras(imsi)
+}
+
+func (s *compactStore) UpdateMeasurements(ctx context.Context, imsi types.IMSI, ecgi types.ECGI, strength float64, candidates []*model.UECell, measGaps bool, reports []*model.MeasReport) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	slot, err := s.slot(imsi)
+	if err != nil {
+		return err
+	}
+	if s.flags[slot]&flagServed == 0 || s.ecgi[slot] != ecgi {
+		return errors.New(errors.Conflict, "UE %d is no longer served by cell %d", imsi, ecgi)
+	}
+	s.strength[slot] = strength
+	s.setNeighbors(slot, candidates)
+	if measGaps {
+		s.flags[slot] |= flagMeasGaps
+	} else {
+		s.flags[slot] &^= flagMeasGaps
+	}
+	if len(reports) > 0 {
+		s.ueExtras(imsi).measReports = reports
+	} else if extras, ok := s.extras[imsi]; ok {
+		extras.measReports = nil
+		s.trimExtras(imsi)
+	}
+	s.send(ctx, slot, Updated)
+	return nil
+}
+
+func (s *compactStore) UpdateRegistrationArea(ctx context.Context, imsi types.IMSI, tacs []uint32) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	slot, err := s.slot(imsi)
+	if err != nil {
+		return err
+	}
+	s.setRegistrationArea(slot, tacs)
+	s.send(ctx, slot, Updated)
+	return nil
+}
+
+func (s *compactStore) UpdateIndoor(ctx context.Context, imsi types.IMSI, indoor bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	slot, err := s.slot(imsi)
+	if err != nil {
+		return err
+	}
+	if indoor {
+		s.flags[slot] |= flagIndoor
+	} else {
+		s.flags[slot] &^= flagIndoor
+	}
+	s.send(ctx, slot, Updated)
+	return nil
+}
+
+func (s *compactStore) AddDRB(ctx context.Context, imsi types.IMSI, drb *model.DRB) error {
+	if drb.ID < minDRBID || drb.ID > maxDRBID {
+		return errors.New(errors.Invalid, "DRB ID must be in range [%d, %d]", minDRBID, maxDRBID)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	slot, err := s.slot(imsi)
+	if err != nil {
+		return err
+	}
+	extras := s.ueExtras(imsi)
+	for _, d := range extras.drbs {
+		if d.ID == drb.ID {
+			return errors.New(errors.AlreadyExists, "DRB already exists")
+		}
+	}
+	extras.drbs = append(extras.drbs, drb)
+	s.send(ctx, slot, Updated)
+	return nil
+}
+
+func (s *compactStore) UpdateDRB(ctx context.Context, imsi types.IMSI, drb *model.DRB) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	slot, err := s.slot(imsi)
+	if err != nil {
+		return err
+	}
+	if extras, ok := s.extras[imsi]; ok {
+		for i, d := range extras.drbs {
+			if d.ID == drb.ID {
+				extras.drbs[i] = drb
+				s.send(ctx, slot, Updated)
+				return nil
+			}
+		}
+	}
+	return errors.New(errors.NotFound, "DRB not found")
+}
+
+func (s *compactStore) DeleteDRB(ctx context.Context, imsi types.IMSI, drbID int32) (*model.DRB, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	slot, err := s.slot(imsi)
+	if err != nil {
+		return nil, err
+	}
+	if extras, ok := s.extras[imsi]; ok {
+		for i, drb := range extras.drbs {
+			if drb.ID == drbID {
+				extras.drbs = append(extras.drbs[:i], extras.drbs[i+1:]...)
+				s.trimExtras(imsi)
+				s.send(ctx, slot, Updated)
+				return drb, nil
+			}
+		}
+	}
+	return nil, errors.New(errors.NotFound, "DRB not found")
+}
+
+func (s *compactStore) AddPDUSession(ctx context.Context, imsi types.IMSI, session *model.PDUSession) error {
+	if session.ID < minPDUSessionID || session.ID > maxPDUSessionID {
+		return errors.New(errors.Invalid, "PDU session ID must be in range [%d, %d]", minPDUSessionID, maxPDUSessionID)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	slot, err := s.slot(imsi)
+	if err != nil {
+		return err
+	}
+	extras := s.ueExtras(imsi)
+	for _, existing := range extras.pduSessions {
+		if existing.ID == session.ID {
+			return errors.New(errors.AlreadyExists, "PDU session already exists")
+		}
+	}
+	extras.pduSessions = append(extras.pduSessions, session)
+	s.send(ctx, slot, Updated)
+	return nil
+}
+
+func (s *compactStore) DeletePDUSession(ctx context.Context, imsi types.IMSI, sessionID int32) (*model.PDUSession, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	slot, err := s.slot(imsi)
+	if err != nil {
+		return nil, err
+	}
+	if extras, ok := s.extras[imsi]; ok {
+		for i, session := range extras.pduSessions {
+			if session.ID == sessionID {
+				extras.pduSessions = append(extras.pduSessions[:i], extras.pduSessions[i+1:]...)
+				s.trimExtras(imsi)
+				s.send(ctx, slot, Updated)
+				return session, nil
+			}
+		}
+	}
+	return nil, errors.New(errors.NotFound, "PDU session not found")
+}
+
+// Trajectory returns the last position and serving cell of the specified UE if recorded at or after the given time;
+// the compact store keeps no earlier positions
+func (s *compactStore) Trajectory(ctx context.Context, imsi types.IMSI, since time.Time) ([]model.TrajectoryPoint, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	slot, err := s.slot(imsi)
+	if err != nil {
+		return nil, err
+	}
+	point := model.TrajectoryPoint{
+		Time:     time.Unix(0, s.recorded[slot]),
+		Location: s.location[slot],
+		Heading:  s.heading[slot],
+		Speed:    s.speed[slot],
+	}
+	if s.flags[slot]&flagServed != 0 {
+		point.ECGI = s.ecgi[slot]
+	}
+	if point.Time.Before(since) {
+		return []model.TrajectoryPoint{}, nil
+	}
+	return []model.TrajectoryPoint{point}, nil
+}
+
+func (s *compactStore) Watch(ctx context.Context, ch chan<- event.Event, options ...WatchOptions) error {
+	log.Debug("Watching ue changes")
+	var watchOptions WatchOptions
+	if len(options) > 0 {
+		watchOptions = options[0]
+	}
+
+	if err := s.watchers.Watch(ctx, ch, watchOptions.filter()); err != nil {
+		log.Error(err)
+		return err
+	}
+
+	if watchOptions.Replay {
+		accepts := watchOptions.ueFilter()
+		replayed := make([]*model.UE, 0)
+		s.mu.RLock()
+		for slot, imsi := range s.imsi {
+			if imsi == 0 {
+				continue
+			}
+			if ue := s.ue(slot); accepts(ue) {
+				replayed = append(replayed, ue)
+			}
+		}
+		s.mu.RUnlock()
+		go func() {
+			for _, ue := range replayed {
+				ch <- event.Event{
+					Key:   ue.IMSI,
+					Value: ue,
+					Type:  None,
+				}
+			}
+		}()
+	}
+
+	return nil
+}
@@ -0,0 +1,179 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ues
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/onosproject/onos-api/go/onos/ransim/types"
+	"github.com/onosproject/ran-simulator/pkg/model"
+	"github.com/onosproject/ran-simulator/pkg/store/event"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompactUERegistry(t *testing.T) {
+	ctx := context.Background()
+	ues := NewCompactUERegistry(16, cellStore(t), model.PlacementConfig{UAVShare: 0.5})
+	assert.Equal(t, 16, ues.Len(ctx))
+	assert.Len(t, ues.ListAllUEs(ctx), 16)
+
+	// Slots of deleted UEs are reused
+	ues.SetUECount(ctx, 10)
+	assert.Equal(t, 10, ues.Len(ctx))
+	ues.SetUECount(ctx, 200)
+	assert.Equal(t, 200, ues.Len(ctx))
+	assert.Len(t, ues.(*compactStore).imsi, 200)
+
+	served := 0
+	for _, ecgi := range []types.ECGI{84325717505, 84325717506, 84325717761, 84325717762} {
+		served += len(ues.ListUEs(ctx, ecgi))
+	}
+	assert.Equal(t, 200, served)
+}
+
+func TestCompactUESnapshots(t *testing.T) {
+	ctx := context.Background()
+	ues := NewCompactUERegistry(1, cellStore(t), model.PlacementConfig{})
+	ue := ues.ListAllUEs(ctx)[0]
+	assert.Equal(t, model.UEType("phone"), ue.Type)
+
+	// UEs are snapshots not reflecting later changes
+	neighbor := &model.UECell{ID: 1, ECGI: 84325717506, Strength: -90}
+	reports := []*model.MeasReport{{Event: model.MeasEventA3, ECGI: 84325717506}}
+	assert.NoError(t, ues.UpdateMeasurements(ctx, ue.IMSI, ue.Cell.ECGI, -80, []*model.UECell{neighbor}, true, reports))
+	assert.NoError(t, ues.UpdateRrcState(ctx, ue.IMSI, model.RrcConnected))
+	assert.NoError(t, ues.UpdateIndoor(ctx, ue.IMSI, true))
+	assert.NoError(t, ues.UpdateRegistrationArea(ctx, ue.IMSI, []uint32{1}))
+	assert.Equal(t, model.RrcIdle, ue.RrcState)
+	assert.Empty(t, ue.Cells)
+
+	ue1, err := ues.Get(ctx, ue.IMSI)
+	assert.NoError(t, err)
+	assert.Equal(t, -80.0, ue1.Cell.Strength)
+	assert.Equal(t, []*model.UECell{neighbor}, ue1.Cells)
+	assert.False(t, ue1.Cells[0] == neighbor)
+	assert.True(t, ue1.MeasGaps)
+	assert.Equal(t, reports, ue1.MeasReports)
+	assert.Equal(t, model.RrcConnected, ue1.RrcState)
+	assert.True(t, ue1.Indoor)
+	assert.Equal(t, []uint32{1}, ue1.RegistrationArea)
+
+	// Registration areas of several tracking areas and measurement reports are held aside while set
+	assert.NoError(t, ues.UpdateRegistrationArea(ctx, ue.IMSI, []uint32{1, 2}))
+	ue1, _ = ues.Get(ctx, ue.IMSI)
+	assert.Equal(t, []uint32{1, 2}, ue1.RegistrationArea)
+	assert.NoError(t, ues.UpdateRegistrationArea(ctx, ue.IMSI, nil))
+	assert.NoError(t, ues.UpdateMeasurements(ctx, ue.IMSI, ue.Cell.ECGI, -80, nil, false, nil))
+	assert.Empty(t, ues.(*compactStore).extras)
+	ue1, _ = ues.Get(ctx, ue.IMSI)
+	assert.Empty(t, ue1.RegistrationArea)
+	assert.Empty(t, ue1.Cells)
+
+	// Added UEs keep their attributes
+	ue1.IMSI = 1
+	ue1.Type = model.UAV
+	ue1.IsAdmitted = true
+	ue1.CRNTI = 7
+	ue1.DRBs = []*model.DRB{{ID: 1}}
+	assert.NoError(t, ues.Add(ctx, ue1))
+	assert.Error(t, ues.Add(ctx, ue1))
+	ue2, err := ues.Get(ctx, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, ue1, ue2)
+
+	deleted, err := ues.Delete(ctx, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, ue1, deleted)
+	_, err = ues.Get(ctx, 1)
+	assert.Error(t, err)
+}
+
+func TestCompactAdmitUE(t *testing.T) {
+	ctx := context.Background()
+	ues := NewCompactUERegistry(2, cellStore(t), model.PlacementConfig{})
+	list := ues.ListAllUEs(ctx)
+	for i, ue := range list {
+		assert.NoError(t, ues.MoveToCell(ctx, ue.IMSI, 84325717505, 10))
+		assert.NoError(t, ues.AdmitUE(ctx, ue.IMSI))
+		list[i], _ = ues.Get(ctx, ue.IMSI)
+		assert.True(t, list[i].IsAdmitted)
+	}
+	assert.NotEqual(t, list[0].CRNTI, list[1].CRNTI)
+
+	// Admitted UEs are allocated a new C-RNTI by the target cell of a handover
+	assert.NoError(t, ues.MoveToCell(ctx, list[0].IMSI, 84325717506, 10))
+	ue, _ := ues.Get(ctx, list[0].IMSI)
+	assert.True(t, ue.IsAdmitted)
+	assert.NotEqual(t, list[0].CRNTI, ue.CRNTI)
+
+	// UEs added with a C-RNTI taken in their cell are allocated a new one
+	added := &model.UE{IMSI: 1, Type: "phone", Cell: &model.UECell{ECGI: 84325717505}, IsAdmitted: true, CRNTI: list[1].CRNTI}
+	assert.NoError(t, ues.Add(ctx, added))
+	ue, _ = ues.Get(ctx, 1)
+	assert.NotEqual(t, list[1].CRNTI, ue.CRNTI)
+
+	assert.NoError(t, ues.ReleaseUE(ctx, list[0].IMSI))
+	ue, _ = ues.Get(ctx, list[0].IMSI)
+	assert.False(t, ue.IsAdmitted)
+	assert.Zero(t, ue.CRNTI)
+	assert.Len(t, ues.(*compactStore).crntis[84325717505], 2)
+	assert.Empty(t, ues.(*compactStore).crntis[84325717506])
+}
+
+func TestCompactWatch(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ues := NewCompactUERegistry(4, cellStore(t), model.PlacementConfig{})
+	ue := ues.ListAllUEs(ctx)[0]
+	source := ue.Cell.ECGI
+	target := types.ECGI(84325717505)
+	if source == target {
+		target = 84325717506
+	}
+
+	// Replayed UEs are sent first; only handovers of the watched UE are reported then
+	ch := make(chan event.Event, 10)
+	assert.NoError(t, ues.Watch(ctx, ch, WatchOptions{Replay: true, IMSIs: []types.IMSI{ue.IMSI}, Types: []UeEvent{HandedOver}}))
+	ueEvent := <-ch
+	assert.Equal(t, None, ueEvent.Type)
+	assert.Equal(t, ue, ueEvent.Value)
+	assert.NoError(t, ues.MoveToCoordinate(ctx, ue.IMSI, model.Coordinate{Lat: 50.0755, Lng: 14.4378}, 182))
+	assert.NoError(t, ues.MoveToCell(ctx, ue.IMSI, target, 5))
+
+	ueEvent = <-ch
+	assert.Equal(t, HandedOver, ueEvent.Type)
+	assert.Equal(t, target, ueEvent.Value.(*model.UE).Cell.ECGI)
+	select {
+	case ueEvent = <-ch:
+		assert.Fail(t, "unexpected event", ueEvent.Type)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestCompactTrajectory(t *testing.T) {
+	ctx := context.Background()
+	ues := NewCompactUERegistry(1, cellStore(t), model.PlacementConfig{})
+	ue := ues.ListAllUEs(ctx)[0]
+
+	// Only the last position is kept, from which the speed is derived
+	start := time.Now()
+	time.Sleep(10 * time.Millisecond)
+	location := model.Coordinate{Lat: ue.Location.Lat + 0.001, Lng: ue.Location.Lng}
+	assert.NoError(t, ues.MoveToCoordinate(ctx, ue.IMSI, location, 90))
+	points, err := ues.Trajectory(ctx, ue.IMSI, start)
+	assert.NoError(t, err)
+	assert.Len(t, points, 1)
+	assert.Equal(t, location, points[0].Location)
+	assert.Equal(t, ue.Cell.ECGI, points[0].ECGI)
+	assert.Greater(t, points[0].Speed, 0.0)
+
+	points, err = ues.Trajectory(ctx, ue.IMSI, time.Now().Add(time.Second))
+	assert.NoError(t, err)
+	assert.Empty(t, points)
+	_, err = ues.Trajectory(ctx, 1, start)
+	assert.Error(t, err)
+}
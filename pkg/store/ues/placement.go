@@ -0,0 +1,190 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ues
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"sort"
+
+	"github.com/onosproject/onos-api/go/onos/ransim/types"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/onosproject/ran-simulator/pkg/model"
+	"github.com/onosproject/ran-simulator/pkg/radio"
+)
+
+const (
+	defaultCellRadius = 1000.0
+	defaultSigma      = 100.0
+)
+
+// place picks the initial location and compass heading of a new UE, as configured by the placement distribution,
+// and the strongest cell at that location serving it; only cells admitting the UE are considered
+func (s *store) place(ctx context.Context, imsi types.IMSI) (model.Coordinate, uint32, *model.Cell, error) {
+	switch s.placement.Distribution {
+	case model.PlacementHotspots:
+		if len(s.placement.Hotspots) > 0 {
+			location := placeAroundHotspot(s.placement.Hotspots)
+			cell, err := s.strongestCell(ctx, imsi, location)
+			return location, 0, cell, err
+		}
+	case model.PlacementRoutes:
+		if len(s.placement.Routes) > 0 {
+			location, heading := placeAlongRoute(s.placement.Routes)
+			cell, err := s.strongestCell(ctx, imsi, location)
+			return location, heading, cell, err
+		}
+	}
+
+	cellList, err := s.admittingCells(ctx, imsi)
+	if err != nil {
+		return model.Coordinate{}, 0, nil, err
+	}
+	radius := s.placement.CellRadius
+	if radius <= 0 {
+		radius = defaultCellRadius
+	}
+	placed := cellList[rand.Intn(len(cellList))]
+	location := placeInSector(placed.Sector, radius)
+	cell, err := s.strongestCell(ctx, imsi, location)
+	// Close to the site the antenna attenuation saturates, so that the sectors of the site are equally strong; the
+	// UE then attaches to the sector it was placed in
+	if err == nil && radio.RSRP(placed, location) >= radio.RSRP(cell, location) {
+		cell = placed
+	}
+	return location, 0, cell, err
+}
+
+// placeUAV picks the initial location and heading of a new UAV and the cell serving it; UAVs are placed on the UAV
+// routes if there are any, and at the UAV altitude above a location drawn from the placement distribution otherwise
+func (s *store) placeUAV(ctx context.Context, imsi types.IMSI) (model.Coordinate, uint32, *model.Cell, error) {
+	if len(s.placement.UAVRoutes) > 0 {
+		location, heading := placeAlongRoute(s.placement.UAVWaypoints())
+		cell, err := s.strongestCell(ctx, imsi, location)
+		return location, heading, cell, err
+	}
+	location, heading, cell, err := s.place(ctx, imsi)
+	location.Alt = s.placement.UAVAltitude
+	if location.Alt <= 0 {
+		location.Alt = model.DefaultUAVAltitude
+	}
+	return location, heading, cell, err
+}
+
+// strongestCell returns the cell admitting the UE whose RSRP at the given location is the highest, i.e. the cell the
+// UE initially attaches to
+func (s *store) strongestCell(ctx context.Context, imsi types.IMSI, location model.Coordinate) (*model.Cell, error) {
+	cellList, err := s.cellStore.GetStrongestCells(ctx, location, 1, func(cell *model.Cell) bool {
+		return cell.Admits(imsi)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(cellList) == 0 {
+		return nil, errors.New(errors.NotFound, "no cells admitting UE %d", imsi)
+	}
+	return cellList[0], nil
+}
+
+// initialNeighbors returns the neighbors in service of the serving cell on its carrier ordered by their RSRP at the
+// given location, i.e. the neighbor list of a new UE until it is measured
+func (s *store) initialNeighbors(ctx context.Context, serving *model.Cell, location model.Coordinate) []*model.UECell {
+	var neighbors []*model.UECell
+	for _, ecgi := range serving.Neighbors {
+		neighbor, err := s.cellStore.Get(ctx, ecgi)
+		if err != nil || !neighbor.InService() || !serving.IsIntraFrequency(neighbor) {
+			continue
+		}
+		neighbors = append(neighbors, &model.UECell{
+			ID:       types.GEnbID(ecgi),
+			ECGI:     ecgi,
+			Strength: radio.RSRP(neighbor, location),
+		})
+	}
+	sort.Slice(neighbors, func(i, j int) bool {
+		return neighbors[i].Strength > neighbors[j].Strength
+	})
+	return neighbors
+}
+
+// admittingCells returns the cells admitting the UE, i.e. all cells except for closed subscriber group cells
+// the UE is not a member of
+func (s *store) admittingCells(ctx context.Context, imsi types.IMSI) ([]*model.Cell, error) {
+	cellList, err := s.cellStore.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	admitting := make([]*model.Cell, 0, len(cellList))
+	for _, cell := range cellList {
+		if cell.Admits(imsi) {
+			admitting = append(admitting, cell)
+		}
+	}
+	if len(admitting) == 0 {
+		return nil, errors.New(errors.NotFound, "no cells admitting UE %d", imsi)
+	}
+	return admitting, nil
+}
+
+// placeInSector picks a location uniformly distributed over the area of the sector with the given radius in meters;
+// the sector spans the arc starting at its azimuth
+func placeInSector(sector model.Sector, radius float64) model.Coordinate {
+	arc := float64(sector.Arc)
+	if arc <= 0 {
+		arc = 360
+	}
+	bearing := float64(sector.Azimuth) + rand.Float64()*arc
+	return radio.Offset(sector.Center, bearing, math.Sqrt(rand.Float64())*radius)
+}
+
+// placeAroundHotspot picks a hotspot by its weight and a location around it with a Gaussian spread
+func placeAroundHotspot(hotspots []model.Hotspot) model.Coordinate {
+	total := 0.0
+	for _, hotspot := range hotspots {
+		total += hotspotWeight(hotspot)
+	}
+	pick := rand.Float64() * total
+	hotspot := hotspots[len(hotspots)-1]
+	for _, h := range hotspots {
+		if pick < hotspotWeight(h) {
+			hotspot = h
+			break
+		}
+		pick -= hotspotWeight(h)
+	}
+	sigma := hotspot.Sigma
+	if sigma <= 0 {
+		sigma = defaultSigma
+	}
+	return radio.Offset(hotspot.Center, rand.Float64()*360, math.Abs(rand.NormFloat64())*sigma)
+}
+
+func hotspotWeight(hotspot model.Hotspot) float64 {
+	if hotspot.Weight <= 0 {
+		return 1
+	}
+	return hotspot.Weight
+}
+
+// placeAlongRoute picks a location on a random segment of a random route and returns it along with the heading of the segment
+func placeAlongRoute(routes [][]model.Coordinate) (model.Coordinate, uint32) {
+	route := routes[rand.Intn(len(routes))]
+	switch len(route) {
+	case 0:
+		return model.Coordinate{}, 0
+	case 1:
+		return route[0], 0
+	}
+	i := rand.Intn(len(route) - 1)
+	from, to := route[i], route[i+1]
+	t := rand.Float64()
+	location := model.Coordinate{
+		Lat: from.Lat + t*(to.Lat-from.Lat),
+		Lng: from.Lng + t*(to.Lng-from.Lng),
+		Alt: from.Alt + t*(to.Alt-from.Alt),
+	}
+	return location, uint32(math.Mod(math.Round(radio.Bearing(from, to)), 360))
+}
@@ -0,0 +1,100 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ues
+
+import (
+	"context"
+	"testing"
+
+	"github.com/onosproject/onos-api/go/onos/ransim/types"
+	"github.com/onosproject/ran-simulator/pkg/model"
+	"github.com/onosproject/ran-simulator/pkg/radio"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUniformPlacement(t *testing.T) {
+	ctx := context.Background()
+	cells := cellStore(t)
+	ues := NewUERegistryWithPlacement(50, cells, model.PlacementConfig{CellRadius: 500})
+	for _, ue := range ues.ListAllUEs(ctx) {
+		cell, err := cells.Get(ctx, ue.Cell.ECGI)
+		assert.NoError(t, err)
+		assert.LessOrEqual(t, radio.Distance(ue.Location, cell.Sector.Center), 501.0)
+		// UEs are placed within the arc of their serving sector
+		bearing := uint32(radio.Bearing(cell.Sector.Center, ue.Location))
+		assert.True(t, bearing >= uint32(cell.Sector.Azimuth) && bearing <= uint32(cell.Sector.Azimuth+cell.Sector.Arc),
+			"bearing %d outside sector of cell %d", bearing, cell.ECGI)
+	}
+}
+
+func TestHotspotPlacement(t *testing.T) {
+	ctx := context.Background()
+	ues := NewUERegistryWithPlacement(20, cellStore(t), model.PlacementConfig{
+		Distribution: model.PlacementHotspots,
+		Hotspots: []model.Hotspot{
+			{Center: model.Coordinate{Lat: 44.01, Lng: 31.0}, Sigma: 50},
+		},
+	})
+	for _, ue := range ues.ListAllUEs(ctx) {
+		assert.Less(t, radio.Distance(ue.Location, model.Coordinate{Lat: 44.01, Lng: 31.0}), 1000.0)
+		assert.Contains(t, []types.ECGI{84325717761, 84325717762}, ue.Cell.ECGI)
+	}
+}
+
+func TestRoutePlacement(t *testing.T) {
+	ctx := context.Background()
+	ues := NewUERegistryWithPlacement(20, cellStore(t), model.PlacementConfig{
+		Distribution: model.PlacementRoutes,
+		Routes: [][]model.Coordinate{
+			{{Lat: 46.0, Lng: 29.0}, {Lat: 46.0, Lng: 29.1}},
+		},
+	})
+	for _, ue := range ues.ListAllUEs(ctx) {
+		assert.Equal(t, 46.0, ue.Location.Lat)
+		assert.True(t, ue.Location.Lng >= 29.0 && ue.Location.Lng <= 29.1)
+		assert.Equal(t, uint32(90), ue.Heading)
+		assert.Contains(t, []types.ECGI{84325717505, 84325717506}, ue.Cell.ECGI)
+	}
+}
+
+func TestUAVPlacement(t *testing.T) {
+	ctx := context.Background()
+	ues := NewUERegistryWithPlacement(20, cellStore(t), model.PlacementConfig{
+		UAVShare: 1,
+		UAVRoutes: [][]model.Coordinate{
+			{{Lat: 46.0, Lng: 29.0, Alt: 50}, {Lat: 46.0, Lng: 29.1, Alt: 150}},
+		},
+	})
+	for _, ue := range ues.ListAllUEs(ctx) {
+		assert.Equal(t, model.UAV, ue.Type)
+		assert.True(t, ue.Location.Alt >= 50 && ue.Location.Alt <= 150)
+		// The altitude increases linearly along the route
+		assert.InDelta(t, 50+(ue.Location.Lng-29.0)*1000, ue.Location.Alt, 1e-6)
+	}
+
+	// Without UAV routes, UAVs are placed at the UAV altitude
+	ues = NewUERegistryWithPlacement(5, cellStore(t), model.PlacementConfig{UAVShare: 1})
+	for _, ue := range ues.ListAllUEs(ctx) {
+		assert.Equal(t, 100.0, ue.Location.Alt)
+	}
+}
+
+func TestPlacementAdmission(t *testing.T) {
+	ctx := context.Background()
+	cells := cellStore(t)
+	cellList, err := cells.List(ctx)
+	assert.NoError(t, err)
+	for _, cell := range cellList[1:] {
+		private := *cell
+		private.CSG = true
+		assert.NoError(t, cells.Update(ctx, &private))
+	}
+
+	// UEs are only placed in cells admitting them
+	ues := NewUERegistry(10, cells)
+	for _, ue := range ues.ListAllUEs(ctx) {
+		assert.Equal(t, cellList[0].ECGI, ue.Cell.ECGI)
+	}
+}
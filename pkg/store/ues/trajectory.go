@@ -0,0 +1,91 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ues
+
+import (
+	"context"
+	"time"
+
+	"github.com/onosproject/onos-api/go/onos/ransim/types"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/onosproject/ran-simulator/pkg/model"
+)
+
+// TrajectoryLength is the number of recent positions kept per UE
+const TrajectoryLength = 1024
+
+// trajectory is a ring buffer of the recent positions of a UE
+type trajectory struct {
+	points []model.TrajectoryPoint
+	next   int
+}
+
+// add adds the point, overwriting the oldest one once the buffer is full
+func (t *trajectory) add(point model.TrajectoryPoint) {
+	if len(t.points) < TrajectoryLength {
+		t.points = append(t.points, point)
+		return
+	}
+	t.points[t.next] = point
+	t.next = (t.next + 1) % TrajectoryLength
+}
+
+// since returns the points recorded at or after the given time in chronological order
+func (t *trajectory) since(since time.Time) []model.TrajectoryPoint {
+	points := make([]model.TrajectoryPoint, 0, len(t.points))
+	for i := range t.points {
+		point := t.points[(t.next+i)%len(t.points)]
+		if !point.Time.Before(since) {
+			points = append(points, point)
+		}
+	}
+	return points
+}
+
+// last returns the most recent point
+func (t *trajectory) last() model.TrajectoryPoint {
+	if len(t.points) < TrajectoryLength {
+		return t.points[len(t.points)-1]
+	}
+	return t.points[(t.next+TrajectoryLength-1)%TrajectoryLength]
+}
+
+// lastPosition returns the most recently recorded position of the UE; the store must be locked
+func (s *store) lastPosition(imsi types.IMSI) (model.TrajectoryPoint, bool) {
+	t, ok := s.trajectories[imsi]
+	if !ok || len(t.points) == 0 {
+		return model.TrajectoryPoint{}, false
+	}
+	return t.last(), true
+}
+
+// recordPosition adds the current position and serving cell of the UE to its trajectory, which is only recorded when
+// the UE moves so that the speed is derived from successive positions; the store must be locked
+func (s *store) recordPosition(ue *model.UE) {
+	t, ok := s.trajectories[ue.IMSI]
+	if !ok {
+		t = &trajectory{}
+		s.trajectories[ue.IMSI] = t
+	}
+	point := model.TrajectoryPoint{Time: time.Now(), Location: ue.Location, Heading: ue.Heading, Speed: ue.Speed}
+	if ue.Cell != nil {
+		point.ECGI = ue.Cell.ECGI
+	}
+	t.add(point)
+}
+
+// Trajectory returns the positions and serving cells of the specified UE recorded at or after the given time
+func (s *store) Trajectory(ctx context.Context, imsi types.IMSI, since time.Time) ([]model.TrajectoryPoint, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if _, ok := s.ues[imsi]; !ok {
+		return nil, errors.New(errors.NotFound, "UE not found")
+	}
+	t, ok := s.trajectories[imsi]
+	if !ok {
+		return []model.TrajectoryPoint{}, nil
+	}
+	return t.since(since), nil
+}
@@ -0,0 +1,98 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ues
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/onosproject/onos-api/go/onos/ransim/types"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/onosproject/ran-simulator/pkg/model"
+	"github.com/onosproject/ran-simulator/pkg/radio"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTrajectory(t *testing.T) {
+	ctx := context.Background()
+	ueStore := NewUERegistry(1, cellStore(t))
+	ue := ueStore.ListAllUEs(ctx)[0]
+	start := ue.Cell.ECGI
+
+	// The initial position is recorded upon creation
+	points, err := ueStore.Trajectory(ctx, ue.IMSI, time.Time{})
+	assert.NoError(t, err)
+	assert.Len(t, points, 1)
+	assert.Equal(t, ue.Location, points[0].Location)
+	assert.Equal(t, start, points[0].ECGI)
+
+	// Moves are recorded in chronological order along with the serving cell at the time; changes of the serving
+	// cell alone are not
+	assert.NoError(t, ueStore.MoveToCoordinate(ctx, ue.IMSI, model.Coordinate{Lat: 46.0, Lng: 29.0}, 90))
+	assert.NoError(t, ueStore.MoveToCell(ctx, ue.IMSI, start+1, -80))
+	assert.NoError(t, ueStore.MoveToCoordinate(ctx, ue.IMSI, model.Coordinate{Lat: 46.001, Lng: 29.0}, 90))
+	points, err = ueStore.Trajectory(ctx, ue.IMSI, time.Time{})
+	assert.NoError(t, err)
+	assert.Len(t, points, 3)
+	assert.Equal(t, model.Coordinate{Lat: 46.0, Lng: 29.0}, points[1].Location)
+	assert.Equal(t, uint32(90), points[1].Heading)
+	assert.Equal(t, start, points[1].ECGI)
+	assert.Equal(t, start+1, points[2].ECGI)
+	assert.False(t, points[2].Time.Before(points[1].Time))
+
+	points, err = ueStore.Trajectory(ctx, ue.IMSI, time.Now().Add(time.Minute))
+	assert.NoError(t, err)
+	assert.Empty(t, points)
+
+	_, err = ueStore.Trajectory(ctx, types.IMSI(1), time.Time{})
+	assert.True(t, errors.IsNotFound(err))
+}
+
+func TestTrajectoryRingBuffer(t *testing.T) {
+	start := time.Now()
+	tr := &trajectory{}
+	for i := 0; i < TrajectoryLength+10; i++ {
+		tr.add(model.TrajectoryPoint{Time: start.Add(time.Duration(i) * time.Second), Heading: uint32(i)})
+	}
+	points := tr.since(time.Time{})
+	assert.Len(t, points, TrajectoryLength)
+	assert.Equal(t, uint32(10), points[0].Heading)
+	assert.Equal(t, uint32(TrajectoryLength+9), points[len(points)-1].Heading)
+
+	points = tr.since(start.Add(time.Duration(TrajectoryLength) * time.Second))
+	assert.Len(t, points, 10)
+	assert.Equal(t, uint32(TrajectoryLength), points[0].Heading)
+	assert.Equal(t, uint32(TrajectoryLength+9), tr.last().Heading)
+}
+
+func TestSpeed(t *testing.T) {
+	ctx := context.Background()
+	ueStore := NewUERegistry(1, cellStore(t))
+	ue := ueStore.ListAllUEs(ctx)[0]
+
+	// The speed is derived from the distance to the previous position and the time elapsed since
+	from := model.Coordinate{Lat: 46.0, Lng: 29.0}
+	to := model.Coordinate{Lat: 46.001, Lng: 29.0}
+	assert.NoError(t, ueStore.MoveToCoordinate(ctx, ue.IMSI, from, 0))
+	time.Sleep(100 * time.Millisecond)
+	assert.NoError(t, ueStore.MoveToCoordinate(ctx, ue.IMSI, to, 0))
+	assert.Greater(t, ue.Speed, 0.0)
+	assert.LessOrEqual(t, ue.Speed, radio.Distance(from, to)/0.1)
+
+	points, err := ueStore.Trajectory(ctx, ue.IMSI, time.Time{})
+	assert.NoError(t, err)
+	assert.Equal(t, ue.Speed, points[len(points)-1].Speed)
+
+	// Changes of the serving cell in between do not distort the speed
+	time.Sleep(100 * time.Millisecond)
+	assert.NoError(t, ueStore.MoveToCell(ctx, ue.IMSI, ue.Cell.ECGI+1, -80))
+	assert.NoError(t, ueStore.MoveToCoordinate(ctx, ue.IMSI, from, 0))
+	assert.LessOrEqual(t, ue.Speed, radio.Distance(from, to)/0.1)
+
+	// Standing still results in a speed of zero
+	assert.NoError(t, ueStore.MoveToCoordinate(ctx, ue.IMSI, from, 0))
+	assert.Equal(t, 0.0, ue.Speed)
+}
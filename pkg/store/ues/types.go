@@ -16,9 +16,15 @@ const (
 	Updated
 	// Deleted deleted  ue event
 	Deleted
+	// HandedOver ue event of a UE handed over to another cell
+	HandedOver
+	// Admitted ue event of a UE admitted by its serving cell
+	Admitted
+	// Released ue event of a UE released by its serving cell
+	Released
 )
 
 // String converts node event to string
 func (e UeEvent) String() string {
-	return [...]string{"None", "Created", "Updated", "Deleted"}[e]
+	return [...]string{"None", "Created", "Updated", "Deleted", "HandedOver", "Admitted", "Released"}[e]
 }
@@ -8,8 +8,9 @@ import (
 	"context"
 	"math/rand"
 	"sync"
+	"time"
 
-	"github.com/google/uuid"
+	"github.com/onosproject/ran-simulator/pkg/store/txn"
 	"github.com/onosproject/ran-simulator/pkg/store/watcher"
 
 	"github.com/onosproject/ran-simulator/pkg/store/event"
@@ -17,13 +18,25 @@ import (
 	"github.com/onosproject/onos-api/go/onos/ransim/types"
 	"github.com/onosproject/onos-lib-go/pkg/errors"
 	liblog "github.com/onosproject/onos-lib-go/pkg/logging"
+	"github.com/onosproject/ran-simulator/pkg/journal"
 	"github.com/onosproject/ran-simulator/pkg/model"
+	"github.com/onosproject/ran-simulator/pkg/radio"
 	"github.com/onosproject/ran-simulator/pkg/store/cells"
 )
 
 const (
 	minIMSI = 1000000
 	maxIMSI = 9999999
+
+	minDRBID = 1
+	maxDRBID = 32
+
+	minPDUSessionID = 1
+	maxPDUSessionID = 15
+
+	// C-RNTI values assignable to UEs
+	minCRNTI = 0x0001
+	maxCRNTI = 0xffef
 )
 
 var log = liblog.GetLogger("store", "ues")
@@ -39,6 +52,9 @@ type Store interface {
 	// CreateUEs creates the specified number of UEs
 	CreateUEs(ctx context.Context, count uint)
 
+	// Add adds the specified UE
+	Add(ctx context.Context, ue *model.UE) error
+
 	// Get retrieves the UE with the specified IMSI
 	Get(ctx context.Context, imsi types.IMSI) (*model.UE, error)
 
@@ -57,6 +73,45 @@ type Store interface {
 	// ListUEs returns an array of all UEs associated with the specified cell
 	ListUEs(ctx context.Context, ecgi types.ECGI) []*model.UE
 
+	// UpdateRrcState updates the RRC state of the specified UE
+	UpdateRrcState(ctx context.Context, imsi types.IMSI, state model.RrcState) error
+
+	// AdmitUE admits the specified UE to its serving cell, allocating a C-RNTI unique within the cell
+	AdmitUE(ctx context.Context, imsi types.IMSI) error
+
+	// ReleaseUE releases the admission of the specified UE and its C-RNTI
+	ReleaseUE(ctx context.Context, imsi types.IMSI) error
+
+	// UpdateMeasurements updates the serving cell strength, the measured candidate cells, the measurement gap
+	// configuration and the triggered measurement events of the specified UE, measured in the specified serving
+	// cell; the update is rejected as a conflict if the UE is no longer served by that cell
+	UpdateMeasurements(ctx context.Context, imsi types.IMSI, ecgi types.ECGI, strength float64, candidates []*model.UECell, measGaps bool, reports []*model.MeasReport) error
+
+	// UpdateRegistrationArea updates the tracking areas the specified UE is registered in
+	UpdateRegistrationArea(ctx context.Context, imsi types.IMSI, tacs []uint32) error
+
+	// UpdateIndoor updates whether the specified UE is within a building
+	UpdateIndoor(ctx context.Context, imsi types.IMSI, indoor bool) error
+
+	// AddDRB establishes a new data radio bearer for the specified UE
+	AddDRB(ctx context.Context, imsi types.IMSI, drb *model.DRB) error
+
+	// UpdateDRB modifies an existing data radio bearer of the specified UE
+	UpdateDRB(ctx context.Context, imsi types.IMSI, drb *model.DRB) error
+
+	// DeleteDRB releases the data radio bearer with the specified ID
+	DeleteDRB(ctx context.Context, imsi types.IMSI, drbID int32) (*model.DRB, error)
+
+	// AddPDUSession adds a PDU session established for the specified UE
+	AddPDUSession(ctx context.Context, imsi types.IMSI, session *model.PDUSession) error
+
+	// DeletePDUSession removes the PDU session with the specified ID
+	DeletePDUSession(ctx context.Context, imsi types.IMSI, sessionID int32) (*model.PDUSession, error)
+
+	// Trajectory returns the positions and serving cells of the specified UE recorded at or after the given time,
+	// limited to the most recent TrajectoryLength ones
+	Trajectory(ctx context.Context, imsi types.IMSI, since time.Time) ([]model.TrajectoryPoint, error)
+
 	// Watch watches the UE inventory events using the supplied channel
 	Watch(ctx context.Context, ch chan<- event.Event, options ...WatchOptions) error
 }
@@ -65,25 +120,75 @@ type Store interface {
 type WatchOptions struct {
 	Replay  bool
 	Monitor bool
+	// IMSIs restricts the events to the specified UEs, if any
+	IMSIs []types.IMSI
+	// ECGIs restricts the events to the UEs served by the specified cells, if any
+	ECGIs []types.ECGI
+	// Types restricts the events to the specified event types, if any; replayed UEs are always sent
+	Types []UeEvent
+}
+
+// ueFilter returns the filter of the watched UEs
+func (o WatchOptions) ueFilter() func(ue *model.UE) bool {
+	imsis := make(map[types.IMSI]bool, len(o.IMSIs))
+	for _, imsi := range o.IMSIs {
+		imsis[imsi] = true
+	}
+	ecgis := make(map[types.ECGI]bool, len(o.ECGIs))
+	for _, ecgi := range o.ECGIs {
+		ecgis[ecgi] = true
+	}
+	return func(ue *model.UE) bool {
+		return (len(imsis) == 0 || imsis[ue.IMSI]) &&
+			(len(ecgis) == 0 || (ue.Cell != nil && ecgis[ue.Cell.ECGI]))
+	}
+}
+
+// filter returns the filter of the events matching the options, or nil if all events are watched
+func (o WatchOptions) filter() watcher.Filter {
+	if len(o.IMSIs) == 0 && len(o.ECGIs) == 0 && len(o.Types) == 0 {
+		return nil
+	}
+	accepts := o.ueFilter()
+	eventTypes := make(map[UeEvent]bool, len(o.Types))
+	for _, t := range o.Types {
+		eventTypes[t] = true
+	}
+	return func(e event.Event) bool {
+		return (len(eventTypes) == 0 || eventTypes[e.Type.(UeEvent)]) && accepts(e.Value.(*model.UE))
+	}
 }
 
 type store struct {
 	mu        sync.RWMutex
 	ues       map[types.IMSI]*model.UE
 	cellStore cells.Store
+	placement model.PlacementConfig
 	watchers  *watcher.Watchers
+	nextCRNTI types.CRNTI
+
+	trajectories map[types.IMSI]*trajectory
 }
 
 // NewUERegistry creates a new user-equipment registry primed with the specified number of UEs to start.
 // UEs will be semi-randomly distributed between the specified cells
 func NewUERegistry(count uint, cellStore cells.Store) Store {
+	return NewUERegistryWithPlacement(count, cellStore, model.PlacementConfig{})
+}
+
+// NewUERegistryWithPlacement creates a new user-equipment registry primed with the specified number of UEs to start.
+// UEs will be placed as configured by the placement distribution and served by the cells covering their locations
+func NewUERegistryWithPlacement(count uint, cellStore cells.Store, placement model.PlacementConfig) Store {
 	log.Infof("Creating registry from model with %d UEs", count)
-	watchers := watcher.NewWatchers()
+	watchers := watcher.NewWatchers("ues")
 	store := &store{
 		mu:        sync.RWMutex{},
 		ues:       make(map[types.IMSI]*model.UE),
 		cellStore: cellStore,
+		placement: placement,
 		watchers:  watchers,
+
+		trajectories: make(map[types.IMSI]*trajectory),
 	}
 	ctx := context.Background()
 	store.CreateUEs(ctx, count)
@@ -92,7 +197,7 @@ func NewUERegistry(count uint, cellStore cells.Store) Store {
 }
 
 func (s *store) SetUECount(ctx context.Context, count uint) {
-	delta := len(s.ues) - int(count)
+	delta := s.Len(ctx) - int(count)
 	if delta < 0 {
 		s.CreateUEs(ctx, uint(-delta))
 	} else if delta > 0 {
@@ -101,17 +206,25 @@ func (s *store) SetUECount(ctx context.Context, count uint) {
 }
 
 func (s *store) Len(ctx context.Context) int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
 	return len(s.ues)
 }
 
+// removeSomeUEs deletes the given number of UEs; they are picked under the lock and deleted via Delete, as UEs may
+// be deleted concurrently, e.g. by changes of other instances applied to the cache
 func (s *store) removeSomeUEs(ctx context.Context, count int) {
-	c := count
+	s.mu.RLock()
+	imsis := make([]types.IMSI, 0, count)
 	for imsi := range s.ues {
-		if c == 0 {
+		if len(imsis) == count {
 			break
 		}
+		imsis = append(imsis, imsi)
+	}
+	s.mu.RUnlock()
+	for _, imsi := range imsis {
 		_, _ = s.Delete(ctx, imsi)
-		c = c - 1
 	}
 }
 
@@ -125,27 +238,60 @@ func (s *store) CreateUEs(ctx context.Context, count uint) {
 			imsi = types.IMSI(rand.Int63n(maxIMSI-minIMSI) + minIMSI)
 		}
 
-		randomCell, err := s.cellStore.GetRandomCell()
+		ueType := model.UEType("phone")
+		place := s.place
+		if s.placement.UAVShare > 0 && rand.Float64() < s.placement.UAVShare {
+			ueType = model.UAV
+			place = s.placeUAV
+		}
+		location, heading, cell, err := place(ctx, imsi)
 		if err != nil {
 			log.Error(err)
+			return
 		}
-		ecgi := randomCell.ECGI
+		ecgi := cell.ECGI
 		ue := &model.UE{
 			IMSI:     imsi,
-			Type:     "phone",
-			Location: model.Coordinate{Lat: 0, Lng: 0},
-			Heading:  0,
+			Type:     ueType,
+			Location: location,
+			Heading:  heading,
 			Cell: &model.UECell{
 				ID:       types.GEnbID(ecgi), // placeholder
 				ECGI:     ecgi,
 				Strength: rand.Float64() * 100,
 			},
-			CRNTI:      types.CRNTI(90125 + i),
-			Cells:      nil,
+			Cells:      s.initialNeighbors(ctx, cell, location),
 			IsAdmitted: false,
 		}
 		s.ues[ue.IMSI] = ue
+		s.recordPosition(ue)
+		journal.Record(journal.UEAttached, uint64(ue.IMSI), map[string]interface{}{"ecgi": ecgi})
+	}
+}
+
+// Add adds a UE with a given imsi
+func (s *store) Add(ctx context.Context, ue *model.UE) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.ues[ue.IMSI]; ok {
+		return errors.New(errors.AlreadyExists, "UE %d already exists", ue.IMSI)
+	}
+	// UEs admitted elsewhere, e.g. handed over by another instance, keep their C-RNTI unless taken in their cell
+	if ue.IsAdmitted && ue.Cell != nil && (ue.CRNTI == 0 || s.crntiUsed(ue.Cell.ECGI, ue.CRNTI)) {
+		ue.CRNTI = s.allocateCRNTI(ue.Cell.ECGI)
+	}
+	s.ues[ue.IMSI] = ue
+	s.recordPosition(ue)
+	createEvent := event.Event{
+		Key:   ue.IMSI,
+		Value: ue,
+		Type:  Created,
 	}
+	s.watchers.SendContext(ctx, createEvent)
+	if ue.Cell != nil {
+		journal.Record(journal.UEAttached, uint64(ue.IMSI), map[string]interface{}{"ecgi": ue.Cell.ECGI})
+	}
+	return nil
 }
 
 // Get gets a UE based on a given imsi
@@ -165,12 +311,14 @@ func (s *store) Delete(ctx context.Context, imsi types.IMSI) (*model.UE, error)
 	defer s.mu.Unlock()
 	if ue, ok := s.ues[imsi]; ok {
 		delete(s.ues, imsi)
+		delete(s.trajectories, imsi)
 		deleteEvent := event.Event{
 			Key:   imsi,
 			Value: ue,
 			Type:  Deleted,
 		}
-		s.watchers.Send(deleteEvent)
+		s.watchers.SendContext(ctx, deleteEvent)
+		journal.Record(journal.UEDetached, uint64(imsi), nil)
 		return ue, nil
 	}
 	return nil, errors.New(errors.NotFound, "UE not found")
@@ -190,14 +338,25 @@ func (s *store) MoveToCell(ctx context.Context, imsi types.IMSI, ecgi types.ECGI
 	s.mu.Lock()
 	defer s.mu.Unlock()
 	if ue, ok := s.ues[imsi]; ok {
+		eventType := Updated
+		if source := ue.Cell.ECGI; source != ecgi {
+			txn.Defer(ctx, func() {
+				journal.Record(journal.HandoverCompleted, uint64(imsi), map[string]interface{}{"source": source, "target": ecgi})
+			})
+			eventType = HandedOver
+			// The target cell allocates a new C-RNTI to admitted UEs
+			if ue.IsAdmitted {
+				ue.CRNTI = s.allocateCRNTI(ecgi)
+			}
+		}
 		ue.Cell.ECGI = ecgi
 		ue.Cell.Strength = strength
 		updateEvent := event.Event{
 			Key:   ue.IMSI,
 			Value: ue,
-			Type:  Updated,
+			Type:  eventType,
 		}
-		s.watchers.Send(updateEvent)
+		s.watchers.SendContext(ctx, updateEvent)
 		return nil
 	}
 	return errors.New(errors.NotFound, "UE not found")
@@ -207,14 +366,165 @@ func (s *store) MoveToCoordinate(ctx context.Context, imsi types.IMSI, location
 	s.mu.Lock()
 	defer s.mu.Unlock()
 	if ue, ok := s.ues[imsi]; ok {
+		if last, ok := s.lastPosition(imsi); ok {
+			if elapsed := time.Since(last.Time); elapsed > 0 {
+				ue.Speed = radio.Distance(last.Location, location) / elapsed.Seconds()
+			}
+		}
 		ue.Location = location
 		ue.Heading = heading
+		s.recordPosition(ue)
+		updateEvent := event.Event{
+			Key:   ue.IMSI,
+			Value: ue,
+			Type:  Updated,
+		}
+		s.watchers.SendContext(ctx, updateEvent)
+		return nil
+	}
+	return errors.New(errors.NotFound, "UE not found")
+}
+
+func (s *store) UpdateRrcState(ctx context.Context, imsi types.IMSI, state model.RrcState) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if ue, ok := s.ues[imsi]; ok {
+		ue.RrcState = state
+		updateEvent := event.Event{
+			Key:   ue.IMSI,
+			Value: ue,
+			Type:  Updated,
+		}
+		s.watchers.SendContext(ctx, updateEvent)
+		return nil
+	}
+	return errors.New(errors.NotFound, "UE not found")
+}
+
+func (s *store) AdmitUE(ctx context.Context, imsi types.IMSI) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ue, ok := s.ues[imsi]
+	if !ok {
+		return errors.New(errors.NotFound, "UE not found")
+	}
+	if ue.Cell == nil {
+		return errors.New(errors.Invalid, "UE %d has no serving cell", imsi)
+	}
+	if ue.IsAdmitted {
+		return nil
+	}
+	ue.CRNTI = s.allocateCRNTI(ue.Cell.ECGI)
+	ue.IsAdmitted = true
+	s.watchers.SendContext(ctx, event.Event{
+		Key:   ue.IMSI,
+		Value: ue,
+		Type:  Admitted,
+	})
+	return nil
+}
+
+func (s *store) ReleaseUE(ctx context.Context, imsi types.IMSI) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ue, ok := s.ues[imsi]
+	if !ok {
+		return errors.New(errors.NotFound, "UE not found")
+	}
+	if !ue.IsAdmitted {
+		return nil
+	}
+	ue.CRNTI = 0
+	ue.IsAdmitted = false
+	s.watchers.SendContext(ctx, event.Event{
+		Key:   ue.IMSI,
+		Value: ue,
+		Type:  Released,
+	})
+	return nil
+}
+
+// crntiUsed returns true if the C-RNTI is allocated to a UE admitted by the specified cell
+func (s *store) crntiUsed(ecgi types.ECGI, crnti types.CRNTI) bool {
+	for _, ue := range s.ues {
+		if ue.IsAdmitted && ue.CRNTI == crnti && ue.Cell != nil && ue.Cell.ECGI == ecgi {
+			return true
+		}
+	}
+	return false
+}
+
+// allocateCRNTI allocates the next C-RNTI not used by the UEs admitted by the specified cell; C-RNTIs are
+// allocated round robin so that released ones are not reused right away. Zero is returned if none is left.
+func (s *store) allocateCRNTI(ecgi types.ECGI) types.CRNTI {
+	used := make(map[types.CRNTI]bool)
+	for _, ue := range s.ues {
+		if ue.IsAdmitted && ue.Cell != nil && ue.Cell.ECGI == ecgi {
+			used[ue.CRNTI] = true
+		}
+	}
+	for i := minCRNTI; i <= maxCRNTI; i++ {
+		if s.nextCRNTI < minCRNTI || s.nextCRNTI > maxCRNTI {
+			s.nextCRNTI = minCRNTI
+		}
+		crnti := s.nextCRNTI
+		s.nextCRNTI++
+		if !used[crnti] {
+			return crnti
+		}
+	}
+	return 0
+}
+
+func (s *store) UpdateMeasurements(ctx context.Context, imsi types.IMSI, ecgi types.ECGI, strength float64, candidates []*model.UECell, measGaps bool, reports []*model.MeasReport) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if ue, ok := s.ues[imsi]; ok {
+		if ue.Cell == nil || ue.Cell.ECGI != ecgi {
+			return errors.New(errors.Conflict, "UE %d is no longer served by cell %d", imsi, ecgi)
+		}
+		ue.Cell.Strength = strength
+		ue.Cells = candidates
+		ue.MeasGaps = measGaps
+		ue.MeasReports = reports
+		updateEvent := event.Event{
+			Key:   ue.IMSI,
+			Value: ue,
+			Type:  Updated,
+		}
+		s.watchers.SendContext(ctx, updateEvent)
+		return nil
+	}
+	return errors.New(errors.NotFound, "UE not found")
+}
+
+func (s *store) UpdateRegistrationArea(ctx context.Context, imsi types.IMSI, tacs []uint32) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if ue, ok := s.ues[imsi]; ok {
+		ue.RegistrationArea = tacs
+		updateEvent := event.Event{
+			Key:   ue.IMSI,
+			Value: ue,
+			Type:  Updated,
+		}
+		s.watchers.SendContext(ctx, updateEvent)
+		return nil
+	}
+	return errors.New(errors.NotFound, "UE not found")
+}
+
+func (s *store) UpdateIndoor(ctx context.Context, imsi types.IMSI, indoor bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if ue, ok := s.ues[imsi]; ok {
+		ue.Indoor = indoor
 		updateEvent := event.Event{
 			Key:   ue.IMSI,
 			Value: ue,
 			Type:  Updated,
 		}
-		s.watchers.Send(updateEvent)
+		s.watchers.SendContext(ctx, updateEvent)
 		return nil
 	}
 	return errors.New(errors.NotFound, "UE not found")
@@ -232,32 +542,140 @@ func (s *store) ListUEs(ctx context.Context, ecgi types.ECGI) []*model.UE {
 	return list
 }
 
+func (s *store) AddDRB(ctx context.Context, imsi types.IMSI, drb *model.DRB) error {
+	if drb.ID < minDRBID || drb.ID > maxDRBID {
+		return errors.New(errors.Invalid, "DRB ID must be in range [%d, %d]", minDRBID, maxDRBID)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if ue, ok := s.ues[imsi]; ok {
+		if _, ok := ue.GetDRB(drb.ID); ok {
+			return errors.New(errors.AlreadyExists, "DRB already exists")
+		}
+		ue.DRBs = append(ue.DRBs, drb)
+		updateEvent := event.Event{
+			Key:   ue.IMSI,
+			Value: ue,
+			Type:  Updated,
+		}
+		s.watchers.SendContext(ctx, updateEvent)
+		return nil
+	}
+	return errors.New(errors.NotFound, "UE not found")
+}
+
+func (s *store) UpdateDRB(ctx context.Context, imsi types.IMSI, drb *model.DRB) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if ue, ok := s.ues[imsi]; ok {
+		for i, d := range ue.DRBs {
+			if d.ID == drb.ID {
+				ue.DRBs[i] = drb
+				updateEvent := event.Event{
+					Key:   ue.IMSI,
+					Value: ue,
+					Type:  Updated,
+				}
+				s.watchers.SendContext(ctx, updateEvent)
+				return nil
+			}
+		}
+		return errors.New(errors.NotFound, "DRB not found")
+	}
+	return errors.New(errors.NotFound, "UE not found")
+}
+
+func (s *store) DeleteDRB(ctx context.Context, imsi types.IMSI, drbID int32) (*model.DRB, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if ue, ok := s.ues[imsi]; ok {
+		for i, drb := range ue.DRBs {
+			if drb.ID == drbID {
+				ue.DRBs = append(ue.DRBs[:i], ue.DRBs[i+1:]...)
+				updateEvent := event.Event{
+					Key:   ue.IMSI,
+					Value: ue,
+					Type:  Updated,
+				}
+				s.watchers.SendContext(ctx, updateEvent)
+				return drb, nil
+			}
+		}
+		return nil, errors.New(errors.NotFound, "DRB not found")
+	}
+	return nil, errors.New(errors.NotFound, "UE not found")
+}
+
+func (s *store) AddPDUSession(ctx context.Context, imsi types.IMSI, session *model.PDUSession) error {
+	if session.ID < minPDUSessionID || session.ID > maxPDUSessionID {
+		return errors.New(errors.Invalid, "PDU session ID must be in range [%d, %d]", minPDUSessionID, maxPDUSessionID)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if ue, ok := s.ues[imsi]; ok {
+		if _, ok := ue.GetPDUSession(session.ID); ok {
+			return errors.New(errors.AlreadyExists, "PDU session already exists")
+		}
+		ue.PDUSessions = append(ue.PDUSessions, session)
+		updateEvent := event.Event{
+			Key:   ue.IMSI,
+			Value: ue,
+			Type:  Updated,
+		}
+		s.watchers.SendContext(ctx, updateEvent)
+		return nil
+	}
+	return errors.New(errors.NotFound, "UE not found")
+}
+
+func (s *store) DeletePDUSession(ctx context.Context, imsi types.IMSI, sessionID int32) (*model.PDUSession, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if ue, ok := s.ues[imsi]; ok {
+		for i, session := range ue.PDUSessions {
+			if session.ID == sessionID {
+				ue.PDUSessions = append(ue.PDUSessions[:i], ue.PDUSessions[i+1:]...)
+				updateEvent := event.Event{
+					Key:   ue.IMSI,
+					Value: ue,
+					Type:  Updated,
+				}
+				s.watchers.SendContext(ctx, updateEvent)
+				return session, nil
+			}
+		}
+		return nil, errors.New(errors.NotFound, "PDU session not found")
+	}
+	return nil, errors.New(errors.NotFound, "UE not found")
+}
+
 func (s *store) Watch(ctx context.Context, ch chan<- event.Event, options ...WatchOptions) error {
 	log.Debug("Watching ue changes")
-	replay := len(options) > 0 && options[0].Replay
+	var watchOptions WatchOptions
+	if len(options) > 0 {
+		watchOptions = options[0]
+	}
 
-	id := uuid.New()
-	err := s.watchers.AddWatcher(id, ch)
-	if err != nil {
+	if err := s.watchers.Watch(ctx, ch, watchOptions.filter()); err != nil {
 		log.Error(err)
-		close(ch)
 		return err
 	}
-	go func() {
-		<-ctx.Done()
-		err = s.watchers.RemoveWatcher(id)
-		if err != nil {
-			log.Error(err)
-		}
-		close(ch)
-	}()
 
-	if replay {
+	if watchOptions.Replay {
 		wg := sync.WaitGroup{}
 		wg.Add(1)
+		accepts := watchOptions.ueFilter()
+		s.mu.RLock()
+		replayed := make([]*model.UE, 0, len(s.ues))
+		for _, ue := range s.ues {
+			if accepts(ue) {
+				replayed = append(replayed, ue)
+			}
+		}
+		s.mu.RUnlock()
 		go func() {
 			defer wg.Done()
-			for _, ue := range s.ues {
+			for _, ue := range replayed {
 				ch <- event.Event{
 					Key:   ue.IMSI,
 					Value: ue,
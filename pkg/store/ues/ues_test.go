@@ -9,9 +9,14 @@ import (
 	"io/ioutil"
 	"math/rand"
 	"testing"
+	"time"
 
+	"github.com/onosproject/onos-api/go/onos/ransim/types"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
 	"github.com/onosproject/ran-simulator/pkg/model"
+	"github.com/onosproject/ran-simulator/pkg/radio"
 	"github.com/onosproject/ran-simulator/pkg/store/cells"
+	"github.com/onosproject/ran-simulator/pkg/store/event"
 	"github.com/onosproject/ran-simulator/pkg/store/nodes"
 	"gopkg.in/yaml.v2"
 
@@ -74,6 +79,122 @@ func TestMoveUEToCell(t *testing.T) {
 	assert.Equal(t, 6, len(ues.ListUEs(ctx, ecgi2)))
 }
 
+func TestUpdateMeasurements(t *testing.T) {
+	ctx := context.Background()
+	for _, ues := range []Store{NewUERegistry(1, cellStore(t)), NewCompactUERegistry(1, cellStore(t), model.PlacementConfig{})} {
+		ue := ues.ListAllUEs(ctx)[0]
+		serving := ue.Cell.ECGI
+		assert.NoError(t, ues.UpdateMeasurements(ctx, ue.IMSI, serving, -80, nil, false, nil))
+
+		// Measurements taken in the former serving cell of a UE handed over meanwhile are rejected
+		err := ues.UpdateMeasurements(ctx, ue.IMSI, serving+1, -70, nil, true, nil)
+		assert.True(t, errors.IsConflict(err))
+		ue, err = ues.Get(ctx, ue.IMSI)
+		assert.NoError(t, err)
+		assert.Equal(t, -80.0, ue.Cell.Strength)
+		assert.False(t, ue.MeasGaps)
+	}
+}
+
+func TestInitialNeighbors(t *testing.T) {
+	ctx := context.Background()
+	cellStore := cellStore(t)
+	cellList, err := cellStore.List(ctx)
+	assert.NoError(t, err)
+	for _, cell := range cellList {
+		updated := *cell
+		updated.Neighbors = nil
+		for _, other := range cellList {
+			if other.ECGI != cell.ECGI {
+				updated.Neighbors = append(updated.Neighbors, other.ECGI)
+			}
+		}
+		switch cell.ECGI {
+		case 84325717761:
+			updated.Earfcn = cell.Earfcn + 1
+		case 84325717762:
+			updated.Locked = true
+		}
+		assert.NoError(t, cellStore.Update(ctx, &updated))
+	}
+
+	// New UEs list the neighbors in service of their serving cell on its carrier, strongest first
+	for _, ues := range []Store{NewUERegistry(16, cellStore), NewCompactUERegistry(16, cellStore, model.PlacementConfig{})} {
+		for _, ue := range ues.ListAllUEs(ctx) {
+			var expected []types.ECGI
+			switch ue.Cell.ECGI {
+			case 84325717505:
+				expected = []types.ECGI{84325717506}
+			case 84325717506:
+				expected = []types.ECGI{84325717505}
+			case 84325717762:
+				expected = []types.ECGI{84325717505, 84325717506}
+			}
+			var listed []types.ECGI
+			for i, neighbor := range ue.Cells {
+				listed = append(listed, neighbor.ECGI)
+				cell, err := cellStore.Get(ctx, neighbor.ECGI)
+				assert.NoError(t, err)
+				assert.Equal(t, radio.RSRP(cell, ue.Location), neighbor.Strength)
+				if i > 0 {
+					assert.True(t, ue.Cells[i-1].Strength >= neighbor.Strength)
+				}
+			}
+			assert.ElementsMatch(t, expected, listed)
+		}
+	}
+}
+
+func TestWatchHandovers(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ues := NewUERegistry(4, cellStore(t))
+	ue := ues.ListAllUEs(ctx)[0]
+	source := ue.Cell.ECGI
+	target := types.ECGI(84325717505)
+	if source == target {
+		target = 84325717506
+	}
+
+	// Only handovers to the watched cell are reported
+	ch := make(chan event.Event, 10)
+	assert.NoError(t, ues.Watch(ctx, ch, WatchOptions{ECGIs: []types.ECGI{target}, Types: []UeEvent{HandedOver}}))
+	assert.NoError(t, ues.MoveToCell(ctx, ue.IMSI, source, 5))
+	assert.NoError(t, ues.MoveToCoordinate(ctx, ue.IMSI, model.Coordinate{Lat: 50.0755, Lng: 14.4378}, 182))
+	assert.NoError(t, ues.MoveToCell(ctx, ue.IMSI, target, 5))
+
+	ueEvent := <-ch
+	assert.Equal(t, HandedOver, ueEvent.Type)
+	assert.Equal(t, ue.IMSI, ueEvent.Key)
+	select {
+	case ueEvent = <-ch:
+		assert.Fail(t, "unexpected event", ueEvent.Type)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestAdmitUE(t *testing.T) {
+	ctx := context.Background()
+	ues := NewUERegistry(2, cellStore(t))
+	list := ues.ListAllUEs(ctx)
+	for _, ue := range list {
+		assert.NoError(t, ues.MoveToCell(ctx, ue.IMSI, 84325717505, 10))
+		assert.NoError(t, ues.AdmitUE(ctx, ue.IMSI))
+		assert.True(t, ue.IsAdmitted)
+	}
+	assert.NotEqual(t, list[0].CRNTI, list[1].CRNTI)
+
+	// Admitted UEs are allocated a new C-RNTI by the target cell of a handover
+	crnti := list[0].CRNTI
+	assert.NoError(t, ues.MoveToCell(ctx, list[0].IMSI, 84325717506, 10))
+	assert.True(t, list[0].IsAdmitted)
+	assert.NotEqual(t, crnti, list[0].CRNTI)
+
+	assert.NoError(t, ues.ReleaseUE(ctx, list[0].IMSI))
+	assert.False(t, list[0].IsAdmitted)
+	assert.Zero(t, list[0].CRNTI)
+}
+
 func TestMoveUEToCoord(t *testing.T) {
 	ctx := context.Background()
 	cellStore := cellStore(t)
@@ -90,3 +211,31 @@ func TestMoveUEToCoord(t *testing.T) {
 	assert.Equal(t, 14.4378, ue1.Location.Lng)
 	assert.Equal(t, uint32(182), ue1.Heading)
 }
+
+func TestUEDRBs(t *testing.T) {
+	ctx := context.Background()
+	ues := NewUERegistry(1, cellStore(t))
+	ue := ues.ListAllUEs(ctx)[0]
+
+	drb := &model.DRB{ID: 1, QoSFlows: []*model.QoSFlow{{QFI: 1, FiveQI: 9, ARP: 15}}}
+	err := ues.AddDRB(ctx, ue.IMSI, drb)
+	assert.NoError(t, err)
+	err = ues.AddDRB(ctx, ue.IMSI, drb)
+	assert.Error(t, err)
+	err = ues.AddDRB(ctx, ue.IMSI, &model.DRB{ID: 33})
+	assert.Error(t, err)
+
+	err = ues.UpdateDRB(ctx, ue.IMSI, &model.DRB{ID: 1, QoSFlows: []*model.QoSFlow{{QFI: 1, FiveQI: 1, ARP: 2, GBR: true}}})
+	assert.NoError(t, err)
+	ue1, err := ues.Get(ctx, ue.IMSI)
+	assert.NoError(t, err)
+	drb1, ok := ue1.GetDRB(1)
+	assert.True(t, ok)
+	assert.Equal(t, int32(1), drb1.QoSFlows[0].FiveQI)
+	assert.True(t, drb1.QoSFlows[0].GBR)
+
+	_, err = ues.DeleteDRB(ctx, ue.IMSI, 1)
+	assert.NoError(t, err)
+	_, err = ues.DeleteDRB(ctx, ue.IMSI, 1)
+	assert.Error(t, err)
+}
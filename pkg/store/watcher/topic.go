@@ -0,0 +1,60 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package watcher
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// TopicStats are the statistics of the events of a topic, summed over all watchers of the topic, e.g. of the stores
+// of several tenants
+type TopicStats struct {
+	// Watchers is the number of registered watchers
+	Watchers int64 `json:"watchers"`
+	// Delivered counts the events delivered to the watchers
+	Delivered uint64 `json:"delivered"`
+	// Dropped counts the events not delivered as their watcher was removed first
+	Dropped uint64 `json:"dropped"`
+}
+
+// topic holds the counters of a topic, updated atomically
+type topic struct {
+	delivered uint64
+	dropped   uint64
+	watchers  int64
+}
+
+var (
+	topics   = make(map[string]*topic)
+	topicsMu sync.Mutex
+)
+
+// getTopic returns the counters of the named topic, creating them if needed
+func getTopic(name string) *topic {
+	topicsMu.Lock()
+	defer topicsMu.Unlock()
+	t, ok := topics[name]
+	if !ok {
+		t = &topic{}
+		topics[name] = t
+	}
+	return t
+}
+
+// Stats returns the statistics of the events of all topics by topic
+func Stats() map[string]TopicStats {
+	topicsMu.Lock()
+	defer topicsMu.Unlock()
+	stats := make(map[string]TopicStats, len(topics))
+	for name, t := range topics {
+		stats[name] = TopicStats{
+			Watchers:  atomic.LoadInt64(&t.watchers),
+			Delivered: atomic.LoadUint64(&t.delivered),
+			Dropped:   atomic.LoadUint64(&t.dropped),
+		}
+	}
+	return stats
+}
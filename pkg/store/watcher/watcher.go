@@ -5,11 +5,15 @@
 package watcher
 
 import (
+	"context"
 	"sync"
+	"sync/atomic"
 
 	"github.com/google/uuid"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
 
 	"github.com/onosproject/ran-simulator/pkg/store/event"
+	"github.com/onosproject/ran-simulator/pkg/store/txn"
 )
 
 // EventChannel is a channel which can accept an Event
@@ -17,59 +21,145 @@ type EventChannel chan event.Event
 
 // Watchers stores the information about watchers
 type Watchers struct {
-	watchers map[uuid.UUID]Watcher
+	topic    *topic
+	watchers map[uuid.UUID]*Watcher
 	rm       sync.RWMutex
 }
 
+// Filter returns true if the event shall be sent to a watcher
+type Filter func(event event.Event) bool
+
 // Watcher event watcher
 type Watcher struct {
-	id uuid.UUID
-	ch chan<- event.Event
+	id     uuid.UUID
+	ch     chan<- event.Event
+	filter Filter
+	// done is closed once the watcher is removed, stopping the deliveries in flight
+	done chan struct{}
+	// inflight tracks the deliveries to the watcher in flight
+	inflight sync.WaitGroup
 }
 
-// NewWatchers creates watchers
-func NewWatchers() *Watchers {
+// NewWatchers creates watchers of the events of the specified topic, e.g. ues, whose deliveries are counted in the
+// statistics of the topic
+func NewWatchers(topic string) *Watchers {
 	return &Watchers{
-		watchers: make(map[uuid.UUID]Watcher),
+		topic:    getTopic(topic),
+		watchers: make(map[uuid.UUID]*Watcher),
 	}
 }
 
 // Send sends an event for all registered watchers
 func (ws *Watchers) Send(event event.Event) {
+	ws.send(event)
+}
+
+// SendContext sends an event for all registered watchers once the transaction of the context commits, if the change
+// is made by a transaction; the event is dropped if the transaction is rolled back
+func (ws *Watchers) SendContext(ctx context.Context, event event.Event) {
+	txn.Defer(ctx, func() {
+		ws.send(event)
+	})
+}
+
+// send delivers the events in the background to the watchers registered at the time of the call; the deliveries are
+// tracked per watcher, under the lock, so that removing a watcher can wait for them to stop
+func (ws *Watchers) send(events ...event.Event) {
 	ws.rm.RLock()
+	watchers := make([]*Watcher, 0, len(ws.watchers))
+	for _, watcher := range ws.watchers {
+		watcher.inflight.Add(1)
+		watchers = append(watchers, watcher)
+	}
+	ws.rm.RUnlock()
 	go func() {
-		for _, watcher := range ws.watchers {
-			watcher.ch <- event
+		for _, event := range events {
+			for _, watcher := range watchers {
+				if watcher.filter == nil || watcher.filter(event) {
+					watcher.deliver(event, ws.topic)
+				}
+			}
+		}
+		for _, watcher := range watchers {
+			watcher.inflight.Done()
 		}
 	}()
-	ws.rm.RUnlock()
+}
+
+// deliver writes the event to the channel of the watcher unless the watcher is removed first, in which case the event
+// is dropped
+func (w *Watcher) deliver(event event.Event, topic *topic) {
+	select {
+	case <-w.done:
+		atomic.AddUint64(&topic.dropped, 1)
+		return
+	default:
+	}
+	select {
+	case w.ch <- event:
+		atomic.AddUint64(&topic.delivered, 1)
+	case <-w.done:
+		atomic.AddUint64(&topic.dropped, 1)
+	}
 }
 
 // AddWatcher adds a watcher
 func (ws *Watchers) AddWatcher(id uuid.UUID, ch chan<- event.Event) error {
+	return ws.AddFilteredWatcher(id, ch, nil)
+}
+
+// AddFilteredWatcher adds a watcher only receiving the events accepted by the specified filter; a nil filter accepts all events
+func (ws *Watchers) AddFilteredWatcher(id uuid.UUID, ch chan<- event.Event, filter Filter) error {
 	ws.rm.Lock()
-	watcher := Watcher{
-		id: id,
-		ch: ch,
+	defer ws.rm.Unlock()
+	if _, ok := ws.watchers[id]; ok {
+		return errors.New(errors.AlreadyExists, "watcher %s already exists", id)
 	}
-	ws.watchers[id] = watcher
-	ws.rm.Unlock()
+	ws.watchers[id] = &Watcher{
+		id:     id,
+		ch:     ch,
+		filter: filter,
+		done:   make(chan struct{}),
+	}
+	atomic.AddInt64(&ws.topic.watchers, 1)
 	return nil
-
 }
 
-// RemoveWatcher removes a watcher
+// RemoveWatcher removes a watcher and waits for the deliveries to it in flight to stop, dropping their events; the
+// channel of the watcher receives no more events once it returns
 func (ws *Watchers) RemoveWatcher(id uuid.UUID) error {
 	ws.rm.Lock()
-	watchers := make(map[uuid.UUID]Watcher, len(ws.watchers)-1)
-	for _, watcher := range ws.watchers {
-		if watcher.id != id {
-			watchers[id] = watcher
-
-		}
-	}
-	ws.watchers = watchers
+	watcher, ok := ws.watchers[id]
+	delete(ws.watchers, id)
 	ws.rm.Unlock()
+	if !ok {
+		return nil
+	}
+	close(watcher.done)
+	watcher.inflight.Wait()
+	atomic.AddInt64(&ws.topic.watchers, -1)
 	return nil
+}
+
+// Watched returns true if any watcher is registered, letting stores skip building events nobody receives
+func (ws *Watchers) Watched() bool {
+	ws.rm.RLock()
+	defer ws.rm.RUnlock()
+	return len(ws.watchers) > 0
+}
 
+// Watch adds a watcher receiving the events accepted by the specified filter until the context is done;
+// the channel is closed once the watcher is removed and its deliveries in flight stopped
+func (ws *Watchers) Watch(ctx context.Context, ch chan<- event.Event, filter Filter) error {
+	id := uuid.New()
+	if err := ws.AddFilteredWatcher(id, ch, filter); err != nil {
+		close(ch)
+		return err
+	}
+	go func() {
+		<-ctx.Done()
+		_ = ws.RemoveWatcher(id)
+		close(ch)
+	}()
+	return nil
 }
@@ -0,0 +1,99 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package watcher
+
+import (
+	"context"
+	"testing"
+
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/onosproject/ran-simulator/pkg/store/event"
+	"github.com/onosproject/ran-simulator/pkg/store/txn"
+	"github.com/stretchr/testify/assert"
+)
+
+// keys returns the keys of the specified number of events received from the channel
+func keys(ch <-chan event.Event, n int) []interface{} {
+	keys := make([]interface{}, n)
+	for i := range keys {
+		keys[i] = (<-ch).Key
+	}
+	return keys
+}
+
+func TestWatch(t *testing.T) {
+	watchers := NewWatchers("test")
+	assert.False(t, watchers.Watched())
+	ctx1, cancel1 := context.WithCancel(context.Background())
+	ch1 := make(chan event.Event, 10)
+	assert.NoError(t, watchers.Watch(ctx1, ch1, nil))
+	assert.True(t, watchers.Watched())
+	ch2 := make(chan event.Event, 10)
+	assert.NoError(t, watchers.Watch(context.Background(), ch2, func(e event.Event) bool {
+		return e.Key != 1
+	}))
+	ch3 := make(chan event.Event, 10)
+	assert.NoError(t, watchers.Watch(context.Background(), ch3, nil))
+
+	watchers.Send(event.Event{Key: 1})
+	watchers.Send(event.Event{Key: 2})
+	assert.ElementsMatch(t, []interface{}{1, 2}, keys(ch1, 2))
+	assert.ElementsMatch(t, []interface{}{2}, keys(ch2, 1))
+	assert.ElementsMatch(t, []interface{}{1, 2}, keys(ch3, 2))
+
+	// The channel of a removed watcher is closed; the other watchers keep receiving events
+	cancel1()
+	_, ok := <-ch1
+	assert.False(t, ok)
+	watchers.Send(event.Event{Key: 3})
+	assert.Equal(t, 3, (<-ch2).Key)
+	assert.Equal(t, 3, (<-ch3).Key)
+}
+
+func TestRemoveInFlight(t *testing.T) {
+	watchers := NewWatchers("inflight")
+	before := Stats()["inflight"]
+	ctx, cancel := context.WithCancel(context.Background())
+	ch := make(chan event.Event)
+	assert.NoError(t, watchers.Watch(ctx, ch, nil))
+
+	// Deliveries blocked on a watcher nobody reads are dropped, rather than sent to the closed channel, once the
+	// watcher is removed
+	watchers.Send(event.Event{Key: 1})
+	watchers.Send(event.Event{Key: 2})
+	cancel()
+	for range ch {
+	}
+	watchers.Send(event.Event{Key: 3})
+
+	stats := Stats()["inflight"]
+	assert.Equal(t, int64(0), stats.Watchers)
+	assert.Equal(t, uint64(2), stats.Delivered+stats.Dropped-before.Delivered-before.Dropped)
+}
+
+func TestSendContext(t *testing.T) {
+	ctx := context.Background()
+	watchers := NewWatchers("test")
+	ch := make(chan event.Event, 10)
+	assert.NoError(t, watchers.Watch(ctx, ch, nil))
+	transactions := txn.NewTransactions()
+
+	// The events of a failed transaction are dropped, whereas the events sent meanwhile by others are delivered
+	err := transactions.Update(ctx, func(txCtx context.Context, tx *txn.Txn) error {
+		watchers.SendContext(txCtx, event.Event{Key: 1})
+		watchers.SendContext(ctx, event.Event{Key: 2})
+		assert.Equal(t, []interface{}{2}, keys(ch, 1))
+		return errors.NewInvalid("failed")
+	})
+	assert.Error(t, err)
+
+	// The events of a committed transaction are delivered once it completes
+	assert.NoError(t, transactions.Update(ctx, func(txCtx context.Context, tx *txn.Txn) error {
+		watchers.SendContext(txCtx, event.Event{Key: 3})
+		return nil
+	}))
+	assert.Equal(t, []interface{}{3}, keys(ch, 1))
+	assert.Empty(t, ch)
+}
@@ -0,0 +1,53 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package tenant
+
+import (
+	"context"
+	"net"
+	"sync"
+
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// bufferSize is the size of the in-process connections to the services of the tenants
+const bufferSize = 1 << 20
+
+// Backend is the in-process endpoint of the northbound services of a tenant; it outlives the restarts of their
+// server, the connections to the backend reconnecting to the latest server
+type Backend struct {
+	mu  sync.Mutex
+	lis *bufconn.Listener
+}
+
+// NewBackend creates a new backend without server
+func NewBackend() *Backend {
+	return &Backend{}
+}
+
+// Listen returns the listener of a new server of the tenant services, replacing the previous server
+func (b *Backend) Listen() net.Listener {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.lis = bufconn.Listen(bufferSize)
+	return b.lis
+}
+
+// Dial returns a new connection to the tenant services; the requests are not secured, the tenant services being
+// reached only through the router
+func (b *Backend) Dial(ctx context.Context) (*grpc.ClientConn, error) {
+	return grpc.DialContext(ctx, "passthrough:///tenant", grpc.WithInsecure(),
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) {
+			b.mu.Lock()
+			lis := b.lis
+			b.mu.Unlock()
+			if lis == nil {
+				return nil, errors.New(errors.Unavailable, "tenant services are not served")
+			}
+			return lis.Dial()
+		}))
+}
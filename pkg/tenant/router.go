@@ -0,0 +1,202 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package tenant routes the northbound requests to the tenants simulated side by side in one process, each tenant
+// simulating its own model with its own nodes, cells, UEs and E2T endpoints behind its own northbound services
+package tenant
+
+import (
+	"context"
+	"io"
+	"sort"
+	"sync"
+
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/onosproject/onos-lib-go/pkg/logging"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+var log = logging.GetLogger("tenant")
+
+const (
+	// MetadataKey is the gRPC metadata key naming the tenant a northbound request is meant for
+	MetadataKey = "ransim-tenant"
+	// Default is the name of the tenant the requests not naming any are meant for
+	Default = "default"
+)
+
+// frame is a message forwarded as is, without decoding it
+type frame struct {
+	payload []byte
+}
+
+// codec passes the forwarded messages through
+type codec struct{}
+
+func (codec) Marshal(v interface{}) ([]byte, error) {
+	f, ok := v.(*frame)
+	if !ok {
+		return nil, errors.New(errors.Internal, "unexpected message type %T", v)
+	}
+	return f.payload, nil
+}
+
+func (codec) Unmarshal(data []byte, v interface{}) error {
+	f, ok := v.(*frame)
+	if !ok {
+		return errors.New(errors.Internal, "unexpected message type %T", v)
+	}
+	f.payload = append(f.payload[:0], data...)
+	return nil
+}
+
+func (codec) Name() string {
+	return "proto"
+}
+
+func (c codec) String() string {
+	return c.Name()
+}
+
+// streamDesc describes the forwarded calls, all of them being forwarded as bidirectional streams
+var streamDesc = &grpc.StreamDesc{ServerStreams: true, ClientStreams: true}
+
+// Router forwards the northbound requests to the services of the tenant named by their metadata, the tenant
+// services being served by their own in-process gRPC servers
+type Router struct {
+	mu    sync.RWMutex
+	conns map[string]*grpc.ClientConn
+}
+
+// NewRouter creates a new router without tenants
+func NewRouter() *Router {
+	return &Router{
+		conns: make(map[string]*grpc.ClientConn),
+	}
+}
+
+// Add routes the requests for the named tenant to the given connection to its services
+func (r *Router) Add(name string, conn *grpc.ClientConn) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.conns[name] = conn
+}
+
+// Remove stops routing the requests for the named tenant
+func (r *Router) Remove(name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.conns, name)
+}
+
+// Tenants returns the names of the tenants, sorted
+func (r *Router) Tenants() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	names := make([]string, 0, len(r.conns))
+	for name := range r.conns {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ServerOptions returns the options of the gRPC server routing all requests through the router; the server must
+// not register any service of its own
+func (r *Router) ServerOptions() []grpc.ServerOption {
+	return []grpc.ServerOption{grpc.CustomCodec(codec{}), grpc.UnknownServiceHandler(r.forward)}
+}
+
+// conn returns the connection to the services of the tenant named by the metadata of the request
+func (r *Router) conn(ctx context.Context) (string, *grpc.ClientConn, error) {
+	name := Default
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if names := md.Get(MetadataKey); len(names) > 0 && names[0] != "" {
+			name = names[0]
+		}
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	conn, ok := r.conns[name]
+	if !ok {
+		return name, nil, errors.New(errors.NotFound, "unknown tenant %s", name)
+	}
+	return name, conn, nil
+}
+
+// forward forwards the request to the services of its tenant, relaying the messages in both directions until the
+// tenant service completes the call
+func (r *Router) forward(srv interface{}, serverStream grpc.ServerStream) error {
+	method, ok := grpc.MethodFromServerStream(serverStream)
+	if !ok {
+		return errors.Status(errors.New(errors.Internal, "unknown method")).Err()
+	}
+	name, conn, err := r.conn(serverStream.Context())
+	if err != nil {
+		return errors.Status(err).Err()
+	}
+	log.Debugf("Forwarding %s to tenant %s", method, name)
+
+	ctx, cancel := context.WithCancel(serverStream.Context())
+	defer cancel()
+	md, _ := metadata.FromIncomingContext(ctx)
+	ctx = metadata.NewOutgoingContext(ctx, md.Copy())
+	clientStream, err := conn.NewStream(ctx, streamDesc, method, grpc.ForceCodec(codec{}))
+	if err != nil {
+		return err
+	}
+
+	// Relay the requests to the tenant service, closing the stream once the client is done sending
+	go func() {
+		for {
+			f := &frame{}
+			if err := serverStream.RecvMsg(f); err != nil {
+				if err == io.EOF {
+					_ = clientStream.CloseSend()
+				} else {
+					cancel()
+				}
+				return
+			}
+			if err := clientStream.SendMsg(f); err != nil {
+				return
+			}
+		}
+	}()
+
+	// Relay the responses of the tenant service along with its headers and trailers
+	header, err := clientStream.Header()
+	if err != nil {
+		return err
+	}
+	if err := serverStream.SendHeader(header); err != nil {
+		return err
+	}
+	for {
+		f := &frame{}
+		if err := clientStream.RecvMsg(f); err != nil {
+			serverStream.SetTrailer(clientStream.Trailer())
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+		if err := serverStream.SendMsg(f); err != nil {
+			return err
+		}
+	}
+}
+
+// DialOptions returns the options of the connections whose requests are meant for the named tenant
+func DialOptions(name string) []grpc.DialOption {
+	return []grpc.DialOption{
+		grpc.WithChainUnaryInterceptor(func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+			return invoker(metadata.AppendToOutgoingContext(ctx, MetadataKey, name), method, req, reply, cc, opts...)
+		}),
+		grpc.WithChainStreamInterceptor(func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+			return streamer(metadata.AppendToOutgoingContext(ctx, MetadataKey, name), desc, cc, method, opts...)
+		}),
+	}
+}
@@ -0,0 +1,85 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package tenant
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// serveTenant serves a health service knowing only the given service on the backend
+func serveTenant(t *testing.T, backend *Backend, service string) *grpc.Server {
+	server := grpc.NewServer()
+	checker := health.NewServer()
+	checker.SetServingStatus(service, healthpb.HealthCheckResponse_SERVING)
+	healthpb.RegisterHealthServer(server, checker)
+	lis := backend.Listen()
+	go func() {
+		assert.NoError(t, server.Serve(lis))
+	}()
+	return server
+}
+
+func TestRouter(t *testing.T) {
+	ctx := context.Background()
+	router := NewRouter()
+	for _, name := range []string{Default, "lab-b"} {
+		backend := NewBackend()
+		server := serveTenant(t, backend, name)
+		defer server.Stop()
+		conn, err := backend.Dial(ctx)
+		assert.NoError(t, err)
+		defer conn.Close()
+		router.Add(name, conn)
+	}
+	assert.Equal(t, []string{Default, "lab-b"}, router.Tenants())
+
+	front := grpc.NewServer(router.ServerOptions()...)
+	defer front.Stop()
+	lis := bufconn.Listen(bufferSize)
+	go func() {
+		_ = front.Serve(lis)
+	}()
+	dial := func(opts ...grpc.DialOption) healthpb.HealthClient {
+		opts = append(opts, grpc.WithInsecure(), grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) {
+			return lis.Dial()
+		}))
+		conn, err := grpc.DialContext(ctx, "passthrough:///router", opts...)
+		assert.NoError(t, err)
+		return healthpb.NewHealthClient(conn)
+	}
+
+	// Requests not naming any tenant are meant for the default one
+	client := dial()
+	response, err := client.Check(ctx, &healthpb.HealthCheckRequest{Service: Default})
+	assert.NoError(t, err)
+	assert.Equal(t, healthpb.HealthCheckResponse_SERVING, response.Status)
+	_, err = client.Check(ctx, &healthpb.HealthCheckRequest{Service: "lab-b"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	client = dial(DialOptions("lab-b")...)
+	response, err = client.Check(ctx, &healthpb.HealthCheckRequest{Service: "lab-b"})
+	assert.NoError(t, err)
+	assert.Equal(t, healthpb.HealthCheckResponse_SERVING, response.Status)
+	watchCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	stream, err := client.Watch(watchCtx, &healthpb.HealthCheckRequest{Service: "lab-b"})
+	assert.NoError(t, err)
+	response, err = stream.Recv()
+	assert.NoError(t, err)
+	assert.Equal(t, healthpb.HealthCheckResponse_SERVING, response.Status)
+
+	_, err = dial(DialOptions("lab-c")...).Check(ctx, &healthpb.HealthCheckRequest{})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
@@ -0,0 +1,433 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package topo registers the simulated nodes and cells in onos-topo
+package topo
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/onosproject/onos-api/go/onos/ransim/types"
+	topoapi "github.com/onosproject/onos-api/go/onos/topo"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/onosproject/onos-lib-go/pkg/logging"
+	"github.com/onosproject/ran-simulator/pkg/model"
+	"github.com/onosproject/ran-simulator/pkg/store/cells"
+	"github.com/onosproject/ran-simulator/pkg/store/event"
+	"github.com/onosproject/ran-simulator/pkg/store/nodes"
+)
+
+var log = logging.GetLogger("topo")
+
+const (
+	// NodeKind is the kind of the entities of the simulated E2 nodes
+	NodeKind = "e2node"
+	// CellKind is the kind of the entities of the simulated cells
+	CellKind = "e2cell"
+	// ContainsKind is the kind of the relations from the nodes to their cells
+	ContainsKind = "contains"
+	// NeighborsKind is the kind of the relations from the cells to their neighbors
+	NeighborsKind = "neighbors"
+	// InstanceAttribute is the attribute identifying the simulator instance which registered the object
+	InstanceAttribute = "ransim-instance"
+)
+
+// retryInterval is the interval of retrying the registration of objects onos-topo failed to accept
+const retryInterval = 5 * time.Second
+
+// NodeID returns the ID of the entity of the node, e.g. ransim/node/5153
+func NodeID(enbID types.EnbID) topoapi.ID {
+	return topoapi.ID(fmt.Sprintf("ransim/node/%d", enbID))
+}
+
+// CellID returns the ID of the entity of the cell, e.g. ransim/cell/84325717505
+func CellID(ecgi types.ECGI) topoapi.ID {
+	return topoapi.ID(fmt.Sprintf("ransim/cell/%d", ecgi))
+}
+
+// change is a pending creation, update or deletion of an object
+type change struct {
+	object  *topoapi.Object
+	deleted bool
+}
+
+// Registrar registers the nodes and cells as entities in onos-topo, along with the relations of the nodes to their
+// cells and of the cells to their neighbors, and keeps them in sync with the node and cell stores; the objects
+// registered by the same instance before, e.g. for a previous model, which are no longer simulated are removed
+type Registrar struct {
+	client     topoapi.TopoClient
+	nodeStore  nodes.Store
+	cellStore  cells.Store
+	instance   string
+	mu         sync.Mutex
+	pending    map[topoapi.ID]*change
+	registered map[topoapi.ID]*topoapi.Object
+	relations  map[types.ECGI][]topoapi.ID
+	reconciled bool
+	wake       chan struct{}
+	cancel     context.CancelFunc
+}
+
+// NewRegistrar creates a new registrar of the objects of the given simulator instance
+func NewRegistrar(client topoapi.TopoClient, nodeStore nodes.Store, cellStore cells.Store, instance string) *Registrar {
+	return &Registrar{
+		client:     client,
+		nodeStore:  nodeStore,
+		cellStore:  cellStore,
+		instance:   instance,
+		pending:    make(map[topoapi.ID]*change),
+		registered: make(map[topoapi.ID]*topoapi.Object),
+		relations:  make(map[types.ECGI][]topoapi.ID),
+		wake:       make(chan struct{}, 1),
+	}
+}
+
+// Start starts registering the nodes and cells; onos-topo need not be available yet
+func (r *Registrar) Start() error {
+	ctx, cancel := context.WithCancel(context.Background())
+	nodeCh := make(chan event.Event)
+	if err := r.nodeStore.Watch(ctx, nodeCh); err != nil {
+		cancel()
+		return err
+	}
+	cellCh := make(chan event.Event)
+	if err := r.cellStore.Watch(ctx, cellCh); err != nil {
+		cancel()
+		return err
+	}
+
+	nodeList, err := r.nodeStore.List(ctx)
+	if err != nil {
+		cancel()
+		return err
+	}
+	for _, node := range nodeList {
+		r.setNode(node)
+	}
+	cellList, err := r.cellStore.List(ctx)
+	if err != nil {
+		cancel()
+		return err
+	}
+	for _, cell := range cellList {
+		r.setCell(cell)
+	}
+
+	r.cancel = cancel
+	go r.processNodeEvents(nodeCh)
+	go r.processCellEvents(cellCh)
+	go r.run(ctx)
+	return nil
+}
+
+// Stop stops registering the nodes and cells; the registered objects are kept
+func (r *Registrar) Stop() {
+	if r.cancel != nil {
+		r.cancel()
+	}
+}
+
+func (r *Registrar) processNodeEvents(ch <-chan event.Event) {
+	for nodeEvent := range ch {
+		node, ok := nodeEvent.Value.(*model.Node)
+		if !ok {
+			continue
+		}
+		if nodeEvent.Type == nodes.Deleted {
+			r.remove(nodeEntity(node, r.instance))
+		} else {
+			r.setNode(node)
+		}
+		r.notify()
+	}
+}
+
+func (r *Registrar) processCellEvents(ch <-chan event.Event) {
+	for cellEvent := range ch {
+		cell, ok := cellEvent.Value.(*model.Cell)
+		if !ok {
+			continue
+		}
+		if cellEvent.Type == cells.Deleted {
+			r.removeCell(cell)
+		} else {
+			r.setCell(cell)
+		}
+		r.notify()
+	}
+}
+
+func (r *Registrar) notify() {
+	select {
+	case r.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (r *Registrar) setNode(node *model.Node) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.set(nodeEntity(node, r.instance))
+}
+
+// setCell registers the cell along with its relations, removing the relations to former neighbors
+func (r *Registrar) setCell(cell *model.Cell) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.set(cellEntity(cell, r.instance))
+	relations := cellRelations(cell, r.instance)
+	ids := make([]topoapi.ID, 0, len(relations))
+	current := make(map[topoapi.ID]bool, len(relations))
+	for _, relation := range relations {
+		r.set(relation)
+		ids = append(ids, relation.ID)
+		current[relation.ID] = true
+	}
+	for _, id := range r.relations[cell.ECGI] {
+		if !current[id] {
+			r.pending[id] = &change{object: &topoapi.Object{ID: id, Type: topoapi.Object_RELATION}, deleted: true}
+		}
+	}
+	r.relations[cell.ECGI] = ids
+}
+
+func (r *Registrar) removeCell(cell *model.Cell) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, id := range r.relations[cell.ECGI] {
+		r.pending[id] = &change{object: &topoapi.Object{ID: id, Type: topoapi.Object_RELATION}, deleted: true}
+	}
+	delete(r.relations, cell.ECGI)
+	r.pending[CellID(cell.ECGI)] = &change{object: cellEntity(cell, r.instance), deleted: true}
+}
+
+func (r *Registrar) remove(object *topoapi.Object) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.pending[object.ID] = &change{object: object, deleted: true}
+}
+
+// set queues the registration of the object unless it is registered already; the lock must be held
+func (r *Registrar) set(object *topoapi.Object) {
+	if registered, ok := r.registered[object.ID]; ok && reflect.DeepEqual(registered.Attributes, object.Attributes) {
+		delete(r.pending, object.ID)
+		return
+	}
+	r.pending[object.ID] = &change{object: object}
+}
+
+func (r *Registrar) run(ctx context.Context) {
+	ticker := time.NewTicker(retryInterval)
+	defer ticker.Stop()
+	for {
+		r.sync(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-r.wake:
+		case <-ticker.C:
+		}
+	}
+}
+
+// sync applies the pending changes, entities being created before and deleted after their relations; it gives up
+// on the first failure, leaving the remaining changes to be retried
+func (r *Registrar) sync(ctx context.Context) {
+	if !r.isReconciled() {
+		if err := r.reconcile(ctx); err != nil {
+			log.Warnf("Unable to list the objects of onos-topo: %v", err)
+			return
+		}
+	}
+	for _, c := range r.pendingChanges() {
+		object, err := r.apply(ctx, c)
+		if err != nil {
+			if ctx.Err() == nil {
+				log.Warnf("Unable to register %s in onos-topo: %v", c.object.ID, err)
+			}
+			return
+		}
+		r.mu.Lock()
+		if r.pending[c.object.ID] == c {
+			delete(r.pending, c.object.ID)
+		}
+		if c.deleted {
+			delete(r.registered, c.object.ID)
+		} else {
+			r.registered[c.object.ID] = object
+		}
+		r.mu.Unlock()
+	}
+}
+
+func (r *Registrar) isReconciled() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.reconciled
+}
+
+// reconcile queues the deletion of the objects registered by this instance which are no longer simulated
+func (r *Registrar) reconcile(ctx context.Context) error {
+	response, err := r.client.List(ctx, &topoapi.ListRequest{})
+	if err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i := range response.Objects {
+		object := &response.Objects[i]
+		if object.Attributes[InstanceAttribute] != r.instance || !strings.HasPrefix(string(object.ID), "ransim/") {
+			continue
+		}
+		if c, ok := r.pending[object.ID]; ok && !c.deleted {
+			continue
+		}
+		if _, ok := r.registered[object.ID]; ok {
+			continue
+		}
+		log.Infof("Removing stale object %s from onos-topo", object.ID)
+		r.pending[object.ID] = &change{object: object, deleted: true}
+	}
+	r.reconciled = true
+	return nil
+}
+
+// pendingChanges returns the pending changes in the order they can be applied
+func (r *Registrar) pendingChanges() []*change {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	changes := make([]*change, 0, len(r.pending))
+	for _, c := range r.pending {
+		changes = append(changes, c)
+	}
+	rank := func(c *change) int {
+		relation := c.object.Type == topoapi.Object_RELATION
+		switch {
+		case !c.deleted && !relation:
+			return 0
+		case !c.deleted:
+			return 1
+		case relation:
+			return 2
+		default:
+			return 3
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		if rank(changes[i]) != rank(changes[j]) {
+			return rank(changes[i]) < rank(changes[j])
+		}
+		return changes[i].object.ID < changes[j].object.ID
+	})
+	return changes
+}
+
+// apply applies the change, updating objects which exist already and creating those which do not
+func (r *Registrar) apply(ctx context.Context, c *change) (*topoapi.Object, error) {
+	id := c.object.ID
+	if c.deleted {
+		if _, err := r.client.Delete(ctx, &topoapi.DeleteRequest{ID: id}); err != nil && !errors.IsNotFound(errors.FromGRPC(err)) {
+			return nil, err
+		}
+		return nil, nil
+	}
+
+	object := *c.object
+	r.mu.Lock()
+	registered, ok := r.registered[id]
+	r.mu.Unlock()
+	if ok {
+		object.Revision = registered.Revision
+		response, err := r.client.Update(ctx, &topoapi.UpdateRequest{Object: &object})
+		if err == nil {
+			return response.Object, nil
+		}
+		if !errors.IsNotFound(errors.FromGRPC(err)) {
+			return nil, err
+		}
+		object.Revision = 0
+	}
+
+	response, err := r.client.Create(ctx, &topoapi.CreateRequest{Object: &object})
+	if err == nil {
+		return response.Object, nil
+	}
+	if !errors.IsAlreadyExists(errors.FromGRPC(err)) {
+		return nil, err
+	}
+	existing, err := r.client.Get(ctx, &topoapi.GetRequest{ID: id})
+	if err != nil {
+		return nil, err
+	}
+	object.Revision = existing.Object.Revision
+	updated, err := r.client.Update(ctx, &topoapi.UpdateRequest{Object: &object})
+	if err != nil {
+		return nil, err
+	}
+	return updated.Object, nil
+}
+
+func nodeEntity(node *model.Node, instance string) *topoapi.Object {
+	return &topoapi.Object{
+		ID:   NodeID(node.EnbID),
+		Type: topoapi.Object_ENTITY,
+		Obj:  &topoapi.Object_Entity{Entity: &topoapi.Entity{KindID: NodeKind}},
+		Attributes: map[string]string{
+			InstanceAttribute: instance,
+			"enbid":           strconv.FormatUint(uint64(node.EnbID), 10),
+			"controllers":     strings.Join(node.Controllers, ","),
+			"service-models":  strings.Join(node.ServiceModels, ","),
+			"status":          node.Status,
+		},
+	}
+}
+
+func cellEntity(cell *model.Cell, instance string) *topoapi.Object {
+	return &topoapi.Object{
+		ID:   CellID(cell.ECGI),
+		Type: topoapi.Object_ENTITY,
+		Obj:  &topoapi.Object_Entity{Entity: &topoapi.Entity{KindID: CellKind}},
+		Attributes: map[string]string{
+			InstanceAttribute: instance,
+			"ecgi":            strconv.FormatUint(uint64(cell.ECGI), 10),
+			"latitude":        strconv.FormatFloat(cell.Sector.Center.Lat, 'f', -1, 64),
+			"longitude":       strconv.FormatFloat(cell.Sector.Center.Lng, 'f', -1, 64),
+			"azimuth":         strconv.Itoa(int(cell.Sector.Azimuth)),
+			"arc":             strconv.Itoa(int(cell.Sector.Arc)),
+			"txpower":         strconv.FormatFloat(cell.TxPowerDB, 'f', -1, 64),
+			"earfcn":          strconv.FormatUint(uint64(cell.Earfcn), 10),
+			"locked":          strconv.FormatBool(cell.Locked),
+			"barred":          strconv.FormatBool(cell.Barred),
+		},
+	}
+}
+
+// cellRelations returns the relations of the node of the cell to the cell and of the cell to its neighbors
+func cellRelations(cell *model.Cell, instance string) []*topoapi.Object {
+	enbID := types.GetEnbID(uint64(cell.ECGI))
+	relations := []*topoapi.Object{
+		relation(topoapi.ID(fmt.Sprintf("ransim/contains/%d/%d", enbID, cell.ECGI)), ContainsKind, NodeID(enbID), CellID(cell.ECGI), instance),
+	}
+	for _, neighbor := range cell.Neighbors {
+		relations = append(relations,
+			relation(topoapi.ID(fmt.Sprintf("ransim/neighbors/%d/%d", cell.ECGI, neighbor)), NeighborsKind, CellID(cell.ECGI), CellID(neighbor), instance))
+	}
+	return relations
+}
+
+func relation(id topoapi.ID, kind topoapi.ID, src topoapi.ID, tgt topoapi.ID, instance string) *topoapi.Object {
+	return &topoapi.Object{
+		ID:         id,
+		Type:       topoapi.Object_RELATION,
+		Obj:        &topoapi.Object_Relation{Relation: &topoapi.Relation{KindID: kind, SrcEntityID: src, TgtEntityID: tgt}},
+		Attributes: map[string]string{InstanceAttribute: instance},
+	}
+}
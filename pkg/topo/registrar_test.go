@@ -0,0 +1,151 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package topo
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/onosproject/onos-api/go/onos/ransim/types"
+	topoapi "github.com/onosproject/onos-api/go/onos/topo"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/onosproject/ran-simulator/pkg/model"
+	"github.com/onosproject/ran-simulator/pkg/store/cells"
+	"github.com/onosproject/ran-simulator/pkg/store/nodes"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+)
+
+// testClient is an in-memory onos-topo
+type testClient struct {
+	mu      sync.Mutex
+	objects map[topoapi.ID]topoapi.Object
+}
+
+func (c *testClient) Create(ctx context.Context, in *topoapi.CreateRequest, opts ...grpc.CallOption) (*topoapi.CreateResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.objects[in.Object.ID]; ok {
+		return nil, errors.Status(errors.New(errors.AlreadyExists, "object exists")).Err()
+	}
+	object := *in.Object
+	object.Revision = 1
+	c.objects[object.ID] = object
+	return &topoapi.CreateResponse{Object: &object}, nil
+}
+
+func (c *testClient) Get(ctx context.Context, in *topoapi.GetRequest, opts ...grpc.CallOption) (*topoapi.GetResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	object, ok := c.objects[in.ID]
+	if !ok {
+		return nil, errors.Status(errors.New(errors.NotFound, "object not found")).Err()
+	}
+	return &topoapi.GetResponse{Object: &object}, nil
+}
+
+func (c *testClient) Update(ctx context.Context, in *topoapi.UpdateRequest, opts ...grpc.CallOption) (*topoapi.UpdateResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	existing, ok := c.objects[in.Object.ID]
+	if !ok {
+		return nil, errors.Status(errors.New(errors.NotFound, "object not found")).Err()
+	}
+	if existing.Revision != in.Object.Revision {
+		return nil, errors.Status(errors.New(errors.Conflict, "revision mismatch")).Err()
+	}
+	object := *in.Object
+	object.Revision++
+	c.objects[object.ID] = object
+	return &topoapi.UpdateResponse{Object: &object}, nil
+}
+
+func (c *testClient) Delete(ctx context.Context, in *topoapi.DeleteRequest, opts ...grpc.CallOption) (*topoapi.DeleteResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.objects[in.ID]; !ok {
+		return nil, errors.Status(errors.New(errors.NotFound, "object not found")).Err()
+	}
+	delete(c.objects, in.ID)
+	return &topoapi.DeleteResponse{}, nil
+}
+
+func (c *testClient) List(ctx context.Context, in *topoapi.ListRequest, opts ...grpc.CallOption) (*topoapi.ListResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	response := &topoapi.ListResponse{}
+	for _, object := range c.objects {
+		response.Objects = append(response.Objects, object)
+	}
+	return response, nil
+}
+
+func (c *testClient) Watch(ctx context.Context, in *topoapi.WatchRequest, opts ...grpc.CallOption) (topoapi.Topo_WatchClient, error) {
+	return nil, errors.New(errors.NotSupported, "watch not supported")
+}
+
+func (c *testClient) get(id topoapi.ID) (topoapi.Object, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	object, ok := c.objects[id]
+	return object, ok
+}
+
+func TestRegistrar(t *testing.T) {
+	ctx := context.Background()
+	m := &model.Model{}
+	assert.NoError(t, model.LoadConfig(m, "../model/test"))
+	nodeStore := nodes.NewNodeRegistry(m.Nodes)
+	cellStore := cells.NewCellRegistry(m.Cells, nodeStore)
+	ecgi := types.ECGI(84325717505)
+	cell, err := cellStore.Get(ctx, ecgi)
+	assert.NoError(t, err)
+
+	// A stale cell registered by this instance for a previous model is removed, objects of other instances are kept
+	stale := cellEntity(&model.Cell{ECGI: 1}, "0")
+	other := cellEntity(&model.Cell{ECGI: 2}, "1")
+	client := &testClient{objects: map[topoapi.ID]topoapi.Object{stale.ID: *stale, other.ID: *other}}
+	registrar := NewRegistrar(client, nodeStore, cellStore, "0")
+	assert.NoError(t, registrar.Start())
+	defer registrar.Stop()
+
+	registered := func(id topoapi.ID) bool {
+		_, ok := client.get(id)
+		return ok
+	}
+	assert.Eventually(t, func() bool {
+		return registered(NodeID(types.GetEnbID(uint64(ecgi)))) && registered(CellID(ecgi)) && !registered(stale.ID)
+	}, time.Second, 10*time.Millisecond)
+	assert.True(t, registered(other.ID))
+	contains, ok := client.get(topoapi.ID("ransim/contains/144470/84325717505"))
+	assert.True(t, ok)
+	assert.Equal(t, CellID(ecgi), contains.GetRelation().TgtEntityID)
+
+	// Changes of the cells are reflected by their attributes and relations
+	updated := *cell
+	updated.TxPowerDB = 5
+	updated.Neighbors = []types.ECGI{84325717506, 84325717761}
+	assert.NoError(t, cellStore.Update(ctx, &updated))
+	assert.Eventually(t, func() bool {
+		object, _ := client.get(CellID(ecgi))
+		return object.Attributes["txpower"] == "5" && object.Revision == 2 &&
+			registered("ransim/neighbors/84325717505/84325717506") && registered("ransim/neighbors/84325717505/84325717761")
+	}, time.Second, 10*time.Millisecond)
+	updated.Neighbors = updated.Neighbors[:1]
+	assert.NoError(t, cellStore.Update(ctx, &updated))
+	assert.Eventually(t, func() bool {
+		return !registered("ransim/neighbors/84325717505/84325717761")
+	}, time.Second, 10*time.Millisecond)
+	assert.True(t, registered("ransim/neighbors/84325717505/84325717506"))
+
+	// Deleted cells are removed along with their relations
+	_, err = cellStore.Delete(ctx, ecgi)
+	assert.NoError(t, err)
+	assert.Eventually(t, func() bool {
+		return !registered(CellID(ecgi)) && !registered(contains.ID)
+	}, time.Second, 10*time.Millisecond)
+}
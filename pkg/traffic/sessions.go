@@ -0,0 +1,174 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package traffic
+
+import (
+	"math/rand"
+	"sort"
+	"time"
+
+	"github.com/onosproject/ran-simulator/pkg/model"
+)
+
+// class holds the default parameters of a class of traffic, overridden by the activity profile of the UE type
+type class struct {
+	meanInterval time.Duration
+	meanDuration time.Duration
+	// downlinkRate and uplinkRate are the mean bit rates of the sessions in kbit/s
+	downlinkRate float64
+	uplinkRate   float64
+	// periodic sessions arrive at fixed intervals rather than as a Poisson process, e.g. keepalives
+	periodic bool
+}
+
+var (
+	// burstsClass generates bursts of activity without volume unless rates are configured
+	burstsClass = class{meanInterval: 60 * time.Second, meanDuration: 10 * time.Second}
+	// webClass generates frequent short page loads
+	webClass = class{meanInterval: 30 * time.Second, meanDuration: 5 * time.Second, downlinkRate: 4000, uplinkRate: 400}
+	// streamingClass generates rare long downlink-heavy sessions
+	streamingClass = class{meanInterval: 10 * time.Minute, meanDuration: 3 * time.Minute, downlinkRate: 5000, uplinkRate: 100}
+	// iotClass generates periodic keepalives of a few bits
+	iotClass = class{meanInterval: 5 * time.Minute, meanDuration: time.Second, downlinkRate: 1, uplinkRate: 2, periodic: true}
+)
+
+// maxQueuedSessions is the number of sessions that can wait for the current one to stop; later arrivals are dropped
+const maxQueuedSessions = 16
+
+// sessionFactory returns the factory of session generators of the class
+func sessionFactory(c class) Factory {
+	return func(profile model.ActivityProfile, now time.Time) Generator {
+		if profile.MeanInterval > 0 {
+			c.meanInterval = profile.MeanInterval
+		}
+		if profile.MeanDuration > 0 {
+			c.meanDuration = profile.MeanDuration
+		}
+		if profile.DownlinkRate > 0 {
+			c.downlinkRate = profile.DownlinkRate
+		}
+		if profile.UplinkRate > 0 {
+			c.uplinkRate = profile.UplinkRate
+		}
+		return newSessionGenerator(c, now)
+	}
+}
+
+// sessionGenerator generates the sessions of a UE as an M/M/1/K queue: sessions arrive as a Poisson process, or
+// periodically, and are served one at a time for an exponentially distributed duration, transferring data at rates
+// drawn per session around the mean rates of the class; at most maxQueuedSessions sessions wait to be served, so that
+// the queue stays bounded while the mean duration of the sessions exceeds their mean interval
+type sessionGenerator struct {
+	class class
+	// last is the time the generator was advanced to
+	last        time.Time
+	nextArrival time.Time
+	// queue holds the arrival times of the sessions waiting for the current one to stop, in order
+	queue  []time.Time
+	active bool
+	// end is the end of the current session
+	end time.Time
+	// downlinkRate and uplinkRate are the bit rates of the current session in bit/s
+	downlinkRate float64
+	uplinkRate   float64
+}
+
+func newSessionGenerator(c class, now time.Time) *sessionGenerator {
+	g := &sessionGenerator{
+		class: c,
+		last:  now,
+	}
+	if c.periodic {
+		// Random phase, so that the UEs do not keep alive in lockstep
+		g.nextArrival = now.Add(time.Duration(rand.Float64() * float64(c.meanInterval)))
+	} else {
+		g.nextArrival = now.Add(g.interval())
+	}
+	return g
+}
+
+// interval returns the time until the next arrival
+func (g *sessionGenerator) interval() time.Duration {
+	if g.class.periodic {
+		return g.class.meanInterval
+	}
+	return time.Duration(rand.ExpFloat64() * float64(g.class.meanInterval))
+}
+
+// enqueue queues a session arriving at the given time, dropping the latest session if the queue is full
+func (g *sessionGenerator) enqueue(arrival time.Time) {
+	i := sort.Search(len(g.queue), func(i int) bool {
+		return g.queue[i].After(arrival)
+	})
+	if i == maxQueuedSessions {
+		return
+	}
+	if len(g.queue) < maxQueuedSessions {
+		g.queue = append(g.queue, time.Time{})
+	}
+	copy(g.queue[i+1:], g.queue[i:])
+	g.queue[i] = arrival
+}
+
+// Trigger queues a session arriving at the given time
+func (g *sessionGenerator) Trigger(now time.Time) {
+	g.enqueue(now)
+}
+
+// Advance serves the sessions arrived until the given time
+func (g *sessionGenerator) Advance(now time.Time) []Event {
+	for !g.nextArrival.After(now) {
+		g.enqueue(g.nextArrival)
+		g.nextArrival = g.nextArrival.Add(g.interval())
+	}
+
+	var events []Event
+	var downlink, uplink float64
+	t := g.last
+	for {
+		if g.active {
+			end := g.end
+			if end.After(now) {
+				end = now
+			}
+			if end.After(t) {
+				downlink += g.downlinkRate * end.Sub(t).Seconds()
+				uplink += g.uplinkRate * end.Sub(t).Seconds()
+				t = end
+			}
+			if g.end.After(now) {
+				break
+			}
+			g.active = false
+			events = append(events, Event{Kind: SessionStopped, Time: g.end})
+		}
+		if len(g.queue) == 0 || g.queue[0].After(now) {
+			break
+		}
+		start := g.queue[0]
+		if start.Before(t) {
+			start = t
+		}
+		g.queue = g.queue[1:]
+		g.start(start)
+		t = start
+		events = append(events, Event{Kind: SessionStarted, Time: start})
+	}
+	if now.After(g.last) {
+		g.last = now
+	}
+	if downlink > 0 || uplink > 0 {
+		events = append(events, Event{Kind: Volume, Time: now, DownlinkBits: downlink, UplinkBits: uplink})
+	}
+	return events
+}
+
+// start starts serving a session at the given time
+func (g *sessionGenerator) start(start time.Time) {
+	g.active = true
+	g.end = start.Add(time.Duration(rand.ExpFloat64() * float64(g.class.meanDuration)))
+	g.downlinkRate = g.class.downlinkRate * 1000 * (0.5 + rand.Float64())
+	g.uplinkRate = g.class.uplinkRate * 1000 * (0.5 + rand.Float64())
+}
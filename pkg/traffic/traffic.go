@@ -0,0 +1,110 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package traffic generates the traffic of UEs as sessions of data transfer, driving their RRC activity and the
+// volume and throughput KPIs of their serving cells
+package traffic
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/onosproject/onos-lib-go/pkg/logging"
+	"github.com/onosproject/ran-simulator/pkg/model"
+)
+
+var log = logging.GetLogger("traffic")
+
+// EventKind is the kind of a traffic event
+type EventKind int
+
+const (
+	// SessionStarted a session of the UE started transferring data
+	SessionStarted EventKind = iota
+	// SessionStopped a session of the UE stopped transferring data
+	SessionStopped
+	// Volume data was transferred by the sessions of the UE
+	Volume
+)
+
+func (k EventKind) String() string {
+	return [...]string{"SessionStarted", "SessionStopped", "Volume"}[k]
+}
+
+// Event is a traffic event of a UE
+type Event struct {
+	Kind EventKind
+	// Time is the time of the event; volume events are stamped with the end of the period the data was transferred in
+	Time time.Time
+	// DownlinkBits and UplinkBits are the volumes transferred by volume events
+	DownlinkBits float64
+	UplinkBits   float64
+}
+
+// Generator generates the traffic of a UE
+type Generator interface {
+	// Advance advances the traffic of the UE to the given time and returns the events since the previous call, in the
+	// order they occurred
+	Advance(now time.Time) []Event
+	// Trigger requests a session starting at the given time, e.g. upon downlink data arriving for the UE; the session
+	// starts once the current ones have stopped
+	Trigger(now time.Time)
+}
+
+// Factory creates the generator of a UE from the activity profile of its type
+type Factory func(profile model.ActivityProfile, now time.Time) Generator
+
+// DefaultGenerator is the name of the generator of the UEs whose activity profile does not name one
+const DefaultGenerator = "bursts"
+
+var (
+	factoriesMu sync.RWMutex
+	factories   = map[string]Factory{
+		DefaultGenerator: sessionFactory(burstsClass),
+		"web":            sessionFactory(webClass),
+		"streaming":      sessionFactory(streamingClass),
+		"iot":            sessionFactory(iotClass),
+	}
+)
+
+// Register registers the factory of the named generator, replacing a built-in one
+func Register(name string, factory Factory) error {
+	if name == "" || factory == nil {
+		return errors.New(errors.Invalid, "generator name and factory are required")
+	}
+	factoriesMu.Lock()
+	defer factoriesMu.Unlock()
+	factories[name] = factory
+	log.Infof("Registered traffic generator %s", name)
+	return nil
+}
+
+// Names returns the names of the registered generators in alphabetical order
+func Names() []string {
+	factoriesMu.RLock()
+	defer factoriesMu.RUnlock()
+	names := make([]string, 0, len(factories))
+	for name := range factories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// New creates the generator named by the activity profile, or the default one if the profile does not name any
+func New(profile model.ActivityProfile, now time.Time) (Generator, error) {
+	name := profile.Generator
+	if name == "" {
+		name = DefaultGenerator
+	}
+	factoriesMu.RLock()
+	factory, ok := factories[name]
+	factoriesMu.RUnlock()
+	if !ok {
+		return nil, errors.New(errors.NotFound, "traffic generator %s not found", name)
+	}
+	return factory(profile, now), nil
+}
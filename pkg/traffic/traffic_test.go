@@ -0,0 +1,157 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package traffic
+
+import (
+	"testing"
+	"time"
+
+	"github.com/onosproject/ran-simulator/pkg/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNew(t *testing.T) {
+	now := time.Now()
+	assert.Equal(t, []string{"bursts", "iot", "streaming", "web"}, Names())
+
+	generator, err := New(model.ActivityProfile{}, now)
+	assert.NoError(t, err)
+	assert.Equal(t, burstsClass, generator.(*sessionGenerator).class)
+
+	generator, err = New(model.ActivityProfile{Generator: "web", MeanInterval: time.Minute, DownlinkRate: 100}, now)
+	assert.NoError(t, err)
+	class := generator.(*sessionGenerator).class
+	assert.Equal(t, time.Minute, class.meanInterval)
+	assert.Equal(t, webClass.meanDuration, class.meanDuration)
+	assert.Equal(t, 100.0, class.downlinkRate)
+	assert.Equal(t, webClass.uplinkRate, class.uplinkRate)
+
+	_, err = New(model.ActivityProfile{Generator: "video"}, now)
+	assert.Error(t, err)
+	assert.Error(t, Register("", sessionFactory(streamingClass)))
+	assert.NoError(t, Register("video", sessionFactory(streamingClass)))
+	defer func() {
+		factoriesMu.Lock()
+		defer factoriesMu.Unlock()
+		delete(factories, "video")
+	}()
+	_, err = New(model.ActivityProfile{Generator: "video"}, now)
+	assert.NoError(t, err)
+}
+
+// run advances the generator over the given period in steps and checks the consistency of the events, returning
+// them along with the time the sessions were active
+func run(t *testing.T, generator Generator, start time.Time, period time.Duration, step time.Duration) ([]Event, time.Duration) {
+	var events []Event
+	var active time.Duration
+	var started time.Time
+	last := start
+	for now := start.Add(step); !now.After(start.Add(period)); now = now.Add(step) {
+		for _, event := range generator.Advance(now) {
+			assert.False(t, event.Time.Before(last))
+			assert.False(t, event.Time.After(now))
+			switch event.Kind {
+			case SessionStarted:
+				assert.True(t, started.IsZero(), "sessions overlap")
+				started = event.Time
+			case SessionStopped:
+				assert.False(t, started.IsZero(), "session stopped before starting")
+				active += event.Time.Sub(started)
+				started = time.Time{}
+			}
+			if event.Kind != Volume {
+				last = event.Time
+			}
+			events = append(events, event)
+		}
+	}
+	if !started.IsZero() {
+		active += start.Add(period).Sub(started)
+	}
+	return events, active
+}
+
+func TestSessionGenerator(t *testing.T) {
+	// Sessions arriving every second on average and lasting half a second keep the queue busy half of the time
+	c := class{meanInterval: time.Second, meanDuration: 500 * time.Millisecond, downlinkRate: 1000, uplinkRate: 10}
+	start := time.Now()
+	generator := newSessionGenerator(c, start)
+	period := time.Hour
+	events, active := run(t, generator, start, period, time.Second)
+	utilization := active.Seconds() / period.Seconds()
+	assert.InDelta(t, 0.5, utilization, 0.05)
+
+	// Volumes are transferred at rates drawn around the mean rate of the class
+	var downlink, uplink float64
+	for _, event := range events {
+		if event.Kind == Volume {
+			downlink += event.DownlinkBits
+			uplink += event.UplinkBits
+		}
+	}
+	assert.InDelta(t, 1000000*active.Seconds(), downlink, 0.1*1000000*active.Seconds())
+	assert.InDelta(t, 100, downlink/uplink, 20)
+}
+
+func TestTrigger(t *testing.T) {
+	c := class{meanInterval: 1000 * time.Hour, meanDuration: time.Minute}
+	start := time.Now()
+	generator := newSessionGenerator(c, start)
+	assert.Empty(t, generator.Advance(start))
+
+	// Triggered sessions start immediately and queue behind the current session, without volume
+	generator.Trigger(start)
+	generator.Trigger(start)
+	events := generator.Advance(start)
+	assert.Equal(t, []Event{{Kind: SessionStarted, Time: start}}, events)
+	end := generator.end
+	events = generator.Advance(end)
+	assert.Equal(t, []Event{{Kind: SessionStopped, Time: end}, {Kind: SessionStarted, Time: end}}, events)
+	assert.Empty(t, generator.queue)
+}
+
+func TestQueueLimit(t *testing.T) {
+	c := class{meanInterval: time.Second, meanDuration: 1000 * time.Hour}
+	start := time.Now()
+	generator := newSessionGenerator(c, start)
+
+	// Sessions arriving while a long session is served are dropped once the queue is full, the first one being served
+	generator.Advance(start.Add(time.Hour))
+	assert.True(t, generator.active)
+	assert.Len(t, generator.queue, maxQueuedSessions-1)
+	for i := 1; i < len(generator.queue); i++ {
+		assert.False(t, generator.queue[i].Before(generator.queue[i-1]))
+	}
+	last := generator.queue[maxQueuedSessions-2]
+	generator.Trigger(start.Add(time.Hour))
+	assert.Len(t, generator.queue, maxQueuedSessions)
+	generator.Trigger(start.Add(2 * time.Hour))
+	assert.Len(t, generator.queue, maxQueuedSessions)
+	assert.Equal(t, start.Add(time.Hour), generator.queue[maxQueuedSessions-1])
+
+	// Earlier arrivals take the place of the latest one
+	generator.Trigger(start)
+	assert.Len(t, generator.queue, maxQueuedSessions)
+	assert.Equal(t, start, generator.queue[0])
+	assert.Equal(t, last, generator.queue[maxQueuedSessions-1])
+}
+
+func TestPeriodicSessions(t *testing.T) {
+	c := class{meanInterval: time.Minute, meanDuration: 10 * time.Millisecond, downlinkRate: 1, uplinkRate: 2, periodic: true}
+	start := time.Now()
+	generator := newSessionGenerator(c, start)
+	events, _ := run(t, generator, start, time.Hour, time.Second)
+
+	var starts []time.Time
+	for _, event := range events {
+		if event.Kind == SessionStarted {
+			starts = append(starts, event.Time)
+		}
+	}
+	assert.Len(t, starts, 60)
+	for i := 1; i < len(starts); i++ {
+		assert.Equal(t, time.Minute, starts[i].Sub(starts[i-1]))
+	}
+}
@@ -16,10 +16,11 @@ type Indication struct {
 	reqID             int32
 	ricInstanceID     int32
 	ranFuncID         int32
+	ricActionID       int32
 	indicationHeader  []byte
 	indicationMessage []byte
 	ricCallProcessID  []byte
-	// TODO add ric action ID and ric indication sn
+	// TODO add ric indication sn
 }
 
 // NewIndication creates a new indication
@@ -55,6 +56,13 @@ func WithRicInstanceID(ricInstanceID int32) func(*Indication) {
 	}
 }
 
+// WithRicActionID sets ric action ID
+func WithRicActionID(ricActionID int32) func(*Indication) {
+	return func(indication *Indication) {
+		indication.ricActionID = ricActionID
+	}
+}
+
 // WithIndicationHeader sets indication header
 func WithIndicationHeader(indicationHeader []byte) func(*Indication) {
 	return func(indication *Indication) {
@@ -94,7 +102,7 @@ func (indication *Indication) Build() (e2Indication *e2appducontents.Ricindicati
 				Id:          int32(v1beta2.ProtocolIeIDRicactionID),
 				Criticality: int32(e2ap_commondatatypes.Criticality_CRITICALITY_REJECT),
 				Value: &e2apies.RicactionId{
-					Value: 2,
+					Value: indication.ricActionID,
 				},
 				Presence: int32(e2ap_commondatatypes.Presence_PRESENCE_MANDATORY),
 			},
@@ -113,20 +113,7 @@ func (subscription *Subscription) BuildSubscriptionFailure() (response *e2appduc
 		Presence: int32(e2ap_commondatatypes.Presence_PRESENCE_MANDATORY),
 	}
 
-	for ricActionID, cause := range subscription.ricActionsNotAdmitted {
-		ranaItemIe := e2appducontents.RicactionNotAdmittedItemIes{
-			Id:          int32(v1beta2.ProtocolIeIDRicactionNotAdmittedItem),
-			Criticality: int32(e2ap_commondatatypes.Criticality_CRITICALITY_IGNORE),
-			Value: &e2appducontents.RicactionNotAdmittedItem{
-				RicActionId: &e2apies.RicactionId{
-					Value: int32(ricActionID),
-				},
-				Cause: cause,
-			},
-			Presence: int32(e2ap_commondatatypes.Presence_PRESENCE_MANDATORY),
-		}
-		ricActionNotAdmittedList.GetValue().Value = append(ricActionNotAdmittedList.GetValue().Value, &ranaItemIe)
-	}
+	appendActionsNotAdmitted(ricActionNotAdmittedList.GetValue(), subscription.ricActionsNotAdmitted)
 
 	resp := &e2appducontents.RicsubscriptionFailure{
 		ProtocolIes: &e2appducontents.RicsubscriptionFailureIes{
@@ -190,6 +177,64 @@ func (subscription *Subscription) BuildSubscriptionResponse() (response *e2appdu
 			E2ApProtocolIes17: &ricActionAdmit,
 		},
 	}
+	AddActionsNotAdmitted(resp, subscription.ricActionsNotAdmitted)
 
 	return resp, nil
 }
+
+// AddActionsNotAdmitted adds the specified actions to the not admitted actions of the subscription response
+func AddActionsNotAdmitted(response *e2appducontents.RicsubscriptionResponse, ricActionsNotAdmitted map[types.RicActionID]*e2apies.Cause) {
+	if len(ricActionsNotAdmitted) == 0 {
+		return
+	}
+	if response.ProtocolIes.E2ApProtocolIes18 == nil {
+		response.ProtocolIes.E2ApProtocolIes18 = &e2appducontents.RicsubscriptionResponseIes_RicsubscriptionResponseIes18{
+			Id:          int32(v1beta2.ProtocolIeIDRicactionsNotAdmitted),
+			Criticality: int32(e2ap_commondatatypes.Criticality_CRITICALITY_REJECT),
+			Value: &e2appducontents.RicactionNotAdmittedList{
+				Value: make([]*e2appducontents.RicactionNotAdmittedItemIes, 0),
+			},
+			Presence: int32(e2ap_commondatatypes.Presence_PRESENCE_OPTIONAL),
+		}
+	}
+	appendActionsNotAdmitted(response.ProtocolIes.E2ApProtocolIes18.GetValue(), ricActionsNotAdmitted)
+}
+
+// AddFailedActionsNotAdmitted adds the specified actions to the not admitted actions of the subscription failure
+func AddFailedActionsNotAdmitted(failure *e2appducontents.RicsubscriptionFailure, ricActionsNotAdmitted map[types.RicActionID]*e2apies.Cause) {
+	appendActionsNotAdmitted(failure.GetProtocolIes().GetE2ApProtocolIes18().GetValue(), ricActionsNotAdmitted)
+}
+
+// appendActionsNotAdmitted appends the specified actions with their causes to the list of not admitted actions
+func appendActionsNotAdmitted(list *e2appducontents.RicactionNotAdmittedList, ricActionsNotAdmitted map[types.RicActionID]*e2apies.Cause) {
+	if list == nil {
+		return
+	}
+	for ricActionID, cause := range ricActionsNotAdmitted {
+		ranaItemIe := &e2appducontents.RicactionNotAdmittedItemIes{
+			Id:          int32(v1beta2.ProtocolIeIDRicactionNotAdmittedItem),
+			Criticality: int32(e2ap_commondatatypes.Criticality_CRITICALITY_IGNORE),
+			Value: &e2appducontents.RicactionNotAdmittedItem{
+				RicActionId: &e2apies.RicactionId{
+					Value: int32(ricActionID),
+				},
+				Cause: cause,
+			},
+			Presence: int32(e2ap_commondatatypes.Presence_PRESENCE_MANDATORY),
+		}
+		list.Value = append(list.Value, ranaItemIe)
+	}
+}
+
+// NewSubscriptionFailure builds e2ap subscription failure rejecting all the actions of the given request with the specified cause
+func NewSubscriptionFailure(request *e2appducontents.RicsubscriptionRequest, cause *e2apies.Cause) (*e2appducontents.RicsubscriptionFailure, error) {
+	ricActionsNotAdmitted := make(map[types.RicActionID]*e2apies.Cause)
+	for _, action := range GetRicActionToBeSetupList(request) {
+		ricActionsNotAdmitted[types.RicActionID(action.Value.RicActionId.Value)] = cause
+	}
+	return NewSubscription(
+		WithRequestID(GetRequesterID(request)),
+		WithRanFuncID(GetRanFunctionID(request)),
+		WithRicInstanceID(GetRicInstanceID(request)),
+		WithActionsNotAdmitted(ricActionsNotAdmitted)).BuildSubscriptionFailure()
+}
@@ -9,6 +9,9 @@ import (
 	"github.com/onosproject/onos-lib-go/pkg/errors"
 )
 
+// NoIncompleteFlag leaves out the optional incomplete flag of a measurement data item, i.e. its measurements are complete
+const NoIncompleteFlag e2smkpmv2.IncompleteFlag = -1
+
 // MeasurementDataItem measurement data item
 type MeasurementDataItem struct {
 	mr             *e2smkpmv2.MeasurementRecord
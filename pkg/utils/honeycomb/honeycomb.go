@@ -27,8 +27,8 @@ func GenerateHoneycombTopology(mapCenter model.Coordinate, numTowers uint, secto
 		MapLayout:     model.MapLayout{Center: mapCenter, LocationsScale: 1.25},
 		Cells:         make(map[string]model.Cell),
 		Nodes:         make(map[string]model.Node),
-		Controllers:   generateControllers(controllerAddresses),
-		ServiceModels: generateServiceModels(serviceModels),
+		Controllers:   GenerateControllers(controllerAddresses),
+		ServiceModels: GenerateServiceModels(serviceModels),
 	}
 
 	aspectRatio := utils.AspectRatio(mapCenter.Lat)
@@ -114,7 +114,8 @@ func GenerateHoneycombTopology(mapCenter model.Coordinate, numTowers uint, secto
 	return m, nil
 }
 
-func generateControllers(addresses []string) map[string]model.Controller {
+// GenerateControllers generates the E2T controllers with the given addresses, named e2t-1, e2t-2, etc.
+func GenerateControllers(addresses []string) map[string]model.Controller {
 	controllers := make(map[string]model.Controller)
 	for i, address := range addresses {
 		name := fmt.Sprintf("e2t-%d", i+1)
@@ -123,7 +124,8 @@ func generateControllers(addresses []string) map[string]model.Controller {
 	return controllers
 }
 
-func generateServiceModels(namesAndIDs []string) map[string]model.ServiceModel {
+// GenerateServiceModels generates the service models with the given names and optional IDs, e.g. kpm/1
+func GenerateServiceModels(namesAndIDs []string) map[string]model.ServiceModel {
 	models := make(map[string]model.ServiceModel)
 	for i, nameAndID := range namesAndIDs {
 		fields := strings.Split(nameAndID, "/")
@@ -0,0 +1,231 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package ns3 converts ns-3/5G-LENA scenario descriptions into models of the RAN simulator
+package ns3
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/onosproject/onos-api/go/onos/ransim/types"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/onosproject/ran-simulator/pkg/model"
+	"github.com/onosproject/ran-simulator/pkg/radio"
+	"github.com/onosproject/ran-simulator/pkg/utils/honeycomb"
+	"gopkg.in/yaml.v2"
+)
+
+// mmWaveFrequency is the carrier frequency in Hz from which bands are considered FR2 millimeter wave bands
+const mmWaveFrequency = 24e9
+
+// Scenario is an ns-3/5G-LENA scenario description, mirroring the parameters of the CcBwpCreator operation bands
+// and the positions and antennas of the gNBs of the scenario
+type Scenario struct {
+	// Bands are the operation bands of the scenario
+	Bands []Band `yaml:"bands"`
+	// Gnbs are the gNBs of the scenario
+	Gnbs []Gnb `yaml:"gnbs"`
+	// UeCount is the number of UEs of the scenario
+	UeCount uint `yaml:"ueCount"`
+}
+
+// Band is an operation band
+type Band struct {
+	ID uint8 `yaml:"id"`
+	// CentralFrequency is the central frequency of the band in Hz
+	CentralFrequency float64 `yaml:"centralFrequency"`
+	// Bandwidth is the bandwidth of the band in Hz
+	Bandwidth float64 `yaml:"bandwidth"`
+	// Scenario is the 3GPP TR 38.901 channel condition scenario of the band, e.g. UMa, UMi-StreetCanyon, RMa or
+	// InH-OfficeMixed
+	Scenario string `yaml:"scenario"`
+}
+
+// Gnb is a gNB operating a cell on each of its bands for each of its sectors
+type Gnb struct {
+	// Position is the position of the gNB in meters; x points east, y north and z is the antenna height
+	Position Vector `yaml:"position"`
+	// TxPower is the transmit power in dBm
+	TxPower float64 `yaml:"txPower"`
+	// Bands are the IDs of the bands the gNB operates on; all bands of the scenario if not specified
+	Bands []uint8 `yaml:"bands"`
+	// Sectors are the antenna sectors of the gNB; the gNB is omni-directional if not specified
+	Sectors []Sector `yaml:"sectors"`
+}
+
+// Vector is a position in meters
+type Vector struct {
+	X float64 `yaml:"x"`
+	Y float64 `yaml:"y"`
+	Z float64 `yaml:"z"`
+}
+
+// Sector is an antenna sector
+type Sector struct {
+	// Bearing is the bearing of the antenna boresight in radians, counterclockwise from the x axis as for the
+	// BearingAngle of ns-3 antenna models
+	Bearing float64 `yaml:"bearing"`
+	// Downtilt is the downtilt of the antenna in radians
+	Downtilt float64 `yaml:"downtilt"`
+}
+
+// Options are the parameters of the conversion not part of the ns-3 scenario
+type Options struct {
+	// Origin is the geographical location of the origin of the ns-3 coordinates
+	Origin              model.Coordinate
+	PlmnID              types.PlmnID
+	EnbIDStart          uint32
+	MaxNeighborDistance float64
+	// MaxNeighbors is the maximum number of neighbors of a cell; -1 for no limit
+	MaxNeighbors        int
+	ControllerAddresses []string
+	ServiceModels       []string
+}
+
+// Parse parses the YAML or JSON scenario description
+func Parse(data []byte) (*Scenario, error) {
+	scenario := &Scenario{}
+	if err := yaml.Unmarshal(data, scenario); err != nil {
+		return nil, errors.New(errors.Invalid, "invalid ns-3 scenario: %v", err)
+	}
+	return scenario, nil
+}
+
+// Convert converts the scenario into a model with a node for each gNB and a cell for each of its sectors and bands;
+// co-sited cells and cells within the maximum neighbor distance of each other are neighbors
+func Convert(scenario *Scenario, options Options) (*model.Model, error) {
+	bands := make(map[uint8]Band)
+	for _, band := range scenario.Bands {
+		if band.CentralFrequency <= 0 {
+			return nil, errors.New(errors.Invalid, "band %d has no central frequency", band.ID)
+		}
+		bands[band.ID] = band
+	}
+
+	m := &model.Model{
+		PlmnID:        options.PlmnID,
+		MapLayout:     model.MapLayout{Center: options.Origin, LocationsScale: 1.25},
+		Cells:         make(map[string]model.Cell),
+		Nodes:         make(map[string]model.Node),
+		Controllers:   honeycomb.GenerateControllers(options.ControllerAddresses),
+		ServiceModels: honeycomb.GenerateServiceModels(options.ServiceModels),
+		UECount:       scenario.UeCount,
+	}
+	controllers := make([]string, 0, len(m.Controllers))
+	for name := range m.Controllers {
+		controllers = append(controllers, name)
+	}
+	sort.Strings(controllers)
+	models := make([]string, 0, len(m.ServiceModels))
+	for name := range m.ServiceModels {
+		models = append(models, name)
+	}
+	sort.Strings(models)
+
+	var cellNames []string
+	for i, gnb := range scenario.Gnbs {
+		enbID := types.EnbID(options.EnbIDStart + uint32(i+1))
+		node := model.Node{
+			EnbID:         enbID,
+			Controllers:   controllers,
+			ServiceModels: models,
+			Status:        "stopped",
+		}
+		center := radio.Offset(options.Origin, 0, gnb.Position.Y)
+		center = radio.Offset(center, 90, gnb.Position.X)
+		center.Alt = 0
+
+		gnbBands := gnb.Bands
+		if len(gnbBands) == 0 {
+			for _, band := range scenario.Bands {
+				gnbBands = append(gnbBands, band.ID)
+			}
+		}
+		sectors := gnb.Sectors
+		omni := len(sectors) == 0
+		if omni {
+			sectors = []Sector{{}}
+		}
+
+		for s, sector := range sectors {
+			for b, id := range gnbBands {
+				band, ok := bands[id]
+				if !ok {
+					return nil, errors.New(errors.Invalid, "gNB %d operates on unknown band %d", i, id)
+				}
+				cellID := types.CellID(s*len(gnbBands) + b + 1)
+				cell := model.Cell{
+					ECGI:        types.ToECGI(options.PlmnID, types.ToECI(enbID, cellID)),
+					Sector:      toSector(center, gnb.Position.Z, sector, len(sectors), omni),
+					Color:       "green",
+					MaxUEs:      99999,
+					Neighbors:   make([]types.ECGI, 0),
+					TxPowerDB:   gnb.TxPower,
+					Bandwidth:   uint32(math.Round(band.Bandwidth / 1e6)),
+					Environment: environment(band),
+					Frequency:   band.CentralFrequency / 1e6,
+				}
+				name := fmt.Sprintf("cell%d", len(cellNames)+1)
+				cellNames = append(cellNames, name)
+				m.Cells[name] = cell
+				node.Cells = append(node.Cells, cell.ECGI)
+			}
+		}
+		m.Nodes[fmt.Sprintf("node%d", i+1)] = node
+	}
+
+	for _, name := range cellNames {
+		cell := m.Cells[name]
+		for _, otherName := range cellNames {
+			other := m.Cells[otherName]
+			if cell.ECGI == other.ECGI || (options.MaxNeighbors >= 0 && len(cell.Neighbors) >= options.MaxNeighbors) {
+				continue
+			}
+			if radio.Distance(cell.Sector.Center, other.Sector.Center) <= options.MaxNeighborDistance {
+				cell.Neighbors = append(cell.Neighbors, other.ECGI)
+			}
+		}
+		m.Cells[name] = cell
+	}
+	return m, nil
+}
+
+// toSector returns the sector of the cell, converting the ns-3 bearing of the boresight to the compass azimuth of the
+// start of the arc
+func toSector(center model.Coordinate, height float64, sector Sector, sectors int, omni bool) model.Sector {
+	if omni {
+		return model.Sector{Center: center, Azimuth: 0, Arc: 360, Height: height}
+	}
+	arc := 360 / sectors
+	boresight := 90 - sector.Bearing*180/math.Pi
+	azimuth := int32(math.Round(boresight-float64(arc)/2)) % 360
+	if azimuth < 0 {
+		azimuth += 360
+	}
+	return model.Sector{
+		Center:         center,
+		Azimuth:        azimuth,
+		Arc:            int32(arc),
+		Height:         height,
+		MechanicalTilt: sector.Downtilt * 180 / math.Pi,
+	}
+}
+
+// environment returns the propagation environment matching the channel condition scenario and frequency of the band
+func environment(band Band) model.Environment {
+	if band.CentralFrequency >= mmWaveFrequency {
+		return model.MmWave
+	}
+	switch {
+	case strings.HasPrefix(band.Scenario, "RMa"):
+		return model.Rural
+	case strings.HasPrefix(band.Scenario, "InH"):
+		return model.Indoor
+	default:
+		return model.Urban
+	}
+}
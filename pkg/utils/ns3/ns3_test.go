@@ -0,0 +1,83 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ns3
+
+import (
+	"testing"
+
+	"github.com/onosproject/onos-api/go/onos/ransim/types"
+	"github.com/onosproject/ran-simulator/pkg/model"
+	"github.com/onosproject/ran-simulator/pkg/radio"
+	"github.com/stretchr/testify/assert"
+)
+
+const scenario = `
+bands:
+  - id: 0
+    centralFrequency: 3.5e9
+    bandwidth: 20e6
+    scenario: UMa
+  - id: 1
+    centralFrequency: 28e9
+    bandwidth: 100e6
+    scenario: UMi-StreetCanyon
+gnbs:
+  - position: {x: 0, y: 0, z: 25}
+    txPower: 43
+    bands: [0]
+    sectors:
+      - bearing: 1.5707963
+        downtilt: 0.1
+      - bearing: -0.5235988
+      - bearing: 3.6651914
+  - position: {x: 500, y: 0, z: 10}
+    txPower: 30
+ueCount: 20
+`
+
+func TestConvert(t *testing.T) {
+	s, err := Parse([]byte(scenario))
+	assert.NoError(t, err)
+	origin := model.Coordinate{Lat: 52.52, Lng: 13.405}
+	plmnID := types.PlmnIDFromString("315010")
+	m, err := Convert(s, Options{Origin: origin, PlmnID: plmnID, EnbIDStart: 5152, MaxNeighborDistance: 100,
+		MaxNeighbors: -1, ControllerAddresses: []string{"onos-e2t"}, ServiceModels: []string{"kpm/1", "rc/3"}})
+	assert.NoError(t, err)
+	assert.Equal(t, uint(20), m.UECount)
+	assert.Len(t, m.Nodes, 2)
+	assert.Len(t, m.Controllers, 1)
+	assert.Len(t, m.ServiceModels, 2)
+
+	// The first gNB has a cell per sector on its single band, the boresight of the first sector pointing north
+	assert.Len(t, m.Nodes["node1"].Cells, 3)
+	cell := m.Cells["cell1"]
+	assert.Equal(t, types.ToECGI(plmnID, types.ToECI(5153, 1)), cell.ECGI)
+	assert.Equal(t, int32(300), cell.Sector.Azimuth)
+	assert.Equal(t, int32(120), cell.Sector.Arc)
+	assert.Equal(t, 25.0, cell.Sector.Height)
+	assert.InDelta(t, 5.73, cell.Sector.MechanicalTilt, 0.01)
+	assert.Equal(t, 43.0, cell.TxPowerDB)
+	assert.Equal(t, 3500.0, cell.Frequency)
+	assert.Equal(t, uint32(20), cell.Bandwidth)
+	assert.Equal(t, model.Urban, cell.Environment)
+	assert.Equal(t, int32(60), m.Cells["cell2"].Sector.Azimuth)
+	assert.Equal(t, int32(180), m.Cells["cell3"].Sector.Azimuth)
+	assert.Len(t, cell.Neighbors, 2)
+
+	// The second gNB is omni-directional and operates on all bands, the 28 GHz one as mmWave cell
+	assert.Len(t, m.Nodes["node2"].Cells, 2)
+	cell = m.Cells["cell5"]
+	assert.Equal(t, int32(360), cell.Sector.Arc)
+	assert.Equal(t, model.MmWave, cell.Environment)
+	assert.InDelta(t, 500, radio.Distance(origin, cell.Sector.Center), 1)
+	assert.Equal(t, []types.ECGI{m.Cells["cell4"].ECGI}, cell.Neighbors)
+	other := m.Cells["cell4"]
+	assert.False(t, cell.IsIntraFrequency(&other))
+
+	// Gnbs may only operate on bands of the scenario
+	s.Gnbs[0].Bands = []uint8{2}
+	_, err = Convert(s, Options{PlmnID: plmnID})
+	assert.Error(t, err)
+}
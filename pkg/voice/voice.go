@@ -0,0 +1,292 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package voice implements a model of the voice calls of the UEs, i.e. VoNR or VoLTE calls, whose setup and drops
+// depend on the SINR of their serving cell, generating call KPIs per cell for voice quality oriented RIC applications
+package voice
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/onosproject/onos-api/go/onos/ransim/types"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/onosproject/onos-lib-go/pkg/logging"
+	"github.com/onosproject/ran-simulator/pkg/model"
+	"github.com/onosproject/ran-simulator/pkg/radio"
+	"github.com/onosproject/ran-simulator/pkg/store/metrics"
+	"github.com/onosproject/ran-simulator/pkg/store/ues"
+)
+
+var log = logging.GetLogger("voice")
+
+// Per-cell call metrics maintained in the metrics store and reported via KPM; calls are counted by the serving cell
+// of the UE at the time of their setup, drop or release
+const (
+	// CallSetupAttempts number of call setups attempted
+	CallSetupAttempts = "VOICE.CallSetupAtt"
+	// CallSetupSuccesses number of calls set up
+	CallSetupSuccesses = "VOICE.CallSetupSucc"
+	// CallDrops number of calls dropped
+	CallDrops = "VOICE.CallDrop"
+	// CallReleases number of calls released normally at their end
+	CallReleases = "VOICE.CallRel"
+	// CallSetupSuccessRate percentage of the call setups attempted which succeeded
+	CallSetupSuccessRate = "VOICE.CallSetupSR"
+	// CallDropRate percentage of the ended calls which were dropped
+	CallDropRate = "VOICE.CallDropRate"
+)
+
+const (
+	defaultMeanCallInterval      = 30 * time.Minute
+	defaultMeanCallDuration      = 2 * time.Minute
+	defaultMobileTerminatedRatio = 0.5
+	defaultMinSINR               = -6.0
+
+	// sinrScale is the decrease of the SINR in dB multiplying the SINR dependent drop rate of the calls by e
+	sinrScale = 1.0
+
+	voiceUpdateInterval = time.Second
+)
+
+// Connector brings UEs into the connected state for their calls, e.g. the RRC controller
+type Connector interface {
+	Connect(ctx context.Context, imsi types.IMSI, cause string, until time.Time) error
+}
+
+// RRC establishment causes of the calls
+const (
+	causeMoVoiceCall = "mo-VoiceCall"
+	causeMtAccess    = "mt-Access"
+)
+
+// callState is the state of the calls of a UE
+type callState struct {
+	active bool
+	// next is the arrival time of the next call while no call is active and the end of the active call otherwise
+	next time.Time
+}
+
+// Controller generates the calls of the UEs, sets them up provided the UE can be connected and its SINR is above the
+// minimum, and drops them at a rate rising steeply as the SINR approaches the minimum
+type Controller struct {
+	ueStore     ues.Store
+	metricStore metrics.Store
+	config      model.VoiceConfig
+	ueTypes     map[model.UEType]bool
+	connector   Connector
+	mu          sync.Mutex
+	calls       map[types.IMSI]*callState
+	lastStep    time.Time
+	cancel      context.CancelFunc
+}
+
+// NewController creates a new voice call controller; unset times, ratio and minimum SINR are replaced by defaults
+func NewController(ueStore ues.Store, metricStore metrics.Store, config model.VoiceConfig) *Controller {
+	if config.MeanCallInterval <= 0 {
+		config.MeanCallInterval = defaultMeanCallInterval
+	}
+	if config.MeanCallDuration <= 0 {
+		config.MeanCallDuration = defaultMeanCallDuration
+	}
+	if config.MobileTerminatedRatio <= 0 {
+		config.MobileTerminatedRatio = defaultMobileTerminatedRatio
+	}
+	if config.MinSINR == 0 {
+		config.MinSINR = defaultMinSINR
+	}
+	ueTypes := make(map[model.UEType]bool, len(config.UETypes))
+	for _, ueType := range config.UETypes {
+		ueTypes[ueType] = true
+	}
+	return &Controller{
+		ueStore:     ueStore,
+		metricStore: metricStore,
+		config:      config,
+		ueTypes:     ueTypes,
+		calls:       make(map[types.IMSI]*callState),
+	}
+}
+
+// SetConnector sets the connector bringing the UEs into the connected state for their calls; calls are only set up
+// for connected UEs without connector
+func (c *Controller) SetConnector(connector Connector) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.connector = connector
+}
+
+// Counters lists the names of the metrics maintained by the controller
+func Counters() []string {
+	return []string{CallSetupAttempts, CallSetupSuccesses, CallDrops, CallReleases, CallSetupSuccessRate, CallDropRate}
+}
+
+// Start starts generating the calls of the UEs
+func (c *Controller) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	c.cancel = cancel
+	go c.run(ctx)
+}
+
+// Stop stops generating the calls of the UEs
+func (c *Controller) Stop() {
+	if c.cancel != nil {
+		c.cancel()
+	}
+}
+
+func (c *Controller) run(ctx context.Context) {
+	ticker := time.NewTicker(voiceUpdateInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case now := <-ticker.C:
+			c.step(ctx, now)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// step advances the calls of all UEs to the given time
+func (c *Controller) step(ctx context.Context, now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	elapsed := now.Sub(c.lastStep)
+	if c.lastStep.IsZero() || elapsed < 0 {
+		elapsed = 0
+	}
+	c.lastStep = now
+	present := make(map[types.IMSI]bool)
+	for _, ue := range c.ueStore.ListAllUEs(ctx) {
+		if len(c.ueTypes) > 0 && !c.ueTypes[ue.Type] {
+			continue
+		}
+		present[ue.IMSI] = true
+		call, ok := c.calls[ue.IMSI]
+		if !ok {
+			call = &callState{next: now.Add(exponential(c.config.MeanCallInterval))}
+			c.calls[ue.IMSI] = call
+		}
+		switch {
+		case !call.active && !now.Before(call.next):
+			c.setup(ctx, ue, call, now)
+		case call.active && c.dropped(ue, elapsed):
+			c.end(ctx, ue, call, CallDrops, now)
+		case call.active && !now.Before(call.next):
+			c.end(ctx, ue, call, CallReleases, now)
+		}
+	}
+	for imsi := range c.calls {
+		if !present[imsi] {
+			delete(c.calls, imsi)
+		}
+	}
+}
+
+// setup sets up a call of the UE, which fails if the UE cannot be connected, if its SINR is below the minimum or with
+// the configured setup failure probability; UEs out of coverage do not attempt calls
+func (c *Controller) setup(ctx context.Context, ue *model.UE, call *callState, now time.Time) {
+	call.next = now.Add(exponential(c.config.MeanCallInterval))
+	if ue.Cell == nil {
+		return
+	}
+	ecgi := ue.Cell.ECGI
+	c.increment(ctx, ecgi, CallSetupAttempts)
+	end := now.Add(exponential(c.config.MeanCallDuration))
+	if err := c.connect(ctx, ue, end); err != nil {
+		log.Debugf("Call setup of UE %d failed: %v", ue.IMSI, err)
+		c.updateRates(ctx, ecgi)
+		return
+	}
+	if sinr := radio.ServingSINR(ue); sinr < c.config.MinSINR {
+		log.Debugf("Call setup of UE %d failed at SINR %.1f dB", ue.IMSI, sinr)
+		c.updateRates(ctx, ecgi)
+		return
+	}
+	if c.config.SetupFailureProbability > 0 && rand.Float64() < c.config.SetupFailureProbability {
+		log.Debugf("Call setup of UE %d failed", ue.IMSI)
+		c.updateRates(ctx, ecgi)
+		return
+	}
+	log.Debugf("Call of UE %d set up via cell %d", ue.IMSI, ecgi)
+	c.increment(ctx, ecgi, CallSetupSuccesses)
+	c.updateRates(ctx, ecgi)
+	call.active = true
+	call.next = end
+}
+
+// connect brings the UE into the connected state until the end of its call, for a call made or received by the UE
+func (c *Controller) connect(ctx context.Context, ue *model.UE, end time.Time) error {
+	if c.connector == nil {
+		if ue.RrcState != model.RrcConnected {
+			return errors.New(errors.Unavailable, "UE %d is not connected", ue.IMSI)
+		}
+		return nil
+	}
+	cause := causeMoVoiceCall
+	if rand.Float64() < c.config.MobileTerminatedRatio {
+		cause = causeMtAccess
+	}
+	return c.connector.Connect(ctx, ue.IMSI, cause, end)
+}
+
+// dropped returns true if the active call of the UE dropped during the given time; calls drop as the UE leaves the
+// connected state, e.g. upon a radio link failure, and otherwise at a rate of the configured drop probability per
+// minute plus a rate rising as a logistic function of the SINR falling towards the minimum, reaching half a drop per
+// second at the minimum
+func (c *Controller) dropped(ue *model.UE, elapsed time.Duration) bool {
+	if ue.Cell == nil || ue.RrcState != model.RrcConnected {
+		return true
+	}
+	rate := c.config.DropProbability/60 + 1/(1+math.Exp((radio.ServingSINR(ue)-c.config.MinSINR)/sinrScale))
+	return rand.Float64() < 1-math.Exp(-rate*elapsed.Seconds())
+}
+
+// end ends the active call of the UE, counting it under the given counter, i.e. as dropped or released
+func (c *Controller) end(ctx context.Context, ue *model.UE, call *callState, counter string, now time.Time) {
+	log.Debugf("Call of UE %d ended: %s", ue.IMSI, counter)
+	call.active = false
+	call.next = now.Add(exponential(c.config.MeanCallInterval))
+	if ue.Cell == nil {
+		return
+	}
+	c.increment(ctx, ue.Cell.ECGI, counter)
+	c.updateRates(ctx, ue.Cell.ECGI)
+}
+
+// updateRates updates the call setup success rate and drop rate of the cell from its counters
+func (c *Controller) updateRates(ctx context.Context, ecgi types.ECGI) {
+	attempts := c.counter(ctx, ecgi, CallSetupAttempts)
+	if attempts > 0 {
+		c.setMetric(ctx, ecgi, CallSetupSuccessRate, 100*float64(c.counter(ctx, ecgi, CallSetupSuccesses))/float64(attempts))
+	}
+	drops := c.counter(ctx, ecgi, CallDrops)
+	if ended := drops + c.counter(ctx, ecgi, CallReleases); ended > 0 {
+		c.setMetric(ctx, ecgi, CallDropRate, 100*float64(drops)/float64(ended))
+	}
+}
+
+func exponential(mean time.Duration) time.Duration {
+	return time.Duration(rand.ExpFloat64() * float64(mean))
+}
+
+func (c *Controller) counter(ctx context.Context, ecgi types.ECGI, name string) uint64 {
+	value, _ := c.metricStore.Get(ctx, uint64(ecgi), name)
+	count, _ := value.(uint64)
+	return count
+}
+
+func (c *Controller) increment(ctx context.Context, ecgi types.ECGI, name string) {
+	_, _ = c.metricStore.Add(ctx, uint64(ecgi), name, 1)
+}
+
+func (c *Controller) setMetric(ctx context.Context, ecgi types.ECGI, name string, value float64) {
+	if err := c.metricStore.Set(ctx, uint64(ecgi), name, value); err != nil {
+		log.Warn(err)
+	}
+}
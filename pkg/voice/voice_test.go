@@ -0,0 +1,154 @@
+// SPDX-FileCopyrightText: 2021-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package voice
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/onosproject/onos-api/go/onos/ransim/types"
+	"github.com/onosproject/ran-simulator/pkg/model"
+	"github.com/onosproject/ran-simulator/pkg/store/cells"
+	"github.com/onosproject/ran-simulator/pkg/store/metrics"
+	"github.com/onosproject/ran-simulator/pkg/store/nodes"
+	"github.com/onosproject/ran-simulator/pkg/store/ues"
+	"github.com/stretchr/testify/assert"
+)
+
+type testConnector struct {
+	ueStore ues.Store
+	causes  []string
+}
+
+func (c *testConnector) Connect(ctx context.Context, imsi types.IMSI, cause string, until time.Time) error {
+	c.causes = append(c.causes, cause)
+	return c.ueStore.UpdateRrcState(ctx, imsi, model.RrcConnected)
+}
+
+func TestCalls(t *testing.T) {
+	ctx := context.Background()
+	m := model.Model{}
+	assert.NoError(t, model.LoadConfig(&m, "../model/test"))
+	cellStore := cells.NewCellRegistry(m.Cells, nodes.NewNodeRegistry(m.Nodes))
+	ueStore := ues.NewUERegistry(1, cellStore)
+	metricStore := metrics.NewMetricsStore()
+	controller := NewController(ueStore, metricStore, model.VoiceConfig{})
+	assert.Equal(t, defaultMinSINR, controller.config.MinSINR)
+
+	ecgi := types.ECGI(84325717505)
+	ue := ueStore.ListAllUEs(ctx)[0]
+	assert.NoError(t, ueStore.MoveToCell(ctx, ue.IMSI, ecgi, -60))
+	assert.NoError(t, ueStore.UpdateRrcState(ctx, ue.IMSI, model.RrcIdle))
+	count := func(name string) uint64 {
+		value, _ := metricStore.Get(ctx, uint64(ecgi), name)
+		c, _ := value.(uint64)
+		return c
+	}
+	rate := func(name string) float64 {
+		value, _ := metricStore.Get(ctx, uint64(ecgi), name)
+		r, _ := value.(float64)
+		return r
+	}
+
+	// Without connector, calls of idle UEs fail to set up
+	now := time.Now()
+	controller.step(ctx, now)
+	call := controller.calls[ue.IMSI]
+	assert.NotNil(t, call)
+	call.next = now
+	controller.step(ctx, now)
+	assert.False(t, call.active)
+	assert.Equal(t, uint64(1), count(CallSetupAttempts))
+	assert.Equal(t, 0.0, rate(CallSetupSuccessRate))
+
+	// The connector brings the UE into the connected state for its call
+	connector := &testConnector{ueStore: ueStore}
+	controller.SetConnector(connector)
+	call.next = now
+	controller.step(ctx, now)
+	assert.True(t, call.active)
+	assert.Len(t, connector.causes, 1)
+	assert.Equal(t, uint64(1), count(CallSetupSuccesses))
+	assert.Equal(t, 50.0, rate(CallSetupSuccessRate))
+
+	// The call is released at its end in good radio conditions
+	controller.step(ctx, call.next)
+	assert.False(t, call.active)
+	assert.Equal(t, uint64(1), count(CallReleases))
+	assert.Equal(t, 0.0, rate(CallDropRate))
+
+	// Calls fail to set up below the minimum SINR
+	interferer := []*model.UECell{{ECGI: 84325717506, Strength: -50}}
+	assert.NoError(t, ueStore.UpdateMeasurements(ctx, ue.IMSI, ue.Cell.ECGI, -60, interferer, false, nil))
+	call.next = now
+	controller.step(ctx, now)
+	assert.False(t, call.active)
+	assert.Equal(t, uint64(3), count(CallSetupAttempts))
+	assert.Equal(t, uint64(1), count(CallSetupSuccesses))
+
+	// Calls drop as the SINR falls below the minimum
+	assert.NoError(t, ueStore.UpdateMeasurements(ctx, ue.IMSI, ue.Cell.ECGI, -60, nil, false, nil))
+	call.next = now
+	controller.step(ctx, now)
+	assert.True(t, call.active)
+	assert.NoError(t, ueStore.UpdateMeasurements(ctx, ue.IMSI, ue.Cell.ECGI, -60, interferer, false, nil))
+	controller.step(ctx, now.Add(10*time.Second))
+	assert.False(t, call.active)
+	assert.Equal(t, uint64(1), count(CallDrops))
+	assert.Equal(t, 50.0, rate(CallDropRate))
+
+	// Calls drop as the UE leaves the connected state
+	assert.NoError(t, ueStore.UpdateMeasurements(ctx, ue.IMSI, ue.Cell.ECGI, -60, nil, false, nil))
+	call.next = now.Add(10 * time.Second)
+	controller.step(ctx, now.Add(10*time.Second))
+	assert.True(t, call.active)
+	assert.NoError(t, ueStore.UpdateRrcState(ctx, ue.IMSI, model.RrcIdle))
+	controller.step(ctx, now.Add(11*time.Second))
+	assert.False(t, call.active)
+	assert.Equal(t, uint64(2), count(CallDrops))
+	assert.InDelta(t, 66.67, rate(CallDropRate), 0.01)
+
+	// Deleted UEs are no longer tracked
+	_, err := ueStore.Delete(ctx, ue.IMSI)
+	assert.NoError(t, err)
+	controller.step(ctx, now)
+	assert.Empty(t, controller.calls)
+}
+
+func TestCallingUETypes(t *testing.T) {
+	ctx := context.Background()
+	m := model.Model{}
+	assert.NoError(t, model.LoadConfig(&m, "../model/test"))
+	cellStore := cells.NewCellRegistry(m.Cells, nodes.NewNodeRegistry(m.Nodes))
+	ueStore := ues.NewUERegistry(2, cellStore)
+	controller := NewController(ueStore, metrics.NewMetricsStore(), model.VoiceConfig{UETypes: []model.UEType{model.UAV}})
+	controller.step(ctx, time.Now())
+	assert.Empty(t, controller.calls)
+}
+
+func TestDropRate(t *testing.T) {
+	controller := NewController(nil, nil, model.VoiceConfig{MinSINR: -5})
+	ue := &model.UE{RrcState: model.RrcConnected, Cell: &model.UECell{Strength: -60}}
+
+	// Calls in good radio conditions do not drop without drop probability
+	drops := 0
+	for i := 0; i < 1000; i++ {
+		if controller.dropped(ue, time.Second) {
+			drops++
+		}
+	}
+	assert.Equal(t, 0, drops)
+
+	// Half a drop per second at the minimum SINR
+	ue.Cells = []*model.UECell{{Strength: -55}}
+	drops = 0
+	for i := 0; i < 10000; i++ {
+		if controller.dropped(ue, time.Second) {
+			drops++
+		}
+	}
+	assert.InDelta(t, 0.39, float64(drops)/10000, 0.03)
+}